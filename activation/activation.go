@@ -61,6 +61,7 @@ type Config struct {
 type Builder struct {
 	pendingPoetClients atomic.Pointer[[]PoetProvingServiceClient]
 	started            *atomic.Bool
+	paused             *atomic.Bool
 
 	eg errgroup.Group
 
@@ -78,8 +79,11 @@ type Builder struct {
 	initialPost       *types.Post
 	validator         nipostValidator
 
-	// smeshingMutex protects `StartSmeshing` and `StopSmeshing` from concurrent access
+	// smeshingMutex protects `StartSmeshing`, `StopSmeshing`, `PauseSmeshing` and `ResumeSmeshing`
+	// from concurrent access
 	smeshingMutex sync.Mutex
+	// smeshingOpts are the options of the last StartSmeshing call, smeshing is resumed with them.
+	smeshingOpts *PostSetupOpts
 
 	// pendingATX is created with current commitment and nipost from current challenge.
 	pendingATX            *types.ActivationTx
@@ -164,6 +168,7 @@ func NewBuilder(
 		layerClock:            layerClock,
 		syncer:                syncer,
 		started:               atomic.NewBool(false),
+		paused:                atomic.NewBool(false),
 		log:                   log,
 		poetRetryInterval:     defaultPoetRetryInterval,
 		poetClientInitializer: defaultPoetClientFunc,
@@ -179,21 +184,47 @@ func (b *Builder) Smeshing() bool {
 	return b.started.Load()
 }
 
+// Paused returns true if smeshing was paused with PauseSmeshing and wasn't resumed yet.
+func (b *Builder) Paused() bool {
+	return b.paused.Load()
+}
+
 // StartSmeshing is the main entry point of the atx builder. It runs the main
 // loop of the builder in a new go-routine and shouldn't be called more than
 // once without calling StopSmeshing in between. If the post data is incomplete
 // or missing, data creation session will be preceded. Changing of the post
 // options (e.g., number of labels), after initial setup, is supported. If data
-// creation fails for any reason then the go-routine will panic.
+// creation fails for any reason then the go-routine will panic. If smeshing
+// was paused, also before the node restart, the options are saved and smeshing
+// starts once it is resumed.
 func (b *Builder) StartSmeshing(coinbase types.Address, opts PostSetupOpts) error {
 	b.smeshingMutex.Lock()
 	defer b.smeshingMutex.Unlock()
 
+	paused, err := loadPaused(b.nipostBuilder.DataDir())
+	if err != nil {
+		return err
+	}
+	b.paused.Store(paused)
+	if paused {
+		if b.started.Load() {
+			return errors.New("already started")
+		}
+		b.coinbaseAccount = coinbase
+		b.smeshingOpts = &opts
+		b.log.Info("smeshing is paused, it will start once resumed")
+		return nil
+	}
+	return b.startSmeshing(coinbase, opts)
+}
+
+func (b *Builder) startSmeshing(coinbase types.Address, opts PostSetupOpts) error {
 	if !b.started.CompareAndSwap(false, true) {
 		return errors.New("already started")
 	}
 
 	b.coinbaseAccount = coinbase
+	b.smeshingOpts = &opts
 	ctx, stop := context.WithCancel(b.parentCtx)
 	b.stop = stop
 
@@ -230,17 +261,67 @@ func (b *Builder) StartSmeshing(coinbase types.Address, opts PostSetupOpts) erro
 	return nil
 }
 
+// PauseSmeshing stops the atx builder without deleting the post data. The paused state
+// is persisted, so smeshing isn't started after the node restart until it is resumed.
+func (b *Builder) PauseSmeshing() error {
+	b.smeshingMutex.Lock()
+	defer b.smeshingMutex.Unlock()
+
+	if b.paused.Load() {
+		return errors.New("already paused")
+	}
+	if !b.started.Load() {
+		return errors.New("not started")
+	}
+	if err := savePaused(b.nipostBuilder.DataDir()); err != nil {
+		return err
+	}
+	b.paused.Store(true)
+	b.stop()
+	if err := b.eg.Wait(); err != nil && !errors.Is(err, context.Canceled) {
+		return fmt.Errorf("failed to stop post data creation session: %w", err)
+	}
+	return nil
+}
+
+// ResumeSmeshing starts the atx builder paused with PauseSmeshing, with the options
+// of the last StartSmeshing call. If smeshing wasn't started, only the paused state is cleared.
+func (b *Builder) ResumeSmeshing() error {
+	b.smeshingMutex.Lock()
+	defer b.smeshingMutex.Unlock()
+
+	if !b.paused.Load() {
+		return errors.New("not paused")
+	}
+	if err := discardPaused(b.nipostBuilder.DataDir()); err != nil {
+		return err
+	}
+	b.paused.Store(false)
+	if b.smeshingOpts == nil {
+		return nil
+	}
+	return b.startSmeshing(b.coinbaseAccount, *b.smeshingOpts)
+}
+
 // StopSmeshing stops the atx builder.
 // It doesn't wait for the smeshing to stop.
 func (b *Builder) StopSmeshing(deleteFiles bool) error {
 	b.smeshingMutex.Lock()
 	defer b.smeshingMutex.Unlock()
 
-	if !b.started.Load() {
+	switch {
+	case b.paused.Load():
+		// smeshing is already stopped, only the paused state is cleared
+		if err := discardPaused(b.nipostBuilder.DataDir()); err != nil {
+			return err
+		}
+		b.paused.Store(false)
+		b.smeshingOpts = nil
+	case !b.started.Load():
 		return errors.New("not started")
+	default:
+		b.stop()
 	}
-
-	b.stop()
 	err := b.eg.Wait()
 	switch {
 	case err == nil || errors.Is(err, context.Canceled):
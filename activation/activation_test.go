@@ -363,6 +363,60 @@ func TestBuilder_StopSmeshing_Delete(t *testing.T) {
 	require.Len(t, files, 0) // state files still deleted
 }
 
+func TestBuilder_PauseSmeshing(t *testing.T) {
+	tab := newTestBuilder(t)
+	tab.mpost.EXPECT().PrepareInitializer(gomock.Any(), gomock.Any()).AnyTimes()
+	tab.mpost.EXPECT().StartSession(gomock.Any()).DoAndReturn(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}).AnyTimes()
+	require.NoError(t, savePost(tab.nipostBuilder.DataDir(), &types.Post{}))
+
+	require.ErrorContains(t, tab.PauseSmeshing(), "not started")
+	require.ErrorContains(t, tab.ResumeSmeshing(), "not paused")
+
+	coinbase := types.Address{1, 2, 3}
+	require.NoError(t, tab.StartSmeshing(coinbase, PostSetupOpts{NumUnits: 2}))
+	require.NoError(t, tab.PauseSmeshing())
+	require.ErrorContains(t, tab.PauseSmeshing(), "already paused")
+	require.True(t, tab.Paused())
+	require.Eventually(t, func() bool { return !tab.Smeshing() }, time.Second, time.Millisecond)
+	post, err := loadPost(tab.nipostBuilder.DataDir())
+	require.NoError(t, err)
+	require.NotNil(t, post)
+
+	require.NoError(t, tab.ResumeSmeshing())
+	require.False(t, tab.Paused())
+	require.True(t, tab.Smeshing())
+	require.Equal(t, coinbase, tab.Coinbase())
+	require.NoError(t, tab.StopSmeshing(false))
+}
+
+func TestBuilder_PauseSmeshingPersisted(t *testing.T) {
+	tab := newTestBuilder(t)
+	tab.mpost.EXPECT().PrepareInitializer(gomock.Any(), gomock.Any()).AnyTimes()
+	tab.mpost.EXPECT().StartSession(gomock.Any()).DoAndReturn(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}).AnyTimes()
+	require.NoError(t, tab.StartSmeshing(types.Address{}, PostSetupOpts{}))
+	require.NoError(t, tab.PauseSmeshing())
+
+	// builder created after the restart doesn't start smeshing until it is resumed
+	restarted := NewBuilder(Config{}, tab.nodeID, tab.sig, tab.cdb, tab.mhdlr, tab.mpub, tab.mnipost, tab.mpost,
+		tab.mclock, tab.msync, logtest.New(t))
+	require.NoError(t, restarted.StartSmeshing(types.Address{}, PostSetupOpts{}))
+	require.True(t, restarted.Paused())
+	require.False(t, restarted.Smeshing())
+
+	require.NoError(t, restarted.ResumeSmeshing())
+	require.True(t, restarted.Smeshing())
+	paused, err := loadPaused(tab.nipostBuilder.DataDir())
+	require.NoError(t, err)
+	require.False(t, paused)
+	require.NoError(t, restarted.StopSmeshing(false))
+}
+
 func TestBuilder_StoppingSmeshingBefore_Initialized(t *testing.T) {
 	tab := newTestBuilder(t)
 	tab.mpost.EXPECT().PrepareInitializer(gomock.Any(), gomock.Any()).AnyTimes()
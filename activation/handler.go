@@ -48,6 +48,7 @@ type Handler struct {
 	goldenATXID     types.ATXID
 	nipostValidator nipostValidator
 	beacon          AtxReceiver
+	receivers       []AtxReceiver
 	tortoise        system.Tortoise
 	log             log.Log
 	mu              sync.Mutex
@@ -96,6 +97,12 @@ func init() {
 	close(closedChan)
 }
 
+// Register adds receiver that is notified about every stored atx.
+// Not safe for concurrent use, expected to be called before handler starts processing atxs.
+func (h *Handler) Register(receiver AtxReceiver) {
+	h.receivers = append(h.receivers, receiver)
+}
+
 // AwaitAtx returns a channel that will receive notification when the specified atx with id is received via gossip.
 func (h *Handler) AwaitAtx(id types.ATXID) chan struct{} {
 	h.mu.Lock()
@@ -421,6 +428,9 @@ func (h *Handler) storeAtx(ctx context.Context, atx *types.VerifiedActivationTx)
 	}
 	h.beacon.OnAtx(header)
 	h.tortoise.OnAtx(header.ToData())
+	for _, receiver := range h.receivers {
+		receiver.OnAtx(header)
+	}
 
 	// notify subscribers
 	if ch, found := h.atxChannels[atx.ID()]; found {
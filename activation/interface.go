@@ -80,6 +80,9 @@ type SmeshingProvider interface {
 	Smeshing() bool
 	StartSmeshing(types.Address, PostSetupOpts) error
 	StopSmeshing(bool) error
+	Paused() bool
+	PauseSmeshing() error
+	ResumeSmeshing() error
 	SmesherID() types.NodeID
 	Coinbase() types.Address
 	SetCoinbase(coinbase types.Address)
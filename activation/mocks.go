@@ -732,6 +732,48 @@ func (mr *MockSmeshingProviderMockRecorder) Coinbase() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Coinbase", reflect.TypeOf((*MockSmeshingProvider)(nil).Coinbase))
 }
 
+// PauseSmeshing mocks base method.
+func (m *MockSmeshingProvider) PauseSmeshing() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PauseSmeshing")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PauseSmeshing indicates an expected call of PauseSmeshing.
+func (mr *MockSmeshingProviderMockRecorder) PauseSmeshing() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseSmeshing", reflect.TypeOf((*MockSmeshingProvider)(nil).PauseSmeshing))
+}
+
+// Paused mocks base method.
+func (m *MockSmeshingProvider) Paused() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Paused")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Paused indicates an expected call of Paused.
+func (mr *MockSmeshingProviderMockRecorder) Paused() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Paused", reflect.TypeOf((*MockSmeshingProvider)(nil).Paused))
+}
+
+// ResumeSmeshing mocks base method.
+func (m *MockSmeshingProvider) ResumeSmeshing() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeSmeshing")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResumeSmeshing indicates an expected call of ResumeSmeshing.
+func (mr *MockSmeshingProviderMockRecorder) ResumeSmeshing() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeSmeshing", reflect.TypeOf((*MockSmeshingProvider)(nil).ResumeSmeshing))
+}
+
 // SetCoinbase mocks base method.
 func (m *MockSmeshingProvider) SetCoinbase(coinbase types.Address) {
 	m.ctrl.T.Helper()
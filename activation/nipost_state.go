@@ -2,9 +2,11 @@ package activation
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"hash/crc64"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 
@@ -19,6 +21,8 @@ const (
 	challengeFilename = "nipost_challenge.bin"
 	builderFilename   = "nipost_builder_state.bin"
 	postFilename      = "post.bin"
+	// pausedFilename marks that smeshing was paused, it has no content.
+	pausedFilename = "smeshing_paused"
 )
 
 func write(path string, data []byte) error {
@@ -183,3 +187,30 @@ func discardPost(dir string) error {
 	}
 	return nil
 }
+
+func savePaused(dir string) error {
+	if err := write(filepath.Join(dir, pausedFilename), nil); err != nil {
+		return fmt.Errorf("saving paused state: %w", err)
+	}
+	return nil
+}
+
+func loadPaused(dir string) (bool, error) {
+	_, err := os.Stat(filepath.Join(dir, pausedFilename))
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, fs.ErrNotExist):
+		return false, nil
+	default:
+		return false, fmt.Errorf("loading paused state: %w", err)
+	}
+}
+
+func discardPaused(dir string) error {
+	filename := filepath.Join(dir, pausedFilename)
+	if err := os.Remove(filename); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("discarding paused state: %w", err)
+	}
+	return nil
+}
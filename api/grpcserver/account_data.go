@@ -0,0 +1,364 @@
+package grpcserver
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/sql/rewards"
+	"github.com/spacemeshos/go-spacemesh/sql/transactions"
+)
+
+const (
+	// defaultAccountDataPageSize is the number of items returned if the limit is not set.
+	defaultAccountDataPageSize = 100
+	// maxAccountDataPageSize is the max number of items returned in a single page.
+	maxAccountDataPageSize = 1000
+)
+
+// AccountDataKind is the kind of the account data item. items within the same layer are
+// ordered by kind in the order of declaration, and then by id.
+type AccountDataKind uint8
+
+const (
+	AccountDataTransaction AccountDataKind = iota
+	AccountDataReceipt
+	AccountDataReward
+)
+
+var accountDataKinds = []AccountDataKind{AccountDataTransaction, AccountDataReceipt, AccountDataReward}
+
+func (k AccountDataKind) String() string {
+	switch k {
+	case AccountDataTransaction:
+		return "txs"
+	case AccountDataReceipt:
+		return "receipts"
+	case AccountDataReward:
+		return "rewards"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (k AccountDataKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (k *AccountDataKind) UnmarshalText(text []byte) error {
+	for _, kind := range accountDataKinds {
+		if kind.String() == string(text) {
+			*k = kind
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown type %q", text)
+}
+
+func parseAccountDataKinds(raw string) ([]AccountDataKind, error) {
+	if raw == "" {
+		return accountDataKinds, nil
+	}
+	selected := map[AccountDataKind]struct{}{}
+	for _, name := range strings.Split(raw, ",") {
+		var kind AccountDataKind
+		if err := kind.UnmarshalText([]byte(name)); err != nil {
+			return nil, err
+		}
+		selected[kind] = struct{}{}
+	}
+	var rst []AccountDataKind
+	for _, kind := range accountDataKinds {
+		if _, ok := selected[kind]; ok {
+			rst = append(rst, kind)
+		}
+	}
+	return rst, nil
+}
+
+// accountDataCursor is the position of the item in the order by layer, kind and id.
+type accountDataCursor struct {
+	Layer types.LayerID
+	Kind  AccountDataKind
+	ID    []byte
+}
+
+func (c *accountDataCursor) less(other *accountDataCursor) bool {
+	if c.Layer != other.Layer {
+		return c.Layer < other.Layer
+	}
+	if c.Kind != other.Kind {
+		return c.Kind < other.Kind
+	}
+	return bytes.Compare(c.ID, other.ID) < 0
+}
+
+func (c *accountDataCursor) encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d:%x", c.Layer, c.Kind, c.ID)))
+}
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+func decodeAccountDataCursor(raw string) (*accountDataCursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	parts := strings.Split(string(decoded), ":")
+	if len(parts) != 3 {
+		return nil, errInvalidCursor
+	}
+	layer, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	kind, err := strconv.ParseUint(parts[1], 10, 8)
+	if err != nil || AccountDataKind(kind) > AccountDataReward {
+		return nil, errInvalidCursor
+	}
+	id, err := hex.DecodeString(parts[2])
+	if err != nil || (AccountDataKind(kind) != AccountDataReward && len(id) != types.TransactionIDSize) {
+		return nil, errInvalidCursor
+	}
+	return &accountDataCursor{Layer: types.LayerID(layer), Kind: AccountDataKind(kind), ID: id}, nil
+}
+
+// AccountTx is the transaction applied to the account state.
+type AccountTx struct {
+	ID        string `json:"id"`
+	Principal string `json:"principal,omitempty"`
+	Nonce     uint64 `json:"nonce"`
+	GasPrice  uint64 `json:"gas_price"`
+	MaxGas    uint64 `json:"max_gas"`
+	Raw       string `json:"raw"`
+}
+
+// AccountReceipt is the result of the transaction applied to the account state.
+type AccountReceipt struct {
+	ID      string `json:"id"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+	Gas     uint64 `json:"gas"`
+	Fee     uint64 `json:"fee"`
+	Block   string `json:"block"`
+}
+
+// AccountReward is the reward received by the account as a coinbase.
+type AccountReward struct {
+	Total       uint64 `json:"total"`
+	LayerReward uint64 `json:"layer_reward"`
+}
+
+// AccountDataItem is a single item of the account data, only the field for its kind is set.
+type AccountDataItem struct {
+	Kind        AccountDataKind `json:"kind"`
+	Layer       types.LayerID   `json:"layer"`
+	Transaction *AccountTx      `json:"transaction,omitempty"`
+	Receipt     *AccountReceipt `json:"receipt,omitempty"`
+	Reward      *AccountReward  `json:"reward,omitempty"`
+
+	cursor accountDataCursor
+}
+
+// AccountData is the page of the account data. Next is the cursor of the next page,
+// it is empty if there are no more items.
+type AccountData struct {
+	Data []AccountDataItem `json:"data"`
+	Next string            `json:"next,omitempty"`
+}
+
+// registerAccountData registers the account data endpoint with the grpc gateway.
+func (s MeshService) registerAccountData(mux *runtime.ServeMux) error {
+	path := "/v1/accounts/{address}/data"
+	if err := mux.HandlePath(http.MethodGet, path, s.accountData); err != nil {
+		return fmt.Errorf("register %s: %w", path, err)
+	}
+	return nil
+}
+
+// accountData returns transactions, receipts and rewards of the account ordered by layer, type and id.
+// the types query parameter is a comma separated list of the types, from and to select the layers range,
+// and cursor is the next cursor from the previous page.
+func (s MeshService) accountData(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	addr, err := types.StringToAddress(params["address"])
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid address: %v", err), http.StatusBadRequest)
+		return
+	}
+	query := r.URL.Query()
+	kinds, err := parseAccountDataKinds(query.Get("types"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	from, err := queryInt(r, "from", 0)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	to, err := queryInt(r, "to", math.MaxUint32)
+	if err != nil || to > math.MaxUint32 || from > to {
+		http.Error(w, fmt.Sprintf("invalid layers range [%d, %d]", from, to), http.StatusBadRequest)
+		return
+	}
+	limit, err := queryInt(r, "limit", defaultAccountDataPageSize)
+	if err != nil || limit < 1 || limit > maxAccountDataPageSize {
+		http.Error(w, fmt.Sprintf("limit must be within [1, %d]", maxAccountDataPageSize), http.StatusBadRequest)
+		return
+	}
+	var cursor *accountDataCursor
+	if raw := query.Get("cursor"); raw != "" {
+		if cursor, err = decodeAccountDataCursor(raw); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	rst, err := s.listAccountData(addr, kinds, types.LayerID(from), types.LayerID(to), cursor, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, rst)
+}
+
+// listAccountData queries up to limit+1 items of every kind after the cursor, and merges them
+// to select the page and to find out if there are more items.
+func (s MeshService) listAccountData(
+	addr types.Address,
+	kinds []AccountDataKind,
+	from, to types.LayerID,
+	cursor *accountDataCursor,
+	limit int,
+) (*AccountData, error) {
+	var items []AccountDataItem
+	for _, kind := range kinds {
+		start := from
+		var after *accountDataCursor
+		if cursor != nil {
+			switch {
+			case cursor.Kind == kind:
+				after = cursor
+			case cursor.Kind < kind && cursor.Layer > start:
+				start = cursor.Layer
+			case cursor.Kind > kind && cursor.Layer >= start:
+				start = cursor.Layer + 1
+			}
+		}
+		if start > to {
+			continue
+		}
+		var (
+			rst []AccountDataItem
+			err error
+		)
+		if kind == AccountDataReward {
+			rst, err = s.accountRewards(addr, start, to, after, limit+1)
+		} else {
+			rst, err = s.accountResults(addr, kind, start, to, after, limit+1)
+		}
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, rst...)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].cursor.less(&items[j].cursor)
+	})
+	rst := &AccountData{Data: items}
+	if len(items) > limit {
+		rst.Data = items[:limit]
+		rst.Next = items[limit-1].cursor.encode()
+	}
+	if rst.Data == nil {
+		rst.Data = []AccountDataItem{}
+	}
+	return rst, nil
+}
+
+func (s MeshService) accountResults(
+	addr types.Address,
+	kind AccountDataKind,
+	start, end types.LayerID,
+	after *accountDataCursor,
+	limit int,
+) ([]AccountDataItem, error) {
+	filter := transactions.ResultsFilter{Address: &addr, Start: &start, End: &end, Limit: limit}
+	if after != nil {
+		filter.After = &transactions.ResultsCursor{Layer: after.Layer}
+		copy(filter.After.ID[:], after.ID)
+	}
+	var items []AccountDataItem
+	if err := transactions.IterateResults(s.cdb, filter, func(tx *types.TransactionWithResult) bool {
+		item := AccountDataItem{
+			Kind:   kind,
+			Layer:  tx.Layer,
+			cursor: accountDataCursor{Layer: tx.Layer, Kind: kind, ID: tx.ID.Bytes()},
+		}
+		if kind == AccountDataTransaction {
+			item.Transaction = &AccountTx{ID: tx.ID.String(), Raw: hex.EncodeToString(tx.Raw)}
+			if tx.TxHeader != nil {
+				item.Transaction.Principal = tx.Principal.String()
+				item.Transaction.Nonce = tx.Nonce
+				item.Transaction.GasPrice = tx.GasPrice
+				item.Transaction.MaxGas = tx.MaxGas
+			}
+		} else {
+			item.Receipt = &AccountReceipt{
+				ID:      tx.ID.String(),
+				Status:  tx.Status.String(),
+				Message: tx.Message,
+				Gas:     tx.Gas,
+				Fee:     tx.Fee,
+				Block:   tx.Block.String(),
+			}
+		}
+		items = append(items, item)
+		return true
+	}); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s MeshService) accountRewards(
+	addr types.Address,
+	start, end types.LayerID,
+	after *accountDataCursor,
+	limit int,
+) ([]AccountDataItem, error) {
+	// there is at most one reward in the layer for the coinbase
+	if after != nil {
+		if after.Layer >= end {
+			return nil, nil
+		}
+		if after.Layer >= start {
+			start = after.Layer + 1
+		}
+	}
+	rst, err := rewards.ListByLayers(s.cdb, addr, start, end, limit)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]AccountDataItem, 0, len(rst))
+	for _, reward := range rst {
+		items = append(items, AccountDataItem{
+			Kind:   AccountDataReward,
+			Layer:  reward.Layer,
+			Reward: &AccountReward{Total: reward.TotalReward, LayerReward: reward.LayerReward},
+			cursor: accountDataCursor{Layer: reward.Layer, Kind: AccountDataReward},
+		})
+	}
+	return items, nil
+}
@@ -0,0 +1,133 @@
+package grpcserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/datastore"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/rewards"
+	"github.com/spacemeshos/go-spacemesh/sql/transactions"
+)
+
+func newAccountDataServer(t *testing.T) (*sql.Database, *httptest.Server) {
+	db := sql.InMemory()
+	svc := NewMeshService(datastore.NewCachedDB(db, logtest.New(t)), nil, nil, nil, 0, types.Hash20{}, 0, 0, 0, logtest.New(t))
+	mux := runtime.NewServeMux()
+	require.NoError(t, svc.registerAccountData(mux))
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return db, srv
+}
+
+func addAccountData(t *testing.T, db *sql.Database, addr types.Address, layers int) {
+	for lid := types.LayerID(1); lid <= types.LayerID(layers); lid++ {
+		for i := 0; i < 2; i++ {
+			tx := &types.Transaction{RawTx: types.NewRawTx(types.RandomBytes(20))}
+			tx.TxHeader = &types.TxHeader{Principal: addr, Nonce: uint64(lid)*2 + uint64(i)}
+			require.NoError(t, db.WithTx(context.Background(), func(dtx *sql.Tx) error {
+				if err := transactions.Add(dtx, tx, time.Time{}); err != nil {
+					return err
+				}
+				return transactions.AddResult(dtx, tx.ID, &types.TransactionResult{
+					Layer:     lid,
+					Block:     types.RandomBlockID(),
+					Fee:       1,
+					Addresses: []types.Address{addr},
+				})
+			}))
+		}
+		require.NoError(t, rewards.Add(db, &types.Reward{Layer: lid, Coinbase: addr, TotalReward: 10, LayerReward: 5}))
+	}
+}
+
+func TestAccountData_Pages(t *testing.T) {
+	db, srv := newAccountDataServer(t)
+	addr := types.GenerateAddress(types.RandomBytes(32))
+	addAccountData(t, db, addr, 5)
+	addAccountData(t, db, types.GenerateAddress(types.RandomBytes(32)), 5)
+
+	var all AccountData
+	require.Equal(t, http.StatusOK, getJSON(t, srv.URL+"/v1/accounts/"+addr.String()+"/data", &all))
+	require.Len(t, all.Data, 25)
+	require.Empty(t, all.Next)
+
+	var (
+		paged  []AccountDataItem
+		cursor string
+	)
+	for {
+		var page AccountData
+		url := fmt.Sprintf("%s/v1/accounts/%s/data?limit=3&cursor=%s", srv.URL, addr.String(), cursor)
+		require.Equal(t, http.StatusOK, getJSON(t, url, &page))
+		require.LessOrEqual(t, len(page.Data), 3)
+		paged = append(paged, page.Data...)
+		if page.Next == "" {
+			break
+		}
+		cursor = page.Next
+	}
+	require.Equal(t, all.Data, paged)
+	for i := 1; i < len(paged); i++ {
+		prev, item := paged[i-1], paged[i]
+		require.True(t, prev.Layer < item.Layer || (prev.Layer == item.Layer && prev.Kind <= item.Kind))
+	}
+}
+
+func TestAccountData_Filters(t *testing.T) {
+	db, srv := newAccountDataServer(t)
+	addr := types.GenerateAddress(types.RandomBytes(32))
+	addAccountData(t, db, addr, 5)
+
+	var rst AccountData
+	url := fmt.Sprintf("%s/v1/accounts/%s/data?types=rewards,receipts&from=2&to=3", srv.URL, addr.String())
+	require.Equal(t, http.StatusOK, getJSON(t, url, &rst))
+	require.Len(t, rst.Data, 6)
+	for _, item := range rst.Data {
+		require.Contains(t, []types.LayerID{2, 3}, item.Layer)
+		require.Nil(t, item.Transaction)
+		switch item.Kind {
+		case AccountDataReceipt:
+			require.NotNil(t, item.Receipt)
+			require.Equal(t, uint64(1), item.Receipt.Fee)
+		case AccountDataReward:
+			require.NotNil(t, item.Reward)
+			require.Equal(t, uint64(10), item.Reward.Total)
+		default:
+			require.Failf(t, "unexpected kind", "%v", item.Kind)
+		}
+	}
+
+	url = fmt.Sprintf("%s/v1/accounts/%s/data?types=txs&from=5", srv.URL, addr.String())
+	require.Equal(t, http.StatusOK, getJSON(t, url, &rst))
+	require.Len(t, rst.Data, 2)
+	for _, item := range rst.Data {
+		require.Equal(t, AccountDataTransaction, item.Kind)
+		require.Equal(t, addr.String(), item.Transaction.Principal)
+	}
+}
+
+func TestAccountData_InvalidRequest(t *testing.T) {
+	_, srv := newAccountDataServer(t)
+	addr := types.GenerateAddress(types.RandomBytes(32))
+	for _, query := range []string{
+		"types=blocks",
+		"from=3&to=2",
+		"limit=0",
+		fmt.Sprintf("limit=%d", maxAccountDataPageSize+1),
+		"cursor=invalid",
+	} {
+		require.Equal(t, http.StatusBadRequest,
+			getJSON(t, fmt.Sprintf("%s/v1/accounts/%s/data?%s", srv.URL, addr.String(), query), nil), query)
+	}
+	require.Equal(t, http.StatusBadRequest, getJSON(t, srv.URL+"/v1/accounts/invalid/data", nil))
+}
@@ -0,0 +1,146 @@
+package grpcserver
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/accounts"
+)
+
+// AccountProofJSON is the merkle proof of the account state at the layer, see types.AccountProof.
+type AccountProofJSON struct {
+	Layer     uint32   `json:"layer"`
+	Root      string   `json:"root"`
+	Address   string   `json:"address"`
+	Updated   uint32   `json:"updated"`
+	NextNonce uint64   `json:"next_nonce"`
+	Balance   uint64   `json:"balance"`
+	Template  string   `json:"template,omitempty"`
+	State     string   `json:"state,omitempty"`
+	Index     uint64   `json:"index"`
+	Leaves    uint64   `json:"leaves"`
+	Path      []string `json:"path"`
+}
+
+// Proof decodes the proof and the root it is verified against.
+func (p *AccountProofJSON) Proof() (*types.AccountProof, types.Hash32, error) {
+	var root types.Hash32
+	if err := decodeHash(p.Root, &root); err != nil {
+		return nil, root, fmt.Errorf("root: %w", err)
+	}
+	addr, err := types.StringToAddress(p.Address)
+	if err != nil {
+		return nil, root, err
+	}
+	proof := &types.AccountProof{
+		Account: types.Account{
+			Layer:     types.LayerID(p.Updated),
+			Address:   addr,
+			NextNonce: p.NextNonce,
+			Balance:   p.Balance,
+		},
+		Index:  p.Index,
+		Leaves: p.Leaves,
+	}
+	if len(p.Template) > 0 {
+		template, err := types.StringToAddress(p.Template)
+		if err != nil {
+			return nil, root, fmt.Errorf("template: %w", err)
+		}
+		proof.Account.TemplateAddress = &template
+	}
+	if len(p.State) > 0 {
+		if proof.Account.State, err = hex.DecodeString(p.State); err != nil {
+			return nil, root, fmt.Errorf("state: %w", err)
+		}
+	}
+	proof.Path = make([]types.Hash32, len(p.Path))
+	for i, raw := range p.Path {
+		if err := decodeHash(raw, &proof.Path[i]); err != nil {
+			return nil, root, fmt.Errorf("path %d: %w", i, err)
+		}
+	}
+	return proof, root, nil
+}
+
+func decodeHash(raw string, hash *types.Hash32) error {
+	decoded, err := hex.DecodeString(raw)
+	if err != nil {
+		return err
+	}
+	if len(decoded) != len(hash) {
+		return fmt.Errorf("expected %d bytes, got %d", len(hash), len(decoded))
+	}
+	copy(hash[:], decoded)
+	return nil
+}
+
+// registerAccountProof registers the account proof endpoint with the grpc gateway.
+func (s GlobalStateService) registerAccountProof(mux *runtime.ServeMux) error {
+	path := "/v1/globalstate/accounts/{address}/proof"
+	if err := mux.HandlePath(http.MethodGet, path, s.accountProof); err != nil {
+		return fmt.Errorf("register %s: %w", path, err)
+	}
+	return nil
+}
+
+// accountProof returns the merkle proof of the account state at the layer in the layer query parameter,
+// or at the latest applied layer if it is not set. The root is the state hash of the layer, so light
+// clients verify the account state against the state hash they agreed on with other nodes.
+func (s GlobalStateService) accountProof(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	addr, err := types.StringToAddress(params["address"])
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid address: %v", err), http.StatusBadRequest)
+		return
+	}
+	var layer types.LayerID
+	if r.URL.Query().Has("layer") {
+		raw, err := queryInt(r, "layer", 0)
+		if err != nil || raw > int(^uint32(0)) {
+			http.Error(w, "invalid layer", http.StatusBadRequest)
+			return
+		}
+		layer = types.LayerID(raw)
+	} else {
+		layer = s.mesh.LatestLayerInState()
+	}
+	proof, root, err := s.conState.AccountProof(addr, layer)
+	switch {
+	case errors.Is(err, sql.ErrNotFound):
+		http.Error(w, fmt.Sprintf("account %s not found at layer %d", addr, layer), http.StatusNotFound)
+		return
+	case errors.Is(err, accounts.ErrPruned):
+		http.Error(w, fmt.Sprintf("state at layer %d is pruned", layer), http.StatusGone)
+		return
+	case err != nil:
+		s.logger.With().Error("failed to prove account state", addr, layer, log.Err(err))
+		http.Error(w, "error proving account state", http.StatusInternalServerError)
+		return
+	}
+	rst := AccountProofJSON{
+		Layer:     layer.Uint32(),
+		Root:      hex.EncodeToString(root[:]),
+		Address:   proof.Account.Address.String(),
+		Updated:   proof.Account.Layer.Uint32(),
+		NextNonce: proof.Account.NextNonce,
+		Balance:   proof.Account.Balance,
+		State:     hex.EncodeToString(proof.Account.State),
+		Index:     proof.Index,
+		Leaves:    proof.Leaves,
+		Path:      make([]string, 0, len(proof.Path)),
+	}
+	if proof.Account.TemplateAddress != nil {
+		rst.Template = proof.Account.TemplateAddress.String()
+	}
+	for _, sibling := range proof.Path {
+		rst.Path = append(rst.Path, hex.EncodeToString(sibling[:]))
+	}
+	writeJSON(w, rst)
+}
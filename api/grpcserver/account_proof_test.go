@@ -0,0 +1,63 @@
+package grpcserver
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/accounts"
+)
+
+func newAccountProofServer(t *testing.T) (*MockmeshAPI, *MockconservativeState, *httptest.Server) {
+	ctrl := gomock.NewController(t)
+	meshAPI := NewMockmeshAPI(ctrl)
+	conState := NewMockconservativeState(ctrl)
+	svc := NewGlobalStateService(meshAPI, conState, logtest.New(t))
+	mux := runtime.NewServeMux()
+	require.NoError(t, svc.registerAccountProof(mux))
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return meshAPI, conState, srv
+}
+
+func TestAccountProof(t *testing.T) {
+	meshAPI, conState, srv := newAccountProofServer(t)
+	template := types.GenerateAddress(types.RandomBytes(32))
+	snapshot := []*types.Account{
+		{Layer: 3, Address: types.GenerateAddress(types.RandomBytes(32)), Balance: 10},
+		{Layer: 5, Address: types.GenerateAddress(types.RandomBytes(32)), Balance: 20, NextNonce: 2, TemplateAddress: &template, State: []byte{1, 2}},
+		{Layer: 7, Address: types.GenerateAddress(types.RandomBytes(32)), Balance: 30},
+	}
+	addr := snapshot[1].Address
+	proof, root, found := types.ProveAccount(snapshot, addr)
+	require.True(t, found)
+
+	lid := types.LayerID(9)
+	meshAPI.EXPECT().LatestLayerInState().Return(lid)
+	conState.EXPECT().AccountProof(addr, lid).Return(proof, root, nil)
+	var rst AccountProofJSON
+	require.Equal(t, http.StatusOK, getJSON(t, fmt.Sprintf("%s/v1/globalstate/accounts/%s/proof", srv.URL, addr.String()), &rst))
+	require.Equal(t, lid.Uint32(), rst.Layer)
+	decoded, decodedRoot, err := rst.Proof()
+	require.NoError(t, err)
+	require.Equal(t, root, decodedRoot)
+	require.Equal(t, proof, decoded)
+	require.True(t, decoded.Verify(decodedRoot))
+
+	url := fmt.Sprintf("%s/v1/globalstate/accounts/%s/proof?layer=4", srv.URL, addr.String())
+	conState.EXPECT().AccountProof(addr, types.LayerID(4)).Return(nil, types.Hash32{}, sql.ErrNotFound)
+	require.Equal(t, http.StatusNotFound, getJSON(t, url, &rst))
+	conState.EXPECT().AccountProof(addr, types.LayerID(4)).Return(nil, types.Hash32{}, accounts.ErrPruned)
+	require.Equal(t, http.StatusGone, getJSON(t, url, &rst))
+
+	require.Equal(t, http.StatusBadRequest, getJSON(t, srv.URL+"/v1/globalstate/accounts/invalid/proof", &rst))
+	require.Equal(t, http.StatusBadRequest, getJSON(t, fmt.Sprintf("%s/v1/globalstate/accounts/%s/proof?layer=x", srv.URL, addr.String()), &rst))
+}
@@ -0,0 +1,91 @@
+package grpcserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log"
+)
+
+// maxBatchAccounts is the max number of addresses in a single batch accounts query.
+const maxBatchAccounts = 100
+
+// AccountsRequest is the list of addresses to query the state for.
+type AccountsRequest struct {
+	Addresses []string `json:"addresses"`
+}
+
+// AccountStateJSON is the counter and balance of the account.
+type AccountStateJSON struct {
+	Counter uint64 `json:"counter"`
+	Balance uint64 `json:"balance"`
+}
+
+// AccountJSON is the current and projected state of the account.
+type AccountJSON struct {
+	Address   string           `json:"address"`
+	Current   AccountStateJSON `json:"current"`
+	Projected AccountStateJSON `json:"projected"`
+}
+
+// AccountsResponse contains the state of the accounts in the order of the requested addresses.
+type AccountsResponse struct {
+	Accounts []AccountJSON `json:"accounts"`
+}
+
+// registerAccounts registers the batch accounts endpoint with the grpc gateway.
+func (s GlobalStateService) registerAccounts(mux *runtime.ServeMux) error {
+	path := "/v1/globalstate/accounts"
+	if err := mux.HandlePath(http.MethodPost, path, s.accounts); err != nil {
+		return fmt.Errorf("register %s: %w", path, err)
+	}
+	return nil
+}
+
+// accounts returns current and projected counter and balance for every address in the request,
+// so that clients managing many accounts don't need to query them one by one.
+func (s GlobalStateService) accounts(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var req AccountsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	if len(req.Addresses) == 0 || len(req.Addresses) > maxBatchAccounts {
+		http.Error(w, fmt.Sprintf("number of addresses must be within [1, %d]", maxBatchAccounts), http.StatusBadRequest)
+		return
+	}
+	addrs := make([]types.Address, 0, len(req.Addresses))
+	for _, raw := range req.Addresses {
+		addr, err := types.StringToAddress(raw)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid address %q: %v", raw, err), http.StatusBadRequest)
+			return
+		}
+		addrs = append(addrs, addr)
+	}
+	rst := AccountsResponse{Accounts: make([]AccountJSON, 0, len(addrs))}
+	for _, addr := range addrs {
+		acct, err := s.getAccount(addr)
+		if err != nil {
+			s.logger.With().Error("unable to fetch projected account state", addr, log.Err(err))
+			http.Error(w, "error fetching projected account data", http.StatusInternalServerError)
+			return
+		}
+		rst.Accounts = append(rst.Accounts, AccountJSON{
+			Address: addr.String(),
+			Current: AccountStateJSON{
+				Counter: acct.StateCurrent.Counter,
+				Balance: acct.StateCurrent.Balance.Value,
+			},
+			Projected: AccountStateJSON{
+				Counter: acct.StateProjected.Counter,
+				Balance: acct.StateProjected.Balance.Value,
+			},
+		})
+	}
+	writeJSON(w, rst)
+}
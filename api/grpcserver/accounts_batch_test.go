@@ -0,0 +1,74 @@
+package grpcserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+)
+
+func newAccountsServer(t *testing.T) (*MockconservativeState, *httptest.Server) {
+	conState := NewMockconservativeState(gomock.NewController(t))
+	svc := NewGlobalStateService(nil, conState, logtest.New(t))
+	mux := runtime.NewServeMux()
+	require.NoError(t, svc.registerAccounts(mux))
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return conState, srv
+}
+
+func postAccounts(t *testing.T, url string, addresses []string, rst *AccountsResponse) int {
+	t.Helper()
+	body, err := json.Marshal(AccountsRequest{Addresses: addresses})
+	require.NoError(t, err)
+	resp, err := http.Post(url+"/v1/globalstate/accounts", "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(rst))
+	}
+	return resp.StatusCode
+}
+
+func TestAccountsBatch(t *testing.T) {
+	conState, srv := newAccountsServer(t)
+	var addrs []string
+	for i := 0; i < 3; i++ {
+		addr := types.GenerateAddress(types.RandomBytes(32))
+		addrs = append(addrs, addr.String())
+		conState.EXPECT().GetBalance(addr).Return(uint64(100*i), nil)
+		conState.EXPECT().GetNonce(addr).Return(types.Nonce(i), nil)
+		conState.EXPECT().GetProjection(addr).Return(uint64(i+1), uint64(100*i+50))
+	}
+
+	var rst AccountsResponse
+	require.Equal(t, http.StatusOK, postAccounts(t, srv.URL, addrs, &rst))
+	require.Len(t, rst.Accounts, len(addrs))
+	for i, acct := range rst.Accounts {
+		require.Equal(t, addrs[i], acct.Address)
+		require.Equal(t, AccountStateJSON{Counter: uint64(i), Balance: uint64(100 * i)}, acct.Current)
+		require.Equal(t, AccountStateJSON{Counter: uint64(i + 1), Balance: uint64(100*i + 50)}, acct.Projected)
+	}
+}
+
+func TestAccountsBatch_Invalid(t *testing.T) {
+	conState, srv := newAccountsServer(t)
+	addr := types.GenerateAddress(types.RandomBytes(32))
+
+	require.Equal(t, http.StatusBadRequest, postAccounts(t, srv.URL, nil, nil))
+	require.Equal(t, http.StatusBadRequest, postAccounts(t, srv.URL, []string{addr.String(), "invalid"}, nil))
+	require.Equal(t, http.StatusBadRequest,
+		postAccounts(t, srv.URL, make([]string, maxBatchAccounts+1), nil))
+
+	conState.EXPECT().GetBalance(addr).Return(uint64(0), errors.New("test"))
+	require.Equal(t, http.StatusInternalServerError, postAccounts(t, srv.URL, []string{addr.String()}, nil))
+}
@@ -0,0 +1,244 @@
+package grpcserver
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/atxs"
+)
+
+const (
+	// defaultAtxsPageSize is the number of atxs returned if the limit is not set.
+	defaultAtxsPageSize = 100
+	// maxAtxsPageSize is the max number of atxs returned in a single page.
+	maxAtxsPageSize = 1000
+)
+
+// NIPostJSON is the metadata of the nipost of the atx.
+type NIPostJSON struct {
+	// Challenge is the challenge of the post proof.
+	Challenge     string `json:"challenge"`
+	LabelsPerUnit uint64 `json:"labels_per_unit"`
+	// MembershipLeafIndex is the index of the atx challenge in the poet proof.
+	MembershipLeafIndex uint64 `json:"membership_leaf_index"`
+}
+
+// ATXJSON is the activation transaction with the nipost metadata.
+type ATXJSON struct {
+	ID                string      `json:"id"`
+	SmesherID         string      `json:"smesher_id"`
+	PublishEpoch      uint32      `json:"publish_epoch"`
+	TargetEpoch       uint32      `json:"target_epoch"`
+	Sequence          uint64      `json:"sequence"`
+	PrevATX           string      `json:"prev_atx"`
+	PositioningATX    string      `json:"positioning_atx"`
+	CommitmentATX     string      `json:"commitment_atx,omitempty"`
+	Coinbase          string      `json:"coinbase"`
+	NumUnits          uint32      `json:"num_units"`
+	EffectiveNumUnits uint32      `json:"effective_num_units"`
+	BaseTickHeight    uint64      `json:"base_tick_height"`
+	TickCount         uint64      `json:"tick_count"`
+	Weight            uint64      `json:"weight"`
+	VRFNonce          *uint64     `json:"vrf_nonce,omitempty"`
+	Received          *time.Time  `json:"received,omitempty"`
+	NIPost            *NIPostJSON `json:"nipost,omitempty"`
+}
+
+// ATXList is the page of the atxs. Next is the value of the query parameter for the next page,
+// it is empty if there are no more atxs.
+type ATXList struct {
+	ATXs []ATXJSON `json:"atxs"`
+	Next string    `json:"next,omitempty"`
+}
+
+func toATXJSON(atx *types.VerifiedActivationTx) ATXJSON {
+	rst := ATXJSON{
+		ID:                hex.EncodeToString(atx.ID().Bytes()),
+		SmesherID:         atx.SmesherID.String(),
+		PublishEpoch:      atx.PublishEpoch.Uint32(),
+		TargetEpoch:       atx.TargetEpoch().Uint32(),
+		Sequence:          atx.Sequence,
+		PrevATX:           hex.EncodeToString(atx.PrevATXID.Bytes()),
+		PositioningATX:    hex.EncodeToString(atx.PositioningATX.Bytes()),
+		Coinbase:          atx.Coinbase.String(),
+		NumUnits:          atx.NumUnits,
+		EffectiveNumUnits: atx.EffectiveNumUnits(),
+		BaseTickHeight:    atx.BaseTickHeight(),
+		TickCount:         atx.TickCount(),
+		Weight:            atx.GetWeight(),
+	}
+	if atx.CommitmentATX != nil {
+		rst.CommitmentATX = hex.EncodeToString(atx.CommitmentATX.Bytes())
+	}
+	if atx.VRFNonce != nil {
+		nonce := uint64(*atx.VRFNonce)
+		rst.VRFNonce = &nonce
+	}
+	if received := atx.Received(); !received.IsZero() {
+		rst.Received = &received
+	}
+	// checkpointed atxs don't have the nipost
+	if atx.NIPost != nil && atx.NIPost.PostMetadata != nil {
+		rst.NIPost = &NIPostJSON{
+			Challenge:           hex.EncodeToString(atx.NIPost.PostMetadata.Challenge),
+			LabelsPerUnit:       atx.NIPost.PostMetadata.LabelsPerUnit,
+			MembershipLeafIndex: atx.NIPost.Membership.LeafIndex,
+		}
+	}
+	return rst
+}
+
+// registerQueries registers the atx query endpoints with the grpc gateway.
+func (s *activationService) registerQueries(mux *runtime.ServeMux) error {
+	for path, handler := range map[string]runtime.HandlerFunc{
+		"/v1/activation/atxs/{id}":           s.atx,
+		"/v1/activation/smeshers/{id}/atxs":  s.smesherAtxs,
+		"/v1/activation/epochs/{epoch}/atxs": s.epochAtxs,
+	} {
+		if err := mux.HandlePath(http.MethodGet, path, handler); err != nil {
+			return fmt.Errorf("register %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+func parseHexID(raw string, size int) ([]byte, error) {
+	id, err := hex.DecodeString(raw)
+	if err != nil || len(id) != size {
+		return nil, fmt.Errorf("invalid id %q, expected %d hex encoded bytes", raw, size)
+	}
+	return id, nil
+}
+
+func parseAtxsLimit(r *http.Request) (int, error) {
+	limit, err := queryInt(r, "limit", defaultAtxsPageSize)
+	if err != nil || limit < 1 || limit > maxAtxsPageSize {
+		return 0, fmt.Errorf("limit must be within [1, %d]", maxAtxsPageSize)
+	}
+	return limit, nil
+}
+
+// atx returns the atx by its id.
+func (s *activationService) atx(w http.ResponseWriter, _ *http.Request, params map[string]string) {
+	raw, err := parseHexID(params["id"], types.ATXIDSize)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	id := types.ATXID(types.BytesToHash(raw))
+	if id == types.EmptyATXID {
+		http.Error(w, "atx not found", http.StatusNotFound)
+		return
+	}
+	atx, err := s.atxProvider.GetFullAtx(id)
+	switch {
+	case errors.Is(err, sql.ErrNotFound):
+		http.Error(w, "atx not found", http.StatusNotFound)
+		return
+	case err != nil:
+		s.logger.With().Error("failed to load atx", log.Err(err))
+		http.Error(w, "failed to load atx", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, toATXJSON(atx))
+}
+
+// smesherAtxs returns the atxs of the smesher ordered by the publish epoch. the from query parameter
+// is the first publish epoch of the page.
+func (s *activationService) smesherAtxs(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	raw, err := parseHexID(params["id"], types.NodeIDSize)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	from, err := queryInt(r, "from", 0)
+	if err != nil || from < 0 {
+		http.Error(w, "from must be a non-negative epoch", http.StatusBadRequest)
+		return
+	}
+	limit, err := parseAtxsLimit(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ids, err := atxs.GetIDsByNodeIDFrom(s.atxProvider, types.BytesToNodeID(raw), types.EpochID(from), limit+1)
+	if err != nil {
+		s.logger.With().Error("failed to list smesher atxs", log.Err(err))
+		http.Error(w, "failed to list atxs", http.StatusInternalServerError)
+		return
+	}
+	s.writeAtxs(w, ids, limit, func(last *types.VerifiedActivationTx) string {
+		return strconv.FormatUint(uint64(last.PublishEpoch+1), 10)
+	})
+}
+
+// epochAtxs returns the atxs that target the epoch ordered by id. the after query parameter
+// is the id of the last atx of the previous page.
+func (s *activationService) epochAtxs(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	target, err := strconv.ParseUint(params["epoch"], 10, 32)
+	if err != nil || target == 0 {
+		http.Error(w, fmt.Sprintf("invalid target epoch %q", params["epoch"]), http.StatusBadRequest)
+		return
+	}
+	var after types.ATXID
+	if value := r.URL.Query().Get("after"); value != "" {
+		raw, err := parseHexID(value, types.ATXIDSize)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		after = types.ATXID(types.BytesToHash(raw))
+	}
+	limit, err := parseAtxsLimit(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ids, err := atxs.GetIDsByEpochAfter(s.atxProvider, types.EpochID(target)-1, after, limit+1)
+	if err != nil {
+		s.logger.With().Error("failed to list epoch atxs", log.Err(err))
+		http.Error(w, "failed to list atxs", http.StatusInternalServerError)
+		return
+	}
+	s.writeAtxs(w, ids, limit, func(last *types.VerifiedActivationTx) string {
+		return hex.EncodeToString(last.ID().Bytes())
+	})
+}
+
+// writeAtxs loads up to limit atxs, ids has one more id if there is the next page.
+func (s *activationService) writeAtxs(
+	w http.ResponseWriter,
+	ids []types.ATXID,
+	limit int,
+	next func(*types.VerifiedActivationTx) string,
+) {
+	more := len(ids) > limit
+	if more {
+		ids = ids[:limit]
+	}
+	rst := ATXList{ATXs: make([]ATXJSON, 0, len(ids))}
+	var last *types.VerifiedActivationTx
+	for _, id := range ids {
+		atx, err := s.atxProvider.GetFullAtx(id)
+		if err != nil {
+			s.logger.With().Error("failed to load atx", id, log.Err(err))
+			http.Error(w, "failed to load atx", http.StatusInternalServerError)
+			return
+		}
+		rst.ATXs = append(rst.ATXs, toATXJSON(atx))
+		last = atx
+	}
+	if more && last != nil {
+		rst.Next = next(last)
+	}
+	writeJSON(w, rst)
+}
@@ -0,0 +1,150 @@
+package grpcserver
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/datastore"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/atxs"
+)
+
+func newActivationQueryServer(t *testing.T) (*sql.Database, *httptest.Server) {
+	db := sql.InMemory()
+	svc := NewActivationService(datastore.NewCachedDB(db, logtest.New(t)), types.ATXID{1}, logtest.New(t))
+	mux := runtime.NewServeMux()
+	require.NoError(t, svc.registerQueries(mux))
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return db, srv
+}
+
+func addQueryAtx(t *testing.T, db *sql.Database, smesher types.NodeID, epoch types.EpochID) *types.VerifiedActivationTx {
+	atx := &types.ActivationTx{
+		InnerActivationTx: types.InnerActivationTx{
+			NIPostChallenge: types.NIPostChallenge{
+				PublishEpoch:   epoch,
+				Sequence:       uint64(epoch),
+				PrevATXID:      types.RandomATXID(),
+				PositioningATX: types.RandomATXID(),
+			},
+			NumUnits: 4,
+			Coinbase: types.GenerateAddress(smesher.Bytes()),
+			NIPost: &types.NIPost{
+				Membership:   types.MerkleProof{LeafIndex: 7},
+				PostMetadata: &types.PostMetadata{Challenge: types.RandomBytes(32), LabelsPerUnit: 1024},
+			},
+		},
+		SmesherID: smesher,
+	}
+	atx.SetID(types.RandomATXID())
+	atx.SetEffectiveNumUnits(atx.NumUnits)
+	atx.SetReceived(time.Now().Local())
+	vatx, err := atx.Verify(100, 10)
+	require.NoError(t, err)
+	require.NoError(t, atxs.Add(db, vatx))
+	return vatx
+}
+
+func TestActivationQuery_Atx(t *testing.T) {
+	db, srv := newActivationQueryServer(t)
+	atx := addQueryAtx(t, db, types.RandomNodeID(), 3)
+
+	var rst ATXJSON
+	require.Equal(t, http.StatusOK, getJSON(t, srv.URL+"/v1/activation/atxs/"+hex.EncodeToString(atx.ID().Bytes()), &rst))
+	require.Equal(t, hex.EncodeToString(atx.ID().Bytes()), rst.ID)
+	require.Equal(t, atx.SmesherID.String(), rst.SmesherID)
+	require.EqualValues(t, 3, rst.PublishEpoch)
+	require.EqualValues(t, 4, rst.TargetEpoch)
+	require.EqualValues(t, 4, rst.NumUnits)
+	require.EqualValues(t, 100, rst.BaseTickHeight)
+	require.EqualValues(t, 10, rst.TickCount)
+	require.Equal(t, atx.GetWeight(), rst.Weight)
+	require.NotNil(t, rst.Received)
+	require.NotNil(t, rst.NIPost)
+	require.Equal(t, hex.EncodeToString(atx.NIPost.PostMetadata.Challenge), rst.NIPost.Challenge)
+	require.EqualValues(t, 1024, rst.NIPost.LabelsPerUnit)
+	require.EqualValues(t, 7, rst.NIPost.MembershipLeafIndex)
+
+	require.Equal(t, http.StatusNotFound, getJSON(t, srv.URL+"/v1/activation/atxs/"+hex.EncodeToString(types.RandomATXID().Bytes()), nil))
+	require.Equal(t, http.StatusNotFound, getJSON(t, srv.URL+"/v1/activation/atxs/"+hex.EncodeToString(types.EmptyATXID.Bytes()), nil))
+	require.Equal(t, http.StatusBadRequest, getJSON(t, srv.URL+"/v1/activation/atxs/abcd", nil))
+}
+
+func TestActivationQuery_Smesher(t *testing.T) {
+	db, srv := newActivationQueryServer(t)
+	smesher := types.RandomNodeID()
+	var ids []string
+	for epoch := types.EpochID(1); epoch <= 5; epoch++ {
+		ids = append(ids, hex.EncodeToString(addQueryAtx(t, db, smesher, epoch).ID().Bytes()))
+		addQueryAtx(t, db, types.RandomNodeID(), epoch)
+	}
+	path := fmt.Sprintf("%s/v1/activation/smeshers/%s/atxs", srv.URL, smesher)
+
+	var got []string
+	next := "0"
+	for next != "" {
+		var page ATXList
+		require.Equal(t, http.StatusOK, getJSON(t, path+"?limit=2&from="+next, &page))
+		require.LessOrEqual(t, len(page.ATXs), 2)
+		for _, atx := range page.ATXs {
+			require.Equal(t, smesher.String(), atx.SmesherID)
+			got = append(got, atx.ID)
+		}
+		next = page.Next
+	}
+	require.Equal(t, ids, got)
+
+	var page ATXList
+	require.Equal(t, http.StatusOK, getJSON(t, path+"?from=4", &page))
+	require.Len(t, page.ATXs, 2)
+	require.Empty(t, page.Next)
+
+	require.Equal(t, http.StatusBadRequest, getJSON(t, path+"?limit=0", nil))
+	require.Equal(t, http.StatusBadRequest, getJSON(t, srv.URL+"/v1/activation/smeshers/xyz/atxs", nil))
+}
+
+func TestActivationQuery_Epoch(t *testing.T) {
+	db, srv := newActivationQueryServer(t)
+	var ids []string
+	for i := 0; i < 5; i++ {
+		ids = append(ids, hex.EncodeToString(addQueryAtx(t, db, types.RandomNodeID(), 2).ID().Bytes()))
+	}
+	addQueryAtx(t, db, types.RandomNodeID(), 3)
+	sort.Slice(ids, func(i, j int) bool {
+		return bytes.Compare([]byte(ids[i]), []byte(ids[j])) < 0
+	})
+
+	// atxs published in epoch 2 target epoch 3
+	path := srv.URL + "/v1/activation/epochs/3/atxs"
+	var got []string
+	var page ATXList
+	require.Equal(t, http.StatusOK, getJSON(t, path+"?limit=3", &page))
+	for {
+		for _, atx := range page.ATXs {
+			require.EqualValues(t, 3, atx.TargetEpoch)
+			got = append(got, atx.ID)
+		}
+		if page.Next == "" {
+			break
+		}
+		after := page.Next
+		page = ATXList{}
+		require.Equal(t, http.StatusOK, getJSON(t, path+"?limit=3&after="+after, &page))
+	}
+	require.Equal(t, ids, got)
+
+	require.Equal(t, http.StatusBadRequest, getJSON(t, srv.URL+"/v1/activation/epochs/0/atxs", nil))
+	require.Equal(t, http.StatusBadRequest, getJSON(t, path+"?after=zz", nil))
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/spacemeshos/go-spacemesh/common/types"
 	"github.com/spacemeshos/go-spacemesh/events"
 	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/signing"
 	"github.com/spacemeshos/go-spacemesh/sql"
 )
 
@@ -31,13 +32,15 @@ type AdminService struct {
 	logger  log.Logger
 	db      *sql.Database
 	dataDir string
+	signer  *signing.EdSigner // signs generated checkpoints
 }
 
 // NewAdminService creates a new admin grpc service.
-func NewAdminService(db *sql.Database, dataDir string, lg log.Logger) *AdminService {
+func NewAdminService(db *sql.Database, dataDir string, signer *signing.EdSigner, lg log.Logger) *AdminService {
 	return &AdminService{
 		logger:  lg,
 		db:      db,
+		signer:  signer,
 		dataDir: dataDir,
 	}
 }
@@ -56,7 +59,7 @@ func (a AdminService) CheckpointStream(req *pb.CheckpointStreamRequest, stream p
 	if numAtxs < defaultNumAtxs {
 		numAtxs = defaultNumAtxs
 	}
-	err := checkpoint.Generate(stream.Context(), afero.NewOsFs(), a.db, a.dataDir, snapshot, numAtxs)
+	err := checkpoint.Generate(stream.Context(), afero.NewOsFs(), a.db, a.dataDir, snapshot, numAtxs, a.signer)
 	if err != nil {
 		return status.Errorf(codes.Internal, fmt.Sprintf("failed to create checkpoint: %s", err.Error()))
 	}
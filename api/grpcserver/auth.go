@@ -0,0 +1,198 @@
+package grpcserver
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"golang.org/x/mod/semver"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const authScheme = "bearer"
+
+// TLSEnabled returns true if the private services are served over TLS.
+func (c Config) TLSEnabled() bool {
+	return c.TLSCert != "" || c.TLSKey != ""
+}
+
+// Validate checks that the listeners, the limits and the authentication options are consistent.
+func (c Config) Validate() error {
+	if err := c.validateListeners(); err != nil {
+		return err
+	}
+	if c.GrpcSendMsgSize <= 0 || c.GrpcRecvMsgSize <= 0 {
+		return errors.New("grpc message sizes must be positive")
+	}
+	if c.CompressionLevel < gzip.DefaultCompression || c.CompressionLevel > gzip.BestCompression {
+		return fmt.Errorf("compression level %d is not within [%d, %d]",
+			c.CompressionLevel, gzip.DefaultCompression, gzip.BestCompression)
+	}
+	if (c.TLSCert == "") != (c.TLSKey == "") {
+		return errors.New("both tls certificate and key must be set")
+	}
+	if c.TLSClientCA != "" && !c.TLSEnabled() {
+		return errors.New("tls client ca requires tls certificate and key")
+	}
+	if c.PrivateJSONListener != "" && c.IsPrivate(Admin) && c.AuthToken == "" {
+		return errors.New("admin endpoints of the private json listener require the auth token")
+	}
+	for _, version := range []string{c.MinPeerVersion, c.MinClientVersion} {
+		if version != "" && !semver.IsValid(version) {
+			return fmt.Errorf("min compatible version %q is not a semantic version", version)
+		}
+	}
+	return nil
+}
+
+// NewTLSCredentials loads the server certificate from the config. if the client ca is set
+// clients are required to present a certificate signed by it (mutual tls).
+func NewTLSCredentials(c Config) (credentials.TransportCredentials, error) {
+	conf, err := newTLSConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(conf), nil
+}
+
+func newTLSConfig(c Config) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(c.TLSCert, c.TLSKey)
+	if err != nil {
+		return nil, fmt.Errorf("load tls key pair: %w", err)
+	}
+	conf := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if c.TLSClientCA != "" {
+		pem, err := os.ReadFile(c.TLSClientCA)
+		if err != nil {
+			return nil, fmt.Errorf("read tls client ca: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in tls client ca %s", c.TLSClientCA)
+		}
+		conf.ClientCAs = pool
+		conf.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return conf, nil
+}
+
+// AuthServerOptions returns the options for the grpc server with the private services.
+// the server is served over tls if it is enabled, and requires the bearer token in the
+// authorization header of every request if the token is set.
+func AuthServerOptions(c Config) ([]grpc.ServerOption, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	var opts []grpc.ServerOption
+	if c.TLSEnabled() {
+		creds, err := NewTLSCredentials(c)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+	if c.AuthToken != "" {
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(UnaryTokenAuth(c.AuthToken)),
+			grpc.ChainStreamInterceptor(StreamTokenAuth(c.AuthToken)),
+		)
+	}
+	return opts, nil
+}
+
+// AuthJSONOptions returns the options for the json gateway with the private services,
+// it is protected in the same way as the grpc server with the private services.
+func AuthJSONOptions(c Config) ([]JSONOpt, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	var opts []JSONOpt
+	if c.TLSEnabled() {
+		conf, err := newTLSConfig(c)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithTLSConfig(conf))
+	}
+	if c.AuthToken != "" {
+		opts = append(opts, WithAuthToken(c.AuthToken))
+	}
+	return opts, nil
+}
+
+func checkToken(ctx context.Context, token string) error {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "missing metadata")
+	}
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return status.Error(codes.Unauthenticated, "missing authorization header")
+	}
+	if err := validToken(values[0], token); err != nil {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	return nil
+}
+
+// validToken checks that the value of the authorization header is the bearer token.
+func validToken(header, token string) error {
+	scheme, received, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, authScheme) {
+		return errors.New("expected bearer token")
+	}
+	if subtle.ConstantTimeCompare([]byte(received), []byte(token)) != 1 {
+		return errors.New("invalid token")
+	}
+	return nil
+}
+
+// RequireToken wraps the http handler to reject requests without the bearer token.
+// requests are not checked if the token is empty.
+func RequireToken(token string, handler http.HandlerFunc) http.HandlerFunc {
+	if token == "" {
+		return handler
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := validToken(r.Header.Get("Authorization"), token); err != nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// UnaryTokenAuth returns the interceptor that rejects unary requests without the bearer token.
+func UnaryTokenAuth(token string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := checkToken(ctx, token); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamTokenAuth returns the interceptor that rejects streams without the bearer token.
+func StreamTokenAuth(token string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := checkToken(ss.Context(), token); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,305 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	tls  tls.Certificate
+}
+
+func genCert(t *testing.T, name string, parent *testCert) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	signer, signerKey := tmpl, key
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCert{
+		cert: cert,
+		key:  key,
+		tls:  tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key},
+	}
+}
+
+// write saves the certificate and the key in pem format and returns their paths.
+func (c *testCert) write(t *testing.T, dir string) (string, string) {
+	t.Helper()
+	certPath := filepath.Join(dir, c.cert.Subject.CommonName+".crt")
+	keyPath := filepath.Join(dir, c.cert.Subject.CommonName+".key")
+	require.NoError(t, os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw}), 0o600))
+	der, err := x509.MarshalECPrivateKey(c.key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0o600))
+	return certPath, keyPath
+}
+
+func launchAuthServer(t *testing.T, conf Config) string {
+	t.Helper()
+	opts, err := AuthServerOptions(conf)
+	require.NoError(t, err)
+	return launchHealthServer(t, opts...)
+}
+
+// launchHealthServer starts the server with the health service and returns its address.
+func launchHealthServer(t *testing.T, opts ...grpc.ServerOption) string {
+	t.Helper()
+	port, err := getFreePort(0)
+	require.NoError(t, err)
+	listener := fmt.Sprintf("127.0.0.1:%d", port)
+	srv := New(listener, logtest.New(t).Named("grpc"), opts...)
+	healthpb.RegisterHealthServer(srv.GrpcServer, health.NewServer())
+	select {
+	case <-srv.Start():
+	case <-time.After(3 * time.Second):
+		require.FailNow(t, "server didn't start")
+	}
+	t.Cleanup(func() { _ = srv.Close() })
+	return listener
+}
+
+func checkHealth(t *testing.T, ctx context.Context, address string, creds credentials.TransportCredentials) error {
+	t.Helper()
+	conn, err := grpc.DialContext(ctx, address, grpc.WithTransportCredentials(creds))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{}, grpc.WaitForReady(false))
+	return err
+}
+
+func TestAuth_Token(t *testing.T) {
+	conf := DefaultTestConfig()
+	conf.AuthToken = "secret"
+	address := launchAuthServer(t, conf)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, tc := range []struct {
+		desc  string
+		md    metadata.MD
+		valid bool
+	}{
+		{desc: "no token"},
+		{desc: "invalid token", md: metadata.Pairs("authorization", "Bearer wrong")},
+		{desc: "other scheme", md: metadata.Pairs("authorization", "Basic secret")},
+		{desc: "valid", md: metadata.Pairs("authorization", "Bearer secret"), valid: true},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			err := checkHealth(t, metadata.NewOutgoingContext(ctx, tc.md), address, insecure.NewCredentials())
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			}
+		})
+	}
+}
+
+func TestAuth_MutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := genCert(t, "ca", nil)
+	caPath, _ := ca.write(t, dir)
+	server := genCert(t, "server", ca)
+
+	conf := DefaultTestConfig()
+	conf.TLSCert, conf.TLSKey = server.write(t, dir)
+	conf.TLSClientCA = caPath
+	address := launchAuthServer(t, conf)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	t.Run("valid client cert", func(t *testing.T) {
+		client := genCert(t, "client", ca)
+		creds := credentials.NewTLS(&tls.Config{RootCAs: roots, Certificates: []tls.Certificate{client.tls}})
+		require.NoError(t, checkHealth(t, ctx, address, creds))
+	})
+	t.Run("no client cert", func(t *testing.T) {
+		creds := credentials.NewTLS(&tls.Config{RootCAs: roots})
+		require.Error(t, checkHealth(t, ctx, address, creds))
+	})
+	t.Run("untrusted client cert", func(t *testing.T) {
+		client := genCert(t, "client", genCert(t, "other", nil))
+		creds := credentials.NewTLS(&tls.Config{RootCAs: roots, Certificates: []tls.Certificate{client.tls}})
+		require.Error(t, checkHealth(t, ctx, address, creds))
+	})
+	t.Run("plaintext", func(t *testing.T) {
+		require.Error(t, checkHealth(t, ctx, address, insecure.NewCredentials()))
+	})
+}
+
+func TestAuth_InvalidConfig(t *testing.T) {
+	conf := DefaultTestConfig()
+	conf.TLSCert = "server.crt"
+	_, err := AuthServerOptions(conf)
+	require.Error(t, err)
+
+	conf = DefaultTestConfig()
+	conf.TLSClientCA = "ca.crt"
+	_, err = AuthServerOptions(conf)
+	require.Error(t, err)
+
+	conf = DefaultTestConfig()
+	conf.MinClientVersion = "1.0"
+	require.Error(t, conf.Validate())
+	conf.MinClientVersion = "v1.0.0"
+	require.NoError(t, conf.Validate())
+}
+
+func TestAuth_RequireToken(t *testing.T) {
+	handler := func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) }
+	for _, tc := range []struct {
+		desc, token, header string
+		code                int
+	}{
+		{desc: "disabled", code: http.StatusNoContent},
+		{desc: "no token", token: "secret", code: http.StatusUnauthorized},
+		{desc: "invalid token", token: "secret", header: "Bearer wrong", code: http.StatusUnauthorized},
+		{desc: "valid", token: "secret", header: "Bearer secret", code: http.StatusNoContent},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/debug/admin/shutdown", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			rec := httptest.NewRecorder()
+			RequireToken(tc.token, handler)(rec, req)
+			require.Equal(t, tc.code, rec.Code)
+		})
+	}
+}
+
+func TestAuth_ValidateListeners(t *testing.T) {
+	conf := DefaultTestConfig()
+	require.NoError(t, conf.Validate())
+
+	conf.PublicServices = append(conf.PublicServices, Smesher)
+	require.ErrorContains(t, conf.Validate(), "both public and private")
+
+	conf = DefaultTestConfig()
+	conf.PrivateJSONListener = conf.PrivateListener
+	require.ErrorContains(t, conf.Validate(), "same address")
+
+	// the listener of the disabled services isn't used
+	conf = DefaultTestConfig()
+	conf.PrivateServices = nil
+	conf.PrivateJSONListener = conf.PrivateListener
+	require.NoError(t, conf.Validate())
+
+	// admin endpoints are never served without authentication
+	conf = DefaultTestConfig()
+	conf.PrivateJSONListener = "127.0.0.1:19095"
+	require.ErrorContains(t, conf.Validate(), "auth token")
+	conf.AuthToken = "secret"
+	require.NoError(t, conf.Validate())
+}
+
+func TestAuth_PrivateJSON(t *testing.T) {
+	dir := t.TempDir()
+	ca := genCert(t, "ca", nil)
+	server := genCert(t, "server", ca)
+
+	conf := DefaultTestConfig()
+	conf.TLSCert, conf.TLSKey = server.write(t, dir)
+	conf.AuthToken = "secret"
+	port, err := getFreePort(0)
+	require.NoError(t, err)
+	conf.PrivateJSONListener = fmt.Sprintf("127.0.0.1:%d", port)
+	opts, err := AuthJSONOptions(conf)
+	require.NoError(t, err)
+
+	opts = append(opts, WithAdminHandlers(map[string]http.HandlerFunc{
+		"/debug/admin/shutdown": func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) },
+	}))
+
+	svc := NewNodeService(nil, nil, nil, nil, nil, NodeInfo{Version: "v1.0.0"}, logtest.New(t))
+	srv := NewJSONHTTPServer(conf.PrivateJSONListener, logtest.New(t), opts...)
+	<-srv.StartService(context.Background(), svc)
+	t.Cleanup(func() { require.NoError(t, srv.Shutdown(context.Background())) })
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}}
+	request := func(method, path, token string) int {
+		req, err := http.NewRequest(method, fmt.Sprintf("https://%s%s", conf.PrivateJSONListener, path), nil)
+		require.NoError(t, err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return resp.StatusCode
+	}
+	get := func(token string) int {
+		return request(http.MethodGet, "/v1/node/info", token)
+	}
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", conf.PrivateJSONListener)
+		if err == nil {
+			_ = conn.Close()
+		}
+		return err == nil
+	}, 3*time.Second, 10*time.Millisecond)
+	require.Equal(t, http.StatusUnauthorized, get(""))
+	require.Equal(t, http.StatusUnauthorized, get("wrong"))
+	require.Equal(t, http.StatusOK, get("secret"))
+	require.Equal(t, http.StatusUnauthorized, request(http.MethodPost, "/debug/admin/shutdown", ""))
+	require.Equal(t, http.StatusNoContent, request(http.MethodPost, "/debug/admin/shutdown", "secret"))
+
+	resp, err := http.Get(fmt.Sprintf("http://%s/v1/node/info", conf.PrivateJSONListener))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
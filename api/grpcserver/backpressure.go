@@ -0,0 +1,130 @@
+package grpcserver
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/libp2p/go-libp2p/core/event"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/spacemeshos/go-spacemesh/log"
+)
+
+// OverflowPolicy defines what happens with the stream once the subscriber's buffer is full.
+type OverflowPolicy string
+
+const (
+	// OverflowDisconnect closes the stream, it is the default policy.
+	OverflowDisconnect OverflowPolicy = "disconnect"
+	// OverflowDropOldest drops the oldest buffered event to make room for the new one.
+	OverflowDropOldest OverflowPolicy = "drop-oldest"
+)
+
+const (
+	// streamBufferHeader and streamOverflowHeader are the request metadata keys that configure
+	// the subscriber's buffer. the effective values are sent back in the response header.
+	streamBufferHeader   = "x-stream-buffer-size"
+	streamOverflowHeader = "x-stream-overflow-policy"
+	// streamDroppedTrailer is the response trailer with the number of events dropped for the subscriber.
+	streamDroppedTrailer = "x-stream-dropped"
+)
+
+// backpressure is the buffer size and overflow policy requested by the stream subscriber.
+// it is shared by all subscriptions of the stream and counts the events dropped for it.
+type backpressure struct {
+	buffer  int
+	policy  OverflowPolicy
+	dropped atomic.Uint64
+}
+
+func defaultBackpressure() *backpressure {
+	return &backpressure{buffer: subscriptionChanBufSize, policy: OverflowDisconnect}
+}
+
+// parseBackpressure reads the buffer size and overflow policy from the request metadata.
+func parseBackpressure(ctx context.Context) (*backpressure, error) {
+	bp := defaultBackpressure()
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return bp, nil
+	}
+	if values := md.Get(streamBufferHeader); len(values) > 0 {
+		size, err := strconv.Atoi(values[0])
+		if err != nil || size < 1 || size > subscriptionChanBufSize {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"%s must be within [1, %d]", streamBufferHeader, subscriptionChanBufSize)
+		}
+		bp.buffer = size
+	}
+	if values := md.Get(streamOverflowHeader); len(values) > 0 {
+		switch policy := OverflowPolicy(values[0]); policy {
+		case OverflowDisconnect, OverflowDropOldest:
+			bp.policy = policy
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "%s must be one of %s, %s",
+				streamOverflowHeader, OverflowDisconnect, OverflowDropOldest)
+		}
+	}
+	return bp, nil
+}
+
+// header returns the effective buffer size and overflow policy of the stream.
+func (bp *backpressure) header() metadata.MD {
+	return metadata.Pairs(
+		streamBufferHeader, strconv.Itoa(bp.buffer),
+		streamOverflowHeader, string(bp.policy),
+	)
+}
+
+// setTrailer reports the number of dropped events when the stream is closed.
+func (bp *backpressure) setTrailer(stream grpc.ServerStream) {
+	stream.SetTrailer(metadata.Pairs(streamDroppedTrailer, strconv.FormatUint(bp.dropped.Load(), 10)))
+}
+
+// consumeEventsWith is consumeEvents that applies the subscriber's buffer size and overflow policy.
+// bufFull is closed only if the policy doesn't allow to keep the stream once the buffer is full.
+// the subscriber never blocks the publisher: events are published on the consensus path.
+func consumeEventsWith[T any](ctx context.Context, subscription event.Subscription, bp *backpressure) (out <-chan T, bufFull <-chan struct{}) {
+	outCh := make(chan T, bp.buffer)
+	bufFullCh := make(chan struct{})
+
+	go func() {
+		defer closeSubscription(subscription)
+
+		for e := range subscription.Out() {
+			event, ok := e.(T)
+			if !ok {
+				log.With().Warning("received invalid event type - dropping")
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case outCh <- event:
+				continue
+			default:
+			}
+			switch bp.policy {
+			case OverflowDropOldest:
+				select {
+				case <-outCh:
+					bp.dropped.Add(1)
+				default:
+				}
+				// this goroutine is the only sender, so there is room for the event after the receive above
+				outCh <- event
+			default:
+				log.With().Debug("subscriber's event buffer is full")
+				bp.dropped.Add(1)
+				close(bufFullCh)
+				return
+			}
+		}
+	}()
+
+	return outCh, bufFullCh
+}
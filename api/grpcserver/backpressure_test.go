@@ -0,0 +1,87 @@
+package grpcserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/events"
+)
+
+func TestParseBackpressure(t *testing.T) {
+	for _, tc := range []struct {
+		desc   string
+		md     metadata.MD
+		buffer int
+		policy OverflowPolicy
+		err    bool
+	}{
+		{desc: "default", buffer: subscriptionChanBufSize, policy: OverflowDisconnect},
+		{
+			desc:   "configured",
+			md:     metadata.Pairs(streamBufferHeader, "10", streamOverflowHeader, string(OverflowDropOldest)),
+			buffer: 10, policy: OverflowDropOldest,
+		},
+		{desc: "zero buffer", md: metadata.Pairs(streamBufferHeader, "0"), err: true},
+		{desc: "large buffer", md: metadata.Pairs(streamBufferHeader, "100000000"), err: true},
+		{desc: "invalid buffer", md: metadata.Pairs(streamBufferHeader, "ten"), err: true},
+		{desc: "invalid policy", md: metadata.Pairs(streamOverflowHeader, "drop-newest"), err: true},
+		{desc: "blocking policy", md: metadata.Pairs(streamOverflowHeader, "block"), err: true},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			bp, err := parseBackpressure(metadata.NewIncomingContext(context.Background(), tc.md))
+			if tc.err {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.buffer, bp.buffer)
+			require.Equal(t, tc.policy, bp.policy)
+		})
+	}
+}
+
+func consumeLayers(t *testing.T, bp *backpressure, n int) (<-chan events.LayerUpdate, <-chan struct{}) {
+	events.CloseEventReporter()
+	events.InitializeReporter()
+	t.Cleanup(events.CloseEventReporter)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	out, full := consumeEventsWith[events.LayerUpdate](ctx, events.SubscribeLayers(), bp)
+	for i := 1; i <= n; i++ {
+		events.ReportLayerUpdate(events.LayerUpdate{LayerID: types.LayerID(i)})
+	}
+	return out, full
+}
+
+func TestConsumeEvents_Disconnect(t *testing.T) {
+	bp := &backpressure{buffer: 2, policy: OverflowDisconnect}
+	_, full := consumeLayers(t, bp, 3)
+	select {
+	case <-full:
+	case <-time.After(time.Second):
+		require.Fail(t, "stream wasn't disconnected")
+	}
+	require.EqualValues(t, 1, bp.dropped.Load())
+}
+
+func TestConsumeEvents_DropOldest(t *testing.T) {
+	bp := &backpressure{buffer: 2, policy: OverflowDropOldest}
+	out, full := consumeLayers(t, bp, 5)
+	require.Eventually(t, func() bool { return bp.dropped.Load() == 3 }, time.Second, time.Millisecond)
+	require.Equal(t, types.LayerID(4), (<-out).LayerID)
+	require.Equal(t, types.LayerID(5), (<-out).LayerID)
+	select {
+	case <-full:
+		require.Fail(t, "stream was disconnected")
+	default:
+	}
+}
@@ -0,0 +1,100 @@
+package grpcserver
+
+import (
+	"compress/gzip"
+	"fmt"
+	"net/http"
+	"strings"
+
+	grpcgzip "google.golang.org/grpc/encoding/gzip" // registers gzip compressor for the grpc servers
+)
+
+// SetCompressionLevel sets the level of the gzip compression of the grpc responses.
+// grpc clients that send the requests compressed with gzip get the responses compressed
+// with the same compressor. it must be called before the servers are started.
+func SetCompressionLevel(c Config) error {
+	if err := grpcgzip.SetLevel(c.CompressionLevel); err != nil {
+		return fmt.Errorf("set gzip level: %w", err)
+	}
+	return nil
+}
+
+// gzipHandler compresses the responses of the json gateway for the clients that accept gzip encoding.
+// websocket upgrades are passed through, as the hijacked connection can't be compressed.
+func gzipHandler(level int, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !acceptsGzip(r) || r.Header.Get("Upgrade") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Add("Vary", "Accept-Encoding")
+		gw := &gzipResponseWriter{ResponseWriter: w, level: level}
+		defer gw.close()
+		next.ServeHTTP(gw, r)
+	})
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, encoding := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		name, _, _ := strings.Cut(encoding, ";")
+		if strings.TrimSpace(name) == "gzip" {
+			return true
+		}
+	}
+	return false
+}
+
+// gzipResponseWriter compresses the body of the response. responses that can't have the body,
+// such as 204 No Content, are sent as is.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	level       int
+	wroteHeader bool
+	gz          *gzip.Writer
+}
+
+func (w *gzipResponseWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if code != http.StatusNoContent && code != http.StatusNotModified {
+			w.Header().Set("Content-Encoding", "gzip")
+			// the length of the compressed body is not known in advance
+			w.Header().Del("Content-Length")
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *gzipResponseWriter) Write(buf []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.gz == nil {
+		gz, err := gzip.NewWriterLevel(w.ResponseWriter, w.level)
+		if err != nil {
+			return 0, err
+		}
+		w.gz = gz
+	}
+	return w.gz.Write(buf)
+}
+
+// Flush sends the compressed data written so far, it is used by the streaming endpoints.
+func (w *gzipResponseWriter) Flush() {
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *gzipResponseWriter) close() {
+	if w.gz == nil && w.Header().Get("Content-Encoding") == "gzip" {
+		// the header was written without the body, the empty body must be a valid gzip stream too
+		w.gz, _ = gzip.NewWriterLevel(w.ResponseWriter, w.level)
+	}
+	if w.gz != nil {
+		_ = w.gz.Close()
+	}
+}
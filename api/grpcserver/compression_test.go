@@ -0,0 +1,94 @@
+package grpcserver
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	grpcgzip "google.golang.org/grpc/encoding/gzip"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestGzipHandler(t *testing.T) {
+	body := strings.Repeat(`{"layer": 1}`, 100)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/body", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, body)
+	})
+	mux.HandleFunc("/empty", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	srv := httptest.NewServer(gzipHandler(gzip.BestSpeed, mux))
+	t.Cleanup(srv.Close)
+
+	get := func(path, encoding string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+		require.NoError(t, err)
+		if encoding != "" {
+			// setting the header explicitly disables transparent decompression of the transport
+			req.Header.Set("Accept-Encoding", encoding)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		return resp
+	}
+
+	t.Run("compressed", func(t *testing.T) {
+		resp := get("/body", "br, gzip;q=0.8")
+		require.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+		gz, err := gzip.NewReader(resp.Body)
+		require.NoError(t, err)
+		decompressed, err := io.ReadAll(gz)
+		require.NoError(t, err)
+		require.Equal(t, body, string(decompressed))
+	})
+	t.Run("not accepted", func(t *testing.T) {
+		resp := get("/body", "identity")
+		require.Empty(t, resp.Header.Get("Content-Encoding"))
+		plain, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, body, string(plain))
+	})
+	t.Run("no content", func(t *testing.T) {
+		resp := get("/empty", "gzip")
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+		require.Empty(t, resp.Header.Get("Content-Encoding"))
+	})
+}
+
+func TestGrpcCompression(t *testing.T) {
+	conf := DefaultTestConfig()
+	conf.CompressionLevel = gzip.BestSpeed
+	require.NoError(t, SetCompressionLevel(conf))
+	t.Cleanup(func() { require.NoError(t, SetCompressionLevel(DefaultTestConfig())) })
+	address := launchHealthServer(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{},
+		grpc.UseCompressor(grpcgzip.Name))
+	require.NoError(t, err)
+}
+
+func TestConfig_ValidateLimits(t *testing.T) {
+	conf := DefaultTestConfig()
+	conf.CompressionLevel = gzip.BestCompression + 1
+	require.ErrorContains(t, conf.Validate(), "compression level")
+
+	conf = DefaultTestConfig()
+	conf.GrpcRecvMsgSize = 0
+	require.ErrorContains(t, conf.Validate(), "message sizes")
+}
@@ -2,6 +2,8 @@
 package grpcserver
 
 import (
+	"compress/gzip"
+	"fmt"
 	"time"
 )
 
@@ -10,9 +12,58 @@ type Config struct {
 	PublicListener  string    `mapstructure:"grpc-public-listener"`
 	PrivateServices []Service `mapstructure:"grpc-private-services"`
 	PrivateListener string    `mapstructure:"grpc-private-listener"`
-	GrpcSendMsgSize int       `mapstructure:"grpc-send-msg-size"`
-	GrpcRecvMsgSize int       `mapstructure:"grpc-recv-msg-size"`
-	JSONListener    string    `mapstructure:"grpc-json-listener"`
+	// GrpcSendMsgSize and GrpcRecvMsgSize are the max sizes of the messages in bytes. the receive size
+	// also limits the size of the request body of the json gateway.
+	GrpcSendMsgSize int `mapstructure:"grpc-send-msg-size"`
+	GrpcRecvMsgSize int `mapstructure:"grpc-recv-msg-size"`
+	// CompressionLevel is the level of the gzip compression of the responses, from -1 (default) to 9.
+	// grpc clients negotiate compression by sending compressed requests, json clients with
+	// the Accept-Encoding header.
+	CompressionLevel int `mapstructure:"grpc-compression-level"`
+
+	JSONListener string `mapstructure:"grpc-json-listener"`
+	// PrivateJSONListener serves the grpc gateway for the private services, if set. it is protected
+	// with the same tls and token options as the private grpc listener.
+	PrivateJSONListener string `mapstructure:"grpc-private-json-listener"`
+	// WebsocketOrigins are the patterns of the origins of the browser pages allowed to connect
+	// to the websocket bridge on the json listener, e.g. wallet.example.com or *.example.com.
+	WebsocketOrigins []string `mapstructure:"grpc-ws-origins"`
+	// MaxConcurrentStreams is the max number of concurrent streams of a single connection. zero means no limit.
+	MaxConcurrentStreams uint32 `mapstructure:"grpc-max-concurrent-streams"`
+	// Reflection enables the server reflection service on the grpc servers.
+	Reflection bool `mapstructure:"grpc-reflection"`
+
+	// TLSCert and TLSKey enable tls for the private services.
+	TLSCert string `mapstructure:"grpc-tls-cert"`
+	TLSKey  string `mapstructure:"grpc-tls-key"`
+	// TLSClientCA requires clients of the private services to present a certificate signed by this ca.
+	TLSClientCA string `mapstructure:"grpc-tls-client-ca"`
+	// AuthToken requires clients of the private services to send it as a bearer token.
+	AuthToken string `mapstructure:"grpc-auth-token"`
+
+	// RateLimit is the number of requests per second allowed for a single client. zero disables the limit.
+	RateLimit      float64 `mapstructure:"grpc-rate-limit"`
+	RateLimitBurst int     `mapstructure:"grpc-rate-limit-burst"`
+	// MethodRateLimits are the limits for the requests of a single client to the methods,
+	// keyed by the full method name (e.g. /spacemesh.v1.MeshService/LayersQuery), or by the
+	// path of the json gateway request (e.g. /v1/mesh/layersquery).
+	MethodRateLimits map[string]float64 `mapstructure:"grpc-method-rate-limits"`
+
+	// SlowRequestThreshold is the duration after which the unary requests are logged as slow, together
+	// with the address of the client. zero disables the logging.
+	SlowRequestThreshold time.Duration `mapstructure:"grpc-slow-request-threshold"`
+
+	// HealthListener serves liveness and readiness probes over plain http, if set.
+	HealthListener string `mapstructure:"grpc-health-listener"`
+	// ReadyMaxLayersBehind is the max number of layers the processed layer can lag behind the current
+	// layer for the node to be ready.
+	ReadyMaxLayersBehind uint32 `mapstructure:"grpc-ready-max-layers-behind"`
+	// ReadyMinPeers is the min number of connected peers for the node to be ready.
+	ReadyMinPeers int `mapstructure:"grpc-ready-min-peers"`
+
+	// MinPeerVersion and MinClientVersion are the oldest compatible versions advertised to the clients.
+	MinPeerVersion   string `mapstructure:"grpc-min-peer-version"`
+	MinClientVersion string `mapstructure:"grpc-min-client-version"`
 
 	SmesherStreamInterval time.Duration
 }
@@ -30,6 +81,46 @@ const (
 	Node        Service = "node"
 )
 
+// IsPrivate returns true if the service is served on the private listener, and requires
+// authentication if it is configured.
+func (c Config) IsPrivate(svc Service) bool {
+	for _, private := range c.PrivateServices {
+		if private == svc {
+			return true
+		}
+	}
+	return false
+}
+
+// validateListeners checks that every service is either public or private, and that the
+// enabled listeners don't share the same address.
+func (c Config) validateListeners() error {
+	for _, svc := range c.PublicServices {
+		if c.IsPrivate(svc) {
+			return fmt.Errorf("service %s can't be both public and private", svc)
+		}
+	}
+	listeners := map[string]string{}
+	for _, listener := range []struct {
+		name, address string
+		enabled       bool
+	}{
+		{"grpc-public-listener", c.PublicListener, len(c.PublicServices) > 0},
+		{"grpc-private-listener", c.PrivateListener, len(c.PrivateServices) > 0},
+		{"grpc-json-listener", c.JSONListener, c.JSONListener != ""},
+		{"grpc-private-json-listener", c.PrivateJSONListener, c.PrivateJSONListener != ""},
+	} {
+		if !listener.enabled {
+			continue
+		}
+		if other, exists := listeners[listener.address]; exists {
+			return fmt.Errorf("%s and %s can't use the same address %s", other, listener.name, listener.address)
+		}
+		listeners[listener.address] = listener.name
+	}
+	return nil
+}
+
 // DefaultConfig defines the default configuration options for api.
 func DefaultConfig() Config {
 	return Config{
@@ -40,6 +131,10 @@ func DefaultConfig() Config {
 		JSONListener:          "",
 		GrpcSendMsgSize:       1024 * 1024 * 10,
 		GrpcRecvMsgSize:       1024 * 1024 * 10,
+		CompressionLevel:      gzip.DefaultCompression,
+		Reflection:            true,
+		ReadyMaxLayersBehind:  10,
+		ReadyMinPeers:         1,
 		SmesherStreamInterval: time.Second,
 	}
 }
@@ -2,6 +2,7 @@ package grpcserver
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/golang/protobuf/ptypes/empty"
@@ -56,6 +57,9 @@ func (d DebugService) Accounts(_ context.Context, in *pb.AccountsRequest) (*pb.A
 	} else {
 		accts, err = accounts.Snapshot(d.db, types.LayerID(in.Layer))
 	}
+	if errors.Is(err, accounts.ErrPruned) {
+		return nil, status.Errorf(codes.OutOfRange, "accounts state at layer %d is pruned", in.Layer)
+	}
 	if err != nil {
 		d.logger.Error("Failed to get all accounts from state: %s", err)
 		return nil, status.Errorf(codes.Internal, "error fetching accounts state")
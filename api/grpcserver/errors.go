@@ -0,0 +1,83 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/spacemeshos/go-spacemesh/sql"
+)
+
+// ErrorDomain is the domain of the error info attached to the errors returned by the grpc services.
+const ErrorDomain = "spacemesh.io"
+
+// errorCode returns the grpc code for the internal error.
+func errorCode(err error) codes.Code {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	case errors.Is(err, sql.ErrNotFound):
+		return codes.NotFound
+	case errors.Is(err, sql.ErrObjectExists):
+		return codes.AlreadyExists
+	case errors.Is(err, sql.ErrNoConnection):
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
+
+// errorReason converts the grpc code to the reason of the error info, e.g. NotFound to NOT_FOUND.
+func errorReason(code codes.Code) string {
+	var reason strings.Builder
+	for i, r := range code.String() {
+		if i > 0 && unicode.IsUpper(r) {
+			reason.WriteByte('_')
+		}
+		reason.WriteRune(unicode.ToUpper(r))
+	}
+	return reason.String()
+}
+
+// ToStatusError converts the error returned by the grpc service to the status error with the code
+// that matches the internal error, instead of Unknown. the error info with the machine readable reason
+// is attached to the status, unless it already has details.
+func ToStatusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		st = status.New(errorCode(err), err.Error())
+	}
+	if st.Code() == codes.OK || len(st.Details()) > 0 {
+		return st.Err()
+	}
+	detailed, derr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: errorReason(st.Code()),
+		Domain: ErrorDomain,
+	})
+	if derr != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// UnaryErrorCodes converts the errors returned by the unary handlers with ToStatusError.
+func UnaryErrorCodes(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	return resp, ToStatusError(err)
+}
+
+// StreamErrorCodes converts the errors returned by the stream handlers with ToStatusError.
+func StreamErrorCodes(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return ToStatusError(handler(srv, ss))
+}
@@ -0,0 +1,70 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/spacemeshos/go-spacemesh/sql"
+)
+
+func TestToStatusError(t *testing.T) {
+	for _, tc := range []struct {
+		desc   string
+		err    error
+		code   codes.Code
+		reason string
+	}{
+		{"internal", errors.New("test"), codes.Internal, "INTERNAL"},
+		{"not found", fmt.Errorf("get atx: %w", sql.ErrNotFound), codes.NotFound, "NOT_FOUND"},
+		{"exists", sql.ErrObjectExists, codes.AlreadyExists, "ALREADY_EXISTS"},
+		{"canceled", context.Canceled, codes.Canceled, "CANCELED"},
+		{"deadline", fmt.Errorf("wait: %w", context.DeadlineExceeded), codes.DeadlineExceeded, "DEADLINE_EXCEEDED"},
+		{"status", status.Error(codes.InvalidArgument, "test"), codes.InvalidArgument, "INVALID_ARGUMENT"},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			st, ok := status.FromError(ToStatusError(tc.err))
+			require.True(t, ok)
+			require.Equal(t, tc.code, st.Code())
+			require.Contains(t, tc.err.Error(), st.Message())
+			require.Len(t, st.Details(), 1)
+			info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+			require.True(t, ok)
+			require.Equal(t, tc.reason, info.Reason)
+			require.Equal(t, ErrorDomain, info.Domain)
+		})
+	}
+
+	require.NoError(t, ToStatusError(nil))
+
+	// details set by the service are preserved
+	st, err := status.New(codes.FailedPrecondition, "test").WithDetails(&errdetails.ErrorInfo{Reason: "CUSTOM"})
+	require.NoError(t, err)
+	converted, ok := status.FromError(ToStatusError(st.Err()))
+	require.True(t, ok)
+	require.Len(t, converted.Details(), 1)
+	require.Equal(t, "CUSTOM", converted.Details()[0].(*errdetails.ErrorInfo).Reason)
+}
+
+func TestUnaryErrorCodes(t *testing.T) {
+	_, err := UnaryErrorCodes(context.Background(), nil, &grpc.UnaryServerInfo{},
+		func(context.Context, any) (any, error) {
+			return nil, sql.ErrNotFound
+		})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	resp, err := UnaryErrorCodes(context.Background(), nil, &grpc.UnaryServerInfo{},
+		func(context.Context, any) (any, error) {
+			return "ok", nil
+		})
+	require.NoError(t, err)
+	require.Equal(t, "ok", resp)
+}
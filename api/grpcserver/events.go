@@ -22,31 +22,7 @@ var (
 )
 
 func consumeEvents[T any](ctx context.Context, subscription event.Subscription) (out <-chan T, bufFull <-chan struct{}) {
-	outCh := make(chan T, subscriptionChanBufSize)
-	bufFullCh := make(chan struct{})
-
-	go func() {
-		defer closeSubscription(subscription)
-
-		for e := range subscription.Out() {
-			event, ok := e.(T)
-			if !ok {
-				log.With().Warning("received invalid event type - dropping")
-				continue
-			}
-			select {
-			case <-ctx.Done():
-				return
-			case outCh <- event:
-			default:
-				log.With().Debug("subscriber's event buffer is full")
-				close(bufFullCh)
-				return
-			}
-		}
-	}()
-
-	return outCh, bufFullCh
+	return consumeEventsWith[T](ctx, subscription, defaultBackpressure())
 }
 
 func closeSubscription(accountSubscription event.Subscription) {
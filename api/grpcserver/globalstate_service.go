@@ -194,8 +194,37 @@ func (s GlobalStateService) AccountDataQuery(_ context.Context, in *pb.AccountDa
 
 // SmesherDataQuery returns historical info on smesher rewards.
 func (s GlobalStateService) SmesherDataQuery(_ context.Context, in *pb.SmesherDataQueryRequest) (*pb.SmesherDataQueryResponse, error) {
-	s.logger.Info("DEPRECATED GRPC GlobalStateService.SmesherDataQuery")
-	return nil, status.Errorf(codes.Unimplemented, "DEPRECATED")
+	s.logger.Info("GRPC GlobalStateService.SmesherDataQuery")
+
+	if in.SmesherId == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "`SmesherId` must be provided")
+	}
+	if len(in.SmesherId.Id) != types.NodeIDSize {
+		return nil, status.Errorf(codes.InvalidArgument, "`SmesherId` must be %d bytes", types.NodeIDSize)
+	}
+	smesher := types.BytesToNodeID(in.SmesherId.Id)
+
+	// If MaxResults is zero, that means unlimited
+	limit := -1
+	if in.MaxResults > 0 {
+		limit = int(in.MaxResults)
+	}
+	rewards, total, err := s.mesh.GetSmesherRewards(smesher, int(in.Offset), limit)
+	if err != nil {
+		s.logger.With().Error("unable to fetch smesher rewards", smesher, log.Err(err))
+		return nil, status.Errorf(codes.Internal, "error getting rewards data")
+	}
+	res := &pb.SmesherDataQueryResponse{TotalResults: uint32(total)}
+	for _, r := range rewards {
+		res.Rewards = append(res.Rewards, &pb.Reward{
+			Layer:       &pb.LayerNumber{Number: r.Layer.Uint32()},
+			Total:       &pb.Amount{Value: r.TotalReward},
+			LayerReward: &pb.Amount{Value: r.LayerReward},
+			Coinbase:    &pb.AccountId{Address: r.Coinbase.String()},
+			Smesher:     &pb.SmesherId{Id: smesher.Bytes()},
+		})
+	}
+	return res, nil
 }
 
 // STREAMS
@@ -336,12 +365,18 @@ func (s GlobalStateService) AppEventStream(*pb.AppEventStreamRequest, pb.GlobalS
 }
 
 // GlobalStateStream exposes a stream of global data data items: rewards, receipts, account info, global state hash.
+// the subscriber can configure its buffer and overflow policy, see parseBackpressure.
 func (s GlobalStateService) GlobalStateStream(in *pb.GlobalStateStreamRequest, stream pb.GlobalStateService_GlobalStateStreamServer) error {
 	s.logger.Info("GRPC GlobalStateService.GlobalStateStream")
 
 	if in.GlobalStateDataFlags == uint32(pb.GlobalStateDataFlag_GLOBAL_STATE_DATA_FLAG_UNSPECIFIED) {
 		return status.Errorf(codes.InvalidArgument, "`GlobalStateDataFlags` must set at least one bitfield")
 	}
+	bp, err := parseBackpressure(stream.Context())
+	if err != nil {
+		return err
+	}
+	defer bp.setTrailer(stream)
 
 	filterAccount := in.GlobalStateDataFlags&uint32(pb.GlobalStateDataFlag_GLOBAL_STATE_DATA_FLAG_ACCOUNT) != 0
 	filterReward := in.GlobalStateDataFlags&uint32(pb.GlobalStateDataFlag_GLOBAL_STATE_DATA_FLAG_REWARD) != 0
@@ -358,12 +393,12 @@ func (s GlobalStateService) GlobalStateStream(in *pb.GlobalStateStreamRequest, s
 	)
 	if filterAccount {
 		if accountSubscription := events.SubscribeAccount(); accountSubscription != nil {
-			accountCh, accountBufFull = consumeEvents[events.Account](stream.Context(), accountSubscription)
+			accountCh, accountBufFull = consumeEventsWith[events.Account](stream.Context(), accountSubscription, bp)
 		}
 	}
 	if filterReward {
 		if rewardsSubscription := events.SubscribeRewards(); rewardsSubscription != nil {
-			rewardsCh, rewardsBufFull = consumeEvents[events.Reward](stream.Context(), rewardsSubscription)
+			rewardsCh, rewardsBufFull = consumeEventsWith[events.Reward](stream.Context(), rewardsSubscription, bp)
 		}
 	}
 
@@ -371,9 +406,12 @@ func (s GlobalStateService) GlobalStateStream(in *pb.GlobalStateStreamRequest, s
 		// Whenever new state is applied to the mesh, a new layer is reported.
 		// There is no separate reporting specifically for new state.
 		if layersSubscription := events.SubscribeLayers(); layersSubscription != nil {
-			layersCh, layersBufFull = consumeEvents[events.LayerUpdate](stream.Context(), layersSubscription)
+			layersCh, layersBufFull = consumeEventsWith[events.LayerUpdate](stream.Context(), layersSubscription, bp)
 		}
 	}
+	if err := stream.SendHeader(bp.header()); err != nil {
+		return err
+	}
 
 	for {
 		select {
@@ -58,7 +58,6 @@ func (s *Server) startInternal(started chan<- struct{}) {
 		s.logger.Error("error listening: %v", err)
 		return
 	}
-	reflection.Register(s.GrpcServer)
 	s.logger.Info("starting new grpc server on %s", s.Listener)
 	close(started)
 	if err := s.GrpcServer.Serve(lis); err != nil {
@@ -66,6 +65,12 @@ func (s *Server) startInternal(started chan<- struct{}) {
 	}
 }
 
+// EnableReflection registers the server reflection service, which allows clients
+// to discover the services and their methods.
+func (s *Server) EnableReflection() {
+	reflection.Register(s.GrpcServer)
+}
+
 // Close stops the server.
 func (s *Server) Close() error {
 	s.logger.Info("stopping the grpc server")
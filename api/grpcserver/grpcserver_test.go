@@ -30,9 +30,11 @@ import (
 	"github.com/stretchr/testify/require"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	"github.com/spacemeshos/go-spacemesh/activation"
@@ -43,7 +45,9 @@ import (
 	vm "github.com/spacemeshos/go-spacemesh/genvm"
 	"github.com/spacemeshos/go-spacemesh/genvm/sdk"
 	"github.com/spacemeshos/go-spacemesh/genvm/sdk/wallet"
+	hareConfig "github.com/spacemeshos/go-spacemesh/hare/config"
 	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/mesh"
 	"github.com/spacemeshos/go-spacemesh/p2p"
 	pubsubmocks "github.com/spacemeshos/go-spacemesh/p2p/pubsub/mocks"
 	"github.com/spacemeshos/go-spacemesh/rand"
@@ -285,6 +289,30 @@ func (m *MeshAPIMock) ProcessedLayer() types.LayerID {
 	return layerVerified
 }
 
+func (m *MeshAPIMock) MeshStatus() mesh.Status {
+	return mesh.Status{
+		Latest:    layerLatest,
+		Processed: layerVerified,
+		Applied:   layerVerified,
+		Verified:  layerVerified,
+	}
+}
+
+func (m *MeshAPIMock) BallotsBySmesher(types.NodeID, types.EpochID) ([]*types.Ballot, error) {
+	return nil, nil
+}
+
+func (m *MeshAPIMock) BlocksBySmesher(types.NodeID, types.EpochID) ([]types.BlockID, error) {
+	return nil, nil
+}
+
+func (m *MeshAPIMock) VerificationStatus() mesh.VerificationStatus {
+	return mesh.VerificationStatus{
+		Processed: layerVerified,
+		Verified:  layerVerified,
+	}
+}
+
 func (m *MeshAPIMock) GetRewards(types.Address) (rewards []*types.Reward, err error) {
 	return []*types.Reward{
 		{
@@ -296,6 +324,17 @@ func (m *MeshAPIMock) GetRewards(types.Address) (rewards []*types.Reward, err er
 	}, nil
 }
 
+func (m *MeshAPIMock) GetSmesherRewards(types.NodeID, int, int) ([]*types.Reward, int, error) {
+	return []*types.Reward{
+		{
+			Layer:       layerFirst,
+			TotalReward: rewardAmount,
+			LayerReward: rewardAmount,
+			Coinbase:    addr1,
+		},
+	}, 1, nil
+}
+
 func (m *MeshAPIMock) GetLayer(tid types.LayerID) (*types.Layer, error) {
 	if tid.After(layerCurrent) {
 		return nil, errors.New("requested layer later than current layer")
@@ -308,6 +347,32 @@ func (m *MeshAPIMock) GetLayer(tid types.LayerID) (*types.Layer, error) {
 	return types.NewExistingLayer(tid, ballots, blocks), nil
 }
 
+func (m *MeshAPIMock) IterateLayerBlocks(tid types.LayerID, fn func(*types.Block) bool) error {
+	layer, err := m.GetLayer(tid)
+	if err != nil {
+		return err
+	}
+	for _, block := range layer.Blocks() {
+		if !fn(block) {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *MeshAPIMock) IterateLayerBallots(tid types.LayerID, fn func(*types.Ballot) bool) error {
+	layer, err := m.GetLayer(tid)
+	if err != nil {
+		return err
+	}
+	for _, ballot := range layer.Ballots() {
+		if !fn(ballot) {
+			return nil
+		}
+	}
+	return nil
+}
+
 func (m *MeshAPIMock) GetATXs(context.Context, []types.ATXID) (map[types.ATXID]*types.VerifiedActivationTx, []types.ATXID) {
 	atxs := map[types.ATXID]*types.VerifiedActivationTx{
 		globalAtx.ID():  globalAtx,
@@ -371,6 +436,22 @@ func (t *ConStateAPIMock) GetMeshTransaction(id types.TransactionID) (*types.Mes
 	return nil, errors.New("it ain't there")
 }
 
+func (t *ConStateAPIMock) ListTransactionsByAddress(from, to types.LayerID, account types.Address, offset, limit int) ([]*types.MeshTransaction, int, error) {
+	txs, err := t.GetTransactionsByAddress(from, to, account)
+	if err != nil {
+		return nil, 0, err
+	}
+	total := len(txs)
+	if offset >= total {
+		return nil, total, nil
+	}
+	txs = txs[offset:]
+	if limit >= 0 && limit < len(txs) {
+		txs = txs[:limit]
+	}
+	return txs, total, nil
+}
+
 func (t *ConStateAPIMock) GetTransactionsByAddress(from, to types.LayerID, account types.Address) ([]*types.MeshTransaction, error) {
 	if from.After(txReturnLayer) {
 		return nil, nil
@@ -399,6 +480,14 @@ func (t *ConStateAPIMock) GetLayerStateRoot(types.LayerID) (types.Hash32, error)
 	return stateRoot, nil
 }
 
+func (t *ConStateAPIMock) AccountProof(types.Address, types.LayerID) (*types.AccountProof, types.Hash32, error) {
+	panic("not implemented")
+}
+
+func (t *ConStateAPIMock) GetAccount(types.Address) (types.Account, error) {
+	panic("not implemented")
+}
+
 func (t *ConStateAPIMock) GetBalance(addr types.Address) (uint64, error) {
 	return t.balances[addr].Uint64(), nil
 }
@@ -407,6 +496,40 @@ func (t *ConStateAPIMock) GetNonce(addr types.Address) (types.Nonce, error) {
 	return t.nonces[addr], nil
 }
 
+func (t *ConStateAPIMock) ListMempool(principal types.Address, offset, limit int) ([]*txs.NanoTX, int) {
+	var rst []*txs.NanoTX
+	for _, tx := range t.poolByTxId {
+		if principal == (types.Address{}) || tx.Principal == principal {
+			rst = append(rst, txs.NewNanoTX(&types.MeshTransaction{Transaction: *tx}))
+		}
+	}
+	return rst, len(rst)
+}
+
+func (t *ConStateAPIMock) MempoolStats() txs.MempoolStats {
+	return txs.MempoolStats{Transactions: len(t.poolByTxId)}
+}
+
+func (t *ConStateAPIMock) Projection(types.Address) txs.AccountProjection {
+	return txs.AccountProjection{
+		Nonce:            accountCounter,
+		Balance:          accountBalance,
+		NextNonce:        accountCounter + 1,
+		SpendableBalance: accountBalance + 1,
+	}
+}
+
+func (t *ConStateAPIMock) EstimateGasPrice(target int) txs.FeeEstimate {
+	return txs.FeeEstimate{GasPrice: 1, TargetLayers: target}
+}
+
+func (t *ConStateAPIMock) MempoolStatus(id types.TransactionID) (txs.MempoolStatus, string, error) {
+	if _, ok := t.poolByTxId[id]; ok {
+		return txs.MempoolPending, "", nil
+	}
+	return txs.MempoolUnknown, "", nil
+}
+
 func (t *ConStateAPIMock) Validation(raw types.RawTx) system.ValidationRequest {
 	panic("dont use this")
 }
@@ -461,6 +584,18 @@ func (*SmeshingAPIMock) StopSmeshing(bool) error {
 	return nil
 }
 
+func (*SmeshingAPIMock) Paused() bool {
+	return false
+}
+
+func (*SmeshingAPIMock) PauseSmeshing() error {
+	return nil
+}
+
+func (*SmeshingAPIMock) ResumeSmeshing() error {
+	return nil
+}
+
 func (*SmeshingAPIMock) SmesherID() types.NodeID {
 	return signer.NodeID()
 }
@@ -556,13 +691,16 @@ func TestNodeService(t *testing.T) {
 	peerCounter := NewMockpeerCounter(ctrl)
 	peerCounter.EXPECT().PeerCount().Return(uint64(0)).AnyTimes()
 	genTime := NewMockgenesisTimeAPI(ctrl)
+	hare := NewMockhareParams(ctrl)
+	hareParams := hareConfig.EpochParams{Epoch: 2, N: 20, Threshold: 11, ExpectedLeaders: 5}
+	hare.EXPECT().ActiveParams().Return(hareParams).AnyTimes()
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
 
 	version := "v0.0.0"
 	build := "cafebabe"
-	grpcService := NewNodeService(peerCounter, meshAPIMock, genTime, syncer, version, build, logtest.New(t).WithName("grpc.Node"))
+	grpcService := NewNodeService(peerCounter, meshAPIMock, genTime, syncer, hare, NodeInfo{Version: version, Commit: build}, logtest.New(t).WithName("grpc.Node"))
 	t.Cleanup(launchServer(t, cfg, grpcService))
 
 	conn := dialGrpc(ctx, t, cfg.PublicListener)
@@ -613,8 +751,13 @@ func TestNodeService(t *testing.T) {
 			layerCurrent := types.LayerID(layersPerEpoch) // end of first epoch
 			genTime.EXPECT().CurrentLayer().Return(layerCurrent)
 			req := &pb.StatusRequest{}
-			res, err := c.Status(context.Background(), req)
+			var header metadata.MD
+			res, err := c.Status(context.Background(), req, grpc.Header(&header))
 			require.NoError(t, err)
+			require.Equal(t, []string{"2"}, header.Get(hareEpochHeader))
+			require.Equal(t, []string{"20"}, header.Get(hareCommitteeSizeHeader))
+			require.Equal(t, []string{"11"}, header.Get(hareThresholdHeader))
+			require.Equal(t, []string{"5"}, header.Get(hareExpectedLeadersHeader))
 			require.Equal(t, uint64(0), res.Status.ConnectedPeers)
 			require.Equal(t, false, res.Status.IsSynced)
 			require.Equal(t, layerLatest.Uint32(), res.Status.SyncedLayer.Number)
@@ -679,6 +822,24 @@ func TestGlobalStateService(t *testing.T) {
 			require.Equal(t, uint64(accountBalance+1), res.AccountWrapper.StateProjected.Balance.Value)
 			require.Equal(t, uint64(accountCounter+1), res.AccountWrapper.StateProjected.Counter)
 		}},
+		{"SmesherDataQuery_MissingSmesher", func(t *testing.T) {
+			_, err := c.SmesherDataQuery(context.Background(), &pb.SmesherDataQueryRequest{})
+			require.Equal(t, codes.InvalidArgument, status.Code(err))
+		}},
+		{"SmesherDataQuery", func(t *testing.T) {
+			smesher := types.RandomNodeID()
+			res, err := c.SmesherDataQuery(context.Background(), &pb.SmesherDataQueryRequest{
+				SmesherId:  &pb.SmesherId{Id: smesher.Bytes()},
+				MaxResults: 10,
+			})
+			require.NoError(t, err)
+			require.Equal(t, uint32(1), res.TotalResults)
+			require.Len(t, res.Rewards, 1)
+			require.Equal(t, layerFirst.Uint32(), res.Rewards[0].Layer.Number)
+			require.Equal(t, uint64(rewardAmount), res.Rewards[0].Total.Value)
+			require.Equal(t, addr1.String(), res.Rewards[0].Coinbase.Address)
+			require.Equal(t, smesher.Bytes(), res.Rewards[0].Smesher.Id)
+		}},
 		{"AccountDataQuery_MissingFilter", func(t *testing.T) {
 			_, err := c.AccountDataQuery(context.Background(), &pb.AccountDataQueryRequest{})
 			require.Error(t, err)
@@ -1619,6 +1780,44 @@ func TestTransactionServiceSubmitInvalidTx(t *testing.T) {
 	req.Nil(res)
 }
 
+func TestTransactionServiceSubmitRejectedTx(t *testing.T) {
+	for _, tc := range []struct {
+		desc   string
+		reason txs.RejectReason
+		code   codes.Code
+	}{
+		{desc: "malformed", reason: txs.RejectMalformed, code: codes.InvalidArgument},
+		{desc: "duplicate", reason: txs.RejectDuplicate, code: codes.AlreadyExists},
+		{desc: "bad nonce", reason: txs.RejectBadNonce, code: codes.FailedPrecondition},
+		{desc: "insufficient funds", reason: txs.RejectInsufficientFunds, code: codes.FailedPrecondition},
+		{desc: "too many pending", reason: txs.RejectTooManyPending, code: codes.ResourceExhausted},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			syncer := NewMocksyncer(ctrl)
+			syncer.EXPECT().IsSynced(gomock.Any()).Return(true)
+			txHandler := NewMocktxValidator(ctrl)
+			metadata := map[string]string{"nonce": "7"}
+			txHandler.EXPECT().VerifyAndCacheTx(gomock.Any(), gomock.Any()).
+				Return(&txs.RejectError{Reason: tc.reason, Metadata: metadata})
+
+			svc := NewTransactionService(sql.InMemory(), nil, meshAPIMock, conStateAPI, syncer, txHandler, logtest.New(t))
+			_, err := svc.SubmitTransaction(context.Background(), &pb.SubmitTransactionRequest{Transaction: []byte{1}})
+			st, ok := status.FromError(err)
+			require.True(t, ok)
+			require.Equal(t, tc.code, st.Code())
+			require.Contains(t, st.Message(), "Failed to verify transaction")
+			require.Len(t, st.Details(), 1)
+			info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+			require.True(t, ok)
+			require.Equal(t, string(tc.reason), info.Reason)
+			require.Equal(t, ErrorDomain, info.Domain)
+			require.Equal(t, metadata, info.Metadata)
+		})
+	}
+}
+
 func TestTransactionService_SubmitNoConcurrency(t *testing.T) {
 	numTxs := 20
 
@@ -2311,7 +2510,7 @@ func TestMultiService(t *testing.T) {
 	genTime := NewMockgenesisTimeAPI(ctrl)
 	genesis := time.Unix(genTimeUnix, 0)
 	genTime.EXPECT().GenesisTime().Return(genesis)
-	svc1 := NewNodeService(peerCounter, meshAPIMock, genTime, syncer, "v0.0.0", "cafebabe", logtest.New(t).WithName("grpc.Node"))
+	svc1 := NewNodeService(peerCounter, meshAPIMock, genTime, syncer, nil, NodeInfo{Version: "v0.0.0", Commit: "cafebabe"}, logtest.New(t).WithName("grpc.Node"))
 	svc2 := NewMeshService(datastore.NewCachedDB(sql.InMemory(), logtest.New(t)), meshAPIMock, conStateAPI, genTime, layersPerEpoch, types.Hash20{}, layerDuration, layerAvgSize, txsPerProposal, logtest.New(t).WithName("grpc.Mesh"))
 	shutDown := launchServer(t, cfg, svc1, svc2)
 	t.Cleanup(shutDown)
@@ -2366,7 +2565,7 @@ func TestJsonApi(t *testing.T) {
 	genTime := NewMockgenesisTimeAPI(ctrl)
 	genesis := time.Unix(genTimeUnix, 0)
 	genTime.EXPECT().GenesisTime().Return(genesis)
-	svc1 := NewNodeService(peerCounter, meshAPIMock, genTime, syncer, "v0.0.0", "cafebabe", logtest.New(t).WithName("grpc.Node"))
+	svc1 := NewNodeService(peerCounter, meshAPIMock, genTime, syncer, nil, NodeInfo{Version: "v0.0.0", Commit: "cafebabe"}, logtest.New(t).WithName("grpc.Node"))
 	svc2 := NewMeshService(datastore.NewCachedDB(sql.InMemory(), logtest.New(t)), meshAPIMock, conStateAPI, genTime, layersPerEpoch, types.Hash20{}, layerDuration, layerAvgSize, txsPerProposal, logtest.New(t).WithName("grpc.Mesh"))
 	t.Cleanup(launchServer(t, cfg, svc1, svc2))
 	time.Sleep(time.Second)
@@ -0,0 +1,216 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/sql"
+)
+
+const (
+	// LivenessService is the name of the service in the grpc health protocol that reports
+	// whether the process is alive. the readiness is reported for the empty service name.
+	LivenessService = "liveness"
+
+	// healthCheckInterval is the interval between readiness checks reported over grpc.
+	healthCheckInterval = 5 * time.Second
+	// dbCheckTimeout is the max time to wait for a database connection.
+	dbCheckTimeout = 2 * time.Second
+	// dbCheckCacheTTL is for how long the result of the database check is reused,
+	// so that frequent probes don't compete with the node for database connections.
+	dbCheckCacheTTL = healthCheckInterval
+)
+
+// HealthCheck is the result of the single readiness check.
+type HealthCheck struct {
+	Name  string `json:"name"`
+	Ready bool   `json:"ready"`
+	Error string `json:"error,omitempty"`
+}
+
+// Readiness is the result of all readiness checks, the node is ready if all checks passed.
+type Readiness struct {
+	Ready  bool          `json:"ready"`
+	Checks []HealthCheck `json:"checks"`
+}
+
+// Health serves the liveness and readiness probes over grpc health protocol and plain http.
+// the node is ready if it processed layers within ReadyMaxLayersBehind from the current layer,
+// it is connected to at least ReadyMinPeers peers, and the database is responsive.
+type Health struct {
+	logger log.Logger
+	cfg    Config
+	db     *sql.Database
+	mesh   meshAPI
+	clock  genesisTimeAPI
+	peers  peerCounter
+	grpc   *health.Server
+
+	mu     sync.Mutex
+	server *http.Server
+
+	dbMu      sync.Mutex
+	dbChecked time.Time
+	dbErr     error
+}
+
+// NewHealth creates the health probes. the node isn't ready until the first readiness check.
+func NewHealth(cfg Config, db *sql.Database, msh meshAPI, clock genesisTimeAPI, peers peerCounter, lg log.Logger) *Health {
+	h := &Health{
+		logger: lg,
+		cfg:    cfg,
+		db:     db,
+		mesh:   msh,
+		clock:  clock,
+		peers:  peers,
+		grpc:   health.NewServer(),
+	}
+	h.grpc.SetServingStatus(LivenessService, healthpb.HealthCheckResponse_SERVING)
+	h.grpc.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	return h
+}
+
+// RegisterService registers the grpc health service with a grpc server instance.
+func (h *Health) RegisterService(server *Server) {
+	healthpb.RegisterHealthServer(server.GrpcServer, h.grpc)
+}
+
+// Readiness runs all readiness checks.
+func (h *Health) Readiness(ctx context.Context) Readiness {
+	rst := Readiness{Ready: true}
+	for _, check := range []struct {
+		name string
+		run  func(context.Context) error
+	}{
+		{"synced", h.checkSynced},
+		{"peers", h.checkPeers},
+		{"database", h.checkDB},
+	} {
+		result := HealthCheck{Name: check.name, Ready: true}
+		if err := check.run(ctx); err != nil {
+			result.Ready = false
+			result.Error = err.Error()
+			rst.Ready = false
+		}
+		rst.Checks = append(rst.Checks, result)
+	}
+	return rst
+}
+
+func (h *Health) checkSynced(context.Context) error {
+	current, processed := h.clock.CurrentLayer(), h.mesh.ProcessedLayer()
+	if current > processed && current.Difference(processed) > h.cfg.ReadyMaxLayersBehind {
+		return fmt.Errorf("processed layer %s is behind current layer %s by more than %d layers",
+			processed, current, h.cfg.ReadyMaxLayersBehind)
+	}
+	return nil
+}
+
+func (h *Health) checkPeers(context.Context) error {
+	if count := h.peers.PeerCount(); count < uint64(h.cfg.ReadyMinPeers) {
+		return fmt.Errorf("connected to %d peers, at least %d required", count, h.cfg.ReadyMinPeers)
+	}
+	return nil
+}
+
+// checkDB runs a read only query in a deferred transaction, which fails if no connection
+// is available within dbCheckTimeout. the result is cached for dbCheckCacheTTL.
+func (h *Health) checkDB(ctx context.Context) error {
+	h.dbMu.Lock()
+	defer h.dbMu.Unlock()
+	if !h.dbChecked.IsZero() && time.Since(h.dbChecked) < dbCheckCacheTTL {
+		return h.dbErr
+	}
+	ctx, cancel := context.WithTimeout(ctx, dbCheckTimeout)
+	defer cancel()
+	h.dbErr = h.queryDB(ctx)
+	h.dbChecked = time.Now()
+	return h.dbErr
+}
+
+func (h *Health) queryDB(ctx context.Context) error {
+	tx, err := h.db.Tx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Release()
+	if _, err := tx.Exec("select 1;", nil, nil); err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+	return nil
+}
+
+// Run periodically updates the readiness reported over grpc until the context is canceled.
+func (h *Health) Run(ctx context.Context) error {
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+	for {
+		status := healthpb.HealthCheckResponse_NOT_SERVING
+		if h.Readiness(ctx).Ready {
+			status = healthpb.HealthCheckResponse_SERVING
+		}
+		h.grpc.SetServingStatus("", status)
+		select {
+		case <-ctx.Done():
+			h.grpc.Shutdown()
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Live responds with ok while the process is running.
+func (h *Health) Live(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, "ok")
+}
+
+// Ready responds with the results of the readiness checks, the status is 503 if the node isn't ready.
+func (h *Health) Ready(w http.ResponseWriter, r *http.Request) {
+	rst := h.Readiness(r.Context())
+	if !rst.Ready {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	writeJSON(w, rst)
+}
+
+// Start serves the probes over plain http at /healthz and /readyz.
+func (h *Health) Start(listener string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", h.Live)
+	mux.HandleFunc("/readyz", h.Ready)
+	server := &http.Server{Addr: listener, Handler: mux}
+	h.mu.Lock()
+	h.server = server
+	h.mu.Unlock()
+
+	h.logger.With().Info("starting health http server", log.String("address", listener))
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			h.logger.With().Error("health http server failed", log.Err(err))
+		}
+	}()
+}
+
+// Shutdown stops the http server.
+func (h *Health) Shutdown(ctx context.Context) error {
+	h.mu.Lock()
+	server := h.server
+	h.mu.Unlock()
+	if server == nil {
+		return nil
+	}
+	if err := server.Shutdown(ctx); err != nil {
+		return fmt.Errorf("shutdown: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package grpcserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/sql"
+)
+
+type testHealth struct {
+	*Health
+	mesh  *MockmeshAPI
+	clock *MockgenesisTimeAPI
+	peers *MockpeerCounter
+}
+
+func newTestHealth(t *testing.T) *testHealth {
+	ctrl := gomock.NewController(t)
+	th := &testHealth{
+		mesh:  NewMockmeshAPI(ctrl),
+		clock: NewMockgenesisTimeAPI(ctrl),
+		peers: NewMockpeerCounter(ctrl),
+	}
+	cfg := DefaultTestConfig()
+	cfg.ReadyMaxLayersBehind = 2
+	cfg.ReadyMinPeers = 3
+	th.Health = NewHealth(cfg, sql.InMemory(), th.mesh, th.clock, th.peers, logtest.New(t))
+	return th
+}
+
+func (th *testHealth) expect(current, processed types.LayerID, peers uint64) {
+	th.clock.EXPECT().CurrentLayer().Return(current).AnyTimes()
+	th.mesh.EXPECT().ProcessedLayer().Return(processed).AnyTimes()
+	th.peers.EXPECT().PeerCount().Return(peers).AnyTimes()
+}
+
+func TestHealth_Readiness(t *testing.T) {
+	for _, tc := range []struct {
+		desc               string
+		current, processed types.LayerID
+		peers              uint64
+		failed             []string
+	}{
+		{desc: "ready", current: 12, processed: 10, peers: 3},
+		{desc: "processed ahead", current: 10, processed: 11, peers: 5},
+		{desc: "not synced", current: 13, processed: 10, peers: 3, failed: []string{"synced"}},
+		{desc: "no peers", current: 10, processed: 10, peers: 2, failed: []string{"peers"}},
+		{desc: "not synced no peers", current: 20, processed: 10, failed: []string{"synced", "peers"}},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			th := newTestHealth(t)
+			th.expect(tc.current, tc.processed, tc.peers)
+
+			rst := th.Readiness(context.Background())
+			require.Equal(t, len(tc.failed) == 0, rst.Ready)
+			require.Len(t, rst.Checks, 3)
+			var failed []string
+			for _, check := range rst.Checks {
+				if !check.Ready {
+					require.NotEmpty(t, check.Error)
+					failed = append(failed, check.Name)
+				}
+			}
+			require.Equal(t, tc.failed, failed)
+		})
+	}
+}
+
+func TestHealth_ReadinessDatabase(t *testing.T) {
+	th := newTestHealth(t)
+	th.expect(10, 10, 3)
+	db, err := sql.Open("file:" + filepath.Join(t.TempDir(), "state.sql"))
+	require.NoError(t, err)
+	th.db = db
+
+	// the check doesn't need the write lock
+	tx, err := th.db.TxImmediate(context.Background())
+	require.NoError(t, err)
+	rst := th.Readiness(context.Background())
+	require.True(t, rst.Ready)
+	require.NoError(t, tx.Release())
+
+	// the result is cached
+	require.NoError(t, th.db.Close())
+	rst = th.Readiness(context.Background())
+	require.True(t, rst.Ready)
+
+	th.dbChecked = time.Time{}
+	rst = th.Readiness(context.Background())
+	require.False(t, rst.Ready)
+	require.Equal(t, "database", rst.Checks[2].Name)
+	require.False(t, rst.Checks[2].Ready)
+}
+
+func TestHealth_HTTP(t *testing.T) {
+	th := newTestHealth(t)
+	peers := uint64(3)
+	th.clock.EXPECT().CurrentLayer().Return(types.LayerID(10)).AnyTimes()
+	th.mesh.EXPECT().ProcessedLayer().Return(types.LayerID(10)).AnyTimes()
+	th.peers.EXPECT().PeerCount().DoAndReturn(func() uint64 { return peers }).AnyTimes()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", th.Live)
+	mux.HandleFunc("/readyz", th.Ready)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	resp, err := http.Get(srv.URL + "/healthz")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var rst Readiness
+	require.Equal(t, http.StatusOK, getJSON(t, srv.URL+"/readyz", &rst))
+	require.True(t, rst.Ready)
+
+	peers = 0
+	resp, err = http.Get(srv.URL + "/readyz")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+}
+
+func TestHealth_Grpc(t *testing.T) {
+	th := newTestHealth(t)
+	th.expect(10, 10, 3)
+
+	check := func(service string) healthpb.HealthCheckResponse_ServingStatus {
+		resp, err := th.grpc.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		require.NoError(t, err)
+		return resp.Status
+	}
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, check(LivenessService))
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, check(""))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- th.Run(ctx) }()
+	require.Eventually(t, func() bool {
+		return check("") == healthpb.HealthCheckResponse_SERVING
+	}, time.Second, 10*time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+}
@@ -1,15 +1,21 @@
 package grpcserver
 
 import (
+	"compress/gzip"
 	"context"
+	"crypto/tls"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	pb "github.com/spacemeshos/api/release/go/spacemesh/v1"
 
+	"github.com/spacemeshos/go-spacemesh/events"
 	"github.com/spacemeshos/go-spacemesh/log"
 )
 
@@ -21,14 +27,85 @@ type JSONHTTPServer struct {
 	mu       sync.RWMutex
 	listener string
 	server   *http.Server
+	// origins are the patterns of the origins allowed to open websocket connections.
+	origins []string
+	// tls and token protect the gateway with the private services.
+	tls   *tls.Config
+	token string
+	// compression is the gzip level of the responses, maxRequest is the max size of the request body.
+	compression int
+	maxRequest  int64
+	// handlers are the admin endpoints served next to the gateway, keyed by the path pattern.
+	handlers map[string]http.HandlerFunc
+	// limiter limits the rate of requests of every client. may be nil
+	limiter *RateLimiter
+}
+
+// JSONOpt configures the json http server.
+type JSONOpt func(*JSONHTTPServer)
+
+// WithWebsocketOrigins allows the websocket connections from the browser pages with the origins
+// matching the patterns, in addition to the pages served from the same host.
+func WithWebsocketOrigins(patterns []string) JSONOpt {
+	return func(s *JSONHTTPServer) {
+		s.origins = patterns
+	}
+}
+
+// WithTLSConfig serves the gateway over tls.
+func WithTLSConfig(conf *tls.Config) JSONOpt {
+	return func(s *JSONHTTPServer) {
+		s.tls = conf
+	}
+}
+
+// WithAuthToken requires the bearer token in the authorization header of every request.
+func WithAuthToken(token string) JSONOpt {
+	return func(s *JSONHTTPServer) {
+		s.token = token
+	}
+}
+
+// WithCompressionLevel sets the gzip level of the responses for the clients that accept gzip encoding.
+func WithCompressionLevel(level int) JSONOpt {
+	return func(s *JSONHTTPServer) {
+		s.compression = level
+	}
+}
+
+// WithMaxRequestSize limits the size of the request body in bytes.
+func WithMaxRequestSize(size int) JSONOpt {
+	return func(s *JSONHTTPServer) {
+		s.maxRequest = int64(size)
+	}
+}
+
+// WithAdminHandlers serves the handlers next to the gateway. they control the node, so they are
+// served only if the gateway requires the bearer token.
+func WithAdminHandlers(handlers map[string]http.HandlerFunc) JSONOpt {
+	return func(s *JSONHTTPServer) {
+		s.handlers = handlers
+	}
+}
+
+// WithRateLimiter limits the rate of requests of every client.
+func WithRateLimiter(limiter *RateLimiter) JSONOpt {
+	return func(s *JSONHTTPServer) {
+		s.limiter = limiter
+	}
 }
 
 // NewJSONHTTPServer creates a new json http server.
-func NewJSONHTTPServer(listener string, lg log.Logger) *JSONHTTPServer {
-	return &JSONHTTPServer{
-		logger:   lg,
-		listener: listener,
+func NewJSONHTTPServer(listener string, lg log.Logger, opts ...JSONOpt) *JSONHTTPServer {
+	s := &JSONHTTPServer{
+		logger:      lg,
+		listener:    listener,
+		compression: gzip.DefaultCompression,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 // Shutdown stops the server.
@@ -77,20 +154,53 @@ func (s *JSONHTTPServer) startInternal(
 	mux := runtime.NewServeMux()
 
 	// register each individual, enabled service
-	serviceCount := 0
+	var (
+		serviceCount int
+		smesher      *SmesherService
+	)
 	for _, svc := range services {
 		var err error
 		switch typed := svc.(type) {
 		case *GlobalStateService:
 			err = pb.RegisterGlobalStateServiceHandlerServer(ctx, mux, typed)
+			if err == nil {
+				err = typed.registerAccounts(mux)
+			}
+			if err == nil {
+				err = typed.registerAccountProof(mux)
+			}
+			if err == nil {
+				err = typed.registerTemplates(mux)
+			}
 		case *MeshService:
 			err = pb.RegisterMeshServiceHandlerServer(ctx, mux, typed)
+			if err == nil {
+				err = typed.registerAccountData(mux)
+			}
+			if err == nil {
+				err = typed.registerSmesherActivity(mux)
+			}
 		case *NodeService:
 			err = pb.RegisterNodeServiceHandlerServer(ctx, mux, typed)
+			if err == nil {
+				err = typed.registerInfo(mux)
+			}
+			if err == nil {
+				err = typed.registerVerification(mux)
+			}
 		case *SmesherService:
 			err = pb.RegisterSmesherServiceHandlerServer(ctx, mux, typed)
+			if err == nil {
+				err = typed.registerPause(mux)
+			}
+			smesher = typed
 		case *TransactionService:
 			err = pb.RegisterTransactionServiceHandlerServer(ctx, mux, typed)
+			if err == nil {
+				err = typed.registerMempool(mux)
+			}
+		case *activationService:
+			err = typed.registerQueries(mux)
 		case *DebugService:
 			err = pb.RegisterDebugServiceHandlerServer(ctx, mux, typed)
 		}
@@ -100,6 +210,13 @@ func (s *JSONHTTPServer) startInternal(
 		serviceCount++
 	}
 
+	if err := mux.HandlePath(http.MethodGet, "/v1/events/stream", s.streamEvents); err != nil {
+		s.logger.Error("registering event stream with grpc gateway failed with %v", err)
+	}
+	if err := mux.HandlePath(http.MethodGet, "/v1/ws", s.websocketHandler(smesher)); err != nil {
+		s.logger.Error("registering websocket bridge with grpc gateway failed with %v", err)
+	}
+
 	close(started)
 
 	// At least one service must be enabled
@@ -108,14 +225,129 @@ func (s *JSONHTTPServer) startInternal(
 		return
 	}
 
+	var root http.Handler = mux
+	if len(s.handlers) > 0 {
+		if s.token == "" {
+			s.logger.Error("not serving admin endpoints; auth token is not set")
+		} else {
+			admin := http.NewServeMux()
+			admin.Handle("/", mux)
+			for pattern, handler := range s.handlers {
+				admin.HandleFunc(pattern, handler)
+			}
+			root = admin
+		}
+	}
+
 	s.logger.With().Info("starting grpc gateway server", log.String("address", s.listener))
-	s.setServer(&http.Server{
-		Addr:    s.listener,
-		Handler: mux,
-	})
+	handler := gzipHandler(s.compression, root)
+	if s.maxRequest > 0 {
+		handler = http.MaxBytesHandler(handler, s.maxRequest)
+	}
+	handler = RequireToken(s.token, handler.ServeHTTP)
+	if s.limiter != nil {
+		handler = s.limiter.Handler(handler)
+	}
+	server := &http.Server{
+		Addr:      s.listener,
+		Handler:   handler,
+		TLSConfig: s.tls,
+	}
+	s.setServer(server)
 
 	// This will block
-	s.logger.Error("error from grpc http listener: %v", s.getServer().ListenAndServe())
+	if s.tls != nil {
+		s.logger.Error("error from grpc https listener: %v", server.ListenAndServeTLS("", ""))
+	} else {
+		s.logger.Error("error from grpc http listener: %v", server.ListenAndServe())
+	}
+}
+
+// streamEvents writes the events of the unified event stream as newline delimited json objects.
+// the events after the cursor query parameter are replayed first, so that a client that reconnects with
+// the cursor of the last received event doesn't miss events. the kinds query parameter is a comma
+// separated list of event kinds, all kinds are streamed if it is not set.
+func (s *JSONHTTPServer) streamEvents(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	cursor, kinds, err := parseStreamQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	sub, replay, err := events.SubscribeStream(cursor, kinds)
+	switch {
+	case errors.Is(err, events.ErrCursorExpired):
+		http.Error(w, err.Error(), http.StatusGone)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	case sub == nil:
+		http.Error(w, "event reporter is not initialized", http.StatusServiceUnavailable)
+		return
+	}
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	flusher, _ := w.(http.Flusher)
+	enc := json.NewEncoder(w)
+	write := func(ev events.StreamEvent) bool {
+		if err := enc.Encode(ev); err != nil {
+			return false
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return true
+	}
+	for _, ev := range replay {
+		if !write(ev) {
+			return
+		}
+	}
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-sub.Full():
+			// the client is too slow, it should reconnect with the cursor of the last received event
+			return
+		case ev := <-sub.Out():
+			if !write(ev) {
+				return
+			}
+		}
+	}
+}
+
+// parseStreamQuery parses the cursor and the kinds of the events to stream. the extra kinds are accepted
+// in addition to the kinds of the unified event stream.
+func parseStreamQuery(r *http.Request, extra ...events.StreamKind) (uint64, []events.StreamKind, error) {
+	var cursor uint64
+	if value := r.URL.Query().Get("cursor"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return 0, nil, fmt.Errorf("invalid cursor: %w", err)
+		}
+		cursor = parsed
+	}
+	var kinds []events.StreamKind
+	if value := r.URL.Query().Get("kinds"); value != "" {
+	next:
+		for _, name := range strings.Split(value, ",") {
+			for _, kind := range extra {
+				if name == string(kind) {
+					kinds = append(kinds, kind)
+					continue next
+				}
+			}
+			kind, err := events.ParseStreamKind(name)
+			if err != nil {
+				return 0, nil, err
+			}
+			kinds = append(kinds, kind)
+		}
+	}
+	return cursor, kinds, nil
 }
 
 func (s *JSONHTTPServer) getServer() *http.Server {
@@ -6,8 +6,12 @@ import (
 
 	"github.com/spacemeshos/go-spacemesh/activation"
 	"github.com/spacemeshos/go-spacemesh/common/types"
+	hareConfig "github.com/spacemeshos/go-spacemesh/hare/config"
+	"github.com/spacemeshos/go-spacemesh/mesh"
 	"github.com/spacemeshos/go-spacemesh/p2p"
+	"github.com/spacemeshos/go-spacemesh/sql"
 	"github.com/spacemeshos/go-spacemesh/system"
+	"github.com/spacemeshos/go-spacemesh/txs"
 )
 
 //go:generate mockgen -package=grpcserver -destination=./mocks.go -source=./interface.go
@@ -17,18 +21,41 @@ type networkIdentity interface {
 	ID() p2p.Peer
 }
 
+// networkTopology is the view of the host on the p2p network.
+type networkTopology interface {
+	Connections() []p2p.Connection
+	RoutingTable() []p2p.Peer
+	Topics() []string
+	ProtocolPeers(string) []p2p.Peer
+	MeshPeers(string) []p2p.Peer
+}
+
+// networkHost is the identity and the topology of the p2p host.
+type networkHost interface {
+	networkIdentity
+	networkTopology
+}
+
 // conservativeState is an API for reading state and transaction/mempool data.
 type conservativeState interface {
 	GetStateRoot() (types.Hash32, error)
 	GetLayerStateRoot(types.LayerID) (types.Hash32, error)
+	AccountProof(types.Address, types.LayerID) (*types.AccountProof, types.Hash32, error)
 	GetAllAccounts() ([]*types.Account, error)
+	GetAccount(types.Address) (types.Account, error)
 	GetBalance(types.Address) (uint64, error)
 	GetNonce(types.Address) (types.Nonce, error)
 	GetProjection(types.Address) (uint64, uint64)
 	GetMeshTransaction(types.TransactionID) (*types.MeshTransaction, error)
 	GetMeshTransactions([]types.TransactionID) ([]*types.MeshTransaction, map[types.TransactionID]struct{})
 	GetTransactionsByAddress(types.LayerID, types.LayerID, types.Address) ([]*types.MeshTransaction, error)
+	ListTransactionsByAddress(types.LayerID, types.LayerID, types.Address, int, int) ([]*types.MeshTransaction, int, error)
 	Validation(raw types.RawTx) system.ValidationRequest
+	ListMempool(types.Address, int, int) ([]*txs.NanoTX, int)
+	MempoolStats() txs.MempoolStats
+	Projection(types.Address) txs.AccountProjection
+	MempoolStatus(types.TransactionID) (txs.MempoolStatus, string, error)
+	EstimateGasPrice(int) txs.FeeEstimate
 }
 
 // syncer is the API to get sync status.
@@ -43,6 +70,8 @@ type txValidator interface {
 
 // atxProvider is used by ActivationService to get ATXes.
 type atxProvider interface {
+	// Executor is used to list the atxs of the smesher and of the epoch.
+	sql.Executor
 	GetFullAtx(id types.ATXID) (*types.VerifiedActivationTx, error)
 	MaxHeightAtx() (types.ATXID, error)
 }
@@ -59,6 +88,11 @@ type peerCounter interface {
 	PeerCount() uint64
 }
 
+// hareParams is an API to get the hare committee parameters in effect in the current epoch.
+type hareParams interface {
+	ActiveParams() hareConfig.EpochParams
+}
+
 // genesisTimeAPI is an API to get genesis time and current layer of the system.
 type genesisTimeAPI interface {
 	GenesisTime() time.Time
@@ -69,11 +103,18 @@ type genesisTimeAPI interface {
 type meshAPI interface {
 	GetATXs(context.Context, []types.ATXID) (map[types.ATXID]*types.VerifiedActivationTx, []types.ATXID)
 	GetLayer(types.LayerID) (*types.Layer, error)
+	IterateLayerBlocks(types.LayerID, func(*types.Block) bool) error
+	IterateLayerBallots(types.LayerID, func(*types.Ballot) bool) error
 	GetRewards(types.Address) ([]*types.Reward, error)
+	GetSmesherRewards(types.NodeID, int, int) ([]*types.Reward, int, error)
+	BallotsBySmesher(types.NodeID, types.EpochID) ([]*types.Ballot, error)
+	BlocksBySmesher(types.NodeID, types.EpochID) ([]types.BlockID, error)
 	LatestLayer() types.LayerID
 	LatestLayerInState() types.LayerID
 	ProcessedLayer() types.LayerID
+	MeshStatus() mesh.Status
 	MeshHash(types.LayerID) (types.Hash32, error)
+	VerificationStatus() mesh.VerificationStatus
 }
 
 type oracle interface {
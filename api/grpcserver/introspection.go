@@ -0,0 +1,144 @@
+package grpcserver
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// QueueDepth returns the number of items waiting in the queue of the component.
+type QueueDepth func() int
+
+// Introspection serves goroutine dumps, heap profiles, gc statistics and queue depths
+// of the components of the running node. It exposes internals of the node, so its handlers
+// should be served only to the node operator.
+type Introspection struct {
+	mu     sync.Mutex
+	queues map[string]QueueDepth
+}
+
+// NewIntrospection creates introspection without registered queues.
+func NewIntrospection() *Introspection {
+	return &Introspection{queues: map[string]QueueDepth{}}
+}
+
+// RegisterQueue registers the queue of the component, its depth is reported under the name.
+func (i *Introspection) RegisterQueue(name string, depth QueueDepth) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.queues[name] = depth
+}
+
+// QueueDepths returns the current depths of the registered queues by name.
+func (i *Introspection) QueueDepths() map[string]int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	rst := make(map[string]int, len(i.queues))
+	for name, depth := range i.queues {
+		rst[name] = depth()
+	}
+	return rst
+}
+
+// GCStats is the summary of the garbage collector and the memory allocator statistics.
+type GCStats struct {
+	NumGC        int64           `json:"num_gc"`
+	LastGC       time.Time       `json:"last_gc"`
+	PauseTotal   time.Duration   `json:"pause_total"`
+	Pauses       []time.Duration `json:"pauses"`
+	HeapAlloc    uint64          `json:"heap_alloc"`
+	HeapInuse    uint64          `json:"heap_inuse"`
+	HeapObjects  uint64          `json:"heap_objects"`
+	Sys          uint64          `json:"sys"`
+	NextGC       uint64          `json:"next_gc"`
+	NumGoroutine int             `json:"num_goroutine"`
+}
+
+// recentPauses is the number of the most recent gc pauses reported in the gc stats.
+const recentPauses = 16
+
+// Goroutines writes stack traces of all goroutines. the debug query parameter selects the format
+// as in runtime/pprof, by default goroutines are written in the same format as an unrecovered panic.
+func (i *Introspection) Goroutines(w http.ResponseWriter, r *http.Request) {
+	level := 2
+	if raw := r.URL.Query().Get("debug"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 || parsed > 2 {
+			http.Error(w, fmt.Sprintf("invalid debug level: %q", raw), http.StatusBadRequest)
+			return
+		}
+		level = parsed
+	}
+	if level == 0 {
+		w.Header().Set("Content-Type", "application/octet-stream")
+	} else {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	}
+	if err := pprof.Lookup("goroutine").WriteTo(w, level); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Heap writes the heap profile in the pprof format. if the gc query parameter is set,
+// garbage collection runs before the profile is taken to report up to date statistics.
+func (i *Introspection) Heap(w http.ResponseWriter, r *http.Request) {
+	if gc, _ := strconv.ParseBool(r.URL.Query().Get("gc")); gc {
+		runtime.GC()
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", `attachment; filename="heap"`)
+	if err := pprof.Lookup("heap").WriteTo(w, 0); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// GC writes the garbage collector statistics.
+func (i *Introspection) GC(w http.ResponseWriter, _ *http.Request) {
+	var (
+		gc  debug.GCStats
+		mem runtime.MemStats
+	)
+	debug.ReadGCStats(&gc)
+	runtime.ReadMemStats(&mem)
+	// pauses are ordered from the most recent
+	if len(gc.Pause) > recentPauses {
+		gc.Pause = gc.Pause[:recentPauses]
+	}
+	writeJSON(w, GCStats{
+		NumGC:        gc.NumGC,
+		LastGC:       gc.LastGC,
+		PauseTotal:   gc.PauseTotal,
+		Pauses:       gc.Pause,
+		HeapAlloc:    mem.HeapAlloc,
+		HeapInuse:    mem.HeapInuse,
+		HeapObjects:  mem.HeapObjects,
+		Sys:          mem.Sys,
+		NextGC:       mem.NextGC,
+		NumGoroutine: runtime.NumGoroutine(),
+	})
+}
+
+// QueueDepthStat is the depth of the component queue.
+type QueueDepthStat struct {
+	Name  string `json:"name"`
+	Depth int    `json:"depth"`
+}
+
+// Queues writes the depths of the registered queues ordered by name.
+func (i *Introspection) Queues(w http.ResponseWriter, _ *http.Request) {
+	depths := i.QueueDepths()
+	rst := make([]QueueDepthStat, 0, len(depths))
+	for name, depth := range depths {
+		rst = append(rst, QueueDepthStat{Name: name, Depth: depth})
+	}
+	sort.Slice(rst, func(i, j int) bool {
+		return rst[i].Name < rst[j].Name
+	})
+	writeJSON(w, rst)
+}
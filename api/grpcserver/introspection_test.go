@@ -0,0 +1,69 @@
+package grpcserver
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newIntrospectionServer(t *testing.T) (*Introspection, *httptest.Server) {
+	introspection := NewIntrospection()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/goroutines", introspection.Goroutines)
+	mux.HandleFunc("/heap", introspection.Heap)
+	mux.HandleFunc("/gc", introspection.GC)
+	mux.HandleFunc("/queues", introspection.Queues)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return introspection, srv
+}
+
+func TestIntrospection_Goroutines(t *testing.T) {
+	_, srv := newIntrospectionServer(t)
+
+	resp, err := http.Get(srv.URL + "/goroutines")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.True(t, strings.Contains(string(body), "TestIntrospection_Goroutines"))
+
+	require.Equal(t, http.StatusBadRequest, getJSON(t, srv.URL+"/goroutines?debug=3", nil))
+}
+
+func TestIntrospection_Heap(t *testing.T) {
+	_, srv := newIntrospectionServer(t)
+
+	resp, err := http.Get(srv.URL + "/heap?gc=true")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NotEmpty(t, body)
+}
+
+func TestIntrospection_GC(t *testing.T) {
+	_, srv := newIntrospectionServer(t)
+
+	var rst GCStats
+	require.Equal(t, http.StatusOK, getJSON(t, srv.URL+"/gc", &rst))
+	require.NotZero(t, rst.HeapAlloc)
+	require.NotZero(t, rst.NumGoroutine)
+	require.LessOrEqual(t, len(rst.Pauses), recentPauses)
+}
+
+func TestIntrospection_Queues(t *testing.T) {
+	introspection, srv := newIntrospectionServer(t)
+	introspection.RegisterQueue("b", func() int { return 2 })
+	introspection.RegisterQueue("a", func() int { return 1 })
+
+	var rst []QueueDepthStat
+	require.Equal(t, http.StatusOK, getJSON(t, srv.URL+"/queues", &rst))
+	require.Equal(t, []QueueDepthStat{{Name: "a", Depth: 1}, {Name: "b", Depth: 2}}, rst)
+}
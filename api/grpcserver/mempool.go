@@ -0,0 +1,170 @@
+package grpcserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/common/util"
+	"github.com/spacemeshos/go-spacemesh/txs"
+)
+
+const (
+	// defaultMempoolPageSize is the number of transactions listed if the limit is not set.
+	defaultMempoolPageSize = 100
+	// maxFeeTargetLayers is the max number of layers accepted as the fee estimation target.
+	maxFeeTargetLayers = 100
+)
+
+// MempoolTx is the transaction in the mempool.
+type MempoolTx struct {
+	ID        string        `json:"id"`
+	Principal string        `json:"principal"`
+	Nonce     uint64        `json:"nonce"`
+	GasPrice  uint64        `json:"gas_price"`
+	MaxGas    uint64        `json:"max_gas"`
+	MaxSpend  uint64        `json:"max_spend"`
+	Layer     types.LayerID `json:"layer"`
+	Received  time.Time     `json:"received"`
+}
+
+// MempoolTxs is the page of the transactions in the mempool.
+type MempoolTxs struct {
+	Transactions []MempoolTx `json:"transactions"`
+	Total        int         `json:"total"`
+}
+
+// MempoolTxStatus is the status of the transaction in the mempool.
+type MempoolTxStatus struct {
+	ID     string            `json:"id"`
+	Status txs.MempoolStatus `json:"status"`
+	Reason string            `json:"reason,omitempty"`
+}
+
+// AccountProjectionJSON is the state of the account projected over its pending transactions.
+type AccountProjectionJSON struct {
+	Address string `json:"address"`
+	txs.AccountProjection
+}
+
+// registerMempool registers the mempool inspection endpoints with the grpc gateway.
+func (s TransactionService) registerMempool(mux *runtime.ServeMux) error {
+	for path, handler := range map[string]runtime.HandlerFunc{
+		"/v1/mempool/transactions":          s.listMempool,
+		"/v1/mempool/transactions/{id}":     s.mempoolStatus,
+		"/v1/mempool/stats":                 s.mempoolStats,
+		"/v1/transactions/fee":              s.estimateFee,
+		"/v1/accounts/{address}/projection": s.accountProjection,
+	} {
+		if err := mux.HandlePath(http.MethodGet, path, handler); err != nil {
+			return fmt.Errorf("register %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+func queryInt(r *http.Request, name string, value int) (int, error) {
+	if raw := r.URL.Query().Get(name); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 {
+			return 0, fmt.Errorf("invalid %s: %q", name, raw)
+		}
+		return parsed, nil
+	}
+	return value, nil
+}
+
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// listMempool lists the transactions in the mempool ordered by principal and nonce.
+// the principal query parameter filters the transactions by principal, offset and limit select the page.
+func (s TransactionService) listMempool(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var principal types.Address
+	if raw := r.URL.Query().Get("principal"); raw != "" {
+		addr, err := types.StringToAddress(raw)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid principal: %v", err), http.StatusBadRequest)
+			return
+		}
+		principal = addr
+	}
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	limit, err := queryInt(r, "limit", defaultMempoolPageSize)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ntxs, total := s.conState.ListMempool(principal, offset, limit)
+	rst := MempoolTxs{Transactions: make([]MempoolTx, 0, len(ntxs)), Total: total}
+	for _, ntx := range ntxs {
+		rst.Transactions = append(rst.Transactions, MempoolTx{
+			ID:        ntx.ID.String(),
+			Principal: ntx.Principal.String(),
+			Nonce:     ntx.Nonce,
+			GasPrice:  ntx.GasPrice,
+			MaxGas:    ntx.MaxGas,
+			MaxSpend:  ntx.MaxSpend,
+			Layer:     ntx.Layer,
+			Received:  ntx.Received,
+		})
+	}
+	writeJSON(w, rst)
+}
+
+// mempoolStatus returns whether the transaction is pending, queued or rejected, with the rejection reason.
+func (s TransactionService) mempoolStatus(w http.ResponseWriter, _ *http.Request, params map[string]string) {
+	raw := util.FromHex(params["id"])
+	if len(raw) != types.TransactionIDSize {
+		http.Error(w, fmt.Sprintf("invalid transaction id: %q", params["id"]), http.StatusBadRequest)
+		return
+	}
+	var tid types.TransactionID
+	copy(tid[:], raw)
+	status, reason, err := s.conState.MempoolStatus(tid)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, MempoolTxStatus{ID: tid.String(), Status: status, Reason: reason})
+}
+
+// mempoolStats returns the number of transactions and accounts in the mempool, and the gas price statistics.
+func (s TransactionService) mempoolStats(w http.ResponseWriter, _ *http.Request, _ map[string]string) {
+	writeJSON(w, s.conState.MempoolStats())
+}
+
+// accountProjection returns the next nonce and the spendable balance of the account, accounting for
+// its pending transactions, so that clients don't need to track the nonces of the submitted transactions.
+func (s TransactionService) accountProjection(w http.ResponseWriter, _ *http.Request, params map[string]string) {
+	addr, err := types.StringToAddress(params["address"])
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid address: %v", err), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, AccountProjectionJSON{Address: addr.String(), AccountProjection: s.conState.Projection(addr)})
+}
+
+// estimateFee returns the recommended gas price for a transaction to be included within
+// the number of layers in the layers query parameter, by default within the next layer.
+func (s TransactionService) estimateFee(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	target, err := queryInt(r, "layers", 1)
+	if err != nil || target < 1 || target > maxFeeTargetLayers {
+		http.Error(w, fmt.Sprintf("layers must be within [1, %d]", maxFeeTargetLayers), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, s.conState.EstimateGasPrice(target))
+}
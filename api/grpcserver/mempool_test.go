@@ -0,0 +1,123 @@
+package grpcserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/txs"
+)
+
+func newMempoolServer(t *testing.T) (*MockconservativeState, *httptest.Server) {
+	conState := NewMockconservativeState(gomock.NewController(t))
+	svc := NewTransactionService(nil, nil, nil, conState, nil, nil, logtest.New(t))
+	mux := runtime.NewServeMux()
+	require.NoError(t, svc.registerMempool(mux))
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return conState, srv
+}
+
+func getJSON(t *testing.T, url string, value any) int {
+	t.Helper()
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(value))
+	}
+	return resp.StatusCode
+}
+
+func TestMempool_List(t *testing.T) {
+	conState, srv := newMempoolServer(t)
+	principal := types.GenerateAddress(types.RandomBytes(32))
+	ntx := &txs.NanoTX{
+		ID:       types.RandomTransactionID(),
+		TxHeader: types.TxHeader{Principal: principal, Nonce: 7, GasPrice: 2, MaxGas: 10},
+	}
+	conState.EXPECT().ListMempool(principal, 5, 10).Return([]*txs.NanoTX{ntx}, 6)
+
+	var rst MempoolTxs
+	require.Equal(t, http.StatusOK,
+		getJSON(t, srv.URL+"/v1/mempool/transactions?principal="+principal.String()+"&offset=5&limit=10", &rst))
+	require.Equal(t, 6, rst.Total)
+	require.Len(t, rst.Transactions, 1)
+	require.Equal(t, ntx.ID.String(), rst.Transactions[0].ID)
+	require.Equal(t, principal.String(), rst.Transactions[0].Principal)
+	require.Equal(t, uint64(7), rst.Transactions[0].Nonce)
+
+	conState.EXPECT().ListMempool(types.Address{}, 0, defaultMempoolPageSize).Return(nil, 0)
+	require.Equal(t, http.StatusOK, getJSON(t, srv.URL+"/v1/mempool/transactions", &rst))
+	require.Empty(t, rst.Transactions)
+
+	require.Equal(t, http.StatusBadRequest, getJSON(t, srv.URL+"/v1/mempool/transactions?principal=invalid", &rst))
+	require.Equal(t, http.StatusBadRequest, getJSON(t, srv.URL+"/v1/mempool/transactions?limit=-1", &rst))
+}
+
+func TestMempool_Status(t *testing.T) {
+	conState, srv := newMempoolServer(t)
+	tid := types.RandomTransactionID()
+	conState.EXPECT().MempoolStatus(tid).Return(txs.MempoolRejected, "bad nonce", nil)
+
+	var rst MempoolTxStatus
+	require.Equal(t, http.StatusOK, getJSON(t, srv.URL+"/v1/mempool/transactions/"+tid.String(), &rst))
+	require.Equal(t, MempoolTxStatus{ID: tid.String(), Status: txs.MempoolRejected, Reason: "bad nonce"}, rst)
+
+	require.Equal(t, http.StatusBadRequest, getJSON(t, srv.URL+"/v1/mempool/transactions/0x01", &rst))
+}
+
+func TestMempool_Stats(t *testing.T) {
+	conState, srv := newMempoolServer(t)
+	stats := txs.MempoolStats{Accounts: 1, Transactions: 2, MinGasPrice: 1, MedianGasPrice: 2, MaxGasPrice: 2, TotalFee: 30}
+	conState.EXPECT().MempoolStats().Return(stats)
+
+	var rst txs.MempoolStats
+	require.Equal(t, http.StatusOK, getJSON(t, srv.URL+"/v1/mempool/stats", &rst))
+	require.Equal(t, stats, rst)
+}
+
+func TestMempool_EstimateFee(t *testing.T) {
+	conState, srv := newMempoolServer(t)
+	estimate := txs.FeeEstimate{GasPrice: 3, TargetLayers: 1, RecentGasPrice: 3, SampleLayers: 20}
+	conState.EXPECT().EstimateGasPrice(1).Return(estimate)
+
+	var rst txs.FeeEstimate
+	require.Equal(t, http.StatusOK, getJSON(t, srv.URL+"/v1/transactions/fee", &rst))
+	require.Equal(t, estimate, rst)
+
+	estimate.TargetLayers = 5
+	conState.EXPECT().EstimateGasPrice(5).Return(estimate)
+	require.Equal(t, http.StatusOK, getJSON(t, srv.URL+"/v1/transactions/fee?layers=5", &rst))
+	require.Equal(t, estimate, rst)
+
+	for _, layers := range []string{"0", "101", "x"} {
+		require.Equal(t, http.StatusBadRequest, getJSON(t, srv.URL+"/v1/transactions/fee?layers="+layers, &rst))
+	}
+}
+
+func TestMempool_AccountProjection(t *testing.T) {
+	conState, srv := newMempoolServer(t)
+	addr := types.GenerateAddress(types.RandomBytes(32))
+	projection := txs.AccountProjection{
+		Nonce:            3,
+		Balance:          1000,
+		NextNonce:        5,
+		SpendableBalance: 800,
+		Pending:          2,
+	}
+	conState.EXPECT().Projection(addr).Return(projection)
+
+	var rst AccountProjectionJSON
+	require.Equal(t, http.StatusOK, getJSON(t, srv.URL+"/v1/accounts/"+addr.String()+"/projection", &rst))
+	require.Equal(t, AccountProjectionJSON{Address: addr.String(), AccountProjection: projection}, rst)
+
+	require.Equal(t, http.StatusBadRequest, getJSON(t, srv.URL+"/v1/accounts/invalid/projection", &rst))
+}
@@ -133,14 +133,13 @@ func (s MeshService) getFilteredActivations(ctx context.Context, startLayer type
 	// See https://github.com/spacemeshos/go-spacemesh/issues/2064.
 	var atxids []types.ATXID
 	for l := startLayer; !l.After(s.mesh.LatestLayer()); l = l.Add(1) {
-		layer, err := s.mesh.GetLayer(l)
-		if layer == nil || err != nil {
-			return nil, status.Errorf(codes.Internal, "error retrieving layer data")
-		}
-		for _, b := range layer.Ballots() {
+		if err := s.mesh.IterateLayerBallots(l, func(b *types.Ballot) bool {
 			if b.EpochData != nil && b.ActiveSet != nil {
 				atxids = append(atxids, b.ActiveSet...)
 			}
+			return true
+		}); err != nil {
+			return nil, status.Errorf(codes.Internal, "error retrieving layer data")
 		}
 	}
 
@@ -190,6 +189,9 @@ func (s MeshService) AccountMeshDataQuery(ctx context.Context, in *pb.AccountMes
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse Filter.AccountId.Address `%s`: %w", in.Filter.AccountId.Address, err)
 	}
+	if filterTx && !filterActivations {
+		return s.accountTransactionsQuery(startLayer, addr, in.Offset, in.MaxResults)
+	}
 	res := &pb.AccountMeshDataQueryResponse{}
 	if filterTx {
 		txs, err := s.getFilteredTransactions(startLayer, addr)
@@ -250,6 +252,35 @@ func (s MeshService) AccountMeshDataQuery(ctx context.Context, in *pb.AccountMes
 	return res, nil
 }
 
+// accountTransactionsQuery pages through account transactions using the index by address,
+// so that only requested transactions are loaded.
+func (s MeshService) accountTransactionsQuery(from types.LayerID, addr types.Address, offset, maxResults uint32) (*pb.AccountMeshDataQueryResponse, error) {
+	// zero max results means unlimited, see AccountMeshDataQuery
+	limit := int(maxResults)
+	if limit == 0 {
+		limit = -1
+	}
+	txs, total, err := s.conState.ListTransactionsByAddress(from, s.mesh.LatestLayer(), addr, int(offset), limit)
+	if err != nil {
+		return nil, fmt.Errorf("reading txs for address %s: %w", addr, err)
+	}
+	if int(offset) > total {
+		return &pb.AccountMeshDataQueryResponse{}, nil
+	}
+	res := &pb.AccountMeshDataQueryResponse{TotalResults: uint32(total)}
+	for _, t := range txs {
+		res.Data = append(res.Data, &pb.AccountMeshData{
+			Datum: &pb.AccountMeshData_MeshTransaction{
+				MeshTransaction: &pb.MeshTransaction{
+					Transaction: castTransaction(&t.Transaction),
+					LayerId:     &pb.LayerNumber{Number: t.LayerID.Uint32()},
+				},
+			},
+		})
+	}
+	return res, nil
+}
+
 func convertLayerID(l types.LayerID) *pb.LayerNumber {
 	if layerID := l.Uint32(); layerID != 0 {
 		return &pb.LayerNumber{Number: layerID}
@@ -304,8 +335,13 @@ func (s MeshService) readLayer(ctx context.Context, layerID types.LayerID, layer
 	// Save activations too
 	var activations []types.ATXID
 
-	// read layer blocks
-	layer, err := s.mesh.GetLayer(layerID)
+	// the statement is released before the transactions are read, so that the callback
+	// doesn't run queries while the read connection is held.
+	var layerBlocks []*types.Block
+	err := s.mesh.IterateLayerBlocks(layerID, func(b *types.Block) bool {
+		layerBlocks = append(layerBlocks, b)
+		return true
+	})
 	// TODO: Be careful with how we handle missing layers here.
 	// A layer that's newer than the currentLayer (defined above)
 	// is clearly an input error. A missing layer that's older than
@@ -315,18 +351,15 @@ func (s MeshService) readLayer(ctx context.Context, layerID types.LayerID, layer
 	// internal errors.
 	if err != nil {
 		s.logger.With().Error("could not read layer from database", layerID, log.Err(err))
-		return nil, status.Errorf(codes.Internal, "error reading layer data")
+		return nil, status.Errorf(codes.Internal, "error retrieving layer data")
 	}
-
-	// TODO add proposal data as needed.
-
-	for _, b := range layer.Blocks() {
+	for _, b := range layerBlocks {
 		mtxs, missing := s.conState.GetMeshTransactions(b.TxIDs)
 		// TODO: Do we ever expect txs to be missing here?
 		// E.g., if this node has not synced/received them yet.
 		if len(missing) != 0 {
 			s.logger.With().Error("could not find transactions from layer",
-				log.String("missing", fmt.Sprint(missing)), layer.Index())
+				log.String("missing", fmt.Sprint(missing)), layerID)
 			return nil, status.Errorf(codes.Internal, "error retrieving tx data")
 		}
 
@@ -340,10 +373,16 @@ func (s MeshService) readLayer(ctx context.Context, layerID types.LayerID, layer
 		})
 	}
 
-	for _, b := range layer.Ballots() {
+	// TODO add proposal data as needed.
+
+	if err := s.mesh.IterateLayerBallots(layerID, func(b *types.Ballot) bool {
 		if b.EpochData != nil && b.ActiveSet != nil {
 			activations = append(activations, b.ActiveSet...)
 		}
+		return true
+	}); err != nil {
+		s.logger.With().Error("could not read layer ballots from database", layerID, log.Err(err))
+		return nil, status.Errorf(codes.Internal, "error retrieving layer data")
 	}
 
 	// Extract ATX data from block data
@@ -353,29 +392,29 @@ func (s MeshService) readLayer(ctx context.Context, layerID types.LayerID, layer
 	atxs, matxs := s.mesh.GetATXs(ctx, activations)
 	if len(matxs) != 0 {
 		s.logger.With().Error("could not find activations from layer",
-			log.String("missing", fmt.Sprint(matxs)), layer.Index())
+			log.String("missing", fmt.Sprint(matxs)), layerID)
 		return nil, status.Errorf(codes.Internal, "error retrieving activations data")
 	}
 	for _, atx := range atxs {
 		pbActivations = append(pbActivations, convertActivation(atx))
 	}
 
-	stateRoot, err := s.conState.GetLayerStateRoot(layer.Index())
+	stateRoot, err := s.conState.GetLayerStateRoot(layerID)
 	if err != nil {
 		// This is expected. We can only retrieve state root for a layer that was applied to state,
 		// which only happens after it's approved/confirmed.
 		s.logger.With().Debug("no state root for layer",
-			layer, log.String("status", layerStatus.String()), log.Err(err))
+			layerID, log.String("status", layerStatus.String()), log.Err(err))
 	}
 	hash, err := s.mesh.MeshHash(layerID)
 	if err != nil {
 		// This is expected. We can only retrieve state root for a layer that was applied to state,
 		// which only happens after it's approved/confirmed.
 		s.logger.With().Debug("no mesh hash at layer",
-			layer, log.String("status", layerStatus.String()), log.Err(err))
+			layerID, log.String("status", layerStatus.String()), log.Err(err))
 	}
 	return &pb.Layer{
-		Number:        &pb.LayerNumber{Number: layer.Index().Uint32()},
+		Number:        &pb.LayerNumber{Number: layerID.Uint32()},
 		Status:        layerStatus,
 		Blocks:        blocks,
 		Activations:   pbActivations,
@@ -397,8 +436,9 @@ func (s MeshService) LayersQuery(ctx context.Context, in *pb.LayersQueryRequest)
 	}
 
 	// Get the latest layers that passed both consensus engines.
-	lastLayerPassedHare := s.mesh.LatestLayerInState()
-	lastLayerPassedTortoise := s.mesh.ProcessedLayer()
+	meshStatus := s.mesh.MeshStatus()
+	lastLayerPassedHare := meshStatus.Applied
+	lastLayerPassedTortoise := meshStatus.Processed
 
 	var layers []*pb.Layer
 	for l := startLayer; !l.After(endLayer); l = l.Add(1) {
@@ -413,19 +453,6 @@ func (s MeshService) LayersQuery(ctx context.Context, in *pb.LayersQueryRequest)
 			layerStatus = pb.Layer_LAYER_STATUS_CONFIRMED
 		}
 
-		layer, err := s.mesh.GetLayer(l)
-		// TODO: Be careful with how we handle missing layers here.
-		// A layer that's newer than the currentLayer (defined above)
-		// is clearly an input error. A missing layer that's older than
-		// lastValidLayer is clearly an internal error. A missing layer
-		// between these two is a gray area: do we define this as an
-		// internal or an input error? For now, all missing layers produce
-		// internal errors.
-		if layer == nil || err != nil {
-			s.logger.With().Error("error retrieving layer data", log.Err(err))
-			return nil, status.Errorf(codes.Internal, "error retrieving layer data")
-		}
-
 		pbLayer, err := s.readLayer(ctx, l, layerStatus)
 		if err != nil {
 			return nil, err
@@ -527,16 +554,31 @@ func (s MeshService) AccountMeshDataStream(in *pb.AccountMeshDataStreamRequest,
 }
 
 // LayerStream exposes a stream of all mesh data per layer.
+// the subscriber can configure its buffer and overflow policy, see parseBackpressure.
 func (s MeshService) LayerStream(_ *pb.LayerStreamRequest, stream pb.MeshService_LayerStreamServer) error {
 	s.logger.Info("GRPC MeshService.LayerStream")
 
+	bp, err := parseBackpressure(stream.Context())
+	if err != nil {
+		return err
+	}
+	defer bp.setTrailer(stream)
+
 	var (
 		layerCh       <-chan events.LayerUpdate
 		layersBufFull <-chan struct{}
+		reorgCh       <-chan events.EventReorg
+		reorgBufFull  <-chan struct{}
 	)
 
 	if layersSubscription := events.SubscribeLayers(); layersSubscription != nil {
-		layerCh, layersBufFull = consumeEvents[events.LayerUpdate](stream.Context(), layersSubscription)
+		layerCh, layersBufFull = consumeEventsWith[events.LayerUpdate](stream.Context(), layersSubscription, bp)
+	}
+	if reorgSubscription := events.SubscribeReorgs(); reorgSubscription != nil {
+		reorgCh, reorgBufFull = consumeEventsWith[events.EventReorg](stream.Context(), reorgSubscription, bp)
+	}
+	if err := stream.SendHeader(bp.header()); err != nil {
+		return err
 	}
 
 	for {
@@ -544,6 +586,25 @@ func (s MeshService) LayerStream(_ *pb.LayerStreamRequest, stream pb.MeshService
 		case <-layersBufFull:
 			s.logger.Info("layer buffer is full, shutting down")
 			return status.Error(codes.Canceled, errAccountBufferFull)
+		case <-reorgBufFull:
+			s.logger.Info("reorg buffer is full, shutting down")
+			return status.Error(codes.Canceled, errAccountBufferFull)
+		case reorg, ok := <-reorgCh:
+			if !ok {
+				s.logger.Info("LayerStream closed, shutting down")
+				return nil
+			}
+			// reverted layers are resent without status, so that clients can invalidate
+			// cached data. they will be sent again once consensus is applied.
+			for lid := reorg.Reverted; !lid.After(reorg.Applied); lid = lid.Add(1) {
+				pbLayer, err := s.readLayer(stream.Context(), lid, pb.Layer_LAYER_STATUS_UNSPECIFIED)
+				if err != nil {
+					return fmt.Errorf("read layer: %w", err)
+				}
+				if err := stream.Send(&pb.LayerStreamResponse{Layer: pbLayer}); err != nil {
+					return fmt.Errorf("send to stream: %w", err)
+				}
+			}
 		case layer, ok := <-layerCh:
 			if !ok {
 				s.logger.Info("LayerStream closed, shutting down")
@@ -0,0 +1,148 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/metrics"
+)
+
+const (
+	metricsSubsystem = "grpc"
+
+	directionReceived = "received"
+	directionSent     = "sent"
+)
+
+var (
+	requestsCount = metrics.NewCounter(
+		"requests",
+		metricsSubsystem,
+		"number of handled requests and opened streams",
+		[]string{"method", "code"},
+	)
+	requestDuration = metrics.NewHistogramWithBuckets(
+		"request_duration_seconds",
+		metricsSubsystem,
+		"duration of handled requests and streams",
+		[]string{"method"},
+		prometheus.ExponentialBuckets(0.001, 2, 16),
+	)
+	messageSize = metrics.NewHistogramWithBuckets(
+		"message_size_bytes",
+		metricsSubsystem,
+		"size of received and sent messages",
+		[]string{"method", "direction"},
+		prometheus.ExponentialBuckets(64, 4, 10),
+	)
+)
+
+// Metrics records the number of requests with their codes, latencies and the sizes of the messages
+// for every method. unary requests that take longer than the configured threshold are logged together
+// with the address and the user agent of the client.
+type Metrics struct {
+	logger log.Logger
+	slow   time.Duration
+}
+
+// NewMetrics creates the Metrics from the config.
+func NewMetrics(c Config, logger log.Logger) *Metrics {
+	return &Metrics{logger: logger, slow: c.SlowRequestThreshold}
+}
+
+// clientAddress returns the ip address of the client without the port.
+func clientAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	addr := p.Addr.String()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+func userAgent(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get("user-agent"); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func observeSize(method, direction string, msg any) {
+	if m, ok := msg.(proto.Message); ok {
+		messageSize.WithLabelValues(method, direction).Observe(float64(proto.Size(m)))
+	}
+}
+
+func (m *Metrics) observe(ctx context.Context, method string, start time.Time, err error, unary bool) {
+	elapsed := time.Since(start)
+	requestsCount.WithLabelValues(method, status.Code(err).String()).Inc()
+	requestDuration.WithLabelValues(method).Observe(elapsed.Seconds())
+	if unary && m.slow > 0 && elapsed >= m.slow {
+		m.logger.With().Warning("slow grpc request",
+			log.String("method", method),
+			log.Duration("duration", elapsed),
+			log.String("client", clientAddress(ctx)),
+			log.String("user_agent", userAgent(ctx)),
+			log.String("code", status.Code(err).String()),
+		)
+	}
+}
+
+// UnaryInterceptor records the metrics of the unary requests.
+func (m *Metrics) UnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	observeSize(info.FullMethod, directionReceived, req)
+	resp, err := handler(ctx, req)
+	if err == nil {
+		observeSize(info.FullMethod, directionSent, resp)
+	}
+	m.observe(ctx, info.FullMethod, start, err, true)
+	return resp, err
+}
+
+// StreamInterceptor records the metrics of the streams. the duration of the stream is observed
+// when it is closed, slow streams are not logged as most of them are long-lived.
+func (m *Metrics) StreamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, &measuredStream{ServerStream: ss, method: info.FullMethod})
+	m.observe(ss.Context(), info.FullMethod, start, err, false)
+	return err
+}
+
+// measuredStream observes the size of every message received and sent on the stream.
+type measuredStream struct {
+	grpc.ServerStream
+	method string
+}
+
+func (s *measuredStream) RecvMsg(msg any) error {
+	if err := s.ServerStream.RecvMsg(msg); err != nil {
+		return err
+	}
+	observeSize(s.method, directionReceived, msg)
+	return nil
+}
+
+func (s *measuredStream) SendMsg(msg any) error {
+	if err := s.ServerStream.SendMsg(msg); err != nil {
+		return err
+	}
+	observeSize(s.method, directionSent, msg)
+	return nil
+}
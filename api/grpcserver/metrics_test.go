@@ -0,0 +1,112 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+)
+
+type testStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testStream) Context() context.Context { return s.ctx }
+
+func (s *testStream) SendMsg(any) error { return nil }
+
+func sampleCount(t *testing.T, obs prometheus.Observer) uint64 {
+	t.Helper()
+	var metric dto.Metric
+	require.NoError(t, obs.(prometheus.Histogram).Write(&metric))
+	return metric.GetHistogram().GetSampleCount()
+}
+
+func TestMetrics_Unary(t *testing.T) {
+	m := NewMetrics(DefaultTestConfig(), logtest.New(t))
+	method := "/test.Metrics/Unary"
+	info := &grpc.UnaryServerInfo{FullMethod: method}
+
+	_, err := m.UnaryInterceptor(context.Background(), wrapperspb.String("request"), info,
+		func(context.Context, any) (any, error) {
+			return wrapperspb.String("response"), nil
+		})
+	require.NoError(t, err)
+	_, err = m.UnaryInterceptor(context.Background(), wrapperspb.String("request"), info,
+		func(context.Context, any) (any, error) {
+			return nil, status.Error(codes.NotFound, "not found")
+		})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	require.Equal(t, 1.0, testutil.ToFloat64(requestsCount.WithLabelValues(method, codes.OK.String())))
+	require.Equal(t, 1.0, testutil.ToFloat64(requestsCount.WithLabelValues(method, codes.NotFound.String())))
+	require.Equal(t, uint64(2), sampleCount(t, requestDuration.WithLabelValues(method)))
+	require.Equal(t, uint64(2), sampleCount(t, messageSize.WithLabelValues(method, directionReceived)))
+	require.Equal(t, uint64(1), sampleCount(t, messageSize.WithLabelValues(method, directionSent)))
+}
+
+func TestMetrics_Stream(t *testing.T) {
+	m := NewMetrics(DefaultTestConfig(), logtest.New(t))
+	method := "/test.Metrics/Stream"
+	info := &grpc.StreamServerInfo{FullMethod: method, IsServerStream: true}
+
+	err := m.StreamInterceptor(nil, &testStream{ctx: context.Background()}, info, func(_ any, ss grpc.ServerStream) error {
+		for i := 0; i < 3; i++ {
+			if err := ss.SendMsg(wrapperspb.String("event")); err != nil {
+				return err
+			}
+		}
+		return status.Error(codes.Canceled, "canceled")
+	})
+	require.Equal(t, codes.Canceled, status.Code(err))
+	require.Equal(t, 1.0, testutil.ToFloat64(requestsCount.WithLabelValues(method, codes.Canceled.String())))
+	require.Equal(t, 0.0, testutil.ToFloat64(requestsCount.WithLabelValues(method, codes.OK.String())))
+}
+
+func TestMetrics_SlowRequest(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	conf := DefaultTestConfig()
+	conf.SlowRequestThreshold = 10 * time.Millisecond
+	m := NewMetrics(conf, log.NewFromLog(zap.New(core)))
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Metrics/Slow"}
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 1234},
+	})
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("user-agent", "wallet/1.0"))
+	fast := func(context.Context, any) (any, error) { return nil, nil }
+	slow := func(context.Context, any) (any, error) {
+		time.Sleep(conf.SlowRequestThreshold)
+		return nil, nil
+	}
+
+	_, err := m.UnaryInterceptor(ctx, nil, info, fast)
+	require.NoError(t, err)
+	require.Zero(t, logs.Len())
+
+	_, err = m.UnaryInterceptor(ctx, nil, info, slow)
+	require.NoError(t, err)
+	require.Equal(t, 1, logs.Len())
+	fields := logs.All()[0].ContextMap()
+	require.Equal(t, "/test.Metrics/Slow", fields["method"])
+	require.Equal(t, "10.0.0.1", fields["client"])
+	require.Equal(t, "wallet/1.0", fields["user_agent"])
+}
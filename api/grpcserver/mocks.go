@@ -12,8 +12,12 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	activation "github.com/spacemeshos/go-spacemesh/activation"
 	types "github.com/spacemeshos/go-spacemesh/common/types"
+	config "github.com/spacemeshos/go-spacemesh/hare/config"
+	mesh "github.com/spacemeshos/go-spacemesh/mesh"
 	p2p "github.com/spacemeshos/go-spacemesh/p2p"
+	sql "github.com/spacemeshos/go-spacemesh/sql"
 	system "github.com/spacemeshos/go-spacemesh/system"
+	txs "github.com/spacemeshos/go-spacemesh/txs"
 )
 
 // MocknetworkIdentity is a mock of networkIdentity interface.
@@ -53,6 +57,206 @@ func (mr *MocknetworkIdentityMockRecorder) ID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ID", reflect.TypeOf((*MocknetworkIdentity)(nil).ID))
 }
 
+// MocknetworkTopology is a mock of networkTopology interface.
+type MocknetworkTopology struct {
+	ctrl     *gomock.Controller
+	recorder *MocknetworkTopologyMockRecorder
+}
+
+// MocknetworkTopologyMockRecorder is the mock recorder for MocknetworkTopology.
+type MocknetworkTopologyMockRecorder struct {
+	mock *MocknetworkTopology
+}
+
+// NewMocknetworkTopology creates a new mock instance.
+func NewMocknetworkTopology(ctrl *gomock.Controller) *MocknetworkTopology {
+	mock := &MocknetworkTopology{ctrl: ctrl}
+	mock.recorder = &MocknetworkTopologyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocknetworkTopology) EXPECT() *MocknetworkTopologyMockRecorder {
+	return m.recorder
+}
+
+// Connections mocks base method.
+func (m *MocknetworkTopology) Connections() []p2p.Connection {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Connections")
+	ret0, _ := ret[0].([]p2p.Connection)
+	return ret0
+}
+
+// Connections indicates an expected call of Connections.
+func (mr *MocknetworkTopologyMockRecorder) Connections() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Connections", reflect.TypeOf((*MocknetworkTopology)(nil).Connections))
+}
+
+// MeshPeers mocks base method.
+func (m *MocknetworkTopology) MeshPeers(arg0 string) []p2p.Peer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MeshPeers", arg0)
+	ret0, _ := ret[0].([]p2p.Peer)
+	return ret0
+}
+
+// MeshPeers indicates an expected call of MeshPeers.
+func (mr *MocknetworkTopologyMockRecorder) MeshPeers(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MeshPeers", reflect.TypeOf((*MocknetworkTopology)(nil).MeshPeers), arg0)
+}
+
+// ProtocolPeers mocks base method.
+func (m *MocknetworkTopology) ProtocolPeers(arg0 string) []p2p.Peer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProtocolPeers", arg0)
+	ret0, _ := ret[0].([]p2p.Peer)
+	return ret0
+}
+
+// ProtocolPeers indicates an expected call of ProtocolPeers.
+func (mr *MocknetworkTopologyMockRecorder) ProtocolPeers(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProtocolPeers", reflect.TypeOf((*MocknetworkTopology)(nil).ProtocolPeers), arg0)
+}
+
+// RoutingTable mocks base method.
+func (m *MocknetworkTopology) RoutingTable() []p2p.Peer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RoutingTable")
+	ret0, _ := ret[0].([]p2p.Peer)
+	return ret0
+}
+
+// RoutingTable indicates an expected call of RoutingTable.
+func (mr *MocknetworkTopologyMockRecorder) RoutingTable() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RoutingTable", reflect.TypeOf((*MocknetworkTopology)(nil).RoutingTable))
+}
+
+// Topics mocks base method.
+func (m *MocknetworkTopology) Topics() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Topics")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Topics indicates an expected call of Topics.
+func (mr *MocknetworkTopologyMockRecorder) Topics() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Topics", reflect.TypeOf((*MocknetworkTopology)(nil).Topics))
+}
+
+// MocknetworkHost is a mock of networkHost interface.
+type MocknetworkHost struct {
+	ctrl     *gomock.Controller
+	recorder *MocknetworkHostMockRecorder
+}
+
+// MocknetworkHostMockRecorder is the mock recorder for MocknetworkHost.
+type MocknetworkHostMockRecorder struct {
+	mock *MocknetworkHost
+}
+
+// NewMocknetworkHost creates a new mock instance.
+func NewMocknetworkHost(ctrl *gomock.Controller) *MocknetworkHost {
+	mock := &MocknetworkHost{ctrl: ctrl}
+	mock.recorder = &MocknetworkHostMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocknetworkHost) EXPECT() *MocknetworkHostMockRecorder {
+	return m.recorder
+}
+
+// Connections mocks base method.
+func (m *MocknetworkHost) Connections() []p2p.Connection {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Connections")
+	ret0, _ := ret[0].([]p2p.Connection)
+	return ret0
+}
+
+// Connections indicates an expected call of Connections.
+func (mr *MocknetworkHostMockRecorder) Connections() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Connections", reflect.TypeOf((*MocknetworkHost)(nil).Connections))
+}
+
+// ID mocks base method.
+func (m *MocknetworkHost) ID() p2p.Peer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ID")
+	ret0, _ := ret[0].(p2p.Peer)
+	return ret0
+}
+
+// ID indicates an expected call of ID.
+func (mr *MocknetworkHostMockRecorder) ID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ID", reflect.TypeOf((*MocknetworkHost)(nil).ID))
+}
+
+// MeshPeers mocks base method.
+func (m *MocknetworkHost) MeshPeers(arg0 string) []p2p.Peer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MeshPeers", arg0)
+	ret0, _ := ret[0].([]p2p.Peer)
+	return ret0
+}
+
+// MeshPeers indicates an expected call of MeshPeers.
+func (mr *MocknetworkHostMockRecorder) MeshPeers(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MeshPeers", reflect.TypeOf((*MocknetworkHost)(nil).MeshPeers), arg0)
+}
+
+// ProtocolPeers mocks base method.
+func (m *MocknetworkHost) ProtocolPeers(arg0 string) []p2p.Peer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProtocolPeers", arg0)
+	ret0, _ := ret[0].([]p2p.Peer)
+	return ret0
+}
+
+// ProtocolPeers indicates an expected call of ProtocolPeers.
+func (mr *MocknetworkHostMockRecorder) ProtocolPeers(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProtocolPeers", reflect.TypeOf((*MocknetworkHost)(nil).ProtocolPeers), arg0)
+}
+
+// RoutingTable mocks base method.
+func (m *MocknetworkHost) RoutingTable() []p2p.Peer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RoutingTable")
+	ret0, _ := ret[0].([]p2p.Peer)
+	return ret0
+}
+
+// RoutingTable indicates an expected call of RoutingTable.
+func (mr *MocknetworkHostMockRecorder) RoutingTable() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RoutingTable", reflect.TypeOf((*MocknetworkHost)(nil).RoutingTable))
+}
+
+// Topics mocks base method.
+func (m *MocknetworkHost) Topics() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Topics")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Topics indicates an expected call of Topics.
+func (mr *MocknetworkHostMockRecorder) Topics() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Topics", reflect.TypeOf((*MocknetworkHost)(nil).Topics))
+}
+
 // MockconservativeState is a mock of conservativeState interface.
 type MockconservativeState struct {
 	ctrl     *gomock.Controller
@@ -76,6 +280,51 @@ func (m *MockconservativeState) EXPECT() *MockconservativeStateMockRecorder {
 	return m.recorder
 }
 
+// AccountProof mocks base method.
+func (m *MockconservativeState) AccountProof(arg0 types.Address, arg1 types.LayerID) (*types.AccountProof, types.Hash32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccountProof", arg0, arg1)
+	ret0, _ := ret[0].(*types.AccountProof)
+	ret1, _ := ret[1].(types.Hash32)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// AccountProof indicates an expected call of AccountProof.
+func (mr *MockconservativeStateMockRecorder) AccountProof(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountProof", reflect.TypeOf((*MockconservativeState)(nil).AccountProof), arg0, arg1)
+}
+
+// EstimateGasPrice mocks base method.
+func (m *MockconservativeState) EstimateGasPrice(arg0 int) txs.FeeEstimate {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EstimateGasPrice", arg0)
+	ret0, _ := ret[0].(txs.FeeEstimate)
+	return ret0
+}
+
+// EstimateGasPrice indicates an expected call of EstimateGasPrice.
+func (mr *MockconservativeStateMockRecorder) EstimateGasPrice(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EstimateGasPrice", reflect.TypeOf((*MockconservativeState)(nil).EstimateGasPrice), arg0)
+}
+
+// GetAccount mocks base method.
+func (m *MockconservativeState) GetAccount(arg0 types.Address) (types.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccount", arg0)
+	ret0, _ := ret[0].(types.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccount indicates an expected call of GetAccount.
+func (mr *MockconservativeStateMockRecorder) GetAccount(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockconservativeState)(nil).GetAccount), arg0)
+}
+
 // GetAllAccounts mocks base method.
 func (m *MockconservativeState) GetAllAccounts() ([]*types.Account, error) {
 	m.ctrl.T.Helper()
@@ -211,6 +460,81 @@ func (mr *MockconservativeStateMockRecorder) GetTransactionsByAddress(arg0, arg1
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsByAddress", reflect.TypeOf((*MockconservativeState)(nil).GetTransactionsByAddress), arg0, arg1, arg2)
 }
 
+// ListMempool mocks base method.
+func (m *MockconservativeState) ListMempool(arg0 types.Address, arg1, arg2 int) ([]*txs.NanoTX, int) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMempool", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*txs.NanoTX)
+	ret1, _ := ret[1].(int)
+	return ret0, ret1
+}
+
+// ListMempool indicates an expected call of ListMempool.
+func (mr *MockconservativeStateMockRecorder) ListMempool(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMempool", reflect.TypeOf((*MockconservativeState)(nil).ListMempool), arg0, arg1, arg2)
+}
+
+// ListTransactionsByAddress mocks base method.
+func (m *MockconservativeState) ListTransactionsByAddress(arg0, arg1 types.LayerID, arg2 types.Address, arg3, arg4 int) ([]*types.MeshTransaction, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransactionsByAddress", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]*types.MeshTransaction)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListTransactionsByAddress indicates an expected call of ListTransactionsByAddress.
+func (mr *MockconservativeStateMockRecorder) ListTransactionsByAddress(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransactionsByAddress", reflect.TypeOf((*MockconservativeState)(nil).ListTransactionsByAddress), arg0, arg1, arg2, arg3, arg4)
+}
+
+// MempoolStats mocks base method.
+func (m *MockconservativeState) MempoolStats() txs.MempoolStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MempoolStats")
+	ret0, _ := ret[0].(txs.MempoolStats)
+	return ret0
+}
+
+// MempoolStats indicates an expected call of MempoolStats.
+func (mr *MockconservativeStateMockRecorder) MempoolStats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MempoolStats", reflect.TypeOf((*MockconservativeState)(nil).MempoolStats))
+}
+
+// MempoolStatus mocks base method.
+func (m *MockconservativeState) MempoolStatus(arg0 types.TransactionID) (txs.MempoolStatus, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MempoolStatus", arg0)
+	ret0, _ := ret[0].(txs.MempoolStatus)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// MempoolStatus indicates an expected call of MempoolStatus.
+func (mr *MockconservativeStateMockRecorder) MempoolStatus(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MempoolStatus", reflect.TypeOf((*MockconservativeState)(nil).MempoolStatus), arg0)
+}
+
+// Projection mocks base method.
+func (m *MockconservativeState) Projection(arg0 types.Address) txs.AccountProjection {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Projection", arg0)
+	ret0, _ := ret[0].(txs.AccountProjection)
+	return ret0
+}
+
+// Projection indicates an expected call of Projection.
+func (mr *MockconservativeStateMockRecorder) Projection(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Projection", reflect.TypeOf((*MockconservativeState)(nil).Projection), arg0)
+}
+
 // Validation mocks base method.
 func (m *MockconservativeState) Validation(raw types.RawTx) system.ValidationRequest {
 	m.ctrl.T.Helper()
@@ -322,6 +646,21 @@ func (m *MockatxProvider) EXPECT() *MockatxProviderMockRecorder {
 	return m.recorder
 }
 
+// Exec mocks base method.
+func (m *MockatxProvider) Exec(arg0 string, arg1 sql.Encoder, arg2 sql.Decoder) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exec", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exec indicates an expected call of Exec.
+func (mr *MockatxProviderMockRecorder) Exec(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exec", reflect.TypeOf((*MockatxProvider)(nil).Exec), arg0, arg1, arg2)
+}
+
 // GetFullAtx mocks base method.
 func (m *MockatxProvider) GetFullAtx(id types.ATXID) (*types.VerifiedActivationTx, error) {
 	m.ctrl.T.Helper()
@@ -470,6 +809,43 @@ func (mr *MockpeerCounterMockRecorder) PeerCount() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PeerCount", reflect.TypeOf((*MockpeerCounter)(nil).PeerCount))
 }
 
+// MockhareParams is a mock of hareParams interface.
+type MockhareParams struct {
+	ctrl     *gomock.Controller
+	recorder *MockhareParamsMockRecorder
+}
+
+// MockhareParamsMockRecorder is the mock recorder for MockhareParams.
+type MockhareParamsMockRecorder struct {
+	mock *MockhareParams
+}
+
+// NewMockhareParams creates a new mock instance.
+func NewMockhareParams(ctrl *gomock.Controller) *MockhareParams {
+	mock := &MockhareParams{ctrl: ctrl}
+	mock.recorder = &MockhareParamsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockhareParams) EXPECT() *MockhareParamsMockRecorder {
+	return m.recorder
+}
+
+// ActiveParams mocks base method.
+func (m *MockhareParams) ActiveParams() config.EpochParams {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActiveParams")
+	ret0, _ := ret[0].(config.EpochParams)
+	return ret0
+}
+
+// ActiveParams indicates an expected call of ActiveParams.
+func (mr *MockhareParamsMockRecorder) ActiveParams() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActiveParams", reflect.TypeOf((*MockhareParams)(nil).ActiveParams))
+}
+
 // MockgenesisTimeAPI is a mock of genesisTimeAPI interface.
 type MockgenesisTimeAPI struct {
 	ctrl     *gomock.Controller
@@ -544,6 +920,36 @@ func (m *MockmeshAPI) EXPECT() *MockmeshAPIMockRecorder {
 	return m.recorder
 }
 
+// BallotsBySmesher mocks base method.
+func (m *MockmeshAPI) BallotsBySmesher(arg0 types.NodeID, arg1 types.EpochID) ([]*types.Ballot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BallotsBySmesher", arg0, arg1)
+	ret0, _ := ret[0].([]*types.Ballot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BallotsBySmesher indicates an expected call of BallotsBySmesher.
+func (mr *MockmeshAPIMockRecorder) BallotsBySmesher(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BallotsBySmesher", reflect.TypeOf((*MockmeshAPI)(nil).BallotsBySmesher), arg0, arg1)
+}
+
+// BlocksBySmesher mocks base method.
+func (m *MockmeshAPI) BlocksBySmesher(arg0 types.NodeID, arg1 types.EpochID) ([]types.BlockID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlocksBySmesher", arg0, arg1)
+	ret0, _ := ret[0].([]types.BlockID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlocksBySmesher indicates an expected call of BlocksBySmesher.
+func (mr *MockmeshAPIMockRecorder) BlocksBySmesher(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlocksBySmesher", reflect.TypeOf((*MockmeshAPI)(nil).BlocksBySmesher), arg0, arg1)
+}
+
 // GetATXs mocks base method.
 func (m *MockmeshAPI) GetATXs(arg0 context.Context, arg1 []types.ATXID) (map[types.ATXID]*types.VerifiedActivationTx, []types.ATXID) {
 	m.ctrl.T.Helper()
@@ -589,6 +995,50 @@ func (mr *MockmeshAPIMockRecorder) GetRewards(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRewards", reflect.TypeOf((*MockmeshAPI)(nil).GetRewards), arg0)
 }
 
+// GetSmesherRewards mocks base method.
+func (m *MockmeshAPI) GetSmesherRewards(arg0 types.NodeID, arg1, arg2 int) ([]*types.Reward, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSmesherRewards", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*types.Reward)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetSmesherRewards indicates an expected call of GetSmesherRewards.
+func (mr *MockmeshAPIMockRecorder) GetSmesherRewards(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSmesherRewards", reflect.TypeOf((*MockmeshAPI)(nil).GetSmesherRewards), arg0, arg1, arg2)
+}
+
+// IterateLayerBallots mocks base method.
+func (m *MockmeshAPI) IterateLayerBallots(arg0 types.LayerID, arg1 func(*types.Ballot) bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IterateLayerBallots", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IterateLayerBallots indicates an expected call of IterateLayerBallots.
+func (mr *MockmeshAPIMockRecorder) IterateLayerBallots(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IterateLayerBallots", reflect.TypeOf((*MockmeshAPI)(nil).IterateLayerBallots), arg0, arg1)
+}
+
+// IterateLayerBlocks mocks base method.
+func (m *MockmeshAPI) IterateLayerBlocks(arg0 types.LayerID, arg1 func(*types.Block) bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IterateLayerBlocks", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IterateLayerBlocks indicates an expected call of IterateLayerBlocks.
+func (mr *MockmeshAPIMockRecorder) IterateLayerBlocks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IterateLayerBlocks", reflect.TypeOf((*MockmeshAPI)(nil).IterateLayerBlocks), arg0, arg1)
+}
+
 // LatestLayer mocks base method.
 func (m *MockmeshAPI) LatestLayer() types.LayerID {
 	m.ctrl.T.Helper()
@@ -632,6 +1082,20 @@ func (mr *MockmeshAPIMockRecorder) MeshHash(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MeshHash", reflect.TypeOf((*MockmeshAPI)(nil).MeshHash), arg0)
 }
 
+// MeshStatus mocks base method.
+func (m *MockmeshAPI) MeshStatus() mesh.Status {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MeshStatus")
+	ret0, _ := ret[0].(mesh.Status)
+	return ret0
+}
+
+// MeshStatus indicates an expected call of MeshStatus.
+func (mr *MockmeshAPIMockRecorder) MeshStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MeshStatus", reflect.TypeOf((*MockmeshAPI)(nil).MeshStatus))
+}
+
 // ProcessedLayer mocks base method.
 func (m *MockmeshAPI) ProcessedLayer() types.LayerID {
 	m.ctrl.T.Helper()
@@ -646,6 +1110,20 @@ func (mr *MockmeshAPIMockRecorder) ProcessedLayer() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessedLayer", reflect.TypeOf((*MockmeshAPI)(nil).ProcessedLayer))
 }
 
+// VerificationStatus mocks base method.
+func (m *MockmeshAPI) VerificationStatus() mesh.VerificationStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerificationStatus")
+	ret0, _ := ret[0].(mesh.VerificationStatus)
+	return ret0
+}
+
+// VerificationStatus indicates an expected call of VerificationStatus.
+func (mr *MockmeshAPIMockRecorder) VerificationStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerificationStatus", reflect.TypeOf((*MockmeshAPI)(nil).VerificationStatus))
+}
+
 // Mockoracle is a mock of oracle interface.
 type Mockoracle struct {
 	ctrl     *gomock.Controller
@@ -0,0 +1,61 @@
+package grpcserver
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"golang.org/x/mod/semver"
+)
+
+// NodeInfo is the build and compatibility information of the node.
+type NodeInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time,omitempty"`
+	// NetworkID is the genesis id, nodes with different genesis ids can't connect to each other.
+	NetworkID   string `json:"network_id"`
+	GenesisHash string `json:"genesis_hash"`
+	GenesisTime string `json:"genesis_time"`
+	// MinPeerVersion and MinClientVersion are the oldest versions of the peers and the clients
+	// that are compatible with the node.
+	MinPeerVersion   string `json:"min_peer_version,omitempty"`
+	MinClientVersion string `json:"min_client_version,omitempty"`
+}
+
+// NodeInfoResponse is the node info, and the compatibility of the client
+// if the client version was sent with the request.
+type NodeInfoResponse struct {
+	NodeInfo
+	Compatible *bool  `json:"compatible,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// registerInfo registers the node info endpoint with the grpc gateway.
+func (s NodeService) registerInfo(mux *runtime.ServeMux) error {
+	path := "/v1/node/info"
+	if err := mux.HandlePath(http.MethodGet, path, s.nodeInfo); err != nil {
+		return fmt.Errorf("register %s: %w", path, err)
+	}
+	return nil
+}
+
+// nodeInfo returns the build and compatibility information of the node. if the client_version
+// query parameter is set, the response reports whether the client is compatible with the node.
+func (s NodeService) nodeInfo(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	rst := NodeInfoResponse{NodeInfo: s.info}
+	if version := r.URL.Query().Get("client_version"); version != "" {
+		if !semver.IsValid(version) {
+			http.Error(w, fmt.Sprintf("invalid client version %q, expected semantic version", version), http.StatusBadRequest)
+			return
+		}
+		compatible := true
+		if semver.IsValid(s.info.MinClientVersion) && semver.Compare(version, s.info.MinClientVersion) < 0 {
+			compatible = false
+			rst.Reason = fmt.Sprintf("client version %s is older than the min compatible version %s",
+				version, s.info.MinClientVersion)
+		}
+		rst.Compatible = &compatible
+	}
+	writeJSON(w, rst)
+}
@@ -0,0 +1,51 @@
+package grpcserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+)
+
+func TestNodeInfo(t *testing.T) {
+	info := NodeInfo{
+		Version:          "v1.2.0",
+		Commit:           "cafebabe",
+		BuildTime:        "2023-08-01T00:00:00Z",
+		NetworkID:        "0x01",
+		GenesisHash:      "0x02",
+		GenesisTime:      "2023-07-01T00:00:00Z",
+		MinPeerVersion:   "v1.1.0",
+		MinClientVersion: "v1.0.0",
+	}
+	svc := NewNodeService(nil, nil, nil, nil, nil, info, logtest.New(t))
+	mux := runtime.NewServeMux()
+	require.NoError(t, svc.registerInfo(mux))
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	var rst NodeInfoResponse
+	require.Equal(t, http.StatusOK, getJSON(t, srv.URL+"/v1/node/info", &rst))
+	require.Equal(t, info, rst.NodeInfo)
+	require.Nil(t, rst.Compatible)
+
+	for _, tc := range []struct {
+		version    string
+		compatible bool
+	}{
+		{"v1.0.0", true},
+		{"v1.3.1", true},
+		{"v0.9.9", false},
+	} {
+		rst = NodeInfoResponse{}
+		require.Equal(t, http.StatusOK, getJSON(t, srv.URL+"/v1/node/info?client_version="+tc.version, &rst))
+		require.NotNil(t, rst.Compatible)
+		require.Equal(t, tc.compatible, *rst.Compatible, tc.version)
+		require.Equal(t, tc.compatible, rst.Reason == "", tc.version)
+	}
+	require.Equal(t, http.StatusBadRequest, getJSON(t, srv.URL+"/v1/node/info?client_version=1.0", nil))
+}
@@ -3,10 +3,12 @@ package grpcserver
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/golang/protobuf/ptypes/empty"
 	pb "github.com/spacemeshos/api/release/go/spacemesh/v1"
 	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
@@ -16,6 +18,14 @@ import (
 	"github.com/spacemeshos/go-spacemesh/log"
 )
 
+// headers with the hare committee parameters sent in response to the Status request.
+const (
+	hareEpochHeader           = "hare-params-epoch"
+	hareCommitteeSizeHeader   = "hare-committee-size"
+	hareThresholdHeader       = "hare-threshold"
+	hareExpectedLeadersHeader = "hare-exp-leaders"
+)
+
 // NodeService is a grpc server that provides the NodeService, which exposes node-related
 // data such as node status, software version, errors, etc. It can also be used to start
 // the sync process, or to shut down the node.
@@ -25,8 +35,8 @@ type NodeService struct {
 	genTime     genesisTimeAPI
 	peerCounter peerCounter
 	syncer      syncer
-	appVersion  string
-	appCommit   string
+	hare        hareParams
+	info        NodeInfo
 }
 
 // RegisterService registers this service with a grpc server instance.
@@ -40,8 +50,8 @@ func NewNodeService(
 	msh meshAPI,
 	genTime genesisTimeAPI,
 	syncer syncer,
-	appVersion string,
-	appCommit string,
+	hare hareParams,
+	info NodeInfo,
 	lg log.Logger,
 ) *NodeService {
 	return &NodeService{
@@ -50,8 +60,8 @@ func NewNodeService(
 		genTime:     genTime,
 		peerCounter: peers,
 		syncer:      syncer,
-		appVersion:  appVersion,
-		appCommit:   appCommit,
+		hare:        hare,
+		info:        info,
 	}
 }
 
@@ -68,7 +78,7 @@ func (s NodeService) Echo(_ context.Context, in *pb.EchoRequest) (*pb.EchoRespon
 func (s NodeService) Version(context.Context, *empty.Empty) (*pb.VersionResponse, error) {
 	s.logger.Info("GRPC NodeService.Version")
 	return &pb.VersionResponse{
-		VersionString: &pb.SimpleString{Value: s.appVersion},
+		VersionString: &pb.SimpleString{Value: s.info.Version},
 	}, nil
 }
 
@@ -76,15 +86,28 @@ func (s NodeService) Version(context.Context, *empty.Empty) (*pb.VersionResponse
 func (s NodeService) Build(context.Context, *empty.Empty) (*pb.BuildResponse, error) {
 	s.logger.Info("GRPC NodeService.Build")
 	return &pb.BuildResponse{
-		BuildString: &pb.SimpleString{Value: s.appCommit},
+		BuildString: &pb.SimpleString{Value: s.info.Commit},
 	}, nil
 }
 
 // Status returns a status object providing information about the connected peers, sync status,
-// current and verified layer.
+// current and verified layer. The hare committee parameters in effect in the current epoch
+// are sent in the response header.
 func (s NodeService) Status(ctx context.Context, _ *pb.StatusRequest) (*pb.StatusResponse, error) {
 	s.logger.Info("GRPC NodeService.Status")
 
+	if s.hare != nil {
+		params := s.hare.ActiveParams()
+		if err := grpc.SetHeader(ctx, metadata.Pairs(
+			hareEpochHeader, strconv.FormatUint(uint64(params.Epoch), 10),
+			hareCommitteeSizeHeader, strconv.Itoa(params.N),
+			hareThresholdHeader, strconv.Itoa(params.Threshold),
+			hareExpectedLeadersHeader, strconv.Itoa(params.ExpectedLeaders),
+		)); err != nil {
+			return nil, status.Errorf(codes.Internal, "set hare params header: %v", err)
+		}
+	}
+
 	curLayer, latestLayer, verifiedLayer := s.getLayers()
 	return &pb.StatusResponse{
 		Status: &pb.NodeStatus{
@@ -111,12 +134,12 @@ func (s NodeService) getLayers() (curLayer, latestLayer, verifiedLayer uint32) {
 	// epochs, so just return the current layer instead
 	curLayerObj := s.genTime.CurrentLayer()
 	curLayer = curLayerObj.Uint32()
+	meshStatus := s.mesh.MeshStatus()
+	latestLayer = meshStatus.Latest.Uint32()
 	if curLayerObj <= types.GetEffectiveGenesis() {
-		latestLayer = s.mesh.LatestLayer().Uint32()
 		verifiedLayer = latestLayer
 	} else {
-		latestLayer = s.mesh.LatestLayer().Uint32()
-		verifiedLayer = s.mesh.LatestLayerInState().Uint32()
+		verifiedLayer = meshStatus.Applied.Uint32()
 	}
 	return
 }
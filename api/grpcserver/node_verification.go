@@ -0,0 +1,47 @@
+package grpcserver
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+)
+
+// VerificationStatusResponse reports how far tortoise verification is behind processing,
+// see mesh.VerificationStatus.
+type VerificationStatusResponse struct {
+	Processed uint32 `json:"processed"`
+	Verified  uint32 `json:"verified"`
+	// Lag is the number of processed layers that are not yet verified.
+	Lag uint32 `json:"lag"`
+	// Rate is the number of layers verified per second.
+	Rate float64 `json:"rate"`
+	// LastProgress is not set until verified layer advances for the first time.
+	LastProgress *time.Time `json:"last_progress,omitempty"`
+}
+
+// registerVerification registers the verification status endpoint with the grpc gateway.
+func (s NodeService) registerVerification(mux *runtime.ServeMux) error {
+	path := "/v1/node/verification"
+	if err := mux.HandlePath(http.MethodGet, path, s.verificationStatus); err != nil {
+		return fmt.Errorf("register %s: %w", path, err)
+	}
+	return nil
+}
+
+// verificationStatus returns the latest processed and verified layers, the lag between them
+// and the rate of verification.
+func (s NodeService) verificationStatus(w http.ResponseWriter, _ *http.Request, _ map[string]string) {
+	status := s.mesh.VerificationStatus()
+	rst := VerificationStatusResponse{
+		Processed: status.Processed.Uint32(),
+		Verified:  status.Verified.Uint32(),
+		Lag:       status.Lag,
+		Rate:      status.Rate,
+	}
+	if !status.LastProgress.IsZero() {
+		rst.LastProgress = &status.LastProgress
+	}
+	writeJSON(w, rst)
+}
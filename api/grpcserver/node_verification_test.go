@@ -0,0 +1,46 @@
+package grpcserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/mesh"
+)
+
+func TestNodeVerificationStatus(t *testing.T) {
+	msh := NewMockmeshAPI(gomock.NewController(t))
+	svc := NewNodeService(nil, msh, nil, nil, nil, NodeInfo{}, logtest.New(t))
+	mux := runtime.NewServeMux()
+	require.NoError(t, svc.registerVerification(mux))
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	msh.EXPECT().VerificationStatus().Return(mesh.VerificationStatus{Processed: 5, Lag: 5})
+	var rst VerificationStatusResponse
+	require.Equal(t, http.StatusOK, getJSON(t, srv.URL+"/v1/node/verification", &rst))
+	require.Equal(t, VerificationStatusResponse{Processed: 5, Lag: 5}, rst)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	msh.EXPECT().VerificationStatus().Return(mesh.VerificationStatus{
+		Processed:    12,
+		Verified:     10,
+		Lag:          2,
+		Rate:         0.5,
+		LastProgress: now,
+	})
+	rst = VerificationStatusResponse{}
+	require.Equal(t, http.StatusOK, getJSON(t, srv.URL+"/v1/node/verification", &rst))
+	require.Equal(t, uint32(12), rst.Processed)
+	require.Equal(t, uint32(10), rst.Verified)
+	require.Equal(t, uint32(2), rst.Lag)
+	require.Equal(t, 0.5, rst.Rate)
+	require.NotNil(t, rst.LastProgress)
+	require.True(t, now.Equal(*rst.LastProgress))
+}
@@ -0,0 +1,148 @@
+package grpcserver
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/spacemeshos/go-spacemesh/metrics"
+)
+
+// rateLimitedClients is the max number of clients whose limiters are kept in memory.
+// limiters of the least recently seen clients are evicted, which resets their limits.
+const rateLimitedClients = 10000
+
+var rateLimited = metrics.NewCounter(
+	"rate_limited",
+	"grpc",
+	"number of requests rejected by the rate limit",
+	[]string{"method"},
+)
+
+type clientLimiter struct {
+	all *rate.Limiter
+
+	mu      sync.Mutex
+	methods map[string]*rate.Limiter
+}
+
+// RateLimiter limits the rate of requests of every client, identified by its ip address.
+// the limit is applied to all requests of the client, and separately to requests of the
+// methods that have their own limit. streams are limited when they are opened.
+// the requests of the json gateway are limited by the http path instead of the grpc method.
+type RateLimiter struct {
+	rate    float64
+	burst   int
+	methods map[string]float64
+	clients *lru.Cache[string, *clientLimiter]
+}
+
+// NewRateLimiter creates the RateLimiter from the config.
+// it returns nil if no limits are configured.
+func NewRateLimiter(c Config) *RateLimiter {
+	if c.RateLimit <= 0 && len(c.MethodRateLimits) == 0 {
+		return nil
+	}
+	clients, err := lru.New[string, *clientLimiter](rateLimitedClients)
+	if err != nil {
+		panic(err) // only for a non-positive size
+	}
+	return &RateLimiter{
+		rate:    c.RateLimit,
+		burst:   c.RateLimitBurst,
+		methods: c.MethodRateLimits,
+		clients: clients,
+	}
+}
+
+func (l *RateLimiter) newLimiter(r float64) *rate.Limiter {
+	if r <= 0 {
+		return rate.NewLimiter(rate.Inf, 0)
+	}
+	burst := l.burst
+	if burst <= 0 {
+		burst = int(math.Max(1, math.Ceil(r)))
+	}
+	return rate.NewLimiter(rate.Limit(r), burst)
+}
+
+func (l *RateLimiter) client(key string) *clientLimiter {
+	cl, ok := l.clients.Get(key)
+	if !ok {
+		cl = &clientLimiter{all: l.newLimiter(l.rate), methods: map[string]*rate.Limiter{}}
+		if prev, exists, _ := l.clients.PeekOrAdd(key, cl); exists {
+			cl = prev
+		}
+	}
+	return cl
+}
+
+// Allow returns the RESOURCE_EXHAUSTED error if the client exceeded the limits for the method.
+func (l *RateLimiter) Allow(ctx context.Context, method string) error {
+	return l.allow(clientAddress(ctx), method)
+}
+
+func (l *RateLimiter) allow(client, method string) error {
+	cl := l.client(client)
+	if r, ok := l.methods[method]; ok {
+		cl.mu.Lock()
+		lim, exists := cl.methods[method]
+		if !exists {
+			lim = l.newLimiter(r)
+			cl.methods[method] = lim
+		}
+		cl.mu.Unlock()
+		if !lim.Allow() {
+			rateLimited.WithLabelValues(method).Inc()
+			return status.Errorf(codes.ResourceExhausted, "rate limit exceeded for %s", method)
+		}
+	}
+	if !cl.all.Allow() {
+		rateLimited.WithLabelValues(method).Inc()
+		return status.Error(codes.ResourceExhausted, "rate limit exceeded")
+	}
+	return nil
+}
+
+// ServerOptions returns the interceptors that apply the limits to the grpc server.
+func (l *RateLimiter) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			if err := l.Allow(ctx, info.FullMethod); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.ChainStreamInterceptor(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := l.Allow(ss.Context(), info.FullMethod); err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	}
+}
+
+// Handler applies the limits to the requests of the json gateway. the requests over the limits
+// fail with 429 Too Many Requests.
+func (l *RateLimiter) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client := r.RemoteAddr
+		if host, _, err := net.SplitHostPort(client); err == nil {
+			client = host
+		}
+		if err := l.allow(client, r.URL.Path); err != nil {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, status.Convert(err).Message(), http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,111 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func clientContext(ip string) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 1234},
+	})
+}
+
+func TestRateLimiter_Disabled(t *testing.T) {
+	require.Nil(t, NewRateLimiter(DefaultTestConfig()))
+}
+
+func TestRateLimiter_PerClient(t *testing.T) {
+	conf := DefaultTestConfig()
+	conf.RateLimit = 0.001
+	conf.RateLimitBurst = 2
+	limiter := NewRateLimiter(conf)
+
+	ctx := clientContext("10.0.0.1")
+	require.NoError(t, limiter.Allow(ctx, "/a"))
+	require.NoError(t, limiter.Allow(ctx, "/b"))
+	err := limiter.Allow(ctx, "/a")
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// the limit is not shared between clients, and doesn't depend on the port
+	require.NoError(t, limiter.Allow(clientContext("10.0.0.2"), "/a"))
+	other := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 4321},
+	})
+	require.Equal(t, codes.ResourceExhausted, status.Code(limiter.Allow(other, "/a")))
+}
+
+func TestRateLimiter_PerMethod(t *testing.T) {
+	conf := DefaultTestConfig()
+	conf.MethodRateLimits = map[string]float64{"/expensive": 0.001}
+	limiter := NewRateLimiter(conf)
+
+	ctx := clientContext("10.0.0.1")
+	require.NoError(t, limiter.Allow(ctx, "/expensive"))
+	require.Equal(t, codes.ResourceExhausted, status.Code(limiter.Allow(ctx, "/expensive")))
+	for i := 0; i < 10; i++ {
+		require.NoError(t, limiter.Allow(ctx, "/cheap"))
+	}
+}
+
+func TestRateLimiter_Server(t *testing.T) {
+	conf := DefaultTestConfig()
+	conf.RateLimit = 0.001
+	conf.RateLimitBurst = 1
+	address := launchHealthServer(t, NewRateLimiter(conf).ServerOptions()...)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	client := healthpb.NewHealthClient(conn)
+
+	_, err = client.Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	_, err = client.Check(ctx, &healthpb.HealthCheckRequest{})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// streams are limited when they are opened
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestRateLimiter_Handler(t *testing.T) {
+	conf := DefaultTestConfig()
+	conf.MethodRateLimits = map[string]float64{"/expensive": 0.001}
+	handler := NewRateLimiter(conf).Handler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func(addr, path string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = addr
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	// the requests are limited by the path
+	require.Equal(t, http.StatusOK, serve("10.0.0.1:1234", "/expensive"))
+	require.Equal(t, http.StatusTooManyRequests, serve("10.0.0.1:1234", "/expensive"))
+	for i := 0; i < 10; i++ {
+		require.Equal(t, http.StatusOK, serve("10.0.0.1:1234", "/cheap"))
+	}
+	// the limit of the client doesn't depend on the port
+	require.Equal(t, http.StatusTooManyRequests, serve("10.0.0.1:4321", "/expensive"))
+	require.Equal(t, http.StatusOK, serve("10.0.0.2:1234", "/expensive"))
+}
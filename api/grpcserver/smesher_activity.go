@@ -0,0 +1,83 @@
+package grpcserver
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log"
+)
+
+// SmesherBallotJSON is a ballot published by the smesher.
+type SmesherBallotJSON struct {
+	ID            string `json:"id"`
+	Layer         uint32 `json:"layer"`
+	Eligibilities uint32 `json:"eligibilities"`
+}
+
+// SmesherActivity is the ballots published by the smesher in the epoch, and the blocks
+// that reward the smesher in the epoch.
+type SmesherActivity struct {
+	Smesher string              `json:"smesher"`
+	Epoch   uint32              `json:"epoch"`
+	Ballots []SmesherBallotJSON `json:"ballots"`
+	Blocks  []string            `json:"blocks"`
+}
+
+// registerSmesherActivity registers the smesher activity endpoint with the grpc gateway.
+func (s MeshService) registerSmesherActivity(mux *runtime.ServeMux) error {
+	path := "/v1/mesh/smeshers/{id}/epochs/{epoch}"
+	if err := mux.HandlePath(http.MethodGet, path, s.smesherActivity); err != nil {
+		return fmt.Errorf("register %s: %w", path, err)
+	}
+	return nil
+}
+
+// smesherActivity returns ballots and rewarded blocks of the smesher in the epoch, ordered by layer.
+func (s MeshService) smesherActivity(w http.ResponseWriter, _ *http.Request, params map[string]string) {
+	raw, err := parseHexID(params["id"], types.NodeIDSize)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	parsed, err := strconv.ParseUint(params["epoch"], 10, 32)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid epoch %q", params["epoch"]), http.StatusBadRequest)
+		return
+	}
+	smesher := types.BytesToNodeID(raw)
+	epoch := types.EpochID(parsed)
+	ballots, err := s.mesh.BallotsBySmesher(smesher, epoch)
+	if err != nil {
+		s.logger.With().Error("failed to list smesher ballots", log.Err(err))
+		http.Error(w, "failed to list ballots", http.StatusInternalServerError)
+		return
+	}
+	blocks, err := s.mesh.BlocksBySmesher(smesher, epoch)
+	if err != nil {
+		s.logger.With().Error("failed to list smesher blocks", log.Err(err))
+		http.Error(w, "failed to list blocks", http.StatusInternalServerError)
+		return
+	}
+	rst := SmesherActivity{
+		Smesher: smesher.String(),
+		Epoch:   epoch.Uint32(),
+		Ballots: make([]SmesherBallotJSON, 0, len(ballots)),
+		Blocks:  make([]string, 0, len(blocks)),
+	}
+	for _, ballot := range ballots {
+		rst.Ballots = append(rst.Ballots, SmesherBallotJSON{
+			ID:            hex.EncodeToString(ballot.ID().Bytes()),
+			Layer:         ballot.Layer.Uint32(),
+			Eligibilities: uint32(len(ballot.EligibilityProofs)),
+		})
+	}
+	for _, id := range blocks {
+		rst.Blocks = append(rst.Blocks, hex.EncodeToString(id.Bytes()))
+	}
+	writeJSON(w, rst)
+}
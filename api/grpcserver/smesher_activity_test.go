@@ -0,0 +1,68 @@
+package grpcserver
+
+import (
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+)
+
+func newSmesherActivityServer(t *testing.T) (*MockmeshAPI, *httptest.Server) {
+	msh := NewMockmeshAPI(gomock.NewController(t))
+	svc := NewMeshService(nil, msh, nil, nil, 0, types.Hash20{}, 0, 0, 0, logtest.New(t))
+	mux := runtime.NewServeMux()
+	require.NoError(t, svc.registerSmesherActivity(mux))
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return msh, srv
+}
+
+func TestSmesherActivity(t *testing.T) {
+	msh, srv := newSmesherActivityServer(t)
+	smesher := types.RandomNodeID()
+	epoch := types.EpochID(3)
+	ballot := types.NewExistingBallot(types.RandomBallotID(), types.EmptyEdSignature, smesher, epoch.FirstLayer())
+	ballot.EligibilityProofs = make([]types.VotingEligibility, 2)
+	block := types.RandomBlockID()
+	msh.EXPECT().BallotsBySmesher(smesher, epoch).Return([]*types.Ballot{&ballot}, nil)
+	msh.EXPECT().BlocksBySmesher(smesher, epoch).Return([]types.BlockID{block}, nil)
+
+	var rst SmesherActivity
+	require.Equal(t, http.StatusOK, getJSON(t, srv.URL+"/v1/mesh/smeshers/"+smesher.String()+"/epochs/3", &rst))
+	require.Equal(t, SmesherActivity{
+		Smesher: smesher.String(),
+		Epoch:   3,
+		Ballots: []SmesherBallotJSON{{
+			ID:            hex.EncodeToString(ballot.ID().Bytes()),
+			Layer:         epoch.FirstLayer().Uint32(),
+			Eligibilities: 2,
+		}},
+		Blocks: []string{hex.EncodeToString(block.Bytes())},
+	}, rst)
+
+	msh.EXPECT().BallotsBySmesher(smesher, types.EpochID(4)).Return(nil, nil)
+	msh.EXPECT().BlocksBySmesher(smesher, types.EpochID(4)).Return(nil, nil)
+	rst = SmesherActivity{}
+	require.Equal(t, http.StatusOK, getJSON(t, srv.URL+"/v1/mesh/smeshers/"+smesher.String()+"/epochs/4", &rst))
+	require.Empty(t, rst.Ballots)
+	require.Empty(t, rst.Blocks)
+}
+
+func TestSmesherActivity_Errors(t *testing.T) {
+	msh, srv := newSmesherActivityServer(t)
+	smesher := types.RandomNodeID()
+
+	require.Equal(t, http.StatusBadRequest, getJSON(t, srv.URL+"/v1/mesh/smeshers/abcd/epochs/1", nil))
+	require.Equal(t, http.StatusBadRequest, getJSON(t, srv.URL+"/v1/mesh/smeshers/"+smesher.String()+"/epochs/x", nil))
+
+	msh.EXPECT().BallotsBySmesher(smesher, types.EpochID(1)).Return(nil, errors.New("test"))
+	require.Equal(t, http.StatusInternalServerError, getJSON(t, srv.URL+"/v1/mesh/smeshers/"+smesher.String()+"/epochs/1", nil))
+}
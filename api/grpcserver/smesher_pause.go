@@ -0,0 +1,74 @@
+package grpcserver
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+)
+
+// smeshingPausedHeader is sent in response to the IsSmeshing request, it is true
+// if smeshing was paused and the node doesn't smesh until it is resumed.
+const smeshingPausedHeader = "smeshing-paused"
+
+// SmeshingStatus is the state of smeshing.
+type SmeshingStatus struct {
+	Smeshing bool   `json:"smeshing"`
+	Paused   bool   `json:"paused"`
+	Coinbase string `json:"coinbase"`
+}
+
+// registerPause registers the endpoints to pause and resume smeshing with the grpc gateway.
+func (s SmesherService) registerPause(mux *runtime.ServeMux) error {
+	for _, route := range []struct {
+		method, path string
+		handler      runtime.HandlerFunc
+	}{
+		{http.MethodGet, "/v1/smesher/status", s.smeshingStatus},
+		{http.MethodPost, "/v1/smesher/pause", s.pauseSmeshing},
+		{http.MethodPost, "/v1/smesher/resume", s.resumeSmeshing},
+	} {
+		if err := mux.HandlePath(route.method, route.path, route.handler); err != nil {
+			return fmt.Errorf("register %s: %w", route.path, err)
+		}
+	}
+	return nil
+}
+
+func (s SmesherService) status() SmeshingStatus {
+	return SmeshingStatus{
+		Smeshing: s.smeshingProvider.Smeshing(),
+		Paused:   s.smeshingProvider.Paused(),
+		Coinbase: s.smeshingProvider.Coinbase().String(),
+	}
+}
+
+// smeshingStatus returns whether the node is smeshing, or smeshing is paused.
+func (s SmesherService) smeshingStatus(w http.ResponseWriter, _ *http.Request, _ map[string]string) {
+	writeJSON(w, s.status())
+}
+
+// pauseSmeshing stops building atxs and proposals without deleting the post data, unlike
+// StopSmeshing. the node stays paused after the restart until smeshing is resumed.
+func (s SmesherService) pauseSmeshing(w http.ResponseWriter, _ *http.Request, _ map[string]string) {
+	s.logger.Info("HTTP SmesherService.PauseSmeshing")
+
+	if err := s.smeshingProvider.PauseSmeshing(); err != nil {
+		s.logger.Error("failed to pause smeshing: %v", err)
+		http.Error(w, fmt.Sprintf("failed to pause smeshing: %v", err), http.StatusConflict)
+		return
+	}
+	writeJSON(w, s.status())
+}
+
+// resumeSmeshing starts smeshing paused with pauseSmeshing, with the same coinbase and post options.
+func (s SmesherService) resumeSmeshing(w http.ResponseWriter, _ *http.Request, _ map[string]string) {
+	s.logger.Info("HTTP SmesherService.ResumeSmeshing")
+
+	if err := s.smeshingProvider.ResumeSmeshing(); err != nil {
+		s.logger.Error("failed to resume smeshing: %v", err)
+		http.Error(w, fmt.Sprintf("failed to resume smeshing: %v", err), http.StatusConflict)
+		return
+	}
+	writeJSON(w, s.status())
+}
@@ -0,0 +1,63 @@
+package grpcserver
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/activation"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+)
+
+func TestSmesherService_Pause(t *testing.T) {
+	smeshing := activation.NewMockSmeshingProvider(gomock.NewController(t))
+	svc := NewSmesherService(nil, smeshing, time.Second, activation.PostSetupOpts{}, logtest.New(t))
+	mux := runtime.NewServeMux()
+	require.NoError(t, svc.registerPause(mux))
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	coinbase := types.GenerateAddress([]byte{1})
+	smeshing.EXPECT().Coinbase().Return(coinbase).AnyTimes()
+	paused := false
+	smeshing.EXPECT().Paused().DoAndReturn(func() bool { return paused }).AnyTimes()
+	smeshing.EXPECT().Smeshing().DoAndReturn(func() bool { return !paused }).AnyTimes()
+
+	var status SmeshingStatus
+	require.Equal(t, http.StatusOK, getJSON(t, srv.URL+"/v1/smesher/status", &status))
+	require.Equal(t, SmeshingStatus{Smeshing: true, Coinbase: coinbase.String()}, status)
+
+	post := func(path string) *http.Response {
+		resp, err := http.Post(srv.URL+path, "application/json", nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	smeshing.EXPECT().PauseSmeshing().DoAndReturn(func() error {
+		paused = true
+		return nil
+	})
+	require.Equal(t, http.StatusOK, post("/v1/smesher/pause").StatusCode)
+	require.Equal(t, http.StatusOK, getJSON(t, srv.URL+"/v1/smesher/status", &status))
+	require.Equal(t, SmeshingStatus{Paused: true, Coinbase: coinbase.String()}, status)
+
+	smeshing.EXPECT().PauseSmeshing().Return(errors.New("already paused"))
+	require.Equal(t, http.StatusConflict, post("/v1/smesher/pause").StatusCode)
+
+	smeshing.EXPECT().ResumeSmeshing().DoAndReturn(func() error {
+		paused = false
+		return nil
+	})
+	require.Equal(t, http.StatusOK, post("/v1/smesher/resume").StatusCode)
+	require.Equal(t, http.StatusOK, getJSON(t, srv.URL+"/v1/smesher/status", &status))
+	require.True(t, status.Smeshing)
+	require.False(t, status.Paused)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/golang/protobuf/ptypes/empty"
@@ -11,7 +12,9 @@ import (
 	"github.com/spacemeshos/post/config"
 	"google.golang.org/genproto/googleapis/rpc/code"
 	rpcstatus "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	"github.com/spacemeshos/go-spacemesh/activation"
@@ -46,10 +49,17 @@ func NewSmesherService(post postSetupProvider, smeshing activation.SmeshingProvi
 	}
 }
 
-// IsSmeshing reports whether the node is smeshing.
-func (s SmesherService) IsSmeshing(context.Context, *empty.Empty) (*pb.IsSmeshingResponse, error) {
+// IsSmeshing reports whether the node is smeshing. whether smeshing is paused
+// is sent in the response header.
+func (s SmesherService) IsSmeshing(ctx context.Context, _ *empty.Empty) (*pb.IsSmeshingResponse, error) {
 	s.logger.Info("GRPC SmesherService.IsSmeshing")
 
+	if err := grpc.SetHeader(ctx, metadata.Pairs(
+		smeshingPausedHeader, strconv.FormatBool(s.smeshingProvider.Paused()),
+	)); err != nil {
+		return nil, status.Errorf(codes.Internal, "set paused header: %v", err)
+	}
+
 	return &pb.IsSmeshingResponse{IsSmeshing: s.smeshingProvider.Smeshing()}, nil
 }
 
@@ -0,0 +1,210 @@
+package grpcserver
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/spacemeshos/go-scale"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	vm "github.com/spacemeshos/go-spacemesh/genvm"
+	"github.com/spacemeshos/go-spacemesh/genvm/core"
+	"github.com/spacemeshos/go-spacemesh/genvm/templates/multisig"
+	"github.com/spacemeshos/go-spacemesh/genvm/templates/vault"
+	"github.com/spacemeshos/go-spacemesh/genvm/templates/vesting"
+	"github.com/spacemeshos/go-spacemesh/genvm/templates/wallet"
+	"github.com/spacemeshos/go-spacemesh/log"
+)
+
+// maxMultisigKeys is the max number of the public keys in the multisig state.
+const maxMultisigKeys = 10
+
+// SpawnArgumentsJSON are the spawn arguments of the template, only the fields of the template are used.
+// Wallet requires a single public key, multisig and vesting require the number of required signatures
+// and the public keys, vault requires the owner and the vesting schedule.
+type SpawnArgumentsJSON struct {
+	Template            string   `json:"template"`
+	Required            uint8    `json:"required,omitempty"`
+	PublicKeys          []string `json:"public_keys,omitempty"`
+	Owner               string   `json:"owner,omitempty"`
+	TotalAmount         uint64   `json:"total_amount,omitempty"`
+	InitialUnlockAmount uint64   `json:"initial_unlock_amount,omitempty"`
+	VestingStart        uint32   `json:"vesting_start,omitempty"`
+	VestingEnd          uint32   `json:"vesting_end,omitempty"`
+}
+
+func (a *SpawnArgumentsJSON) publicKeys() ([]core.PublicKey, error) {
+	keys := make([]core.PublicKey, len(a.PublicKeys))
+	for i, raw := range a.PublicKeys {
+		if err := decodeHash(strings.TrimPrefix(raw, "0x"), &keys[i]); err != nil {
+			return nil, fmt.Errorf("public key %d: %w", i, err)
+		}
+	}
+	return keys, nil
+}
+
+// decode returns the address of the template and its spawn arguments.
+func (a *SpawnArgumentsJSON) decode() (core.Address, scale.Encodable, error) {
+	template, ok := vm.TemplateByName(a.Template)
+	if !ok {
+		return template, nil, fmt.Errorf("unknown template %q", a.Template)
+	}
+	switch a.Template {
+	case vm.WalletTemplate, vm.MultisigTemplate, vm.VestingTemplate:
+		keys, err := a.publicKeys()
+		if err != nil {
+			return template, nil, err
+		}
+		if a.Template == vm.WalletTemplate {
+			if len(keys) != 1 {
+				return template, nil, errors.New("wallet requires a single public key")
+			}
+			return template, &wallet.SpawnArguments{PublicKey: keys[0]}, nil
+		}
+		if len(keys) > maxMultisigKeys {
+			return template, nil, fmt.Errorf("max %d public keys", maxMultisigKeys)
+		}
+		return template, &multisig.SpawnArguments{Required: a.Required, PublicKeys: keys}, nil
+	default:
+		owner, err := types.StringToAddress(a.Owner)
+		if err != nil {
+			return template, nil, fmt.Errorf("owner: %w", err)
+		}
+		return template, &vault.SpawnArguments{
+			Owner:               owner,
+			TotalAmount:         a.TotalAmount,
+			InitialUnlockAmount: a.InitialUnlockAmount,
+			VestingStart:        types.LayerID(a.VestingStart),
+			VestingEnd:          types.LayerID(a.VestingEnd),
+		}, nil
+	}
+}
+
+// SpawnPrincipalJSON is the address of the account spawned with the arguments.
+type SpawnPrincipalJSON struct {
+	Principal string `json:"principal"`
+	Template  string `json:"template_address"`
+}
+
+// VaultJSON is the vesting schedule of the vault. Available is the amount vested
+// at the latest applied layer, including the amount drained so far.
+type VaultJSON struct {
+	Owner               string `json:"owner"`
+	TotalAmount         uint64 `json:"total_amount"`
+	InitialUnlockAmount uint64 `json:"initial_unlock_amount"`
+	VestingStart        uint32 `json:"vesting_start"`
+	VestingEnd          uint32 `json:"vesting_end"`
+	DrainedSoFar        uint64 `json:"drained_so_far"`
+	Available           uint64 `json:"available"`
+}
+
+// AccountTemplateJSON is the decoded template state of the spawned account.
+type AccountTemplateJSON struct {
+	Address         string     `json:"address"`
+	Template        string     `json:"template"`
+	TemplateAddress string     `json:"template_address"`
+	Balance         uint64     `json:"balance"`
+	NextNonce       uint64     `json:"next_nonce"`
+	Required        uint8      `json:"required,omitempty"`
+	PublicKeys      []string   `json:"public_keys,omitempty"`
+	Vault           *VaultJSON `json:"vault,omitempty"`
+}
+
+// registerTemplates registers the template endpoints with the grpc gateway.
+func (s GlobalStateService) registerTemplates(mux *runtime.ServeMux) error {
+	for _, route := range []struct {
+		method, path string
+		handler      runtime.HandlerFunc
+	}{
+		{http.MethodPost, "/v1/globalstate/templates/principal", s.spawnPrincipal},
+		{http.MethodGet, "/v1/globalstate/accounts/{address}/template", s.accountTemplate},
+	} {
+		if err := mux.HandlePath(route.method, route.path, route.handler); err != nil {
+			return fmt.Errorf("register %s: %w", route.path, err)
+		}
+	}
+	return nil
+}
+
+// spawnPrincipal validates the spawn arguments of the template and returns the address of the account
+// spawned with them, so that clients can fund the account before the spawn transaction.
+func (s GlobalStateService) spawnPrincipal(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var req SpawnArgumentsJSON
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	template, args, err := req.decode()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	principal, err := vm.SpawnPrincipal(template, args)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, SpawnPrincipalJSON{Principal: principal.String(), Template: template.String()})
+}
+
+// accountTemplate returns the template state of the spawned account.
+func (s GlobalStateService) accountTemplate(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	addr, err := types.StringToAddress(params["address"])
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid address: %v", err), http.StatusBadRequest)
+		return
+	}
+	account, err := s.conState.GetAccount(addr)
+	if err != nil {
+		s.logger.With().Error("failed to get account", addr, log.Err(err))
+		http.Error(w, "error fetching account", http.StatusInternalServerError)
+		return
+	}
+	if account.TemplateAddress == nil {
+		http.Error(w, fmt.Sprintf("account %s is not spawned", addr), http.StatusNotFound)
+		return
+	}
+	template, err := vm.LoadTemplate(&account)
+	if err != nil {
+		s.logger.With().Error("failed to load account template", addr, log.Err(err))
+		http.Error(w, "error loading account template", http.StatusInternalServerError)
+		return
+	}
+	rst := AccountTemplateJSON{
+		Address:         addr.String(),
+		Template:        vm.TemplateName(*account.TemplateAddress),
+		TemplateAddress: account.TemplateAddress.String(),
+		Balance:         account.Balance,
+		NextNonce:       account.NextNonce,
+	}
+	var keys []core.PublicKey
+	switch typed := template.(type) {
+	case *wallet.Wallet:
+		keys = []core.PublicKey{typed.PublicKey}
+	case *multisig.MultiSig:
+		rst.Required = typed.Required
+		keys = typed.PublicKeys
+	case *vesting.Vesting:
+		rst.Required = typed.Required
+		keys = typed.PublicKeys
+	case *vault.Vault:
+		rst.Vault = &VaultJSON{
+			Owner:               typed.Owner.String(),
+			TotalAmount:         typed.TotalAmount,
+			InitialUnlockAmount: typed.InitialUnlockAmount,
+			VestingStart:        typed.VestingStart.Uint32(),
+			VestingEnd:          typed.VestingEnd.Uint32(),
+			DrainedSoFar:        typed.DrainedSoFar,
+			Available:           typed.Available(s.mesh.LatestLayerInState()),
+		}
+	}
+	for _, key := range keys {
+		rst.PublicKeys = append(rst.PublicKeys, hex.EncodeToString(key[:]))
+	}
+	writeJSON(w, rst)
+}
@@ -0,0 +1,159 @@
+package grpcserver
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/spacemeshos/go-scale"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/genvm/core"
+	sdkmultisig "github.com/spacemeshos/go-spacemesh/genvm/sdk/multisig"
+	"github.com/spacemeshos/go-spacemesh/genvm/templates/multisig"
+	"github.com/spacemeshos/go-spacemesh/genvm/templates/vault"
+	"github.com/spacemeshos/go-spacemesh/genvm/templates/vesting"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+)
+
+func newTemplatesServer(t *testing.T) (*MockmeshAPI, *MockconservativeState, *httptest.Server) {
+	ctrl := gomock.NewController(t)
+	meshAPI := NewMockmeshAPI(ctrl)
+	conState := NewMockconservativeState(ctrl)
+	svc := NewGlobalStateService(meshAPI, conState, logtest.New(t))
+	mux := runtime.NewServeMux()
+	require.NoError(t, svc.registerTemplates(mux))
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return meshAPI, conState, srv
+}
+
+func postSpawnArguments(t *testing.T, url string, args SpawnArgumentsJSON, rst *SpawnPrincipalJSON) int {
+	t.Helper()
+	body, err := json.Marshal(args)
+	require.NoError(t, err)
+	resp, err := http.Post(url+"/v1/globalstate/templates/principal", "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(rst))
+	}
+	return resp.StatusCode
+}
+
+func encodeState(t *testing.T, state scale.Encodable) []byte {
+	t.Helper()
+	buf := bytes.NewBuffer(nil)
+	_, err := state.EncodeScale(scale.NewEncoder(buf))
+	require.NoError(t, err)
+	return buf.Bytes()
+}
+
+func TestTemplates_SpawnPrincipal(t *testing.T) {
+	_, _, srv := newTemplatesServer(t)
+	pubs := [][]byte{types.RandomBytes(32), types.RandomBytes(32), types.RandomBytes(32)}
+	var keys []string
+	for _, pub := range pubs {
+		keys = append(keys, hex.EncodeToString(pub))
+	}
+
+	var rst SpawnPrincipalJSON
+	args := SpawnArgumentsJSON{Template: "vesting", Required: 2, PublicKeys: keys}
+	require.Equal(t, http.StatusOK, postSpawnArguments(t, srv.URL, args, &rst))
+	require.Equal(t, sdkmultisig.Address(vesting.TemplateAddress, 2, pubs...).String(), rst.Principal)
+	require.Equal(t, vesting.TemplateAddress.String(), rst.Template)
+
+	owner := types.GenerateAddress(types.RandomBytes(32))
+	args = SpawnArgumentsJSON{
+		Template:     "vault",
+		Owner:        owner.String(),
+		TotalAmount:  100,
+		VestingStart: 10,
+		VestingEnd:   20,
+	}
+	require.Equal(t, http.StatusOK, postSpawnArguments(t, srv.URL, args, &rst))
+	expected := core.ComputePrincipal(vault.TemplateAddress, &vault.SpawnArguments{
+		Owner:        owner,
+		TotalAmount:  100,
+		VestingStart: 10,
+		VestingEnd:   20,
+	})
+	require.Equal(t, expected.String(), rst.Principal)
+
+	for _, invalid := range []SpawnArgumentsJSON{
+		{Template: "unknown"},
+		{Template: "wallet", PublicKeys: keys},
+		{Template: "multisig", Required: 4, PublicKeys: keys},
+		{Template: "multisig", Required: 1, PublicKeys: []string{"01"}},
+		{Template: "vault", Owner: owner.String(), TotalAmount: 1, InitialUnlockAmount: 2},
+		{Template: "vault", Owner: owner.String(), VestingStart: 2, VestingEnd: 1},
+	} {
+		require.Equal(t, http.StatusBadRequest, postSpawnArguments(t, srv.URL, invalid, &rst), invalid)
+	}
+}
+
+func TestTemplates_AccountTemplate(t *testing.T) {
+	meshAPI, conState, srv := newTemplatesServer(t)
+	url := func(addr types.Address) string {
+		return fmt.Sprintf("%s/v1/globalstate/accounts/%s/template", srv.URL, addr.String())
+	}
+
+	addr := types.GenerateAddress(types.RandomBytes(32))
+	conState.EXPECT().GetAccount(addr).Return(types.Account{Address: addr, Balance: 10}, nil)
+	var rst AccountTemplateJSON
+	require.Equal(t, http.StatusNotFound, getJSON(t, url(addr), &rst))
+
+	key := types.RandomHash()
+	template := multisig.TemplateAddress
+	conState.EXPECT().GetAccount(addr).Return(types.Account{
+		Address:         addr,
+		Balance:         10,
+		NextNonce:       2,
+		TemplateAddress: &template,
+		State:           encodeState(t, &multisig.MultiSig{Required: 1, PublicKeys: []core.PublicKey{key}}),
+	}, nil)
+	require.Equal(t, http.StatusOK, getJSON(t, url(addr), &rst))
+	require.Equal(t, AccountTemplateJSON{
+		Address:         addr.String(),
+		Template:        "multisig",
+		TemplateAddress: template.String(),
+		Balance:         10,
+		NextNonce:       2,
+		Required:        1,
+		PublicKeys:      []string{hex.EncodeToString(key[:])},
+	}, rst)
+
+	owner := types.GenerateAddress(types.RandomBytes(32))
+	template = vault.TemplateAddress
+	conState.EXPECT().GetAccount(addr).Return(types.Account{
+		Address:         addr,
+		Balance:         70,
+		TemplateAddress: &template,
+		State: encodeState(t, &vault.Vault{
+			Owner:        owner,
+			TotalAmount:  100,
+			VestingStart: 10,
+			VestingEnd:   20,
+			DrainedSoFar: 30,
+		}),
+	}, nil)
+	meshAPI.EXPECT().LatestLayerInState().Return(types.LayerID(15))
+	rst = AccountTemplateJSON{}
+	require.Equal(t, http.StatusOK, getJSON(t, url(addr), &rst))
+	require.Equal(t, "vault", rst.Template)
+	require.Equal(t, &VaultJSON{
+		Owner:        owner.String(),
+		TotalAmount:  100,
+		VestingStart: 10,
+		VestingEnd:   20,
+		DrainedSoFar: 30,
+		Available:    50,
+	}, rst.Vault)
+}
@@ -0,0 +1,71 @@
+package grpcserver
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/spacemeshos/go-spacemesh/p2p"
+)
+
+// NetworkTopology is the view of the node on the p2p network.
+type NetworkTopology struct {
+	ID           p2p.Peer         `json:"id"`
+	Connections  []p2p.Connection `json:"connections"`
+	RoutingTable []p2p.Peer       `json:"routing_table"`
+	Topics       []TopicPeers     `json:"topics"`
+}
+
+// TopicPeers are the peers subscribed to the gossip topic, and the subset of them
+// in the gossipsub mesh of the node.
+type TopicPeers struct {
+	Topic string     `json:"topic"`
+	Peers []p2p.Peer `json:"peers"`
+	Mesh  []p2p.Peer `json:"mesh"`
+}
+
+// Topology serves the view of the node on the p2p network. it exposes the addresses of the
+// peers of the node, so it is served only by the private json gateway.
+type Topology struct {
+	host networkHost
+}
+
+// NewTopology creates the topology endpoint for the host.
+func NewTopology(host networkHost) *Topology {
+	return &Topology{host: host}
+}
+
+// Topology writes the connected peers, the contents of the dht routing table and
+// the peers of every gossip topic, for network health tooling and visualization.
+func (t *Topology) Topology(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, fmt.Sprintf("method %s not allowed", r.Method), http.StatusMethodNotAllowed)
+		return
+	}
+	rst := NetworkTopology{
+		ID:           t.host.ID(),
+		Connections:  t.host.Connections(),
+		RoutingTable: sortedPeers(t.host.RoutingTable()),
+	}
+	sort.Slice(rst.Connections, func(i, j int) bool {
+		return rst.Connections[i].Peer < rst.Connections[j].Peer
+	})
+	topics := t.host.Topics()
+	sort.Strings(topics)
+	for _, topic := range topics {
+		rst.Topics = append(rst.Topics, TopicPeers{
+			Topic: topic,
+			Peers: sortedPeers(t.host.ProtocolPeers(topic)),
+			Mesh:  sortedPeers(t.host.MeshPeers(topic)),
+		})
+	}
+	writeJSON(w, rst)
+}
+
+func sortedPeers(peers []p2p.Peer) []p2p.Peer {
+	if peers == nil {
+		peers = []p2p.Peer{}
+	}
+	sort.Slice(peers, func(i, j int) bool { return peers[i] < peers[j] })
+	return peers
+}
@@ -0,0 +1,64 @@
+package grpcserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/p2p"
+)
+
+func TestTopology(t *testing.T) {
+	host := NewMocknetworkHost(gomock.NewController(t))
+	srv := httptest.NewServer(http.HandlerFunc(NewTopology(host).Topology))
+	t.Cleanup(srv.Close)
+
+	peers := make([]p2p.Peer, 4)
+	for i := range peers {
+		_, pub, err := crypto.GenerateEd25519Key(nil)
+		require.NoError(t, err)
+		peers[i], err = peer.IDFromPublicKey(pub)
+		require.NoError(t, err)
+	}
+	sort.Slice(peers, func(i, j int) bool { return peers[i] < peers[j] })
+	self, p1, p2, p3 := peers[0], peers[1], peers[2], peers[3]
+	opened := time.Now().UTC().Truncate(time.Second)
+	host.EXPECT().ID().Return(self)
+	host.EXPECT().Connections().Return([]p2p.Connection{
+		{Peer: p2, Address: "/ip4/10.0.0.2/tcp/7513", Opened: opened},
+		{Peer: p1, Address: "/ip4/10.0.0.1/tcp/7513", Outbound: true, Opened: opened},
+	})
+	host.EXPECT().RoutingTable().Return([]p2p.Peer{p3, p1})
+	host.EXPECT().Topics().Return([]string{"b", "a"})
+	host.EXPECT().ProtocolPeers("a").Return([]p2p.Peer{p2, p1})
+	host.EXPECT().MeshPeers("a").Return([]p2p.Peer{p1})
+	host.EXPECT().ProtocolPeers("b").Return(nil)
+	host.EXPECT().MeshPeers("b").Return(nil)
+
+	var rst NetworkTopology
+	require.Equal(t, http.StatusOK, getJSON(t, srv.URL+"/debug/network/topology", &rst))
+	require.Equal(t, NetworkTopology{
+		ID: self,
+		Connections: []p2p.Connection{
+			{Peer: p1, Address: "/ip4/10.0.0.1/tcp/7513", Outbound: true, Opened: opened},
+			{Peer: p2, Address: "/ip4/10.0.0.2/tcp/7513", Opened: opened},
+		},
+		RoutingTable: []p2p.Peer{p1, p3},
+		Topics: []TopicPeers{
+			{Topic: "a", Peers: []p2p.Peer{p1, p2}, Mesh: []p2p.Peer{p1}},
+			{Topic: "b", Peers: []p2p.Peer{}, Mesh: []p2p.Peer{}},
+		},
+	}, rst)
+
+	resp, err := http.Post(srv.URL+"/debug/network/topology", "application/json", nil)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
@@ -9,6 +9,7 @@ import (
 
 	pb "github.com/spacemeshos/api/release/go/spacemesh/v1"
 	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	rpcstatus "google.golang.org/genproto/googleapis/rpc/status"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -21,6 +22,7 @@ import (
 	"github.com/spacemeshos/go-spacemesh/p2p/pubsub"
 	"github.com/spacemeshos/go-spacemesh/sql"
 	"github.com/spacemeshos/go-spacemesh/sql/transactions"
+	"github.com/spacemeshos/go-spacemesh/txs"
 )
 
 // TransactionService exposes transaction data, and a submit tx endpoint.
@@ -92,7 +94,7 @@ func (s TransactionService) SubmitTransaction(ctx context.Context, in *pb.Submit
 	}
 
 	if err := s.txHandler.VerifyAndCacheTx(ctx, in.Transaction); err != nil {
-		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("Failed to verify transaction: %s", err.Error()))
+		return nil, rejectionError(err)
 	}
 
 	if err := s.publisher.Publish(ctx, pubsub.TxProtocol, in.Transaction); err != nil {
@@ -160,6 +162,17 @@ func (s TransactionService) TransactionsState(_ context.Context, in *pb.Transact
 	return res, nil
 }
 
+// toTransactionID converts the transaction id from the API.
+// Since the txid coming in from the API does not have a fixed length (see
+// https://github.com/spacemeshos/api/issues/130), we need to convert it from a slice to a fixed
+// size array before we can convert it into a TransactionID object. We don't need to worry about
+// error handling, since copy intelligently copies only what it can.
+func toTransactionID(id *pb.TransactionId) types.TransactionID {
+	var arrayID [32]byte
+	copy(arrayID[:], id.Id[:])
+	return types.TransactionID(arrayID)
+}
+
 // STREAMS
 
 // TransactionsStateStream exposes a stream of tx data.
@@ -233,15 +246,22 @@ func (s TransactionService) TransactionsStateStream(in *pb.TransactionsStateStre
 			// TODO: this is inefficient, come up with a more optimal way of doing this
 			// TODO: tx status should depend upon block status, not layer status
 
-			// In order to read transactions, we first need to read layer blocks
-			layerObj, err := s.mesh.GetLayer(layer.LayerID)
-			if err != nil {
-				s.logger.With().Error("error reading layer data for updated layer", layer.LayerID, log.Err(err))
-				return status.Error(codes.Internal, "error reading layer data")
+			var txstate pb.TransactionState_TransactionState
+			switch layer.Status {
+			case events.LayerStatusTypeApproved:
+				txstate = pb.TransactionState_TRANSACTION_STATE_MESH
+			case events.LayerStatusTypeConfirmed:
+				txstate = pb.TransactionState_TRANSACTION_STATE_PROCESSED
+			default:
+				txstate = pb.TransactionState_TRANSACTION_STATE_UNSPECIFIED
 			}
 
-			// Filter for any matching transactions in the reported layer
-			for _, b := range layerObj.Blocks() {
+			// In order to read transactions, we first need to read layer blocks.
+			// Collect the matching transactions in the reported layer, one block at a time. The transactions
+			// are read and sent after the statement is released, so that a slow subscriber doesn't hold
+			// the read connection.
+			var matched []*pb.TransactionId
+			if err := s.mesh.IterateLayerBlocks(layer.LayerID, func(b *types.Block) bool {
 				blockTXIDSet := make(map[types.TransactionID]struct{})
 
 				// create a set for the block transaction IDs
@@ -249,48 +269,36 @@ func (s TransactionService) TransactionsStateStream(in *pb.TransactionsStateStre
 					blockTXIDSet[txid] = struct{}{}
 				}
 
-				var txstate pb.TransactionState_TransactionState
-				switch layer.Status {
-				case events.LayerStatusTypeApproved:
-					txstate = pb.TransactionState_TRANSACTION_STATE_MESH
-				case events.LayerStatusTypeConfirmed:
-					txstate = pb.TransactionState_TRANSACTION_STATE_PROCESSED
-				default:
-					txstate = pb.TransactionState_TRANSACTION_STATE_UNSPECIFIED
-				}
-
 				for _, inputTxID := range in.TransactionId {
-					// Since the txid coming in from the API does not have a fixed length (see
-					// https://github.com/spacemeshos/api/issues/130), we need to convert it from a slice to a fixed
-					// size array before we can convert it into a TransactionID object. We don't need to worry about
-					// error handling, since copy intelligently copies only what it can. If the resulting TransactionID
-					// is invalid, an error will be thrown below.
-					var arrayID [32]byte
-					copy(arrayID[:], inputTxID.Id[:])
-					txid := types.TransactionID(arrayID)
 					// if there is an ID corresponding to inputTxID in the block
-					if _, exists := blockTXIDSet[txid]; exists {
-						res := &pb.TransactionsStateStreamResponse{
-							TransactionState: &pb.TransactionState{
-								Id:    inputTxID,
-								State: txstate,
-							},
-						}
-						if in.IncludeTransactions {
-							tx, err := s.conState.GetMeshTransaction(txid)
-							if err != nil {
-								s.logger.Error("could not find transaction %v from layer %v: %v", txid, layer, err)
-								return status.Error(codes.Internal, "error retrieving tx data")
-							}
-
-							res.Transaction = castTransaction(&tx.Transaction)
-						}
-
-						if err := stream.Send(res); err != nil {
-							return fmt.Errorf("send stream: %w", err)
-						}
+					if _, exists := blockTXIDSet[toTransactionID(inputTxID)]; exists {
+						matched = append(matched, inputTxID)
 					}
 				}
+				return true
+			}); err != nil {
+				s.logger.With().Error("error reading layer data for updated layer", layer.LayerID, log.Err(err))
+				return status.Error(codes.Internal, "error reading layer data")
+			}
+			for _, inputTxID := range matched {
+				res := &pb.TransactionsStateStreamResponse{
+					TransactionState: &pb.TransactionState{
+						Id:    inputTxID,
+						State: txstate,
+					},
+				}
+				if in.IncludeTransactions {
+					txid := toTransactionID(inputTxID)
+					tx, err := s.conState.GetMeshTransaction(txid)
+					if err != nil {
+						s.logger.Error("could not find transaction %v from layer %v: %v", txid, layer, err)
+						return status.Error(codes.Internal, "error retrieving tx data")
+					}
+					res.Transaction = castTransaction(&tx.Transaction)
+				}
+				if err := stream.Send(res); err != nil {
+					return fmt.Errorf("send stream: %w", err)
+				}
 			}
 		case <-stream.Context().Done():
 			return nil
@@ -434,3 +442,31 @@ func (m resultsMatcher) match(rst *types.TransactionWithResult) bool {
 	}
 	return true
 }
+
+// rejectionError converts the error of the transaction verification to the status error. if the transaction
+// was rejected with a known reason, the reason and its metadata are attached to the status as the error info.
+func rejectionError(err error) error {
+	msg := fmt.Sprintf("Failed to verify transaction: %s", err.Error())
+	var rerr *txs.RejectError
+	if !errors.As(err, &rerr) {
+		return status.Error(codes.InvalidArgument, msg)
+	}
+	statusCode := codes.InvalidArgument
+	switch rerr.Reason {
+	case txs.RejectDuplicate:
+		statusCode = codes.AlreadyExists
+	case txs.RejectBadNonce, txs.RejectInsufficientFunds:
+		statusCode = codes.FailedPrecondition
+	case txs.RejectTooManyPending:
+		statusCode = codes.ResourceExhausted
+	}
+	st, derr := status.New(statusCode, msg).WithDetails(&errdetails.ErrorInfo{
+		Reason:   string(rerr.Reason),
+		Domain:   ErrorDomain,
+		Metadata: rerr.Metadata,
+	})
+	if derr != nil {
+		return status.Error(statusCode, msg)
+	}
+	return st.Err()
+}
@@ -0,0 +1,136 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	pb "github.com/spacemeshos/api/release/go/spacemesh/v1"
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
+
+	"github.com/spacemeshos/go-spacemesh/events"
+	"github.com/spacemeshos/go-spacemesh/log"
+)
+
+// StreamPostSetup is the kind of the websocket frames with the post setup progress. the frames are not
+// part of the unified event stream, they are sent periodically if the smesher service is served by the gateway.
+const StreamPostSetup events.StreamKind = "post_setup"
+
+// wsWriteTimeout is the max time to write a single frame to the websocket connection.
+const wsWriteTimeout = 10 * time.Second
+
+// StreamPostSetupData is the data of the post setup progress frame.
+type StreamPostSetupData struct {
+	State            string `json:"state"`
+	NumLabelsWritten uint64 `json:"num_labels_written"`
+	NumUnits         uint32 `json:"num_units,omitempty"`
+}
+
+// websocketHandler bridges the unified event stream to the websocket connection for the clients that
+// can't use grpc streams, such as browser wallets. every event is sent as a json text frame in the same
+// format as in the events stream endpoint, and the cursor and kinds query parameters have the same meaning.
+// the post setup progress frames are also sent if the smesher service is enabled.
+func (s *JSONHTTPServer) websocketHandler(smesher *SmesherService) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		cursor, kinds, err := parseStreamQuery(r, StreamPostSetup)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var (
+			postSetup    = len(kinds) == 0 && smesher != nil
+			streamKinds  []events.StreamKind
+			streamEvents = len(kinds) == 0
+		)
+		for _, kind := range kinds {
+			if kind == StreamPostSetup {
+				if smesher == nil {
+					http.Error(w, "smesher service is not enabled", http.StatusBadRequest)
+					return
+				}
+				postSetup = true
+				continue
+			}
+			streamEvents = true
+			streamKinds = append(streamKinds, kind)
+		}
+
+		var (
+			sub    *events.BufferedSubscription[events.StreamEvent]
+			replay []events.StreamEvent
+			out    <-chan events.StreamEvent
+			full   <-chan struct{}
+		)
+		if streamEvents {
+			sub, replay, err = events.SubscribeStream(cursor, streamKinds)
+			switch {
+			case errors.Is(err, events.ErrCursorExpired):
+				http.Error(w, err.Error(), http.StatusGone)
+				return
+			case err != nil:
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			case sub == nil:
+				http.Error(w, "event reporter is not initialized", http.StatusServiceUnavailable)
+				return
+			}
+			defer sub.Close()
+			out, full = sub.Out(), sub.Full()
+		}
+
+		conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{OriginPatterns: s.origins})
+		if err != nil {
+			// the error response is written by accept
+			s.logger.With().Debug("failed to accept websocket connection", log.Err(err))
+			return
+		}
+		defer conn.Close(websocket.StatusInternalError, "")
+		// the client isn't expected to send anything, the context is canceled once it closes the connection
+		ctx := conn.CloseRead(r.Context())
+		write := func(ev events.StreamEvent) bool {
+			wctx, cancel := context.WithTimeout(ctx, wsWriteTimeout)
+			defer cancel()
+			return wsjson.Write(wctx, conn, ev) == nil
+		}
+		for _, ev := range replay {
+			if !write(ev) {
+				return
+			}
+		}
+		var ticks <-chan time.Time
+		if postSetup {
+			ticker := time.NewTicker(smesher.streamInterval)
+			defer ticker.Stop()
+			ticks = ticker.C
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-full:
+				// the client is too slow, it should reconnect with the cursor of the last received event
+				conn.Close(websocket.StatusPolicyViolation, "client is too slow, reconnect with the last cursor")
+				return
+			case ev := <-out:
+				if !write(ev) {
+					return
+				}
+			case <-ticks:
+				status := smesher.postSetupProvider.Status()
+				data := StreamPostSetupData{
+					State:            pb.PostSetupStatus_State(status.State).String(),
+					NumLabelsWritten: status.NumLabelsWritten,
+				}
+				if status.LastOpts != nil {
+					data.NumUnits = status.LastOpts.NumUnits
+				}
+				if !write(events.StreamEvent{Kind: StreamPostSetup, Data: data}) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package grpcserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/require"
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
+
+	"github.com/spacemeshos/go-spacemesh/activation"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/events"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+)
+
+type wsFrame struct {
+	Cursor uint64            `json:"cursor"`
+	Kind   events.StreamKind `json:"kind"`
+	Data   map[string]any    `json:"data"`
+}
+
+func newWebsocketServer(t *testing.T, smesher *SmesherService) string {
+	events.CloseEventReporter()
+	events.InitializeReporter()
+	t.Cleanup(events.CloseEventReporter)
+
+	s := NewJSONHTTPServer("", logtest.New(t))
+	mux := runtime.NewServeMux()
+	require.NoError(t, mux.HandlePath(http.MethodGet, "/v1/ws", s.websocketHandler(smesher)))
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return "ws" + strings.TrimPrefix(srv.URL, "http") + "/v1/ws"
+}
+
+func dialWebsocket(t *testing.T, url string) *websocket.Conn {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, _, err := websocket.Dial(ctx, url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close(websocket.StatusNormalClosure, "") })
+	return conn
+}
+
+func readFrame(t *testing.T, conn *websocket.Conn) wsFrame {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var frame wsFrame
+	require.NoError(t, wsjson.Read(ctx, conn, &frame))
+	return frame
+}
+
+func TestWebsocket_Events(t *testing.T) {
+	url := newWebsocketServer(t, nil)
+	events.ReportBlockApplied(types.LayerID(1), types.RandomBlockID())
+
+	// replays the buffered events and streams the new ones
+	conn := dialWebsocket(t, url+"?kinds=block")
+	frame := readFrame(t, conn)
+	require.Equal(t, uint64(1), frame.Cursor)
+	require.Equal(t, events.StreamBlock, frame.Kind)
+	require.EqualValues(t, 1, frame.Data["layer"])
+
+	events.ReportBlockApplied(types.LayerID(2), types.RandomBlockID())
+	frame = readFrame(t, conn)
+	require.Equal(t, uint64(2), frame.Cursor)
+	require.EqualValues(t, 2, frame.Data["layer"])
+
+	// resumes after the cursor
+	conn = dialWebsocket(t, url+"?cursor=1")
+	frame = readFrame(t, conn)
+	require.Equal(t, uint64(2), frame.Cursor)
+}
+
+func TestWebsocket_PostSetup(t *testing.T) {
+	post := NewMockpostSetupProvider(gomock.NewController(t))
+	post.EXPECT().Status().Return(&activation.PostSetupStatus{
+		State:            activation.PostSetupStateInProgress,
+		NumLabelsWritten: 100,
+		LastOpts:         &activation.PostSetupOpts{NumUnits: 4},
+	}).AnyTimes()
+	smesher := NewSmesherService(post, nil, 10*time.Millisecond, activation.PostSetupOpts{}, logtest.New(t))
+	url := newWebsocketServer(t, smesher)
+
+	conn := dialWebsocket(t, url+"?kinds="+string(StreamPostSetup))
+	frame := readFrame(t, conn)
+	require.Equal(t, StreamPostSetup, frame.Kind)
+	require.Equal(t, "STATE_IN_PROGRESS", frame.Data["state"])
+	require.EqualValues(t, 100, frame.Data["num_labels_written"])
+	require.EqualValues(t, 4, frame.Data["num_units"])
+}
+
+func TestWebsocket_InvalidRequest(t *testing.T) {
+	url := newWebsocketServer(t, nil)
+	for _, query := range []string{"?kinds=unknown", "?cursor=invalid", "?kinds=" + string(StreamPostSetup)} {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		_, resp, err := websocket.Dial(ctx, url+query, nil)
+		cancel()
+		require.Error(t, err, query)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+	}
+}
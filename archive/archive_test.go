@@ -0,0 +1,171 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/activation"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/p2p"
+	"github.com/spacemeshos/go-spacemesh/signing"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/atxs"
+	"github.com/spacemeshos/go-spacemesh/sql/ballots"
+	"github.com/spacemeshos/go-spacemesh/sql/blocks"
+	"github.com/spacemeshos/go-spacemesh/sql/certificates"
+	"github.com/spacemeshos/go-spacemesh/sql/poets"
+	"github.com/spacemeshos/go-spacemesh/sql/transactions"
+)
+
+func TestMain(m *testing.M) {
+	types.SetLayersPerEpoch(4)
+
+	res := m.Run()
+	os.Exit(res)
+}
+
+type testData struct {
+	poet   types.Hash32
+	atx    types.ATXID
+	tx     types.TransactionID
+	ballot types.BallotID
+	block  types.BlockID
+	layer  types.LayerID
+}
+
+func populate(t *testing.T, db *sql.Database) testData {
+	t.Helper()
+	signer, err := signing.NewEdSigner()
+	require.NoError(t, err)
+
+	ref := types.RandomHash()
+	require.NoError(t, poets.Add(db, types.PoetProofRef(ref), []byte("proof"), []byte("sid"), "rid"))
+
+	atx := &types.ActivationTx{
+		InnerActivationTx: types.InnerActivationTx{
+			NIPostChallenge: types.NIPostChallenge{PublishEpoch: 1},
+			NIPost: &types.NIPost{
+				Post:         &types.Post{},
+				PostMetadata: &types.PostMetadata{Challenge: ref[:]},
+			},
+			NumUnits: 2,
+		},
+	}
+	require.NoError(t, activation.SignAndFinalizeAtx(signer, atx))
+	atx.SetEffectiveNumUnits(atx.NumUnits)
+	atx.SetReceived(time.Now())
+	vatx, err := atx.Verify(0, 1)
+	require.NoError(t, err)
+	require.NoError(t, atxs.Add(db, vatx))
+
+	tx := &types.Transaction{}
+	tx.Raw = []byte{1, 2, 3}
+	tx.ID = types.TransactionID{1}
+	require.NoError(t, transactions.Add(db, tx, time.Now()))
+
+	lid := types.LayerID(11)
+	blt := types.RandomBallot()
+	blt.Layer = lid
+	blt.Signature = signer.Sign(signing.BALLOT, blt.SignedBytes())
+	blt.SmesherID = signer.NodeID()
+	require.NoError(t, blt.Initialize())
+	require.NoError(t, ballots.Add(db, blt))
+
+	block := &types.Block{InnerBlock: types.InnerBlock{LayerIndex: lid, TxIDs: []types.TransactionID{tx.ID}}}
+	block.Initialize()
+	require.NoError(t, blocks.Add(db, block))
+	require.NoError(t, certificates.Add(db, lid, &types.Certificate{BlockID: block.ID()}))
+
+	return testData{poet: ref, atx: atx.ID(), tx: tx.ID, ballot: blt.ID(), block: block.ID(), layer: lid}
+}
+
+type recorder struct {
+	imported []types.Hash32
+	certs    map[types.LayerID]types.BlockID
+}
+
+func (r *recorder) validator(kind string) Validator {
+	return func(_ context.Context, hash types.Hash32, peer p2p.Peer, data []byte) error {
+		if peer != p2p.NoPeer || len(data) == 0 {
+			return errors.New("unexpected " + kind)
+		}
+		r.imported = append(r.imported, hash)
+		return nil
+	}
+}
+
+func (r *recorder) validators() Validators {
+	r.certs = map[types.LayerID]types.BlockID{}
+	return Validators{
+		Poet:   r.validator("poet"),
+		Atx:    r.validator("atx"),
+		Tx:     r.validator("tx"),
+		Ballot: r.validator("ballot"),
+		Block:  r.validator("block"),
+		Certificate: func(_ context.Context, lid types.LayerID, cert *types.Certificate) error {
+			r.certs[lid] = cert.BlockID
+			return nil
+		},
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	for _, tc := range []struct {
+		desc string
+		name string
+	}{
+		{desc: "directory", name: "archive"},
+		{desc: "tar", name: "archive.tar"},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			db := sql.InMemory()
+			data := populate(t, db)
+			target := filepath.Join(t.TempDir(), tc.name)
+			require.NoError(t, Export(db, target, 1, 20))
+
+			r := &recorder{}
+			last, err := Import(context.Background(), logtest.New(t), target, r.validators())
+			require.NoError(t, err)
+			require.Equal(t, data.layer, last)
+			require.Equal(t, []types.Hash32{
+				data.poet,
+				data.atx.Hash32(),
+				data.tx.Hash32(),
+				data.ballot.AsHash32(),
+				data.block.AsHash32(),
+			}, r.imported)
+			require.Equal(t, map[types.LayerID]types.BlockID{data.layer: data.block}, r.certs)
+		})
+	}
+}
+
+func TestImport_InvalidObject(t *testing.T) {
+	db := sql.InMemory()
+	populate(t, db)
+	target := filepath.Join(t.TempDir(), "archive")
+	require.NoError(t, Export(db, target, 1, 20))
+
+	r := &recorder{}
+	v := r.validators()
+	errInvalid := errors.New("invalid")
+	v.Tx = func(context.Context, types.Hash32, p2p.Peer, []byte) error {
+		return errInvalid
+	}
+	_, err := Import(context.Background(), logtest.New(t), target, v)
+	require.ErrorIs(t, err, errInvalid)
+	require.Len(t, r.imported, 2)
+	require.Empty(t, r.certs)
+}
+
+func TestImport_Missing(t *testing.T) {
+	_, err := Import(context.Background(), logtest.New(t), filepath.Join(t.TempDir(), "missing"), Validators{})
+	require.Error(t, err)
+}
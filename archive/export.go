@@ -0,0 +1,260 @@
+// Package archive exports the mesh data of a node into a local directory or a tar archive,
+// and imports it into another node by validating every object as if it was received from the network.
+package archive
+
+import (
+	"archive/tar"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spacemeshos/go-spacemesh/codec"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/datastore"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/atxs"
+	"github.com/spacemeshos/go-spacemesh/sql/ballots"
+	"github.com/spacemeshos/go-spacemesh/sql/blocks"
+	"github.com/spacemeshos/go-spacemesh/sql/certificates"
+)
+
+// the archive is laid out in directories, one for every kind of objects. objects are stored
+// in files named by their hex encoded hash, ATXs are grouped by epoch and ballots and blocks by layer.
+// certificates are stored in files named by their layer.
+//
+//	poets/<hash>
+//	atxs/<epoch>/<hash>
+//	txs/<hash>
+//	ballots/<layer>/<hash>
+//	blocks/<layer>/<hash>
+//	certificates/<layer>
+const (
+	poetsDir        = "poets"
+	atxsDir         = "atxs"
+	txsDir          = "txs"
+	ballotsDir      = "ballots"
+	blocksDir       = "blocks"
+	certificatesDir = "certificates"
+
+	// tarExt is the extension of the path that is exported as a tar archive instead of a directory.
+	tarExt = ".tar"
+)
+
+// numName formats epochs and layers so that lexical order of the names matches the numeric order.
+func numName(n uint32) string {
+	return fmt.Sprintf("%010d", n)
+}
+
+func hashName(hash types.Hash32) string {
+	return hex.EncodeToString(hash[:])
+}
+
+type writer interface {
+	write(name string, data []byte) error
+	Close() error
+}
+
+type dirWriter struct {
+	dir string
+}
+
+func (w *dirWriter) write(name string, data []byte) error {
+	full := filepath.Join(w.dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(full), 0o700); err != nil {
+		return fmt.Errorf("create dir for %s: %w", name, err)
+	}
+	if err := os.WriteFile(full, data, 0o600); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+func (w *dirWriter) Close() error {
+	return nil
+}
+
+type tarWriter struct {
+	file *os.File
+	tw   *tar.Writer
+}
+
+func newTarWriter(file string) (*tarWriter, error) {
+	f, err := os.Create(file)
+	if err != nil {
+		return nil, fmt.Errorf("create archive: %w", err)
+	}
+	return &tarWriter{file: f, tw: tar.NewWriter(f)}, nil
+}
+
+func (w *tarWriter) write(name string, data []byte) error {
+	if err := w.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     int64(len(data)),
+		Mode:     0o600,
+		ModTime:  time.Now(),
+	}); err != nil {
+		return fmt.Errorf("write header %s: %w", name, err)
+	}
+	if _, err := w.tw.Write(data); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+func (w *tarWriter) Close() error {
+	if err := w.tw.Close(); err != nil {
+		w.file.Close()
+		return fmt.Errorf("close archive: %w", err)
+	}
+	return w.file.Close()
+}
+
+// Export writes ATXs up to the epoch of the last layer, and ballots, blocks and certificates
+// in the layers [from, to] together with the poet proofs and transactions they depend on.
+// the archive is written as a tar file if the path has .tar extension, and as a directory otherwise.
+func Export(db *sql.Database, target string, from, to types.LayerID) error {
+	var (
+		w   writer
+		err error
+	)
+	if strings.HasSuffix(target, tarExt) {
+		w, err = newTarWriter(target)
+		if err != nil {
+			return err
+		}
+	} else {
+		w = &dirWriter{dir: target}
+	}
+	if err := export(db, w, from, to); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+func export(db *sql.Database, w writer, from, to types.LayerID) error {
+	bs := datastore.NewBlobStore(db)
+	// poet proofs are exported first as ATXs can't be validated without them.
+	poets := map[types.Hash32]struct{}{}
+	if err := iterateAtxs(bs, to.GetEpoch(), func(epoch types.EpochID, id types.ATXID, blob []byte) error {
+		var atx types.ActivationTx
+		if err := codec.Decode(blob, &atx); err != nil {
+			return fmt.Errorf("decode atx %s: %w", id, err)
+		}
+		ref := atx.GetPoetProofRef()
+		if _, ok := poets[ref]; ok {
+			return nil
+		}
+		poets[ref] = struct{}{}
+		return exportBlob(bs, w, datastore.POETDB, path.Join(poetsDir, hashName(ref)), ref)
+	}); err != nil {
+		return err
+	}
+	if err := iterateAtxs(bs, to.GetEpoch(), func(epoch types.EpochID, id types.ATXID, blob []byte) error {
+		return w.write(path.Join(atxsDir, numName(epoch.Uint32()), hashName(id.Hash32())), blob)
+	}); err != nil {
+		return err
+	}
+	txs := map[types.TransactionID]struct{}{}
+	for lid := from; !lid.After(to); lid = lid.Add(1) {
+		blks, err := blocks.Layer(db, lid)
+		if err != nil {
+			return fmt.Errorf("blocks in layer %s: %w", lid, err)
+		}
+		for _, block := range blks {
+			for _, tid := range block.TxIDs {
+				if _, ok := txs[tid]; ok {
+					continue
+				}
+				txs[tid] = struct{}{}
+				if err := exportBlob(bs, w, datastore.TXDB, path.Join(txsDir, hashName(tid.Hash32())), tid.Hash32()); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for lid := from; !lid.After(to); lid = lid.Add(1) {
+		ids, err := ballots.IDsInLayer(db, lid)
+		if err != nil && !errors.Is(err, sql.ErrNotFound) {
+			return fmt.Errorf("ballots in layer %s: %w", lid, err)
+		}
+		for _, id := range ids {
+			name := path.Join(ballotsDir, numName(lid.Uint32()), hashName(id.AsHash32()))
+			if err := exportBlob(bs, w, datastore.BallotDB, name, id.AsHash32()); err != nil {
+				return err
+			}
+		}
+	}
+	for lid := from; !lid.After(to); lid = lid.Add(1) {
+		ids, err := blocks.IDsInLayer(db, lid)
+		if err != nil && !errors.Is(err, sql.ErrNotFound) {
+			return fmt.Errorf("blocks in layer %s: %w", lid, err)
+		}
+		for _, id := range ids {
+			name := path.Join(blocksDir, numName(lid.Uint32()), hashName(id.AsHash32()))
+			if err := exportBlob(bs, w, datastore.BlockDB, name, id.AsHash32()); err != nil {
+				return err
+			}
+		}
+	}
+	for lid := from; !lid.After(to); lid = lid.Add(1) {
+		certs, err := certificates.Get(db, lid)
+		if errors.Is(err, sql.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("certificates in layer %s: %w", lid, err)
+		}
+		for _, cert := range certs {
+			if !cert.Valid || cert.Cert == nil {
+				continue
+			}
+			data, err := codec.Encode(cert.Cert)
+			if err != nil {
+				return fmt.Errorf("encode certificate in layer %s: %w", lid, err)
+			}
+			if err := w.write(path.Join(certificatesDir, numName(lid.Uint32())), data); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	return nil
+}
+
+func exportBlob(bs *datastore.BlobStore, w writer, hint datastore.Hint, name string, hash types.Hash32) error {
+	blob, err := bs.Get(hint, hash.Bytes())
+	if err != nil {
+		return fmt.Errorf("get %s blob %s: %w", hint, hash, err)
+	}
+	return w.write(name, blob)
+}
+
+// iterateAtxs calls fn for every ATX published up to and including the epoch, except for checkpointed ATXs.
+func iterateAtxs(bs *datastore.BlobStore, last types.EpochID, fn func(types.EpochID, types.ATXID, []byte) error) error {
+	for epoch := types.EpochID(0); epoch <= last; epoch++ {
+		ids, err := atxs.GetIDsByEpoch(bs.DB, epoch)
+		if err != nil {
+			return fmt.Errorf("atxs in epoch %d: %w", epoch, err)
+		}
+		for _, id := range ids {
+			blob, err := bs.Get(datastore.ATXDB, id.Bytes())
+			if err != nil {
+				return fmt.Errorf("get atx blob %s: %w", id, err)
+			}
+			if len(blob) == 0 {
+				// checkpointed atxs are stored without the blob and can't be validated
+				continue
+			}
+			if err := fn(epoch, id, blob); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package archive
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spacemeshos/go-spacemesh/codec"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/p2p"
+)
+
+var errInvalidEntry = errors.New("invalid archive entry")
+
+// Validator validates and stores an object, the same as objects fetched from peers.
+type Validator func(context.Context, types.Hash32, p2p.Peer, []byte) error
+
+// Validators are used to import objects from the archive.
+type Validators struct {
+	Poet        Validator
+	Atx         Validator
+	Tx          Validator
+	Ballot      Validator
+	Block       Validator
+	Certificate func(context.Context, types.LayerID, *types.Certificate) error
+}
+
+// Import validates and stores objects from the archive at the path, which is either a directory
+// or a tar archive produced by Export. objects are imported in the order of their dependencies,
+// and the import stops at the first object that fails validation.
+// returns the last layer with ballots or blocks in the archive.
+func Import(ctx context.Context, logger log.Log, source string, v Validators) (types.LayerID, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return 0, fmt.Errorf("stat archive: %w", err)
+	}
+	dir := source
+	if !info.IsDir() {
+		dir, err = os.MkdirTemp("", "spacemesh-archive")
+		if err != nil {
+			return 0, fmt.Errorf("create temp dir: %w", err)
+		}
+		defer os.RemoveAll(dir)
+		if err := extract(source, dir); err != nil {
+			return 0, err
+		}
+	}
+	imp := &importer{logger: logger, dir: dir}
+	for _, kind := range []struct {
+		dir       string
+		validator Validator
+	}{
+		{poetsDir, v.Poet},
+		{atxsDir, v.Atx},
+		{txsDir, v.Tx},
+		{ballotsDir, v.Ballot},
+		{blocksDir, v.Block},
+	} {
+		if err := imp.importObjects(ctx, kind.dir, kind.validator); err != nil {
+			return 0, err
+		}
+	}
+	if err := imp.importCertificates(ctx, v.Certificate); err != nil {
+		return 0, err
+	}
+	return imp.last, nil
+}
+
+type importer struct {
+	logger log.Log
+	dir    string
+	last   types.LayerID
+}
+
+func (imp *importer) importObjects(ctx context.Context, kind string, validator Validator) error {
+	imported := 0
+	if err := imp.walk(kind, func(name string, data []byte) error {
+		hash, err := parseHash(path.Base(name))
+		if err != nil {
+			return err
+		}
+		if kind == ballotsDir || kind == blocksDir {
+			lid, err := parseLayer(path.Base(path.Dir(name)))
+			if err != nil {
+				return err
+			}
+			if lid.After(imp.last) {
+				imp.last = lid
+			}
+		}
+		if err := validator(ctx, hash, p2p.NoPeer, data); err != nil {
+			return fmt.Errorf("validate %s: %w", name, err)
+		}
+		imported++
+		return nil
+	}); err != nil {
+		return err
+	}
+	imp.logger.WithContext(ctx).With().Info("imported objects from archive",
+		log.String("kind", kind),
+		log.Int("count", imported),
+	)
+	return nil
+}
+
+func (imp *importer) importCertificates(ctx context.Context, handler func(context.Context, types.LayerID, *types.Certificate) error) error {
+	return imp.walk(certificatesDir, func(name string, data []byte) error {
+		lid, err := parseLayer(path.Base(name))
+		if err != nil {
+			return err
+		}
+		var cert types.Certificate
+		if err := codec.Decode(data, &cert); err != nil {
+			return fmt.Errorf("decode %s: %w", name, err)
+		}
+		if err := handler(ctx, lid, &cert); err != nil {
+			return fmt.Errorf("validate %s: %w", name, err)
+		}
+		return nil
+	})
+}
+
+// walk reads files in the directory of the kind in lexical order.
+func (imp *importer) walk(kind string, fn func(name string, data []byte) error) error {
+	root := filepath.Join(imp.dir, kind)
+	err := filepath.WalkDir(root, func(full string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(imp.dir, full)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(full)
+		if err != nil {
+			return fmt.Errorf("read %s: %w", rel, err)
+		}
+		return fn(filepath.ToSlash(rel), data)
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func parseHash(name string) (types.Hash32, error) {
+	var hash types.Hash32
+	decoded, err := hex.DecodeString(name)
+	if err != nil || len(decoded) != len(hash) {
+		return hash, fmt.Errorf("%w: %s is not a hash", errInvalidEntry, name)
+	}
+	copy(hash[:], decoded)
+	return hash, nil
+}
+
+func parseLayer(name string) (types.LayerID, error) {
+	lid, err := strconv.ParseUint(name, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s is not a layer", errInvalidEntry, name)
+	}
+	return types.LayerID(lid), nil
+}
+
+// extract unpacks regular files from the tar archive into the directory.
+func extract(source, dir string) error {
+	f, err := os.Open(source)
+	if err != nil {
+		return fmt.Errorf("open archive: %w", err)
+	}
+	defer f.Close()
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("read archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Clean(hdr.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return fmt.Errorf("%w: %s is outside of the archive", errInvalidEntry, hdr.Name)
+		}
+		full := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(full), 0o700); err != nil {
+			return fmt.Errorf("create dir for %s: %w", name, err)
+		}
+		out, err := os.OpenFile(full, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+		if err != nil {
+			return fmt.Errorf("create %s: %w", name, err)
+		}
+		_, err = io.Copy(out, tr)
+		out.Close()
+		if err != nil {
+			return fmt.Errorf("extract %s: %w", name, err)
+		}
+	}
+}
@@ -64,6 +64,14 @@ func WithCertConfig(cfg CertConfig) CertifierOpt {
 	}
 }
 
+// WithCommittee defines the committee size and the certify threshold in effect in the epoch.
+// CommitteeSize and CertifyThreshold of CertConfig are used if it is not set.
+func WithCommittee(committee func(types.EpochID) (size, threshold int)) CertifierOpt {
+	return func(c *Certifier) {
+		c.committeeParams = committee
+	}
+}
+
 // WithCertifierLogger defines logger for Certifier.
 func WithCertifierLogger(logger log.Log) CertifierOpt {
 	return func(c *Certifier) {
@@ -95,6 +103,8 @@ type Certifier struct {
 	layerClock layerClock
 	beacon     system.BeaconGetter
 	tortoise   system.Tortoise
+	// committeeParams returns the committee size and the certify threshold in the epoch.
+	committeeParams func(types.EpochID) (int, int)
 
 	mu          sync.Mutex
 	certifyMsgs map[types.LayerID]map[types.BlockID]*certInfo
@@ -196,13 +206,22 @@ func (c *Certifier) prune() {
 	}
 }
 
+// committee returns the committee size and the certify threshold in effect in the layer.
+func (c *Certifier) committee(lid types.LayerID) (int, int) {
+	if c.committeeParams == nil {
+		return c.cfg.CommitteeSize, c.cfg.CertifyThreshold
+	}
+	return c.committeeParams(lid.GetEpoch())
+}
+
 func (c *Certifier) createIfNeeded(lid types.LayerID, bid types.BlockID) {
 	if _, ok := c.certifyMsgs[lid]; !ok {
 		c.certifyMsgs[lid] = make(map[types.BlockID]*certInfo)
 	}
 	if _, ok := c.certifyMsgs[lid][bid]; !ok {
+		size, _ := c.committee(lid)
 		c.certifyMsgs[lid][bid] = &certInfo{
-			signatures: make([]types.CertifyMessage, 0, c.cfg.CommitteeSize),
+			signatures: make([]types.CertifyMessage, 0, size),
 		}
 	}
 }
@@ -232,7 +251,8 @@ func (c *Certifier) CertifyIfEligible(ctx context.Context, logger log.Log, lid t
 		return err
 	}
 
-	eligibilityCount, err := c.oracle.CalcEligibility(ctx, lid, eligibility.CertifyRound, c.cfg.CommitteeSize, c.nodeID, proof)
+	size, _ := c.committee(lid)
+	eligibilityCount, err := c.oracle.CalcEligibility(ctx, lid, eligibility.CertifyRound, size, c.nodeID, proof)
 	if err != nil {
 		return err
 	}
@@ -273,7 +293,7 @@ func (c *Certifier) NumCached() int {
 func (c *Certifier) HandleSyncedCertificate(ctx context.Context, lid types.LayerID, cert *types.Certificate) error {
 	logger := c.logger.WithContext(ctx).WithFields(lid, cert.BlockID)
 	logger.Debug("processing synced certificate")
-	if err := c.validateCert(ctx, logger, cert); err != nil {
+	if err := c.validateCert(ctx, logger, lid, cert); err != nil {
 		return err
 	}
 
@@ -285,7 +305,8 @@ func (c *Certifier) HandleSyncedCertificate(ctx context.Context, lid types.Layer
 	return nil
 }
 
-func (c *Certifier) validateCert(ctx context.Context, logger log.Log, cert *types.Certificate) error {
+func (c *Certifier) validateCert(ctx context.Context, logger log.Log, lid types.LayerID, cert *types.Certificate) error {
+	_, threshold := c.committee(lid)
 	eligibilityCnt := uint16(0)
 	for _, msg := range cert.Signatures {
 		if err := c.validate(ctx, logger, msg); err != nil {
@@ -293,10 +314,10 @@ func (c *Certifier) validateCert(ctx context.Context, logger log.Log, cert *type
 		}
 		eligibilityCnt += msg.EligibilityCnt
 	}
-	if int(eligibilityCnt) < c.cfg.CertifyThreshold {
+	if int(eligibilityCnt) < threshold {
 		logger.With().Warning("certificate not meeting threshold",
 			log.Int("num_msgs", len(cert.Signatures)),
-			log.Int("threshold", c.cfg.CertifyThreshold),
+			log.Int("threshold", threshold),
 			log.Uint16("eligibility_count", eligibilityCnt),
 		)
 		return errInvalidCert
@@ -377,7 +398,8 @@ func (c *Certifier) validate(ctx context.Context, logger log.Log, msg types.Cert
 	if !c.edVerifier.Verify(signing.HARE, msg.SmesherID, msg.Bytes(), msg.Signature) {
 		return fmt.Errorf("%w: failed to verify signature", errMalformedData)
 	}
-	valid, err := c.oracle.Validate(ctx, msg.LayerID, eligibility.CertifyRound, c.cfg.CommitteeSize, msg.SmesherID, msg.Proof, msg.EligibilityCnt)
+	size, _ := c.committee(msg.LayerID)
+	valid, err := c.oracle.Validate(ctx, msg.LayerID, eligibility.CertifyRound, size, msg.SmesherID, msg.Proof, msg.EligibilityCnt)
 	if err != nil {
 		logger.With().Warning("failed to validate cert msg", log.Err(err))
 		return err
@@ -418,8 +440,9 @@ func (c *Certifier) tryGenCert(ctx context.Context, logger log.Log, lid types.La
 		logger.Fatal("missing block in cache")
 	}
 
+	_, threshold := c.committee(lid)
 	if c.certifyMsgs[lid][bid].done ||
-		c.certifyMsgs[lid][bid].totalEligibility < uint16(c.cfg.CertifyThreshold) {
+		c.certifyMsgs[lid][bid].totalEligibility < uint16(threshold) {
 		return nil
 	}
 
@@ -463,7 +486,7 @@ func (c *Certifier) checkAndSave(ctx context.Context, logger log.Log, lid types.
 			invalid = append(invalid, old.Block)
 			continue
 		}
-		if err = c.validateCert(ctx, logger, old.Cert); err == nil {
+		if err = c.validateCert(ctx, logger, lid, old.Cert); err == nil {
 			logger.With().Warning("old cert still valid", log.Stringer("old_cert", old.Block))
 			valid = append(valid, old.Block)
 		} else {
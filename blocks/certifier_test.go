@@ -664,3 +664,29 @@ func Test_CertifyIfEligible_BeaconNotAvailable(t *testing.T) {
 	tc.mb.EXPECT().GetBeacon(b.LayerIndex.GetEpoch()).Return(types.EmptyBeacon, errors.New("meh"))
 	require.ErrorIs(t, tc.CertifyIfEligible(context.Background(), tc.logger, b.LayerIndex, b.ID()), errBeaconNotAvailable)
 }
+
+func Test_CertifierCommitteeByEpoch(t *testing.T) {
+	tc := newTestCertifier(t)
+	b := generateBlock(t, tc.db)
+	const size, threshold = 20, 4
+	WithCommittee(func(epoch types.EpochID) (int, int) {
+		require.Equal(t, b.LayerIndex.GetEpoch(), epoch)
+		return size, threshold
+	})(tc.Certifier)
+
+	tc.mb.EXPECT().GetBeacon(b.LayerIndex.GetEpoch()).Return(types.RandomBeacon(), nil)
+	proof := types.RandomVrfSignature()
+	tc.mOracle.EXPECT().Proof(gomock.Any(), b.LayerIndex, eligibility.CertifyRound).Return(proof, nil)
+	tc.mOracle.EXPECT().CalcEligibility(gomock.Any(), b.LayerIndex, eligibility.CertifyRound, size, tc.nodeID, proof).Return(uint16(0), nil)
+	require.NoError(t, tc.CertifyIfEligible(context.Background(), tc.logger, b.LayerIndex, b.ID()))
+
+	cert := &types.Certificate{BlockID: b.ID()}
+	for i := 0; i < threshold/int(defaultCnt); i++ {
+		nid, msg := genCertifyMsg(t, b.LayerIndex, b.ID(), defaultCnt)
+		tc.mOracle.EXPECT().Validate(gomock.Any(), b.LayerIndex, eligibility.CertifyRound, size, nid, msg.Proof, defaultCnt).
+			Return(true, nil)
+		cert.Signatures = append(cert.Signatures, *msg)
+	}
+	tc.mTortoise.EXPECT().OnHareOutput(b.LayerIndex, b.ID())
+	require.NoError(t, tc.HandleSyncedCertificate(context.Background(), b.LayerIndex, cert))
+}
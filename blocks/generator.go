@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"math"
 	"sync"
 	"time"
 
@@ -47,7 +46,6 @@ type Config struct {
 	LayerSize          uint32
 	LayersPerEpoch     uint32
 	GenBlockInterval   time.Duration
-	BlockGasLimit      uint64
 	OptFilterThreshold int
 }
 
@@ -56,7 +54,6 @@ func defaultConfig() Config {
 		LayerSize:          50,
 		LayersPerEpoch:     3,
 		GenBlockInterval:   time.Second,
-		BlockGasLimit:      math.MaxUint64,
 		OptFilterThreshold: 90,
 	}
 }
@@ -201,7 +198,9 @@ func (g *Generator) processHareOutput(out hare.LayerOutput) (*types.Block, error
 				failErrCnt.Inc()
 				return fmt.Errorf("preprocess get layer %d proposals: %w", out.Layer, err)
 			}
-			md, err = getProposalMetadata(out.Ctx, g.logger, g.cdb, g.cfg, out.Layer, props)
+			// the gas limit of the block is the one the vm applies to the layer
+			gasLimit := g.executor.GasSchedule(out.Layer).GasLimit
+			md, err = getProposalMetadata(out.Ctx, g.logger, g.cdb, g.cfg, out.Layer, gasLimit, props)
 			if err != nil {
 				return err
 			}
@@ -18,6 +18,7 @@ import (
 	"github.com/spacemeshos/go-spacemesh/blocks/mocks"
 	"github.com/spacemeshos/go-spacemesh/common/types"
 	"github.com/spacemeshos/go-spacemesh/datastore"
+	vm "github.com/spacemeshos/go-spacemesh/genvm"
 	"github.com/spacemeshos/go-spacemesh/genvm/sdk/wallet"
 	"github.com/spacemeshos/go-spacemesh/hare"
 	"github.com/spacemeshos/go-spacemesh/hare/eligibility"
@@ -47,7 +48,6 @@ func testConfig() Config {
 		LayerSize:          layerSize,
 		LayersPerEpoch:     epochSize,
 		GenBlockInterval:   10 * time.Millisecond,
-		BlockGasLimit:      math.MaxUint64,
 		OptFilterThreshold: 90,
 	}
 }
@@ -59,6 +59,7 @@ type testGenerator struct {
 	mockFetch  *smocks.MockProposalFetcher
 	mockCert   *mocks.Mockcertifier
 	mockPatrol *mocks.MocklayerPatrol
+	gasLimit   uint64
 }
 
 func createTestGenerator(t *testing.T) *testGenerator {
@@ -70,7 +71,11 @@ func createTestGenerator(t *testing.T) *testGenerator {
 		mockFetch:  smocks.NewMockProposalFetcher(ctrl),
 		mockCert:   mocks.NewMockcertifier(ctrl),
 		mockPatrol: mocks.NewMocklayerPatrol(ctrl),
+		gasLimit:   math.MaxUint64,
 	}
+	tg.mockExec.EXPECT().GasSchedule(gomock.Any()).DoAndReturn(func(types.LayerID) vm.GasSchedule {
+		return vm.GasSchedule{CostFactor: 100, GasLimit: tg.gasLimit}
+	}).AnyTimes()
 	lg := logtest.New(t)
 	cdb := datastore.NewCachedDB(sql.InMemory(), lg)
 	tg.Generator = NewGenerator(cdb, tg.mockExec, tg.mockMesh, tg.mockFetch, tg.mockCert, tg.mockPatrol,
@@ -330,7 +335,7 @@ func Test_run(t *testing.T) {
 		tc := tc
 		t.Run(tc.desc, func(t *testing.T) {
 			tg := createTestGenerator(t)
-			tg.cfg.BlockGasLimit = tc.gasLimit
+			tg.gasLimit = tc.gasLimit
 			layerID := types.GetEffectiveGenesis().Add(100)
 			require.NoError(t, layers.SetApplied(tg.cdb, layerID-1, types.EmptyBlockID))
 			var meshHash types.Hash32
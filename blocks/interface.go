@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/spacemeshos/go-spacemesh/common/types"
+	vm "github.com/spacemeshos/go-spacemesh/genvm"
 	"github.com/spacemeshos/go-spacemesh/log"
 )
 
@@ -20,6 +21,7 @@ type meshProvider interface {
 
 type executor interface {
 	ExecuteOptimistic(context.Context, types.LayerID, uint64, []types.AnyReward, []types.TransactionID) (*types.Block, error)
+	GasSchedule(types.LayerID) vm.GasSchedule
 }
 
 type layerClock interface {
@@ -10,6 +10,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	types "github.com/spacemeshos/go-spacemesh/common/types"
+	vm "github.com/spacemeshos/go-spacemesh/genvm"
 	log "github.com/spacemeshos/go-spacemesh/log"
 )
 
@@ -137,6 +138,20 @@ func (mr *MockexecutorMockRecorder) ExecuteOptimistic(arg0, arg1, arg2, arg3, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteOptimistic", reflect.TypeOf((*Mockexecutor)(nil).ExecuteOptimistic), arg0, arg1, arg2, arg3, arg4)
 }
 
+// GasSchedule mocks base method.
+func (m *Mockexecutor) GasSchedule(arg0 types.LayerID) vm.GasSchedule {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GasSchedule", arg0)
+	ret0, _ := ret[0].(vm.GasSchedule)
+	return ret0
+}
+
+// GasSchedule indicates an expected call of GasSchedule.
+func (mr *MockexecutorMockRecorder) GasSchedule(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GasSchedule", reflect.TypeOf((*Mockexecutor)(nil).GasSchedule), arg0)
+}
+
 // MocklayerClock is a mock of layerClock interface.
 type MocklayerClock struct {
 	ctrl     *gomock.Controller
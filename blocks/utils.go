@@ -52,6 +52,7 @@ func getProposalMetadata(
 	cdb *datastore.CachedDB,
 	cfg Config,
 	lid types.LayerID,
+	gasLimit uint64,
 	proposals []*types.Proposal,
 ) (*proposalMetadata, error) {
 	var (
@@ -123,9 +124,9 @@ func getProposalMetadata(
 		md.optFilter = true
 	}
 	if len(mtxs) > 0 {
-		var gasLimit uint64
-		if !md.optFilter {
-			gasLimit = cfg.BlockGasLimit
+		if md.optFilter {
+			// the transactions out of the gas limit are skipped by the vm
+			gasLimit = 0
 		}
 		blockSeed := types.CalcProposalsHash32(types.ToProposalIDs(md.proposals), nil).Bytes()
 		md.tids, err = getBlockTXs(logger, mtxs, blockSeed, gasLimit)
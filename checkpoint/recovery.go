@@ -21,6 +21,7 @@ import (
 	"github.com/spacemeshos/go-spacemesh/sql/atxs"
 	"github.com/spacemeshos/go-spacemesh/sql/poets"
 	"github.com/spacemeshos/go-spacemesh/sql/recovery"
+	"github.com/spacemeshos/go-spacemesh/sql/syncstate"
 )
 
 const recoveryDir = "recovery"
@@ -31,6 +32,10 @@ type Config struct {
 
 	// set to false if atxs are not compatible before and after the checkpoint recovery.
 	PreserveOwnAtx bool `mapstructure:"preserve-own-atx"`
+
+	// hex encoded public keys of the nodes trusted to sign checkpoints.
+	// if not empty, only checkpoints signed by one of them are recovered from.
+	TrustedSigners []string `mapstructure:"recovery-trusted-signers"`
 }
 
 func DefaultConfig() Config {
@@ -48,6 +53,8 @@ type RecoverConfig struct {
 	NodeID         types.NodeID
 	Uri            string
 	Restore        types.LayerID
+	GenesisID      types.Hash20
+	TrustedSigners []types.NodeID
 }
 
 func RecoveryDir(dataDir string) string {
@@ -143,6 +150,10 @@ func RecoverWithDb(
 type recoverydata struct {
 	accounts []*types.Account
 	atxs     []*atxs.CheckpointAtx
+	// layer and aggregatedHash are the trusted aggregated hash of the snapshot layer,
+	// aggregatedHash is empty if the checkpoint doesn't have it.
+	layer          types.LayerID
+	aggregatedHash types.Hash32
 }
 
 func recoverFromLocalFile(
@@ -155,7 +166,7 @@ func recoverFromLocalFile(
 ) (*PreservedData, error) {
 	logger.With().Info("recovering from checkpoint file", log.String("file", file))
 	newGenesis := cfg.Restore - 1
-	data, err := checkpointData(fs, file, newGenesis)
+	data, err := checkpointData(fs, file, cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -219,6 +230,11 @@ func recoverFromLocalFile(
 				catx.SmesherID,
 			)
 		}
+		if data.aggregatedHash != (types.Hash32{}) {
+			if err = syncstate.SetTrustedHash(tx, data.layer, data.aggregatedHash); err != nil {
+				return fmt.Errorf("save trusted hash: %w", err)
+			}
+		}
 		if err = recovery.SetCheckpoint(tx, cfg.Restore); err != nil {
 			return fmt.Errorf("save checkppoint info: %w", err)
 		}
@@ -241,7 +257,8 @@ func recoverFromLocalFile(
 	return preserve, nil
 }
 
-func checkpointData(fs afero.Fs, file string, newGenesis types.LayerID) (*recoverydata, error) {
+func checkpointData(fs afero.Fs, file string, cfg *RecoverConfig) (*recoverydata, error) {
+	newGenesis := cfg.Restore - 1
 	data, err := afero.ReadFile(fs, file)
 	if err != nil {
 		return nil, fmt.Errorf("%w: read recovery file %v", err, file)
@@ -256,6 +273,15 @@ func checkpointData(fs afero.Fs, file string, newGenesis types.LayerID) (*recove
 	if checkpoint.Version != SchemaVersion {
 		return nil, fmt.Errorf("expected version %v, got %v", SchemaVersion, checkpoint.Version)
 	}
+	if len(cfg.TrustedSigners) > 0 {
+		if err = verifySignature(&checkpoint, cfg.GenesisID, cfg.TrustedSigners); err != nil {
+			return nil, err
+		}
+	}
+	if checkpoint.Data.Layer != 0 && types.LayerID(checkpoint.Data.Layer).After(newGenesis) {
+		return nil, fmt.Errorf("checkpoint snapshot layer %d is after the new genesis %s",
+			checkpoint.Data.Layer, newGenesis)
+	}
 
 	allAccts := make([]*types.Account, 0, len(checkpoint.Data.Accounts))
 	for _, acct := range checkpoint.Data.Accounts {
@@ -288,10 +314,19 @@ func checkpointData(fs afero.Fs, file string, newGenesis types.LayerID) (*recove
 		copy(catx.Coinbase[:], atx.Coinbase)
 		allAtxs = append(allAtxs, &catx)
 	}
-	return &recoverydata{
+	rst := &recoverydata{
 		accounts: allAccts,
 		atxs:     allAtxs,
-	}, nil
+	}
+	if len(checkpoint.Data.AggregatedHash) > 0 {
+		if len(checkpoint.Data.AggregatedHash) != types.Hash32Length || checkpoint.Data.Layer == 0 {
+			return nil, fmt.Errorf("invalid aggregated hash %x for snapshot layer %d",
+				checkpoint.Data.AggregatedHash, checkpoint.Data.Layer)
+		}
+		rst.layer = types.LayerID(checkpoint.Data.Layer)
+		rst.aggregatedHash = types.BytesToHash(checkpoint.Data.AggregatedHash)
+	}
+	return rst, nil
 }
 
 func collectOwnAtxDeps(
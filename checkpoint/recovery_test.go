@@ -32,6 +32,7 @@ import (
 	"github.com/spacemeshos/go-spacemesh/sql/atxs"
 	"github.com/spacemeshos/go-spacemesh/sql/poets"
 	"github.com/spacemeshos/go-spacemesh/sql/recovery"
+	"github.com/spacemeshos/go-spacemesh/sql/syncstate"
 	smocks "github.com/spacemeshos/go-spacemesh/system/mocks"
 )
 
@@ -179,6 +180,125 @@ func TestRecover(t *testing.T) {
 	}
 }
 
+func TestRecover_TrustedSigners(t *testing.T) {
+	signer, err := signing.NewEdSigner(signing.WithPrefix(types.Hash20{1}.Bytes()))
+	require.NoError(t, err)
+	other, err := signing.NewEdSigner(signing.WithPrefix(types.Hash20{1}.Bytes()))
+	require.NoError(t, err)
+
+	encode := func(tb testing.TB, modify func(*types.Checkpoint)) []byte {
+		var cp types.Checkpoint
+		require.NoError(tb, json.Unmarshal([]byte(checkpointdata), &cp))
+		modify(&cp)
+		buf, err := json.Marshal(&cp)
+		require.NoError(tb, err)
+		return buf
+	}
+	tt := []struct {
+		name   string
+		data   []byte
+		expErr error
+	}{
+		{
+			name: "trusted",
+			data: encode(t, func(cp *types.Checkpoint) {
+				require.NoError(t, checkpoint.Sign(cp, signer))
+			}),
+		},
+		{
+			name:   "unsigned",
+			data:   []byte(checkpointdata),
+			expErr: checkpoint.ErrUntrustedCheckpoint,
+		},
+		{
+			name: "untrusted",
+			data: encode(t, func(cp *types.Checkpoint) {
+				require.NoError(t, checkpoint.Sign(cp, other))
+			}),
+			expErr: checkpoint.ErrUntrustedCheckpoint,
+		},
+		{
+			name: "modified",
+			data: encode(t, func(cp *types.Checkpoint) {
+				require.NoError(t, checkpoint.Sign(cp, signer))
+				cp.Data.Accounts[0].Balance++
+			}),
+			expErr: checkpoint.ErrUntrustedCheckpoint,
+		},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				_, err := w.Write(tc.data)
+				require.NoError(t, err)
+			}))
+			defer ts.Close()
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			cfg := &checkpoint.RecoverConfig{
+				GoldenAtx:      goldenAtx,
+				PostDataDir:    t.TempDir(),
+				DataDir:        t.TempDir(),
+				DbFile:         "test.sql",
+				NodeID:         types.NodeID{2, 3, 4},
+				Uri:            fmt.Sprintf("%s/snapshot-15", ts.URL),
+				Restore:        types.LayerID(recoverLayer),
+				GenesisID:      types.Hash20{1},
+				TrustedSigners: []types.NodeID{signer.NodeID()},
+			}
+			db := sql.InMemory()
+			_, err := checkpoint.RecoverWithDb(ctx, logtest.New(t), db, afero.NewMemMapFs(), cfg)
+			if tc.expErr != nil {
+				require.ErrorIs(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			newdb, err := sql.Open("file:" + filepath.Join(cfg.DataDir, cfg.DbFile))
+			require.NoError(t, err)
+			defer newdb.Close()
+			verifyDbContent(t, newdb)
+		})
+	}
+}
+
+func TestRecover_TrustedHash(t *testing.T) {
+	var cp types.Checkpoint
+	require.NoError(t, json.Unmarshal([]byte(checkpointdata), &cp))
+	snapshot := types.LayerID(recoverLayer - 3)
+	aggHash := types.RandomHash()
+	cp.Data.Layer = snapshot.Uint32()
+	cp.Data.AggregatedHash = aggHash.Bytes()
+	data, err := json.Marshal(&cp)
+	require.NoError(t, err)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write(data)
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	cfg := &checkpoint.RecoverConfig{
+		GoldenAtx:   goldenAtx,
+		PostDataDir: t.TempDir(),
+		DataDir:     t.TempDir(),
+		DbFile:      "test.sql",
+		NodeID:      types.NodeID{2, 3, 4},
+		Uri:         fmt.Sprintf("%s/snapshot-15", ts.URL),
+		Restore:     types.LayerID(recoverLayer),
+	}
+	_, err = checkpoint.RecoverWithDb(context.Background(), logtest.New(t), sql.InMemory(), afero.NewMemMapFs(), cfg)
+	require.NoError(t, err)
+	newdb, err := sql.Open("file:" + filepath.Join(cfg.DataDir, cfg.DbFile))
+	require.NoError(t, err)
+	defer newdb.Close()
+	got, err := syncstate.TrustedHash(newdb, snapshot)
+	require.NoError(t, err)
+	require.Equal(t, aggHash, got)
+}
+
 func TestRecover_SameRecoveryInfo(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		require.Equal(t, http.MethodGet, r.Method)
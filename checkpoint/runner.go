@@ -3,17 +3,21 @@ package checkpoint
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"path/filepath"
 
 	pb "github.com/spacemeshos/api/release/go/spacemesh/v1"
 	"github.com/spf13/afero"
 
 	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/signing"
 	"github.com/spacemeshos/go-spacemesh/sql"
 	"github.com/spacemeshos/go-spacemesh/sql/accounts"
 	"github.com/spacemeshos/go-spacemesh/sql/atxs"
 	"github.com/spacemeshos/go-spacemesh/sql/identities"
+	"github.com/spacemeshos/go-spacemesh/sql/layers"
 )
 
 const (
@@ -91,6 +95,13 @@ func checkpointDB(ctx context.Context, db *sql.Database, snapshot types.LayerID,
 		})
 	}
 
+	checkpoint.Data.Layer = snapshot.Uint32()
+	aggHash, err := layers.GetAggregatedHash(tx, snapshot)
+	if err == nil && aggHash != (types.Hash32{}) {
+		checkpoint.Data.AggregatedHash = aggHash.Bytes()
+	} else if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return nil, fmt.Errorf("aggregated hash: %w", err)
+	}
 	acctSnapshot, err := accounts.Snapshot(tx, snapshot)
 	if err != nil {
 		return nil, fmt.Errorf("accounts snapshot: %w", err)
@@ -112,18 +123,49 @@ func checkpointDB(ctx context.Context, db *sql.Database, snapshot types.LayerID,
 	return checkpoint, nil
 }
 
-func Generate(ctx context.Context, fs afero.Fs, db *sql.Database, dataDir string, snapshot types.LayerID, numAtxs int) error {
+// ExportSnapshot writes checkpoint with the state at the snapshot layer to w.
+// The checkpoint is signed if signer is not nil. Output is consumed by checkpoint recovery.
+func ExportSnapshot(
+	ctx context.Context,
+	db *sql.Database,
+	snapshot types.LayerID,
+	numAtxs int,
+	signer *signing.EdSigner,
+	w io.Writer,
+) error {
 	checkpoint, err := checkpointDB(ctx, db, snapshot, numAtxs)
 	if err != nil {
 		return err
 	}
+	if signer != nil {
+		if err = Sign(checkpoint, signer); err != nil {
+			return err
+		}
+	}
+	if err := json.NewEncoder(w).Encode(checkpoint); err != nil {
+		return fmt.Errorf("marshal checkpoint json: %w", err)
+	}
+	return nil
+}
+
+// Generate persists checkpoint at the snapshot layer in the data directory.
+// The checkpoint is signed if signer is not nil.
+func Generate(
+	ctx context.Context,
+	fs afero.Fs,
+	db *sql.Database,
+	dataDir string,
+	snapshot types.LayerID,
+	numAtxs int,
+	signer *signing.EdSigner,
+) error {
 	rf, err := NewRecoveryFile(fs, SelfCheckpointFilename(dataDir, snapshot))
 	if err != nil {
 		return fmt.Errorf("new recovery file: %w", err)
 	}
 	// one writer persist the checkpoint data, one returning result to caller.
-	if err = json.NewEncoder(rf.fwriter).Encode(checkpoint); err != nil {
-		return fmt.Errorf("marshal checkpoint json: %w", err)
+	if err = ExportSnapshot(ctx, db, snapshot, numAtxs, signer, rf.fwriter); err != nil {
+		return err
 	}
 	if err = rf.Save(fs); err != nil {
 		return err
@@ -17,6 +17,7 @@ import (
 
 	"github.com/spacemeshos/go-spacemesh/checkpoint"
 	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/signing"
 	"github.com/spacemeshos/go-spacemesh/sql"
 	"github.com/spacemeshos/go-spacemesh/sql/accounts"
 	"github.com/spacemeshos/go-spacemesh/sql/atxs"
@@ -83,6 +84,7 @@ func expectedCheckpoint(t *testing.T, snapshot types.LayerID, numAtxs int) *type
 		Version: "https://spacemesh.io/checkpoint.schema.json.1.0",
 		Data: types.InnerData{
 			CheckpointId: "snapshot-5",
+			Layer:        snapshot.Uint32(),
 		},
 	}
 
@@ -244,7 +246,7 @@ func TestRunner_Generate(t *testing.T) {
 			require.NoError(t, err)
 			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 			defer cancel()
-			err = checkpoint.Generate(ctx, fs, db, dir, snapshot, tc.numAtxs)
+			err = checkpoint.Generate(ctx, fs, db, dir, snapshot, tc.numAtxs, nil)
 			if tc.fail {
 				require.Error(t, err)
 				return
@@ -266,6 +268,26 @@ func TestRunner_Generate(t *testing.T) {
 	}
 }
 
+func TestRunner_ExportSnapshot(t *testing.T) {
+	db := sql.InMemory()
+	snapshot := types.LayerID(5)
+	createMesh(t, db, allAtxs, allAccounts)
+	signer, err := signing.NewEdSigner()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, checkpoint.ExportSnapshot(context.Background(), db, snapshot, 2, signer, &buf))
+	require.NoError(t, checkpoint.ValidateSchema(buf.Bytes()))
+	var got types.Checkpoint
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+
+	require.Equal(t, snapshot.Uint32(), got.Data.Layer)
+	require.NotEmpty(t, got.Data.Atxs)
+	require.NotEmpty(t, got.Data.Accounts)
+	require.Equal(t, signer.NodeID().Bytes(), got.Signer)
+	require.NotEmpty(t, got.Signature)
+}
+
 func TestRunner_Generate_Error(t *testing.T) {
 	const numEpochs = 2
 
@@ -303,7 +325,7 @@ func TestRunner_Generate_Error(t *testing.T) {
 			require.NoError(t, err)
 			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 			defer cancel()
-			err = checkpoint.Generate(ctx, fs, db, dir, snapshot, numEpochs)
+			err = checkpoint.Generate(ctx, fs, db, dir, snapshot, numEpochs, nil)
 			if tc.missingCommitment {
 				require.ErrorContains(t, err, "atxs snapshot commitment")
 			} else if tc.missingVrf {
@@ -0,0 +1,56 @@
+package checkpoint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/signing"
+)
+
+var ErrUntrustedCheckpoint = errors.New("checkpoint is not signed by a trusted signer")
+
+// Sign signs the checkpoint data with the signer.
+func Sign(checkpoint *types.Checkpoint, signer *signing.EdSigner) error {
+	msg, err := json.Marshal(&checkpoint.Data)
+	if err != nil {
+		return fmt.Errorf("marshal checkpoint data: %w", err)
+	}
+	checkpoint.Signer = signer.NodeID().Bytes()
+	sig := signer.Sign(signing.CHECKPOINT, msg)
+	checkpoint.Signature = sig[:]
+	return nil
+}
+
+// verifySignature checks that the checkpoint data is signed by one of the trusted signers.
+func verifySignature(checkpoint *types.Checkpoint, genesisID types.Hash20, trusted []types.NodeID) error {
+	if len(checkpoint.Signer) != types.NodeIDSize || len(checkpoint.Signature) != types.EdSignatureSize {
+		return fmt.Errorf("%w: missing signature", ErrUntrustedCheckpoint)
+	}
+	signer := types.BytesToNodeID(checkpoint.Signer)
+	found := false
+	for _, id := range trusted {
+		if id == signer {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("%w: unknown signer %s", ErrUntrustedCheckpoint, signer)
+	}
+	verifier, err := signing.NewEdVerifier(signing.WithVerifierPrefix(genesisID.Bytes()))
+	if err != nil {
+		return fmt.Errorf("create verifier: %w", err)
+	}
+	msg, err := json.Marshal(&checkpoint.Data)
+	if err != nil {
+		return fmt.Errorf("marshal checkpoint data: %w", err)
+	}
+	var sig types.EdSignature
+	copy(sig[:], checkpoint.Signature)
+	if !verifier.Verify(signing.CHECKPOINT, signer, msg, sig) {
+		return fmt.Errorf("%w: invalid signature from %s", ErrUntrustedCheckpoint, signer)
+	}
+	return nil
+}
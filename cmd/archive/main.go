@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/spacemeshos/go-spacemesh/archive"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/sql"
+)
+
+var (
+	db             = flag.String("db", "", "path to the state database of the node")
+	out            = flag.String("out", "", "directory or .tar file to write the archive to")
+	layersPerEpoch = flag.Uint("layers-per-epoch", 4032, "number of layers in the epoch")
+	from           = flag.Uint("from", 0, "first layer to export")
+	to             = flag.Uint("to", 0, "last layer to export")
+)
+
+func main() {
+	flag.Parse()
+	if *db == "" || *out == "" {
+		fmt.Println("both -db and -out must be set")
+		os.Exit(1)
+	}
+	types.SetLayersPerEpoch(uint32(*layersPerEpoch))
+
+	sqlDB, err := sql.Open("file:" + *db)
+	if err != nil {
+		fmt.Printf("failed to open database: %s\n", err)
+		os.Exit(1)
+	}
+	defer sqlDB.Close()
+	if err := archive.Export(sqlDB, *out, types.LayerID(*from), types.LayerID(*to)); err != nil {
+		fmt.Printf("failed to export archive: %s\n", err)
+		os.Exit(1)
+	}
+}
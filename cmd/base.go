@@ -24,6 +24,9 @@ var (
 
 	// Commit is the git commit used to build the app. Designed to be overwritten by make.
 	Commit string
+
+	// BuildTime is the time when the app was built. Designed to be overwritten by make.
+	BuildTime string
 )
 
 // EnsureCLIFlags checks flag types and converts them.
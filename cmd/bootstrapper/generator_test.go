@@ -20,6 +20,7 @@ import (
 	"github.com/spacemeshos/go-spacemesh/common/types"
 	"github.com/spacemeshos/go-spacemesh/datastore"
 	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/mesh"
 	"github.com/spacemeshos/go-spacemesh/sql"
 	"github.com/spacemeshos/go-spacemesh/sql/atxs"
 )
@@ -107,8 +108,25 @@ type MeshAPIMock struct{}
 func (m *MeshAPIMock) LatestLayer() types.LayerID                        { panic("not implemented") }
 func (m *MeshAPIMock) LatestLayerInState() types.LayerID                 { panic("not implemented") }
 func (m *MeshAPIMock) ProcessedLayer() types.LayerID                     { panic("not implemented") }
+func (m *MeshAPIMock) MeshStatus() mesh.Status                           { panic("not implemented") }
+func (m *MeshAPIMock) VerificationStatus() mesh.VerificationStatus       { panic("not implemented") }
 func (m *MeshAPIMock) GetRewards(types.Address) ([]*types.Reward, error) { panic("not implemented") }
 func (m *MeshAPIMock) GetLayer(types.LayerID) (*types.Layer, error)      { panic("not implemented") }
+func (m *MeshAPIMock) IterateLayerBlocks(types.LayerID, func(*types.Block) bool) error {
+	panic("not implemented")
+}
+func (m *MeshAPIMock) IterateLayerBallots(types.LayerID, func(*types.Ballot) bool) error {
+	panic("not implemented")
+}
+func (m *MeshAPIMock) GetSmesherRewards(types.NodeID, int, int) ([]*types.Reward, int, error) {
+	panic("not implemented")
+}
+func (m *MeshAPIMock) BallotsBySmesher(types.NodeID, types.EpochID) ([]*types.Ballot, error) {
+	panic("not implemented")
+}
+func (m *MeshAPIMock) BlocksBySmesher(types.NodeID, types.EpochID) ([]types.BlockID, error) {
+	panic("not implemented")
+}
 func (m *MeshAPIMock) GetATXs(context.Context, []types.ATXID) (map[types.ATXID]*types.VerifiedActivationTx, []types.ATXID) {
 	panic("not implemented")
 }
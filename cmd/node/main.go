@@ -12,15 +12,17 @@ import (
 )
 
 var (
-	version string
-	commit  string
-	branch  string
+	version   string
+	commit    string
+	branch    string
+	buildTime string
 )
 
 func main() { // run the app
 	cmd.Version = version
 	cmd.Commit = commit
 	cmd.Branch = branch
+	cmd.BuildTime = buildTime
 	if err := node.GetCommand().Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
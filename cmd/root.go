@@ -28,6 +28,8 @@ func AddCommands(cmd *cobra.Command) {
 		"recovery-uri", cfg.Recovery.Uri, "reset the node state based on the supplied checkpoint file")
 	cmd.PersistentFlags().Uint32Var(&cfg.Recovery.Restore,
 		"recovery-layer", cfg.Recovery.Restore, "restart the mesh with the checkpoint file at this layer")
+	cmd.PersistentFlags().StringSliceVar(&cfg.Recovery.TrustedSigners,
+		"recovery-trusted-signers", cfg.Recovery.TrustedSigners, "only recover from checkpoint files signed by one of these hex encoded public keys")
 
 	/** ======================== BaseConfig Flags ========================== **/
 	cmd.PersistentFlags().StringVarP(&cfg.BaseConfig.ConfigFile,
@@ -36,6 +38,8 @@ func AddCommands(cmd *cobra.Command) {
 		cfg.BaseConfig.DataDirParent, "Specify data directory for spacemesh")
 	cmd.PersistentFlags().StringVar(&cfg.BaseConfig.FileLock,
 		"filelock", cfg.BaseConfig.FileLock, "Filesystem lock to prevent running more than one instance.")
+	cmd.PersistentFlags().StringVar(&cfg.BaseConfig.ImportArchive,
+		"import-archive", cfg.BaseConfig.ImportArchive, "import mesh data from a directory or a tar archive exported by another node")
 	cmd.PersistentFlags().StringVar(&cfg.LOGGING.Encoder, "log-encoder",
 		cfg.LOGGING.Encoder, "Log as JSON instead of plain text")
 	cmd.PersistentFlags().BoolVar(&cfg.CollectMetrics, "metrics",
@@ -52,6 +56,14 @@ func AddCommands(cmd *cobra.Command) {
 		cfg.Genesis.GenesisTime, "Time of the genesis layer in 2019-13-02T17:02:00+00:00 format")
 	cmd.PersistentFlags().StringVar(&cfg.Genesis.ExtraData, "genesis-extra-data",
 		cfg.Genesis.ExtraData, "genesis extra-data will be committed to the genesis id")
+	cmd.PersistentFlags().Uint32Var(&cfg.Genesis.EffectiveGenesis, "genesis-effective-layer",
+		cfg.Genesis.EffectiveGenesis, "last genesis layer, must be the last layer in the epoch. 0 uses two genesis epochs")
+	cmd.PersistentFlags().StringVar(&cfg.Genesis.InitialBeacon, "genesis-initial-beacon",
+		cfg.Genesis.InitialBeacon, "hex encoded beacon for the first epoch after genesis")
+	cmd.PersistentFlags().StringVar(&cfg.Genesis.AccountsFile, "genesis-accounts-file",
+		cfg.Genesis.AccountsFile, "json file with the genesis accounts and vaults, replaces the configured accounts")
+	cmd.PersistentFlags().StringVar(&cfg.Genesis.AccountsHash, "genesis-accounts-hash",
+		cfg.Genesis.AccountsHash, "hex encoded hash of the genesis accounts file")
 	cmd.PersistentFlags().DurationVar(&cfg.LayerDuration, "layer-duration",
 		cfg.LayerDuration, "Duration between layers")
 	cmd.PersistentFlags().Uint32Var(&cfg.LayerAvgSize, "layer-average-size",
@@ -66,8 +78,30 @@ func AddCommands(cmd *cobra.Command) {
 
 	cmd.PersistentFlags().IntVar(&cfg.TxsPerProposal, "txs-per-proposal",
 		cfg.TxsPerProposal, "the number of transactions to select per proposal")
+	cmd.PersistentFlags().DurationVar(&cfg.LateProposalGrace, "late-proposal-grace",
+		cfg.LateProposalGrace, "how long after the end of the layer gossiped proposals are still accepted, zero disables the check")
 	cmd.PersistentFlags().Uint64Var(&cfg.BlockGasLimit, "block-gas-limit",
 		cfg.BlockGasLimit, "max gas allowed per block")
+	cmd.PersistentFlags().Uint64Var(&cfg.ReplaceFeeBump, "tx-replace-fee-bump",
+		cfg.ReplaceFeeBump, "min fee increase in percentage for a transaction to replace the pending one with the same nonce")
+	cmd.PersistentFlags().IntVar(&cfg.MempoolMaxTXs, "mempool-max-txs",
+		cfg.MempoolMaxTXs, "max number of transactions in the mempool, zero disables the limit")
+	cmd.PersistentFlags().DurationVar(&cfg.MempoolMaxAge, "mempool-max-age",
+		cfg.MempoolMaxAge, "max time a transaction stays in the mempool, zero disables the expiry")
+	cmd.PersistentFlags().IntVar(&cfg.MempoolMaxBytes, "mempool-max-bytes",
+		cfg.MempoolMaxBytes, "max total size in bytes of transactions in the mempool, zero disables the limit")
+	cmd.PersistentFlags().IntVar(&cfg.MempoolMaxAcctTXs, "mempool-max-account-txs",
+		cfg.MempoolMaxAcctTXs, "max number of pending transactions of a single account in the mempool")
+	cmd.PersistentFlags().IntVar(&cfg.MempoolMaxOrphans, "mempool-max-orphans",
+		cfg.MempoolMaxOrphans, "max number of transactions held until the gap before their nonces is filled")
+	cmd.PersistentFlags().Uint32Var(&cfg.TxRebroadcastInterval, "tx-rebroadcast-interval",
+		cfg.TxRebroadcastInterval, "number of layers after which unconfirmed transactions submitted via api are gossiped again, zero disables the rebroadcast")
+	cmd.PersistentFlags().IntVar(&cfg.TxRebroadcastRetries, "tx-rebroadcast-retries",
+		cfg.TxRebroadcastRetries, "max number of times a transaction submitted via api is gossiped again")
+	cmd.PersistentFlags().Uint32Var(&cfg.StateRetention, "state-retention-layers",
+		cfg.StateRetention, "number of layers for which historical account states are kept, zero keeps the full history")
+	cmd.PersistentFlags().IntVar(&cfg.ApplyWorkers, "apply-workers",
+		cfg.ApplyWorkers, "number of transactions of the layer executed concurrently, zero uses all cpus")
 	cmd.PersistentFlags().IntVar(&cfg.OptFilterThreshold, "optimistic-filtering-threshold",
 		cfg.OptFilterThreshold, "threshold for optimistic filtering in percentage")
 
@@ -144,16 +178,52 @@ func AddCommands(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringVar(&cfg.API.PrivateListener, "grpc-private-listener",
 		cfg.API.PrivateListener, "Socket for the list of services specified in grpc-private-services.")
 	cmd.PersistentFlags().IntVar(&cfg.API.GrpcRecvMsgSize, "grpc-recv-msg-size",
-		cfg.API.GrpcRecvMsgSize, "GRPC api recv message size")
+		cfg.API.GrpcRecvMsgSize, "GRPC api recv message size in bytes, also limits the request body of the grpc gateway")
 	cmd.PersistentFlags().IntVar(&cfg.API.GrpcSendMsgSize, "grpc-send-msg-size",
-		cfg.API.GrpcSendMsgSize, "GRPC api send message size")
+		cfg.API.GrpcSendMsgSize, "GRPC api send message size in bytes")
+	cmd.PersistentFlags().IntVar(&cfg.API.CompressionLevel, "grpc-compression-level",
+		cfg.API.CompressionLevel, "Gzip level of the api responses for the clients that negotiate compression, from -1 (default) to 9.")
+	cmd.PersistentFlags().StringSliceVar(&cfg.API.WebsocketOrigins, "grpc-ws-origins",
+		cfg.API.WebsocketOrigins, "Origins of the browser pages allowed to connect to the websocket bridge on the json listener.")
+	cmd.PersistentFlags().Uint32Var(&cfg.API.MaxConcurrentStreams, "grpc-max-concurrent-streams",
+		cfg.API.MaxConcurrentStreams, "Max number of concurrent streams of a single grpc connection. If set to 0 - streams are not limited.")
+	cmd.PersistentFlags().BoolVar(&cfg.API.Reflection, "grpc-reflection",
+		cfg.API.Reflection, "Enable the grpc server reflection service.")
 	cmd.PersistentFlags().StringVar(&cfg.API.JSONListener, "grpc-json-listener",
 		cfg.API.JSONListener, "Socket for the grpc gateway for the list of services in grpc-public-services. If left empty - grpc gateway won't be enabled.")
+	cmd.PersistentFlags().StringVar(&cfg.API.PrivateJSONListener, "grpc-private-json-listener",
+		cfg.API.PrivateJSONListener, "Socket for the grpc gateway for the list of services in grpc-private-services and the admin endpoints. If left empty - private grpc gateway won't be enabled.")
+	cmd.PersistentFlags().StringVar(&cfg.API.TLSCert, "grpc-tls-cert",
+		cfg.API.TLSCert, "Path to the tls certificate for the services specified in grpc-private-services.")
+	cmd.PersistentFlags().StringVar(&cfg.API.TLSKey, "grpc-tls-key",
+		cfg.API.TLSKey, "Path to the tls key for the services specified in grpc-private-services.")
+	cmd.PersistentFlags().StringVar(&cfg.API.TLSClientCA, "grpc-tls-client-ca",
+		cfg.API.TLSClientCA, "Path to the ca that must sign client certificates for the services specified in grpc-private-services (mutual tls).")
+	cmd.PersistentFlags().StringVar(&cfg.API.AuthToken, "grpc-auth-token",
+		cfg.API.AuthToken, "Bearer token required by the services specified in grpc-private-services. Must be set if the private grpc gateway serves the admin service.")
+	cmd.PersistentFlags().Float64Var(&cfg.API.RateLimit, "grpc-rate-limit",
+		cfg.API.RateLimit, "Number of requests per second allowed for a single client. If set to 0 - requests are not limited.")
+	cmd.PersistentFlags().IntVar(&cfg.API.RateLimitBurst, "grpc-rate-limit-burst",
+		cfg.API.RateLimitBurst, "Number of requests a single client can make at once above the rate limit. If set to 0 - equals to the rate limit.")
+	cmd.PersistentFlags().DurationVar(&cfg.API.SlowRequestThreshold, "grpc-slow-request-threshold",
+		cfg.API.SlowRequestThreshold, "Log the grpc requests that take longer than this duration. If set to 0 - slow requests are not logged.")
+	cmd.PersistentFlags().StringVar(&cfg.API.MinPeerVersion, "grpc-min-peer-version",
+		cfg.API.MinPeerVersion, "Oldest version of the peers compatible with the node, advertised to the clients (e.g. v1.0.0).")
+	cmd.PersistentFlags().StringVar(&cfg.API.MinClientVersion, "grpc-min-client-version",
+		cfg.API.MinClientVersion, "Oldest version of the clients compatible with the node (e.g. v1.0.0).")
+	cmd.PersistentFlags().StringVar(&cfg.API.HealthListener, "grpc-health-listener",
+		cfg.API.HealthListener, "(Plain HTTP) Socket for the liveness (/healthz) and readiness (/readyz) probes. If not set - probes are served only over grpc.")
+	cmd.PersistentFlags().Uint32Var(&cfg.API.ReadyMaxLayersBehind, "grpc-ready-max-layers-behind",
+		cfg.API.ReadyMaxLayersBehind, "Max number of layers the node can be behind the current layer to be ready.")
+	cmd.PersistentFlags().IntVar(&cfg.API.ReadyMinPeers, "grpc-ready-min-peers",
+		cfg.API.ReadyMinPeers, "Min number of connected peers for the node to be ready.")
 	/**======================== Hare Flags ========================== **/
 
 	// N determines the size of the hare committee
 	cmd.PersistentFlags().IntVar(&cfg.HARE.N, "hare-committee-size",
 		cfg.HARE.N, "Size of Hare committee")
+	cmd.PersistentFlags().IntVar(&cfg.HARE.Threshold, "hare-threshold",
+		cfg.HARE.Threshold, "Number of votes required to prove a value in the Hare protocol. Majority of the committee if zero")
 	// RoundDuration determines the duration of a round in the Hare protocol
 	cmd.PersistentFlags().DurationVar(&cfg.HARE.RoundDuration, "hare-round-duration",
 		cfg.HARE.RoundDuration, "Duration of round in the Hare protocol")
@@ -165,11 +235,31 @@ func AddCommands(cmd *cobra.Command) {
 		cfg.HARE.LimitIterations, "The limit of the number of iteration per consensus process")
 	cmd.PersistentFlags().IntVar(&cfg.HARE.LimitConcurrent, "hare-limit-concurrent",
 		cfg.HARE.LimitConcurrent, "The number of consensus processes running concurrently")
+	cmd.PersistentFlags().StringVar(&cfg.HARE.LimitPolicy, "hare-limit-policy",
+		cfg.HARE.LimitPolicy, "Policy for a consensus process beyond hare-limit-concurrent: skip stops the oldest process, queue delays the new one")
+	cmd.PersistentFlags().BoolVar(&cfg.HARE.WAL, "hare-wal",
+		cfg.HARE.WAL, "Persist hare messages to resume in-flight consensus processes after restart")
+	cmd.PersistentFlags().BoolVar(&cfg.HARE.Turbo, "hare-turbo",
+		cfg.HARE.Turbo, "Finalize layers without running the hare protocol. Requires standalone mode")
+	cmd.PersistentFlags().DurationVar(&cfg.HARE.BatchInterval, "hare-batch-interval",
+		cfg.HARE.BatchInterval, "Aggregate own hare messages for concurrent layers into batches published at this interval. Zero disables batching")
+	cmd.PersistentFlags().DurationVar(&cfg.HARE.VerifyWindow, "hare-verify-window",
+		cfg.HARE.VerifyWindow, "Collect signatures of incoming hare messages for this duration and verify them as a batch. Zero disables batch verification")
+	cmd.PersistentFlags().Uint32Var((*uint32)(&cfg.HARE.BatchVerifyEpoch), "hare-batch-verify-epoch",
+		uint32(cfg.HARE.BatchVerifyEpoch), "First epoch in which signatures of incoming hare messages are verified with the ZIP-215 rules and may be batched. Zero disables batch verification")
+	cmd.PersistentFlags().IntVar(&cfg.HARE.RebroadcastPeers, "hare-rebroadcast-peers",
+		cfg.HARE.RebroadcastPeers, "Republish own hare messages after the number of connected peers recovers to this value. Zero disables rebroadcast")
+	cmd.PersistentFlags().IntVar(&cfg.HARE.IdentityBudget, "hare-identity-budget",
+		cfg.HARE.IdentityBudget, "Maximal number of hare messages accepted from a single identity in a round. Zero disables the budget")
 
 	/**======================== Hare Eligibility Oracle Flags ========================== **/
 
 	cmd.PersistentFlags().Uint32Var(&cfg.HareEligibility.ConfidenceParam, "eligibility-confidence-param",
 		cfg.HareEligibility.ConfidenceParam, "The relative layer (with respect to the current layer) we are confident to have consensus about")
+	cmd.PersistentFlags().Uint32Var((*uint32)(&cfg.HareEligibility.VrfV2Epoch), "eligibility-vrf-v2-epoch",
+		uint32(cfg.HareEligibility.VrfV2Epoch), "First epoch in which hare eligibility proofs include the epoch and the identity vrf nonce, legacy proofs are rejected starting from it. Zero keeps the legacy proofs")
+	cmd.PersistentFlags().Uint32Var((*uint32)(&cfg.HareEligibility.StorageWeightEpoch), "eligibility-storage-weight-epoch",
+		uint32(cfg.HareEligibility.StorageWeightEpoch), "First epoch in which hare eligibility is weighted by the storage committed in the atx. Zero keeps the full atx weight")
 
 	/**======================== Beacon Flags ========================== **/
 
@@ -209,6 +299,32 @@ func AddCommands(cmd *cobra.Command) {
 		cfg.Tortoise.BadBeaconVoteDelayLayers, "number of layers to ignore a ballot with a different beacon")
 	cmd.PersistentFlags().BoolVar(&cfg.Tortoise.EnableTracer, "tortoise-enable-tracer",
 		cfg.Tortoise.EnableTracer, "recovrd every tortoise input/output into the loggin output")
+	cmd.PersistentFlags().DurationVar(&cfg.Tortoise.RerunInterval, "tortoise-rerun-interval",
+		cfg.Tortoise.RerunInterval, "interval between background tortoise reruns from the database. 0 disables reruns")
+	cmd.PersistentFlags().Uint64Var(&cfg.Tortoise.MemoryBudget, "tortoise-memory-budget",
+		cfg.Tortoise.MemoryBudget, "approximate limit in bytes for tortoise state, layers before the window are evicted to fit it. 0 disables the limit")
+
+	/**======================== Fetch Flags ========================== **/
+	cmd.PersistentFlags().IntVar(&cfg.FETCH.DownloadLimit, "fetch-download-limit",
+		cfg.FETCH.DownloadLimit, "bytes per second received by fetch and sync protocols, gossip is not limited. 0 is unlimited")
+	cmd.PersistentFlags().IntVar(&cfg.FETCH.UploadLimit, "fetch-upload-limit",
+		cfg.FETCH.UploadLimit, "bytes per second sent by fetch and sync protocols, gossip is not limited. 0 is unlimited")
+	cmd.PersistentFlags().DurationVar(&cfg.FETCH.QuarantineCooldown, "fetch-quarantine-cooldown",
+		cfg.FETCH.QuarantineCooldown, "duration for which a hash is not fetched after its data failed validation from every peer. 0 disables the quarantine")
+
+	/**======================== Pruning Flags ========================== **/
+	cmd.PersistentFlags().Uint32Var(&cfg.Pruning.RetainLayers, "prune-retain-layers",
+		cfg.Pruning.RetainLayers, "number of layers behind the last verified layer to keep bodies of applied transactions for. 0 disables pruning")
+	cmd.PersistentFlags().DurationVar(&cfg.Pruning.Interval, "prune-interval",
+		cfg.Pruning.Interval, "interval between background mesh pruning runs")
+	cmd.PersistentFlags().BoolVar(&cfg.Pruning.Compact, "prune-compact",
+		cfg.Pruning.Compact, "compact the database after pruning to return freed space to the filesystem")
+
+	/**======================== Integrity Flags ========================== **/
+	cmd.PersistentFlags().BoolVar(&cfg.Integrity.CheckOnStartup, "mesh-check-on-startup",
+		cfg.Integrity.CheckOnStartup, "check mesh integrity for all layers when the node starts")
+	cmd.PersistentFlags().BoolVar(&cfg.Integrity.Repair, "mesh-check-repair",
+		cfg.Integrity.Repair, "delete dangling index entries found by the mesh integrity check on startup")
 
 	// TODO(moshababo): add usage desc
 	cmd.PersistentFlags().Uint64Var(&cfg.POST.LabelsPerUnit, "post-labels-per-unit",
@@ -0,0 +1,172 @@
+package types
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/spacemeshos/go-spacemesh/codec"
+	"github.com/spacemeshos/go-spacemesh/hash"
+)
+
+// prefixes separate the hashes of the leaves and the internal nodes of the accounts tree.
+var (
+	accountLeafPrefix = []byte{0}
+	accountNodePrefix = []byte{1}
+)
+
+// AccountProof is a merkle proof of the account state against the root of the accounts tree.
+// The leaves of the tree are the accounts ordered by address, the last node on the level
+// without a pair is moved to the next level as is.
+type AccountProof struct {
+	Account Account
+	// Index is the position of the account in the ordered accounts.
+	Index uint64
+	// Leaves is the total number of accounts in the tree.
+	Leaves uint64
+	// Path is the list of the sibling hashes from the leaf to the root.
+	Path []Hash32
+}
+
+func accountLeaf(account *Account) Hash32 {
+	return hash.Sum(accountLeafPrefix, codec.MustEncode(account))
+}
+
+func accountNode(left, right Hash32) Hash32 {
+	return hash.Sum(accountNodePrefix, left[:], right[:])
+}
+
+func nextAccountsLevel(level []Hash32) []Hash32 {
+	next := make([]Hash32, 0, (len(level)+1)/2)
+	for i := 0; i < len(level); i += 2 {
+		if i+1 == len(level) {
+			next = append(next, level[i])
+		} else {
+			next = append(next, accountNode(level[i], level[i+1]))
+		}
+	}
+	return next
+}
+
+func sortAccounts(accounts []*Account) {
+	sort.Slice(accounts, func(i, j int) bool {
+		return bytes.Compare(accounts[i].Address[:], accounts[j].Address[:]) < 0
+	})
+}
+
+// AccountsTree is the accounts tree of the state at a layer. It is immutable, so that
+// the tree of the layer can be served while the tree of the next layer is built.
+type AccountsTree struct {
+	accounts []Account
+	// levels are the hashes of the tree from the leaves to the root.
+	levels [][]Hash32
+}
+
+// NewAccountsTree builds the tree of the accounts. Accounts are sorted in place.
+func NewAccountsTree(accounts []*Account) *AccountsTree {
+	sortAccounts(accounts)
+	tree := &AccountsTree{accounts: make([]Account, 0, len(accounts))}
+	for _, account := range accounts {
+		tree.accounts = append(tree.accounts, *account)
+	}
+	tree.build()
+	return tree
+}
+
+func (t *AccountsTree) build() {
+	level := make([]Hash32, 0, len(t.accounts))
+	for i := range t.accounts {
+		level = append(level, accountLeaf(&t.accounts[i]))
+	}
+	t.levels = [][]Hash32{level}
+	for len(level) > 1 {
+		level = nextAccountsLevel(level)
+		t.levels = append(t.levels, level)
+	}
+}
+
+// Update returns the tree with the changed accounts, the accounts that are not in the tree are inserted.
+func (t *AccountsTree) Update(changed []*Account) *AccountsTree {
+	updated := &AccountsTree{accounts: make([]Account, len(t.accounts), len(t.accounts)+len(changed))}
+	copy(updated.accounts, t.accounts)
+	for _, account := range changed {
+		i := sort.Search(len(updated.accounts), func(i int) bool {
+			return bytes.Compare(updated.accounts[i].Address[:], account.Address[:]) >= 0
+		})
+		if i < len(updated.accounts) && updated.accounts[i].Address == account.Address {
+			updated.accounts[i] = *account
+			continue
+		}
+		updated.accounts = append(updated.accounts, Account{})
+		copy(updated.accounts[i+1:], updated.accounts[i:])
+		updated.accounts[i] = *account
+	}
+	updated.build()
+	return updated
+}
+
+// Root returns the root of the tree, empty hash if the tree has no accounts.
+func (t *AccountsTree) Root() Hash32 {
+	top := t.levels[len(t.levels)-1]
+	if len(top) == 0 {
+		return Hash32{}
+	}
+	return top[0]
+}
+
+// Prove returns the proof for the account with the address, false if there is no such account.
+func (t *AccountsTree) Prove(address Address) (*AccountProof, bool) {
+	i := sort.Search(len(t.accounts), func(i int) bool {
+		return bytes.Compare(t.accounts[i].Address[:], address[:]) >= 0
+	})
+	if i == len(t.accounts) || t.accounts[i].Address != address {
+		return nil, false
+	}
+	proof := &AccountProof{Account: t.accounts[i], Index: uint64(i), Leaves: uint64(len(t.accounts))}
+	idx := proof.Index
+	for _, level := range t.levels[:len(t.levels)-1] {
+		if sibling := idx ^ 1; sibling < uint64(len(level)) {
+			proof.Path = append(proof.Path, level[sibling])
+		}
+		idx /= 2
+	}
+	return proof, true
+}
+
+// AccountsRoot computes the root of the accounts tree. Accounts are sorted in place.
+func AccountsRoot(accounts []*Account) Hash32 {
+	return NewAccountsTree(accounts).Root()
+}
+
+// ProveAccount computes the root of the accounts tree and the proof for the account with the address.
+// Proof is nil if there is no account with the address. Accounts are sorted in place.
+func ProveAccount(accounts []*Account, address Address) (*AccountProof, Hash32, bool) {
+	tree := NewAccountsTree(accounts)
+	proof, found := tree.Prove(address)
+	return proof, tree.Root(), found
+}
+
+// Verify returns true if the proof is consistent with the root of the accounts tree.
+func (p *AccountProof) Verify(root Hash32) bool {
+	if p.Index >= p.Leaves {
+		return false
+	}
+	current := accountLeaf(&p.Account)
+	path := p.Path
+	for idx, n := p.Index, p.Leaves; n > 1; idx, n = idx/2, (n+1)/2 {
+		switch {
+		case idx%2 == 1:
+			if len(path) == 0 {
+				return false
+			}
+			current = accountNode(path[0], current)
+			path = path[1:]
+		case idx+1 < n:
+			if len(path) == 0 {
+				return false
+			}
+			current = accountNode(current, path[0])
+			path = path[1:]
+		}
+	}
+	return len(path) == 0 && current == root
+}
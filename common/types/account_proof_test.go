@@ -0,0 +1,67 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountProof(t *testing.T) {
+	for n := 1; n <= 9; n++ {
+		accounts := make([]*Account, 0, n)
+		for i := 0; i < n; i++ {
+			accounts = append(accounts, &Account{
+				Layer:   LayerID(i),
+				Address: GenerateAddress(RandomBytes(32)),
+				Balance: uint64(i),
+			})
+		}
+		root := AccountsRoot(accounts)
+		for _, account := range accounts {
+			proof, proofRoot, found := ProveAccount(accounts, account.Address)
+			require.True(t, found)
+			require.Equal(t, root, proofRoot)
+			require.Equal(t, *account, proof.Account)
+			require.True(t, proof.Verify(root), "accounts %d index %d", n, proof.Index)
+
+			tampered := *proof
+			tampered.Account.Balance++
+			require.False(t, tampered.Verify(root))
+			if n > 1 {
+				tampered = *proof
+				tampered.Index = (proof.Index + 1) % proof.Leaves
+				require.False(t, tampered.Verify(root))
+			}
+		}
+		_, _, found := ProveAccount(accounts, GenerateAddress(RandomBytes(32)))
+		require.False(t, found)
+	}
+}
+
+func TestAccountsTreeUpdate(t *testing.T) {
+	accounts := make([]*Account, 0, 10)
+	for i := 0; i < 7; i++ {
+		accounts = append(accounts, &Account{Address: GenerateAddress(RandomBytes(32)), Balance: uint64(i)})
+	}
+	tree := NewAccountsTree(accounts)
+	root := tree.Root()
+
+	changed := []*Account{
+		{Address: accounts[3].Address, Balance: 100},
+		{Address: GenerateAddress(RandomBytes(32)), Balance: 200},
+		{Address: GenerateAddress(RandomBytes(32)), Balance: 300},
+	}
+	updated := tree.Update(changed)
+	require.Equal(t, root, tree.Root(), "tree of the previous layer is not modified")
+
+	expected := append([]*Account{}, accounts...)
+	expected[3] = changed[0]
+	expected = append(expected, changed[1:]...)
+	require.Equal(t, AccountsRoot(expected), updated.Root())
+	for _, account := range expected {
+		proof, found := updated.Prove(account.Address)
+		require.True(t, found)
+		require.Equal(t, *account, proof.Account)
+		require.True(t, proof.Verify(updated.Root()))
+	}
+}
@@ -108,8 +108,10 @@ type AnyReward struct {
 
 // CoinbaseReward contains the reward information by coinbase, used as an interface to VM.
 type CoinbaseReward struct {
-	Coinbase Address
-	Weight   RatNum
+	// SmesherID is the id of the smesher who earned the reward.
+	SmesherID NodeID
+	Coinbase  Address
+	Weight    RatNum
 }
 
 // Initialize calculates and sets the Block's cached blockID.
@@ -4,12 +4,22 @@ type Checkpoint struct {
 	Command string    `json:"command"`
 	Version string    `json:"version"`
 	Data    InnerData `json:"data"`
+
+	// Signer and Signature are optional. Signature is the signature of the signer over Data.
+	Signer    []byte `json:"signer,omitempty"`
+	Signature []byte `json:"signature,omitempty"`
 }
 
 type InnerData struct {
 	CheckpointId string            `json:"id"`
 	Atxs         []AtxSnapshot     `json:"atxs"`
 	Accounts     []AccountSnapshot `json:"accounts"`
+
+	// Layer and AggregatedHash are optional. They describe the mesh at the snapshot layer.
+	Layer uint32 `json:"layer,omitempty"`
+	// AggregatedHash commits to the blocks of all layers up to the snapshot layer,
+	// layers below the checkpoint are verified against it.
+	AggregatedHash []byte `json:"aggregatedHash,omitempty"`
 }
 
 type AtxSnapshot struct {
@@ -19,6 +19,7 @@ import (
 	vm "github.com/spacemeshos/go-spacemesh/genvm"
 	hareConfig "github.com/spacemeshos/go-spacemesh/hare/config"
 	eligConfig "github.com/spacemeshos/go-spacemesh/hare/eligibility/config"
+	"github.com/spacemeshos/go-spacemesh/mesh"
 	"github.com/spacemeshos/go-spacemesh/p2p"
 	"github.com/spacemeshos/go-spacemesh/syncer"
 	timeConfig "github.com/spacemeshos/go-spacemesh/timesync/config"
@@ -61,6 +62,8 @@ type Config struct {
 	Bootstrap       bootstrap.Config      `mapstructure:"bootstrap"`
 	Sync            syncer.Config         `mapstructure:"syncer"`
 	Recovery        checkpoint.Config     `mapstructure:"recovery"`
+	Pruning         mesh.PruningConfig    `mapstructure:"pruning"`
+	Integrity       mesh.IntegrityConfig  `mapstructure:"integrity"`
 }
 
 // DataDir returns the absolute path to use for the node's data. This is the tilde-expanded path given in the config
@@ -100,6 +103,36 @@ type BaseConfig struct {
 
 	TxsPerProposal int    `mapstructure:"txs-per-proposal"`
 	BlockGasLimit  uint64 `mapstructure:"block-gas-limit"`
+	// ReplaceFeeBump is the percentage by which the fee of the transaction must be higher than the fee of
+	// the pending transaction with the same principal and nonce to replace it in the mempool.
+	ReplaceFeeBump uint64 `mapstructure:"tx-replace-fee-bump"`
+	// MempoolMaxTXs is the max number of transactions in the mempool, the cheapest are evicted when it is full.
+	MempoolMaxTXs int `mapstructure:"mempool-max-txs"`
+	// MempoolMaxAge is the max time the transaction stays in the mempool before it is evicted.
+	MempoolMaxAge time.Duration `mapstructure:"mempool-max-age"`
+	// MempoolMaxBytes is the max total size of the transactions in the mempool, the cheapest are evicted
+	// when it is exceeded.
+	MempoolMaxBytes int `mapstructure:"mempool-max-bytes"`
+	// MempoolMaxAcctTXs is the max number of pending transactions of a single principal in the mempool.
+	MempoolMaxAcctTXs int `mapstructure:"mempool-max-account-txs"`
+	// MempoolMaxOrphans is the max number of transactions with the nonce gap held in memory until
+	// the gap is filled.
+	MempoolMaxOrphans int `mapstructure:"mempool-max-orphans"`
+	// TxRebroadcastInterval is the number of layers after which the unconfirmed transaction submitted
+	// via api of this node is gossiped again, zero disables the rebroadcast.
+	TxRebroadcastInterval uint32 `mapstructure:"tx-rebroadcast-interval"`
+	// TxRebroadcastRetries is the max number of times the transaction is gossiped again.
+	TxRebroadcastRetries int `mapstructure:"tx-rebroadcast-retries"`
+	// StateRetention is the number of layers for which the historical account states are kept,
+	// zero keeps the full history. it must cover the tortoise window.
+	StateRetention uint32 `mapstructure:"state-retention-layers"`
+	// GasSchedules change the gas costs and the gas limit of the vm starting from their activation layers.
+	// they must be identical on all nodes of the network.
+	GasSchedules []vm.GasSchedule `mapstructure:"gas-schedules"`
+	// ApplyWorkers is the number of transactions of the layer executed concurrently, zero uses all cpus.
+	ApplyWorkers int `mapstructure:"apply-workers"`
+	// LateProposalGrace is how long after the end of the layer gossiped proposals are still accepted.
+	LateProposalGrace time.Duration `mapstructure:"late-proposal-grace"`
 	// if the number of proposals with the same mesh state crosses this threshold (in percentage),
 	// then we optimistically filter out infeasible transactions before constructing the block.
 	OptFilterThreshold int    `mapstructure:"optimistic-filtering-threshold"`
@@ -109,6 +142,10 @@ type BaseConfig struct {
 	DatabaseLatencyMetering bool `mapstructure:"db-latency-metering"`
 
 	NetworkHRP string `mapstructure:"network-hrp"`
+
+	// ImportArchive is a directory or a tar archive with the mesh data exported by another node.
+	// the data is validated and imported on startup, before the node syncs with peers.
+	ImportArchive string `mapstructure:"import-archive"`
 }
 
 type PublicMetrics struct {
@@ -149,6 +186,8 @@ func DefaultConfig() Config {
 		Bootstrap:       bootstrap.DefaultConfig(),
 		Sync:            syncer.DefaultConfig(),
 		Recovery:        checkpoint.DefaultConfig(),
+		Pruning:         mesh.DefaultPruningConfig(),
+		Integrity:       mesh.DefaultIntegrityConfig(),
 	}
 }
 
@@ -174,10 +213,19 @@ func defaultBaseConfig() BaseConfig {
 		PoETServers:         []string{"127.0.0.1"},
 		TxsPerProposal:      100,
 		BlockGasLimit:       math.MaxUint64,
+		ReplaceFeeBump:      10,
+		MempoolMaxTXs:       100_000,
+		MempoolMaxAge:       24 * time.Hour,
+		MempoolMaxBytes:     64 << 20,
+		MempoolMaxAcctTXs:   100,
+		MempoolMaxOrphans:   1000,
 		OptFilterThreshold:  90,
 		TickSize:            100,
 		DatabaseConnections: 16,
 		NetworkHRP:          "sm",
+
+		TxRebroadcastInterval: 5,
+		TxRebroadcastRetries:  10,
 	}
 }
 
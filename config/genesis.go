@@ -1,10 +1,12 @@
 package config
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
@@ -21,6 +23,19 @@ type GenesisConfig struct {
 	GenesisTime string            `mapstructure:"genesis-time"`
 	ExtraData   string            `mapstructure:"genesis-extra-data"`
 	Accounts    map[string]uint64 `mapstructure:"accounts"`
+	// EffectiveGenesis is the last genesis layer. If zero, genesis lasts two epochs
+	// (see types.SetLayersPerEpoch). Must be the last layer in the epoch.
+	EffectiveGenesis uint32 `mapstructure:"effective-genesis"`
+	// InitialBeacon is a hex encoded beacon for the first epoch after genesis.
+	// If empty, the beacon is expected from the bootstrap update.
+	InitialBeacon string `mapstructure:"initial-beacon"`
+	// AccountsFile is a path to the json file with the genesis allocation (see GenesisAllocation).
+	// If set, the allocation replaces Accounts.
+	AccountsFile string `mapstructure:"accounts-file"`
+	// AccountsHash is a hex encoded hash of the AccountsFile content, it is committed to the genesis id.
+	AccountsHash string `mapstructure:"accounts-hash"`
+	// Vaults are the vesting vaults loaded from AccountsFile.
+	Vaults []GenesisVault `mapstructure:"-"`
 }
 
 // GenesisID computes genesis id from GenesisTime and ExtraData.
@@ -36,6 +51,16 @@ func (g *GenesisConfig) GoldenATX() types.Hash32 {
 	}
 	hh.Write([]byte(strconv.FormatInt(parsed.Unix(), 10)))
 	hh.Write([]byte(g.ExtraData))
+	// optional parameters are included only if set, so that ids of the existing networks don't change
+	if g.EffectiveGenesis != 0 {
+		hh.Write([]byte(strconv.FormatUint(uint64(g.EffectiveGenesis), 10)))
+	}
+	if len(g.InitialBeacon) > 0 {
+		hh.Write([]byte(g.InitialBeacon))
+	}
+	if len(g.AccountsHash) > 0 {
+		hh.Write([]byte(strings.TrimPrefix(g.AccountsHash, "0x")))
+	}
 	return types.BytesToHash(hh.Sum(nil))
 }
 
@@ -52,9 +77,49 @@ func (g *GenesisConfig) Validate() error {
 		return fmt.Errorf("can't parse genesis time %s using time.RFC3339(%s) %w",
 			g.GenesisTime, time.RFC3339, err)
 	}
+	if len(g.InitialBeacon) > 0 {
+		if _, err := g.Beacon(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateLayers checks that genesis layers are consistent with the number of layers per epoch.
+func (g *GenesisConfig) ValidateLayers(layersPerEpoch uint32) error {
+	if g.EffectiveGenesis == 0 {
+		return nil
+	}
+	if layersPerEpoch == 0 {
+		return fmt.Errorf("layers per epoch must be set")
+	}
+	if (g.EffectiveGenesis+1)%layersPerEpoch != 0 {
+		return fmt.Errorf("effective genesis %d must be the last layer in the epoch (layers per epoch %d)",
+			g.EffectiveGenesis, layersPerEpoch)
+	}
 	return nil
 }
 
+// Beacon decodes InitialBeacon. Returns empty beacon if InitialBeacon is not set.
+func (g *GenesisConfig) Beacon() (types.Beacon, error) {
+	var beacon types.Beacon
+	if len(g.InitialBeacon) == 0 {
+		return beacon, nil
+	}
+	decoded, err := hex.DecodeString(strings.TrimPrefix(g.InitialBeacon, "0x"))
+	if err != nil {
+		return beacon, fmt.Errorf("decode initial beacon %s: %w", g.InitialBeacon, err)
+	}
+	if len(decoded) != types.BeaconSize {
+		return beacon, fmt.Errorf("initial beacon %s must be %d bytes", g.InitialBeacon, types.BeaconSize)
+	}
+	copy(beacon[:], decoded)
+	if beacon == types.EmptyBeacon {
+		return beacon, fmt.Errorf("initial beacon must not be empty")
+	}
+	return beacon, nil
+}
+
 // Diff returns difference between two configs.
 func (g *GenesisConfig) Diff(other *GenesisConfig) string {
 	return cmp.Diff(g, other)
@@ -93,6 +158,13 @@ func (g *GenesisConfig) ToAccounts() []types.Account {
 			Balance: balance,
 		})
 	}
+	for i := range g.Vaults {
+		account, err := g.Vaults[i].account()
+		if err != nil {
+			log.Panic("could not create vault from genesis config: %s", err.Error())
+		}
+		rst = append(rst, account)
+	}
 	return rst
 }
 
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spacemeshos/go-scale"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/genvm/core"
+	"github.com/spacemeshos/go-spacemesh/genvm/templates/vault"
+	"github.com/spacemeshos/go-spacemesh/hash"
+)
+
+// GenesisVault is a vesting vault allocated at genesis.
+type GenesisVault struct {
+	Owner               string `json:"owner"`
+	TotalAmount         uint64 `json:"total_amount"`
+	InitialUnlockAmount uint64 `json:"initial_unlock_amount"`
+	VestingStart        uint32 `json:"vesting_start"`
+	VestingEnd          uint32 `json:"vesting_end"`
+}
+
+func (v *GenesisVault) args() (*vault.SpawnArguments, error) {
+	owner, err := types.StringToAddress(v.Owner)
+	if err != nil {
+		return nil, fmt.Errorf("vault owner %s: %w", v.Owner, err)
+	}
+	if v.InitialUnlockAmount > v.TotalAmount {
+		return nil, fmt.Errorf("vault of %s: initial unlock %d is larger than total %d",
+			v.Owner, v.InitialUnlockAmount, v.TotalAmount)
+	}
+	if v.VestingEnd < v.VestingStart {
+		return nil, fmt.Errorf("vault of %s: vesting end %d is before start %d",
+			v.Owner, v.VestingEnd, v.VestingStart)
+	}
+	return &vault.SpawnArguments{
+		Owner:               owner,
+		TotalAmount:         v.TotalAmount,
+		InitialUnlockAmount: v.InitialUnlockAmount,
+		VestingStart:        types.LayerID(v.VestingStart),
+		VestingEnd:          types.LayerID(v.VestingEnd),
+	}, nil
+}
+
+// account returns the spawned vault account with the full amount on its balance.
+func (v *GenesisVault) account() (types.Account, error) {
+	args, err := v.args()
+	if err != nil {
+		return types.Account{}, err
+	}
+	state := vault.Vault{
+		Owner:               args.Owner,
+		TotalAmount:         args.TotalAmount,
+		InitialUnlockAmount: args.InitialUnlockAmount,
+		VestingStart:        args.VestingStart,
+		VestingEnd:          args.VestingEnd,
+	}
+	buf := bytes.NewBuffer(nil)
+	if _, err := state.EncodeScale(scale.NewEncoder(buf)); err != nil {
+		return types.Account{}, fmt.Errorf("encode vault of %s: %w", v.Owner, err)
+	}
+	template := vault.TemplateAddress
+	return types.Account{
+		Address:         core.ComputePrincipal(template, args),
+		Balance:         args.TotalAmount,
+		TemplateAddress: &template,
+		State:           buf.Bytes(),
+	}, nil
+}
+
+// GenesisAllocation is the content of the genesis accounts file.
+type GenesisAllocation struct {
+	// Accounts maps the address to its initial balance.
+	Accounts map[string]uint64 `json:"accounts"`
+	// Vaults are the vesting vaults spawned at genesis.
+	Vaults []GenesisVault `json:"vaults,omitempty"`
+}
+
+// Validate checks the addresses and the vesting schedules in the allocation.
+func (a *GenesisAllocation) Validate() error {
+	for addr := range a.Accounts {
+		if _, err := types.StringToAddress(addr); err != nil {
+			return fmt.Errorf("account %s: %w", addr, err)
+		}
+	}
+	for i := range a.Vaults {
+		if _, err := a.Vaults[i].args(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AllocationHash returns hex encoded hash of the genesis accounts file content.
+func AllocationHash(data []byte) string {
+	sum := hash.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// LoadAccounts replaces the configured accounts with the allocation from AccountsFile.
+// The hash of the file content must match AccountsHash. Noop if AccountsFile is not set.
+func (g *GenesisConfig) LoadAccounts() error {
+	if len(g.AccountsFile) == 0 {
+		return nil
+	}
+	if len(g.AccountsHash) == 0 {
+		return errors.New("genesis accounts hash must be set together with the accounts file")
+	}
+	data, err := os.ReadFile(g.AccountsFile)
+	if err != nil {
+		return fmt.Errorf("read genesis accounts %s: %w", g.AccountsFile, err)
+	}
+	if actual := AllocationHash(data); actual != strings.TrimPrefix(g.AccountsHash, "0x") {
+		return fmt.Errorf("genesis accounts %s hash mismatch: expected %s, actual %s",
+			g.AccountsFile, g.AccountsHash, actual)
+	}
+	var alloc GenesisAllocation
+	if err := json.Unmarshal(data, &alloc); err != nil {
+		return fmt.Errorf("decode genesis accounts %s: %w", g.AccountsFile, err)
+	}
+	if err := alloc.Validate(); err != nil {
+		return fmt.Errorf("genesis accounts %s: %w", g.AccountsFile, err)
+	}
+	g.Accounts = alloc.Accounts
+	g.Vaults = alloc.Vaults
+	return nil
+}
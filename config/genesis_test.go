@@ -1,11 +1,16 @@
 package config
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/genvm/templates/vault"
 	"github.com/spacemeshos/go-spacemesh/hash"
 )
 
@@ -34,3 +39,105 @@ func TestGenesisID(t *testing.T) {
 		require.Equal(t, expected[:20], cfg.GenesisID().Bytes())
 	})
 }
+
+func TestGenesisOptionalParams(t *testing.T) {
+	base := GenesisConfig{ExtraData: "one", GenesisTime: "2023-03-15T18:00:00Z"}
+	t.Run("genesis id", func(t *testing.T) {
+		custom := base
+		custom.EffectiveGenesis = 7
+		require.NotEqual(t, base.GenesisID(), custom.GenesisID())
+		withBeacon := base
+		withBeacon.InitialBeacon = "0x01020304"
+		require.NotEqual(t, base.GenesisID(), withBeacon.GenesisID())
+	})
+	t.Run("effective genesis", func(t *testing.T) {
+		cfg := base
+		require.NoError(t, cfg.ValidateLayers(4))
+		cfg.EffectiveGenesis = 7
+		require.NoError(t, cfg.ValidateLayers(4))
+		cfg.EffectiveGenesis = 8
+		require.ErrorContains(t, cfg.ValidateLayers(4), "last layer in the epoch")
+	})
+	t.Run("initial beacon", func(t *testing.T) {
+		cfg := base
+		beacon, err := cfg.Beacon()
+		require.NoError(t, err)
+		require.Equal(t, types.EmptyBeacon, beacon)
+
+		cfg.InitialBeacon = "0x01020304"
+		require.NoError(t, cfg.Validate())
+		beacon, err = cfg.Beacon()
+		require.NoError(t, err)
+		require.Equal(t, types.Beacon{1, 2, 3, 4}, beacon)
+
+		for _, invalid := range []string{"0x0102", "zz", "0x00000000"} {
+			cfg.InitialBeacon = invalid
+			require.Error(t, cfg.Validate(), invalid)
+		}
+	})
+}
+
+func TestGenesisAccountsFile(t *testing.T) {
+	owner := types.GenerateAddress([]byte{1})
+	alloc := GenesisAllocation{
+		Accounts: map[string]uint64{types.GenerateAddress([]byte{2}).String(): 100},
+		Vaults: []GenesisVault{{
+			Owner:               owner.String(),
+			TotalAmount:         1000,
+			InitialUnlockAmount: 100,
+			VestingStart:        10,
+			VestingEnd:          20,
+		}},
+	}
+	data, err := json.Marshal(alloc)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "accounts.json")
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+
+	base := GenesisConfig{
+		ExtraData:   "one",
+		GenesisTime: "2023-03-15T18:00:00Z",
+		Accounts:    map[string]uint64{types.GenerateAddress([]byte{3}).String(): 1},
+	}
+	t.Run("loaded", func(t *testing.T) {
+		cfg := base
+		cfg.AccountsFile = path
+		cfg.AccountsHash = AllocationHash(data)
+		require.NoError(t, cfg.LoadAccounts())
+		require.Equal(t, alloc.Accounts, cfg.Accounts)
+		require.NotEqual(t, base.GenesisID(), cfg.GenesisID())
+
+		accounts := cfg.ToAccounts()
+		require.Len(t, accounts, 2)
+		require.Equal(t, types.GenerateAddress([]byte{2}), accounts[0].Address)
+		require.EqualValues(t, 100, accounts[0].Balance)
+		require.Equal(t, vault.TemplateAddress, *accounts[1].TemplateAddress)
+		require.EqualValues(t, 1000, accounts[1].Balance)
+		require.NotEmpty(t, accounts[1].State)
+	})
+	t.Run("hash mismatch", func(t *testing.T) {
+		cfg := base
+		cfg.AccountsFile = path
+		cfg.AccountsHash = AllocationHash([]byte("other"))
+		require.ErrorContains(t, cfg.LoadAccounts(), "hash mismatch")
+		require.Equal(t, base.Accounts, cfg.Accounts)
+	})
+	t.Run("hash required", func(t *testing.T) {
+		cfg := base
+		cfg.AccountsFile = path
+		require.Error(t, cfg.LoadAccounts())
+	})
+	t.Run("invalid vault", func(t *testing.T) {
+		invalid := alloc
+		invalid.Vaults = []GenesisVault{{Owner: owner.String(), TotalAmount: 1, InitialUnlockAmount: 2}}
+		data, err := json.Marshal(invalid)
+		require.NoError(t, err)
+		path := filepath.Join(t.TempDir(), "invalid.json")
+		require.NoError(t, os.WriteFile(path, data, 0o644))
+
+		cfg := base
+		cfg.AccountsFile = path
+		cfg.AccountsHash = AllocationHash(data)
+		require.ErrorContains(t, cfg.LoadAccounts(), "initial unlock")
+	})
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/spacemeshos/go-spacemesh/fetch"
 	hareConfig "github.com/spacemeshos/go-spacemesh/hare/config"
 	eligConfig "github.com/spacemeshos/go-spacemesh/hare/eligibility/config"
+	"github.com/spacemeshos/go-spacemesh/mesh"
 	"github.com/spacemeshos/go-spacemesh/p2p"
 	"github.com/spacemeshos/go-spacemesh/syncer"
 	timeConfig "github.com/spacemeshos/go-spacemesh/timesync/config"
@@ -51,6 +52,16 @@ func MainnetConfig() Config {
 
 			TxsPerProposal: 700,       // https://github.com/spacemeshos/go-spacemesh/issues/4559
 			BlockGasLimit:  100107000, // 3000 of spends
+			ReplaceFeeBump: 10,
+			MempoolMaxTXs:  100_000,
+			MempoolMaxAge:  24 * time.Hour,
+
+			MempoolMaxBytes:   64 << 20,
+			MempoolMaxAcctTXs: 100,
+			MempoolMaxOrphans: 1000,
+
+			TxRebroadcastInterval: 5,
+			TxRebroadcastRetries:  10,
 
 			OptFilterThreshold: 90,
 
@@ -88,6 +99,9 @@ func MainnetConfig() Config {
 		},
 		HareEligibility: eligConfig.Config{
 			ConfidenceParam: 200,
+			// weighting by storage commitment changes eligibility of every identity,
+			// it must be enabled at an epoch agreed upon by the whole network.
+			StorageWeightEpoch: 0,
 		},
 		Beacon: beacon.Config{
 			Kappa:                    40,
@@ -131,11 +145,17 @@ func MainnetConfig() Config {
 		FETCH:    fetch.DefaultConfig(),
 		LOGGING:  defaultLoggingConfig(),
 		Sync: syncer.Config{
-			Interval:         time.Minute,
-			EpochEndFraction: 0.8,
-			MaxStaleDuration: time.Hour,
-			Standalone:       false,
+			Interval:           time.Minute,
+			EpochEndFraction:   0.8,
+			MaxStaleDuration:   time.Hour,
+			PeerIsolation:      time.Hour,
+			Standalone:         false,
+			GossipBufferSize:   1000,
+			GossipBufferLayers: 2,
+			LightQuorum:        3,
 		},
-		Recovery: checkpoint.DefaultConfig(),
+		Recovery:  checkpoint.DefaultConfig(),
+		Pruning:   mesh.DefaultPruningConfig(),
+		Integrity: mesh.DefaultIntegrityConfig(),
 	}
 }
@@ -0,0 +1,60 @@
+package events
+
+import (
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log"
+)
+
+// EventReorg is reported when consensus changes the outcome of layers that
+// were previously applied to the state.
+type EventReorg struct {
+	// OldVerified is the last verified layer before the reorg.
+	OldVerified types.LayerID
+	// NewVerified is the last verified layer after the reorg.
+	NewVerified types.LayerID
+	// Reverted is the first layer whose applied block was reverted.
+	Reverted types.LayerID
+	// Applied is the latest layer that was applied to the state before the reorg.
+	Applied types.LayerID
+	// Blocks that were applied in the reverted layers.
+	Blocks []types.BlockID
+}
+
+// Field returns a log field. Implements the LoggableField interface.
+func (r EventReorg) Field() log.Field {
+	return log.Object("reorg", log.ObjectMarshallerFunc(func(encoder log.ObjectEncoder) error {
+		encoder.AddUint32("old_verified", r.OldVerified.Uint32())
+		encoder.AddUint32("new_verified", r.NewVerified.Uint32())
+		encoder.AddUint32("reverted", r.Reverted.Uint32())
+		encoder.AddUint32("applied", r.Applied.Uint32())
+		encoder.AddInt("blocks", len(r.Blocks))
+		return nil
+	}))
+}
+
+// ReportReorg reports that previously applied layers were reverted.
+func ReportReorg(reorg EventReorg) {
+	mu.RLock()
+	defer mu.RUnlock()
+	if reporter != nil {
+		if err := reporter.reorgEmitter.Emit(reorg); err != nil {
+			log.With().Error("failed to emit reorg", reorg, log.Err(err))
+		} else {
+			log.With().Debug("reported reorg", reorg)
+		}
+	}
+}
+
+// SubscribeReorgs subscribes to the reorgs.
+func SubscribeReorgs() Subscription {
+	mu.RLock()
+	defer mu.RUnlock()
+	if reporter != nil {
+		sub, err := reporter.bus.Subscribe(new(EventReorg))
+		if err != nil {
+			log.With().Panic("Failed to subscribe to reorgs")
+		}
+		return sub
+	}
+	return nil
+}
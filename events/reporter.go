@@ -65,6 +65,7 @@ func ReportTxWithValidity(layerID types.LayerID, tx *types.Transaction, valid bo
 		Valid:       valid,
 	}
 	if reporter != nil {
+		reportStream(StreamTransaction, StreamTransactionData{ID: tx.ID.String(), Layer: layerID, Valid: valid})
 		if err := reporter.transactionEmitter.Emit(txWithValidity); err != nil {
 			// TODO(nkryuchkov): consider returning an error and log outside the function
 			log.With().Error("Failed to emit transaction", tx.ID, layerID, log.Err(err))
@@ -81,6 +82,12 @@ func ReportNewActivation(activation *types.VerifiedActivationTx) {
 
 	activationTxEvent := ActivationTx{VerifiedActivationTx: activation}
 	if reporter != nil {
+		reportStream(StreamActivation, StreamActivationData{
+			ID:           activation.ID().String(),
+			Smesher:      activation.SmesherID.String(),
+			PublishEpoch: activation.PublishEpoch,
+			NumUnits:     activation.NumUnits,
+		})
 		if err := reporter.activationEmitter.Emit(activationTxEvent); err != nil {
 			// TODO(nkryuchkov): consider returning an error and log outside the function
 			log.With().Error("Failed to emit activation", activation.ID(), activation.PublishEpoch, log.Err(err))
@@ -94,6 +101,12 @@ func ReportRewardReceived(r Reward) {
 	defer mu.RUnlock()
 
 	if reporter != nil {
+		reportStream(StreamReward, StreamRewardData{
+			Layer:       r.Layer,
+			Total:       r.Total,
+			LayerReward: r.LayerReward,
+			Coinbase:    r.Coinbase.String(),
+		})
 		if err := reporter.rewardEmitter.Emit(r); err != nil {
 			// TODO(nkryuchkov): consider returning an error and log outside the function
 			log.With().Error("Failed to emit rewards", r.Layer, log.Err(err))
@@ -109,6 +122,7 @@ func ReportLayerUpdate(layer LayerUpdate) {
 	defer mu.RUnlock()
 
 	if reporter != nil {
+		reportStream(StreamLayer, StreamLayerData{Layer: layer.LayerID, Status: layer.Status})
 		if err := reporter.layerEmitter.Emit(layer); err != nil {
 			// TODO(nkryuchkov): consider returning an error and log outside the function
 			log.With().Error("Failed to emit updated layer", layer, log.Err(err))
@@ -124,6 +138,7 @@ func ReportError(err NodeError) {
 	defer mu.RUnlock()
 
 	if reporter != nil {
+		reportStream(StreamError, StreamErrorData{Msg: err.Msg, Level: err.Level.String()})
 		if err := reporter.errorEmitter.Emit(err); err != nil {
 			// TODO(nkryuchkov): consider returning an error and log outside the function
 			log.With().Error("Failed to emit error", log.Err(err))
@@ -420,11 +435,13 @@ type EventReporter struct {
 	rewardEmitter      event.Emitter
 	resultsEmitter     event.Emitter
 	proposalsEmitter   event.Emitter
+	reorgEmitter       event.Emitter
 	events             struct {
 		sync.Mutex
 		buf     *Ring[UserEvent]
 		emitter event.Emitter
 	}
+	stream   eventStream
 	stopChan chan struct{}
 }
 
@@ -484,10 +501,18 @@ func newEventReporter() *EventReporter {
 	if err != nil {
 		log.With().Panic("failed to to create proposal emitter", log.Err(err))
 	}
+	reorgEmitter, err := bus.Emitter(new(EventReorg))
+	if err != nil {
+		log.With().Panic("failed to create reorg emitter", log.Err(err))
+	}
 	eventsEmitter, err := bus.Emitter(new(UserEvent))
 	if err != nil {
 		log.With().Panic("failed to to create proposal emitter", log.Err(err))
 	}
+	streamEmitter, err := bus.Emitter(new(StreamEvent))
+	if err != nil {
+		log.With().Panic("failed to create stream emitter", log.Err(err))
+	}
 
 	reporter := &EventReporter{
 		bus:                bus,
@@ -500,10 +525,13 @@ func newEventReporter() *EventReporter {
 		resultsEmitter:     resultsEmitter,
 		errorEmitter:       errorEmitter,
 		proposalsEmitter:   proposalsEmitter,
+		reorgEmitter:       reorgEmitter,
 		stopChan:           make(chan struct{}),
 	}
 	reporter.events.buf = newRing[UserEvent](100)
 	reporter.events.emitter = eventsEmitter
+	reporter.stream.buf = newRing[StreamEvent](streamBufferSize)
+	reporter.stream.emitter = streamEmitter
 	return reporter
 }
 
@@ -539,6 +567,12 @@ func CloseEventReporter() {
 		if err := reporter.proposalsEmitter.Close(); err != nil {
 			log.With().Panic("failed to close propoposalsEmitter", log.Err(err))
 		}
+		if err := reporter.reorgEmitter.Close(); err != nil {
+			log.With().Panic("failed to close reorgEmitter", log.Err(err))
+		}
+		if err := reporter.stream.emitter.Close(); err != nil {
+			log.With().Panic("failed to close stream emitter", log.Err(err))
+		}
 
 		close(reporter.stopChan)
 		reporter = nil
@@ -593,7 +627,7 @@ func (r *Ring[T]) Iterate(iter func(val T) bool) {
 	if r.last == -1 {
 		return
 	}
-	if r.last > r.first {
+	if r.last >= r.first {
 		for i := r.first; i <= r.last; i++ {
 			if !iter(r.data[i]) {
 				return
@@ -51,6 +51,16 @@ func TestRingBuffer(t *testing.T) {
 		})
 		require.Equal(t, terminate, expect)
 	})
+	t.Run("single", func(t *testing.T) {
+		buffer := newRing[int](cap)
+		buffer.insert(1)
+		var values []int
+		buffer.Iterate(func(val int) bool {
+			values = append(values, val)
+			return true
+		})
+		require.Equal(t, []int{1}, values)
+	})
 	t.Run("not full", func(t *testing.T) {
 		buffer := newRing[int](cap)
 		for i := 0; i < cap/2; i++ {
@@ -0,0 +1,216 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/libp2p/go-libp2p/core/event"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log"
+)
+
+// streamBufferSize is the number of the most recent stream events that are replayed to subscribers.
+const streamBufferSize = 10000
+
+// ErrCursorExpired is returned if the events after the cursor are no longer buffered.
+var ErrCursorExpired = errors.New("events after the cursor are no longer buffered")
+
+// StreamKind is the kind of the event in the unified event stream.
+type StreamKind string
+
+const (
+	StreamLayer       StreamKind = "layer"
+	StreamBlock       StreamKind = "block"
+	StreamTransaction StreamKind = "transaction"
+	StreamReward      StreamKind = "reward"
+	StreamActivation  StreamKind = "activation"
+	StreamError       StreamKind = "error"
+	StreamTxReplaced  StreamKind = "tx_replaced"
+	StreamTxDropped   StreamKind = "tx_dropped"
+)
+
+// ParseStreamKind parses the kind of the event in the unified event stream.
+func ParseStreamKind(value string) (StreamKind, error) {
+	switch kind := StreamKind(value); kind {
+	case StreamLayer, StreamBlock, StreamTransaction, StreamReward, StreamActivation, StreamError, StreamTxReplaced, StreamTxDropped:
+		return kind, nil
+	default:
+		return "", fmt.Errorf("unknown event kind %q", value)
+	}
+}
+
+// StreamEvent is an event in the unified event stream. Cursor increases by one with every event,
+// subscribers pass the cursor of the last received event to replay the events they missed.
+type StreamEvent struct {
+	Cursor uint64     `json:"cursor"`
+	Kind   StreamKind `json:"kind"`
+	Data   any        `json:"data"`
+}
+
+// StreamLayerData is the data of the layer event.
+type StreamLayerData struct {
+	Layer  types.LayerID `json:"layer"`
+	Status int           `json:"status"`
+}
+
+// StreamBlockData is the data of the event for the block applied to the state.
+type StreamBlockData struct {
+	Layer types.LayerID `json:"layer"`
+	Block string        `json:"block"`
+}
+
+// StreamTransactionData is the data of the transaction event.
+type StreamTransactionData struct {
+	ID    string        `json:"id"`
+	Layer types.LayerID `json:"layer"`
+	Valid bool          `json:"valid"`
+}
+
+// StreamRewardData is the data of the reward event.
+type StreamRewardData struct {
+	Layer       types.LayerID `json:"layer"`
+	Total       uint64        `json:"total"`
+	LayerReward uint64        `json:"layer_reward"`
+	Coinbase    string        `json:"coinbase"`
+}
+
+// StreamActivationData is the data of the activation event.
+type StreamActivationData struct {
+	ID           string        `json:"id"`
+	Smesher      string        `json:"smesher"`
+	PublishEpoch types.EpochID `json:"publish_epoch"`
+	NumUnits     uint32        `json:"num_units"`
+}
+
+// StreamErrorData is the data of the error event.
+type StreamErrorData struct {
+	Msg   string `json:"msg"`
+	Level string `json:"level"`
+}
+
+// StreamTxReplacedData is the data of the event for the pending transaction replaced in the mempool
+// by the transaction with the same principal and nonce and a higher fee.
+type StreamTxReplacedData struct {
+	Principal string `json:"principal"`
+	Nonce     uint64 `json:"nonce"`
+	Replaced  string `json:"replaced"`
+	By        string `json:"by"`
+}
+
+// StreamTxDroppedData is the data of the event for the transaction submitted via api of this node
+// that is no longer rebroadcast, as it was not confirmed after the max number of retries.
+type StreamTxDroppedData struct {
+	ID        string `json:"id"`
+	Principal string `json:"principal"`
+	Nonce     uint64 `json:"nonce"`
+	Retries   int    `json:"retries"`
+}
+
+type eventStream struct {
+	sync.Mutex
+	cursor  uint64
+	buf     *Ring[StreamEvent]
+	emitter event.Emitter
+}
+
+func (r *EventReporter) emitStreamEvent(kind StreamKind, data any) error {
+	r.stream.Lock()
+	defer r.stream.Unlock()
+	r.stream.cursor++
+	ev := StreamEvent{Cursor: r.stream.cursor, Kind: kind, Data: data}
+	r.stream.buf.insert(ev)
+	return r.stream.emitter.Emit(ev)
+}
+
+func (r *EventReporter) subStream(cursor uint64, kinds []StreamKind, opts ...SubOpt) (*BufferedSubscription[StreamEvent], []StreamEvent, error) {
+	match := func(ev *StreamEvent) bool {
+		if len(kinds) == 0 {
+			return true
+		}
+		for _, kind := range kinds {
+			if ev.Kind == kind {
+				return true
+			}
+		}
+		return false
+	}
+	r.stream.Lock()
+	defer r.stream.Unlock()
+	var (
+		replay []StreamEvent
+		oldest uint64
+	)
+	r.stream.buf.Iterate(func(ev StreamEvent) bool {
+		if oldest == 0 {
+			oldest = ev.Cursor
+		}
+		if ev.Cursor > cursor && match(&ev) {
+			replay = append(replay, ev)
+		}
+		return true
+	})
+	if cursor != 0 && cursor+1 < oldest {
+		return nil, nil, fmt.Errorf("%w: %d", ErrCursorExpired, cursor)
+	}
+	sub, err := SubscribeMatched(match, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sub, replay, nil
+}
+
+// SubscribeStream subscribes to the events of the kinds in the unified event stream, or to all events if no
+// kinds are passed. it returns buffered events after the cursor, which should be consumed before the subscription.
+// pass the cursor of the last received event to replay missed events after reconnect, or zero to replay
+// all buffered events. ErrCursorExpired is returned if some events after the cursor are no longer buffered.
+func SubscribeStream(cursor uint64, kinds []StreamKind, opts ...SubOpt) (*BufferedSubscription[StreamEvent], []StreamEvent, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+	if reporter == nil {
+		return nil, nil, nil
+	}
+	return reporter.subStream(cursor, kinds, opts...)
+}
+
+func reportStream(kind StreamKind, data any) {
+	if reporter == nil {
+		return
+	}
+	if err := reporter.emitStreamEvent(kind, data); err != nil {
+		// not logged as an error, as errors are reported to the stream
+		log.With().Warning("failed to emit stream event", log.String("kind", string(kind)), log.Err(err))
+	}
+}
+
+// ReportBlockApplied reports the block applied to the state in the layer.
+func ReportBlockApplied(lid types.LayerID, bid types.BlockID) {
+	mu.RLock()
+	defer mu.RUnlock()
+	reportStream(StreamBlock, StreamBlockData{Layer: lid, Block: bid.String()})
+}
+
+// ReportTxReplaced reports the pending transaction replaced in the mempool by the one with the higher fee.
+func ReportTxReplaced(principal types.Address, nonce uint64, replaced, by types.TransactionID) {
+	mu.RLock()
+	defer mu.RUnlock()
+	reportStream(StreamTxReplaced, StreamTxReplacedData{
+		Principal: principal.String(),
+		Nonce:     nonce,
+		Replaced:  replaced.String(),
+		By:        by.String(),
+	})
+}
+
+// ReportTxDropped reports the local transaction that is no longer rebroadcast.
+func ReportTxDropped(id types.TransactionID, principal types.Address, nonce uint64, retries int) {
+	mu.RLock()
+	defer mu.RUnlock()
+	reportStream(StreamTxDropped, StreamTxDroppedData{
+		ID:        id.String(),
+		Principal: principal.String(),
+		Nonce:     nonce,
+		Retries:   retries,
+	})
+}
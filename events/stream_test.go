@@ -0,0 +1,54 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+)
+
+func TestSubscribeStream(t *testing.T) {
+	InitializeReporter()
+	t.Cleanup(CloseEventReporter)
+
+	for i := 1; i <= 3; i++ {
+		ReportLayerUpdate(LayerUpdate{LayerID: types.LayerID(i)})
+	}
+	ReportBlockApplied(types.LayerID(3), types.BlockID{1})
+
+	sub, replay, err := SubscribeStream(1, []StreamKind{StreamLayer})
+	require.NoError(t, err)
+	defer sub.Close()
+	require.Equal(t, []StreamEvent{
+		{Cursor: 2, Kind: StreamLayer, Data: StreamLayerData{Layer: 2}},
+		{Cursor: 3, Kind: StreamLayer, Data: StreamLayerData{Layer: 3}},
+	}, replay)
+
+	ReportBlockApplied(types.LayerID(4), types.BlockID{2})
+	ReportLayerUpdate(LayerUpdate{LayerID: types.LayerID(4)})
+	select {
+	case ev := <-sub.Out():
+		require.Equal(t, StreamEvent{Cursor: 6, Kind: StreamLayer, Data: StreamLayerData{Layer: 4}}, ev)
+	case <-time.After(time.Second):
+		require.Fail(t, "timed out waiting for event")
+	}
+}
+
+func TestSubscribeStreamExpired(t *testing.T) {
+	InitializeReporter()
+	t.Cleanup(CloseEventReporter)
+
+	for i := 0; i <= streamBufferSize+1; i++ {
+		ReportLayerUpdate(LayerUpdate{LayerID: types.LayerID(i)})
+	}
+	_, _, err := SubscribeStream(1, nil)
+	require.ErrorIs(t, err, ErrCursorExpired)
+
+	sub, replay, err := SubscribeStream(0, nil)
+	require.NoError(t, err)
+	defer sub.Close()
+	require.Len(t, replay, streamBufferSize)
+	require.Equal(t, uint64(3), replay[0].Cursor)
+}
@@ -1,7 +1,6 @@
 package fetch
 
 import (
-	"math/rand"
 	"sync"
 
 	lru "github.com/hashicorp/golang-lru/v2"
@@ -71,24 +70,20 @@ func (hpc *HashPeersCache) Add(hash types.Hash32, peer p2p.Peer) {
 	hpc.add(hash, peer)
 }
 
-// GetRandom returns a random peer for a given hash.
-func (hpc *HashPeersCache) GetRandom(hash types.Hash32, hint datastore.Hint, rng *rand.Rand) (p2p.Peer, bool) {
+// GetPeers returns the peers registered for a given hash.
+func (hpc *HashPeersCache) GetPeers(hash types.Hash32, hint datastore.Hint) ([]p2p.Peer, bool) {
 	hpc.mu.Lock()
 	defer hpc.mu.Unlock()
 
 	hashPeersMap, exists := hpc.getWithStats(hash, hint)
-	if !exists {
-		return p2p.NoPeer, false
+	if !exists || len(hashPeersMap) == 0 {
+		return nil, false
 	}
-	n := rng.Intn(len(hashPeersMap)) + 1
-	i := 0
+	peers := make([]p2p.Peer, 0, len(hashPeersMap))
 	for peer := range hashPeersMap {
-		i++
-		if i == n {
-			return peer, true
-		}
+		peers = append(peers, peer)
 	}
-	return p2p.NoPeer, false
+	return peers, true
 }
 
 // RegisterPeerHashes registers provided peer for a list of hashes.
@@ -1,10 +1,8 @@
 package fetch
 
 import (
-	"math/rand"
 	"sync"
 	"testing"
-	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -70,7 +68,7 @@ func TestAdd(t *testing.T) {
 	})
 }
 
-func TestGetRandom(t *testing.T) {
+func TestGetPeers(t *testing.T) {
 	t.Parallel()
 	t.Run("1Hash3Peers", func(t *testing.T) {
 		cache := NewHashPeersCache(10)
@@ -93,10 +91,9 @@ func TestGetRandom(t *testing.T) {
 			cache.Add(hash, peer3)
 		}()
 		wg.Wait()
-		rng := rand.New(rand.NewSource(time.Now().UnixNano()))
-		peer, exists := cache.GetRandom(hash, datastore.TXDB, rng)
+		peers, exists := cache.GetPeers(hash, datastore.TXDB)
 		require.Equal(t, true, exists)
-		require.Contains(t, []p2p.Peer{peer1, peer2, peer3}, peer)
+		require.ElementsMatch(t, []p2p.Peer{peer1, peer2, peer3}, peers)
 	})
 	t.Run("2Hashes1Peer", func(t *testing.T) {
 		cache := NewHashPeersCache(10)
@@ -114,13 +111,12 @@ func TestGetRandom(t *testing.T) {
 			cache.Add(hash2, peer)
 		}()
 		wg.Wait()
-		rng := rand.New(rand.NewSource(time.Now().UnixNano()))
-		randomPeer, exists := cache.GetRandom(hash1, datastore.TXDB, rng)
+		peers, exists := cache.GetPeers(hash1, datastore.TXDB)
 		require.Equal(t, true, exists)
-		require.Equal(t, peer, randomPeer)
-		randomPeer, exists = cache.GetRandom(hash2, datastore.TXDB, rng)
+		require.Equal(t, []p2p.Peer{peer}, peers)
+		peers, exists = cache.GetPeers(hash2, datastore.TXDB)
 		require.Equal(t, true, exists)
-		require.Equal(t, peer, randomPeer)
+		require.Equal(t, []p2p.Peer{peer}, peers)
 	})
 }
 
@@ -147,7 +143,6 @@ func TestRace(t *testing.T) {
 	hash := types.RandomHash()
 	peer1 := p2p.Peer("test_peer_1")
 	peer2 := p2p.Peer("test_peer_2")
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
 	var wg sync.WaitGroup
 	wg.Add(4)
 	go func() {
@@ -156,7 +151,7 @@ func TestRace(t *testing.T) {
 	}()
 	go func() {
 		defer wg.Done()
-		cache.GetRandom(hash, datastore.TXDB, rng)
+		cache.GetPeers(hash, datastore.TXDB)
 	}()
 	go func() {
 		defer wg.Done()
@@ -164,7 +159,7 @@ func TestRace(t *testing.T) {
 	}()
 	go func() {
 		defer wg.Done()
-		cache.GetRandom(hash, datastore.TXDB, rng)
+		cache.GetPeers(hash, datastore.TXDB)
 	}()
 	wg.Wait()
 }
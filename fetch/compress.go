@@ -0,0 +1,78 @@
+package fetch
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spacemeshos/go-spacemesh/p2p/server"
+)
+
+// responses on the compressed protocol are prefixed with the encoding of the rest of the response.
+const (
+	encodingRaw byte = iota
+	encodingFlate
+)
+
+// maxDecompressedSize limits the memory used to decompress a response from a malicious peer.
+const maxDecompressedSize = 128 << 20
+
+var errDecompressedTooLarge = errors.New("decompressed response is too large")
+
+// compressed wraps the handler to compress its responses that are at least threshold bytes long.
+func compressed(handler server.Handler, threshold int) server.Handler {
+	return func(ctx context.Context, req []byte) ([]byte, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return compress(resp, threshold)
+	}
+}
+
+func compress(data []byte, threshold int) ([]byte, error) {
+	if len(data) < threshold {
+		hashRespBytes.WithLabelValues("raw").Add(float64(len(data)))
+		return append([]byte{encodingRaw}, data...), nil
+	}
+	var buf bytes.Buffer
+	buf.WriteByte(encodingFlate)
+	w, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	if err != nil {
+		return nil, fmt.Errorf("create compressor: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, fmt.Errorf("compress: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("compress: %w", err)
+	}
+	hashRespBytes.WithLabelValues("flate").Add(float64(buf.Len()))
+	return buf.Bytes(), nil
+}
+
+func decompress(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, errors.New("empty response")
+	}
+	switch data[0] {
+	case encodingRaw:
+		return data[1:], nil
+	case encodingFlate:
+		r := flate.NewReader(bytes.NewReader(data[1:]))
+		defer r.Close()
+		out, err := io.ReadAll(io.LimitReader(r, maxDecompressedSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("decompress: %w", err)
+		}
+		if len(out) > maxDecompressedSize {
+			return nil, errDecompressedTooLarge
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("unknown response encoding %d", data[0])
+	}
+}
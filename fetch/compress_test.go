@@ -0,0 +1,36 @@
+package fetch
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompress(t *testing.T) {
+	data := bytes.Repeat([]byte("spacemesh"), 1000)
+
+	raw, err := compress(data, len(data)+1)
+	require.NoError(t, err)
+	require.Equal(t, encodingRaw, raw[0])
+	got, err := decompress(raw)
+	require.NoError(t, err)
+	require.Equal(t, data, got)
+
+	compressed, err := compress(data, len(data))
+	require.NoError(t, err)
+	require.Equal(t, encodingFlate, compressed[0])
+	require.Less(t, len(compressed), len(data))
+	got, err = decompress(compressed)
+	require.NoError(t, err)
+	require.Equal(t, data, got)
+}
+
+func TestDecompress_Invalid(t *testing.T) {
+	_, err := decompress(nil)
+	require.Error(t, err)
+	_, err = decompress([]byte{7, 1, 2})
+	require.Error(t, err)
+	_, err = decompress([]byte{encodingFlate, 1, 2, 3})
+	require.Error(t, err)
+}
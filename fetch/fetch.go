@@ -6,9 +6,13 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"sort"
 	"sync"
 	"time"
 
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/multiformats/go-multistream"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/spacemeshos/go-spacemesh/codec"
@@ -16,19 +20,25 @@ import (
 	"github.com/spacemeshos/go-spacemesh/datastore"
 	"github.com/spacemeshos/go-spacemesh/log"
 	"github.com/spacemeshos/go-spacemesh/p2p"
+	"github.com/spacemeshos/go-spacemesh/p2p/pubsub"
 	"github.com/spacemeshos/go-spacemesh/p2p/server"
 	"github.com/spacemeshos/go-spacemesh/system"
 )
 
 const (
-	atxProtocol      = "ax/1"
-	lyrDataProtocol  = "ld/1"
-	lyrOpnsProtocol  = "lp/1"
-	hashProtocol     = "hs/1"
-	meshHashProtocol = "mh/1"
-	malProtocol      = "ml/1"
+	atxProtocol     = "ax/1"
+	atxPageProtocol = "ap/1"
+	lyrDataProtocol = "ld/1"
+	lyrOpnsProtocol = "lp/1"
+	hashProtocol    = "hs/1"
+	// hashCompressedProtocol is the same as hashProtocol, but responses may be compressed.
+	hashCompressedProtocol = "hc/1"
+	meshHashProtocol       = "mh/1"
+	malProtocol            = "ml/1"
 
 	cacheSize = 1000
+	// quarantineSize is the number of quarantined hashes that are remembered.
+	quarantineSize = 10000
 )
 
 var (
@@ -36,6 +46,11 @@ var (
 	errExceedMaxRetries = errors.New("fetch failed after max retries for request")
 
 	errValidatorsNotSet = errors.New("validators not set")
+
+	// ErrQuarantined is returned for hashes that recently failed validation from every peer that served them.
+	ErrQuarantined = errors.New("hash is quarantined after failed validation")
+	// errValidatorConflict is returned if the hash is already requested with a different validator.
+	errValidatorConflict = errors.New("hash is requested with a different validator")
 )
 
 // request contains all relevant Data for a single request for a specified hash.
@@ -46,6 +61,11 @@ type request struct {
 	validator dataReceiver
 	promise   *promise
 	retries   int
+	priority  Priority
+	// invalidFrom contains peers that served data that failed validation.
+	invalidFrom map[p2p.Peer]struct{}
+	// custom is true if the data is passed to the validator of the caller instead of the registered one.
+	custom bool
 }
 
 type promise struct {
@@ -56,6 +76,7 @@ type promise struct {
 type batchInfo struct {
 	RequestBatch
 	peer p2p.Peer
+	sent time.Time
 }
 
 // setID calculates the hash of all requests and sets it as this batches ID.
@@ -82,6 +103,16 @@ type Config struct {
 	BatchSize, QueueSize int
 	RequestTimeout       time.Duration // in seconds
 	MaxRetriesForRequest int
+	// RequestCompression requests peers to compress responses to hash requests.
+	RequestCompression bool
+	// CompressionThreshold is the minimal size in bytes of the response to a hash request that is compressed.
+	CompressionThreshold int
+	// DownloadLimit and UploadLimit cap the bytes per second received and sent by fetch protocols.
+	// gossip is not limited by them. 0 means unlimited.
+	DownloadLimit, UploadLimit int
+	// QuarantineCooldown is the duration for which requests for a hash are rejected once its data
+	// failed validation from every peer it was requested from. 0 disables the quarantine.
+	QuarantineCooldown time.Duration
 }
 
 // DefaultConfig is the default config for the fetch component.
@@ -93,14 +124,11 @@ func DefaultConfig() Config {
 		BatchSize:            20,
 		RequestTimeout:       time.Second * time.Duration(10),
 		MaxRetriesForRequest: 100,
+		CompressionThreshold: 1024,
+		QuarantineCooldown:   10 * time.Minute,
 	}
 }
 
-// randomPeer returns a random peer from current peer list.
-func randomPeer(peers []p2p.Peer) p2p.Peer {
-	return peers[rand.Intn(len(peers))]
-}
-
 // Option is a type to configure a fetcher.
 type Option func(*Fetch)
 
@@ -152,11 +180,18 @@ type Fetch struct {
 	// ongoing contains requests that have been processed and are waiting for responses
 	ongoing map[types.Hash32]*request
 	// batched contains batched ongoing requests.
-	batched      map[types.Hash32]*batchInfo
+	batched map[types.Hash32]*batchInfo
+	// inflight contains requests to peers that wait for responses, by protocol, peer and request.
+	inflight     map[string]*inflightRequest
+	inflightMu   sync.Mutex
 	batchTimeout *time.Ticker
 	mu           sync.Mutex
 	onlyOnce     sync.Once
 	hashToPeers  *HashPeersCache
+	peers        *peerStats
+	bandwidth    *server.Bandwidth
+	// quarantine contains the time until which requests for the hash are rejected.
+	quarantine *lru.Cache[types.Hash32, time.Time]
 
 	shutdownCtx context.Context
 	cancel      context.CancelFunc
@@ -175,23 +210,35 @@ func NewFetch(cdb *datastore.CachedDB, msh meshProvider, b system.BeaconGetter,
 		unprocessed: make(map[types.Hash32]*request),
 		ongoing:     make(map[types.Hash32]*request),
 		batched:     make(map[types.Hash32]*batchInfo),
+		inflight:    make(map[string]*inflightRequest),
 		hashToPeers: NewHashPeersCache(cacheSize),
+		peers:       newPeerStats(peerStatsSize),
 	}
+	quarantine, err := lru.New[types.Hash32, time.Time](quarantineSize)
+	if err != nil {
+		log.Panic("could not initialize cache ", err)
+	}
+	f.quarantine = quarantine
 	for _, opt := range opts {
 		opt(f)
 	}
 
 	f.batchTimeout = time.NewTicker(f.cfg.BatchTimeout)
+	f.bandwidth = server.NewBandwidth(f.cfg.DownloadLimit, f.cfg.UploadLimit)
 	srvOpts := []server.Opt{
 		server.WithTimeout(f.cfg.RequestTimeout),
 		server.WithLog(f.logger),
+		server.WithBandwidth(f.bandwidth),
 	}
 	if len(f.servers) == 0 {
 		h := newHandler(cdb, bs, msh, b, f.logger)
 		f.servers[atxProtocol] = server.New(host, atxProtocol, h.handleEpochInfoReq, srvOpts...)
+		f.servers[atxPageProtocol] = server.New(host, atxPageProtocol, h.handleEpochPageReq, srvOpts...)
 		f.servers[lyrDataProtocol] = server.New(host, lyrDataProtocol, h.handleLayerDataReq, srvOpts...)
 		f.servers[lyrOpnsProtocol] = server.New(host, lyrOpnsProtocol, h.handleLayerOpinionsReq, srvOpts...)
 		f.servers[hashProtocol] = server.New(host, hashProtocol, h.handleHashReq, srvOpts...)
+		f.servers[hashCompressedProtocol] = server.New(host, hashCompressedProtocol,
+			compressed(h.handleHashReq, f.cfg.CompressionThreshold), srvOpts...)
 		f.servers[meshHashProtocol] = server.New(host, meshHashProtocol, h.handleMeshHashReq, srvOpts...)
 		f.servers[malProtocol] = server.New(host, malProtocol, h.handleMaliciousIDsReq, srvOpts...)
 	}
@@ -232,6 +279,23 @@ func (f *Fetch) SetValidators(
 	}
 }
 
+// SetBandwidthLimits updates the bytes per second received and sent by fetch protocols. 0 means unlimited.
+func (f *Fetch) SetBandwidthLimits(download, upload int) {
+	f.bandwidth.SetLimits(download, upload)
+}
+
+// BandwidthLimits returns the bytes per second received and sent by fetch protocols.
+func (f *Fetch) BandwidthLimits() (download, upload int) {
+	return f.bandwidth.Limits()
+}
+
+// QueueDepths returns the number of hash requests waiting to be sent and waiting for responses.
+func (f *Fetch) QueueDepths() (unprocessed, ongoing int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.unprocessed), len(f.ongoing)
+}
+
 // Start starts handling fetch requests.
 func (f *Fetch) Start() error {
 	if f.validators == nil {
@@ -294,6 +358,16 @@ func (f *Fetch) loop() {
 	}
 }
 
+// receiveCompressedResponse decompresses the response received on the compressed protocol.
+func (f *Fetch) receiveCompressedResponse(data []byte) {
+	decompressed, err := decompress(data)
+	if err != nil {
+		f.logger.With().Warning("failed to decompress batch response", log.Err(err))
+		return
+	}
+	f.receiveResponse(decompressed)
+}
+
 // receive Data from message server and call response handlers accordingly.
 func (f *Fetch) receiveResponse(data []byte) {
 	if f.stopped() {
@@ -320,6 +394,7 @@ func (f *Fetch) receiveResponse(data []byte) {
 			log.Stringer("batch_hash", response.ID))
 		return
 	}
+	f.peers.onSuccess(batch.peer, time.Since(batch.sent))
 
 	batchMap := batch.toMap()
 	// iterate all hash Responses
@@ -338,7 +413,11 @@ func (f *Fetch) receiveResponse(data []byte) {
 		rsp := resp
 		f.eg.Go(func() error {
 			// validation fetch data recursively. offload to another goroutine
-			f.hashValidationDone(rsp.Hash, req.validator(req.ctx, rsp.Hash, batch.peer, rsp.Data))
+			err := req.validator(req.ctx, rsp.Hash, batch.peer, rsp.Data)
+			if errors.Is(err, pubsub.ErrValidationReject) {
+				f.peers.onInvalid(batch.peer)
+			}
+			f.hashValidationDone(rsp.Hash, batch.peer, err)
 			return nil
 		})
 		delete(batchMap, resp.Hash)
@@ -356,7 +435,7 @@ func (f *Fetch) receiveResponse(data []byte) {
 	}
 }
 
-func (f *Fetch) hashValidationDone(hash types.Hash32, err error) {
+func (f *Fetch) hashValidationDone(hash types.Hash32, peer p2p.Peer, err error) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
@@ -365,6 +444,26 @@ func (f *Fetch) hashValidationDone(hash types.Hash32, err error) {
 		f.logger.With().Error("validation ran for unknown hash", log.Stringer("hash", hash))
 		return
 	}
+	delete(f.ongoing, hash)
+	if errors.Is(err, pubsub.ErrValidationReject) {
+		f.logger.WithContext(req.ctx).With().Warning("peer served invalid data",
+			log.String("hint", string(req.hint)),
+			log.Stringer("hash", hash),
+			log.Stringer("peer", peer),
+			log.Err(err),
+		)
+		if req.invalidFrom == nil {
+			req.invalidFrom = map[p2p.Peer]struct{}{}
+		}
+		req.invalidFrom[peer] = struct{}{}
+		req.retries++
+		if req.retries <= f.cfg.MaxRetriesForRequest && len(f.candidatePeers(req)) > 0 {
+			// retry from a peer that didn't serve invalid data
+			f.unprocessed[hash] = req
+			return
+		}
+		f.quarantineHash(req)
+	}
 	if err != nil {
 		req.promise.err = err
 	} else {
@@ -372,7 +471,55 @@ func (f *Fetch) hashValidationDone(hash types.Hash32, err error) {
 			log.Stringer("hash", hash))
 	}
 	close(req.promise.completed)
-	delete(f.ongoing, hash)
+}
+
+// candidatePeers returns peers that can be asked for the hash, excluding those that served invalid data.
+func (f *Fetch) candidatePeers(req *request) []p2p.Peer {
+	peers, ok := f.hashToPeers.GetPeers(req.hash, req.hint)
+	if !ok {
+		peers = f.host.GetPeers()
+	}
+	var candidates []p2p.Peer
+	for _, peer := range peers {
+		if _, ok := req.invalidFrom[peer]; !ok {
+			candidates = append(candidates, peer)
+		}
+	}
+	if len(candidates) == 0 && ok {
+		// none of the peers known to have the hash served valid data, try any other peer
+		for _, peer := range f.host.GetPeers() {
+			if _, ok := req.invalidFrom[peer]; !ok {
+				candidates = append(candidates, peer)
+			}
+		}
+	}
+	return candidates
+}
+
+func (f *Fetch) quarantineHash(req *request) {
+	if f.cfg.QuarantineCooldown == 0 {
+		return
+	}
+	f.logger.WithContext(req.ctx).With().Warning("quarantined hash after failed validation",
+		log.String("hint", string(req.hint)),
+		log.Stringer("hash", req.hash),
+		log.Int("invalid_peers", len(req.invalidFrom)),
+		log.Duration("cooldown", f.cfg.QuarantineCooldown),
+	)
+	f.quarantine.Add(req.hash, time.Now().Add(f.cfg.QuarantineCooldown))
+	hashQuarantined.WithLabelValues(string(req.hint)).Inc()
+}
+
+func (f *Fetch) quarantined(hash types.Hash32) bool {
+	until, ok := f.quarantine.Get(hash)
+	if !ok {
+		return false
+	}
+	if time.Now().After(until) {
+		f.quarantine.Remove(hash)
+		return false
+	}
+	return true
 }
 
 func (f *Fetch) failAfterRetry(hash types.Hash32) {
@@ -413,18 +560,38 @@ func (f *Fetch) requestHashBatchFromPeers() {
 	f.send(requestList)
 }
 
+// getUnprocessed returns queued requests ordered by priority. backfill requests stay in the queue
+// while requests with higher priority are queued, so that they don't compete for the peers.
 func (f *Fetch) getUnprocessed() []RequestMessage {
 	f.mu.Lock()
 	defer f.mu.Unlock()
-	var requestList []RequestMessage
+	deferBackfill := false
+	for _, req := range f.unprocessed {
+		if req.priority > PriorityBackfill {
+			deferBackfill = true
+			break
+		}
+	}
+	var reqs []*request
 	// only send one request per hash
 	for hash, req := range f.unprocessed {
+		if deferBackfill && req.priority == PriorityBackfill {
+			deferredRequests.Inc()
+			continue
+		}
 		f.logger.WithContext(req.ctx).With().Debug("processing hash request", log.Stringer("hash", hash))
-		requestList = append(requestList, RequestMessage{Hash: hash, Hint: req.hint})
+		reqs = append(reqs, req)
 		// move the processed requests to pending
 		f.ongoing[hash] = req
 		delete(f.unprocessed, hash)
 	}
+	sort.SliceStable(reqs, func(i, j int) bool {
+		return reqs[i].priority > reqs[j].priority
+	})
+	requestList := make([]RequestMessage, 0, len(reqs))
+	for _, req := range reqs {
+		requestList = append(requestList, RequestMessage{Hash: req.hash, Hint: req.hint})
+	}
 	return requestList
 }
 
@@ -463,9 +630,13 @@ func (f *Fetch) organizeRequests(requests []RequestMessage) map[p2p.Peer][][]Req
 	}
 
 	for _, req := range requests {
-		p, exists := f.hashToPeers.GetRandom(req.Hash, req.Hint, rng)
-		if !exists {
-			p = randomPeer(peers)
+		var p p2p.Peer
+		if candidates := f.retryPeers(req.Hash); len(candidates) > 0 {
+			p = f.peers.selectPeer(candidates, rng)
+		} else if hashPeers, exists := f.hashToPeers.GetPeers(req.Hash, req.Hint); exists {
+			p = f.peers.selectPeer(hashPeers, rng)
+		} else {
+			p = f.peers.selectPeer(peers, rng)
 		}
 
 		_, ok := peer2requests[p]
@@ -496,8 +667,20 @@ func (f *Fetch) organizeRequests(requests []RequestMessage) map[p2p.Peer][][]Req
 	return result
 }
 
+// retryPeers returns peers that can be asked for the hash which data failed validation from other peers.
+func (f *Fetch) retryPeers(hash types.Hash32) []p2p.Peer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	req, ok := f.ongoing[hash]
+	if !ok || len(req.invalidFrom) == 0 {
+		return nil
+	}
+	return f.candidatePeers(req)
+}
+
 // sendBatch dispatches batched request messages to provided peer.
 func (f *Fetch) sendBatch(p p2p.Peer, batch *batchInfo) error {
+	batch.sent = time.Now()
 	f.mu.Lock()
 	f.batched[batch.ID] = batch
 	f.mu.Unlock()
@@ -505,8 +688,20 @@ func (f *Fetch) sendBatch(p p2p.Peer, batch *batchInfo) error {
 	f.logger.With().Debug("sending batch request",
 		log.Stringer("batch_hash", batch.ID),
 		log.Stringer("peer", batch.peer))
+	proto, receiver := hashProtocol, f.receiveResponse
+	if f.cfg.RequestCompression && f.peers.supportsCompression(p) {
+		proto, receiver = hashCompressedProtocol, f.receiveCompressedResponse
+	}
 	// timeout function will be called if no response was received for the hashes sent
 	errorFunc := func(err error) {
+		if proto == hashCompressedProtocol && errors.Is(err, multistream.ErrNotSupported[protocol.ID]{}) {
+			f.logger.With().Debug("peer doesn't support compression, resending batch",
+				log.Stringer("batch_hash", batch.ID),
+				log.Stringer("peer", p))
+			f.peers.onCompressionUnsupported(p)
+			_ = f.sendBatch(p, batch)
+			return
+		}
 		f.logger.With().Warning("failed to send batch",
 			log.Stringer("batch_hash", batch.ID),
 			log.Err(err))
@@ -530,7 +725,7 @@ func (f *Fetch) sendBatch(p p2p.Peer, batch *batchInfo) error {
 			log.Int("num_requests", len(batch.Requests)),
 			log.Stringer("peer", p))
 
-		err = f.servers[hashProtocol].Request(f.shutdownCtx, p, bytes, f.receiveResponse, errorFunc)
+		err = f.servers[proto].Request(f.shutdownCtx, p, bytes, receiver, errorFunc)
 		if err == nil {
 			break
 		}
@@ -555,6 +750,7 @@ func (f *Fetch) handleHashError(batchHash types.Hash32, err error) {
 		f.logger.With().Error("batch not found", log.Stringer("batch_hash", batchHash))
 		return
 	}
+	f.peers.onFailure(batch.peer)
 	for _, br := range batch.Requests {
 		req, ok := f.ongoing[br.Hash]
 		if !ok {
@@ -574,7 +770,9 @@ func (f *Fetch) handleHashError(batchHash types.Hash32, err error) {
 
 // getHash is the regular buffered call to get a specific hash, using provided hash, h as hint the receiving end will
 // know where to look for the hash, this function returns HashDataPromiseResult channel that will hold Data received or error.
-func (f *Fetch) getHash(ctx context.Context, hash types.Hash32, h datastore.Hint, receiver dataReceiver) (*promise, error) {
+// requests for the same hash are served once, unless the data is passed to a custom validator,
+// as the data would then skip the validator of one of the requests.
+func (f *Fetch) getHash(ctx context.Context, hash types.Hash32, h datastore.Hint, receiver dataReceiver, custom bool) (*promise, error) {
 	if f.stopped() {
 		return nil, f.shutdownCtx.Err()
 	}
@@ -583,12 +781,23 @@ func (f *Fetch) getHash(ctx context.Context, hash types.Hash32, h datastore.Hint
 	if _, err := f.bs.Get(h, hash.Bytes()); err == nil {
 		return nil, nil
 	}
+	if f.quarantined(hash) {
+		return nil, fmt.Errorf("%w: %s", ErrQuarantined, hash)
+	}
 
+	priority := priorityFrom(ctx)
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
+	if req, ok := f.ongoing[hash]; ok && (custom || req.custom) {
+		return nil, fmt.Errorf("%w: %s", errValidatorConflict, hash)
+	}
+	if req, ok := f.unprocessed[hash]; ok && (custom || req.custom) {
+		return nil, fmt.Errorf("%w: %s", errValidatorConflict, hash)
+	}
 	if _, ok := f.ongoing[hash]; ok {
 		f.logger.WithContext(ctx).With().Debug("request ongoing", log.Stringer("hash", hash))
+		dedupHits.WithLabelValues(string(h)).Inc()
 		return f.ongoing[hash].promise, nil
 	}
 
@@ -601,16 +810,23 @@ func (f *Fetch) getHash(ctx context.Context, hash types.Hash32, h datastore.Hint
 			promise: &promise{
 				completed: make(chan struct{}, 1),
 			},
+			priority: priority,
+			custom:   custom,
 		}
 		f.logger.WithContext(ctx).With().Debug("hash request added to queue",
 			log.Stringer("hash", hash),
 			log.Int("queued", len(f.unprocessed)))
 	} else {
+		dedupHits.WithLabelValues(string(h)).Inc()
 		f.logger.WithContext(ctx).With().Debug("hash request already in queue",
 			log.Stringer("hash", hash),
 			log.Int("retries", f.unprocessed[hash].retries),
 			log.Int("queued", len(f.unprocessed)))
+		if req := f.unprocessed[hash]; req.priority < priority {
+			req.priority = priority
+		}
 	}
+	hashRequests.WithLabelValues(priority.String()).Inc()
 	if len(f.unprocessed) >= f.cfg.QueueSize {
 		f.eg.Go(func() error {
 			f.requestHashBatchFromPeers() // Process the batch.
@@ -620,6 +836,56 @@ func (f *Fetch) getHash(ctx context.Context, hash types.Hash32, h datastore.Hint
 	return f.unprocessed[hash].promise, nil
 }
 
+type inflightRequest struct {
+	done chan struct{}
+	data []byte
+	err  error
+}
+
+// peerRequest sends the request on the protocol to the peer and waits for the response.
+// identical requests to the same peer that are made before the response is received are not sent,
+// they wait for the response to the first request instead. the request is not canceled when ctx is done,
+// as other callers may still wait for it.
+func (f *Fetch) peerRequest(ctx context.Context, proto string, peer p2p.Peer, req []byte) ([]byte, error) {
+	key := proto + "/" + peer.String() + "/" + string(req)
+	f.inflightMu.Lock()
+	inflight, ok := f.inflight[key]
+	if !ok {
+		inflight = &inflightRequest{done: make(chan struct{})}
+		f.inflight[key] = inflight
+	}
+	f.inflightMu.Unlock()
+
+	if ok {
+		dedupHits.WithLabelValues(proto).Inc()
+	} else {
+		okCB := func(data []byte) {
+			f.completeRequest(key, inflight, data, nil)
+		}
+		errCB := func(err error) {
+			f.completeRequest(key, inflight, nil, err)
+		}
+		if err := f.servers[proto].Request(f.shutdownCtx, peer, req, okCB, errCB); err != nil {
+			errCB(err)
+		}
+	}
+	select {
+	case <-inflight.done:
+		return inflight.data, inflight.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (f *Fetch) completeRequest(key string, inflight *inflightRequest, data []byte, err error) {
+	f.inflightMu.Lock()
+	delete(f.inflight, key)
+	f.inflightMu.Unlock()
+	inflight.data = data
+	inflight.err = err
+	close(inflight.done)
+}
+
 // RegisterPeerHashes registers provided peer for a list of hashes.
 func (f *Fetch) RegisterPeerHashes(peer p2p.Peer, hashes []types.Hash32) {
 	if peer == f.host.ID() {
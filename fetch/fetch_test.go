@@ -3,11 +3,15 @@ package fetch
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math/rand"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/multiformats/go-multistream"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -30,6 +34,7 @@ type testFetch struct {
 	mLyrS   *mocks.Mockrequester
 	mOpnS   *mocks.Mockrequester
 	mHashS  *mocks.Mockrequester
+	mHashCS *mocks.Mockrequester
 	mMHashS *mocks.Mockrequester
 
 	mMesh        *mocks.MockmeshProvider
@@ -53,6 +58,7 @@ func createFetch(tb testing.TB) *testFetch {
 		mLyrS:        mocks.NewMockrequester(ctrl),
 		mOpnS:        mocks.NewMockrequester(ctrl),
 		mHashS:       mocks.NewMockrequester(ctrl),
+		mHashCS:      mocks.NewMockrequester(ctrl),
 		mMHashS:      mocks.NewMockrequester(ctrl),
 		mMalH:        mocks.NewMockSyncValidator(ctrl),
 		mAtxH:        mocks.NewMockSyncValidator(ctrl),
@@ -70,6 +76,11 @@ func createFetch(tb testing.TB) *testFetch {
 		1000,
 		time.Second * time.Duration(3),
 		3,
+		false,
+		0,
+		0,
+		0,
+		0,
 	}
 	lg := logtest.New(tb)
 	tf.Fetch = NewFetch(datastore.NewCachedDB(sql.InMemory(), lg), tf.mMesh, nil, nil,
@@ -77,12 +88,13 @@ func createFetch(tb testing.TB) *testFetch {
 		WithConfig(cfg),
 		WithLogger(lg),
 		withServers(map[string]requester{
-			malProtocol:      tf.mMalS,
-			atxProtocol:      tf.mAtxS,
-			lyrDataProtocol:  tf.mLyrS,
-			lyrOpnsProtocol:  tf.mOpnS,
-			hashProtocol:     tf.mHashS,
-			meshHashProtocol: tf.mMHashS,
+			malProtocol:            tf.mMalS,
+			atxProtocol:            tf.mAtxS,
+			lyrDataProtocol:        tf.mLyrS,
+			lyrOpnsProtocol:        tf.mOpnS,
+			hashProtocol:           tf.mHashS,
+			hashCompressedProtocol: tf.mHashCS,
+			meshHashProtocol:       tf.mMHashS,
 		}),
 		withHost(tf.mh))
 	tf.Fetch.SetValidators(tf.mAtxH, tf.mPoetH, tf.mBallotH, tf.mBlocksH, tf.mProposalH, tf.mTxBlocksH, tf.mTxProposalH, tf.mMalH)
@@ -120,16 +132,47 @@ func TestFetch_GetHash(t *testing.T) {
 	hint2 := datastore.BallotDB
 
 	// test hash aggregation
-	p0, err := f.getHash(context.TODO(), h1, hint, goodReceiver)
+	p0, err := f.getHash(context.TODO(), h1, hint, goodReceiver, false)
 	require.NoError(t, err)
-	p1, err := f.getHash(context.TODO(), h1, hint, goodReceiver)
+	p1, err := f.getHash(context.TODO(), h1, hint, goodReceiver, false)
 	require.NoError(t, err)
 	require.Equal(t, p0.completed, p1.completed)
 
 	h2 := types.RandomHash()
-	p2, err := f.getHash(context.TODO(), h2, hint2, goodReceiver)
+	p2, err := f.getHash(context.TODO(), h2, hint2, goodReceiver, false)
 	require.NoError(t, err)
 	require.NotEqual(t, p1.completed, p2.completed)
+
+	// requests with a custom validator are not merged with other requests
+	_, err = f.getHash(context.TODO(), h2, hint2, goodReceiver, true)
+	require.ErrorIs(t, err, errValidatorConflict)
+	h3 := types.RandomHash()
+	_, err = f.getHash(context.TODO(), h3, hint2, goodReceiver, true)
+	require.NoError(t, err)
+	_, err = f.getHash(context.TODO(), h3, hint2, goodReceiver, false)
+	require.ErrorIs(t, err, errValidatorConflict)
+}
+
+func TestFetch_Priority(t *testing.T) {
+	f := createFetch(t)
+	backfill := WithPriority(context.Background(), PriorityBackfill)
+	h1, h2, h3 := types.RandomHash(), types.RandomHash(), types.RandomHash()
+	for _, req := range []struct {
+		ctx  context.Context
+		hash types.Hash32
+	}{{backfill, h1}, {backfill, h2}, {context.Background(), h3}} {
+		_, err := f.getHash(req.ctx, req.hash, datastore.BallotDB, goodReceiver, false)
+		require.NoError(t, err)
+	}
+	// backfill requests are deferred while there are requests with higher priority
+	require.Equal(t, []RequestMessage{{Hash: h3, Hint: datastore.BallotDB}}, f.getUnprocessed())
+
+	// priority is raised by the request with higher priority
+	_, err := f.getHash(context.Background(), h2, datastore.BallotDB, goodReceiver, false)
+	require.NoError(t, err)
+	require.Equal(t, PriorityTortoise, f.unprocessed[h2].priority)
+	require.Equal(t, []RequestMessage{{Hash: h2, Hint: datastore.BallotDB}}, f.getUnprocessed())
+	require.Equal(t, []RequestMessage{{Hash: h1, Hint: datastore.BallotDB}}, f.getUnprocessed())
 }
 
 func TestFetch_RequestHashBatchFromPeers(t *testing.T) {
@@ -196,10 +239,10 @@ func TestFetch_RequestHashBatchFromPeers(t *testing.T) {
 				receiver = badReceiver
 			}
 			for i := 0; i < 2; i++ {
-				p, err := f.getHash(context.TODO(), hsh0, datastore.ProposalDB, receiver)
+				p, err := f.getHash(context.TODO(), hsh0, datastore.ProposalDB, receiver, false)
 				require.NoError(t, err)
 				p0 = append(p0, p)
-				p, err = f.getHash(context.TODO(), hsh1, datastore.BlockDB, receiver)
+				p, err = f.getHash(context.TODO(), hsh1, datastore.BlockDB, receiver, false)
 				require.NoError(t, err)
 				p1 = append(p1, p)
 			}
@@ -220,6 +263,149 @@ func TestFetch_RequestHashBatchFromPeers(t *testing.T) {
 	}
 }
 
+func TestFetch_InvalidDataRetriedFromOtherPeers(t *testing.T) {
+	f := createFetch(t)
+	f.cfg.QuarantineCooldown = time.Minute
+	bad, good := p2p.Peer("bad"), p2p.Peer("good")
+	f.mh.EXPECT().ID().Return(p2p.Peer("self")).AnyTimes()
+	f.mh.EXPECT().GetPeers().Return([]p2p.Peer{bad, good}).AnyTimes()
+	hash := types.RandomHash()
+	f.RegisterPeerHashes(bad, []types.Hash32{hash})
+
+	var served []p2p.Peer
+	f.mHashS.EXPECT().Request(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, peer p2p.Peer, req []byte, okFunc func([]byte), _ func(error)) error {
+			served = append(served, peer)
+			var rb RequestBatch
+			require.NoError(t, codec.Decode(req, &rb))
+			bts, err := codec.Encode(&ResponseBatch{
+				ID:        rb.ID,
+				Responses: []ResponseMessage{{Hash: hash, Data: []byte(peer)}},
+			})
+			require.NoError(t, err)
+			okFunc(bts)
+			return nil
+		}).Times(2)
+	receiver := func(_ context.Context, _ types.Hash32, peer p2p.Peer, _ []byte) error {
+		if peer == bad {
+			return pubsub.ErrValidationReject
+		}
+		return nil
+	}
+
+	p, err := f.getHash(context.TODO(), hash, datastore.BlockDB, receiver, false)
+	require.NoError(t, err)
+	f.requestHashBatchFromPeers()
+	require.Eventually(t, func() bool {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		_, ok := f.unprocessed[hash]
+		return ok
+	}, time.Second, 10*time.Millisecond)
+	f.requestHashBatchFromPeers()
+
+	<-p.completed
+	require.NoError(t, p.err)
+	require.Equal(t, []p2p.Peer{bad, good}, served)
+	stat, ok := f.peers.peek(bad)
+	require.True(t, ok)
+	require.InDelta(t, 1, stat.invalid, 0.01)
+	require.False(t, f.quarantined(hash))
+}
+
+func TestFetch_InvalidDataQuarantined(t *testing.T) {
+	f := createFetch(t)
+	f.cfg.QuarantineCooldown = time.Minute
+	bad := p2p.Peer("bad")
+	f.mh.EXPECT().GetPeers().Return([]p2p.Peer{bad}).AnyTimes()
+	hash := types.RandomHash()
+	f.mHashS.EXPECT().Request(gomock.Any(), bad, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ p2p.Peer, req []byte, okFunc func([]byte), _ func(error)) error {
+			var rb RequestBatch
+			require.NoError(t, codec.Decode(req, &rb))
+			bts, err := codec.Encode(&ResponseBatch{
+				ID:        rb.ID,
+				Responses: []ResponseMessage{{Hash: hash, Data: []byte("a")}},
+			})
+			require.NoError(t, err)
+			okFunc(bts)
+			return nil
+		})
+	receiver := func(context.Context, types.Hash32, p2p.Peer, []byte) error {
+		return pubsub.ErrValidationReject
+	}
+
+	p, err := f.getHash(context.TODO(), hash, datastore.BlockDB, receiver, false)
+	require.NoError(t, err)
+	f.requestHashBatchFromPeers()
+	<-p.completed
+	require.ErrorIs(t, p.err, pubsub.ErrValidationReject)
+
+	_, err = f.getHash(context.TODO(), hash, datastore.BlockDB, receiver, false)
+	require.ErrorIs(t, err, ErrQuarantined)
+
+	// cooldown expired
+	f.quarantine.Add(hash, time.Now().Add(-time.Second))
+	p, err = f.getHash(context.TODO(), hash, datastore.BlockDB, receiver, false)
+	require.NoError(t, err)
+	require.NotNil(t, p)
+}
+
+func TestFetch_CompressedResponses(t *testing.T) {
+	f := createFetch(t)
+	f.cfg.RequestCompression = true
+	f.cfg.MaxRetriesForRequest = 0
+	f.cfg.MaxRetriesForPeer = 0
+	peer := p2p.Peer("buddy")
+	f.mh.EXPECT().GetPeers().Return([]p2p.Peer{peer}).AnyTimes()
+
+	respond := func(t *testing.T, req []byte, threshold int) []byte {
+		var rb RequestBatch
+		require.NoError(t, codec.Decode(req, &rb))
+		resps := make([]ResponseMessage, 0, len(rb.Requests))
+		for _, r := range rb.Requests {
+			resps = append(resps, ResponseMessage{Hash: r.Hash, Data: []byte("a")})
+		}
+		bts, err := codec.Encode(&ResponseBatch{ID: rb.ID, Responses: resps})
+		require.NoError(t, err)
+		if threshold < 0 {
+			return bts
+		}
+		compressed, err := compress(bts, threshold)
+		require.NoError(t, err)
+		return compressed
+	}
+	fetchHash := func(t *testing.T) {
+		p, err := f.getHash(context.TODO(), types.RandomHash(), datastore.BlockDB, goodReceiver, false)
+		require.NoError(t, err)
+		f.requestHashBatchFromPeers()
+		<-p.completed
+		require.NoError(t, p.err)
+	}
+
+	f.mHashCS.EXPECT().Request(gomock.Any(), peer, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ p2p.Peer, req []byte, okFunc func([]byte), _ func(error)) error {
+			okFunc(respond(t, req, 0))
+			return nil
+		})
+	fetchHash(t)
+
+	// the peer doesn't support compression, the batch is resent on the uncompressed protocol
+	f.mHashCS.EXPECT().Request(gomock.Any(), peer, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ p2p.Peer, _ []byte, _ func([]byte), errFunc func(error)) error {
+			errFunc(fmt.Errorf("failed to negotiate protocol: %w", multistream.ErrNotSupported[protocol.ID]{}))
+			return nil
+		})
+	f.mHashS.EXPECT().Request(gomock.Any(), peer, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ p2p.Peer, req []byte, okFunc func([]byte), _ func(error)) error {
+			okFunc(respond(t, req, -1))
+			return nil
+		}).Times(2)
+	fetchHash(t)
+	// and the peer is not asked for compressed responses anymore
+	fetchHash(t)
+}
+
 func TestFetch_GetHash_StartStopSanity(t *testing.T) {
 	f := createFetch(t)
 	f.mh.EXPECT().Close()
@@ -265,11 +451,11 @@ func TestFetch_Loop_BatchRequestMax(t *testing.T) {
 	f.mh.EXPECT().Close()
 	defer f.Stop()
 	require.NoError(t, f.Start())
-	p1, err := f.getHash(context.TODO(), h1, hint, goodReceiver)
+	p1, err := f.getHash(context.TODO(), h1, hint, goodReceiver, false)
 	require.NoError(t, err)
-	p2, err := f.getHash(context.TODO(), h2, hint, goodReceiver)
+	p2, err := f.getHash(context.TODO(), h2, hint, goodReceiver, false)
 	require.NoError(t, err)
-	p3, err := f.getHash(context.TODO(), h3, hint, goodReceiver)
+	p3, err := f.getHash(context.TODO(), h3, hint, goodReceiver, false)
 	require.NoError(t, err)
 	for _, p := range []*promise{p1, p2, p3} {
 		<-p.completed
@@ -282,10 +468,12 @@ func TestFetch_GetRandomPeer(t *testing.T) {
 	for i := 0; i < len(myPeers); i++ {
 		myPeers[i] = p2p.Peer(types.RandomBytes(20))
 	}
+	stats := newPeerStats(peerStatsSize)
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
 	allTheSame := true
 	for i := 0; i < 20; i++ {
-		peer1 := randomPeer(myPeers)
-		peer2 := randomPeer(myPeers)
+		peer1 := stats.selectPeer(myPeers, rng)
+		peer2 := stats.selectPeer(myPeers, rng)
 		if peer1 != peer2 {
 			allTheSame = false
 		}
@@ -321,6 +509,11 @@ func TestFetch_PeerDroppedWhenMessageResultsInValidationReject(t *testing.T) {
 		1000,
 		time.Second * time.Duration(3),
 		3,
+		false,
+		0,
+		0,
+		0,
+		0,
 	}
 	p2pconf := p2p.DefaultConfig()
 	p2pconf.Listen = "/ip4/127.0.0.1/tcp/0"
@@ -375,7 +568,7 @@ func TestFetch_PeerDroppedWhenMessageResultsInValidationReject(t *testing.T) {
 	fetcher.SetValidators(vf, nil, nil, nil, nil, nil, nil, nil)
 
 	// Request an atx by hash
-	_, err = fetcher.getHash(ctx, types.Hash32{}, datastore.ATXDB, fetcher.validators.atx.HandleMessage)
+	_, err = fetcher.getHash(ctx, types.Hash32{}, datastore.ATXDB, fetcher.validators.atx.HandleMessage, false)
 	require.NoError(t, err)
 	fetcher.requestHashBatchFromPeers()
 
@@ -390,7 +583,7 @@ func TestFetch_PeerDroppedWhenMessageResultsInValidationReject(t *testing.T) {
 	fetcher.SetValidators(ValidatorFunc(pubsub.DropPeerOnSyncValidationReject(vf, h, lg)), nil, nil, nil, nil, nil, nil, nil)
 
 	// Request an atx by hash
-	_, err = fetcher.getHash(ctx, types.Hash32{}, datastore.ATXDB, fetcher.validators.atx.HandleMessage)
+	_, err = fetcher.getHash(ctx, types.Hash32{}, datastore.ATXDB, fetcher.validators.atx.HandleMessage, false)
 	require.NoError(t, err)
 	fetcher.requestHashBatchFromPeers()
 
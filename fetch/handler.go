@@ -77,6 +77,35 @@ func (h *handler) handleEpochInfoReq(ctx context.Context, msg []byte) ([]byte, e
 	return bts, nil
 }
 
+// handleEpochPageReq returns a page of IDs of ATXs published in the specified epoch.
+func (h *handler) handleEpochPageReq(ctx context.Context, msg []byte) ([]byte, error) {
+	var req EpochPageRequest
+	if err := codec.Decode(msg, &req); err != nil {
+		return nil, err
+	}
+	limit := req.Limit
+	if limit == 0 || limit > MaxAtxsInPage {
+		limit = MaxAtxsInPage
+	}
+	atxids, err := atxs.GetIDsByEpochAfter(h.cdb, req.Epoch, req.After, int(limit))
+	if err != nil {
+		h.logger.WithContext(ctx).With().Warning("failed to get epoch atx IDs", req.Epoch, log.Err(err))
+		return nil, err
+	}
+	page := EpochPage{
+		AtxIDs: atxids,
+	}
+	h.logger.WithContext(ctx).With().Debug("responded to epoch page request",
+		req.Epoch,
+		log.Stringer("after", req.After),
+		log.Int("atx_count", len(page.AtxIDs)))
+	bts, err := codec.Encode(&page)
+	if err != nil {
+		h.logger.WithContext(ctx).With().Fatal("failed to serialize epoch page", req.Epoch, log.Err(err))
+	}
+	return bts, nil
+}
+
 // handleLayerDataReq returns all data in a layer, described in LayerData.
 func (h *handler) handleLayerDataReq(ctx context.Context, req []byte) ([]byte, error) {
 	var (
@@ -1,7 +1,9 @@
 package fetch
 
 import (
+	"bytes"
 	"context"
+	"sort"
 	"testing"
 	"time"
 
@@ -299,6 +301,40 @@ func TestHandleEpochInfoReq(t *testing.T) {
 	}
 }
 
+func TestHandleEpochPageReq(t *testing.T) {
+	th := createTestHandler(t)
+	epoch := types.EpochID(11)
+	var expected []types.ATXID
+	for i := 0; i < 10; i++ {
+		vatx := newAtx(t, epoch)
+		require.NoError(t, atxs.Add(th.cdb, vatx))
+		expected = append(expected, vatx.ID())
+	}
+	sort.Slice(expected, func(i, j int) bool {
+		return bytes.Compare(expected[i].Bytes(), expected[j].Bytes()) < 0
+	})
+
+	var (
+		got []types.ATXID
+		req = EpochPageRequest{Epoch: epoch, Limit: 4}
+	)
+	for {
+		reqBytes, err := codec.Encode(&req)
+		require.NoError(t, err)
+		out, err := th.handleEpochPageReq(context.Background(), reqBytes)
+		require.NoError(t, err)
+		var page EpochPage
+		require.NoError(t, codec.Decode(out, &page))
+		require.LessOrEqual(t, len(page.AtxIDs), int(req.Limit))
+		got = append(got, page.AtxIDs...)
+		if len(page.AtxIDs) < int(req.Limit) {
+			break
+		}
+		req.After = page.AtxIDs[len(page.AtxIDs)-1]
+	}
+	require.Equal(t, expected, got)
+}
+
 func TestHandleMaliciousIDsReq(t *testing.T) {
 	tt := []struct {
 		name   string
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 
@@ -24,15 +25,24 @@ func (f *Fetch) GetAtxs(ctx context.Context, ids []types.ATXID) error {
 	}
 	f.logger.WithContext(ctx).With().Debug("requesting atxs from peer", log.Int("num_atxs", len(ids)))
 	hashes := types.ATXIDsToHashes(ids)
-	return f.getHashes(ctx, hashes, datastore.ATXDB, f.validators.atx.HandleMessage)
+	return f.getHashes(ctx, hashes, datastore.ATXDB, f.validators.atx.HandleMessage, false)
 }
 
 type dataReceiver func(context.Context, types.Hash32, p2p.Peer, []byte) error
 
-func (f *Fetch) getHashes(ctx context.Context, hashes []types.Hash32, hint datastore.Hint, receiver dataReceiver) error {
+// GetHashes gets the data for the hashes and passes it to the validator instead of the validator registered
+// for the hint. it is used for data that is not validated by the regular handlers, such as historical data.
+func (f *Fetch) GetHashes(ctx context.Context, hashes []types.Hash32, hint datastore.Hint, validator SyncValidator) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+	return f.getHashes(ctx, hashes, hint, validator.HandleMessage, true)
+}
+
+func (f *Fetch) getHashes(ctx context.Context, hashes []types.Hash32, hint datastore.Hint, receiver dataReceiver, custom bool) error {
 	var eg multierror.Group
 	for _, hash := range hashes {
-		p, err := f.getHash(ctx, hash, hint, receiver)
+		p, err := f.getHash(ctx, hash, hint, receiver, custom)
 		if err != nil {
 			return err
 		}
@@ -65,7 +75,7 @@ func (f *Fetch) GetMalfeasanceProofs(ctx context.Context, ids []types.NodeID) er
 	}
 	f.logger.WithContext(ctx).With().Debug("requesting malfeasance proofs from peer", log.Int("num_proofs", len(ids)))
 	hashes := types.NodeIDsToHashes(ids)
-	return f.getHashes(ctx, hashes, datastore.Malfeasance, f.validators.malfeasance.HandleMessage)
+	return f.getHashes(ctx, hashes, datastore.Malfeasance, f.validators.malfeasance.HandleMessage, false)
 }
 
 // GetBallots gets data for the specified BallotIDs and validates them.
@@ -75,7 +85,7 @@ func (f *Fetch) GetBallots(ctx context.Context, ids []types.BallotID) error {
 	}
 	f.logger.WithContext(ctx).With().Debug("requesting ballots from peer", log.Int("num_ballots", len(ids)))
 	hashes := types.BallotIDsToHashes(ids)
-	return f.getHashes(ctx, hashes, datastore.BallotDB, f.validators.ballot.HandleMessage)
+	return f.getHashes(ctx, hashes, datastore.BallotDB, f.validators.ballot.HandleMessage, false)
 }
 
 // GetProposals gets the data for given proposal IDs from peers.
@@ -85,7 +95,9 @@ func (f *Fetch) GetProposals(ctx context.Context, ids []types.ProposalID) error
 	}
 	f.logger.WithContext(ctx).With().Debug("requesting proposals from peer", log.Int("num_proposals", len(ids)))
 	hashes := types.ProposalIDsToHashes(ids)
-	return f.getHashes(ctx, hashes, datastore.ProposalDB, f.validators.proposal.HandleMessage)
+	// proposals are fetched for the hare output or to vote on them in hare
+	ctx = withDefaultPriority(ctx, PriorityHare)
+	return f.getHashes(ctx, hashes, datastore.ProposalDB, f.validators.proposal.HandleMessage, false)
 }
 
 // GetBlocks gets the data for given block IDs from peers.
@@ -95,12 +107,12 @@ func (f *Fetch) GetBlocks(ctx context.Context, ids []types.BlockID) error {
 	}
 	f.logger.WithContext(ctx).With().Debug("requesting blocks from peer", log.Int("num_blocks", len(ids)))
 	hashes := types.BlockIDsToHashes(ids)
-	return f.getHashes(ctx, hashes, datastore.BlockDB, f.validators.block.HandleMessage)
+	return f.getHashes(ctx, hashes, datastore.BlockDB, f.validators.block.HandleMessage, false)
 }
 
 // GetProposalTxs fetches the txs provided as IDs and validates them, returns an error if one TX failed to be fetched.
 func (f *Fetch) GetProposalTxs(ctx context.Context, ids []types.TransactionID) error {
-	return f.getTxs(ctx, ids, f.validators.txProposal.HandleMessage)
+	return f.getTxs(withDefaultPriority(ctx, PriorityHare), ids, f.validators.txProposal.HandleMessage)
 }
 
 // GetBlockTxs fetches the txs provided as IDs and saves them, they will be validated
@@ -115,13 +127,13 @@ func (f *Fetch) getTxs(ctx context.Context, ids []types.TransactionID, receiver
 	}
 	f.logger.WithContext(ctx).With().Debug("requesting txs from peer", log.Int("num_txs", len(ids)))
 	hashes := types.TransactionIDsToHashes(ids)
-	return f.getHashes(ctx, hashes, datastore.TXDB, receiver)
+	return f.getHashes(ctx, hashes, datastore.TXDB, receiver, false)
 }
 
 // GetPoetProof gets poet proof from remote peer.
 func (f *Fetch) GetPoetProof(ctx context.Context, id types.Hash32) error {
 	f.logger.WithContext(ctx).With().Debug("getting poet proof", log.Stringer("hash", id))
-	pm, err := f.getHash(ctx, id, datastore.POETDB, f.validators.poet.HandleMessage)
+	pm, err := f.getHash(ctx, id, datastore.POETDB, f.validators.poet.HandleMessage, false)
 	if err != nil {
 		return err
 	}
@@ -153,7 +165,7 @@ func (f *Fetch) GetPoetProof(ctx context.Context, id types.Hash32) error {
 }
 
 func (f *Fetch) GetMaliciousIDs(ctx context.Context, peers []p2p.Peer, okCB func([]byte, p2p.Peer), errCB func(error, p2p.Peer)) error {
-	return poll(ctx, f.servers[malProtocol], peers, []byte{}, okCB, errCB)
+	return f.poll(ctx, f.servers[malProtocol], peers, []byte{}, okCB, errCB)
 }
 
 // GetLayerData get layer data from peers.
@@ -162,7 +174,7 @@ func (f *Fetch) GetLayerData(ctx context.Context, peers []p2p.Peer, lid types.La
 	if err != nil {
 		return err
 	}
-	return poll(ctx, f.servers[lyrDataProtocol], peers, lidBytes, okCB, errCB)
+	return f.poll(ctx, f.servers[lyrDataProtocol], peers, lidBytes, okCB, errCB)
 }
 
 // GetLayerOpinions get opinions on data in the specified layer from peers.
@@ -171,16 +183,19 @@ func (f *Fetch) GetLayerOpinions(ctx context.Context, peers []p2p.Peer, lid type
 	if err != nil {
 		return err
 	}
-	return poll(ctx, f.servers[lyrOpnsProtocol], peers, lidBytes, okCB, errCB)
+	return f.poll(ctx, f.servers[lyrOpnsProtocol], peers, lidBytes, okCB, errCB)
 }
 
-func poll(ctx context.Context, srv requester, peers []p2p.Peer, req []byte, okCB func([]byte, p2p.Peer), errCB func(error, p2p.Peer)) error {
+func (f *Fetch) poll(ctx context.Context, srv requester, peers []p2p.Peer, req []byte, okCB func([]byte, p2p.Peer), errCB func(error, p2p.Peer)) error {
 	for _, p := range peers {
 		peer := p
+		start := time.Now()
 		okFunc := func(data []byte) {
+			f.peers.onSuccess(peer, time.Since(start))
 			okCB(data, peer)
 		}
 		errFunc := func(err error) {
+			f.peers.onFailure(peer)
 			errCB(err, peer)
 		}
 		if err := srv.Request(ctx, peer, req, okFunc, errFunc); err != nil {
@@ -196,36 +211,43 @@ func (f *Fetch) PeerEpochInfo(ctx context.Context, peer p2p.Peer, epoch types.Ep
 		log.Stringer("peer", peer),
 		log.Stringer("epoch", epoch))
 
-	var (
-		done = make(chan error, 1)
-		ed   EpochData
-	)
-	okCB := func(data []byte) {
-		defer close(done)
-		done <- codec.Decode(data, &ed)
+	epochBytes, err := codec.Encode(epoch)
+	if err != nil {
+		return nil, err
+	}
+	data, err := f.peerRequest(ctx, atxProtocol, peer, epochBytes)
+	if err != nil {
+		return nil, err
 	}
-	errCB := func(perr error) {
-		defer close(done)
-		done <- perr
+	var ed EpochData
+	if err := codec.Decode(data, &ed); err != nil {
+		return nil, err
 	}
-	epochBytes, err := codec.Encode(epoch)
+	f.RegisterPeerHashes(peer, types.ATXIDsToHashes(ed.AtxIDs))
+	return &ed, nil
+}
+
+// PeerEpochPage gets a page of IDs of ATXs published in the given epoch from the specified peer.
+func (f *Fetch) PeerEpochPage(ctx context.Context, peer p2p.Peer, req *EpochPageRequest) (*EpochPage, error) {
+	f.logger.WithContext(ctx).With().Debug("requesting epoch page from peer",
+		log.Stringer("peer", peer),
+		log.Stringer("epoch", req.Epoch),
+		log.Stringer("after", req.After))
+
+	reqData, err := codec.Encode(req)
 	if err != nil {
 		return nil, err
 	}
-	if err := f.servers[atxProtocol].Request(ctx, peer, epochBytes, okCB, errCB); err != nil {
+	data, err := f.peerRequest(ctx, atxPageProtocol, peer, reqData)
+	if err != nil {
 		return nil, err
 	}
-	select {
-	case err := <-done:
-		if err != nil {
-			return nil, err
-		}
-		f.RegisterPeerHashes(peer, types.ATXIDsToHashes(ed.AtxIDs))
-		return &ed, nil
-	case <-ctx.Done():
-		f.logger.WithContext(ctx).With().Debug("context done")
-		return nil, ctx.Err()
+	var page EpochPage
+	if err := codec.Decode(data, &page); err != nil {
+		return nil, err
 	}
+	f.RegisterPeerHashes(peer, types.ATXIDsToHashes(page.AtxIDs))
+	return &page, nil
 }
 
 func (f *Fetch) PeerMeshHashes(ctx context.Context, peer p2p.Peer, req *MeshHashRequest) (*MeshHashes, error) {
@@ -234,38 +256,19 @@ func (f *Fetch) PeerMeshHashes(ctx context.Context, peer p2p.Peer, req *MeshHash
 		log.Object("req", req),
 	)
 
-	var (
-		done    = make(chan error, 1)
-		hashes  []types.Hash32
-		reqData []byte
-	)
 	reqData, err := codec.Encode(req)
 	if err != nil {
 		f.logger.With().Fatal("failed to encode mesh hash request", log.Err(err))
 	}
-
-	okCB := func(data []byte) {
-		defer close(done)
-		h, err := codec.DecodeSlice[types.Hash32](data)
-		hashes = h
-		done <- err
-	}
-	errCB := func(perr error) {
-		defer close(done)
-		done <- perr
-	}
-	if err = f.servers[meshHashProtocol].Request(ctx, peer, reqData, okCB, errCB); err != nil {
+	data, err := f.peerRequest(ctx, meshHashProtocol, peer, reqData)
+	if err != nil {
 		return nil, err
 	}
-	select {
-	case err := <-done:
-		if err != nil {
-			return nil, err
-		}
-		return &MeshHashes{
-			Hashes: hashes,
-		}, nil
-	case <-ctx.Done():
-		return nil, ctx.Err()
+	hashes, err := codec.DecodeSlice[types.Hash32](data)
+	if err != nil {
+		return nil, err
 	}
+	return &MeshHashes{
+		Hashes: hashes,
+	}, nil
 }
@@ -6,8 +6,10 @@ import (
 	"os"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/sync/errgroup"
 
@@ -184,7 +186,7 @@ func TestFetch_getHashes(t *testing.T) {
 					return nil
 				}).Times(len(peers))
 
-			got := f.getHashes(context.Background(), hashes, datastore.BlockDB, f.validators.block.HandleMessage)
+			got := f.getHashes(context.Background(), hashes, datastore.BlockDB, f.validators.block.HandleMessage, false)
 			if len(tc.fetchErrs) > 0 || tc.hdlrErr != nil {
 				require.NotEmpty(t, got)
 			} else {
@@ -651,6 +653,49 @@ func Test_PeerEpochInfo(t *testing.T) {
 	}
 }
 
+func Test_PeerEpochInfoDedup(t *testing.T) {
+	peer := p2p.Peer("p0")
+	f := createFetch(t)
+	f.mh.EXPECT().ID().Return(p2p.Peer("self")).AnyTimes()
+	expected, data := generateEpochData(t)
+	sent := make(chan func([]byte), 1)
+	f.mAtxS.EXPECT().Request(gomock.Any(), peer, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ p2p.Peer, _ []byte, okCB func([]byte), _ func(error)) error {
+			sent <- okCB
+			return nil
+		}).Times(2)
+
+	before := testutil.ToFloat64(dedupHits.WithLabelValues(atxProtocol))
+	var eg errgroup.Group
+	got := make([]*EpochData, 3)
+	for i := range got {
+		i := i
+		eg.Go(func() error {
+			ed, err := f.PeerEpochInfo(context.Background(), peer, types.EpochID(111))
+			got[i] = ed
+			return err
+		})
+	}
+	okCB := <-sent
+	require.Eventually(t, func() bool {
+		return testutil.ToFloat64(dedupHits.WithLabelValues(atxProtocol)) == before+2
+	}, time.Second, 10*time.Millisecond)
+	okCB(data)
+	require.NoError(t, eg.Wait())
+	for _, ed := range got {
+		require.Equal(t, expected, ed)
+	}
+
+	// the request is sent again once the response is received
+	go func() {
+		okCB := <-sent
+		okCB(data)
+	}()
+	ed, err := f.PeerEpochInfo(context.Background(), peer, types.EpochID(111))
+	require.NoError(t, err)
+	require.Equal(t, expected, ed)
+}
+
 func TestFetch_GetMeshHashes(t *testing.T) {
 	peer := p2p.Peer("p0")
 	errUnknown := errors.New("unknown")
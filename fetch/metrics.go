@@ -47,6 +47,42 @@ var (
 		subsystem,
 		"total error from sending peers hash requests",
 		[]string{hint})
+
+	peerResults = metrics.NewCounter(
+		"peer_results",
+		subsystem,
+		"total results of requests to peers that are used to weight peer selection",
+		[]string{"result"})
+
+	hashRespBytes = metrics.NewCounter(
+		"hash_resp_bytes",
+		subsystem,
+		"total size of responses to hash requests on the compressed protocol",
+		[]string{"encoding"})
+
+	hashQuarantined = metrics.NewCounter(
+		"hash_quarantined",
+		subsystem,
+		"total hashes quarantined after their data failed validation from every peer",
+		[]string{hint})
+
+	dedupHits = metrics.NewCounter(
+		"dedup_hits",
+		subsystem,
+		"total requests that were not sent as an identical request was already in progress",
+		[]string{"request"})
+
+	hashRequests = metrics.NewCounter(
+		"hash_requests",
+		subsystem,
+		"total hash requests by priority",
+		[]string{"priority"})
+
+	deferredRequests = metrics.NewCounter(
+		"deferred_requests",
+		subsystem,
+		"total times a backfill request was kept in the queue as requests with higher priority were queued",
+		[]string{}).WithLabelValues()
 )
 
 // logCacheHit logs cache hit.
@@ -0,0 +1,179 @@
+package fetch
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/p2p"
+)
+
+const (
+	// peerStatsSize is the number of peers for which the stats are tracked.
+	peerStatsSize = 1000
+	// latencyAlpha is the weight of the last response in the exponential moving average of the latency.
+	latencyAlpha = 0.2
+	// invalidPenalty is how many failed requests a single response with invalid data is worth.
+	invalidPenalty = 10
+	// minPeerWeight keeps demoted peers eligible for selection, so that they can recover.
+	minPeerWeight = 0.01
+	// peerStatHalfLife is the time after which the results of the past requests count half as much.
+	peerStatHalfLife = 10 * time.Minute
+)
+
+type peerStat struct {
+	// success, failures and invalid are decayed counts of the request results as of updated.
+	success  float64
+	failures float64
+	invalid  float64
+	updated  time.Time
+	latency  time.Duration
+	// noCompression is set if the peer doesn't support the protocol with compressed responses.
+	noCompression bool
+}
+
+// decayFactor is the weight of the results recorded before updated at the time now.
+func (s *peerStat) decayFactor(now time.Time) float64 {
+	if s.updated.IsZero() || !now.After(s.updated) {
+		return 1
+	}
+	return math.Exp2(-float64(now.Sub(s.updated)) / float64(peerStatHalfLife))
+}
+
+// decay scales the counts of the past results down to the time now.
+func (s *peerStat) decay(now time.Time) {
+	f := s.decayFactor(now)
+	s.success *= f
+	s.failures *= f
+	s.invalid *= f
+	s.updated = now
+}
+
+// weight is higher for peers that respond successfully, quickly and with valid data.
+// the older results count less, so that the weight of a peer follows its recent behavior.
+func (s *peerStat) weight(now time.Time) float64 {
+	f := s.decayFactor(now)
+	success, failures, invalid := f*s.success, f*s.failures, f*s.invalid
+	// the success rate of a peer without requests is 0.5.
+	rate := (success + 1) / (success + failures + invalidPenalty*invalid + 2)
+	w := rate / (1 + s.latency.Seconds())
+	if w < minPeerWeight {
+		return minPeerWeight
+	}
+	return w
+}
+
+// peerStats tracks results of requests to peers and selects peers for new requests
+// proportionally to their weight.
+type peerStats struct {
+	mu    sync.Mutex
+	stats *lru.Cache[p2p.Peer, *peerStat]
+}
+
+func newPeerStats(size int) *peerStats {
+	stats, err := lru.New[p2p.Peer, *peerStat](size)
+	if err != nil {
+		log.Panic("could not initialize cache ", err)
+	}
+	return &peerStats{stats: stats}
+}
+
+// update returns the stats of the peer decayed to the time now, adding them if the peer is not tracked.
+func (ps *peerStats) update(peer p2p.Peer, now time.Time) *peerStat {
+	stat, ok := ps.stats.Get(peer)
+	if !ok {
+		stat = &peerStat{}
+		ps.stats.Add(peer, stat)
+	}
+	stat.decay(now)
+	return stat
+}
+
+// onSuccess records a response received from the peer after latency.
+func (ps *peerStats) onSuccess(peer p2p.Peer, latency time.Duration) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	stat := ps.update(peer, time.Now())
+	stat.success++
+	if stat.latency == 0 {
+		stat.latency = latency
+	} else {
+		stat.latency = time.Duration(latencyAlpha*float64(latency) + (1-latencyAlpha)*float64(stat.latency))
+	}
+	peerResults.WithLabelValues("ok").Inc()
+}
+
+// onFailure records a request to the peer that failed or timed out.
+func (ps *peerStats) onFailure(peer p2p.Peer) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.update(peer, time.Now()).failures++
+	peerResults.WithLabelValues("fail").Inc()
+}
+
+// onInvalid records data from the peer that failed validation.
+func (ps *peerStats) onInvalid(peer p2p.Peer) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.update(peer, time.Now()).invalid++
+	peerResults.WithLabelValues("invalid").Inc()
+}
+
+// onCompressionUnsupported records that the peer doesn't support compressed responses.
+func (ps *peerStats) onCompressionUnsupported(peer p2p.Peer) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	stat, ok := ps.stats.Get(peer)
+	if !ok {
+		stat = &peerStat{}
+		ps.stats.Add(peer, stat)
+	}
+	stat.noCompression = true
+}
+
+// peek returns a copy of the stats of the peer without adding or decaying them.
+func (ps *peerStats) peek(peer p2p.Peer) (peerStat, bool) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	stat, ok := ps.stats.Peek(peer)
+	if !ok {
+		return peerStat{}, false
+	}
+	return *stat, true
+}
+
+// supportsCompression returns false if the peer is known to not support compressed responses.
+func (ps *peerStats) supportsCompression(peer p2p.Peer) bool {
+	stat, ok := ps.peek(peer)
+	return !ok || !stat.noCompression
+}
+
+// selectPeer returns a random peer, where the probability of a peer to be selected is proportional to its weight.
+// it doesn't change the stats, the peers without the stats are weighted as the peers without requests.
+func (ps *peerStats) selectPeer(peers []p2p.Peer, rng *rand.Rand) p2p.Peer {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	now := time.Now()
+	weights := make([]float64, len(peers))
+	total := 0.0
+	for i, peer := range peers {
+		stat, ok := ps.stats.Peek(peer)
+		if !ok {
+			stat = &peerStat{}
+		}
+		weights[i] = stat.weight(now)
+		total += weights[i]
+	}
+	r := rng.Float64() * total
+	for i, w := range weights {
+		if r < w {
+			return peers[i]
+		}
+		r -= w
+	}
+	return peers[len(peers)-1]
+}
@@ -0,0 +1,80 @@
+package fetch
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/p2p"
+)
+
+func TestPeerStat_Weight(t *testing.T) {
+	fresh := &peerStat{}
+	good := &peerStat{success: 10, latency: 100 * time.Millisecond}
+	slow := &peerStat{success: 10, latency: 5 * time.Second}
+	failing := &peerStat{success: 10, failures: 5, latency: 100 * time.Millisecond}
+	invalid := &peerStat{success: 10, invalid: 1, latency: 100 * time.Millisecond}
+
+	now := time.Now()
+	require.Greater(t, good.weight(now), fresh.weight(now))
+	require.Greater(t, good.weight(now), slow.weight(now))
+	require.Greater(t, good.weight(now), failing.weight(now))
+	require.Greater(t, failing.weight(now), invalid.weight(now))
+
+	require.Equal(t, minPeerWeight, (&peerStat{invalid: 100}).weight(now))
+}
+
+func TestPeerStat_Decay(t *testing.T) {
+	now := time.Now()
+	stat := &peerStat{success: 8, failures: 4, invalid: 2, updated: now}
+	fresh := (&peerStat{}).weight(now)
+	demoted := stat.weight(now)
+	require.Less(t, demoted, fresh)
+
+	// the weight recovers as the past results decay
+	later := now.Add(peerStatHalfLife)
+	require.Greater(t, stat.weight(later), demoted)
+	require.InDelta(t, fresh, stat.weight(now.Add(100*peerStatHalfLife)), 1e-6)
+
+	stat.decay(later)
+	require.Equal(t, later, stat.updated)
+	require.InDelta(t, 4, stat.success, 1e-9)
+	require.InDelta(t, 2, stat.failures, 1e-9)
+	require.InDelta(t, 1, stat.invalid, 1e-9)
+}
+
+func TestPeerStats_SelectPeer(t *testing.T) {
+	stats := newPeerStats(peerStatsSize)
+	good, bad := p2p.Peer("good"), p2p.Peer("bad")
+	for i := 0; i < 10; i++ {
+		stats.onSuccess(good, 10*time.Millisecond)
+		stats.onFailure(bad)
+	}
+	stats.onInvalid(bad)
+
+	rng := rand.New(rand.NewSource(1))
+	selected := map[p2p.Peer]int{}
+	for i := 0; i < 1000; i++ {
+		selected[stats.selectPeer([]p2p.Peer{good, bad}, rng)]++
+	}
+	require.Greater(t, selected[good], 900)
+	require.Positive(t, selected[bad])
+
+	// selection doesn't start tracking unknown peers
+	stats.selectPeer([]p2p.Peer{"unknown"}, rng)
+	require.False(t, stats.stats.Contains("unknown"))
+	require.Equal(t, 2, stats.stats.Len())
+}
+
+func TestPeerStats_Latency(t *testing.T) {
+	stats := newPeerStats(peerStatsSize)
+	peer := p2p.Peer("peer")
+	stats.onSuccess(peer, time.Second)
+	stat, ok := stats.stats.Peek(peer)
+	require.True(t, ok)
+	require.Equal(t, time.Second, stat.latency)
+	stats.onSuccess(peer, 0)
+	require.Equal(t, 800*time.Millisecond, stat.latency)
+}
@@ -0,0 +1,51 @@
+package fetch
+
+import "context"
+
+// Priority of the hash requests. requests with higher priority are sent first.
+type Priority uint8
+
+const (
+	// PriorityBackfill is used for the bulk download of the history that is not needed for consensus.
+	// such requests are sent only when no requests with higher priority are queued.
+	PriorityBackfill Priority = iota
+	// PriorityTortoise is used for the data of the layers within the tortoise window. it is the default.
+	PriorityTortoise
+	// PriorityHare is used for the data needed by hare to complete the current layer.
+	PriorityHare
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityBackfill:
+		return "backfill"
+	case PriorityTortoise:
+		return "tortoise"
+	case PriorityHare:
+		return "hare"
+	default:
+		return "unknown"
+	}
+}
+
+type priorityKey struct{}
+
+// WithPriority returns the context for the hash requests with the priority.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// withDefaultPriority returns the context with the priority, unless the context has a priority set already.
+func withDefaultPriority(ctx context.Context, p Priority) context.Context {
+	if _, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return ctx
+	}
+	return WithPriority(ctx, p)
+}
+
+func priorityFrom(ctx context.Context) Priority {
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return p
+	}
+	return PriorityTortoise
+}
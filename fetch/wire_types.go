@@ -105,6 +105,21 @@ type EpochData struct {
 	AtxIDs []types.ATXID `scale:"max=100000"` // max. expected number of ATXs per epoch is 100_000
 }
 
+// MaxAtxsInPage is the maximal number of ATX IDs in EpochPage.
+const MaxAtxsInPage = 5000
+
+// EpochPageRequest requests IDs of ATXs published in the epoch, ordered by ID and starting after the After ID.
+type EpochPageRequest struct {
+	Epoch types.EpochID
+	After types.ATXID
+	Limit uint32
+}
+
+// EpochPage is a response to EpochPageRequest. the page is the last one if it has less IDs than requested.
+type EpochPage struct {
+	AtxIDs []types.ATXID `scale:"max=5000"` // same as MaxAtxsInPage
+}
+
 // LayerData is the data response for a given layer ID.
 type LayerData struct {
 	Ballots []types.BallotID `scale:"max=500"` // expected are 50 proposals per layer + safety margin
@@ -100,6 +100,13 @@ var (
 		[]string{},
 	).WithLabelValues()
 
+	conflictingTxCount = metrics.NewCounter(
+		"conflicting_txs",
+		namespace,
+		"Number of transactions executed again after the concurrent execution, as they conflicted with preceding transactions.",
+		[]string{},
+	).WithLabelValues()
+
 	transactionsPerBlock = metrics.NewHistogramWithBuckets(
 		"transactions_per_block",
 		namespace,
@@ -142,3 +149,10 @@ var appliedLayer = metrics.NewGauge(
 	"Applied layer",
 	[]string{},
 ).WithLabelValues()
+
+var prunedLayer = metrics.NewGauge(
+	"pruned_layer",
+	namespace,
+	"Layer before which the account states are pruned",
+	[]string{},
+).WithLabelValues()
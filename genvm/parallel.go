@@ -0,0 +1,90 @@
+package vm
+
+import (
+	"bytes"
+	"math"
+	"sync"
+
+	"github.com/spacemeshos/go-scale"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/genvm/core"
+	"github.com/spacemeshos/go-spacemesh/log"
+)
+
+// minParallelTxs is the min number of transactions in the layer that are executed concurrently,
+// smaller layers are executed sequentially.
+const minParallelTxs = 64
+
+// speculation is the outcome of the transaction executed against the state before the layer.
+// it is the same as the outcome of the sequential execution unless the transaction loaded
+// any account that was updated by the preceding transactions in the layer.
+type speculation struct {
+	out   outcome
+	err   error
+	loads []core.Address
+}
+
+// conflicts returns true if the transaction loaded any of the updated accounts.
+func (s *speculation) conflicts(updated map[core.Address]struct{}) bool {
+	for _, address := range s.loads {
+		if _, exist := updated[address]; exist {
+			return true
+		}
+	}
+	return false
+}
+
+// loadRecorder records the addresses of the accounts loaded from the underlying loader.
+type loadRecorder struct {
+	loader core.AccountLoader
+	loads  []core.Address
+}
+
+func (l *loadRecorder) Get(address core.Address) (core.Account, error) {
+	l.loads = append(l.loads, address)
+	return l.loader.Get(address)
+}
+
+// speculate executes transactions concurrently, each against the state before the layer.
+// the outcomes are committed by the caller in the order of the transactions, and transactions
+// that conflict with the preceding ones are executed again. so the result of the layer doesn't depend
+// on the number of workers.
+func (v *VM) speculate(lctx ApplyContext, schedule GasSchedule, txs []types.Transaction) []speculation {
+	var (
+		rst   = make([]speculation, len(txs))
+		next  = make(chan int, len(txs))
+		wg    sync.WaitGroup
+		state = core.DBLoader{Executor: v.db}
+	)
+	for i := range txs {
+		next <- i
+	}
+	close(next)
+	workers := v.cfg.ApplyWorkers
+	if workers > len(txs) {
+		workers = len(txs)
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				recorder := &loadRecorder{loader: state}
+				req := &Request{
+					vm:       v,
+					cache:    core.NewStagedCache(recorder),
+					lid:      lctx.Layer,
+					schedule: schedule,
+					raw:      txs[i].GetRaw(),
+					decoder:  scale.NewDecoder(bytes.NewReader(txs[i].GetRaw().Raw)),
+				}
+				// gas left in the layer is checked when the outcome is committed
+				out, err := v.run(v.logger.WithFields(log.Int("ith", i)), req, txs[i], math.MaxUint64)
+				rst[i] = speculation{out: out, err: err, loads: recorder.loads}
+			}
+		}()
+	}
+	wg.Wait()
+	return rst
+}
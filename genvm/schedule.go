@@ -0,0 +1,60 @@
+package vm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+)
+
+// baseCostFactor is the cost factor that keeps the gas costs of the templates unchanged.
+const baseCostFactor = 100
+
+// GasSchedule defines the gas costs applied starting from the layer,
+// so that the changes of the economic parameters are activated at the same layer by all nodes.
+type GasSchedule struct {
+	// Layer is the first layer the schedule is applied to.
+	Layer types.LayerID `mapstructure:"layer"`
+	// CostFactor scales the gas costs of the templates, in percents.
+	CostFactor uint64 `mapstructure:"cost-factor"`
+	// GasLimit is the max gas consumed by the transactions in a layer,
+	// zero keeps the gas limit of the previous schedule.
+	GasLimit uint64 `mapstructure:"gas-limit"`
+}
+
+// scale returns the gas cost scaled by the cost factor of the schedule.
+func (s GasSchedule) scale(gas uint64) uint64 {
+	if s.CostFactor == baseCostFactor {
+		return gas
+	}
+	return gas * s.CostFactor / baseCostFactor
+}
+
+// ValidateGasSchedules checks that the schedules are activated in the increasing order of layers
+// and that none of them makes transactions free.
+func ValidateGasSchedules(schedules []GasSchedule) error {
+	for i, schedule := range schedules {
+		if schedule.CostFactor == 0 {
+			return fmt.Errorf("gas schedule at layer %s: zero cost factor", schedule.Layer)
+		}
+		if i > 0 && !schedule.Layer.After(schedules[i-1].Layer) {
+			return errors.New("gas schedules must be ordered by the activation layer")
+		}
+	}
+	return nil
+}
+
+// schedule returns the gas schedule active at the layer.
+func (c *Config) schedule(lid types.LayerID) GasSchedule {
+	active := GasSchedule{CostFactor: baseCostFactor, GasLimit: c.GasLimit}
+	for _, schedule := range c.GasSchedules {
+		if schedule.Layer.After(lid) {
+			break
+		}
+		active.CostFactor = schedule.CostFactor
+		if schedule.GasLimit != 0 {
+			active.GasLimit = schedule.GasLimit
+		}
+	}
+	return active
+}
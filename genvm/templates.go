@@ -0,0 +1,84 @@
+package vm
+
+import (
+	"fmt"
+
+	"github.com/spacemeshos/go-scale"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/genvm/core"
+	"github.com/spacemeshos/go-spacemesh/genvm/registry"
+	"github.com/spacemeshos/go-spacemesh/genvm/templates/multisig"
+	"github.com/spacemeshos/go-spacemesh/genvm/templates/vault"
+	"github.com/spacemeshos/go-spacemesh/genvm/templates/vesting"
+	"github.com/spacemeshos/go-spacemesh/genvm/templates/wallet"
+)
+
+// Names of the supported templates.
+const (
+	WalletTemplate   = "wallet"
+	MultisigTemplate = "multisig"
+	VestingTemplate  = "vesting"
+	VaultTemplate    = "vault"
+)
+
+var (
+	templates = NewRegistry()
+
+	templateNames = map[core.Address]string{
+		wallet.TemplateAddress:   WalletTemplate,
+		multisig.TemplateAddress: MultisigTemplate,
+		vesting.TemplateAddress:  VestingTemplate,
+		vault.TemplateAddress:    VaultTemplate,
+	}
+)
+
+// NewRegistry returns the registry with all supported templates.
+func NewRegistry() *registry.Registry {
+	reg := registry.New()
+	wallet.Register(reg)
+	multisig.Register(reg)
+	vesting.Register(reg)
+	vault.Register(reg)
+	return reg
+}
+
+// TemplateName returns the name of the supported template, empty if the template is not supported.
+func TemplateName(template core.Address) string {
+	return templateNames[template]
+}
+
+// TemplateByName returns the address of the supported template with the name.
+func TemplateByName(name string) (core.Address, bool) {
+	for address, known := range templateNames {
+		if known == name {
+			return address, true
+		}
+	}
+	return core.Address{}, false
+}
+
+// SpawnPrincipal validates the spawn arguments in the same way as the spawn transaction,
+// and returns the address of the account spawned with them.
+func SpawnPrincipal(template core.Address, args scale.Encodable) (types.Address, error) {
+	handler := templates.Get(template)
+	if handler == nil {
+		return types.Address{}, fmt.Errorf("%w: unknown template %s", core.ErrMalformed, template)
+	}
+	if _, err := handler.New(args); err != nil {
+		return types.Address{}, fmt.Errorf("%w: %s", core.ErrMalformed, err.Error())
+	}
+	return core.ComputePrincipal(template, args), nil
+}
+
+// LoadTemplate decodes the template state of the spawned account.
+func LoadTemplate(account *types.Account) (core.Template, error) {
+	if account.TemplateAddress == nil {
+		return nil, fmt.Errorf("%w: account %s", core.ErrNotSpawned, account.Address)
+	}
+	handler := templates.Get(*account.TemplateAddress)
+	if handler == nil {
+		return nil, fmt.Errorf("%w: unknown template %s", core.ErrInternal, *account.TemplateAddress)
+	}
+	return handler.Load(account.State)
+}
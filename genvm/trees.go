@@ -0,0 +1,54 @@
+package vm
+
+import (
+	"sync"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+)
+
+// treeCacheSize is the number of the recently applied layers with the accounts trees kept in memory.
+const treeCacheSize = 4
+
+// accountTrees caches the accounts trees of the recently applied layers. the tree of the last applied
+// layer is updated with the changed accounts of the next layer instead of being built from the database.
+type accountTrees struct {
+	mu    sync.Mutex
+	last  types.LayerID
+	trees map[types.LayerID]*types.AccountsTree
+}
+
+func (c *accountTrees) get(lid types.LayerID) *types.AccountsTree {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.trees[lid]
+}
+
+// latest returns the tree of the last applied layer, nil if it is not cached.
+func (c *accountTrees) latest() (types.LayerID, *types.AccountsTree) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.last, c.trees[c.last]
+}
+
+func (c *accountTrees) add(lid types.LayerID, tree *types.AccountsTree) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.trees == nil {
+		c.trees = map[types.LayerID]*types.AccountsTree{}
+	}
+	c.trees[lid] = tree
+	c.last = lid
+	for cached := range c.trees {
+		if !cached.Add(treeCacheSize).After(lid) || cached.After(lid) {
+			delete(c.trees, cached)
+		}
+	}
+}
+
+// reset drops the cached trees after the state is changed outside of the applied layers.
+func (c *accountTrees) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.trees = nil
+	c.last = 0
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"runtime"
 	"time"
 
 	"github.com/spacemeshos/go-scale"
@@ -13,11 +14,6 @@ import (
 	"github.com/spacemeshos/go-spacemesh/events"
 	"github.com/spacemeshos/go-spacemesh/genvm/core"
 	"github.com/spacemeshos/go-spacemesh/genvm/registry"
-	"github.com/spacemeshos/go-spacemesh/genvm/templates/multisig"
-	"github.com/spacemeshos/go-spacemesh/genvm/templates/vault"
-	"github.com/spacemeshos/go-spacemesh/genvm/templates/vesting"
-	"github.com/spacemeshos/go-spacemesh/genvm/templates/wallet"
-	"github.com/spacemeshos/go-spacemesh/hash"
 	"github.com/spacemeshos/go-spacemesh/log"
 	"github.com/spacemeshos/go-spacemesh/sql"
 	"github.com/spacemeshos/go-spacemesh/sql/accounts"
@@ -41,12 +37,25 @@ func WithLogger(logger log.Log) Opt {
 type Config struct {
 	GasLimit  uint64
 	GenesisID types.Hash20
+	// StateRetention is the number of layers for which the historical account states are kept.
+	// the older states are pruned, zero disables pruning.
+	StateRetention uint32
+	// GasSchedules override the gas costs and the gas limit starting from their activation layers.
+	GasSchedules []GasSchedule
+	// ApplyWorkers is the number of transactions of the layer executed concurrently,
+	// values below 2 execute them sequentially.
+	ApplyWorkers int
 }
 
+// pruneBatch is the max number of layers pruned after a single applied layer,
+// so that the database with a long history is pruned gradually.
+const pruneBatch = 1000
+
 // DefaultConfig returns the default RewardConfig.
 func DefaultConfig() Config {
 	return Config{
-		GasLimit: 100_000_000,
+		GasLimit:     100_000_000,
+		ApplyWorkers: runtime.NumCPU(),
 	}
 }
 
@@ -63,12 +72,8 @@ func New(db *sql.Database, opts ...Opt) *VM {
 		logger:   log.NewNop(),
 		db:       db,
 		cfg:      DefaultConfig(),
-		registry: registry.New(),
+		registry: NewRegistry(),
 	}
-	wallet.Register(vm.registry)
-	multisig.Register(vm.registry)
-	vesting.Register(vm.registry)
-	vault.Register(vm.registry)
 	for _, opt := range opts {
 		opt(vm)
 	}
@@ -81,16 +86,104 @@ type VM struct {
 	db       *sql.Database
 	cfg      Config
 	registry *registry.Registry
+	trees    accountTrees
+}
+
+// prune removes the account states older than the retention window of the applied layer
+// and returns the new pruned layer, zero if nothing was pruned.
+func (v *VM) prune(db sql.Executor, applied types.LayerID) (types.LayerID, error) {
+	if v.cfg.StateRetention == 0 || applied.Uint32() <= v.cfg.StateRetention {
+		return 0, nil
+	}
+	target := applied.Sub(v.cfg.StateRetention)
+	pruned, err := accounts.Pruned(db)
+	if err != nil {
+		return 0, err
+	}
+	if !target.After(pruned) {
+		return 0, nil
+	}
+	if target.Difference(pruned) > pruneBatch {
+		target = pruned.Add(pruneBatch)
+	}
+	if err := accounts.Prune(db, target); err != nil {
+		return 0, err
+	}
+	return target, nil
+}
+
+// AccountProof returns the merkle proof of the account state at the layer and the state hash
+// of the layer it is verified against (see types.AccountProof).
+func (v *VM) AccountProof(address types.Address, lid types.LayerID) (*types.AccountProof, types.Hash32, error) {
+	root, err := layers.GetStateHash(v.db, lid)
+	if err != nil {
+		return nil, types.Hash32{}, err
+	}
+	tree := v.trees.get(lid)
+	if tree == nil {
+		snapshot, err := accounts.Snapshot(v.db, lid)
+		if err != nil && !errors.Is(err, sql.ErrNotFound) {
+			return nil, types.Hash32{}, err
+		}
+		tree = types.NewAccountsTree(snapshot)
+	}
+	// the state hash of the layer applied by the older version of the node is not the accounts root
+	if tree.Root() != root {
+		return nil, types.Hash32{}, fmt.Errorf("%w: accounts tree at layer %s doesn't match the state hash",
+			core.ErrInternal, lid)
+	}
+	proof, found := tree.Prove(address)
+	if !found {
+		return nil, types.Hash32{}, fmt.Errorf("%w: account %s at layer %s", sql.ErrNotFound, address, lid)
+	}
+	return proof, root, nil
+}
+
+// accountsTree returns the accounts tree of the layer with the changed accounts. the tree is built
+// from the database unless the tree of the previous layer is cached.
+func (v *VM) accountsTree(db sql.Executor, lid types.LayerID, changed []*types.Account) (*types.AccountsTree, error) {
+	if last, tree := v.trees.latest(); tree != nil && last.Before(lid) {
+		return tree.Update(changed), nil
+	}
+	snapshot, err := accounts.Snapshot(db, lid)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return nil, err
+	}
+	return types.NewAccountsTree(snapshot), nil
 }
 
 // Validation initializes validation request.
 func (v *VM) Validation(raw types.RawTx) system.ValidationRequest {
+	return v.ValidationAt(v.pendingLayer(), raw)
+}
+
+// ValidationAt initializes validation request with the gas costs of the layer.
+func (v *VM) ValidationAt(lid types.LayerID, raw types.RawTx) system.ValidationRequest {
 	return &Request{
-		vm:      v,
-		cache:   core.NewStagedCache(core.DBLoader{Executor: v.db}),
-		decoder: scale.NewDecoder(bytes.NewReader(raw.Raw)),
-		raw:     raw,
+		vm:       v,
+		schedule: v.cfg.schedule(lid),
+		cache:    core.NewStagedCache(core.DBLoader{Executor: v.db}),
+		decoder:  scale.NewDecoder(bytes.NewReader(raw.Raw)),
+		raw:      raw,
+	}
+}
+
+// GasSchedule returns the gas schedule active at the layer.
+func (v *VM) GasSchedule(lid types.LayerID) GasSchedule {
+	return v.cfg.schedule(lid)
+}
+
+// pendingLayer returns the layer that will be applied next.
+func (v *VM) pendingLayer() types.LayerID {
+	applied, err := layers.GetLastApplied(v.db)
+	if err != nil {
+		v.logger.With().Warning("failed to load last applied layer", log.Err(err))
+		return types.GetEffectiveGenesis()
+	}
+	if applied.Before(types.GetEffectiveGenesis()) {
+		return types.GetEffectiveGenesis()
 	}
+	return applied.Add(1)
 }
 
 // GetLayerStateRoot returns the state root at a given layer.
@@ -135,7 +228,11 @@ func (v *VM) revert(lid types.LayerID) error {
 	if err != nil {
 		return err
 	}
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	v.trees.reset()
+	return nil
 }
 
 // Revert all changes that we made after the layer.
@@ -161,6 +258,11 @@ func (v *VM) GetNonce(address core.Address) (core.Nonce, error) {
 	return account.NextNonce, nil
 }
 
+// GetAccount returns the latest state of the account.
+func (v *VM) GetAccount(address types.Address) (types.Account, error) {
+	return accounts.Latest(v.db, address)
+}
+
 // GetBalance returns balance for an address.
 func (v *VM) GetBalance(address types.Address) (uint64, error) {
 	account, err := accounts.Latest(v.db, address)
@@ -184,7 +286,11 @@ func (v *VM) ApplyGenesis(genesis []types.Account) error {
 			return fmt.Errorf("inserting genesis account: %w", err)
 		}
 	}
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	v.trees.reset()
+	return nil
 }
 
 // Apply transactions.
@@ -213,9 +319,7 @@ func (v *VM) Apply(lctx ApplyContext, txs []types.Transaction, blockRewards []ty
 	t3 := time.Now()
 	blockDurationRewards.Observe(float64(time.Since(t2)))
 
-	hasher := hash.New()
-	encoder := scale.NewEncoder(hasher)
-	total := 0
+	var changed []*types.Account
 
 	tx, err := v.db.TxImmediate(context.Background())
 	if err != nil {
@@ -223,36 +327,52 @@ func (v *VM) Apply(lctx ApplyContext, txs []types.Transaction, blockRewards []ty
 	}
 	defer tx.Release()
 
-	for _, reward := range rewardsResult {
+	for i, reward := range rewardsResult {
 		if err := rewards.Add(tx, &reward); err != nil {
 			return nil, nil, fmt.Errorf("%w: %s", core.ErrInternal, err.Error())
 		}
+		if smesher := blockRewards[i].SmesherID; smesher != types.EmptyNodeID {
+			if err := rewards.AddSmesher(tx, smesher, &reward); err != nil {
+				return nil, nil, fmt.Errorf("%w: %s", core.ErrInternal, err.Error())
+			}
+		}
 	}
 
 	ss.IterateChanged(func(account *core.Account) bool {
-		total++
 		account.Layer = lctx.Layer
 		v.logger.With().Debug("update account state", log.Inline(account))
 		err = accounts.Update(tx, account)
 		if err != nil {
 			return false
 		}
-		account.EncodeScale(encoder)
+		changed = append(changed, account)
 		return true
 	})
 	if err != nil {
 		return nil, nil, fmt.Errorf("%w: %s", core.ErrInternal, err.Error())
 	}
-	writesPerBlock.Observe(float64(total))
+	writesPerBlock.Observe(float64(len(changed)))
 
-	var hash types.Hash32
-	hasher.Sum(hash[:0])
+	// state hash is the root of the accounts tree, so that the account state is proven against it
+	tree, err := v.accountsTree(tx, lctx.Layer, changed)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", core.ErrInternal, err.Error())
+	}
+	hash := tree.Root()
 	if err := layers.UpdateStateHash(tx, lctx.Layer, hash); err != nil {
 		return nil, nil, err
 	}
+	pruned, err := v.prune(tx, lctx.Layer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", core.ErrInternal, err.Error())
+	}
 	if err := tx.Commit(); err != nil {
 		return nil, nil, fmt.Errorf("%w: %s", core.ErrInternal, err.Error())
 	}
+	v.trees.add(lctx.Layer, tree)
+	if pruned != 0 {
+		prunedLayer.Set(float64(pruned))
+	}
 	ss.IterateChanged(func(account *core.Account) bool {
 		events.ReportAccountUpdate(account.Address)
 		return true
@@ -287,137 +407,184 @@ func (v *VM) execute(lctx ApplyContext, ss *core.StagedCache, txs []types.Transa
 		fees        uint64
 		ineffective []types.Transaction
 		executed    []types.TransactionWithResult
-		limit       = v.cfg.GasLimit
+		schedule    = v.cfg.schedule(lctx.Layer)
+		limit       = schedule.GasLimit
+		speculated  []speculation
+		// dirty is the set of accounts updated by the transactions executed in this layer.
+		dirty = map[core.Address]struct{}{}
 	)
+	if v.cfg.ApplyWorkers > 1 && len(txs) >= minParallelTxs {
+		speculated = v.speculate(lctx, schedule, txs)
+	}
 	for i := range txs {
 		logger := v.logger.WithFields(log.Int("ith", i))
 		txCount.Inc()
 
-		t1 := time.Now()
-		tx := txs[i]
-
-		rd.Reset(tx.GetRaw().Raw)
-		req := &Request{
-			vm:      v,
-			cache:   ss,
-			lid:     lctx.Layer,
-			raw:     txs[i].GetRaw(),
-			decoder: decoder,
+		var (
+			out outcome
+			err error
+		)
+		if speculated != nil && !speculated[i].conflicts(dirty) {
+			out, err = speculated[i].out, speculated[i].err
+			// gas left in the layer depends on the preceding transactions, so it is checked in order
+			if out.ctx != nil {
+				if out = v.checkLimit(logger, schedule, limit, txs[i].GetRaw(), out); out.ineffective != nil {
+					err = nil
+				}
+			}
+		} else {
+			if speculated != nil {
+				conflictingTxCount.Inc()
+			}
+			rd.Reset(txs[i].GetRaw().Raw)
+			req := &Request{
+				vm:       v,
+				cache:    ss,
+				lid:      lctx.Layer,
+				schedule: schedule,
+				raw:      txs[i].GetRaw(),
+				decoder:  decoder,
+			}
+			out, err = v.run(logger, req, txs[i], limit)
 		}
-
-		header, err := req.Parse()
 		if err != nil {
-			logger.With().Warning("ineffective transaction. failed to parse",
-				tx.GetRaw().ID,
-				log.Err(err),
-			)
-			ineffective = append(ineffective, types.Transaction{RawTx: tx.GetRaw()})
-			invalidTxCount.Inc()
-			continue
+			return nil, nil, 0, err
 		}
-		ctx := req.ctx
-		args := req.args
-
-		if header.GasPrice == 0 {
-			logger.With().Warning("ineffective transaction. zero gas price",
-				log.Object("header", header),
-				log.Object("account", &ctx.PrincipalAccount),
-			)
-			ineffective = append(ineffective, types.Transaction{RawTx: tx.GetRaw()})
+		if out.ineffective != nil {
+			ineffective = append(ineffective, *out.ineffective)
 			invalidTxCount.Inc()
 			continue
 		}
-		if intrinsic := core.IntrinsicGas(ctx.Gas.BaseGas, tx.GetRaw().Raw); ctx.PrincipalAccount.Balance < intrinsic {
-			logger.With().Warning("ineffective transaction. intrinstic gas not covered",
-				log.Object("header", header),
-				log.Object("account", &ctx.PrincipalAccount),
-				log.Uint64("intrinsic gas", intrinsic),
-			)
-			ineffective = append(ineffective, types.Transaction{RawTx: tx.GetRaw()})
-			invalidTxCount.Inc()
-			continue
+		ctx := out.ctx
+		if err := ctx.Apply(ss); err != nil {
+			return nil, nil, 0, fmt.Errorf("%w: %s", core.ErrInternal, err.Error())
 		}
-		if limit < ctx.Header.MaxGas {
-			logger.With().Warning("ineffective transaction. out of block gas",
-				log.Uint64("block gas limit", v.cfg.GasLimit),
-				log.Uint64("current limit", limit),
-				log.Object("header", header),
-				log.Object("account", &ctx.PrincipalAccount),
-			)
-			ineffective = append(ineffective, types.Transaction{RawTx: tx.GetRaw()})
-			invalidTxCount.Inc()
-			continue
+		for _, address := range ctx.Updated() {
+			dirty[address] = struct{}{}
 		}
+		fees += ctx.Fee()
+		limit -= ctx.Consumed()
 
-		// NOTE this part is executed only for transactions that weren't verified
-		// when saved into database by txs module
-		if !tx.Verified() && !req.Verify() {
-			logger.With().Warning("ineffective transaction. failed verify",
-				log.Object("header", header),
-				log.Object("account", &ctx.PrincipalAccount),
-			)
-			ineffective = append(ineffective, types.Transaction{RawTx: tx.GetRaw()})
-			invalidTxCount.Inc()
-			continue
-		}
+		executed = append(executed, out.result)
+	}
+	return executed, ineffective, fees, nil
+}
 
-		if ctx.PrincipalAccount.NextNonce > ctx.Header.Nonce {
-			logger.With().Warning("ineffective transaction. nonce too low",
-				log.Object("header", header),
-				log.Object("account", &ctx.PrincipalAccount),
-			)
-			ineffective = append(ineffective, types.Transaction{RawTx: tx.GetRaw(), TxHeader: header})
-			invalidTxCount.Inc()
-			continue
-		}
+// outcome of the transaction executed against the state of the request.
+type outcome struct {
+	// ineffective is set if the transaction can't be executed.
+	ineffective *types.Transaction
+	// ctx is set if the transaction passed the checks that precede the block gas limit check,
+	// result is set if it was executed. the changes in ctx are not applied yet.
+	ctx    *core.Context
+	result types.TransactionWithResult
+}
+
+// run executes the transaction, the changes are applied to the state by the caller.
+// ineffective transactions are returned in the outcome, the error is returned only if it is internal.
+func (v *VM) run(logger log.Log, req *Request, tx types.Transaction, limit uint64) (outcome, error) {
+	t1 := time.Now()
+	header, err := req.Parse()
+	if err != nil {
+		logger.With().Warning("ineffective transaction. failed to parse",
+			tx.GetRaw().ID,
+			log.Err(err),
+		)
+		return outcome{ineffective: &types.Transaction{RawTx: tx.GetRaw()}}, nil
+	}
+	ctx := req.ctx
+	args := req.args
+
+	if header.GasPrice == 0 {
+		logger.With().Warning("ineffective transaction. zero gas price",
+			log.Object("header", header),
+			log.Object("account", &ctx.PrincipalAccount),
+		)
+		return outcome{ineffective: &types.Transaction{RawTx: tx.GetRaw()}}, nil
+	}
+	if intrinsic := req.schedule.scale(core.IntrinsicGas(ctx.Gas.BaseGas, tx.GetRaw().Raw)); ctx.PrincipalAccount.Balance < intrinsic {
+		logger.With().Warning("ineffective transaction. intrinstic gas not covered",
+			log.Object("header", header),
+			log.Object("account", &ctx.PrincipalAccount),
+			log.Uint64("intrinsic gas", intrinsic),
+		)
+		return outcome{ineffective: &types.Transaction{RawTx: tx.GetRaw()}}, nil
+	}
+	if out := v.checkLimit(logger, req.schedule, limit, tx.GetRaw(), outcome{ctx: ctx}); out.ineffective != nil {
+		return out, nil
+	}
 
-		t2 := time.Now()
-		logger.With().Debug("applying transaction",
+	// NOTE this part is executed only for transactions that weren't verified
+	// when saved into database by txs module
+	if !tx.Verified() && !req.Verify() {
+		logger.With().Warning("ineffective transaction. failed verify",
 			log.Object("header", header),
 			log.Object("account", &ctx.PrincipalAccount),
 		)
+		return outcome{ineffective: &types.Transaction{RawTx: tx.GetRaw()}, ctx: ctx}, nil
+	}
 
-		rst := types.TransactionWithResult{}
-		rst.Layer = lctx.Layer
+	if ctx.PrincipalAccount.NextNonce > ctx.Header.Nonce {
+		logger.With().Warning("ineffective transaction. nonce too low",
+			log.Object("header", header),
+			log.Object("account", &ctx.PrincipalAccount),
+		)
+		return outcome{ineffective: &types.Transaction{RawTx: tx.GetRaw(), TxHeader: header}, ctx: ctx}, nil
+	}
 
-		err = ctx.Consume(ctx.Header.MaxGas)
-		if err == nil {
-			err = ctx.PrincipalHandler.Exec(ctx, ctx.Header.Method, args)
-		}
-		if err != nil {
-			logger.With().Debug("transaction failed",
-				log.Object("header", header),
-				log.Object("account", &ctx.PrincipalAccount),
-				log.Err(err),
-			)
-			if errors.Is(err, core.ErrInternal) {
-				return nil, nil, 0, err
-			}
-		}
-		transactionDurationExecute.Observe(float64(time.Since(t2)))
+	t2 := time.Now()
+	logger.With().Debug("applying transaction",
+		log.Object("header", header),
+		log.Object("account", &ctx.PrincipalAccount),
+	)
 
-		rst.RawTx = txs[i].GetRaw()
-		rst.TxHeader = &ctx.Header
-		rst.Status = types.TransactionSuccess
-		if err != nil {
-			rst.Status = types.TransactionFailure
-			rst.Message = err.Error()
-		}
-		rst.Gas = ctx.Consumed()
-		rst.Fee = ctx.Fee()
-		rst.Addresses = ctx.Updated()
+	rst := types.TransactionWithResult{}
+	rst.Layer = req.lid
 
-		err = ctx.Apply(ss)
-		if err != nil {
-			return nil, nil, 0, fmt.Errorf("%w: %s", core.ErrInternal, err.Error())
+	err = ctx.Consume(ctx.Header.MaxGas)
+	if err == nil {
+		err = ctx.PrincipalHandler.Exec(ctx, ctx.Header.Method, args)
+	}
+	if err != nil {
+		logger.With().Debug("transaction failed",
+			log.Object("header", header),
+			log.Object("account", &ctx.PrincipalAccount),
+			log.Err(err),
+		)
+		if errors.Is(err, core.ErrInternal) {
+			return outcome{ctx: ctx}, err
 		}
-		fees += ctx.Fee()
-		limit -= ctx.Consumed()
+	}
+	transactionDurationExecute.Observe(float64(time.Since(t2)))
 
-		executed = append(executed, rst)
-		transactionDuration.Observe(float64(time.Since(t1)))
+	rst.RawTx = tx.GetRaw()
+	rst.TxHeader = &ctx.Header
+	rst.Status = types.TransactionSuccess
+	if err != nil {
+		rst.Status = types.TransactionFailure
+		rst.Message = err.Error()
 	}
-	return executed, ineffective, fees, nil
+	rst.Gas = ctx.Consumed()
+	rst.Fee = ctx.Fee()
+	rst.Addresses = ctx.Updated()
+
+	transactionDuration.Observe(float64(time.Since(t1)))
+	return outcome{ctx: ctx, result: rst}, nil
+}
+
+// checkLimit makes the transaction ineffective if its max gas is over the gas left in the layer.
+func (v *VM) checkLimit(logger log.Log, schedule GasSchedule, limit uint64, raw types.RawTx, out outcome) outcome {
+	ctx := out.ctx
+	if limit >= ctx.Header.MaxGas {
+		return out
+	}
+	logger.With().Warning("ineffective transaction. out of block gas",
+		log.Uint64("block gas limit", schedule.GasLimit),
+		log.Uint64("current limit", limit),
+		log.Object("header", &ctx.Header),
+		log.Object("account", &ctx.PrincipalAccount),
+	)
+	return outcome{ineffective: &types.Transaction{RawTx: raw}}
 }
 
 // Request used to implement 2-step validation flow.
@@ -427,9 +594,10 @@ type Request struct {
 	vm    *VM
 	cache *core.StagedCache
 
-	lid     types.LayerID
-	raw     types.RawTx
-	decoder *scale.Decoder
+	lid      types.LayerID
+	schedule GasSchedule
+	raw      types.RawTx
+	decoder  *scale.Decoder
 
 	// both ctx and args are set after successful Parse
 	ctx  *core.Context
@@ -442,7 +610,7 @@ func (r *Request) Parse() (*core.Header, error) {
 	if len(r.raw.Raw) > core.TxSizeLimit {
 		return nil, fmt.Errorf("%w: tx size (%d) > limit (%d)", core.ErrTxLimit, len(r.raw.Raw), core.TxSizeLimit)
 	}
-	header, ctx, args, err := parse(r.vm.logger, r.lid, r.vm.registry, r.cache, r.vm.cfg, r.raw.Raw, r.decoder)
+	header, ctx, args, err := parse(r.vm.logger, r.lid, r.vm.registry, r.cache, r.vm.cfg, r.schedule, r.raw.Raw, r.decoder)
 	if err != nil {
 		return nil, err
 	}
@@ -463,7 +631,7 @@ func (r *Request) Verify() bool {
 	return rst
 }
 
-func parse(logger log.Log, lid types.LayerID, reg *registry.Registry, loader core.AccountLoader, cfg Config, raw []byte, decoder *scale.Decoder) (*core.Header, *core.Context, scale.Encodable, error) {
+func parse(logger log.Log, lid types.LayerID, reg *registry.Registry, loader core.AccountLoader, cfg Config, schedule GasSchedule, raw []byte, decoder *scale.Decoder) (*core.Header, *core.Context, scale.Encodable, error) {
 	version, _, err := scale.DecodeCompact8(decoder)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("%w: failed to decode version %s", core.ErrMalformed, err.Error())
@@ -572,7 +740,7 @@ func parse(logger log.Log, lid types.LayerID, reg *registry.Registry, loader cor
 	ctx.Header.Principal = principal
 	ctx.Header.TemplateAddress = *templateAddress
 	ctx.Header.Method = method
-	ctx.Header.MaxGas = core.MaxGas(ctx.Gas.BaseGas, ctx.Gas.FixedGas, raw)
+	ctx.Header.MaxGas = schedule.scale(core.MaxGas(ctx.Gas.BaseGas, ctx.Gas.FixedGas, raw))
 	ctx.Header.GasPrice = output.GasPrice
 	ctx.Header.Nonce = output.Nonce
 	ctx.Args = args
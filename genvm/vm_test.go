@@ -31,7 +31,6 @@ import (
 	"github.com/spacemeshos/go-spacemesh/genvm/templates/vault"
 	"github.com/spacemeshos/go-spacemesh/genvm/templates/vesting"
 	"github.com/spacemeshos/go-spacemesh/genvm/templates/wallet"
-	"github.com/spacemeshos/go-spacemesh/hash"
 	"github.com/spacemeshos/go-spacemesh/log/logtest"
 	"github.com/spacemeshos/go-spacemesh/signing"
 	"github.com/spacemeshos/go-spacemesh/sql"
@@ -2254,7 +2253,7 @@ func TestBeforeEffectiveGenesis(t *testing.T) {
 	require.ErrorIs(t, err, core.ErrInternal)
 }
 
-func TestStateHashFromUpdatedAccounts(t *testing.T) {
+func TestStateHashIsAccountsRoot(t *testing.T) {
 	tt := newTester(t).addSingleSig(10).applyGenesis()
 
 	root, err := tt.GetStateRoot()
@@ -2262,32 +2261,177 @@ func TestStateHashFromUpdatedAccounts(t *testing.T) {
 	require.Equal(t, types.Hash32{}, root)
 
 	lid := types.GetEffectiveGenesis()
-	skipped, _, err := tt.Apply(testContext(lid), notVerified(
-		tt.selfSpawn(0),
-		tt.selfSpawn(1),
-		tt.spend(0, 2, 100),
-		tt.spend(1, 4, 100),
-	), nil)
-	require.NoError(tt, err)
-	require.Empty(tt, skipped)
+	for _, txs := range [][]types.RawTx{
+		{tt.selfSpawn(0), tt.selfSpawn(1), tt.spend(0, 2, 100), tt.spend(1, 4, 100)},
+		{tt.spend(0, 5, 100), tt.selfSpawn(2)},
+	} {
+		skipped, _, err := tt.Apply(testContext(lid), notVerified(txs...), nil)
+		require.NoError(tt, err)
+		require.Empty(tt, skipped)
+
+		snapshot, err := accounts.Snapshot(tt.db, lid)
+		require.NoError(t, err)
+		expected := types.AccountsRoot(snapshot)
+
+		statehash, err := layers.GetStateHash(tt.db, lid)
+		require.NoError(t, err)
+		require.Equal(t, expected, statehash)
 
-	expected := types.Hash32{}
-	hasher := hash.New()
-	encoder := scale.NewEncoder(hasher)
-	for _, pos := range []int{0, 1, 2, 4} {
-		account, err := accounts.Get(tt.db, tt.accounts[pos].getAddress(), lid)
+		root, err = tt.GetStateRoot()
 		require.NoError(t, err)
-		account.EncodeScale(encoder)
+		require.Equal(t, expected, root)
+		lid = lid.Add(1)
 	}
-	hasher.Sum(expected[:0])
+}
+
+func TestPruneStates(t *testing.T) {
+	tt := newTester(t).addSingleSig(2).applyGenesis()
+	tt.cfg.StateRetention = 2
 
-	statehash, err := layers.GetStateHash(tt.db, lid)
+	lid := types.GetEffectiveGenesis()
+	_, _, err := tt.Apply(testContext(lid), notVerified(tt.selfSpawn(0)), nil)
+	require.NoError(t, err)
+	for nonce := 1; nonce <= 5; nonce++ {
+		_, _, err := tt.Apply(testContext(lid.Add(uint32(nonce))), notVerified(tt.spend(0, 1, uint64(nonce))), nil)
+		require.NoError(t, err)
+	}
+	last := lid.Add(5)
+	pruned, err := accounts.Pruned(tt.db)
 	require.NoError(t, err)
-	require.Equal(t, expected, statehash)
+	require.Equal(t, last.Sub(tt.cfg.StateRetention), pruned)
 
-	root, err = tt.GetStateRoot()
+	_, err = accounts.Get(tt.db, tt.accounts[0].getAddress(), pruned.Sub(1))
+	require.ErrorIs(t, err, accounts.ErrPruned)
+	for layer := pruned; !layer.After(last); layer = layer.Add(1) {
+		account, err := accounts.Get(tt.db, tt.accounts[0].getAddress(), layer)
+		require.NoError(t, err)
+		require.Equal(t, layer, account.Layer)
+	}
+}
+
+func TestAccountProof(t *testing.T) {
+	tt := newTester(t).addSingleSig(3).applyGenesis()
+	lid := types.GetEffectiveGenesis()
+	_, _, err := tt.Apply(testContext(lid), notVerified(tt.selfSpawn(0), tt.spend(0, 1, 100)), nil)
+	require.NoError(t, err)
+
+	state, err := tt.GetLayerStateRoot(lid)
+	require.NoError(t, err)
+	snapshot, err := accounts.Snapshot(tt.db, lid)
 	require.NoError(t, err)
-	require.Equal(t, expected, root)
+	// proofs are served from the cached tree and from the tree built from the database
+	for _, cached := range []bool{true, false} {
+		if !cached {
+			tt.trees.reset()
+		}
+		for _, account := range snapshot {
+			proof, root, err := tt.AccountProof(account.Address, lid)
+			require.NoError(t, err)
+			require.Equal(t, state, root)
+			require.Equal(t, *account, proof.Account)
+			require.True(t, proof.Verify(root))
+		}
+	}
+	_, _, err = tt.AccountProof(types.GenerateAddress(types.RandomBytes(32)), lid)
+	require.ErrorIs(t, err, sql.ErrNotFound)
+}
+
+func TestGasSchedules(t *testing.T) {
+	tt := newTester(t).addSingleSig(2).applyGenesis()
+	genesis := types.GetEffectiveGenesis()
+	tt.VM.cfg.GasSchedules = []GasSchedule{
+		{Layer: genesis.Add(1), CostFactor: 200},
+		{Layer: genesis.Add(3), CostFactor: 50, GasLimit: 1},
+	}
+	require.NoError(t, ValidateGasSchedules(tt.VM.cfg.GasSchedules))
+	require.Equal(t, GasSchedule{CostFactor: baseCostFactor, GasLimit: tt.VM.cfg.GasLimit}, tt.GasSchedule(genesis))
+	require.Equal(t, GasSchedule{CostFactor: 200, GasLimit: tt.VM.cfg.GasLimit}, tt.GasSchedule(genesis.Add(2)))
+	require.Equal(t, GasSchedule{CostFactor: 50, GasLimit: 1}, tt.GasSchedule(genesis.Add(3)))
+
+	_, results, err := tt.Apply(testContext(genesis), notVerified(tt.selfSpawn(0)), nil)
+	require.NoError(t, err)
+	require.Equal(t, tt.estimateSpawnGas(0, 0), int(results[0].Gas))
+
+	for _, lid := range []types.LayerID{genesis.Add(1), genesis.Add(2)} {
+		nonce := tt.nextNonce(0)
+		_, results, err = tt.Apply(testContext(lid), notVerified(tt.spendWithNonce(0, 1, 100, nonce)), nil)
+		require.NoError(t, err)
+		require.Equal(t, 2*tt.estimateSpendGas(0, 1, 100, nonce), int(results[0].Gas))
+		require.NoError(t, layers.SetApplied(tt.db, lid, types.RandomBlockID()))
+	}
+
+	// the next layer activates the last schedule
+	nonce := tt.nextNonce(0)
+	header, err := tt.Validation(tt.spendWithNonce(0, 1, 100, nonce)).Parse()
+	require.NoError(t, err)
+	require.Equal(t, tt.estimateSpendGas(0, 1, 100, nonce)/2, int(header.MaxGas))
+	header, err = tt.ValidationAt(genesis.Add(2), tt.spendWithNonce(0, 1, 100, nonce)).Parse()
+	require.NoError(t, err)
+	require.Equal(t, 2*tt.estimateSpendGas(0, 1, 100, nonce), int(header.MaxGas))
+
+	skipped, _, err := tt.Apply(testContext(genesis.Add(3)), notVerified(tt.spendWithNonce(0, 1, 100, nonce)), nil)
+	require.NoError(t, err)
+	require.Len(t, skipped, 1, "out of the gas limit of the schedule")
+
+	require.Error(t, ValidateGasSchedules([]GasSchedule{{Layer: 1}}))
+	require.Error(t, ValidateGasSchedules([]GasSchedule{
+		{Layer: 2, CostFactor: 100},
+		{Layer: 2, CostFactor: 200},
+	}))
+}
+
+func TestParallelApply(t *testing.T) {
+	tt := newTester(t).addSingleSig(100).addMultisig(20, 2, 3).applyGenesis()
+	sequential := tt.VM
+	parallel := New(sql.InMemory(),
+		WithLogger(logtest.New(t)),
+		WithConfig(Config{GasLimit: math.MaxUint64, ApplyWorkers: 4}),
+	)
+	var genesis []core.Account
+	for _, account := range tt.accounts {
+		genesis = append(genesis, core.Account{Address: account.getAddress(), Balance: 1_000_000_000_000})
+	}
+	require.NoError(t, parallel.ApplyGenesis(genesis))
+
+	spends := notVerified(tt.randSpendN(200, 10)...)
+	layers := [][]types.Transaction{
+		notVerified(tt.spawnAll()...),
+		// the last transaction is ineffective due to the nonce that was already used
+		append(spends, notVerified(tt.spendWithNonce(0, 1, 10, 0))...),
+		notVerified(tt.randSpendN(200, 10)...),
+	}
+	lid := types.GetEffectiveGenesis()
+	var (
+		gas     uint64
+		skipped []types.Transaction
+	)
+	for i, txs := range layers {
+		if i == len(layers)-1 {
+			// half of the transactions are out of the block gas
+			sequential.cfg.GasLimit = gas / 2
+			parallel.cfg.GasLimit = gas / 2
+		}
+		expectedSkipped, expected, err := sequential.Apply(testContext(lid), txs, nil)
+		require.NoError(t, err)
+		var results []types.TransactionWithResult
+		skipped, results, err = parallel.Apply(testContext(lid), txs, nil)
+		require.NoError(t, err)
+		require.Equal(t, expectedSkipped, skipped)
+		require.Equal(t, expected, results)
+
+		expectedRoot, err := sequential.GetStateRoot()
+		require.NoError(t, err)
+		root, err := parallel.GetStateRoot()
+		require.NoError(t, err)
+		require.Equal(t, expectedRoot, root)
+
+		gas = 0
+		for _, rst := range results {
+			gas += rst.Gas
+		}
+		lid = lid.Add(1)
+	}
+	require.NotEmpty(t, skipped)
 }
 
 func BenchmarkWallet(b *testing.B) {
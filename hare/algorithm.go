@@ -39,13 +39,21 @@ const (
 
 // report is the termination report of the CP.
 type report struct {
-	id        types.LayerID // layer id
-	set       *Set          // agreed-upon set
-	completed bool          // whether the CP completed
+	id           types.LayerID // layer id
+	set          *Set          // agreed-upon set
+	completed    bool          // whether the CP completed
+	rounds       uint32        // number of rounds after the pre-round
+	participants int           // number of distinct eligible identities seen
 }
 
 func (proc *consensusProcess) report(completed bool) {
-	proc.comm.report <- report{id: proc.layer, set: proc.value, completed: completed}
+	proc.comm.report <- report{
+		id:           proc.layer,
+		set:          proc.value,
+		completed:    completed,
+		rounds:       proc.getRound() + 1, // pre-round is the max uint32
+		participants: proc.eTracker.Participants(),
+	}
 }
 
 type wcReport struct {
@@ -153,6 +161,12 @@ type communication struct {
 	// if the consensus process terminates, output the result to report
 	report chan report
 	wc     chan wcReport
+	// wal records messages published by the consensus process. may be nil.
+	wal *wal
+	// participation records eligibility of the node identity in every round. may be nil.
+	participation *participation
+	// rebroadcast republishes own messages after the node reconnects. may be nil.
+	rebroadcast *rebroadcaster
 }
 
 // consensusProcess is an entity (a single participant) in the Hare protocol.
@@ -226,8 +240,8 @@ func newConsensusProcess(
 		clock:     clock,
 	}
 	proc.ctx, proc.cancel = context.WithCancel(ctx)
-	proc.preRoundTracker = newPreRoundTracker(logger.WithContext(proc.ctx).WithFields(proc.layer), comm.mchOut, proc.eTracker, cfg.N/2+1, cfg.N)
-	proc.validator = newSyntaxContextValidator(signing, edVerifier, cfg.N/2+1, proc.statusValidator(), stateQuerier, ev, proc.mTracker, proc.eTracker, logger)
+	proc.preRoundTracker = newPreRoundTracker(logger.WithContext(proc.ctx).WithFields(proc.layer), comm.mchOut, proc.eTracker, cfg.CommitteeThreshold(), cfg.N)
+	proc.validator = newSyntaxContextValidator(signing, edVerifier, cfg.CommitteeThreshold(), proc.statusValidator(), stateQuerier, ev, proc.mTracker, proc.eTracker, logger)
 
 	return proc
 }
@@ -302,6 +316,7 @@ func (proc *consensusProcess) eventLoop() {
 	})
 
 	endOfRound := proc.clock.AwaitEndOfRound(preRound)
+	roundStart := time.Now()
 
 PreRound:
 	for {
@@ -312,10 +327,13 @@ PreRound:
 				proc.handleMessage(ctx, hmsg)
 			} else if emsg, ok := msg.(*types.HareEligibilityGossip); ok {
 				proc.onMalfeasance(emsg)
+			} else if req, ok := msg.(*snapshotRequest); ok {
+				req.result <- proc.snapshot()
 			} else {
 				proc.Log.Fatal("unexpected message type")
 			}
 		case <-endOfRound:
+			roundTime.WithLabelValues(roundType(preRound)).Observe(time.Since(roundStart).Seconds())
 			break PreRound
 		case <-proc.ctx.Done():
 			logger.With().Info("terminating: received signal during preround",
@@ -338,6 +356,7 @@ PreRound:
 	// start first iteration
 	proc.onRoundBegin(ctx)
 	endOfRound = proc.clock.AwaitEndOfRound(proc.getRound())
+	roundStart = time.Now()
 
 	for {
 		select {
@@ -349,10 +368,13 @@ PreRound:
 				proc.handleMessage(ctx, hmsg)
 			} else if emsg, ok := msg.(*types.HareEligibilityGossip); ok {
 				proc.onMalfeasance(emsg)
+			} else if req, ok := msg.(*snapshotRequest); ok {
+				req.result <- proc.snapshot()
 			} else {
 				proc.Log.Fatal("unexpected message type")
 			}
 		case <-endOfRound: // next round event
+			roundTime.WithLabelValues(roundType(proc.getRound())).Observe(time.Since(roundStart).Seconds())
 			proc.onRoundEnd(ctx)
 			if proc.terminating() {
 				return
@@ -365,12 +387,14 @@ PreRound:
 				logger.With().Warning("terminating: reached iterations limit",
 					log.Int("limit", proc.cfg.LimitIterations),
 					log.Uint32("current_round", round))
+				roundResults.WithLabelValues(roundType(round-1), roundIterationsLimit).Inc()
 				proc.report(notCompleted)
 				proc.terminate()
 				return
 			}
 			proc.onRoundBegin(ctx)
 			endOfRound = proc.clock.AwaitEndOfRound(round)
+			roundStart = time.Now()
 
 		case <-proc.ctx.Done(): // close event
 			logger.With().Debug("terminating: received signal",
@@ -438,23 +462,28 @@ func (proc *consensusProcess) handleMessage(ctx context.Context, m *Message) {
 			// validate syntax for early messages
 			if !proc.validator.SyntacticallyValidateMessage(ctx, m) {
 				logger.Warning("early message failed syntactic validation, discarding")
+				roundMessages.WithLabelValues(m.Type.String(), msgInvalid).Inc()
 				return
 			}
 
+			roundMessages.WithLabelValues(m.Type.String(), msgEarly).Inc()
 			proc.onEarlyMessage(ctx, m)
 			return
 		}
 
 		// not an early message but also contextually invalid
 		logger.With().Warning("late message failed contextual validation, discarding", log.Err(err))
+		roundMessages.WithLabelValues(m.Type.String(), msgLate).Inc()
 		return
 	}
 
 	// validate syntax for contextually valid messages
 	if !proc.validator.SyntacticallyValidateMessage(ctx, m) {
 		logger.Warning("message failed syntactic validation, discarding")
+		roundMessages.WithLabelValues(m.Type.String(), msgInvalid).Inc()
 		return
 	}
+	roundMessages.WithLabelValues(m.Type.String(), msgValid).Inc()
 
 	// warn on late pre-round msgs
 	if m.Type == pre && proc.getRound() != preRound {
@@ -516,10 +545,22 @@ func (proc *consensusProcess) sendMessage(ctx context.Context, msg *Message) boo
 	)
 	logger := proc.WithContext(ctx)
 
-	if err := proc.publisher.Publish(ctx, pubsub.HareProtocol, msg.Bytes()); err != nil {
+	buf := proc.comm.wal.sent(ctx, proc.layer, msg.Round)
+	if buf != nil {
+		logger.Info("republishing message recorded before restart")
+	} else {
+		buf = msg.Bytes()
+		proc.comm.wal.add(ctx, msg, true, buf)
+	}
+	if err := proc.publisher.Publish(ctx, pubsub.HareProtocol, buf); err != nil {
 		logger.With().Error("failed to broadcast round message", log.Err(err))
+		proc.comm.participation.eligible(ctx, proc.layer, msg.Round, proc.signer.NodeID(), msg.Eligibility.Count, reasonPublishFailed)
+		proc.comm.rebroadcast.add(msg, false)
 		return false
 	}
+	proc.comm.rebroadcast.add(msg, true)
+	roundMessages.WithLabelValues(msg.Type.String(), msgSent).Inc()
+	proc.comm.participation.participated(ctx, proc.layer, msg.Round, proc.signer.NodeID())
 
 	logger.Debug("should participate: message sent")
 	return true
@@ -542,12 +583,23 @@ func (proc *consensusProcess) onRoundEnd(ctx context.Context) {
 		if s != nil {
 			sStr = s.String()
 		}
+		switch {
+		case proc.proposalTracker.IsConflicting():
+			roundResults.WithLabelValues(proposal.String(), roundConflictingProposal).Inc()
+		case s == nil:
+			roundResults.WithLabelValues(proposal.String(), roundNoProposal).Inc()
+		default:
+			roundResults.WithLabelValues(proposal.String(), roundOk).Inc()
+		}
 		logger.Event().Debug("proposal round ended",
 			log.Int("set_size", proc.value.Size()),
 			log.String("proposed_set", sStr),
 			log.Bool("is_conflicting", proc.proposalTracker.IsConflicting()))
 	case commitRound:
 		logger.With().Debug("commit round ended", log.Int("set_size", proc.value.Size()))
+	case notifyRound:
+		// the process terminates as soon as it receives enough notifications
+		roundResults.WithLabelValues(notify.String(), roundNotEnoughNotify).Inc()
 	}
 }
 
@@ -568,7 +620,7 @@ func (proc *consensusProcess) beginStatusRound(ctx context.Context) {
 		proc.getRound(),
 		proc.comm.mchOut,
 		proc.eTracker,
-		proc.cfg.N/2+1,
+		proc.cfg.CommitteeThreshold(),
 		proc.cfg.N)
 
 	// check participation
@@ -594,6 +646,11 @@ func (proc *consensusProcess) beginProposalRound(ctx context.Context) {
 	// done with building proposal, reset statuses tracking
 	defer func() { proc.statusesTracker = nil }()
 
+	if proc.statusesTracker.IsSVPReady() {
+		roundResults.WithLabelValues(status.String(), roundOk).Inc()
+	} else {
+		roundResults.WithLabelValues(status.String(), roundNoSVP).Inc()
+	}
 	if proc.statusesTracker.IsSVPReady() && proc.shouldParticipate(ctx) {
 		builder, err := proc.initDefaultBuilder(proc.statusesTracker.ProposalSet(defaultSetSize))
 		if err != nil {
@@ -619,7 +676,7 @@ func (proc *consensusProcess) beginCommitRound(ctx context.Context) {
 		proc.getRound(),
 		proc.comm.mchOut,
 		proc.eTracker,
-		proc.cfg.N/2+1,
+		proc.cfg.CommitteeThreshold(),
 		proc.cfg.N,
 		proposedSet)
 
@@ -660,19 +717,22 @@ func (proc *consensusProcess) beginNotifyRound(ctx context.Context) {
 
 	if proc.proposalTracker.IsConflicting() {
 		logger.Warning("begin notify round: proposal is conflicting")
+		roundResults.WithLabelValues(commit.String(), roundConflictingProposal).Inc()
 		return
 	}
 
 	if !proc.commitTracker.HasEnoughCommits() {
 		logger.With().Warning("begin notify round: not enough commits",
-			log.Int("expected", proc.cfg.N/2+1),
+			log.Int("expected", proc.cfg.CommitteeThreshold()),
 			log.Object("actual", proc.commitTracker.CommitCount()))
+		roundResults.WithLabelValues(commit.String(), roundNotEnoughCommits).Inc()
 		return
 	}
 
 	cert := proc.commitTracker.BuildCertificate()
 	if cert == nil {
 		logger.Error("failed to build certificate at begin notify round")
+		roundResults.WithLabelValues(commit.String(), roundNoCertificate).Inc()
 		return
 	}
 
@@ -680,8 +740,10 @@ func (proc *consensusProcess) beginNotifyRound(ctx context.Context) {
 	if s == nil {
 		// it's possible we received a late conflicting proposal
 		logger.Error("failed to get proposal set at begin notify round")
+		roundResults.WithLabelValues(commit.String(), roundNoProposal).Inc()
 		return
 	}
+	roundResults.WithLabelValues(commit.String(), roundOk).Inc()
 
 	// update set & matching certificate
 	proc.value = s
@@ -811,7 +873,7 @@ func (proc *consensusProcess) processNotifyMsg(ctx context.Context, msg *Message
 		}
 	}
 
-	threshold := proc.cfg.N/2 + 1
+	threshold := proc.cfg.CommitteeThreshold()
 	notifyCount := proc.notifyTracker.NotificationsCount(s)
 	if notifyCount == nil {
 		proc.WithContext(ctx).Fatal("unexpected count")
@@ -835,6 +897,7 @@ func (proc *consensusProcess) processNotifyMsg(ctx context.Context, msg *Message
 		log.Object("notify_count", notifyCount),
 		log.Int("set_size", proc.value.Size()))
 	proc.report(completed)
+	roundResults.WithLabelValues(notify.String(), roundOk).Inc()
 	numIterations.Observe(float64(proc.getRound()))
 	proc.terminate()
 }
@@ -894,21 +957,30 @@ func (proc *consensusProcess) shouldParticipate(ctx context.Context) bool {
 	res, err := proc.oracle.IsIdentityActiveOnConsensusView(ctx, proc.signer.NodeID(), proc.layer)
 	if err != nil {
 		logger.With().Error("failed to check own identity for activeness", log.Err(err))
+		proc.recordEligibility(ctx, 0, reasonOracleError)
 		return false
 	}
 
 	if !res {
 		logger.Debug("should not participate: identity is not active")
+		proc.recordEligibility(ctx, 0, reasonNotActive)
 		return false
 	}
 
-	currentRole := proc.currentRole(ctx)
+	currentRole, err := proc.currentRole(ctx)
+	if err != nil {
+		logger.With().Error("failed to check eligibility", log.Err(err))
+		proc.recordEligibility(ctx, 0, reasonOracleError)
+		return false
+	}
 	if currentRole == passive {
 		logger.Debug("should not participate: passive")
+		proc.recordEligibility(ctx, 0, reasonNotEligible)
 		return false
 	}
 
 	eligibilityCount := proc.getEligibilityCount()
+	proc.recordEligibility(ctx, eligibilityCount, "")
 
 	// should participate
 	logger.With().Debug("should participate",
@@ -918,13 +990,15 @@ func (proc *consensusProcess) shouldParticipate(ctx context.Context) bool {
 	return true
 }
 
-// Returns the role matching the current round if eligible for this round, false otherwise.
-func (proc *consensusProcess) currentRole(ctx context.Context) role {
-	logger := proc.WithContext(ctx).WithFields(proc.layer)
+func (proc *consensusProcess) recordEligibility(ctx context.Context, count uint16, reason string) {
+	proc.comm.participation.eligible(ctx, proc.layer, proc.getRound(), proc.signer.NodeID(), count, reason)
+}
+
+// Returns the role matching the current round if eligible for this round, passive otherwise.
+func (proc *consensusProcess) currentRole(ctx context.Context) (role, error) {
 	proof, err := proc.oracle.Proof(ctx, proc.layer, proc.getRound())
 	if err != nil {
-		logger.With().Error("failed to get eligibility proof from oracle", log.Err(err))
-		return passive
+		return passive, fmt.Errorf("eligibility proof: %w", err)
 	}
 
 	k := proc.getRound()
@@ -932,20 +1006,19 @@ func (proc *consensusProcess) currentRole(ctx context.Context) role {
 	size := expectedCommitteeSize(k, proc.cfg.N, proc.cfg.ExpectedLeaders)
 	eligibilityCount, err := proc.oracle.CalcEligibility(ctx, proc.layer, k, size, proc.nid, proof)
 	if err != nil {
-		logger.With().Error("failed to check eligibility", log.Err(err))
-		return passive
+		return passive, fmt.Errorf("calc eligibility: %w", err)
 	}
 
 	proc.setEligibilityCount(eligibilityCount)
 
 	if eligibilityCount > 0 { // eligible
 		if proc.currentRound() == proposalRound {
-			return leader
+			return leader, nil
 		}
-		return active
+		return active, nil
 	}
 
-	return passive
+	return passive, nil
 }
 
 func (proc *consensusProcess) getEligibilityCount() uint16 {
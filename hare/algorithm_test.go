@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -324,6 +325,41 @@ func TestConsensusProcess_handleMessage(t *testing.T) {
 	r.Equal(1, len(proc.pending))
 }
 
+func TestConsensusProcess_handleMessageMetrics(t *testing.T) {
+	proc := generateConsensusProcess(t)
+	mValidator := &mockMessageValidator{}
+	proc.validator = mValidator
+	signer, err := signing.NewEdSigner()
+	require.NoError(t, err)
+	msg := BuildPreRoundMsg(signer, NewSetFromValues(types.ProposalID{1}), types.VrfSignature{3})
+
+	for _, tc := range []struct {
+		syntax  bool
+		context error
+		result  string
+	}{
+		{syntax: false, result: msgInvalid},
+		{syntax: true, result: msgValid},
+		{syntax: true, context: errors.New("not valid"), result: msgLate},
+		{syntax: true, context: errEarlyMsg, result: msgEarly},
+	} {
+		mValidator.syntaxValid = tc.syntax
+		mValidator.contextValid = tc.context
+		counter := roundMessages.WithLabelValues(pre.String(), tc.result)
+		before := testutil.ToFloat64(counter)
+		proc.handleMessage(context.Background(), msg)
+		require.Equal(t, before+1, testutil.ToFloat64(counter), tc.result)
+	}
+}
+
+func TestRoundType(t *testing.T) {
+	require.Equal(t, "preround", roundType(preRound))
+	for i, expected := range []string{"status", "proposal", "commit", "notify"} {
+		require.Equal(t, expected, roundType(uint32(i)))
+		require.Equal(t, expected, roundType(uint32(i+RoundsPerIteration)))
+	}
+}
+
 func TestConsensusProcess_nextRound(t *testing.T) {
 	broker := buildBroker(t, t.Name())
 	broker.mockSyncS.EXPECT().IsSynced(gomock.Any()).Return(true).AnyTimes()
@@ -0,0 +1,145 @@
+package hare
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/spacemeshos/go-spacemesh/codec"
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/p2p"
+	"github.com/spacemeshos/go-spacemesh/p2p/pubsub"
+)
+
+//go:generate scalegen -types MessageBatch
+
+// maxBatchSize is the number of messages after which the batch is published without waiting.
+const maxBatchSize = 100
+
+// errPartialBatch is returned for the batch with invalid messages, so that it is not relayed.
+// the valid messages are relayed in a new batch instead.
+var errPartialBatch = errors.New("hare batch with invalid messages")
+
+// MessageBatch is a collection of hare messages for different layers published by the same node.
+type MessageBatch struct {
+	Messages []Message `scale:"max=100"`
+}
+
+// batchPublisher aggregates hare messages published while several consensus
+// processes are running and publishes them as a single batch.
+type batchPublisher struct {
+	pubsub.Publisher
+	logger     log.Log
+	ctx        context.Context
+	interval   time.Duration
+	concurrent func() int
+
+	mu      sync.Mutex
+	pending []Message
+	timer   *time.Timer
+}
+
+func newBatchPublisher(ctx context.Context, publisher pubsub.Publisher, interval time.Duration, concurrent func() int, logger log.Log) *batchPublisher {
+	return &batchPublisher{
+		Publisher:  publisher,
+		logger:     logger,
+		ctx:        ctx,
+		interval:   interval,
+		concurrent: concurrent,
+	}
+}
+
+// Publish adds hare message to the batch if more than one consensus process is running.
+// Other messages are published immediately.
+func (b *batchPublisher) Publish(ctx context.Context, protocol string, msg []byte) error {
+	if protocol != pubsub.HareProtocol || b.concurrent() < 2 {
+		return b.Publisher.Publish(ctx, protocol, msg)
+	}
+	hareMsg, err := MessageFromBuffer(msg)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pending = append(b.pending, *hareMsg)
+	if len(b.pending) >= maxBatchSize {
+		b.timer.Stop()
+		b.publishLocked()
+	} else if len(b.pending) == 1 {
+		b.timer = time.AfterFunc(b.interval, b.flush)
+	}
+	return nil
+}
+
+func (b *batchPublisher) flush() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.publishLocked()
+}
+
+func (b *batchPublisher) publishLocked() {
+	pending := b.pending
+	b.pending = nil
+	var (
+		protocol string
+		buf      []byte
+	)
+	switch len(pending) {
+	case 0:
+		return
+	case 1:
+		protocol, buf = pubsub.HareProtocol, pending[0].Bytes()
+	default:
+		protocol, buf = pubsub.HareBatchProtocol, codec.MustEncode(&MessageBatch{Messages: pending})
+	}
+	if err := b.Publisher.Publish(b.ctx, protocol, buf); err != nil {
+		b.logger.With().Error("failed to publish hare messages",
+			log.String("protocol", protocol),
+			log.Int("messages", len(pending)),
+			log.Err(err),
+		)
+		return
+	}
+	batchSize.Observe(float64(len(pending)))
+}
+
+// HandleBatch unbatches hare messages and passes them to the broker.
+// The batch is relayed as is only if all of its messages are valid. Otherwise the valid messages
+// are published as a new batch, and the original batch is not relayed.
+func (b *Broker) HandleBatch(ctx context.Context, peer p2p.Peer, msg []byte) error {
+	var batch MessageBatch
+	if err := codec.Decode(msg, &batch); err != nil {
+		return fmt.Errorf("%w: decode hare batch: %v", pubsub.ErrValidationReject, err)
+	}
+	unbatchedMessages.Add(float64(len(batch.Messages)))
+	var (
+		last  error
+		valid []Message
+	)
+	for i := range batch.Messages {
+		if err := b.HandleMessage(ctx, peer, batch.Messages[i].Bytes()); err != nil {
+			last = err
+			continue
+		}
+		valid = append(valid, batch.Messages[i])
+	}
+	switch {
+	case len(valid) == len(batch.Messages):
+		return nil
+	case len(valid) == 0:
+		return last
+	}
+	relay := codec.MustEncode(&MessageBatch{Messages: valid})
+	if err := b.publisher.Publish(ctx, pubsub.HareBatchProtocol, relay); err != nil {
+		b.WithContext(ctx).With().Warning("failed to relay valid messages of hare batch",
+			log.Int("valid", len(valid)),
+			log.Int("messages", len(batch.Messages)),
+			log.Err(err),
+		)
+		return fmt.Errorf("relay hare batch: %w", err)
+	}
+	return fmt.Errorf("%w: relayed %d of %d messages", errPartialBatch, len(valid), len(batch.Messages))
+}
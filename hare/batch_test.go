@@ -0,0 +1,114 @@
+package hare
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/codec"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/p2p/pubsub"
+	pubsubmocks "github.com/spacemeshos/go-spacemesh/p2p/pubsub/mocks"
+)
+
+func TestBatchPublisher(t *testing.T) {
+	publisher := pubsubmocks.NewMockPublisher(gomock.NewController(t))
+	concurrent := 1
+	b := newBatchPublisher(context.Background(), publisher, 10*time.Millisecond, func() int { return concurrent }, logtest.New(t))
+
+	// single consensus process publishes immediately
+	msg := createMessage(t, 10)
+	publisher.EXPECT().Publish(gomock.Any(), pubsub.HareProtocol, msg)
+	require.NoError(t, b.Publish(context.Background(), pubsub.HareProtocol, msg))
+
+	// other protocols are never batched
+	concurrent = 2
+	publisher.EXPECT().Publish(gomock.Any(), pubsub.MalfeasanceProof, []byte{1})
+	require.NoError(t, b.Publish(context.Background(), pubsub.MalfeasanceProof, []byte{1}))
+
+	published := make(chan []byte, 1)
+	publisher.EXPECT().Publish(gomock.Any(), pubsub.HareBatchProtocol, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, buf []byte) error {
+			published <- buf
+			return nil
+		})
+	msgs := [][]byte{createMessage(t, 10), createMessage(t, 11)}
+	for _, msg := range msgs {
+		require.NoError(t, b.Publish(context.Background(), pubsub.HareProtocol, msg))
+	}
+	select {
+	case buf := <-published:
+		var batch MessageBatch
+		require.NoError(t, codec.Decode(buf, &batch))
+		require.Len(t, batch.Messages, len(msgs))
+		for i := range msgs {
+			require.Equal(t, msgs[i], batch.Messages[i].Bytes())
+		}
+	case <-time.After(time.Second):
+		require.FailNow(t, "batch was not published")
+	}
+}
+
+func TestBroker_HandleBatch(t *testing.T) {
+	broker := buildBroker(t, t.Name())
+	broker.mockSyncS.EXPECT().IsSynced(gomock.Any()).Return(true).AnyTimes()
+	broker.mockSyncS.EXPECT().IsBeaconSynced(gomock.Any()).Return(true).AnyTimes()
+	broker.mockStateQ.EXPECT().IsIdentityActiveOnConsensusView(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil).AnyTimes()
+	broker.mockMesh.EXPECT().GetMalfeasanceProof(gomock.Any()).AnyTimes()
+	broker.Start(context.Background())
+	t.Cleanup(broker.Close)
+
+	require.ErrorIs(t, broker.HandleBatch(context.Background(), "", []byte{1, 2, 3}), pubsub.ErrValidationReject)
+
+	lids := []types.LayerID{1, 2}
+	var batch MessageBatch
+	inboxes := make([]chan any, 0, len(lids))
+	for _, lid := range lids {
+		inbox, _, err := broker.Register(context.Background(), lid)
+		require.NoError(t, err)
+		inboxes = append(inboxes, inbox)
+		msg, err := MessageFromBuffer(createMessage(t, lid))
+		require.NoError(t, err)
+		batch.Messages = append(batch.Messages, *msg)
+	}
+	require.NoError(t, broker.HandleBatch(context.Background(), "", codec.MustEncode(&batch)))
+	for i, lid := range lids {
+		waitForMessages(t, inboxes[i], lid, 1)
+	}
+	// only the valid messages are relayed
+	invalid := func() Message {
+		msg, err := MessageFromBuffer(createMessage(t, lids[0]))
+		require.NoError(t, err)
+		msg.Signature = types.RandomEdSignature()
+		return *msg
+	}
+	validMsg := func() Message {
+		msg, err := MessageFromBuffer(createMessage(t, lids[0]))
+		require.NoError(t, err)
+		return *msg
+	}
+	partial := MessageBatch{Messages: []Message{validMsg(), invalid()}}
+	broker.mockPublisher.EXPECT().Publish(gomock.Any(), pubsub.HareBatchProtocol, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, buf []byte) error {
+			var relayed MessageBatch
+			require.NoError(t, codec.Decode(buf, &relayed))
+			require.Len(t, relayed.Messages, 1)
+			require.Equal(t, partial.Messages[0].Bytes(), relayed.Messages[0].Bytes())
+			return nil
+		})
+	require.ErrorIs(t, broker.HandleBatch(context.Background(), "", codec.MustEncode(&partial)), errPartialBatch)
+
+	errPublish := errors.New("publish")
+	partial = MessageBatch{Messages: []Message{validMsg(), invalid()}}
+	broker.mockPublisher.EXPECT().Publish(gomock.Any(), pubsub.HareBatchProtocol, gomock.Any()).Return(errPublish)
+	require.ErrorIs(t, broker.HandleBatch(context.Background(), "", codec.MustEncode(&partial)), errPublish)
+
+	// nothing is relayed if all messages are invalid
+	partial = MessageBatch{Messages: []Message{invalid()}}
+	require.Error(t, broker.HandleBatch(context.Background(), "", codec.MustEncode(&partial)))
+}
@@ -33,6 +33,8 @@ type Broker struct {
 	cfg           config.Config
 	msh           mesh
 	edVerifier    *signing.EdVerifier
+	sigBatcher    *sigBatcher              // verifies signatures in batches. may be nil
+	budget        *signerBudget            // limits messages accepted from a single identity. may be nil
 	roleValidator validator                // provides eligibility validation
 	stateQuerier  stateQuerier             // provides activeness check
 	nodeSyncState system.SyncStateProvider // provider function to check if the node is currently synced
@@ -42,7 +44,8 @@ type Broker struct {
 	pending       map[types.LayerID][]any // the buffer of pending early messages for the next layer
 	latestLayer   types.LayerID           // the latest layer to attempt register (successfully or unsuccessfully)
 	minDeleted    types.LayerID
-	limit         int // max number of simultaneous consensus processes
+	limit         int  // max number of simultaneous consensus processes
+	wal           *wal // records valid messages to replay them after restart. may be nil
 
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -129,7 +132,7 @@ func (b *Broker) validateTiming(ctx context.Context, m *Message) error {
 }
 
 // HandleMessage separate listener routine that receives gossip messages and adds them to the priority queue.
-func (b *Broker) HandleMessage(ctx context.Context, _ p2p.Peer, msg []byte) error {
+func (b *Broker) HandleMessage(ctx context.Context, peer p2p.Peer, msg []byte) error {
 	select {
 	case <-ctx.Done():
 		return errClosed
@@ -172,12 +175,18 @@ func (b *Broker) HandleMessage(ctx context.Context, _ p2p.Peer, msg []byte) erro
 		isEarly = true
 	}
 
-	if !b.edVerifier.Verify(signing.HARE, hareMsg.SmesherID, hareMsg.SignedBytes(), hareMsg.Signature) {
+	if !b.verifySignature(ctx, hareMsg) {
 		logger.With().Error("failed to verify signature",
 			log.Int("sig_len", len(hareMsg.Signature)),
 		)
 		return fmt.Errorf("verify ed25519 signature")
 	}
+
+	if !b.budget.spend(hareMsg.SmesherID, msgLayer, hareMsg.Round) {
+		overBudgetMessages.Inc()
+		logger.With().Debug("identity exceeded hare message budget", log.Stringer("peer", peer))
+		return errOverBudget
+	}
 	hareMsg.signedHash = types.BytesToHash(hareMsg.InnerMessage.HashBytes())
 
 	if err := checkIdentity(ctx, b.Log, hareMsg, b.stateQuerier); err != nil {
@@ -209,6 +218,8 @@ func (b *Broker) HandleMessage(ctx context.Context, _ p2p.Peer, msg []byte) erro
 		return fmt.Errorf("known malicious %v", hareMsg.SmesherID.String())
 	}
 
+	b.wal.add(ctx, hareMsg, false, msg)
+
 	if isEarly {
 		return b.handleEarlyMessage(logger, msgLayer, hareMsg.SmesherID, hareMsg)
 	}
@@ -231,6 +242,13 @@ func (b *Broker) HandleMessage(ctx context.Context, _ p2p.Peer, msg []byte) erro
 	return nil
 }
 
+func (b *Broker) verifySignature(ctx context.Context, msg *Message) bool {
+	if b.sigBatcher != nil {
+		return b.sigBatcher.Verify(ctx, msg.Layer, msg.SmesherID, msg.SignedBytes(), msg.Signature)
+	}
+	return b.edVerifier.Verify(signing.HARE, msg.SmesherID, msg.SignedBytes(), msg.Signature)
+}
+
 func (b *Broker) handleMaliciousHareMessage(
 	ctx context.Context,
 	nodeID types.NodeID,
@@ -242,7 +260,7 @@ func (b *Broker) handleMaliciousHareMessage(
 		b.mu.Lock()
 		defer b.mu.Unlock()
 		if _, ok := b.trackers[msg.Layer]; !ok {
-			b.trackers[msg.Layer] = NewEligibilityTracker(b.cfg.N)
+			b.trackers[msg.Layer] = NewEligibilityTracker(b.cfg.EpochParams(msg.Layer.GetEpoch()).N)
 		}
 		return !b.trackers[msg.Layer].Track(nodeID, msg.Round, msg.Eligibility.Count, false)
 	}()
@@ -371,6 +389,7 @@ func (b *Broker) CleanOldLayers(current types.LayerID) {
 			delete(b.pending, lid)
 		}
 	}
+	b.budget.prune(b.minDeleted)
 }
 
 // Register a layer to receive messages
@@ -410,7 +429,7 @@ func (b *Broker) Register(ctx context.Context, id types.LayerID) (chan any, *Eli
 	}
 	delete(b.pending, id)
 	if _, ok := b.trackers[id]; !ok {
-		b.trackers[id] = NewEligibilityTracker(b.cfg.N)
+		b.trackers[id] = NewEligibilityTracker(b.cfg.EpochParams(id.GetEpoch()).N)
 	}
 	return outboxCh, b.trackers[id], nil
 }
@@ -522,6 +522,70 @@ func TestBroker_PubkeyExtraction(t *testing.T) {
 	}
 }
 
+func TestBroker_BatchVerify(t *testing.T) {
+	broker := buildBroker(t, t.Name())
+	broker.sigBatcher = newSigBatcher(broker.edVerifier, 10*time.Millisecond, instanceID1.GetEpoch(), logtest.New(t))
+	broker.mockSyncS.EXPECT().IsSynced(gomock.Any()).Return(true).AnyTimes()
+	broker.mockSyncS.EXPECT().IsBeaconSynced(gomock.Any()).Return(true).AnyTimes()
+	broker.mockStateQ.EXPECT().IsIdentityActiveOnConsensusView(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil).AnyTimes()
+	broker.mockMesh.EXPECT().GetMalfeasanceProof(gomock.Any())
+	broker.Start(context.Background())
+	t.Cleanup(broker.Close)
+	inbox, _, _ := broker.Register(context.Background(), instanceID1)
+
+	signer, err := signing.NewEdSigner()
+	require.NoError(t, err)
+	invalid := BuildPreRoundMsg(signer, NewSetFromValues(types.RandomProposalID()), types.EmptyVrfSignature)
+	invalid.Signature = signer.Sign(signing.HARE, []byte("other"))
+	require.Error(t, broker.HandleMessage(context.Background(), "", mustEncode(t, invalid)))
+
+	m := BuildPreRoundMsg(signer, NewSetFromValues(types.RandomProposalID()), types.EmptyVrfSignature)
+	require.NoError(t, broker.HandleMessage(context.Background(), "", mustEncode(t, m)))
+	select {
+	case msg := <-inbox:
+		require.Equal(t, m.SmesherID, msg.(*Message).SmesherID)
+	case <-time.After(time.Second):
+		require.FailNow(t, "timed out")
+	}
+}
+
+func TestBroker_SignerBudget(t *testing.T) {
+	broker := buildBroker(t, t.Name())
+	broker.budget = newSignerBudget(1)
+	broker.mockSyncS.EXPECT().IsSynced(gomock.Any()).Return(true).AnyTimes()
+	broker.mockSyncS.EXPECT().IsBeaconSynced(gomock.Any()).Return(true).AnyTimes()
+	broker.mockStateQ.EXPECT().IsIdentityActiveOnConsensusView(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil).AnyTimes()
+	broker.mockMesh.EXPECT().GetMalfeasanceProof(gomock.Any()).Times(2)
+	broker.Start(context.Background())
+	t.Cleanup(broker.Close)
+	inbox, _, _ := broker.Register(context.Background(), instanceID1)
+
+	buildMsg := func(signer *signing.EdSigner) []byte {
+		return mustEncode(t, BuildPreRoundMsg(signer, NewSetFromValues(types.RandomProposalID()), types.EmptyVrfSignature))
+	}
+	signer, err := signing.NewEdSigner()
+	require.NoError(t, err)
+	other, err := signing.NewEdSigner()
+	require.NoError(t, err)
+	require.NoError(t, broker.HandleMessage(context.Background(), "a", buildMsg(signer)))
+	// the budget is charged to the signer regardless of the relaying peer, the peer is not penalized
+	err = broker.HandleMessage(context.Background(), "b", buildMsg(signer))
+	require.ErrorIs(t, err, errOverBudget)
+	require.NotErrorIs(t, err, pubsub.ErrValidationReject)
+	require.NoError(t, broker.HandleMessage(context.Background(), "a", buildMsg(other)))
+	require.Len(t, inbox, 2)
+
+	// the budget is not charged for messages with invalid signatures
+	invalid := BuildPreRoundMsg(other, NewSetFromValues(types.RandomProposalID()), types.EmptyVrfSignature)
+	invalid.Signature = types.EmptyEdSignature
+	require.Error(t, broker.HandleMessage(context.Background(), "a", mustEncode(t, invalid)))
+	require.Len(t, broker.budget.counts, 2)
+
+	broker.Unregister(context.Background(), instanceID1)
+	broker.CleanOldLayers(instanceID1)
+	require.Empty(t, broker.budget.counts)
+}
+
 func Test_newMsg(t *testing.T) {
 	signer, err := signing.NewEdSigner()
 	require.NoError(t, err)
@@ -0,0 +1,62 @@
+package hare
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+)
+
+// errOverBudget is returned for messages beyond the budget of the signer. the message is dropped
+// without rejecting it, as the relaying peer can't know the budget of the signer was exhausted.
+var errOverBudget = errors.New("identity exceeded hare message budget")
+
+type budgetKey struct {
+	layer  types.LayerID
+	round  uint32
+	signer types.NodeID
+}
+
+// signerBudget counts messages of every identity in every round and limits them to protect
+// the consensus from a single identity that floods the network.
+// all methods are no-op on a nil signerBudget.
+type signerBudget struct {
+	limit int
+
+	mu     sync.Mutex
+	counts map[budgetKey]int
+}
+
+func newSignerBudget(limit int) *signerBudget {
+	return &signerBudget{limit: limit, counts: map[budgetKey]int{}}
+}
+
+// spend accounts a message signed by the identity. returns false if the identity exhausted its budget for the round.
+// the signature must be verified before the message is accounted, otherwise anyone can exhaust the budget of others.
+func (b *signerBudget) spend(signer types.NodeID, lid types.LayerID, round uint32) bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	key := budgetKey{layer: lid, round: round, signer: signer}
+	if b.counts[key] >= b.limit {
+		return false
+	}
+	b.counts[key]++
+	return true
+}
+
+// prune drops the counters for layers up to and including lid.
+func (b *signerBudget) prune(lid types.LayerID) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for key := range b.counts {
+		if !key.layer.After(lid) {
+			delete(b.counts, key)
+		}
+	}
+}
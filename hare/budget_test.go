@@ -0,0 +1,33 @@
+package hare
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+)
+
+func TestSignerBudget(t *testing.T) {
+	b := newSignerBudget(2)
+	lid := types.LayerID(10)
+	a, other := types.RandomNodeID(), types.RandomNodeID()
+	for i := 0; i < 2; i++ {
+		require.True(t, b.spend(a, lid, preRound))
+	}
+	require.False(t, b.spend(a, lid, preRound))
+	require.True(t, b.spend(other, lid, preRound))
+	require.True(t, b.spend(a, lid, statusRound))
+	require.True(t, b.spend(a, lid.Add(1), preRound))
+
+	b.prune(lid)
+	require.True(t, b.spend(a, lid, preRound))
+	require.True(t, b.spend(a, lid.Add(1), preRound))
+	require.False(t, b.spend(a, lid.Add(1), preRound))
+}
+
+func TestSignerBudget_Nil(t *testing.T) {
+	var b *signerBudget
+	require.True(t, b.spend(types.RandomNodeID(), types.LayerID(10), preRound))
+	b.prune(types.LayerID(10))
+}
@@ -1,20 +1,78 @@
 package config
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+)
+
+const (
+	// LimitSkip stops the oldest consensus process to start a new one beyond LimitConcurrent.
+	LimitSkip = "skip"
+	// LimitQueue delays a new consensus process beyond LimitConcurrent until a running one terminates.
+	LimitQueue = "queue"
+)
 
 // Config is the configuration of the Hare.
 type Config struct {
 	N               int           `mapstructure:"hare-committee-size"`   // total number of active parties
+	Threshold       int           `mapstructure:"hare-threshold"`        // number of votes required to prove a value, majority of N if zero
 	RoundDuration   time.Duration `mapstructure:"hare-round-duration"`   // the duration of a single round
 	WakeupDelta     time.Duration `mapstructure:"hare-wakeup-delta"`     // the wakeup delta after tick
 	ExpectedLeaders int           `mapstructure:"hare-exp-leaders"`      // the expected number of leaders
 	LimitIterations int           `mapstructure:"hare-limit-iterations"` // limit on number of iterations
 	LimitConcurrent int           `mapstructure:"hare-limit-concurrent"` // limit number of concurrent CPs
+	LimitPolicy     string        `mapstructure:"hare-limit-policy"`     // what to do with a CP beyond LimitConcurrent
 	StopAtxGrading  uint32        `mapstructure:"stop-atx-grading"`
+	WAL             bool          `mapstructure:"hare-wal"` // persist messages to resume consensus after restart
+
+	// Turbo finalizes every layer with the proposals known at the end of the wakeup delta,
+	// without running the protocol. It is only safe on a network with a single node.
+	Turbo bool `mapstructure:"hare-turbo"`
+
+	// BatchInterval is how long own messages are aggregated while several consensus processes
+	// are running, before they are published as a single batch. Batching is disabled if zero.
+	BatchInterval time.Duration `mapstructure:"hare-batch-interval"`
+
+	// VerifyWindow is how long signatures of incoming messages are collected before they
+	// are verified as a batch. Batch verification is disabled if zero.
+	VerifyWindow time.Duration `mapstructure:"hare-verify-window"`
+
+	// BatchVerifyEpoch is the first epoch in which signatures of incoming messages are verified
+	// with the ZIP-215 rules, which allow batch verification. ZIP-215 accepts some signatures that
+	// are rejected otherwise, so the epoch must be agreed upon by the whole network.
+	// Batch verification is disabled if zero.
+	BatchVerifyEpoch types.EpochID `mapstructure:"hare-batch-verify-epoch"`
+
+	// RebroadcastPeers is the number of connected peers below which the node is considered
+	// partitioned. Own messages of layers in progress are republished after the node reconnects.
+	// Rebroadcast is disabled if zero.
+	RebroadcastPeers int `mapstructure:"hare-rebroadcast-peers"`
+
+	// IdentityBudget is the maximal number of messages accepted from a single identity in a round.
+	// Messages beyond the budget are dropped. The budget is disabled if zero.
+	IdentityBudget int `mapstructure:"hare-identity-budget"`
+
+	// Updates change committee parameters starting from the first layer of the specified epochs.
+	// Updates must be ordered by epoch.
+	Updates []EpochParams `mapstructure:"hare-epoch-params"`
 
 	Hdist uint32
 }
 
+// EpochParams are the committee parameters that are in effect starting from the Epoch.
+type EpochParams struct {
+	Epoch           types.EpochID `mapstructure:"epoch" json:"epoch"`
+	N               int           `mapstructure:"committee-size" json:"committee-size"`
+	Threshold       int           `mapstructure:"threshold" json:"threshold"`
+	ExpectedLeaders int           `mapstructure:"exp-leaders" json:"exp-leaders"`
+	// RoundDuration is the duration of a single round, zero keeps the duration of the previous epochs.
+	// it is changed at the epoch boundary, so that all nodes run the rounds of a layer with the same duration.
+	RoundDuration time.Duration `mapstructure:"round-duration" json:"round-duration"`
+}
+
 // DefaultConfig returns the default configuration for the hare.
 func DefaultConfig() Config {
 	return Config{
@@ -24,6 +82,102 @@ func DefaultConfig() Config {
 		ExpectedLeaders: 5,
 		LimitIterations: 5,
 		LimitConcurrent: 5,
+		LimitPolicy:     LimitSkip,
 		Hdist:           20,
 	}
 }
+
+// MaxRoundDuration returns the longest duration a round may take in any epoch.
+func (c Config) MaxRoundDuration() time.Duration {
+	longest := c.RoundDuration
+	for _, update := range c.Updates {
+		if update.RoundDuration > longest {
+			longest = update.RoundDuration
+		}
+	}
+	return longest
+}
+
+// CommitteeThreshold returns the number of votes required to prove a value.
+func (c Config) CommitteeThreshold() int {
+	return threshold(c.N, c.Threshold)
+}
+
+// EpochParams returns the committee parameters that are in effect in the epoch.
+func (c Config) EpochParams(epoch types.EpochID) EpochParams {
+	params := EpochParams{
+		N:               c.N,
+		Threshold:       c.Threshold,
+		ExpectedLeaders: c.ExpectedLeaders,
+		RoundDuration:   c.RoundDuration,
+	}
+	for _, update := range c.Updates {
+		if update.Epoch > epoch {
+			break
+		}
+		duration := params.RoundDuration
+		params = update
+		if params.RoundDuration == 0 {
+			params.RoundDuration = duration
+		}
+	}
+	params.Threshold = threshold(params.N, params.Threshold)
+	return params
+}
+
+// ForEpoch returns the config with committee parameters that are in effect in the epoch.
+func (c Config) ForEpoch(epoch types.EpochID) Config {
+	params := c.EpochParams(epoch)
+	c.N, c.Threshold, c.ExpectedLeaders = params.N, params.Threshold, params.ExpectedLeaders
+	c.RoundDuration = params.RoundDuration
+	return c
+}
+
+// Validate checks that base committee parameters and all updates are consistent.
+func (c Config) Validate() error {
+	if err := validateParams(c.N, c.Threshold, c.ExpectedLeaders); err != nil {
+		return err
+	}
+	switch c.LimitPolicy {
+	case "", LimitSkip, LimitQueue:
+	default:
+		return fmt.Errorf("unknown limit policy %q", c.LimitPolicy)
+	}
+	var prev types.EpochID
+	for i, update := range c.Updates {
+		if update.Epoch == 0 {
+			return errors.New("epoch params must have activation epoch")
+		}
+		if i > 0 && update.Epoch <= prev {
+			return fmt.Errorf("epoch params must be ordered by epoch: %s follows %s", update.Epoch, prev)
+		}
+		if err := validateParams(update.N, update.Threshold, update.ExpectedLeaders); err != nil {
+			return fmt.Errorf("epoch params for %s: %w", update.Epoch, err)
+		}
+		if update.RoundDuration < 0 {
+			return fmt.Errorf("epoch params for %s: negative round duration", update.Epoch)
+		}
+		prev = update.Epoch
+	}
+	return nil
+}
+
+func validateParams(n, thresh, leaders int) error {
+	if n <= 0 {
+		return fmt.Errorf("committee size (%d) must be positive", n)
+	}
+	if thresh != 0 && (thresh <= n/2 || thresh > n) {
+		return fmt.Errorf("threshold (%d) must be a majority of committee size (%d)", thresh, n)
+	}
+	if leaders <= 0 {
+		return fmt.Errorf("expected leaders (%d) must be positive", leaders)
+	}
+	return nil
+}
+
+func threshold(n, thresh int) int {
+	if thresh == 0 {
+		return n/2 + 1
+	}
+	return thresh
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+)
+
+func TestConfigValidate(t *testing.T) {
+	for _, tc := range []struct {
+		desc string
+		cfg  Config
+		err  bool
+	}{
+		{
+			desc: "default",
+			cfg:  DefaultConfig(),
+		},
+		{
+			desc: "threshold is not majority",
+			cfg:  Config{N: 10, Threshold: 5, ExpectedLeaders: 5},
+			err:  true,
+		},
+		{
+			desc: "threshold larger than committee",
+			cfg:  Config{N: 10, Threshold: 11, ExpectedLeaders: 5},
+			err:  true,
+		},
+		{
+			desc: "ordered updates",
+			cfg: Config{N: 10, ExpectedLeaders: 5, Updates: []EpochParams{
+				{Epoch: 2, N: 20, ExpectedLeaders: 5},
+				{Epoch: 4, N: 20, Threshold: 15, ExpectedLeaders: 10},
+			}},
+		},
+		{
+			desc: "queue policy",
+			cfg:  Config{N: 10, ExpectedLeaders: 5, LimitPolicy: LimitQueue},
+		},
+		{
+			desc: "unknown policy",
+			cfg:  Config{N: 10, ExpectedLeaders: 5, LimitPolicy: "drop"},
+			err:  true,
+		},
+		{
+			desc: "update without epoch",
+			cfg:  Config{N: 10, ExpectedLeaders: 5, Updates: []EpochParams{{N: 20, ExpectedLeaders: 5}}},
+			err:  true,
+		},
+		{
+			desc: "unordered updates",
+			cfg: Config{N: 10, ExpectedLeaders: 5, Updates: []EpochParams{
+				{Epoch: 4, N: 20, ExpectedLeaders: 5},
+				{Epoch: 4, N: 30, ExpectedLeaders: 5},
+			}},
+			err: true,
+		},
+		{
+			desc: "invalid update",
+			cfg:  Config{N: 10, ExpectedLeaders: 5, Updates: []EpochParams{{Epoch: 2, N: 20}}},
+			err:  true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestForEpoch(t *testing.T) {
+	cfg := Config{N: 10, ExpectedLeaders: 5, Updates: []EpochParams{
+		{Epoch: 2, N: 20, Threshold: 15, ExpectedLeaders: 6},
+		{Epoch: 4, N: 30, ExpectedLeaders: 7},
+	}}
+	require.NoError(t, cfg.Validate())
+
+	for _, tc := range []struct {
+		epoch                 uint32
+		n, thresh, expLeaders int
+	}{
+		{epoch: 0, n: 10, thresh: 6, expLeaders: 5},
+		{epoch: 1, n: 10, thresh: 6, expLeaders: 5},
+		{epoch: 2, n: 20, thresh: 15, expLeaders: 6},
+		{epoch: 3, n: 20, thresh: 15, expLeaders: 6},
+		{epoch: 4, n: 30, thresh: 16, expLeaders: 7},
+		{epoch: 10, n: 30, thresh: 16, expLeaders: 7},
+	} {
+		epochCfg := cfg.ForEpoch(types.EpochID(tc.epoch))
+		require.Equal(t, tc.n, epochCfg.N, "epoch %d", tc.epoch)
+		require.Equal(t, tc.thresh, epochCfg.CommitteeThreshold(), "epoch %d", tc.epoch)
+		require.Equal(t, tc.expLeaders, epochCfg.ExpectedLeaders, "epoch %d", tc.epoch)
+	}
+}
+
+func TestRoundDurationForEpoch(t *testing.T) {
+	cfg := Config{N: 10, ExpectedLeaders: 5, RoundDuration: 10 * time.Second, Updates: []EpochParams{
+		{Epoch: 2, N: 10, ExpectedLeaders: 5, RoundDuration: 20 * time.Second},
+		{Epoch: 4, N: 20, ExpectedLeaders: 5},
+		{Epoch: 6, N: 20, ExpectedLeaders: 5, RoundDuration: 5 * time.Second},
+	}}
+	require.NoError(t, cfg.Validate())
+	require.Equal(t, 20*time.Second, cfg.MaxRoundDuration())
+
+	for epoch, expected := range map[types.EpochID]time.Duration{
+		1: 10 * time.Second,
+		2: 20 * time.Second,
+		4: 20 * time.Second,
+		6: 5 * time.Second,
+	} {
+		require.Equal(t, expected, cfg.ForEpoch(epoch).RoundDuration, "epoch %d", epoch)
+	}
+
+	cfg.Updates[0].RoundDuration = -time.Second
+	require.Error(t, cfg.Validate())
+}
@@ -1,11 +1,34 @@
 package config
 
+import (
+	"github.com/spacemeshos/go-spacemesh/common/types"
+)
+
 // Config is the configuration of the oracle package.
 type Config struct {
 	ConfidenceParam uint32 `mapstructure:"eligibility-confidence-param"` // the confidence interval
+	// VrfV2Epoch is the first epoch in which eligibility proofs are built from the
+	// versioned vrf message that includes the epoch and the identity vrf nonce.
+	// zero keeps the legacy vrf message.
+	VrfV2Epoch types.EpochID `mapstructure:"eligibility-vrf-v2-epoch"`
+	// StorageWeightEpoch is the first epoch in which identities are weighted by the storage
+	// committed in their atx (effective num units), instead of the full atx weight that also
+	// depends on the number of poet ticks. zero keeps the full atx weight.
+	StorageWeightEpoch types.EpochID `mapstructure:"eligibility-storage-weight-epoch"`
+}
+
+// VrfV2 returns true if proofs for the epoch are built from the versioned vrf message.
+func (c Config) VrfV2(epoch types.EpochID) bool {
+	return c.VrfV2Epoch != 0 && epoch >= c.VrfV2Epoch
+}
+
+// StorageWeight returns true if identities are weighted by their storage commitment in the epoch.
+func (c Config) StorageWeight(epoch types.EpochID) bool {
+	return c.StorageWeightEpoch != 0 && epoch >= c.StorageWeightEpoch
 }
 
 // DefaultConfig returns the default configuration for the oracle package.
+// identities are weighted by their storage commitment from the first epoch.
 func DefaultConfig() Config {
-	return Config{ConfidenceParam: 1}
+	return Config{ConfidenceParam: 1, StorageWeightEpoch: 1}
 }
@@ -11,6 +11,11 @@ type activeSetCache interface {
 	Get(key types.EpochID) (value *cachedActiveSet, ok bool)
 }
 
+type layerClock interface {
+	CurrentLayer() types.LayerID
+	AwaitLayer(types.LayerID) <-chan struct{}
+}
+
 type vrfVerifier interface {
 	Verify(nodeID types.NodeID, msg []byte, sig types.VrfSignature) bool
 }
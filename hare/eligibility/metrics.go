@@ -0,0 +1,35 @@
+package eligibility
+
+import (
+	"github.com/spacemeshos/go-spacemesh/metrics"
+)
+
+const subsystem = "hare_eligibility"
+
+var proofsCacheLookups = metrics.NewCounter(
+	"proofs_cache_lookups",
+	subsystem,
+	"Number of lookups in the cache of verified vrf eligibility proofs",
+	[]string{"result"},
+)
+
+var (
+	proofsCacheHits   = proofsCacheLookups.WithLabelValues("hit")
+	proofsCacheMisses = proofsCacheLookups.WithLabelValues("miss")
+)
+
+var (
+	activeSetSize = metrics.NewGauge(
+		"active_set_size",
+		subsystem,
+		"Number of identities in the active set used by the oracle in the current layer",
+		[]string{},
+	).WithLabelValues()
+
+	activeSetWeight = metrics.NewGauge(
+		"active_set_weight",
+		subsystem,
+		"Total weight of the active set used by the oracle in the current layer",
+		[]string{},
+	).WithLabelValues()
+)
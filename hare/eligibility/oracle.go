@@ -20,6 +20,7 @@ import (
 	"github.com/spacemeshos/go-spacemesh/signing"
 	"github.com/spacemeshos/go-spacemesh/sql"
 	"github.com/spacemeshos/go-spacemesh/sql/ballots"
+	"github.com/spacemeshos/go-spacemesh/sql/certificates"
 	"github.com/spacemeshos/go-spacemesh/system"
 )
 
@@ -43,6 +44,7 @@ const (
 
 const (
 	activesCacheSize = 5                       // we don't expect to handle more than two layers concurrently
+	proofsCacheSize  = 8192                    // enough for all rounds of several layers with a large committee
 	maxSupportedN    = (math.MaxInt32 / 2) + 1 // higher values result in an overflow when calculating CDF
 )
 
@@ -50,6 +52,7 @@ var (
 	errZeroCommitteeSize = errors.New("zero committee size")
 	errEmptyActiveSet    = errors.New("empty active set")
 	errZeroTotalWeight   = errors.New("zero total weight")
+	errInvalidProof      = errors.New("invalid vrf proof")
 	ErrNotActive         = errors.New("oracle: miner is not active in epoch")
 )
 
@@ -58,6 +61,13 @@ type cachedActiveSet struct {
 	total uint64
 }
 
+// proofKey identifies the eligibility proof of an identity in a round.
+type proofKey struct {
+	id    types.NodeID
+	layer types.LayerID
+	round uint32
+}
+
 // Oracle is the hare eligibility oracle.
 type Oracle struct {
 	lock           sync.Mutex
@@ -67,6 +77,7 @@ type Oracle struct {
 	vrfVerifier    vrfVerifier
 	layersPerEpoch uint32
 	activesCache   activeSetCache
+	proofsCache    *lru.Cache[proofKey, types.VrfSignature] // proofs that passed vrf verification
 	fallback       map[types.EpochID][]types.ATXID
 	cfg            config.Config
 	log.Log
@@ -86,6 +97,10 @@ func New(
 	if err != nil {
 		logger.With().Fatal("failed to create lru cache for active set", log.Err(err))
 	}
+	pc, err := lru.New[proofKey, types.VrfSignature](proofsCacheSize)
+	if err != nil {
+		logger.With().Fatal("failed to create lru cache for proofs", log.Err(err))
+	}
 
 	return &Oracle{
 		beacons:        beacons,
@@ -94,13 +109,17 @@ func New(
 		vrfSigner:      vrfSigner,
 		layersPerEpoch: layersPerEpoch,
 		activesCache:   ac,
+		proofsCache:    pc,
 		fallback:       map[types.EpochID][]types.ATXID{},
 		cfg:            cfg,
 		Log:            logger,
 	}
 }
 
-//go:generate scalegen -types VrfMessage
+//go:generate scalegen -types VrfMessage,VrfMessageV2
+
+// vrfMessageVersion is the version of VrfMessageV2.
+const vrfMessageVersion uint8 = 2
 
 // VrfMessage is a verification message. It is also the payload for the signature in `types.HareEligibility`.
 type VrfMessage struct {
@@ -110,8 +129,51 @@ type VrfMessage struct {
 	Layer  types.LayerID
 }
 
-// buildVRFMessage builds the VRF message used as input for the BLS (msg=Beacon##Layer##Round).
-func (o *Oracle) buildVRFMessage(ctx context.Context, layer types.LayerID, round uint32) ([]byte, error) {
+// VrfMessageV2 is a versioned verification message. Unlike VrfMessage it is bound to the epoch
+// and to the vrf nonce of the identity, so that proofs can't be reused across networks or restarts.
+type VrfMessageV2 struct {
+	Version uint8                 // always vrfMessageVersion
+	Type    types.EligibilityType // always types.EligibilityHare
+	Beacon  types.Beacon
+	Epoch   types.EpochID
+	Nonce   types.VRFPostIndex
+	Round   uint32
+	Layer   types.LayerID
+}
+
+// buildVRFMessage builds the VRF message used as input for the BLS.
+// starting from VrfV2Epoch the message is VrfMessageV2 (msg=Version##Beacon##Epoch##Nonce##Round##Layer),
+// before that it is VrfMessage (msg=Beacon##Round##Layer).
+func (o *Oracle) buildVRFMessage(ctx context.Context, id types.NodeID, layer types.LayerID, round uint32) ([]byte, error) {
+	if !o.cfg.VrfV2(layer.GetEpoch()) {
+		return o.buildLegacyVRFMessage(ctx, layer, round)
+	}
+	beacon, err := o.beacons.GetBeacon(layer.GetEpoch())
+	if err != nil {
+		return nil, fmt.Errorf("get beacon: %w", err)
+	}
+	nonce, err := o.cdb.VRFNonce(id, layer.GetEpoch())
+	if err != nil {
+		return nil, fmt.Errorf("get vrf nonce: %w", err)
+	}
+
+	msg := VrfMessageV2{
+		Version: vrfMessageVersion,
+		Type:    types.EligibilityHare,
+		Beacon:  beacon,
+		Epoch:   layer.GetEpoch(),
+		Nonce:   nonce,
+		Round:   round,
+		Layer:   layer,
+	}
+	buf, err := codec.Encode(&msg)
+	if err != nil {
+		o.WithContext(ctx).With().Fatal("failed to encode", log.Err(err))
+	}
+	return buf, nil
+}
+
+func (o *Oracle) buildLegacyVRFMessage(ctx context.Context, layer types.LayerID, round uint32) ([]byte, error) {
 	beacon, err := o.beacons.GetBeacon(layer.GetEpoch())
 	if err != nil {
 		return nil, fmt.Errorf("get beacon: %w", err)
@@ -151,6 +213,27 @@ func (o *Oracle) minerWeight(ctx context.Context, layer types.LayerID, id types.
 	return w, nil
 }
 
+// verifyProof checks the vrf signature of the identity for the layer and round.
+// proofs that passed verification are cached, so that duplicate and regossiped
+// messages don't have to be verified again.
+func (o *Oracle) verifyProof(ctx context.Context, layer types.LayerID, round uint32, id types.NodeID, vrfSig types.VrfSignature) error {
+	key := proofKey{id: id, layer: layer, round: round}
+	if cached, ok := o.proofsCache.Get(key); ok && cached == vrfSig {
+		proofsCacheHits.Inc()
+		return nil
+	}
+	proofsCacheMisses.Inc()
+	msg, err := o.buildVRFMessage(ctx, id, layer, round)
+	if err != nil {
+		return err
+	}
+	if !o.vrfVerifier.Verify(id, msg, vrfSig) {
+		return errInvalidProof
+	}
+	o.proofsCache.Add(key, vrfSig)
+	return nil
+}
+
 func calcVrfFrac(vrfSig types.VrfSignature) fixed.Fixed {
 	return fixed.FracFromBytes(vrfSig[:8])
 }
@@ -176,18 +259,15 @@ func (o *Oracle) prepareEligibilityCheck(ctx context.Context, layer types.LayerI
 		return 0, fixed.Fixed{}, fixed.Fixed{}, true, err
 	}
 
-	msg, err := o.buildVRFMessage(ctx, layer, round)
-	if err != nil {
+	if err := o.verifyProof(ctx, layer, round, id, vrfSig); err != nil {
+		if errors.Is(err, errInvalidProof) {
+			logger.Debug("eligibility: a node did not pass vrf signature verification")
+			return 0, fixed.Fixed{}, fixed.Fixed{}, true, nil
+		}
 		logger.With().Warning("could not build vrf message", log.Err(err))
 		return 0, fixed.Fixed{}, fixed.Fixed{}, true, err
 	}
 
-	// validate message
-	if !o.vrfVerifier.Verify(id, msg, vrfSig) {
-		logger.Debug("eligibility: a node did not pass vrf signature verification")
-		return 0, fixed.Fixed{}, fixed.Fixed{}, true, nil
-	}
-
 	// get active set size
 	totalWeight, err := o.totalWeight(ctx, layer)
 	if err != nil {
@@ -206,23 +286,34 @@ func (o *Oracle) prepareEligibilityCheck(ctx context.Context, layer types.LayerI
 		log.Uint64("total_weight", totalWeight),
 	)
 
-	n := minerWeight
-
-	// calc p
 	if uint64(committeeSize) > totalWeight {
 		logger.With().Warning("committee size is greater than total weight",
 			log.Int("committee_size", committeeSize),
 			log.Uint64("total_weight", totalWeight),
 		)
+	}
+	n, p, err := calcThreshold(committeeSize, minerWeight, totalWeight)
+	if err != nil {
+		return 0, fixed.Fixed{}, fixed.Fixed{}, false, fmt.Errorf("eligibility of %v: %w", id, err)
+	}
+	return n, p, calcVrfFrac(vrfSig), false, nil
+}
+
+// calcThreshold returns the parameters of the binomial distribution of the number of
+// eligibilities of an identity with the miner weight. every unit of weight is a trial
+// that succeeds with probability committeeSize/totalWeight, so that the expected number of
+// eligibilities of the whole active set equals the committee size.
+func calcThreshold(committeeSize int, minerWeight, totalWeight uint64) (int, fixed.Fixed, error) {
+	n := minerWeight
+	if uint64(committeeSize) > totalWeight {
+		// probability can't exceed 1. split every unit of weight into committeeSize trials instead.
 		totalWeight *= uint64(committeeSize)
 		n *= uint64(committeeSize)
 	}
 	if n > maxSupportedN {
-		return 0, fixed.Fixed{}, fixed.Fixed{}, false, fmt.Errorf("miner weight exceeds supported maximum (id: %v, weight: %d, max: %d", id, minerWeight, maxSupportedN)
+		return 0, fixed.Fixed{}, fmt.Errorf("miner weight exceeds supported maximum (weight: %d, max: %d)", minerWeight, maxSupportedN)
 	}
-
-	p := fixed.DivUint64(uint64(committeeSize), totalWeight)
-	return int(n), p, calcVrfFrac(vrfSig), false, nil
+	return int(n), fixed.DivUint64(uint64(committeeSize), totalWeight), nil
 }
 
 // Validate validates the number of eligibilities of ID on the given Layer where msg is the VRF message, sig is the role
@@ -316,18 +407,15 @@ func (o *Oracle) CalcEligibility(
 
 // Proof returns the role proof for the current Layer & Round.
 func (o *Oracle) Proof(ctx context.Context, layer types.LayerID, round uint32) (types.VrfSignature, error) {
-	msg, err := o.buildVRFMessage(ctx, layer, round)
+	msg, err := o.buildVRFMessage(ctx, o.vrfSigner.NodeID(), layer, round)
 	if err != nil {
 		return types.EmptyVrfSignature, err
 	}
 	return o.vrfSigner.Sign(msg), nil
 }
 
-// Returns a map of all active node IDs in the specified layer id.
-func (o *Oracle) actives(ctx context.Context, targetLayer types.LayerID) (*cachedActiveSet, error) {
-	if !targetLayer.After(types.GetEffectiveGenesis()) {
-		return nil, errEmptyActiveSet
-	}
+// activesEpoch returns the epoch of the active set used in the specified layer.
+func (o *Oracle) activesEpoch(targetLayer types.LayerID) types.EpochID {
 	targetEpoch := targetLayer.GetEpoch()
 	// the first bootstrap data targets first epoch after genesis (epoch 2)
 	// and the epoch where checkpoint recovery happens
@@ -335,6 +423,15 @@ func (o *Oracle) actives(ctx context.Context, targetLayer types.LayerID) (*cache
 		targetLayer.Difference(targetEpoch.FirstLayer()) < o.cfg.ConfidenceParam {
 		targetEpoch -= 1
 	}
+	return targetEpoch
+}
+
+// Returns a map of all active node IDs in the specified layer id.
+func (o *Oracle) actives(ctx context.Context, targetLayer types.LayerID) (*cachedActiveSet, error) {
+	if !targetLayer.After(types.GetEffectiveGenesis()) {
+		return nil, errEmptyActiveSet
+	}
+	targetEpoch := o.activesEpoch(targetLayer)
 	o.WithContext(ctx).With().Debug("hare oracle getting active set",
 		log.Stringer("target_layer", targetLayer),
 		log.Stringer("target_layer_epoch", targetLayer.GetEpoch()),
@@ -367,6 +464,79 @@ func (o *Oracle) actives(ctx context.Context, targetLayer types.LayerID) (*cache
 	return aset, nil
 }
 
+// ActiveSetInfo describes the active set used by the oracle in a layer.
+type ActiveSetInfo struct {
+	Layer       types.LayerID `json:"layer"`
+	Epoch       types.EpochID `json:"epoch"` // epoch of the active set
+	Size        int           `json:"size"`
+	TotalWeight uint64        `json:"total_weight"`
+}
+
+// ActiveSetInfo returns the size and the total weight of the active set used in the specified layer.
+func (o *Oracle) ActiveSetInfo(ctx context.Context, layer types.LayerID) (ActiveSetInfo, error) {
+	aset, err := o.actives(ctx, layer)
+	if err != nil {
+		return ActiveSetInfo{}, err
+	}
+	return ActiveSetInfo{
+		Layer:       layer,
+		Epoch:       o.activesEpoch(layer),
+		Size:        len(aset.set),
+		TotalWeight: aset.total,
+	}, nil
+}
+
+// Run precomputes the active set as soon as it takes effect, so that it isn't
+// computed in the critical path of the hare protocol. the set is computed only once
+// its inputs are final: the fallback active set or the certified first block of the epoch.
+// otherwise it is computed on the first query, as before. It returns when the context is canceled.
+func (o *Oracle) Run(ctx context.Context, clock layerClock) error {
+	for lid := clock.CurrentLayer(); ; lid = lid.Add(1) {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-clock.AwaitLayer(lid):
+		}
+		if !lid.After(types.GetEffectiveGenesis()) {
+			continue
+		}
+		if final, err := o.activesFinal(o.activesEpoch(lid)); err != nil {
+			o.With().Warning("failed to check active set inputs", lid, log.Err(err))
+			continue
+		} else if !final {
+			continue
+		}
+		info, err := o.ActiveSetInfo(ctx, lid)
+		if err != nil {
+			o.With().Warning("failed to precompute active set", lid, log.Err(err))
+			continue
+		}
+		activeSetSize.Set(float64(info.Size))
+		activeSetWeight.Set(float64(info.TotalWeight))
+	}
+}
+
+// activesFinal returns true if the active set of the epoch is already cached or its inputs
+// won't change anymore. the active set from the ref ballots is not final, as more ballots may arrive.
+func (o *Oracle) activesFinal(epoch types.EpochID) (bool, error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	if _, exists := o.activesCache.Get(epoch); exists {
+		return true, nil
+	}
+	if _, exists := o.fallback[epoch]; exists {
+		return true, nil
+	}
+	_, err := certificates.FirstInEpoch(o.cdb, epoch)
+	switch {
+	case errors.Is(err, sql.ErrNotFound):
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+	return true, nil
+}
+
 func (o *Oracle) ActiveSet(ctx context.Context, targetEpoch types.EpochID) ([]types.ATXID, error) {
 	aset, err := o.actives(ctx, targetEpoch.FirstLayer().Add(o.cfg.ConfidenceParam))
 	if err != nil {
@@ -410,7 +580,11 @@ func (o *Oracle) computeActiveWeights(targetEpoch types.EpochID, activeSet []typ
 		if err != nil {
 			return nil, fmt.Errorf("hare actives get ATX %s, epoch %d: %w", id, targetEpoch, err)
 		}
-		weightedActiveSet[atx.NodeID] = atx.GetWeight()
+		if o.cfg.StorageWeight(targetEpoch) {
+			weightedActiveSet[atx.NodeID] = uint64(atx.EffectiveNumUnits)
+		} else {
+			weightedActiveSet[atx.NodeID] = atx.GetWeight()
+		}
 	}
 	return weightedActiveSet, nil
 }
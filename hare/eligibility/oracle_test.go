@@ -17,6 +17,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/spacemeshos/go-spacemesh/codec"
 	"github.com/spacemeshos/go-spacemesh/common/types"
 	"github.com/spacemeshos/go-spacemesh/datastore"
 	"github.com/spacemeshos/go-spacemesh/hare/eligibility/config"
@@ -116,11 +117,13 @@ func createActiveSet(tb testing.TB, cdb *datastore.CachedDB, lid types.LayerID,
 	for i, id := range activeSet {
 		nodeID := types.BytesToNodeID([]byte(strconv.Itoa(i)))
 		miners = append(miners, nodeID)
+		nonce := types.VRFPostIndex(i)
 		atx := &types.ActivationTx{InnerActivationTx: types.InnerActivationTx{
 			NIPostChallenge: types.NIPostChallenge{
 				PublishEpoch: lid.GetEpoch(),
 			},
 			NumUnits: uint32(i + 1),
+			VRFNonce: &nonce,
 		}}
 		atx.SetID(id)
 		atx.SetEffectiveNumUnits(atx.NumUnits)
@@ -238,6 +241,27 @@ func TestCalcEligibility(t *testing.T) {
 	})
 }
 
+func TestValidate_CachedProof(t *testing.T) {
+	o := defaultOracle(t)
+	lid := types.EpochID(5).FirstLayer()
+	miners := createLayerData(t, o.cdb, lid.Sub(defLayersPerEpoch), 5)
+	o.mBeacon.EXPECT().GetBeacon(lid.GetEpoch()).Return(types.RandomBeacon(), nil).Times(2)
+	o.mVerifier.EXPECT().Verify(miners[0], gomock.Any(), gomock.Any()).Return(true).Times(2)
+
+	sig := types.RandomVrfSignature()
+	res, err := o.CalcEligibility(context.Background(), lid, 1, 10, miners[0], sig)
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		valid, err := o.Validate(context.Background(), lid, 1, 10, miners[0], sig, res)
+		require.NoError(t, err)
+		require.True(t, valid)
+	}
+
+	// different proof for the same identity, layer and round is verified again
+	_, err = o.CalcEligibility(context.Background(), lid, 1, 10, miners[0], types.RandomVrfSignature())
+	require.NoError(t, err)
+}
+
 func TestCalcEligibilityWithSpaceUnit(t *testing.T) {
 	const committeeSize = 800
 	tcs := []struct {
@@ -267,7 +291,8 @@ func TestCalcEligibilityWithSpaceUnit(t *testing.T) {
 			for _, nodeID := range miners {
 				sig := types.RandomVrfSignature()
 
-				o.mBeacon.EXPECT().GetBeacon(lid.GetEpoch()).Return(beacon, nil).Times(2)
+				// validation of the same proof is served from the cache
+				o.mBeacon.EXPECT().GetBeacon(lid.GetEpoch()).Return(beacon, nil).Times(1)
 				res, err := o.CalcEligibility(context.Background(), lid, 1, committeeSize, nodeID, sig)
 				require.NoError(t, err)
 
@@ -437,7 +462,7 @@ func TestBuildVRFMessage_BeaconError(t *testing.T) {
 	o := defaultOracle(t)
 	errUnknown := errors.New("unknown")
 	o.mBeacon.EXPECT().GetBeacon(gomock.Any()).Return(types.EmptyBeacon, errUnknown).Times(1)
-	msg, err := o.buildVRFMessage(context.Background(), types.LayerID(1), 1)
+	msg, err := o.buildVRFMessage(context.Background(), types.EmptyNodeID, types.LayerID(1), 1)
 	require.ErrorIs(t, err, errUnknown)
 	require.Nil(t, msg)
 }
@@ -448,28 +473,98 @@ func TestBuildVRFMessage(t *testing.T) {
 	secondLayer := firstLayer.Add(1)
 	beacon := types.RandomBeacon()
 	o.mBeacon.EXPECT().GetBeacon(firstLayer.GetEpoch()).Return(beacon, nil).Times(1)
-	m1, err := o.buildVRFMessage(context.Background(), firstLayer, 2)
+	m1, err := o.buildVRFMessage(context.Background(), types.EmptyNodeID, firstLayer, 2)
 	require.NoError(t, err)
 
 	// check not same for different round
 	o.mBeacon.EXPECT().GetBeacon(firstLayer.GetEpoch()).Return(beacon, nil).Times(1)
-	m3, err := o.buildVRFMessage(context.Background(), firstLayer, 3)
+	m3, err := o.buildVRFMessage(context.Background(), types.EmptyNodeID, firstLayer, 3)
 	require.NoError(t, err)
 	require.NotEqual(t, m1, m3)
 
 	// check not same for different layer
 	o.mBeacon.EXPECT().GetBeacon(firstLayer.GetEpoch()).Return(beacon, nil).Times(1)
-	m4, err := o.buildVRFMessage(context.Background(), secondLayer, 2)
+	m4, err := o.buildVRFMessage(context.Background(), types.EmptyNodeID, secondLayer, 2)
 	require.NoError(t, err)
 	require.NotEqual(t, m1, m4)
 
 	// check same call returns same result
 	o.mBeacon.EXPECT().GetBeacon(firstLayer.GetEpoch()).Return(beacon, nil).Times(1)
-	m5, err := o.buildVRFMessage(context.Background(), firstLayer, 2)
+	m5, err := o.buildVRFMessage(context.Background(), types.EmptyNodeID, firstLayer, 2)
 	require.NoError(t, err)
 	require.Equal(t, m1, m5) // check same result
 }
 
+func TestBuildVRFMessage_V2(t *testing.T) {
+	o := defaultOracle(t)
+	lid := types.EpochID(5).FirstLayer()
+	miners := createLayerData(t, o.cdb, lid.Sub(defLayersPerEpoch), 2)
+	beacon := types.RandomBeacon()
+	o.mBeacon.EXPECT().GetBeacon(lid.GetEpoch()).Return(beacon, nil).AnyTimes()
+
+	legacy, err := o.buildVRFMessage(context.Background(), miners[0], lid, 2)
+	require.NoError(t, err)
+
+	o.cfg.VrfV2Epoch = lid.GetEpoch()
+	m1, err := o.buildVRFMessage(context.Background(), miners[0], lid, 2)
+	require.NoError(t, err)
+	require.NotEqual(t, legacy, m1)
+	var msg VrfMessageV2
+	require.NoError(t, codec.Decode(m1, &msg))
+	require.Equal(t, VrfMessageV2{
+		Version: vrfMessageVersion,
+		Type:    types.EligibilityHare,
+		Beacon:  beacon,
+		Epoch:   lid.GetEpoch(),
+		Nonce:   0,
+		Round:   2,
+		Layer:   lid,
+	}, msg)
+
+	// check not same for different identity nonce
+	m2, err := o.buildVRFMessage(context.Background(), miners[1], lid, 2)
+	require.NoError(t, err)
+	require.NotEqual(t, m1, m2)
+
+	// identity without an atx has no nonce
+	_, err = o.buildVRFMessage(context.Background(), types.RandomNodeID(), lid, 2)
+	require.ErrorIs(t, err, sql.ErrNotFound)
+
+	// epochs before the upgrade use the legacy message
+	o.cfg.VrfV2Epoch = lid.GetEpoch() + 1
+	m3, err := o.buildVRFMessage(context.Background(), miners[0], lid, 2)
+	require.NoError(t, err)
+	require.Equal(t, legacy, m3)
+}
+
+func TestValidate_VrfV2Activation(t *testing.T) {
+	o := defaultOracle(t)
+	lid := types.EpochID(5).FirstLayer()
+	miners := createLayerData(t, o.cdb, lid.Sub(defLayersPerEpoch), 5)
+	o.mBeacon.EXPECT().GetBeacon(lid.GetEpoch()).Return(types.RandomBeacon(), nil).AnyTimes()
+	legacy, err := o.buildVRFMessage(context.Background(), miners[0], lid, 1)
+	require.NoError(t, err)
+
+	// high vrf fraction makes the identity eligible regardless of its weight
+	sig := types.VrfSignature{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	o.cfg.VrfV2Epoch = lid.GetEpoch() + 1
+	o.mVerifier.EXPECT().Verify(miners[0], legacy, sig).Return(true)
+	res, err := o.CalcEligibility(context.Background(), lid, 1, 10, miners[0], sig)
+	require.NoError(t, err)
+	require.NotZero(t, res)
+
+	// only the versioned message is verified starting from the activation epoch
+	o.cfg.VrfV2Epoch = lid.GetEpoch()
+	sig[8] = 1
+	versioned, err := o.buildVRFMessage(context.Background(), miners[0], lid, 1)
+	require.NoError(t, err)
+	require.NotEqual(t, legacy, versioned)
+	o.mVerifier.EXPECT().Verify(miners[0], versioned, sig).Return(false)
+	res, err = o.CalcEligibility(context.Background(), lid, 1, 10, miners[0], sig)
+	require.NoError(t, err)
+	require.Zero(t, res)
+}
+
 func TestBuildVRFMessage_Concurrency(t *testing.T) {
 	o := defaultOracle(t)
 
@@ -481,7 +576,7 @@ func TestBuildVRFMessage_Concurrency(t *testing.T) {
 	for i := 0; i < total; i++ {
 		wg.Add(1)
 		go func(x int) {
-			_, err := o.buildVRFMessage(context.Background(), firstLayer, uint32(x%expectAdd))
+			_, err := o.buildVRFMessage(context.Background(), types.EmptyNodeID, firstLayer, uint32(x%expectAdd))
 			assert.NoError(t, err)
 			wg.Done()
 		}(i)
@@ -512,6 +607,80 @@ func TestActiveSet(t *testing.T) {
 	}
 }
 
+func TestActiveSetInfo(t *testing.T) {
+	numMiners := 5
+	o := defaultOracle(t)
+	targetEpoch := types.EpochID(5)
+	layer := targetEpoch.FirstLayer().Add(o.cfg.ConfidenceParam)
+	createLayerData(t, o.cdb, targetEpoch.FirstLayer(), numMiners)
+	o.mBeacon.EXPECT().GetBeacon(gomock.Any()).AnyTimes()
+
+	info, err := o.ActiveSetInfo(context.Background(), layer)
+	require.NoError(t, err)
+	require.Equal(t, ActiveSetInfo{
+		Layer:       layer,
+		Epoch:       targetEpoch,
+		Size:        numMiners,
+		TotalWeight: 15,
+	}, info)
+
+	_, err = o.ActiveSetInfo(context.Background(), targetEpoch.FirstLayer())
+	require.ErrorIs(t, err, errEmptyActiveSet)
+}
+
+func TestOracle_Run(t *testing.T) {
+	numMiners := 5
+	for _, tc := range []struct {
+		desc      string
+		certified bool
+	}{
+		{desc: "certified first block", certified: true},
+		// the set from the ref ballots is computed on the first query, as more ballots may arrive
+		{desc: "ref ballots", certified: false},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			o := defaultOracle(t)
+			targetEpoch := types.EpochID(5)
+			layer := targetEpoch.FirstLayer().Add(o.cfg.ConfidenceParam)
+			activeSet := types.RandomActiveSet(numMiners)
+			miners := createActiveSet(t, o.cdb, targetEpoch.FirstLayer().Sub(1), activeSet)
+			blts := createBallots(t, o.cdb, targetEpoch.FirstLayer(), activeSet, miners)
+			if tc.certified {
+				createBlock(t, o.cdb, blts)
+			}
+			runOracle(t, o, layer)
+			_, exists := o.activesCache.Get(targetEpoch)
+			require.Equal(t, tc.certified, exists)
+		})
+	}
+}
+
+// runOracle runs the oracle until it processes the layer.
+func runOracle(t *testing.T, o *testOracle, layer types.LayerID) {
+	clock := NewMocklayerClock(gomock.NewController(t))
+	clock.EXPECT().CurrentLayer().Return(layer)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	clock.EXPECT().AwaitLayer(gomock.Any()).DoAndReturn(func(lid types.LayerID) <-chan struct{} {
+		ch := make(chan struct{})
+		if lid == layer {
+			close(ch)
+		} else {
+			close(done)
+		}
+		return ch
+	}).Times(2)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- o.Run(ctx, clock)
+	}()
+	<-done
+	cancel()
+	require.NoError(t, <-errCh)
+}
+
 func TestActives(t *testing.T) {
 	numMiners := 5
 	t.Run("genesis bootstrap", func(t *testing.T) {
@@ -655,6 +824,94 @@ func TestMaxSupportedN(t *testing.T) {
 	})
 }
 
+func TestCalcThreshold(t *testing.T) {
+	for _, tc := range []struct {
+		desc                     string
+		committee                int
+		minerWeight, totalWeight uint64
+		n                        int
+		trials                   uint64 // total number of trials in the active set
+		err                      bool
+	}{
+		{desc: "weight is a number of trials", committee: 10, minerWeight: 20, totalWeight: 100, n: 20, trials: 100},
+		{desc: "committee equal to total weight", committee: 100, minerWeight: 1, totalWeight: 100, n: 1, trials: 100},
+		{desc: "committee larger than total weight", committee: 800, minerWeight: 3, totalWeight: 10, n: 2400, trials: 8000},
+		{desc: "max supported weight", committee: 1, minerWeight: maxSupportedN, totalWeight: 2 * maxSupportedN, n: maxSupportedN, trials: 2 * maxSupportedN},
+		{desc: "weight exceeds max supported", committee: 1, minerWeight: maxSupportedN + 1, totalWeight: 2 * maxSupportedN, err: true},
+		{desc: "scaled weight exceeds max supported", committee: 100_000, minerWeight: 50_000, totalWeight: 60_000, err: true},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			n, p, err := calcThreshold(tc.committee, tc.minerWeight, tc.totalWeight)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.n, n)
+			require.Equal(t, fixed.DivUint64(uint64(tc.committee), tc.trials), p)
+		})
+	}
+}
+
+func TestCalcThreshold_ExpectedCommitteeSize(t *testing.T) {
+	rng := rand.New(rand.NewSource(1001))
+	for _, committee := range []int{10, 50, 800} {
+		for _, numMiners := range []int{1, 5, 100, 2000} {
+			weights := make([]uint64, numMiners)
+			var total uint64
+			for i := range weights {
+				weights[i] = uint64(rng.Intn(1000) + 1)
+				total += weights[i]
+			}
+			// expected number of eligibilities of the active set is the sum of n*p of every identity
+			var expected float64
+			for _, weight := range weights {
+				n, p, err := calcThreshold(committee, weight, total)
+				require.NoError(t, err)
+				expected += float64(n) * p.Float()
+			}
+			require.InDelta(t, float64(committee), expected, 0.001*float64(committee),
+				"committee %d miners %d", committee, numMiners)
+		}
+	}
+}
+
+func TestComputeActiveWeights_StorageWeight(t *testing.T) {
+	const ticks = 10
+	o := defaultOracle(t)
+	o.cfg.StorageWeightEpoch = 5
+
+	var activeSet []types.ATXID
+	expected := map[types.NodeID]uint64{}
+	for i := 0; i < 3; i++ {
+		atx := &types.ActivationTx{InnerActivationTx: types.InnerActivationTx{
+			NIPostChallenge: types.NIPostChallenge{PublishEpoch: 3},
+			NumUnits:        uint32(i + 1),
+		}}
+		atx.SetID(types.RandomATXID())
+		atx.SetEffectiveNumUnits(atx.NumUnits)
+		atx.SetReceived(time.Now())
+		atx.SmesherID = types.RandomNodeID()
+		vAtx, err := atx.Verify(0, ticks)
+		require.NoError(t, err)
+		require.NoError(t, atxs.Add(o.cdb, vAtx))
+		activeSet = append(activeSet, atx.ID())
+		expected[atx.SmesherID] = uint64(atx.NumUnits)
+	}
+
+	weights, err := o.computeActiveWeights(5, activeSet)
+	require.NoError(t, err)
+	require.Equal(t, expected, weights)
+
+	// full atx weight before the storage weight epoch
+	weights, err = o.computeActiveWeights(4, activeSet)
+	require.NoError(t, err)
+	for id, weight := range expected {
+		require.Equal(t, weight*ticks, weights[id])
+	}
+}
+
 func TestActiveSetDD(t *testing.T) {
 	t.Parallel()
 
@@ -876,3 +1133,11 @@ func FuzzVrfMessageConsistency(f *testing.F) {
 func FuzzVrfMessageSafety(f *testing.F) {
 	tester.FuzzSafety[VrfMessage](f)
 }
+
+func FuzzVrfMessageV2Consistency(f *testing.F) {
+	tester.FuzzConsistency[VrfMessageV2](f)
+}
+
+func FuzzVrfMessageV2Safety(f *testing.F) {
+	tester.FuzzSafety[VrfMessageV2](f)
+}
@@ -55,6 +55,19 @@ func (et *EligibilityTracker) Track(nodeID types.NodeID, round uint32, count uin
 	return wasDishonest
 }
 
+// Participants returns the number of distinct identities tracked in any round.
+func (et *EligibilityTracker) Participants() int {
+	et.mu.RLock()
+	defer et.mu.RUnlock()
+	seen := make(map[types.NodeID]struct{}, et.expectedSize)
+	for _, nodes := range et.nodesByRound {
+		for id := range nodes {
+			seen[id] = struct{}{}
+		}
+	}
+	return len(seen)
+}
+
 // Dishonest returns whether an eligible identity is known malicious.
 func (et *EligibilityTracker) Dishonest(nodeID types.NodeID, round uint32) bool {
 	et.mu.RLock()
@@ -110,3 +110,13 @@ func TestEligibilityTracker(t *testing.T) {
 		require.Equal(t, 0, good)
 	}
 }
+
+func TestEligibilityTracker_Participants(t *testing.T) {
+	et := hare.NewEligibilityTracker(3)
+	require.Zero(t, et.Participants())
+	id1, id2 := types.RandomNodeID(), types.RandomNodeID()
+	et.Track(id1, 0, 1, true)
+	et.Track(id1, 1, 1, true)
+	et.Track(id2, 1, 1, false)
+	require.Equal(t, 2, et.Participants())
+}
@@ -95,6 +95,14 @@ func withMesh(m mesh) Opt {
 	}
 }
 
+// WithPeerCounter sets the source of the number of connected peers, which is used
+// to detect that the node was partitioned.
+func WithPeerCounter(peers peerCounter) Opt {
+	return func(h *Hare) {
+		h.peers = peers
+	}
+}
+
 // Hare is the orchestrator that starts new consensus processes and collects their output.
 type Hare struct {
 	log.Log
@@ -122,9 +130,19 @@ type Hare struct {
 	mu         sync.Mutex
 	lastLayer  types.LayerID
 	outputs    map[types.LayerID][]types.ProposalID
+	results    map[types.LayerID]*LayerResult
 	cps        map[types.LayerID]Consensus
+	cancels    map[types.LayerID]context.CancelFunc
+	queue      []pendingInstance
 
-	factory consensusFactory
+	// serializes starting consensus processes from the tick loop and from the queue.
+	startMu sync.Mutex
+
+	factory       consensusFactory
+	wal           *wal
+	participation *participation
+	peers         peerCounter
+	rebroadcast   *rebroadcaster
 
 	nodeID      types.NodeID
 	sigVerifier malfeasance.SigVerifier
@@ -162,7 +180,7 @@ func New(
 	h.newRoundClock = func(layerID types.LayerID) RoundClock {
 		layerTime := layerClock.LayerToTime(layerID)
 		wakeupDelta := conf.WakeupDelta
-		roundDuration := h.config.RoundDuration
+		roundDuration := h.config.EpochParams(layerID.GetEpoch()).RoundDuration
 		h.With().Debug("creating hare round clock", layerID,
 			log.String("layer_time", layerTime.String()),
 			log.Duration("wakeup_delta", wakeupDelta),
@@ -171,7 +189,7 @@ func New(
 		return NewSimpleRoundClock(layerTime, wakeupDelta, roundDuration)
 	}
 
-	ev := newEligibilityValidator(rolacle, conf.N, conf.ExpectedLeaders, logger)
+	ev := newEligibilityValidator(rolacle, conf, logger)
 	h.mchMalfeasance = make(chan *types.MalfeasanceGossip, conf.N)
 	h.sign = sign
 	h.blockGenCh = ch
@@ -185,14 +203,25 @@ func New(
 	h.outputChan = make(chan report, h.config.Hdist)
 	h.wcChan = make(chan wcReport, h.config.Hdist)
 	h.outputs = make(map[types.LayerID][]types.ProposalID, h.config.Hdist) // we keep results about LayerBuffer past layers
+	h.results = make(map[types.LayerID]*LayerResult, h.config.Hdist)
 	h.cps = make(map[types.LayerID]Consensus, h.config.LimitConcurrent)
+	h.cancels = make(map[types.LayerID]context.CancelFunc, h.config.LimitConcurrent)
 	h.factory = func(ctx context.Context, conf config.Config, instanceId types.LayerID, s *Set, oracle Rolacle, et *EligibilityTracker, signing *signing.EdSigner, p2p pubsub.Publisher, comm communication, clock RoundClock) Consensus {
 		return newConsensusProcess(ctx, conf, instanceId, s, oracle, stateQ, signing, edVerifier, et, nid, p2p, comm, ev, clock, logger)
 	}
+	if conf.Turbo {
+		logger.Warning("hare turbo mode is enabled, layers are finalized without running the protocol")
+		h.factory = func(ctx context.Context, _ config.Config, instanceId types.LayerID, s *Set, _ Rolacle, _ *EligibilityTracker, _ *signing.EdSigner, _ pubsub.Publisher, comm communication, _ RoundClock) Consensus {
+			return newTurboProcess(ctx, instanceId, s, comm)
+		}
+	}
 
 	h.nodeID = nid
 	h.sigVerifier = edVerifier
 	h.ctx, h.cancel = context.WithCancel(context.Background())
+	if conf.BatchInterval > 0 {
+		h.publisher = newBatchPublisher(h.ctx, publisher, conf.BatchInterval, h.numCPs, logger)
+	}
 
 	for _, opt := range opts {
 		opt(h)
@@ -202,15 +231,41 @@ func New(
 		h.msh = defaultMesh{CachedDB: cdb}
 	}
 	h.broker = newBroker(h.config, h.msh, edVerifier, ev, stateQ, syncState, publisher, conf.LimitConcurrent, logger)
+	if conf.VerifyWindow > 0 && conf.BatchVerifyEpoch > 0 {
+		h.broker.sigBatcher = newSigBatcher(edVerifier, conf.VerifyWindow, conf.BatchVerifyEpoch, logger)
+	}
+	if conf.IdentityBudget > 0 {
+		h.broker.budget = newSignerBudget(conf.IdentityBudget)
+	}
+	if conf.WAL {
+		h.wal = newWAL(h.msh.Cache(), logger)
+		h.broker.wal = h.wal
+	}
+	if cdb != nil {
+		h.participation = newParticipation(cdb, logger)
+	}
+	if conf.RebroadcastPeers > 0 && h.peers != nil {
+		h.rebroadcast = newRebroadcaster(publisher, h.peers, conf.RebroadcastPeers, logger)
+	}
 
 	return h
 }
 
+// ActiveParams returns the committee parameters that are in effect in the current epoch.
+func (h *Hare) ActiveParams() config.EpochParams {
+	return h.config.EpochParams(h.layerClock.CurrentLayer().GetEpoch())
+}
+
 // GetHareMsgHandler returns the gossip handler for hare protocol message.
 func (h *Hare) GetHareMsgHandler() pubsub.GossipHandler {
 	return h.broker.HandleMessage
 }
 
+// GetHareBatchHandler returns the gossip handler for batches of hare protocol messages.
+func (h *Hare) GetHareBatchHandler() pubsub.GossipHandler {
+	return h.broker.HandleBatch
+}
+
 func (h *Hare) HandleEligibility(ctx context.Context, emsg *types.HareEligibilityGossip) {
 	h.broker.HandleEligibility(ctx, emsg)
 }
@@ -274,7 +329,6 @@ func (h *Hare) collectOutput(ctx context.Context, output report) error {
 
 	var pids []types.ProposalID
 	if output.completed {
-		consensusOkCnt.Inc()
 		h.WithContext(ctx).With().Info("hare terminated with success", layerID, log.Int("num_proposals", output.set.Size()))
 		set := output.set
 		postNumProposals.Add(float64(set.Size()))
@@ -288,7 +342,6 @@ func (h *Hare) collectOutput(ctx context.Context, output report) error {
 		case <-ctx.Done():
 		}
 	} else {
-		consensusFailCnt.Inc()
 		h.WithContext(ctx).With().Warning("hare terminated with failure", layerID)
 	}
 
@@ -360,12 +413,49 @@ func (h *Hare) onTick(ctx context.Context, lid types.LayerID) (bool, error) {
 		return false, errors.New("closed while waiting for hare delta")
 	}
 
+	return h.startConsensus(ctx, lid, clock, nil)
+}
+
+// resume restarts the consensus process for the layer that was in progress
+// before the node was restarted, using the messages recorded in the wal.
+func (h *Hare) resume(ctx context.Context, lid types.LayerID) bool {
+	if h.wal == nil || lid <= types.GetEffectiveGenesis() || h.getCP(lid) != nil {
+		return false
+	}
+	clock := h.newRoundClock(lid)
+	if time.Now().After(clock.RoundEnd(uint32(h.config.LimitIterations) * RoundsPerIteration)) {
+		return false
+	}
+	msgs := h.wal.replay(ctx, lid)
+	if len(msgs) == 0 {
+		return false
+	}
+	h.With().Info("resuming hare from wal",
+		log.Context(ctx),
+		lid,
+		log.Int("messages", len(msgs)),
+	)
+	h.setLastLayer(lid)
+	started, err := h.startConsensus(ctx, lid, clock, msgs)
+	if err != nil {
+		h.With().Warning("failed to resume hare", log.Context(ctx), lid, log.Err(err))
+	}
+	return started
+}
+
+// startConsensus starts the consensus process for the layer. replay messages
+// are delivered to the process before any gossip received after the start.
+func (h *Hare) startConsensus(ctx context.Context, lid types.LayerID, clock RoundClock, replay []*Message) (bool, error) {
+	h.startMu.Lock()
+	defer h.startMu.Unlock()
+
 	if !h.broker.Synced(ctx, lid) {
 		// if not currently synced don't start consensus process
 		h.With().Info("not starting hare: node not synced at this layer",
 			log.Context(ctx),
 			lid,
 		)
+		h.recordSkipped(lid, "not synced")
 		return false, nil
 	}
 
@@ -376,6 +466,11 @@ func (h *Hare) onTick(ctx context.Context, lid types.LayerID) (bool, error) {
 			log.Context(ctx),
 			lid,
 		)
+		h.recordSkipped(lid, "beacon not retrieved")
+		return false, nil
+	}
+
+	if !h.admit(ctx, lid, clock, replay) {
 		return false, nil
 	}
 
@@ -383,32 +478,50 @@ func (h *Hare) onTick(ctx context.Context, lid types.LayerID) (bool, error) {
 	if err != nil {
 		return false, fmt.Errorf("broker register: %w", err)
 	}
+	for _, msg := range replay {
+		select {
+		case ch <- msg:
+		default:
+			h.With().Warning("inbox is full, dropping replayed message", log.Context(ctx), lid)
+		}
+	}
 	comm := communication{
-		inbox:  ch,
-		mchOut: h.mchMalfeasance,
-		report: h.outputChan,
-		wc:     h.wcChan,
+		inbox:         ch,
+		mchOut:        h.mchMalfeasance,
+		report:        h.outputChan,
+		wc:            h.wcChan,
+		wal:           h.wal,
+		participation: h.participation,
+		rebroadcast:   h.rebroadcast,
 	}
 	props := goodProposals(ctx, h.Log, h.msh, h.nodeID, lid, types.LayerID(h.config.StopAtxGrading), beacon, h.layerClock.LayerToTime(lid.GetEpoch().FirstLayer()), h.config.WakeupDelta)
 	preNumProposals.Add(float64(len(props)))
 	set := NewSet(props)
-	cp := h.factory(ctx, h.config, lid, set, h.rolacle, et, h.sign, h.publisher, comm, clock)
+	cfg := h.config.ForEpoch(lid.GetEpoch())
+	cpCtx, cancel := context.WithCancel(ctx)
+	cp := h.factory(cpCtx, cfg, lid, set, h.rolacle, et, h.sign, h.publisher, comm, clock)
 
 	h.With().Debug("starting hare",
 		log.Context(ctx),
 		lid,
 		log.Int("num proposals", len(props)),
+		log.Int("committee_size", cfg.N),
+		log.Int("threshold", cfg.CommitteeThreshold()),
+		log.Int("exp_leaders", cfg.ExpectedLeaders),
 	)
+	h.recordStarted(lid)
+	// register before starting, the process may report its output immediately
+	h.addCP(ctx, cp, cancel)
 	cp.Start()
-	h.addCP(ctx, cp)
 	h.patrol.SetHareInCharge(lid)
 	return true, nil
 }
 
-func (h *Hare) addCP(ctx context.Context, cp Consensus) {
+func (h *Hare) addCP(ctx context.Context, cp Consensus, cancel context.CancelFunc) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 	h.cps[cp.ID()] = cp
+	h.cancels[cp.ID()] = cancel
 	h.With().Debug("number of consensus processes (after register)",
 		log.Context(ctx),
 		log.Int("count", len(h.cps)),
@@ -416,6 +529,12 @@ func (h *Hare) addCP(ctx context.Context, cp Consensus) {
 	processesGauge.Set(float64(len(h.cps)))
 }
 
+func (h *Hare) numCPs() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.cps)
+}
+
 func (h *Hare) getCP(lid types.LayerID) Consensus {
 	h.mu.Lock()
 	defer h.mu.Unlock()
@@ -434,6 +553,11 @@ func (h *Hare) removeCP(ctx context.Context, lid types.LayerID) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 	delete(h.cps, cp.ID())
+	h.rebroadcast.remove(cp.ID())
+	if cancel, exist := h.cancels[cp.ID()]; exist {
+		cancel()
+		delete(h.cancels, cp.ID())
+	}
 	h.With().Debug("number of consensus processes (after deregister)",
 		log.Context(ctx),
 		lid,
@@ -657,6 +781,7 @@ func (h *Hare) outputCollectionLoop(ctx context.Context) {
 		case out := <-h.outputChan:
 			layerID := out.id
 			ctx := log.WithNewSessionID(ctx)
+			h.recordOutput(out)
 			if err := h.collectOutput(ctx, out); err != nil {
 				h.With().Warning("error collecting output from hare",
 					log.Context(ctx),
@@ -666,6 +791,9 @@ func (h *Hare) outputCollectionLoop(ctx context.Context) {
 			}
 			h.broker.Unregister(ctx, out.id)
 			h.removeCP(ctx, out.id)
+			h.wal.truncate(ctx, out.id)
+			h.participation.prune(ctx, out.id)
+			h.startQueued()
 		case <-h.ctx.Done():
 			return
 		}
@@ -674,12 +802,22 @@ func (h *Hare) outputCollectionLoop(ctx context.Context) {
 
 // listens to new layers.
 func (h *Hare) tickLoop(ctx context.Context) {
-	for layer := h.layerClock.CurrentLayer(); ; layer = layer.Add(1) {
+	current := h.layerClock.CurrentLayer()
+	if h.wal != nil && current > 0 {
+		// consensus for the previous layer may still be in progress
+		h.resume(ctx, current.Sub(1))
+	}
+	for layer := current; ; layer = layer.Add(1) {
 		ctx := log.WithNewSessionID(ctx)
 		select {
 		case <-h.layerClock.AwaitLayer(layer):
 			if time.Since(h.layerClock.LayerToTime(layer)) > h.config.WakeupDelta {
-				h.WithContext(ctx).With().Warning("missed hare window, skipping layer", layer)
+				if h.resume(ctx, layer) {
+					h.broker.CleanOldLayers(layer)
+				} else {
+					h.WithContext(ctx).With().Warning("missed hare window, skipping layer", layer)
+					h.recordSkipped(layer, "missed hare window")
+				}
 				continue
 			}
 			_, err := h.onTick(ctx, layer)
@@ -775,6 +913,12 @@ func (h *Hare) Start(ctx context.Context) error {
 		h.malfeasanceLoop(ctxMalfLoop)
 		return nil
 	})
+	if h.rebroadcast != nil {
+		h.eg.Go(func() error {
+			h.rebroadcast.run(ctx)
+			return nil
+		})
+	}
 
 	return nil
 }
@@ -483,6 +483,49 @@ func TestHare_onTick_NotSynced(t *testing.T) {
 	h.Close()
 }
 
+func TestHare_resume(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.N = 2
+	cfg.RoundDuration = time.Minute
+	cfg.WakeupDelta = 0
+	clock := newMockClock()
+	mockMesh := newMockMesh(t)
+	h := createTestHare(t, mockMesh, cfg, clock, noopPubSub(t), t.Name())
+	h.wal = newWAL(sql.InMemory(), logtest.New(t))
+	h.broker.wal = h.wal
+	h.broker.Start(context.Background())
+	defer h.broker.Close()
+
+	lyrID := types.GetEffectiveGenesis().Add(1)
+	// no messages recorded for the layer
+	require.False(t, h.resume(context.Background(), lyrID))
+
+	signer, err := signing.NewEdSigner()
+	require.NoError(t, err)
+	msg := BuildPreRoundMsg(signer, NewSetFromValues(types.ProposalID{1}), types.EmptyVrfSignature)
+	require.Equal(t, lyrID, msg.Layer)
+	h.wal.add(context.Background(), msg, false, msg.Bytes())
+
+	// consensus for the layer has already ended
+	require.False(t, h.resume(context.Background(), lyrID))
+
+	clock.advanceLayer()
+	var inbox chan any
+	h.factory = func(ctx context.Context, cfg config.Config, instanceId types.LayerID, s *Set, oracle Rolacle, et *EligibilityTracker, sig *signing.EdSigner, p2p pubsub.Publisher, comm communication, clock RoundClock) Consensus {
+		require.Equal(t, h.wal, comm.wal)
+		inbox = comm.inbox
+		return newMockConsensusProcess(cfg, instanceId, s, oracle, sig, p2p, comm.report, comm.wc, make(chan struct{}))
+	}
+	mockMesh.EXPECT().GetEpochAtx(lyrID.GetEpoch()-1, h.nodeID).Return(nil, sql.ErrNotFound)
+	mockMesh.EXPECT().Proposals(lyrID).Return(nil, nil)
+	require.True(t, h.resume(context.Background(), lyrID))
+	require.NotNil(t, inbox)
+	require.Equal(t, msg, <-inbox)
+
+	// already running
+	require.False(t, h.resume(context.Background(), lyrID))
+}
+
 func TestHare_goodProposals(t *testing.T) {
 	beacon := types.RandomBeacon()
 	nodeBeacon := types.RandomBeacon()
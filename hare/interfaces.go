@@ -40,3 +40,7 @@ type mesh interface {
 type weakCoin interface {
 	Set(types.LayerID, bool) error
 }
+
+type peerCounter interface {
+	PeerCount() uint64
+}
@@ -0,0 +1,113 @@
+package hare
+
+import (
+	"context"
+	"time"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/hare/config"
+	"github.com/spacemeshos/go-spacemesh/log"
+)
+
+const skippedLimit = "limit of concurrent processes"
+
+// pendingInstance is a consensus process waiting for a running one to terminate.
+type pendingInstance struct {
+	ctx    context.Context
+	lid    types.LayerID
+	clock  RoundClock
+	replay []*Message
+}
+
+// admit makes room for a new consensus process if LimitConcurrent processes are already running.
+// with the skip policy the oldest running process is stopped. with the queue policy the new
+// process is queued until a running one terminates and false is returned.
+func (h *Hare) admit(ctx context.Context, lid types.LayerID, clock RoundClock, replay []*Message) bool {
+	if h.config.LimitConcurrent <= 0 {
+		return true
+	}
+	h.mu.Lock()
+	if len(h.cps) < h.config.LimitConcurrent {
+		h.mu.Unlock()
+		return true
+	}
+	if h.config.LimitPolicy == config.LimitQueue {
+		h.queue = append(h.queue, pendingInstance{ctx: ctx, lid: lid, clock: clock, replay: replay})
+		var dropped []pendingInstance
+		if over := len(h.queue) - h.config.LimitConcurrent; over > 0 {
+			dropped, h.queue = h.queue[:over], h.queue[over:]
+		}
+		queued := len(h.queue)
+		h.mu.Unlock()
+		queuedInstances.Inc()
+		h.With().Info("queued consensus process due to limit of concurrent processes",
+			log.Context(ctx),
+			lid,
+			log.Int("queued", queued),
+		)
+		for _, pending := range dropped {
+			h.skipPending(pending)
+		}
+		return false
+	}
+	oldest := lid
+	for id := range h.cps {
+		oldest = types.MinLayer(oldest, id)
+	}
+	h.mu.Unlock()
+	h.With().Info("stopping oldest consensus process due to limit of concurrent processes",
+		log.Context(ctx),
+		lid,
+		log.Stringer("oldest", oldest),
+	)
+	skippedInstances.Inc()
+	h.stopCP(ctx, oldest)
+	return true
+}
+
+// startQueued starts queued consensus processes while there is room for them.
+// processes that can no longer terminate in time are skipped.
+func (h *Hare) startQueued() {
+	for {
+		h.mu.Lock()
+		if len(h.queue) == 0 || len(h.cps) >= h.config.LimitConcurrent {
+			h.mu.Unlock()
+			return
+		}
+		next := h.queue[0]
+		h.queue = h.queue[1:]
+		h.mu.Unlock()
+
+		if time.Now().After(next.clock.RoundEnd(uint32(h.config.LimitIterations) * RoundsPerIteration)) {
+			h.skipPending(next)
+			continue
+		}
+		if _, err := h.startConsensus(next.ctx, next.lid, next.clock, next.replay); err != nil {
+			h.With().Warning("failed to start queued hare", log.Context(next.ctx), next.lid, log.Err(err))
+		}
+	}
+}
+
+func (h *Hare) skipPending(pending pendingInstance) {
+	h.With().Info("skipping queued consensus process",
+		log.Context(pending.ctx),
+		pending.lid,
+	)
+	skippedInstances.Inc()
+	h.recordSkipped(pending.lid, skippedLimit)
+}
+
+// stopCP cancels the running consensus process without waiting for its output.
+func (h *Hare) stopCP(ctx context.Context, lid types.LayerID) {
+	h.mu.Lock()
+	cancel, exist := h.cancels[lid]
+	h.mu.Unlock()
+	if !exist {
+		return
+	}
+	cancel()
+	h.broker.Unregister(ctx, lid)
+	h.removeCP(ctx, lid)
+	h.wal.truncate(ctx, lid)
+	h.recordStopped(lid, skippedLimit)
+}
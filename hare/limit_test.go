@@ -0,0 +1,119 @@
+package hare
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/hare/config"
+	"github.com/spacemeshos/go-spacemesh/p2p/pubsub"
+	"github.com/spacemeshos/go-spacemesh/signing"
+	"github.com/spacemeshos/go-spacemesh/sql"
+)
+
+type idleConsensusProcess struct {
+	ctx context.Context
+	id  types.LayerID
+}
+
+func (cp *idleConsensusProcess) Start() {}
+
+func (cp *idleConsensusProcess) Stop() {}
+
+func (cp *idleConsensusProcess) ID() types.LayerID {
+	return cp.id
+}
+
+func createLimitedHare(t *testing.T, policy string) (*hareWithMocks, map[types.LayerID]*idleConsensusProcess) {
+	cfg := config.DefaultConfig()
+	cfg.LimitConcurrent = 2
+	cfg.LimitPolicy = policy
+	mockMesh := newMockMesh(t)
+	mockMesh.EXPECT().GetEpochAtx(gomock.Any(), gomock.Any()).Return(nil, sql.ErrNotFound).AnyTimes()
+	mockMesh.EXPECT().Proposals(gomock.Any()).Return(nil, nil).AnyTimes()
+	h := createTestHare(t, mockMesh, cfg, newMockClock(), noopPubSub(t), t.Name())
+	started := map[types.LayerID]*idleConsensusProcess{}
+	h.factory = func(ctx context.Context, _ config.Config, lid types.LayerID, _ *Set, _ Rolacle, _ *EligibilityTracker, _ *signing.EdSigner, _ pubsub.Publisher, _ communication, _ RoundClock) Consensus {
+		cp := &idleConsensusProcess{ctx: ctx, id: lid}
+		started[lid] = cp
+		return cp
+	}
+	return h, started
+}
+
+func TestHare_LimitSkip(t *testing.T) {
+	h, started := createLimitedHare(t, config.LimitSkip)
+	lid := types.GetEffectiveGenesis().Add(1)
+	for i := 0; i < 3; i++ {
+		ok, err := h.startConsensus(context.Background(), lid.Add(uint32(i)), NewSimpleRoundClock(time.Now(), 0, time.Minute), nil)
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+	require.Equal(t, 2, h.numCPs())
+	require.Nil(t, h.getCP(lid))
+	require.ErrorIs(t, started[lid].ctx.Err(), context.Canceled)
+	require.NoError(t, started[lid.Add(1)].ctx.Err())
+
+	results := h.Results()
+	require.Len(t, results, 3)
+	require.True(t, results[0].Started)
+	require.Equal(t, skippedLimit, results[0].Skipped)
+	require.Empty(t, results[1].Skipped)
+}
+
+func TestHare_LimitQueue(t *testing.T) {
+	h, started := createLimitedHare(t, config.LimitQueue)
+	lid := types.GetEffectiveGenesis().Add(1)
+	for i := 0; i < 2; i++ {
+		ok, err := h.startConsensus(context.Background(), lid.Add(uint32(i)), NewSimpleRoundClock(time.Now(), 0, time.Minute), nil)
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+	// expired while queued
+	ok, err := h.startConsensus(context.Background(), lid.Add(2), NewSimpleRoundClock(time.Now().Add(-time.Hour), 0, time.Second), nil)
+	require.NoError(t, err)
+	require.False(t, ok)
+	ok, err = h.startConsensus(context.Background(), lid.Add(3), NewSimpleRoundClock(time.Now(), 0, time.Minute), nil)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Equal(t, 2, h.numCPs())
+	require.NotContains(t, started, lid.Add(3))
+
+	// nothing starts until a running process terminates
+	h.startQueued()
+	require.NotContains(t, started, lid.Add(3))
+
+	h.removeCP(context.Background(), lid)
+	h.startQueued()
+	require.Equal(t, 2, h.numCPs())
+	require.NotContains(t, started, lid.Add(2))
+	require.Contains(t, started, lid.Add(3))
+	require.NotNil(t, h.getCP(lid.Add(3)))
+
+	results := h.Results()
+	require.Len(t, results, 4)
+	require.Equal(t, lid.Add(2), results[2].Layer)
+	require.False(t, results[2].Started)
+	require.Equal(t, skippedLimit, results[2].Skipped)
+}
+
+func TestHare_LimitQueue_Bounded(t *testing.T) {
+	h, started := createLimitedHare(t, config.LimitQueue)
+	lid := types.GetEffectiveGenesis().Add(1)
+	for i := 0; i < 5; i++ {
+		_, err := h.startConsensus(context.Background(), lid.Add(uint32(i)), NewSimpleRoundClock(time.Now(), 0, time.Minute), nil)
+		require.NoError(t, err)
+	}
+	require.Len(t, started, 2)
+	h.mu.Lock()
+	require.Len(t, h.queue, 2)
+	require.Equal(t, lid.Add(3), h.queue[0].lid)
+	h.mu.Unlock()
+	results := h.Results()
+	require.Equal(t, lid.Add(2), results[2].Layer)
+	require.Equal(t, skippedLimit, results[2].Skipped)
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/hare/config"
 	"github.com/spacemeshos/go-spacemesh/log"
 	"github.com/spacemeshos/go-spacemesh/signing"
 )
@@ -17,18 +18,18 @@ type messageValidator interface {
 }
 
 type eligibilityValidator struct {
-	oracle        Rolacle
-	maxExpActives int // the maximal expected committee size
-	expLeaders    int // the expected number of leaders
+	oracle Rolacle
+	cfg    config.Config // committee size and expected leaders are selected by the epoch of the layer
 	log.Log
 }
 
-func newEligibilityValidator(oracle Rolacle, maxExpActives, expLeaders int, logger log.Log) *eligibilityValidator {
-	return &eligibilityValidator{oracle, maxExpActives, expLeaders, logger}
+func newEligibilityValidator(oracle Rolacle, cfg config.Config, logger log.Log) *eligibilityValidator {
+	return &eligibilityValidator{oracle, cfg, logger}
 }
 
 func (ev *eligibilityValidator) validateRole(ctx context.Context, nodeID types.NodeID, layer types.LayerID, round uint32, proof types.VrfSignature, eligibilityCount uint16) (bool, error) {
-	return ev.oracle.Validate(ctx, layer, round, expectedCommitteeSize(round, ev.maxExpActives, ev.expLeaders), nodeID, proof, eligibilityCount)
+	params := ev.cfg.EpochParams(layer.GetEpoch())
+	return ev.oracle.Validate(ctx, layer, round, expectedCommitteeSize(round, params.N, params.ExpectedLeaders), nodeID, proof, eligibilityCount)
 }
 
 func (ev *eligibilityValidator) ValidateEligibilityGossip(ctx context.Context, em *types.HareEligibilityGossip) bool {
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/hare/config"
 	"github.com/spacemeshos/go-spacemesh/hare/mocks"
 	"github.com/spacemeshos/go-spacemesh/log/logtest"
 	"github.com/spacemeshos/go-spacemesh/signing"
@@ -88,7 +89,7 @@ func TestMessageValidator_ValidateCertificate(t *testing.T) {
 func TestEligibilityValidator_validateRole_FailedToValidate(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mo := mocks.NewMockRolacle(ctrl)
-	ev := newEligibilityValidator(mo, 1, 5, logtest.New(t))
+	ev := newEligibilityValidator(mo, config.Config{N: 1, ExpectedLeaders: 5}, logtest.New(t))
 
 	signer, err := signing.NewEdSigner()
 	require.NoError(t, err)
@@ -105,7 +106,7 @@ func TestEligibilityValidator_validateRole_FailedToValidate(t *testing.T) {
 func TestEligibilityValidator_validateRole_NotEligible(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mo := mocks.NewMockRolacle(ctrl)
-	ev := newEligibilityValidator(mo, 1, 5, logtest.New(t))
+	ev := newEligibilityValidator(mo, config.Config{N: 1, ExpectedLeaders: 5}, logtest.New(t))
 
 	signer, err := signing.NewEdSigner()
 	require.NoError(t, err)
@@ -121,7 +122,7 @@ func TestEligibilityValidator_validateRole_NotEligible(t *testing.T) {
 func TestEligibilityValidator_validateRole_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mo := mocks.NewMockRolacle(ctrl)
-	ev := newEligibilityValidator(mo, 1, 5, logtest.New(t))
+	ev := newEligibilityValidator(mo, config.Config{N: 1, ExpectedLeaders: 5}, logtest.New(t))
 
 	signer, err := signing.NewEdSigner()
 	require.NoError(t, err)
@@ -9,9 +9,22 @@ import (
 const (
 	namespace = "hare"
 
-	// labels for hare consensus output.
-	success = "ok"
-	failure = "fail"
+	// results of a message handled by the consensus process.
+	msgSent    = "sent"
+	msgValid   = "valid"
+	msgEarly   = "early"
+	msgLate    = "late"
+	msgInvalid = "invalid"
+
+	// results of a round. a failed round is labeled with the failure reason.
+	roundOk                  = "ok"
+	roundNoSVP               = "no_svp"
+	roundNoProposal          = "no_proposal"
+	roundConflictingProposal = "conflicting_proposal"
+	roundNotEnoughCommits    = "not_enough_commits"
+	roundNoCertificate       = "no_certificate"
+	roundNotEnoughNotify     = "not_enough_notifications"
+	roundIterationsLimit     = "iterations_limit"
 )
 
 var (
@@ -29,15 +42,6 @@ var (
 		[]string{},
 	).WithLabelValues()
 
-	consensusCount = metrics.NewCounter(
-		"consensus",
-		namespace,
-		"number of hare consensus",
-		[]string{"outcome"},
-	)
-	consensusOkCnt   = consensusCount.WithLabelValues(success)
-	consensusFailCnt = consensusCount.WithLabelValues(failure)
-
 	numIterations = metrics.NewHistogramWithBuckets(
 		"num_iterations",
 		namespace,
@@ -53,3 +57,101 @@ var (
 		[]string{},
 	).WithLabelValues()
 )
+
+var (
+	batchSize = metrics.NewHistogramWithBuckets(
+		"batch_size",
+		namespace,
+		"number of own messages in a published batch",
+		[]string{},
+		prometheus.ExponentialBuckets(1, 2, 7),
+	).WithLabelValues()
+
+	unbatchedMessages = metrics.NewCounter(
+		"unbatched_messages",
+		namespace,
+		"number of messages received in batches",
+		[]string{},
+	).WithLabelValues()
+
+	verifyBatchSize = metrics.NewHistogramWithBuckets(
+		"verify_batch_size",
+		namespace,
+		"number of signatures of incoming messages verified as a batch",
+		[]string{},
+		prometheus.ExponentialBuckets(1, 2, 9),
+	).WithLabelValues()
+
+	verifyBatchFailures = metrics.NewCounter(
+		"verify_batch_failures",
+		namespace,
+		"number of signature batches that failed and were verified individually",
+		[]string{},
+	).WithLabelValues()
+
+	rebroadcastMessages = metrics.NewCounter(
+		"rebroadcast_messages",
+		namespace,
+		"number of own messages republished after the node reconnected to the network",
+		[]string{},
+	).WithLabelValues()
+
+	overBudgetMessages = metrics.NewCounter(
+		"over_budget_messages",
+		namespace,
+		"number of messages dropped because the identity exceeded its budget for the round",
+		[]string{},
+	).WithLabelValues()
+)
+
+var (
+	limitedInstances = metrics.NewCounter(
+		"limited_instances",
+		namespace,
+		"number of consensus processes queued or skipped due to the limit of concurrent processes",
+		[]string{"action"},
+	)
+	queuedInstances  = limitedInstances.WithLabelValues("queued")
+	skippedInstances = limitedInstances.WithLabelValues("skipped")
+)
+
+var (
+	roundMessages = metrics.NewCounter(
+		"round_messages",
+		namespace,
+		"number of messages sent and received by the consensus processes by round type and result",
+		[]string{"round", "result"},
+	)
+
+	roundResults = metrics.NewCounter(
+		"round_results",
+		namespace,
+		"number of completed rounds by round type and result, failed rounds are labeled with the reason",
+		[]string{"round", "result"},
+	)
+
+	roundTime = metrics.NewHistogramWithBuckets(
+		"round_time",
+		namespace,
+		"time in seconds between the start and the end of a round by round type",
+		[]string{"round"},
+		prometheus.ExponentialBuckets(0.5, 2, 10),
+	)
+)
+
+// roundType returns the label of the round type for the round counter.
+func roundType(round uint32) string {
+	if round == preRound {
+		return pre.String()
+	}
+	switch round % RoundsPerIteration {
+	case statusRound:
+		return status.String()
+	case proposalRound:
+		return proposal.String()
+	case commitRound:
+		return commit.String()
+	default:
+		return notify.String()
+	}
+}
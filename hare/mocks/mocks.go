@@ -317,3 +317,40 @@ func (mr *MockweakCoinMockRecorder) Set(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockweakCoin)(nil).Set), arg0, arg1)
 }
+
+// MockpeerCounter is a mock of peerCounter interface.
+type MockpeerCounter struct {
+	ctrl     *gomock.Controller
+	recorder *MockpeerCounterMockRecorder
+}
+
+// MockpeerCounterMockRecorder is the mock recorder for MockpeerCounter.
+type MockpeerCounterMockRecorder struct {
+	mock *MockpeerCounter
+}
+
+// NewMockpeerCounter creates a new mock instance.
+func NewMockpeerCounter(ctrl *gomock.Controller) *MockpeerCounter {
+	mock := &MockpeerCounter{ctrl: ctrl}
+	mock.recorder = &MockpeerCounterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockpeerCounter) EXPECT() *MockpeerCounterMockRecorder {
+	return m.recorder
+}
+
+// PeerCount mocks base method.
+func (m *MockpeerCounter) PeerCount() uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PeerCount")
+	ret0, _ := ret[0].(uint64)
+	return ret0
+}
+
+// PeerCount indicates an expected call of PeerCount.
+func (mr *MockpeerCounterMockRecorder) PeerCount() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PeerCount", reflect.TypeOf((*MockpeerCounter)(nil).PeerCount))
+}
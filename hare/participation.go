@@ -0,0 +1,72 @@
+package hare
+
+import (
+	"context"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/hareparticipation"
+)
+
+// participationEpochs is the number of most recent epochs for which participation is kept.
+const participationEpochs = 2
+
+const (
+	// reasons for not participating in a round.
+	reasonNotActive     = "not active"
+	reasonOracleError   = "oracle error"
+	reasonNotEligible   = "not eligible"
+	reasonPublishFailed = "publish failed"
+)
+
+// participation persists eligibility of the node identity in every round, so that
+// smeshers can audit missed participation.
+// all methods are no-op on a nil participation.
+type participation struct {
+	db     sql.Executor
+	logger log.Log
+}
+
+func newParticipation(db sql.Executor, logger log.Log) *participation {
+	return &participation{db: db, logger: logger}
+}
+
+// eligible records the result of the eligibility check for the round.
+// reason is empty if the identity is expected to participate.
+func (p *participation) eligible(ctx context.Context, lid types.LayerID, round uint32, id types.NodeID, count uint16, reason string) {
+	if p == nil {
+		return
+	}
+	rec := &hareparticipation.Record{
+		Layer:       lid,
+		Round:       round,
+		NodeID:      id,
+		Eligibility: count,
+		Reason:      reason,
+	}
+	if err := hareparticipation.Add(p.db, rec); err != nil {
+		p.logger.With().Warning("failed to record hare participation", log.Context(ctx), lid, log.Err(err))
+	}
+}
+
+// participated records that the identity published a message in the round.
+func (p *participation) participated(ctx context.Context, lid types.LayerID, round uint32, id types.NodeID) {
+	if p == nil {
+		return
+	}
+	if err := hareparticipation.SetParticipated(p.db, lid, round, id); err != nil {
+		p.logger.With().Warning("failed to record hare participation", log.Context(ctx), lid, log.Err(err))
+	}
+}
+
+// prune removes records older than participationEpochs before the epoch of the terminated layer.
+func (p *participation) prune(ctx context.Context, lid types.LayerID) {
+	if p == nil || lid.GetEpoch() < participationEpochs {
+		return
+	}
+	oldest := (lid.GetEpoch() - participationEpochs + 1).FirstLayer()
+	if err := hareparticipation.Prune(p.db, oldest); err != nil {
+		p.logger.With().Warning("failed to prune hare participation", log.Context(ctx), lid, log.Err(err))
+	}
+}
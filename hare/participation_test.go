@@ -0,0 +1,92 @@
+package hare
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/hare/mocks"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/hareparticipation"
+)
+
+func TestConsensusProcess_Participation(t *testing.T) {
+	db := sql.InMemory()
+	ctrl := gomock.NewController(t)
+	oracle := mocks.NewMockRolacle(ctrl)
+	net := &mockP2p{}
+
+	proc := generateConsensusProcess(t)
+	proc.oracle = oracle
+	proc.publisher = net
+	proc.comm.participation = newParticipation(db, logtest.New(t))
+	id := proc.signer.NodeID()
+
+	// not active in the pre-round
+	oracle.EXPECT().IsIdentityActiveOnConsensusView(gomock.Any(), id, proc.layer).Return(false, nil)
+	require.False(t, proc.shouldParticipate(context.Background()))
+
+	// eligible in the status round, but failed to publish
+	proc.advanceToNextRound(context.Background())
+	oracle.EXPECT().IsIdentityActiveOnConsensusView(gomock.Any(), id, proc.layer).Return(true, nil).AnyTimes()
+	oracle.EXPECT().Proof(gomock.Any(), proc.layer, gomock.Any()).Return(types.VrfSignature{1}, nil).AnyTimes()
+	oracle.EXPECT().CalcEligibility(gomock.Any(), proc.layer, uint32(0), gomock.Any(), id, gomock.Any()).Return(uint16(2), nil)
+	require.True(t, proc.shouldParticipate(context.Background()))
+	msg := newMessageBuilder().SetType(status).SetLayer(proc.layer).SetRoundCounter(proc.getRound()).
+		SetCommittedRound(preRound).SetValues(proc.value).SetEligibilityCount(2).Sign(proc.signer).Build()
+	net.setErr(errors.New("publish failed"))
+	require.False(t, proc.sendMessage(context.Background(), msg))
+
+	// not eligible in the proposal round
+	proc.advanceToNextRound(context.Background())
+	oracle.EXPECT().CalcEligibility(gomock.Any(), proc.layer, uint32(1), gomock.Any(), id, gomock.Any()).Return(uint16(0), nil)
+	require.False(t, proc.shouldParticipate(context.Background()))
+
+	// eligible and participated in the commit round
+	proc.advanceToNextRound(context.Background())
+	oracle.EXPECT().CalcEligibility(gomock.Any(), proc.layer, uint32(2), gomock.Any(), id, gomock.Any()).Return(uint16(1), nil)
+	require.True(t, proc.shouldParticipate(context.Background()))
+	msg = newMessageBuilder().SetType(commit).SetLayer(proc.layer).SetRoundCounter(proc.getRound()).
+		SetValues(proc.value).SetEligibilityCount(1).Sign(proc.signer).Build()
+	net.setErr(nil)
+	require.True(t, proc.sendMessage(context.Background(), msg))
+
+	// oracle failed in the notify round
+	proc.advanceToNextRound(context.Background())
+	oracle.EXPECT().CalcEligibility(gomock.Any(), proc.layer, uint32(3), gomock.Any(), id, gomock.Any()).Return(uint16(0), errors.New("oracle"))
+	require.False(t, proc.shouldParticipate(context.Background()))
+
+	recs, err := hareparticipation.List(db, proc.layer, proc.layer)
+	require.NoError(t, err)
+	require.Equal(t, []hareparticipation.Record{
+		{Layer: proc.layer, Round: 0, NodeID: id, Eligibility: 2, Reason: reasonPublishFailed},
+		{Layer: proc.layer, Round: 1, NodeID: id, Reason: reasonNotEligible},
+		{Layer: proc.layer, Round: 2, NodeID: id, Eligibility: 1, Participated: true},
+		{Layer: proc.layer, Round: 3, NodeID: id, Reason: reasonOracleError},
+		{Layer: proc.layer, Round: preRound, NodeID: id, Reason: reasonNotActive},
+	}, recs)
+}
+
+func TestParticipation_Prune(t *testing.T) {
+	db := sql.InMemory()
+	p := newParticipation(db, logtest.New(t))
+	id := types.RandomNodeID()
+	epoch := types.EpochID(participationEpochs + 1)
+	for _, lid := range []types.LayerID{epoch.FirstLayer() - 1, epoch.FirstLayer(), (epoch - 1).FirstLayer() - 1, (epoch - 1).FirstLayer()} {
+		p.eligible(context.Background(), lid, 0, id, 1, "")
+	}
+
+	p.prune(context.Background(), epoch.FirstLayer())
+	recs, err := hareparticipation.List(db, 0, epoch.FirstLayer())
+	require.NoError(t, err)
+	require.Equal(t, []hareparticipation.Record{
+		{Layer: (epoch - 1).FirstLayer(), NodeID: id, Eligibility: 1},
+		{Layer: epoch.FirstLayer() - 1, NodeID: id, Eligibility: 1},
+		{Layer: epoch.FirstLayer(), NodeID: id, Eligibility: 1},
+	}, recs)
+}
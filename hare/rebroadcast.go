@@ -0,0 +1,137 @@
+package hare
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/spacemeshos/go-spacemesh/codec"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/p2p/pubsub"
+)
+
+// rebroadcastInterval is how often the rebroadcaster checks the number of connected peers.
+const rebroadcastInterval = time.Second
+
+// rebroadcaster keeps recent messages published by the node for layers that are still in progress.
+// if the node was partitioned, it republishes them after it reconnects, so that its votes are not lost.
+// all methods are no-op on a nil rebroadcaster.
+type rebroadcaster struct {
+	publisher pubsub.Publisher
+	peers     peerCounter
+	minPeers  uint64
+	logger    log.Log
+
+	mu          sync.Mutex
+	msgs        map[types.LayerID][]Message
+	partitioned bool
+}
+
+func newRebroadcaster(publisher pubsub.Publisher, peers peerCounter, minPeers int, logger log.Log) *rebroadcaster {
+	return &rebroadcaster{
+		publisher: publisher,
+		peers:     peers,
+		minPeers:  uint64(minPeers),
+		logger:    logger,
+		msgs:      map[types.LayerID][]Message{},
+	}
+}
+
+// add records a message published by the node. the node publishes at most one message
+// per round, and messages from the previous iterations are dropped, as they will be ignored
+// by the peers anyway.
+// published is false if the message failed to be published, which means the node is partitioned.
+func (r *rebroadcaster) add(msg *Message, published bool) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !published {
+		r.partitioned = true
+	}
+	msgs := r.msgs[msg.Layer]
+	for i := range msgs {
+		if msgs[i].Round == msg.Round {
+			msgs[i] = *msg
+			return
+		}
+	}
+	msgs = append(msgs, *msg)
+	if len(msgs) > RoundsPerIteration {
+		msgs = msgs[len(msgs)-RoundsPerIteration:]
+	}
+	r.msgs[msg.Layer] = msgs
+}
+
+// remove drops messages for the terminated layer.
+func (r *rebroadcaster) remove(lid types.LayerID) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.msgs, lid)
+}
+
+// check republishes recorded messages if the node reconnected since the last check.
+func (r *rebroadcaster) check(ctx context.Context) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	if r.peers.PeerCount() < r.minPeers {
+		if !r.partitioned {
+			r.logger.With().Warning("hare detected network partition", log.Uint64("peers", r.peers.PeerCount()))
+		}
+		r.partitioned = true
+		r.mu.Unlock()
+		return
+	}
+	if !r.partitioned {
+		r.mu.Unlock()
+		return
+	}
+	r.partitioned = false
+	var msgs []Message
+	for _, layer := range r.msgs {
+		msgs = append(msgs, layer...)
+	}
+	r.mu.Unlock()
+
+	if len(msgs) == 0 {
+		return
+	}
+	// published as a batch, as identical messages published recently are dropped by pubsub
+	for len(msgs) > 0 {
+		n := len(msgs)
+		if n > maxBatchSize {
+			n = maxBatchSize
+		}
+		buf := codec.MustEncode(&MessageBatch{Messages: msgs[:n]})
+		if err := r.publisher.Publish(ctx, pubsub.HareBatchProtocol, buf); err != nil {
+			r.logger.With().Warning("failed to rebroadcast hare messages", log.Context(ctx), log.Err(err))
+			r.mu.Lock()
+			r.partitioned = true
+			r.mu.Unlock()
+			return
+		}
+		rebroadcastMessages.Add(float64(n))
+		msgs = msgs[n:]
+	}
+	r.logger.With().Info("rebroadcasted hare messages after reconnect", log.Context(ctx))
+}
+
+func (r *rebroadcaster) run(ctx context.Context) {
+	ticker := time.NewTicker(rebroadcastInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.check(ctx)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package hare
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/codec"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/hare/mocks"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/p2p/pubsub"
+	pubsubmocks "github.com/spacemeshos/go-spacemesh/p2p/pubsub/mocks"
+)
+
+func TestRebroadcaster(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	publisher := pubsubmocks.NewMockPublisher(ctrl)
+	peers := mocks.NewMockpeerCounter(ctrl)
+	r := newRebroadcaster(publisher, peers, 3, logtest.New(t))
+
+	var published []MessageBatch
+	expectPublish := func(err error) {
+		publisher.EXPECT().Publish(gomock.Any(), pubsub.HareBatchProtocol, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ string, buf []byte) error {
+				var batch MessageBatch
+				require.NoError(t, codec.Decode(buf, &batch))
+				published = append(published, batch)
+				return err
+			})
+	}
+
+	var msgs []*Message
+	for i := 0; i < RoundsPerIteration+1; i++ {
+		msg, err := MessageFromBuffer(createMessage(t, 10))
+		require.NoError(t, err)
+		msg.Round = uint32(i)
+		msgs = append(msgs, msg)
+		r.add(msg, true)
+	}
+	other, err := MessageFromBuffer(createMessage(t, 11))
+	require.NoError(t, err)
+	r.add(other, true)
+
+	// connected all the time
+	peers.EXPECT().PeerCount().Return(uint64(3))
+	r.check(context.Background())
+	require.Empty(t, published)
+
+	// partitioned and reconnected
+	peers.EXPECT().PeerCount().Return(uint64(1)).Times(2)
+	r.check(context.Background())
+	require.Empty(t, published)
+	peers.EXPECT().PeerCount().Return(uint64(5))
+	expectPublish(nil)
+	r.check(context.Background())
+	require.Len(t, published, 1)
+	// messages from the previous iteration are not republished
+	require.ElementsMatch(t, append(derefMessages(msgs[1:]), *other), published[0].Messages)
+
+	// nothing to do after rebroadcast
+	peers.EXPECT().PeerCount().Return(uint64(5))
+	r.check(context.Background())
+	require.Len(t, published, 1)
+
+	// failed publish is a sign of partition, the layer terminated in the meantime
+	r.remove(10)
+	r.add(other, false)
+	peers.EXPECT().PeerCount().Return(uint64(5))
+	expectPublish(errors.New("no peers"))
+	r.check(context.Background())
+	require.Len(t, published, 2)
+	peers.EXPECT().PeerCount().Return(uint64(5))
+	expectPublish(nil)
+	r.check(context.Background())
+	require.Len(t, published, 3)
+	require.Equal(t, []Message{*other}, published[2].Messages)
+}
+
+func TestRebroadcaster_Nil(t *testing.T) {
+	var r *rebroadcaster
+	msg, err := MessageFromBuffer(createMessage(t, 10))
+	require.NoError(t, err)
+	r.add(msg, false)
+	r.remove(types.LayerID(10))
+	r.check(context.Background())
+}
+
+func derefMessages(msgs []*Message) []Message {
+	rst := make([]Message, 0, len(msgs))
+	for _, msg := range msgs {
+		rst = append(rst, *msg)
+	}
+	return rst
+}
@@ -0,0 +1,94 @@
+package hare
+
+import (
+	"sort"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+)
+
+// LayerResult summarizes the consensus process of a recent layer.
+type LayerResult struct {
+	Layer types.LayerID `json:"layer"`
+	// Started is false if the consensus process was not started for the layer.
+	Started bool `json:"started"`
+	// Skipped is the reason the consensus process was not started, or was stopped before termination.
+	Skipped string `json:"skipped,omitempty"`
+	// Terminated is true if the consensus process reported the output.
+	Terminated bool `json:"terminated"`
+	// Completed is true if the consensus process agreed on the output.
+	Completed bool `json:"completed"`
+	// Rounds is the number of rounds after the pre-round until termination.
+	Rounds uint32 `json:"rounds"`
+	// Participants is the number of distinct eligible identities seen during the layer.
+	Participants int `json:"participants"`
+	// OutputSize is the number of proposals in the output.
+	OutputSize int `json:"output_size"`
+}
+
+// Results returns the results of the recent layers ordered by layer.
+func (h *Hare) Results() []LayerResult {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	rst := make([]LayerResult, 0, len(h.results))
+	for _, result := range h.results {
+		rst = append(rst, *result)
+	}
+	sort.Slice(rst, func(i, j int) bool {
+		return rst[i].Layer.Before(rst[j].Layer)
+	})
+	return rst
+}
+
+func (h *Hare) recordSkipped(lid types.LayerID, reason string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.addResultLocked(&LayerResult{Layer: lid, Skipped: reason})
+}
+
+func (h *Hare) recordStarted(lid types.LayerID) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.addResultLocked(&LayerResult{Layer: lid, Started: true})
+}
+
+func (h *Hare) recordStopped(lid types.LayerID, reason string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	result, exist := h.results[lid]
+	if !exist {
+		result = &LayerResult{Layer: lid, Started: true}
+		h.addResultLocked(result)
+	}
+	result.Skipped = reason
+}
+
+func (h *Hare) recordOutput(out report) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	result, exist := h.results[out.id]
+	if !exist {
+		result = &LayerResult{Layer: out.id, Started: true}
+		h.addResultLocked(result)
+	}
+	result.Terminated = true
+	result.Completed = out.completed
+	result.Rounds = out.rounds
+	result.Participants = out.participants
+	if out.completed && out.set != nil {
+		result.OutputSize = out.set.Size()
+	}
+}
+
+// addResultLocked keeps results only for the layers within hdist of the latest result.
+func (h *Hare) addResultLocked(result *LayerResult) {
+	h.results[result.Layer] = result
+	if !result.Layer.After(types.LayerID(h.config.Hdist)) {
+		return
+	}
+	oldest := result.Layer.Sub(h.config.Hdist)
+	for lid := range h.results {
+		if lid.Before(oldest) {
+			delete(h.results, lid)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package hare
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/hare/config"
+)
+
+func TestHare_Results(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Hdist = 3
+	h := createTestHare(t, newMockMesh(t), cfg, newMockClock(), noopPubSub(t), t.Name())
+	require.Empty(t, h.Results())
+
+	h.recordSkipped(10, "not synced")
+	h.recordStarted(11)
+	h.recordStarted(12)
+	set := NewSetFromValues(types.RandomProposalID(), types.RandomProposalID())
+	h.recordOutput(report{id: 11, set: set, completed: true, rounds: 4, participants: 7})
+	h.recordOutput(report{id: 12, set: set, completed: false, rounds: 8, participants: 3})
+	require.Equal(t, []LayerResult{
+		{Layer: 10, Skipped: "not synced"},
+		{Layer: 11, Started: true, Terminated: true, Completed: true, Rounds: 4, Participants: 7, OutputSize: 2},
+		{Layer: 12, Started: true, Terminated: true, Rounds: 8, Participants: 3},
+	}, h.Results())
+
+	// results older than hdist are dropped
+	h.recordStarted(14)
+	rst := h.Results()
+	require.Len(t, rst, 3)
+	require.Equal(t, types.LayerID(11), rst[0].Layer)
+	require.Equal(t, LayerResult{Layer: 14, Started: true}, rst[2])
+}
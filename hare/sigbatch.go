@@ -0,0 +1,98 @@
+package hare
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/signing"
+)
+
+// maxVerifySize is the number of signatures after which the batch is verified without waiting.
+const maxVerifySize = 256
+
+type sigRequest struct {
+	id     types.NodeID
+	msg    []byte
+	sig    types.EdSignature
+	result chan bool
+}
+
+// sigBatcher collects signatures of incoming hare messages over a short window and
+// verifies them as a batch. if the batch fails, every signature is verified individually.
+// batch verification uses the ZIP-215 rules, so it is used only for messages starting from
+// the epoch agreed upon by the network. signatures of earlier messages are verified strictly.
+type sigBatcher struct {
+	verifier *signing.EdVerifier
+	logger   log.Log
+	window   time.Duration
+	epoch    types.EpochID
+
+	mu      sync.Mutex
+	pending []*sigRequest
+	timer   *time.Timer
+}
+
+func newSigBatcher(verifier *signing.EdVerifier, window time.Duration, epoch types.EpochID, logger log.Log) *sigBatcher {
+	return &sigBatcher{verifier: verifier, window: window, epoch: epoch, logger: logger}
+}
+
+// Verify blocks until the signature of the message for the layer is verified with the rest of the batch.
+func (s *sigBatcher) Verify(ctx context.Context, lid types.LayerID, id types.NodeID, msg []byte, sig types.EdSignature) bool {
+	if lid.GetEpoch() < s.epoch {
+		return s.verifier.Verify(signing.HARE, id, msg, sig)
+	}
+	req := &sigRequest{id: id, msg: msg, sig: sig, result: make(chan bool, 1)}
+	s.mu.Lock()
+	s.pending = append(s.pending, req)
+	if len(s.pending) >= maxVerifySize {
+		s.timer.Stop()
+		pending := s.pending
+		s.pending = nil
+		s.mu.Unlock()
+		s.verify(pending)
+	} else {
+		if len(s.pending) == 1 {
+			s.timer = time.AfterFunc(s.window, s.flush)
+		}
+		s.mu.Unlock()
+	}
+	select {
+	case valid := <-req.result:
+		return valid
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (s *sigBatcher) flush() {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = nil
+	s.mu.Unlock()
+	s.verify(pending)
+}
+
+func (s *sigBatcher) verify(pending []*sigRequest) {
+	if len(pending) == 0 {
+		return
+	}
+	verifyBatchSize.Observe(float64(len(pending)))
+	batch := s.verifier.NewBatch()
+	for _, req := range pending {
+		batch.Add(signing.HARE, req.id, req.msg, req.sig)
+	}
+	if batch.Verify() {
+		for _, req := range pending {
+			req.result <- true
+		}
+		return
+	}
+	verifyBatchFailures.Inc()
+	s.logger.With().Debug("hare signature batch failed, verifying individually", log.Int("size", len(pending)))
+	for _, req := range pending {
+		req.result <- s.verifier.VerifyZIP215(signing.HARE, req.id, req.msg, req.sig)
+	}
+}
@@ -0,0 +1,108 @@
+package hare
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/signing"
+)
+
+// batchLayer returns a layer in the epoch in which the batch verification is activated in tests.
+func batchLayer() types.LayerID {
+	return types.EpochID(1).FirstLayer()
+}
+
+func TestSigBatcher(t *testing.T) {
+	const size = 20
+	verifier, err := signing.NewEdVerifier()
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		desc     string
+		window   time.Duration
+		invalid  map[int]struct{}
+		failures float64
+	}{
+		{desc: "valid", window: 10 * time.Millisecond},
+		{desc: "invalid", window: 10 * time.Millisecond, invalid: map[int]struct{}{3: {}, 11: {}}, failures: 1},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			s := newSigBatcher(verifier, tc.window, 1, logtest.New(t))
+			before := testutil.ToFloat64(verifyBatchFailures)
+
+			results := make([]bool, size)
+			var wg sync.WaitGroup
+			for i := 0; i < size; i++ {
+				signer, err := signing.NewEdSigner()
+				require.NoError(t, err)
+				msg := []byte{byte(i)}
+				sig := signer.Sign(signing.HARE, msg)
+				if _, ok := tc.invalid[i]; ok {
+					sig = signer.Sign(signing.HARE, []byte("other"))
+				}
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					results[i] = s.Verify(context.Background(), batchLayer(), signer.NodeID(), msg, sig)
+				}(i)
+			}
+			wg.Wait()
+			for i, valid := range results {
+				_, invalid := tc.invalid[i]
+				require.Equal(t, !invalid, valid, i)
+			}
+			require.Equal(t, before+tc.failures, testutil.ToFloat64(verifyBatchFailures))
+		})
+	}
+}
+
+func TestSigBatcher_MaxSize(t *testing.T) {
+	verifier, err := signing.NewEdVerifier()
+	require.NoError(t, err)
+	// the window is never reached, the batch is verified when it is full
+	s := newSigBatcher(verifier, time.Hour, 1, logtest.New(t))
+	signer, err := signing.NewEdSigner()
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < maxVerifySize; i++ {
+		msg := []byte{byte(i), byte(i >> 8)}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.True(t, s.Verify(context.Background(), batchLayer(), signer.NodeID(), msg, signer.Sign(signing.HARE, msg)))
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSigBatcher_Canceled(t *testing.T) {
+	verifier, err := signing.NewEdVerifier()
+	require.NoError(t, err)
+	s := newSigBatcher(verifier, time.Hour, 1, logtest.New(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.False(t, s.Verify(ctx, batchLayer(), types.RandomNodeID(), []byte{1}, types.RandomEdSignature()))
+}
+
+func TestSigBatcher_ActivationEpoch(t *testing.T) {
+	verifier, err := signing.NewEdVerifier()
+	require.NoError(t, err)
+	s := newSigBatcher(verifier, time.Millisecond, 2, logtest.New(t))
+	// valid only with the ZIP-215 rules, see signing.TestEdVerifier_ZIP215
+	var id types.NodeID
+	id[0] = 1
+	var sig types.EdSignature
+	msg := []byte{1}
+
+	require.False(t, s.Verify(context.Background(), types.EpochID(2).FirstLayer()-1, id, msg, sig))
+	require.True(t, s.Verify(context.Background(), types.EpochID(2).FirstLayer(), id, msg, sig))
+}
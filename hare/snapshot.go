@@ -0,0 +1,150 @@
+package hare
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+)
+
+// ErrNotRunning is returned if there is no running consensus process for the layer.
+var ErrNotRunning = errors.New("consensus process is not running")
+
+// InstanceSnapshot is the live state of a running consensus process.
+type InstanceSnapshot struct {
+	Layer          types.LayerID      `json:"layer"`
+	Round          uint32             `json:"round"`
+	RoundType      string             `json:"round_type"`
+	Iteration      uint32             `json:"iteration"`
+	CommittedRound uint32             `json:"committed_round"`
+	Value          []types.ProposalID `json:"value"`
+	// Certificate is the set of values in the certificate gathered by the process, if any.
+	Certificate      []types.ProposalID `json:"certificate,omitempty"`
+	EligibilityCount uint16             `json:"eligibility_count"`
+	// Participants is the number of identities that sent valid messages in any round.
+	Participants int `json:"participants"`
+
+	PreRound PreRoundSnapshot  `json:"preround"`
+	Status   *StatusSnapshot   `json:"status,omitempty"`
+	Proposal *ProposalSnapshot `json:"proposal,omitempty"`
+	Commit   *CommitSnapshot   `json:"commit,omitempty"`
+	Notify   *NotifySnapshot   `json:"notify,omitempty"`
+}
+
+// PreRoundSnapshot is the state of the pre-round tracker.
+type PreRoundSnapshot struct {
+	Messages int  `json:"messages"`
+	Coinflip bool `json:"coinflip"`
+}
+
+// StatusSnapshot is the state of the status tracker of the current iteration.
+type StatusSnapshot struct {
+	Messages int  `json:"messages"`
+	SVPReady bool `json:"svp_ready"`
+	// SafeValues is the set proved by the status messages, once the svp is ready.
+	SafeValues []types.ProposalID `json:"safe_values,omitempty"`
+}
+
+// ProposalSnapshot is the state of the proposal tracker of the current iteration.
+type ProposalSnapshot struct {
+	Proposed    []types.ProposalID `json:"proposed,omitempty"`
+	Conflicting bool               `json:"conflicting"`
+}
+
+// CommitSnapshot is the state of the commit tracker of the current iteration.
+type CommitSnapshot struct {
+	Count         TallySnapshot `json:"count"`
+	EnoughCommits bool          `json:"enough_commits"`
+}
+
+// NotifySnapshot is the state of the notify tracker.
+type NotifySnapshot struct {
+	Messages     int `json:"messages"`
+	Certificates int `json:"certificates"`
+}
+
+// TallySnapshot is the number of eligibilities of honest, dishonest and
+// known equivocating identities.
+type TallySnapshot struct {
+	Honest            int `json:"honest"`
+	Dishonest         int `json:"dishonest"`
+	KnownEquivocators int `json:"known_equivocators"`
+}
+
+// snapshotRequest is sent to the inbox of the consensus process, so that the state
+// is read by the event loop and doesn't race with message handling.
+type snapshotRequest struct {
+	result chan *InstanceSnapshot
+}
+
+func (proc *consensusProcess) snapshot() *InstanceSnapshot {
+	round := proc.getRound()
+	s := &InstanceSnapshot{
+		Layer:            proc.layer,
+		Round:            round,
+		RoundType:        roundType(round),
+		Iteration:        inferIteration(round),
+		CommittedRound:   proc.committedRound,
+		Value:            proc.value.ToSlice(),
+		EligibilityCount: proc.getEligibilityCount(),
+		Participants:     proc.eTracker.Participants(),
+		PreRound: PreRoundSnapshot{
+			Messages: len(proc.preRoundTracker.preRound),
+			Coinflip: proc.preRoundTracker.coinflip,
+		},
+	}
+	if round == preRound {
+		s.Iteration = 0
+	}
+	if proc.certificate != nil {
+		s.Certificate = proc.certificate.Values
+	}
+	if st := proc.statusesTracker; st != nil {
+		s.Status = &StatusSnapshot{Messages: len(st.statuses), SVPReady: st.IsSVPReady()}
+		if s.Status.SVPReady {
+			s.Status.SafeValues = st.ProposalSet(defaultSetSize).ToSlice()
+		}
+	}
+	if pt := proc.proposalTracker; pt != nil {
+		s.Proposal = &ProposalSnapshot{Conflicting: pt.IsConflicting()}
+		if set := pt.ProposedSet(); set != nil {
+			s.Proposal.Proposed = set.ToSlice()
+		}
+	}
+	if ct := proc.commitTracker; ct != nil {
+		count := ct.CommitCount()
+		s.Commit = &CommitSnapshot{
+			Count: TallySnapshot{
+				Honest:            count.hCount,
+				Dishonest:         count.dhCount,
+				KnownEquivocators: count.keCount,
+			},
+			EnoughCommits: ct.HasEnoughCommits(),
+		}
+	}
+	if nt := proc.notifyTracker; nt != nil {
+		s.Notify = &NotifySnapshot{Messages: len(nt.notifies), Certificates: len(nt.certificates)}
+	}
+	return s
+}
+
+// Snapshot returns the live state of the consensus process running for the layer.
+func (h *Hare) Snapshot(ctx context.Context, lid types.LayerID) (*InstanceSnapshot, error) {
+	inbox := h.broker.getInbox(lid)
+	if h.getCP(lid) == nil || inbox == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotRunning, lid)
+	}
+	req := &snapshotRequest{result: make(chan *InstanceSnapshot, 1)}
+	select {
+	case inbox <- req:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	select {
+	case s := <-req.result:
+		return s, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
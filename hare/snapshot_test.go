@@ -0,0 +1,66 @@
+package hare
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/hare/config"
+	"github.com/spacemeshos/go-spacemesh/sql"
+)
+
+func TestConsensusProcess_Snapshot(t *testing.T) {
+	proc := generateConsensusProcess(t)
+	s := proc.snapshot()
+	require.Equal(t, instanceID1, s.Layer)
+	require.Equal(t, preRound, s.Round)
+	require.Equal(t, pre.String(), s.RoundType)
+	require.Zero(t, s.Iteration)
+	require.Equal(t, proc.value.ToSlice(), s.Value)
+	require.Nil(t, s.Status)
+	require.Nil(t, s.Commit)
+
+	proc.advanceToNextRound(context.Background())
+	proc.beginStatusRound(context.Background())
+	proc.statusesTracker.RecordStatus(context.Background(), buildStatusMsg(proc.signer, proc.value, 0))
+	s = proc.snapshot()
+	require.Equal(t, status.String(), s.RoundType)
+	require.Equal(t, &StatusSnapshot{Messages: 1}, s.Status)
+
+	proc.advanceToNextRound(context.Background())
+	proc.advanceToNextRound(context.Background())
+	proc.advanceToNextRound(context.Background())
+	proc.advanceToNextRound(context.Background())
+	proc.certificate = &Certificate{Values: []types.ProposalID{{1}}}
+	s = proc.snapshot()
+	require.EqualValues(t, 1, s.Iteration)
+	require.Equal(t, []types.ProposalID{{1}}, s.Certificate)
+}
+
+func TestHare_Snapshot(t *testing.T) {
+	mockMesh := newMockMesh(t)
+	mockMesh.EXPECT().GetEpochAtx(gomock.Any(), gomock.Any()).Return(nil, sql.ErrNotFound).AnyTimes()
+	mockMesh.EXPECT().Proposals(gomock.Any()).Return(nil, nil).AnyTimes()
+	h := createTestHare(t, mockMesh, config.DefaultConfig(), newMockClock(), noopPubSub(t), t.Name())
+	h.mockRoracle.EXPECT().IsIdentityActiveOnConsensusView(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+	lid := types.GetEffectiveGenesis().Add(1)
+
+	_, err := h.Snapshot(context.Background(), lid)
+	require.ErrorIs(t, err, ErrNotRunning)
+
+	ok, err := h.startConsensus(context.Background(), lid, NewSimpleRoundClock(time.Now(), time.Minute, time.Minute), nil)
+	require.NoError(t, err)
+	require.True(t, ok)
+	t.Cleanup(func() { h.stopCP(context.Background(), lid) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	s, err := h.Snapshot(ctx, lid)
+	require.NoError(t, err)
+	require.Equal(t, lid, s.Layer)
+	require.Equal(t, preRound, s.Round)
+}
@@ -0,0 +1,46 @@
+package hare
+
+import (
+	"context"
+	"sync"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+)
+
+// turboProcess outputs its input set as soon as it is started, without running the protocol.
+// it agrees with the rest of the network only if the node is the only participant,
+// and is meant for single node networks used in development.
+type turboProcess struct {
+	ctx   context.Context
+	layer types.LayerID
+	set   *Set
+	comm  communication
+	once  sync.Once
+}
+
+func newTurboProcess(ctx context.Context, layer types.LayerID, s *Set, comm communication) *turboProcess {
+	return &turboProcess{ctx: ctx, layer: layer, set: s, comm: comm}
+}
+
+// ID returns the instance id.
+func (p *turboProcess) ID() types.LayerID {
+	return p.layer
+}
+
+// Start reports the input set and a weak coin that is always false.
+func (p *turboProcess) Start() {
+	p.once.Do(func() {
+		select {
+		case p.comm.wc <- wcReport{id: p.layer}:
+		case <-p.ctx.Done():
+			return
+		}
+		select {
+		case p.comm.report <- report{id: p.layer, set: p.set, completed: true}:
+		case <-p.ctx.Done():
+		}
+	})
+}
+
+// Stop is a no-op as Start doesn't leave any work behind.
+func (p *turboProcess) Stop() {}
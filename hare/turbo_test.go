@@ -0,0 +1,37 @@
+package hare
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/hare/config"
+	"github.com/spacemeshos/go-spacemesh/sql"
+)
+
+func TestHare_Turbo(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Turbo = true
+	mockMesh := newMockMesh(t)
+	h := createTestHare(t, mockMesh, cfg, newMockClock(), noopPubSub(t), t.Name())
+
+	lid := types.GetEffectiveGenesis().Add(1)
+	props := []*types.Proposal{randomProposal(lid, types.EmptyBeacon), randomProposal(lid, types.EmptyBeacon)}
+	mockMesh.EXPECT().GetEpochAtx(lid.GetEpoch()-1, h.nodeID).Return(nil, sql.ErrNotFound)
+	mockMesh.EXPECT().Proposals(lid).Return(props, nil)
+
+	started, err := h.startConsensus(context.Background(), lid, NewSimpleRoundClock(time.Now(), 0, time.Minute), nil)
+	require.NoError(t, err)
+	require.True(t, started)
+	require.NotNil(t, h.getCP(lid))
+
+	wc := <-h.wcChan
+	require.Equal(t, wcReport{id: lid}, wc)
+	out := <-h.outputChan
+	require.Equal(t, lid, out.id)
+	require.True(t, out.completed)
+	require.ElementsMatch(t, types.ToProposalIDs(props), out.set.ToSlice())
+}
@@ -0,0 +1,81 @@
+package hare
+
+import (
+	"context"
+	"errors"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/harewal"
+)
+
+// wal persists hare messages for layers that are still in progress, so that a
+// restarted node can rejoin the consensus instead of abstaining.
+// all methods are no-op on a nil wal.
+type wal struct {
+	db     sql.Executor
+	logger log.Log
+}
+
+func newWAL(db sql.Executor, logger log.Log) *wal {
+	return &wal{db: db, logger: logger}
+}
+
+func (w *wal) add(ctx context.Context, msg *Message, sent bool, buf []byte) {
+	if w == nil {
+		return
+	}
+	if err := harewal.Add(w.db, msg.Layer, msg.Round, sent, buf); err != nil {
+		w.logger.With().Warning("failed to record hare message", log.Context(ctx), msg.Layer, log.Err(err))
+	}
+}
+
+// sent returns the message this node published in the round before it was restarted.
+// it must be republished instead of a new one, otherwise the node equivocates.
+func (w *wal) sent(ctx context.Context, lid types.LayerID, round uint32) []byte {
+	if w == nil {
+		return nil
+	}
+	buf, err := harewal.Sent(w.db, lid, round)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNotFound) {
+			w.logger.With().Warning("failed to read hare wal", log.Context(ctx), lid, log.Err(err))
+		}
+		return nil
+	}
+	return buf
+}
+
+// replay returns the messages recorded for the layer in the order they were received.
+func (w *wal) replay(ctx context.Context, lid types.LayerID) []*Message {
+	if w == nil {
+		return nil
+	}
+	bufs, err := harewal.Layer(w.db, lid)
+	if err != nil {
+		w.logger.With().Warning("failed to read hare wal", log.Context(ctx), lid, log.Err(err))
+		return nil
+	}
+	msgs := make([]*Message, 0, len(bufs))
+	for _, buf := range bufs {
+		msg, err := MessageFromBuffer(buf)
+		if err != nil || msg.InnerMessage == nil {
+			w.logger.With().Warning("malformed message in hare wal", log.Context(ctx), lid, log.Err(err))
+			continue
+		}
+		msg.signedHash = types.BytesToHash(msg.InnerMessage.HashBytes())
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
+
+// truncate removes messages for terminated layers.
+func (w *wal) truncate(ctx context.Context, lid types.LayerID) {
+	if w == nil {
+		return
+	}
+	if err := harewal.Prune(w.db, lid); err != nil {
+		w.logger.With().Warning("failed to truncate hare wal", log.Context(ctx), lid, log.Err(err))
+	}
+}
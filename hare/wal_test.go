@@ -0,0 +1,40 @@
+package hare
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/signing"
+	"github.com/spacemeshos/go-spacemesh/sql"
+)
+
+func TestWAL(t *testing.T) {
+	ctx := context.Background()
+	w := newWAL(sql.InMemory(), logtest.New(t))
+
+	signer, err := signing.NewEdSigner()
+	require.NoError(t, err)
+	own := BuildPreRoundMsg(signer, NewSetFromValues(types.ProposalID{1}), types.EmptyVrfSignature)
+	w.add(ctx, own, true, own.Bytes())
+
+	other, err := signing.NewEdSigner()
+	require.NoError(t, err)
+	received := BuildPreRoundMsg(other, NewSetFromValues(types.ProposalID{2}), types.EmptyVrfSignature)
+	w.add(ctx, received, false, received.Bytes())
+
+	require.Equal(t, own.Bytes(), w.sent(ctx, instanceID1, preRound))
+	require.Nil(t, w.sent(ctx, instanceID1, 0))
+	require.Equal(t, []*Message{own, received}, w.replay(ctx, instanceID1))
+
+	w.truncate(ctx, instanceID1)
+	require.Empty(t, w.replay(ctx, instanceID1))
+
+	var disabled *wal
+	disabled.add(ctx, own, true, own.Bytes())
+	require.Nil(t, disabled.sent(ctx, instanceID1, preRound))
+	require.Nil(t, disabled.replay(ctx, instanceID1))
+}
@@ -0,0 +1,67 @@
+package mesh
+
+import (
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/mesh/metrics"
+)
+
+const layerCacheSize = 32
+
+// recentCache keeps recently requested layers in memory.
+//
+// Layers are invalidated when a new ballot or block is added to them, and the whole cache
+// is cleared when the state is reverted or the mesh is pruned. Every invalidation bumps the
+// generation, so that the layer loaded from the database concurrently with the invalidation
+// is not stored in the cache.
+type recentCache struct {
+	mu         sync.Mutex
+	generation uint64
+	layers     *lru.Cache[types.LayerID, *types.Layer]
+}
+
+func newRecentCache(layersSize int) (*recentCache, error) {
+	layers, err := lru.New[types.LayerID, *types.Layer](layersSize)
+	if err != nil {
+		return nil, err
+	}
+	return &recentCache{layers: layers}, nil
+}
+
+// getLayer returns cached layer and the generation that must be passed to addLayer on miss.
+func (c *recentCache) getLayer(lid types.LayerID) (*types.Layer, uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if layer, exist := c.layers.Get(lid); exist {
+		metrics.LayerCacheHits.Inc()
+		return layer, c.generation
+	}
+	metrics.LayerCacheMisses.Inc()
+	return nil, c.generation
+}
+
+func (c *recentCache) addLayer(layer *types.Layer, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation != c.generation {
+		return
+	}
+	c.layers.Add(layer.Index(), layer)
+}
+
+func (c *recentCache) invalidateLayer(lid types.LayerID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	c.layers.Remove(lid)
+}
+
+func (c *recentCache) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	c.layers.Purge()
+}
@@ -0,0 +1,56 @@
+package mesh
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+)
+
+func TestRecentCache(t *testing.T) {
+	cache, err := newRecentCache(2)
+	require.NoError(t, err)
+
+	t.Run("evicted", func(t *testing.T) {
+		for lid := types.LayerID(1); lid <= 3; lid++ {
+			_, generation := cache.getLayer(lid)
+			cache.addLayer(types.NewLayer(lid), generation)
+		}
+		layer, _ := cache.getLayer(1)
+		require.Nil(t, layer)
+		for lid := types.LayerID(2); lid <= 3; lid++ {
+			layer, _ := cache.getLayer(lid)
+			require.NotNil(t, layer)
+		}
+	})
+	t.Run("invalidated", func(t *testing.T) {
+		cache.invalidateLayer(3)
+		layer, _ := cache.getLayer(3)
+		require.Nil(t, layer)
+		layer, _ = cache.getLayer(2)
+		require.NotNil(t, layer)
+	})
+	t.Run("stale", func(t *testing.T) {
+		lid := types.LayerID(10)
+		_, generation := cache.getLayer(lid)
+		cache.invalidateLayer(lid)
+		cache.addLayer(types.NewLayer(lid), generation)
+		layer, _ := cache.getLayer(lid)
+		require.Nil(t, layer)
+	})
+	t.Run("purged", func(t *testing.T) {
+		lid := types.LayerID(20)
+		_, generation := cache.getLayer(lid)
+		cache.addLayer(types.NewLayer(lid), generation)
+		cache.purge()
+		layer, _ := cache.getLayer(lid)
+		require.Nil(t, layer)
+		layer, _ = cache.getLayer(2)
+		require.Nil(t, layer)
+		// loaded concurrently with the purge
+		cache.addLayer(types.NewLayer(lid), generation)
+		layer, _ = cache.getLayer(lid)
+		require.Nil(t, layer)
+	})
+}
@@ -0,0 +1,142 @@
+package mesh
+
+import (
+	"sync"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+)
+
+// EventKind is a kind of the mesh event.
+type EventKind uint8
+
+const (
+	// EventBlockStored is published when a new block is stored in the mesh.
+	EventBlockStored EventKind = iota + 1
+	// EventLayerValidated is published when consensus results for a layer are verified.
+	EventLayerValidated
+	// EventLayerApplied is published when a block (or empty layer) is applied to the state.
+	EventLayerApplied
+	// EventAtxReceived is published when a new atx is received by the node.
+	EventAtxReceived
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventBlockStored:
+		return "block_stored"
+	case EventLayerValidated:
+		return "layer_validated"
+	case EventLayerApplied:
+		return "layer_applied"
+	case EventAtxReceived:
+		return "atx_received"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is published by the mesh to subscribers.
+type Event struct {
+	Kind  EventKind
+	Layer types.LayerID
+	// Block is set for EventBlockStored and EventLayerApplied. Empty if empty layer was applied.
+	Block types.BlockID
+	// Atx is set for EventAtxReceived.
+	Atx *types.ActivationTxHeader
+}
+
+// Subscription receives mesh events.
+//
+// Events are published on the consensus path and delivery never blocks. If buffer overflows
+// subscription is closed and Full channel is closed, consumer is expected to resubscribe
+// and reload state from the database.
+type Subscription struct {
+	hub    *subscriptions
+	kinds  map[EventKind]struct{}
+	result chan Event
+	full   chan struct{}
+}
+
+// Out is a channel with events.
+func (s *Subscription) Out() <-chan Event {
+	return s.result
+}
+
+// Full is closed if subscription buffer overflows.
+func (s *Subscription) Full() <-chan struct{} {
+	return s.full
+}
+
+// Close removes subscription. Safe to call multiple times.
+func (s *Subscription) Close() {
+	s.hub.remove(s)
+}
+
+func (s *Subscription) matches(kind EventKind) bool {
+	if len(s.kinds) == 0 {
+		return true
+	}
+	_, exist := s.kinds[kind]
+	return exist
+}
+
+type subscriptions struct {
+	mu   sync.Mutex
+	subs map[*Subscription]struct{}
+}
+
+func (h *subscriptions) add(buffer int, kinds ...EventKind) *Subscription {
+	sub := &Subscription{
+		hub:    h,
+		kinds:  make(map[EventKind]struct{}, len(kinds)),
+		result: make(chan Event, buffer),
+		full:   make(chan struct{}),
+	}
+	for _, kind := range kinds {
+		sub.kinds[kind] = struct{}{}
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.subs == nil {
+		h.subs = map[*Subscription]struct{}{}
+	}
+	h.subs[sub] = struct{}{}
+	return sub
+}
+
+func (h *subscriptions) remove(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.subs, sub)
+}
+
+func (h *subscriptions) publish(ev Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subs {
+		if !sub.matches(ev.Kind) {
+			continue
+		}
+		select {
+		case sub.result <- ev:
+		default:
+			delete(h.subs, sub)
+			close(sub.full)
+		}
+	}
+}
+
+// Subscribe to mesh events of the specified kinds, or to all events if kinds are not specified.
+// Buffer is the number of events that can be queued before subscription is dropped.
+func (msh *Mesh) Subscribe(buffer int, kinds ...EventKind) *Subscription {
+	return msh.subscriptions.add(buffer, kinds...)
+}
+
+// OnAtx publishes EventAtxReceived. Implements activation.AtxReceiver.
+func (msh *Mesh) OnAtx(header *types.ActivationTxHeader) {
+	msh.subscriptions.publish(Event{
+		Kind:  EventAtxReceived,
+		Layer: header.PublishEpoch.FirstLayer(),
+		Atx:   header,
+	})
+}
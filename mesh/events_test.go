@@ -0,0 +1,83 @@
+package mesh
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/fixture"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+)
+
+func receive(tb testing.TB, sub *Subscription) Event {
+	tb.Helper()
+	select {
+	case ev := <-sub.Out():
+		return ev
+	default:
+		require.FailNow(tb, "expected event")
+	}
+	return Event{}
+}
+
+func TestSubscribe(t *testing.T) {
+	tm := createTestMesh(t)
+	tm.mockTortoise.EXPECT().OnBlock(gomock.Any()).AnyTimes()
+	tm.mockTortoise.EXPECT().TallyVotes(gomock.Any(), gomock.Any()).AnyTimes()
+	tm.mockVM.EXPECT().GetStateRoot().AnyTimes()
+	tm.mockVM.EXPECT().Apply(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	tm.mockState.EXPECT().UpdateCache(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	tm.mockState.EXPECT().LinkTXsWithBlock(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+
+	all := tm.Subscribe(10)
+	defer all.Close()
+	applied := tm.Subscribe(10, EventLayerApplied)
+	defer applied.Close()
+
+	lid := types.GetEffectiveGenesis().Add(1)
+	block := types.NewExistingBlock(types.BlockID{1}, types.InnerBlock{LayerIndex: lid})
+	require.NoError(t, tm.AddBlockWithTXs(context.Background(), block))
+	require.Equal(t, Event{Kind: EventBlockStored, Layer: lid, Block: block.ID()}, receive(t, all))
+	// block already exists
+	require.NoError(t, tm.AddBlockWithTXs(context.Background(), block))
+	require.Empty(t, all.Out())
+
+	tm.mockTortoise.EXPECT().Updates().Return(rlayers(
+		rlayer(lid, rblock(block.ID(), fixture.Valid(), fixture.Data())),
+	))
+	require.NoError(t, tm.ProcessLayer(context.Background(), lid))
+	require.Equal(t, Event{Kind: EventLayerApplied, Layer: lid, Block: block.ID()}, receive(t, all))
+	require.Equal(t, Event{Kind: EventLayerValidated, Layer: lid, Block: block.ID()}, receive(t, all))
+	require.Equal(t, Event{Kind: EventLayerApplied, Layer: lid, Block: block.ID()}, receive(t, applied))
+	require.Empty(t, applied.Out())
+
+	header := &types.ActivationTxHeader{}
+	header.PublishEpoch = 2
+	tm.OnAtx(header)
+	require.Equal(t, Event{Kind: EventAtxReceived, Layer: types.EpochID(2).FirstLayer(), Atx: header}, receive(t, all))
+	require.Empty(t, applied.Out())
+}
+
+func TestSubscribeOverflow(t *testing.T) {
+	tm := createTestMesh(t)
+	sub := tm.Subscribe(1)
+	tm.OnAtx(&types.ActivationTxHeader{})
+	select {
+	case <-sub.Full():
+		require.FailNow(t, "subscription is not expected to be full")
+	default:
+	}
+	tm.OnAtx(&types.ActivationTxHeader{})
+	select {
+	case <-sub.Full():
+	default:
+		require.FailNow(t, "subscription is expected to be full")
+	}
+	// dropped subscription doesn't receive new events
+	receive(t, sub)
+	tm.OnAtx(&types.ActivationTxHeader{})
+	require.Empty(t, sub.Out())
+	sub.Close()
+}
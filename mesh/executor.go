@@ -41,6 +41,11 @@ func NewExecutor(cdb *datastore.CachedDB, vm vmState, cs conservativeState, lg l
 	}
 }
 
+// GasSchedule returns the gas schedule the vm applies to the layer.
+func (e *Executor) GasSchedule(lid types.LayerID) vm.GasSchedule {
+	return e.vm.GasSchedule(lid)
+}
+
 // Revert reverts the VM state and conservative cache to the given layer.
 func (e *Executor) Revert(ctx context.Context, revertTo types.LayerID) error {
 	e.mu.Lock()
@@ -79,6 +84,10 @@ func (e *Executor) ExecuteOptimistic(
 	if err := e.checkOrder(lid); err != nil {
 		return nil, err
 	}
+	// block id is not known until transactions are executed
+	if err := e.journal(lid, types.EmptyBlockID, tids); err != nil {
+		return nil, err
+	}
 	executable, err := e.getExecutableTxs(tids)
 	if err != nil {
 		return nil, err
@@ -130,8 +139,14 @@ func (e *Executor) Execute(ctx context.Context, lid types.LayerID, block *types.
 		return err
 	}
 	if block == nil {
+		if err := e.journal(lid, types.EmptyBlockID, nil); err != nil {
+			return err
+		}
 		return e.executeEmpty(ctx, lid)
 	}
+	if err := e.journal(lid, block.ID(), block.TxIDs); err != nil {
+		return err
+	}
 
 	logger := e.logger.WithContext(ctx).WithFields(lid, block.ID())
 	executable, err := e.getExecutableTxs(block.TxIDs)
@@ -171,8 +186,9 @@ func (e *Executor) convertRewards(rewards []types.AnyReward) ([]types.CoinbaseRe
 			return nil, fmt.Errorf("exec convert rewards: %w", err)
 		}
 		res = append(res, types.CoinbaseReward{
-			Coinbase: atx.Coinbase,
-			Weight:   r.Weight,
+			SmesherID: atx.NodeID,
+			Coinbase:  atx.Coinbase,
+			Weight:    r.Weight,
 		})
 	}
 	sort.Slice(res, func(i, j int) bool {
@@ -215,6 +231,15 @@ func (e *Executor) checkOrder(lid types.LayerID) error {
 	return nil
 }
 
+// journal records the layer before it is applied, so that partially applied state
+// can be reverted if the node crashes before the mesh records the layer as applied.
+func (e *Executor) journal(lid types.LayerID, bid types.BlockID, tids []types.TransactionID) error {
+	if err := layers.AddJournal(e.cdb, &layers.JournalEntry{Layer: lid, Block: bid, TxIDs: tids}); err != nil {
+		return fmt.Errorf("executor journal: %w", err)
+	}
+	return nil
+}
+
 func updateResults(bid types.BlockID, executed []types.TransactionWithResult) {
 	for _, tx := range executed {
 		tx.Block = bid
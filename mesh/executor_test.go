@@ -84,6 +84,12 @@ func createATX(t testing.TB, db sql.Executor, cb types.Address) types.ATXID {
 	return vAtx.ID()
 }
 
+func smesherOf(t testing.TB, db sql.Executor, id types.ATXID) types.NodeID {
+	atx, err := atxs.Get(db, id)
+	require.NoError(t, err)
+	return atx.SmesherID
+}
+
 func TestExecutor_Execute(t *testing.T) {
 	te := newTestExecutor(t)
 	lid := types.GetEffectiveGenesis()
@@ -140,12 +146,14 @@ func TestExecutor_Execute(t *testing.T) {
 	}
 	expRewards := []types.CoinbaseReward{
 		{
-			Coinbase: cbs[0],
-			Weight:   rewards[0].Weight,
+			SmesherID: smesherOf(t, te.db, rewards[0].AtxID),
+			Coinbase:  cbs[0],
+			Weight:    rewards[0].Weight,
 		},
 		{
-			Coinbase: cbs[1],
-			Weight:   rewards[1].Weight,
+			SmesherID: smesherOf(t, te.db, rewards[1].AtxID),
+			Coinbase:  cbs[1],
+			Weight:    rewards[1].Weight,
 		},
 	}
 	sort.Slice(expRewards, func(i, j int) bool {
@@ -237,12 +245,14 @@ func TestExecutor_ExecuteOptimistic(t *testing.T) {
 	}
 	expRewards := []types.CoinbaseReward{
 		{
-			Coinbase: cbs[0],
-			Weight:   rewards[0].Weight,
+			SmesherID: smesherOf(t, te.db, rewards[0].AtxID),
+			Coinbase:  cbs[0],
+			Weight:    rewards[0].Weight,
 		},
 		{
-			Coinbase: cbs[1],
-			Weight:   rewards[1].Weight,
+			SmesherID: smesherOf(t, te.db, rewards[1].AtxID),
+			Coinbase:  cbs[1],
+			Weight:    rewards[1].Weight,
 		},
 	}
 	sort.Slice(expRewards, func(i, j int) bool {
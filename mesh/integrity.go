@@ -0,0 +1,248 @@
+package mesh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/datastore"
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/atxs"
+	"github.com/spacemeshos/go-spacemesh/sql/ballots"
+	"github.com/spacemeshos/go-spacemesh/sql/blocks"
+	"github.com/spacemeshos/go-spacemesh/sql/layers"
+	"github.com/spacemeshos/go-spacemesh/sql/transactions"
+)
+
+// maxReportedIssues limits the number of issues that are kept in the report.
+// All issues are still counted.
+const maxReportedIssues = 1000
+
+// IntegrityConfig is the config for the mesh integrity check.
+type IntegrityConfig struct {
+	// CheckOnStartup runs the integrity check for all layers when the node starts.
+	CheckOnStartup bool `mapstructure:"mesh-check-on-startup"`
+	// Repair deletes dangling index entries found by the check on startup.
+	Repair bool `mapstructure:"mesh-check-repair"`
+}
+
+// DefaultIntegrityConfig returns the default config for the mesh integrity check.
+func DefaultIntegrityConfig() IntegrityConfig {
+	return IntegrityConfig{}
+}
+
+// IssueKind is a kind of the inconsistency found by the integrity check.
+type IssueKind string
+
+const (
+	// IssueBlockLayer is reported when the layer of the encoded block differs from the indexed layer.
+	IssueBlockLayer IssueKind = "block_layer_mismatch"
+	// IssueMissingTx is reported when the block references a transaction that is not stored.
+	IssueMissingTx IssueKind = "missing_tx"
+	// IssueMissingAtx is reported when the ballot or block reward references an atx that is not stored.
+	IssueMissingAtx IssueKind = "missing_atx"
+	// IssueMissingAppliedBlock is reported when the layer is applied with a block that is not stored.
+	IssueMissingAppliedBlock IssueKind = "missing_applied_block"
+)
+
+// IntegrityIssue is a single inconsistency found by the integrity check.
+type IntegrityIssue struct {
+	Kind  IssueKind     `json:"kind"`
+	Layer types.LayerID `json:"layer"`
+	// Object is the id of the object that holds the reference.
+	Object string `json:"object"`
+	// Ref is the id of the missing or inconsistent reference.
+	Ref string `json:"ref,omitempty"`
+}
+
+// IntegrityReport is the outcome of the integrity check.
+type IntegrityReport struct {
+	From types.LayerID `json:"from"`
+	To   types.LayerID `json:"to"`
+	// Total is the number of issues found, Issues is capped by maxReportedIssues.
+	Total  int              `json:"total"`
+	Issues []IntegrityIssue `json:"issues,omitempty"`
+	// Dangling is the number of index entries that reference missing objects, by index name.
+	Dangling map[string]int `json:"dangling"`
+	Repaired bool           `json:"repaired"`
+	Duration time.Duration  `json:"duration"`
+}
+
+// Healthy is true if no issues and no dangling index entries were found.
+func (r *IntegrityReport) Healthy() bool {
+	if r.Total > 0 {
+		return false
+	}
+	for _, n := range r.Dangling {
+		if n > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *IntegrityReport) add(issue IntegrityIssue) {
+	r.Total++
+	if len(r.Issues) < maxReportedIssues {
+		r.Issues = append(r.Issues, issue)
+	}
+}
+
+// CheckIntegrity walks layers in the range [from, to] and verifies that blocks are stored in the layer
+// they are encoded with, that referenced transactions and atxs are stored, and that applied blocks exist.
+// It also counts index entries that reference missing objects, and deletes them if repair is true.
+//
+// Bodies that were pruned are skipped. Inconsistencies other than dangling index entries
+// are only reported, as repairing them requires data from peers.
+func CheckIntegrity(ctx context.Context, db *datastore.CachedDB, from, to types.LayerID, repair bool) (*IntegrityReport, error) {
+	start := time.Now()
+	rst := &IntegrityReport{From: from, To: to, Dangling: map[string]int{}}
+	for lid := from; !lid.After(to); lid = lid.Add(1) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := checkLayer(db, lid, rst); err != nil {
+			return nil, fmt.Errorf("check layer %s: %w", lid, err)
+		}
+	}
+	if err := checkDangling(ctx, db, rst, repair); err != nil {
+		return nil, err
+	}
+	rst.Duration = time.Since(start)
+	return rst, nil
+}
+
+func checkLayer(db sql.Executor, lid types.LayerID, rst *IntegrityReport) error {
+	blks, err := blocks.Layer(db, lid)
+	if err != nil {
+		return err
+	}
+	for _, block := range blks {
+		if block.LayerIndex != lid {
+			rst.add(IntegrityIssue{
+				Kind:   IssueBlockLayer,
+				Layer:  lid,
+				Object: block.ID().String(),
+				Ref:    block.LayerIndex.String(),
+			})
+		}
+		for _, tid := range block.TxIDs {
+			exists, err := transactions.Has(db, tid)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				rst.add(IntegrityIssue{Kind: IssueMissingTx, Layer: lid, Object: block.ID().String(), Ref: tid.String()})
+			}
+		}
+		for _, reward := range block.Rewards {
+			if err := checkAtx(db, lid, block.ID().String(), reward.AtxID, rst); err != nil {
+				return err
+			}
+		}
+	}
+	blts, err := ballots.Layer(db, lid)
+	if err != nil {
+		return err
+	}
+	for _, ballot := range blts {
+		if err := checkAtx(db, lid, ballot.ID().String(), ballot.AtxID, rst); err != nil {
+			return err
+		}
+	}
+	applied, err := layers.GetApplied(db, lid)
+	switch {
+	case errors.Is(err, sql.ErrNotFound):
+		return nil
+	case err != nil:
+		return err
+	case applied == types.EmptyBlockID:
+		return nil
+	}
+	exists, err := blocks.Has(db, applied)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		rst.add(IntegrityIssue{Kind: IssueMissingAppliedBlock, Layer: lid, Object: lid.String(), Ref: applied.String()})
+	}
+	return nil
+}
+
+func checkAtx(db sql.Executor, lid types.LayerID, object string, id types.ATXID, rst *IntegrityReport) error {
+	exists, err := atxs.Has(db, id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		rst.add(IntegrityIssue{Kind: IssueMissingAtx, Layer: lid, Object: object, Ref: id.String()})
+	}
+	return nil
+}
+
+func checkDangling(ctx context.Context, db *datastore.CachedDB, rst *IntegrityReport, repair bool) error {
+	indexes := []struct {
+		name   string
+		count  func(sql.Executor) (int, error)
+		delete func(sql.Executor) error
+	}{
+		{"block_rewards", blocks.CountDanglingRewards, blocks.DeleteDanglingRewards},
+		{"block_transactions", transactions.CountDanglingBlockLinks, transactions.DeleteDanglingBlockLinks},
+		{"transactions_addresses", transactions.CountDanglingAddresses, transactions.DeleteDanglingAddresses},
+	}
+	for _, index := range indexes {
+		n, err := index.count(db)
+		if err != nil {
+			return err
+		}
+		rst.Dangling[index.name] = n
+	}
+	if !repair {
+		return nil
+	}
+	if err := db.WithTx(ctx, func(dbtx *sql.Tx) error {
+		for _, index := range indexes {
+			if rst.Dangling[index.name] == 0 {
+				continue
+			}
+			if err := index.delete(dbtx); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("repair dangling indexes: %w", err)
+	}
+	rst.Repaired = true
+	return nil
+}
+
+// LogIntegrityReport logs the summary of the report and the reported issues.
+func LogIntegrityReport(logger log.Log, rst *IntegrityReport) {
+	for _, issue := range rst.Issues {
+		logger.With().Warning("mesh integrity issue",
+			log.String("kind", string(issue.Kind)),
+			issue.Layer,
+			log.String("object", issue.Object),
+			log.String("ref", issue.Ref),
+		)
+	}
+	fields := []log.LoggableField{
+		log.Stringer("from", rst.From),
+		log.Stringer("to", rst.To),
+		log.Int("issues", rst.Total),
+		log.Bool("repaired", rst.Repaired),
+		log.Duration("duration", rst.Duration),
+	}
+	for name, n := range rst.Dangling {
+		fields = append(fields, log.Int(name, n))
+	}
+	if rst.Healthy() {
+		logger.With().Info("mesh integrity check passed", fields...)
+	} else {
+		logger.With().Warning("mesh integrity check found inconsistencies", fields...)
+	}
+}
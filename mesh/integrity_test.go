@@ -0,0 +1,69 @@
+package mesh
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/datastore"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/signing"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/blocks"
+	"github.com/spacemeshos/go-spacemesh/sql/layers"
+	"github.com/spacemeshos/go-spacemesh/sql/transactions"
+)
+
+func TestCheckIntegrity(t *testing.T) {
+	types.SetLayersPerEpoch(3)
+	ctx := context.Background()
+	db := datastore.NewCachedDB(sql.InMemory(), logtest.New(t))
+	lid := types.GetEffectiveGenesis().Add(1)
+
+	sig, err := signing.NewEdSigner()
+	require.NoError(t, err)
+	createIdentity(t, db, sig)
+
+	healthy := types.NewExistingBlock(types.BlockID{1}, types.InnerBlock{
+		LayerIndex: lid,
+		TxIDs:      CreateAndSaveTxs(t, db, 2),
+	})
+	require.NoError(t, blocks.Add(db, healthy))
+	require.NoError(t, layers.SetApplied(db, lid, healthy.ID()))
+
+	rst, err := CheckIntegrity(ctx, db, lid, lid.Add(1), false)
+	require.NoError(t, err)
+	require.True(t, rst.Healthy(), "%+v", rst)
+
+	missingTx := types.RandomTransactionID()
+	missingAtx := types.RandomATXID()
+	broken := types.NewExistingBlock(types.BlockID{2}, types.InnerBlock{
+		LayerIndex: lid.Add(1),
+		TxIDs:      []types.TransactionID{missingTx},
+		Rewards:    []types.AnyReward{{AtxID: missingAtx}},
+	})
+	require.NoError(t, blocks.Add(db, broken))
+	require.NoError(t, layers.SetApplied(db, lid.Add(1), types.BlockID{3}))
+	require.NoError(t, transactions.AddToBlock(db, missingTx, lid.Add(1), types.BlockID{4}))
+
+	rst, err = CheckIntegrity(ctx, db, lid, lid.Add(1), false)
+	require.NoError(t, err)
+	require.False(t, rst.Healthy())
+	require.ElementsMatch(t, []IntegrityIssue{
+		{Kind: IssueMissingTx, Layer: lid.Add(1), Object: broken.ID().String(), Ref: missingTx.String()},
+		{Kind: IssueMissingAtx, Layer: lid.Add(1), Object: broken.ID().String(), Ref: missingAtx.String()},
+		{Kind: IssueMissingAppliedBlock, Layer: lid.Add(1), Object: lid.Add(1).String(), Ref: types.BlockID{3}.String()},
+	}, rst.Issues)
+	require.Equal(t, 3, rst.Total)
+	require.Equal(t, 1, rst.Dangling["block_transactions"])
+	require.False(t, rst.Repaired)
+
+	rst, err = CheckIntegrity(ctx, db, lid, lid.Add(1), true)
+	require.NoError(t, err)
+	require.True(t, rst.Repaired)
+	n, err := transactions.CountDanglingBlockLinks(db)
+	require.NoError(t, err)
+	require.Zero(t, n)
+}
@@ -17,6 +17,7 @@ type conservativeState interface {
 }
 
 type vmState interface {
+	GasSchedule(types.LayerID) vm.GasSchedule
 	GetStateRoot() (types.Hash32, error)
 	Revert(types.LayerID) error
 	Apply(vm.ApplyContext, []types.Transaction, []types.CoinbaseReward) ([]types.Transaction, []types.TransactionWithResult, error)
@@ -25,3 +26,7 @@ type vmState interface {
 type layerClock interface {
 	CurrentLayer() types.LayerID
 }
+
+type layerVerifier interface {
+	LastVerified() types.LayerID
+}
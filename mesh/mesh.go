@@ -6,10 +6,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"sync"
 	"time"
 
-	"go.uber.org/atomic"
 	"go.uber.org/zap/zapcore"
 
 	"github.com/spacemeshos/go-spacemesh/codec"
@@ -19,6 +19,7 @@ import (
 	"github.com/spacemeshos/go-spacemesh/events"
 	"github.com/spacemeshos/go-spacemesh/hash"
 	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/mesh/metrics"
 	"github.com/spacemeshos/go-spacemesh/sql"
 	"github.com/spacemeshos/go-spacemesh/sql/ballots"
 	"github.com/spacemeshos/go-spacemesh/sql/blocks"
@@ -43,15 +44,14 @@ type Mesh struct {
 
 	missingBlocks chan []types.BlockID
 
-	mu sync.Mutex
-	// latestLayer is the latest layer this node had seen from blocks
-	latestLayer atomic.Value
-	// latestLayerInState is the latest layer whose contents have been applied to the state
-	latestLayerInState atomic.Value
-	// processedLayer is the latest layer whose votes have been processed
-	processedLayer      atomic.Value
+	mu                  sync.Mutex
+	status              status
 	nextProcessedLayers map[types.LayerID]struct{}
 	maxProcessedLayer   types.LayerID
+	verification        verificationTracker
+
+	subscriptions subscriptions
+	cache         *recentCache
 
 	pendingUpdates struct {
 		min, max types.LayerID
@@ -60,7 +60,12 @@ type Mesh struct {
 
 // NewMesh creates a new instant of a mesh.
 func NewMesh(cdb *datastore.CachedDB, c layerClock, trtl system.Tortoise, exec *Executor, state conservativeState, logger log.Log) (*Mesh, error) {
+	cache, err := newRecentCache(layerCacheSize)
+	if err != nil {
+		return nil, fmt.Errorf("create cache: %w", err)
+	}
 	msh := &Mesh{
+		cache:               cache,
 		logger:              logger,
 		cdb:                 cdb,
 		clock:               c,
@@ -70,10 +75,6 @@ func NewMesh(cdb *datastore.CachedDB, c layerClock, trtl system.Tortoise, exec *
 		nextProcessedLayers: make(map[types.LayerID]struct{}),
 		missingBlocks:       make(chan []types.BlockID, 32),
 	}
-	msh.latestLayer.Store(types.LayerID(0))
-	msh.latestLayerInState.Store(types.LayerID(0))
-	msh.processedLayer.Store(types.LayerID(0))
-
 	lid, err := ballots.LatestLayer(cdb)
 	if err != nil && !errors.Is(err, sql.ErrNotFound) {
 		return nil, fmt.Errorf("get latest layer %w", err)
@@ -101,8 +102,10 @@ func NewMesh(cdb *datastore.CachedDB, c layerClock, trtl system.Tortoise, exec *
 	}
 
 	msh.setLatestLayer(msh.logger, genesis)
-	msh.processedLayer.Store(genesis)
-	msh.setLatestLayerInState(genesis)
+	msh.status.update(func(s *Status) {
+		s.Processed = genesis
+		s.Applied = genesis
+	})
 	return msh, nil
 }
 
@@ -113,15 +116,22 @@ func (msh *Mesh) recoverFromDB(latest types.LayerID) {
 	if err != nil {
 		msh.logger.With().Fatal("failed to recover processed layer", log.Err(err))
 	}
-	msh.processedLayer.Store(lyr)
-
 	applied, err := layers.GetLastApplied(msh.cdb)
 	if err != nil {
 		msh.logger.With().Fatal("failed to recover latest applied layer", log.Err(err))
 	}
-	msh.setLatestLayerInState(applied)
+	msh.status.update(func(s *Status) {
+		s.Processed = lyr
+		s.Applied = applied
+		// best known approximation until tortoise results are applied again
+		s.Verified = applied
+	})
 
-	if applied.After(types.GetEffectiveGenesis()) {
+	reverted, err := msh.recoverJournal(applied)
+	if err != nil {
+		msh.logger.With().Fatal("failed to recover layer apply journal", log.Err(err))
+	}
+	if !reverted && applied.After(types.GetEffectiveGenesis()) {
 		if err = msh.executor.Revert(context.Background(), applied); err != nil {
 			msh.logger.With().Fatal("failed to load state for layer", msh.LatestLayerInState(), log.Err(err))
 		}
@@ -131,9 +141,36 @@ func (msh *Mesh) recoverFromDB(latest types.LayerID) {
 		log.Stringer("processed", msh.ProcessedLayer()))
 }
 
+// recoverJournal reverts the state to the last applied layer if the node stopped
+// while a layer was being applied. The layer is applied again once tortoise results are processed.
+func (msh *Mesh) recoverJournal(applied types.LayerID) (bool, error) {
+	entry, err := layers.GetJournal(msh.cdb)
+	if errors.Is(err, sql.ErrNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	reverted := entry.Layer.After(applied)
+	if reverted {
+		msh.logger.With().Warning("layer was not completely applied, reverting state",
+			entry.Layer,
+			entry.Block,
+			log.Int("num_txs", len(entry.TxIDs)),
+			log.Stringer("applied", applied),
+		)
+		if err := msh.executor.Revert(context.Background(), applied); err != nil {
+			return false, err
+		}
+	}
+	if err := layers.ClearJournal(msh.cdb); err != nil {
+		return false, err
+	}
+	return reverted, nil
+}
+
 // LatestLayerInState returns the latest layer we applied to state.
 func (msh *Mesh) LatestLayerInState() types.LayerID {
-	return msh.latestLayerInState.Load().(types.LayerID)
+	return msh.status.get().Applied
 }
 
 // MissingBlocks returns single consumer channel.
@@ -144,7 +181,7 @@ func (msh *Mesh) MissingBlocks() <-chan []types.BlockID {
 
 // LatestLayer - returns the latest layer we saw from the network.
 func (msh *Mesh) LatestLayer() types.LayerID {
-	return msh.latestLayer.Load().(types.LayerID)
+	return msh.status.get().Latest
 }
 
 // MeshHash returns the aggregated mesh hash at the specified layer.
@@ -158,20 +195,26 @@ func (msh *Mesh) setLatestLayer(logger log.Log, lid types.LayerID) {
 		LayerID: lid,
 		Status:  events.LayerStatusTypeUnknown,
 	})
-	for {
-		current := msh.LatestLayer()
-		if !lid.After(current) {
-			return
-		}
-		if msh.latestLayer.CompareAndSwap(current, lid) {
-			events.ReportNodeStatusUpdate()
-			logger.With().Debug("set latest known layer", lid)
+	updated := false
+	msh.status.update(func(s *Status) {
+		if lid.After(s.Latest) {
+			s.Latest = lid
+			updated = true
 		}
+	})
+	if updated {
+		events.ReportNodeStatusUpdate()
+		logger.With().Debug("set latest known layer", lid)
 	}
 }
 
 // GetLayer returns GetLayer i from the database.
+// Recently requested layers are served from the cache.
 func (msh *Mesh) GetLayer(lid types.LayerID) (*types.Layer, error) {
+	cached, generation := msh.cache.getLayer(lid)
+	if cached != nil {
+		return cached, nil
+	}
 	blts, err := ballots.Layer(msh.cdb, lid)
 	if err != nil {
 		return nil, fmt.Errorf("layer ballots: %w", err)
@@ -180,12 +223,55 @@ func (msh *Mesh) GetLayer(lid types.LayerID) (*types.Layer, error) {
 	if err != nil {
 		return nil, fmt.Errorf("layer blks: %w", err)
 	}
-	return types.NewExistingLayer(lid, blts, blks), nil
+	layer := types.NewExistingLayer(lid, blts, blks)
+	msh.cache.addLayer(layer, generation)
+	return layer, nil
+}
+
+// IterateLayerBlocks passes blocks in the layer to fn one at a time, without loading
+// the whole layer in memory. Iteration stops if fn returns false. fn is called while the statement
+// holds the read connection, so it must not query the database or block on I/O.
+func (msh *Mesh) IterateLayerBlocks(lid types.LayerID, fn func(*types.Block) bool) error {
+	if cached, _ := msh.cache.getLayer(lid); cached != nil {
+		for _, block := range cached.Blocks() {
+			if !fn(block) {
+				return nil
+			}
+		}
+		return nil
+	}
+	if err := blocks.IterateLayer(msh.cdb, lid, fn); err != nil {
+		return fmt.Errorf("iterate layer blocks: %w", err)
+	}
+	return nil
+}
+
+// IterateLayerBallots passes ballots in the layer to fn one at a time, without loading
+// the whole layer in memory. Iteration stops if fn returns false. fn is called while the statement
+// holds the read connection, so it must not query the database or block on I/O.
+func (msh *Mesh) IterateLayerBallots(lid types.LayerID, fn func(*types.Ballot) bool) error {
+	if cached, _ := msh.cache.getLayer(lid); cached != nil {
+		for _, ballot := range cached.Ballots() {
+			if !fn(ballot) {
+				return nil
+			}
+		}
+		return nil
+	}
+	if err := ballots.IterateLayer(msh.cdb, lid, fn); err != nil {
+		return fmt.Errorf("iterate layer ballots: %w", err)
+	}
+	return nil
+}
+
+// OnPruned clears the recent layers cache after the mesh was pruned.
+func (msh *Mesh) OnPruned() {
+	msh.cache.purge()
 }
 
 // ProcessedLayer returns the last processed layer ID.
 func (msh *Mesh) ProcessedLayer() types.LayerID {
-	return msh.processedLayer.Load().(types.LayerID)
+	return msh.status.get().Processed
 }
 
 func (msh *Mesh) setProcessedLayer(layerID types.LayerID) error {
@@ -222,7 +308,9 @@ func (msh *Mesh) setProcessedLayer(layerID types.LayerID) error {
 	if err := layers.SetProcessed(msh.cdb, processed); err != nil {
 		return fmt.Errorf("failed to set processed layer %v: %w", processed, err)
 	}
-	msh.processedLayer.Store(processed)
+	msh.status.update(func(s *Status) {
+		s.Processed = processed
+	})
 	events.ReportNodeStatusUpdate()
 	msh.logger.Event().Debug("processed layer set", processed)
 	return nil
@@ -255,10 +343,15 @@ func (msh *Mesh) ensureStateConsistent(ctx context.Context, results []result.Lay
 	if changed == 0 {
 		return nil
 	}
+	reorg, err := msh.collectReorg(changed, results)
+	if err != nil {
+		return err
+	}
 	revert := changed.Sub(1)
 	msh.logger.With().Info("reverting state",
 		log.Context(ctx),
 		log.Uint32("revert_to", revert.Uint32()),
+		reorg,
 	)
 	if err := msh.executor.Revert(ctx, revert); err != nil {
 		return fmt.Errorf("revert state to layer %v: %w", revert, err)
@@ -266,10 +359,46 @@ func (msh *Mesh) ensureStateConsistent(ctx context.Context, results []result.Lay
 	if err := layers.UnsetAppliedFrom(msh.cdb, revert.Add(1)); err != nil {
 		return fmt.Errorf("unset applied layer %v: %w", revert.Add(1), err)
 	}
-	msh.setLatestLayerInState(revert)
+	msh.cache.purge()
+	msh.status.update(func(s *Status) {
+		s.Applied = revert
+		s.Verified = reorg.NewVerified
+	})
+	events.ReportReorg(reorg)
 	return nil
 }
 
+// collectReorg gathers blocks applied from the changed layer up to the latest applied layer.
+func (msh *Mesh) collectReorg(changed types.LayerID, results []result.Layer) (events.EventReorg, error) {
+	status := msh.status.get()
+	reorg := events.EventReorg{
+		OldVerified: status.Verified,
+		NewVerified: status.Verified,
+		Reverted:    changed,
+		Applied:     status.Applied,
+	}
+	for _, layer := range results {
+		if !layer.Verified {
+			reorg.NewVerified = types.MinLayer(reorg.NewVerified, layer.Layer.Sub(1))
+			break
+		}
+		reorg.NewVerified = layer.Layer
+	}
+	for lid := changed; !lid.After(reorg.Applied); lid = lid.Add(1) {
+		applied, err := layers.GetApplied(msh.cdb, lid)
+		if errors.Is(err, sql.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return reorg, fmt.Errorf("get applied %v: %w", lid, err)
+		}
+		if !applied.IsEmpty() {
+			reorg.Blocks = append(reorg.Blocks, applied)
+		}
+	}
+	return reorg, nil
+}
+
 // ProcessLayer reads latest consensus results and ensures that vm state
 // is consistent with results.
 // It is safe to call after optimistically executing the block.
@@ -277,6 +406,11 @@ func (msh *Mesh) ProcessLayer(ctx context.Context, lid types.LayerID) error {
 	msh.mu.Lock()
 	defer msh.mu.Unlock()
 
+	start := time.Now()
+	defer func() {
+		metrics.LayerValidationDuration.WithLabelValues(positionLabel(lid)).Observe(time.Since(start).Seconds())
+	}()
+
 	msh.logger.With().Debug("processing layer",
 		log.Context(ctx),
 		log.Uint32("layer_id", lid.Uint32()),
@@ -288,6 +422,7 @@ func (msh *Mesh) ProcessLayer(ctx context.Context, lid types.LayerID) error {
 		lid); err != nil {
 		return err
 	}
+	msh.verification.update(msh.ProcessedLayer(), msh.trtl.LatestComplete(), time.Now())
 	results := msh.trtl.Updates()
 	pending := msh.pendingUpdates.min != 0
 	if len(results) > 0 {
@@ -368,9 +503,11 @@ func (msh *Mesh) applyResults(ctx context.Context, results []result.Layer) error
 					return fmt.Errorf("get block: %w", err)
 				}
 			}
+			start := time.Now()
 			if err := msh.executor.Execute(ctx, layer.Layer, block); err != nil {
 				return fmt.Errorf("execute block %v/%v: %w", layer.Layer, target, err)
 			}
+			metrics.LayerApplyDuration.WithLabelValues(positionLabel(layer.Layer)).Observe(time.Since(start).Seconds())
 		} else {
 			msh.logger.With().Debug("correct block already applied",
 				log.Context(ctx),
@@ -385,6 +522,9 @@ func (msh *Mesh) applyResults(ctx context.Context, results []result.Layer) error
 			if err := layers.SetMeshHash(dbtx, layer.Layer, layer.Opinion); err != nil {
 				return fmt.Errorf("set mesh hash for %v/%v: %w", layer.Layer, layer.Opinion, err)
 			}
+			if err := layers.DeleteJournal(dbtx, layer.Layer); err != nil {
+				return err
+			}
 			for _, block := range layer.Blocks {
 				if block.Data && block.Valid {
 					if err := blocks.SetValid(dbtx, block.Header.ID); err != nil {
@@ -400,21 +540,52 @@ func (msh *Mesh) applyResults(ctx context.Context, results []result.Layer) error
 		}); err != nil {
 			return err
 		}
+		msh.subscriptions.publish(Event{Kind: EventLayerApplied, Layer: layer.Layer, Block: target})
+		if target != types.EmptyBlockID {
+			events.ReportBlockApplied(layer.Layer, target)
+		}
 		if layer.Verified {
 			events.ReportLayerUpdate(events.LayerUpdate{
 				LayerID: layer.Layer,
 				Status:  events.LayerStatusTypeApplied,
 			})
+			msh.subscriptions.publish(Event{Kind: EventLayerValidated, Layer: layer.Layer, Block: target})
+			if err := msh.observeLayerSize(layer); err != nil {
+				msh.logger.With().Warning("failed to observe layer size",
+					log.Context(ctx),
+					layer.Layer,
+					log.Err(err),
+				)
+			}
 		}
 
 		msh.logger.With().Debug("state persisted",
 			log.Context(ctx),
 			log.Stringer("applied", target),
 		)
-		if layer.Layer > msh.LatestLayerInState() {
-			msh.setLatestLayerInState(layer.Layer)
-		}
+		msh.status.update(func(s *Status) {
+			s.Applied = types.MaxLayer(s.Applied, layer.Layer)
+			if layer.Verified {
+				s.Verified = types.MaxLayer(s.Verified, layer.Layer)
+			}
+		})
+	}
+	return nil
+}
+
+func positionLabel(lid types.LayerID) string {
+	return strconv.FormatUint(uint64(lid.OrdinalInEpoch()), 10)
+}
+
+// observeLayerSize records the number of blocks and ballots in the verified layer.
+func (msh *Mesh) observeLayerSize(layer result.Layer) error {
+	ids, err := ballots.IDsInLayer(msh.cdb, layer.Layer)
+	if err != nil {
+		return fmt.Errorf("ballots in layer %v: %w", layer.Layer, err)
 	}
+	position := positionLabel(layer.Layer)
+	metrics.LayerNumBlocks.WithLabelValues(position).Observe(float64(len(layer.Blocks)))
+	metrics.LayerNumBallots.WithLabelValues(position).Observe(float64(len(ids)))
 	return nil
 }
 
@@ -502,17 +673,18 @@ func (msh *Mesh) ProcessLayerPerHareOutput(ctx context.Context, layerID types.La
 		return err
 	}
 	if executed {
-		if err := layers.SetApplied(msh.cdb, layerID, blockID); err != nil {
-			return fmt.Errorf("optimistically applied for %v/%v: %w", layerID, blockID, err)
+		if err := msh.cdb.WithTx(ctx, func(dbtx *sql.Tx) error {
+			if err := layers.SetApplied(dbtx, layerID, blockID); err != nil {
+				return fmt.Errorf("optimistically applied for %v/%v: %w", layerID, blockID, err)
+			}
+			return layers.DeleteJournal(dbtx, layerID)
+		}); err != nil {
+			return err
 		}
 	}
 	return msh.ProcessLayer(ctx, layerID)
 }
 
-func (msh *Mesh) setLatestLayerInState(lyr types.LayerID) {
-	msh.latestLayerInState.Store(lyr)
-}
-
 // SetZeroBlockLayer advances the latest layer in the network with a layer
 // that has no data.
 func (msh *Mesh) SetZeroBlockLayer(ctx context.Context, lid types.LayerID) {
@@ -589,6 +761,7 @@ func (msh *Mesh) AddBallot(ctx context.Context, ballot *types.Ballot) (*types.Ma
 	}); err != nil {
 		return nil, err
 	}
+	msh.cache.invalidateLayer(ballot.Layer)
 	if proof != nil {
 		msh.cdb.CacheMalfeasanceProof(ballot.SmesherID, proof)
 		msh.trtl.OnMalfeasance(ballot.SmesherID)
@@ -608,9 +781,14 @@ func (msh *Mesh) AddBlockWithTXs(ctx context.Context, block *types.Block) error
 	// add block to the tortoise before storing it
 	// otherwise fetcher will not wait until data is stored in the tortoise
 	msh.trtl.OnBlock(block.ToVote())
-	if err := blocks.Add(msh.cdb, block); err != nil && !errors.Is(err, sql.ErrObjectExists) {
+	if err := blocks.Add(msh.cdb, block); err != nil {
+		if errors.Is(err, sql.ErrObjectExists) {
+			return nil
+		}
 		return err
 	}
+	msh.cache.invalidateLayer(block.LayerIndex)
+	msh.subscriptions.publish(Event{Kind: EventBlockStored, Layer: block.LayerIndex, Block: block.ID()})
 	return nil
 }
 
@@ -635,6 +813,30 @@ func (msh *Mesh) GetRewards(coinbase types.Address) ([]*types.Reward, error) {
 	return rewards.List(msh.cdb, coinbase)
 }
 
+// BallotsBySmesher returns ballots produced by the smesher in the epoch.
+func (msh *Mesh) BallotsBySmesher(nodeID types.NodeID, epoch types.EpochID) ([]*types.Ballot, error) {
+	return ballots.BySmesher(msh.cdb, nodeID, epoch)
+}
+
+// BlocksBySmesher returns ids of the blocks that reward the smesher in the epoch.
+func (msh *Mesh) BlocksBySmesher(nodeID types.NodeID, epoch types.EpochID) ([]types.BlockID, error) {
+	return blocks.IDsBySmesher(msh.cdb, nodeID, epoch)
+}
+
+// GetSmesherRewards returns at most limit rewards earned by the smesher, starting from offset,
+// and the total number of rewards earned by the smesher. Negative limit means no limit.
+func (msh *Mesh) GetSmesherRewards(smesher types.NodeID, offset, limit int) ([]*types.Reward, int, error) {
+	total, err := rewards.CountBySmesher(msh.cdb, smesher)
+	if err != nil {
+		return nil, 0, err
+	}
+	rst, err := rewards.ListBySmesher(msh.cdb, smesher, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return rst, total, nil
+}
+
 // LastVerified returns the latest layer verified by tortoise.
 func (msh *Mesh) LastVerified() types.LayerID {
 	return msh.trtl.LatestComplete()
@@ -13,6 +13,7 @@ import (
 	"github.com/spacemeshos/go-spacemesh/common/types"
 	"github.com/spacemeshos/go-spacemesh/common/types/result"
 	"github.com/spacemeshos/go-spacemesh/datastore"
+	"github.com/spacemeshos/go-spacemesh/events"
 	"github.com/spacemeshos/go-spacemesh/genvm/sdk/wallet"
 	"github.com/spacemeshos/go-spacemesh/hash"
 	"github.com/spacemeshos/go-spacemesh/log/logtest"
@@ -58,6 +59,7 @@ func createTestMesh(t *testing.T) *testMesh {
 		mockState:    mocks.NewMockconservativeState(ctrl),
 		mockTortoise: smocks.NewMockTortoise(ctrl),
 	}
+	tm.mockTortoise.EXPECT().LatestComplete().AnyTimes()
 	exec := NewExecutor(db, tm.mockVM, tm.mockState, lg)
 	msh, err := NewMesh(db, tm.mockClock, tm.mockTortoise, exec, tm.mockState, lg)
 	require.NoError(t, err)
@@ -225,6 +227,29 @@ func TestMesh_WakeUp(t *testing.T) {
 	require.Equal(t, latestState, gotLS)
 }
 
+func TestMesh_WakeUpWithJournal(t *testing.T) {
+	tm := createTestMesh(t)
+	latest := types.GetEffectiveGenesis().Add(2)
+	b := types.NewExistingBallot(types.BallotID{1, 2, 3}, types.EmptyEdSignature, types.EmptyNodeID, latest)
+	require.NoError(t, ballots.Add(tm.cdb, &b))
+	require.NoError(t, layers.SetProcessed(tm.cdb, latest))
+	applied := types.GetEffectiveGenesis()
+	require.NoError(t, layers.AddJournal(tm.cdb, &layers.JournalEntry{
+		Layer: applied.Add(1),
+		Block: types.RandomBlockID(),
+		TxIDs: types.RandomTXSet(3),
+	}))
+
+	tm.mockVM.EXPECT().Revert(applied)
+	tm.mockState.EXPECT().RevertCache(applied)
+	tm.mockVM.EXPECT().GetStateRoot()
+	msh, err := NewMesh(tm.cdb, tm.mockClock, tm.mockTortoise, tm.executor, tm.mockState, logtest.New(t))
+	require.NoError(t, err)
+	require.Equal(t, applied, msh.LatestLayerInState())
+	_, err = layers.GetJournal(tm.cdb)
+	require.ErrorIs(t, err, sql.ErrNotFound)
+}
+
 func TestMesh_GetLayer(t *testing.T) {
 	tm := createTestMesh(t)
 	id := types.GetEffectiveGenesis().Add(1)
@@ -243,6 +268,92 @@ func TestMesh_GetLayer(t *testing.T) {
 	require.ElementsMatch(t, blks, lyr.Blocks())
 }
 
+func TestMesh_GetLayerCached(t *testing.T) {
+	tm := createTestMesh(t)
+	tm.mockTortoise.EXPECT().OnBlock(gomock.Any()).AnyTimes()
+	tm.mockState.EXPECT().LinkTXsWithBlock(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	id := types.GetEffectiveGenesis().Add(1)
+
+	blts := createLayerBallots(t, tm.Mesh, id)
+	lyr, err := tm.GetLayer(id)
+	require.NoError(t, err)
+	require.ElementsMatch(t, blts, lyr.Ballots())
+
+	// served from the cache even though data was removed from the database
+	_, err = tm.cdb.Exec("delete from ballots;", nil, nil)
+	require.NoError(t, err)
+	cached, err := tm.GetLayer(id)
+	require.NoError(t, err)
+	require.Same(t, lyr, cached)
+
+	block := genLayerBlock(id, nil)
+	require.NoError(t, tm.AddBlockWithTXs(context.Background(), block))
+	lyr, err = tm.GetLayer(id)
+	require.NoError(t, err)
+	require.Empty(t, lyr.Ballots())
+	require.Equal(t, []*types.Block{block}, lyr.Blocks())
+
+	ballot := genLayerBallot(t, id)
+	_, err = tm.AddBallot(context.Background(), ballot)
+	require.NoError(t, err)
+	lyr, err = tm.GetLayer(id)
+	require.NoError(t, err)
+	require.Equal(t, []*types.Ballot{ballot}, lyr.Ballots())
+}
+
+func TestMesh_IterateLayer(t *testing.T) {
+	tm := createTestMesh(t)
+	id := types.GetEffectiveGenesis().Add(1)
+
+	blts := createLayerBallots(t, tm.Mesh, id)
+	blks := createLayerBlocks(t, tm.db, tm.Mesh, id)
+	collect := func() ([]*types.Ballot, []*types.Block) {
+		var (
+			gotBallots []*types.Ballot
+			gotBlocks  []*types.Block
+		)
+		require.NoError(t, tm.IterateLayerBallots(id, func(ballot *types.Ballot) bool {
+			gotBallots = append(gotBallots, ballot)
+			return true
+		}))
+		require.NoError(t, tm.IterateLayerBlocks(id, func(block *types.Block) bool {
+			gotBlocks = append(gotBlocks, block)
+			return true
+		}))
+		return gotBallots, gotBlocks
+	}
+	gotBallots, gotBlocks := collect()
+	require.ElementsMatch(t, blts, gotBallots)
+	require.ElementsMatch(t, blks, gotBlocks)
+
+	// same result when the layer is cached
+	_, err := tm.GetLayer(id)
+	require.NoError(t, err)
+	gotBallots, gotBlocks = collect()
+	require.ElementsMatch(t, blts, gotBallots)
+	require.ElementsMatch(t, blks, gotBlocks)
+
+	var n int
+	require.NoError(t, tm.IterateLayerBlocks(id, func(*types.Block) bool {
+		n++
+		return false
+	}))
+	require.Equal(t, 1, n)
+}
+
+func TestMesh_Status(t *testing.T) {
+	tm := createTestMesh(t)
+	genesis := types.GetEffectiveGenesis()
+	require.Equal(t, Status{Latest: genesis, Processed: genesis, Applied: genesis}, tm.MeshStatus())
+
+	tm.setLatestLayer(logtest.New(t), genesis.Add(5))
+	status := tm.MeshStatus()
+	require.Equal(t, genesis.Add(5), status.Latest)
+	require.Equal(t, status.Latest, tm.LatestLayer())
+	require.Equal(t, status.Processed, tm.ProcessedLayer())
+	require.Equal(t, status.Applied, tm.LatestLayerInState())
+}
+
 func TestMesh_LatestKnownLayer(t *testing.T) {
 	tm := createTestMesh(t)
 	lg := logtest.New(t)
@@ -714,6 +825,61 @@ func TestProcessLayer(t *testing.T) {
 	}
 }
 
+func TestProcessLayer_Reorg(t *testing.T) {
+	events.InitializeReporter()
+	t.Cleanup(events.CloseEventReporter)
+	sub, err := events.Subscribe[events.EventReorg]()
+	require.NoError(t, err)
+	t.Cleanup(sub.Close)
+
+	tm := createTestMesh(t)
+	tm.mockTortoise.EXPECT().TallyVotes(gomock.Any(), gomock.Any()).AnyTimes()
+	tm.mockVM.EXPECT().GetStateRoot().AnyTimes()
+	tm.mockVM.EXPECT().Revert(gomock.Any()).AnyTimes()
+	tm.mockState.EXPECT().RevertCache(gomock.Any()).AnyTimes()
+	tm.mockVM.EXPECT().Apply(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	tm.mockState.EXPECT().UpdateCache(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+
+	start := types.GetEffectiveGenesis().Add(1)
+	updates := rlayers(
+		rlayer(start, rblock(idg("1"), fixture.Valid(), fixture.Data())),
+		rlayer(start.Add(1), rblock(idg("2"), fixture.Valid(), fixture.Data())),
+	)
+	ensuresDatabaseConsistent(t, tm.cdb, updates)
+	tm.mockTortoise.EXPECT().Updates().Return(updates)
+	require.NoError(t, tm.ProcessLayer(context.TODO(), start.Add(1)))
+	select {
+	case <-sub.Out():
+		require.FailNow(t, "unexpected reorg")
+	default:
+	}
+
+	updates = rlayers(
+		rlayer(start.Add(1),
+			rblock(idg("2"), fixture.Invalid(), fixture.Data()),
+			rblock(idg("3"), fixture.Valid(), fixture.Data()),
+		),
+	)
+	ensuresDatabaseConsistent(t, tm.cdb, updates)
+	tm.mockTortoise.EXPECT().Updates().Return(updates)
+	require.NoError(t, tm.ProcessLayer(context.TODO(), start.Add(1)))
+	select {
+	case reorg := <-sub.Out():
+		require.Equal(t, events.EventReorg{
+			OldVerified: start.Add(1),
+			NewVerified: start.Add(1),
+			Reverted:    start.Add(1),
+			Applied:     start.Add(1),
+			Blocks:      []types.BlockID{idg("2")},
+		}, reorg)
+	case <-time.After(time.Second):
+		require.FailNow(t, "reorg event wasn't reported")
+	}
+	applied, err := layers.GetApplied(tm.cdb, start.Add(1))
+	require.NoError(t, err)
+	require.Equal(t, idg("3"), applied)
+}
+
 func ensuresDatabaseConsistent(t *testing.T, db sql.Executor, results []result.Layer) {
 	for _, layer := range results {
 		for _, rst := range layer.Blocks {
@@ -11,11 +11,111 @@ const (
 	Subsystem = "mesh"
 )
 
-// LayerNumBlocks is number of blocks in layer.
+// PositionLabel is a label with the position of the layer in the epoch (layer modulo layers per epoch).
+const PositionLabel = "position"
+
+// LayerNumBlocks is number of blocks in verified layer.
 var LayerNumBlocks = metrics.NewHistogramWithBuckets(
 	"layer_num_blocks",
 	Subsystem,
-	"Number of blocks in layer",
-	[]string{},
+	"Number of blocks in verified layer",
+	[]string{PositionLabel},
+	prometheus.ExponentialBuckets(1, 2, 16),
+)
+
+// LayerNumBallots is number of ballots in verified layer.
+var LayerNumBallots = metrics.NewHistogramWithBuckets(
+	"layer_num_ballots",
+	Subsystem,
+	"Number of ballots in verified layer",
+	[]string{PositionLabel},
 	prometheus.ExponentialBuckets(1, 2, 16),
 )
+
+// LayerValidationDuration is the time it takes to process layer (count votes and apply results).
+var LayerValidationDuration = metrics.NewHistogramWithBuckets(
+	"layer_validation_duration_seconds",
+	Subsystem,
+	"Duration of processing layer, including counting votes and applying results",
+	[]string{PositionLabel},
+	prometheus.ExponentialBuckets(0.001, 2, 16),
+)
+
+// LayerApplyDuration is the time it takes to apply the block of the layer to the state.
+var LayerApplyDuration = metrics.NewHistogramWithBuckets(
+	"layer_apply_duration_seconds",
+	Subsystem,
+	"Duration of applying the block of the layer to the state",
+	[]string{PositionLabel},
+	prometheus.ExponentialBuckets(0.001, 2, 16),
+)
+
+// PrunedLayer is the layer (exclusive) up to which mesh bodies were pruned.
+var PrunedLayer = metrics.NewGauge(
+	"pruned_layer",
+	Subsystem,
+	"Layer up to which block, ballot and transaction bodies were pruned",
+	[]string{},
+).WithLabelValues()
+
+// PrunedBytes is the number of bytes freed by pruning mesh bodies.
+var PrunedBytes = metrics.NewCounter(
+	"pruned_bytes",
+	Subsystem,
+	"Number of bytes freed by pruning block, ballot and transaction bodies",
+	[]string{},
+).WithLabelValues()
+
+// CompactedBytes is the number of bytes returned to the filesystem by database compaction.
+var CompactedBytes = metrics.NewCounter(
+	"compacted_bytes",
+	Subsystem,
+	"Number of bytes returned to the filesystem by database compaction",
+	[]string{},
+).WithLabelValues()
+
+// ProcessedLayer is the latest layer whose votes were counted by tortoise.
+var ProcessedLayer = metrics.NewGauge(
+	"processed_layer",
+	Subsystem,
+	"Latest layer whose votes were counted by tortoise",
+	[]string{},
+).WithLabelValues()
+
+// VerifiedLayer is the latest layer verified by tortoise.
+var VerifiedLayer = metrics.NewGauge(
+	"verified_layer",
+	Subsystem,
+	"Latest layer verified by tortoise",
+	[]string{},
+).WithLabelValues()
+
+// VerificationLag is the number of processed layers that are not yet verified.
+var VerificationLag = metrics.NewGauge(
+	"verification_lag",
+	Subsystem,
+	"Number of processed layers that are not yet verified by tortoise",
+	[]string{},
+).WithLabelValues()
+
+// VerificationRate is the number of layers verified per second.
+var VerificationRate = metrics.NewGauge(
+	"verification_rate",
+	Subsystem,
+	"Number of layers verified per second, measured between the last two verified layers",
+	[]string{},
+).WithLabelValues()
+
+var cacheLookups = metrics.NewCounter(
+	"cache_lookups",
+	Subsystem,
+	"Number of lookups in the recent layers cache",
+	[]string{"kind", "result"},
+)
+
+var (
+	// LayerCacheHits is the number of GetLayer calls served from the cache.
+	LayerCacheHits = cacheLookups.WithLabelValues("layer", "hit")
+	// LayerCacheMisses is the number of GetLayer calls that were loaded from the database.
+	LayerCacheMisses = cacheLookups.WithLabelValues("layer", "miss")
+)
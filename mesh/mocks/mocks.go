@@ -131,6 +131,20 @@ func (mr *MockvmStateMockRecorder) Apply(arg0, arg1, arg2 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apply", reflect.TypeOf((*MockvmState)(nil).Apply), arg0, arg1, arg2)
 }
 
+// GasSchedule mocks base method.
+func (m *MockvmState) GasSchedule(arg0 types.LayerID) vm.GasSchedule {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GasSchedule", arg0)
+	ret0, _ := ret[0].(vm.GasSchedule)
+	return ret0
+}
+
+// GasSchedule indicates an expected call of GasSchedule.
+func (mr *MockvmStateMockRecorder) GasSchedule(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GasSchedule", reflect.TypeOf((*MockvmState)(nil).GasSchedule), arg0)
+}
+
 // GetStateRoot mocks base method.
 func (m *MockvmState) GetStateRoot() (types.Hash32, error) {
 	m.ctrl.T.Helper()
@@ -196,3 +210,40 @@ func (mr *MocklayerClockMockRecorder) CurrentLayer() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CurrentLayer", reflect.TypeOf((*MocklayerClock)(nil).CurrentLayer))
 }
+
+// MocklayerVerifier is a mock of layerVerifier interface.
+type MocklayerVerifier struct {
+	ctrl     *gomock.Controller
+	recorder *MocklayerVerifierMockRecorder
+}
+
+// MocklayerVerifierMockRecorder is the mock recorder for MocklayerVerifier.
+type MocklayerVerifierMockRecorder struct {
+	mock *MocklayerVerifier
+}
+
+// NewMocklayerVerifier creates a new mock instance.
+func NewMocklayerVerifier(ctrl *gomock.Controller) *MocklayerVerifier {
+	mock := &MocklayerVerifier{ctrl: ctrl}
+	mock.recorder = &MocklayerVerifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocklayerVerifier) EXPECT() *MocklayerVerifierMockRecorder {
+	return m.recorder
+}
+
+// LastVerified mocks base method.
+func (m *MocklayerVerifier) LastVerified() types.LayerID {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastVerified")
+	ret0, _ := ret[0].(types.LayerID)
+	return ret0
+}
+
+// LastVerified indicates an expected call of LastVerified.
+func (mr *MocklayerVerifierMockRecorder) LastVerified() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastVerified", reflect.TypeOf((*MocklayerVerifier)(nil).LastVerified))
+}
@@ -0,0 +1,259 @@
+package mesh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/datastore"
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/mesh/metrics"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/transactions"
+)
+
+// ErrPruningDisabled is returned when pruning is requested but retention is not configured.
+var ErrPruningDisabled = errors.New("pruning is disabled")
+
+// PruningConfig is the config for Pruner.
+type PruningConfig struct {
+	// RetainLayers is the number of layers behind the last verified layer
+	// for which transaction bodies are kept. It can't be smaller than the tortoise window,
+	// so that reverted layers can be applied again.
+	// Pruning is disabled if set to 0.
+	RetainLayers uint32 `mapstructure:"prune-retain-layers"`
+	// Interval between background pruning runs.
+	Interval time.Duration `mapstructure:"prune-interval"`
+	// Compact the database after a pruning run that freed any bodies.
+	Compact bool `mapstructure:"prune-compact"`
+}
+
+// DefaultPruningConfig returns the default config for Pruner.
+func DefaultPruningConfig() PruningConfig {
+	return PruningConfig{
+		RetainLayers: 0,
+		Interval:     time.Hour,
+	}
+}
+
+// PruningResult is the outcome of a single pruning run.
+type PruningResult struct {
+	// Before is the layer (exclusive) bodies were pruned up to.
+	Before       types.LayerID
+	Transactions int
+	// Freed is the number of bytes freed by dropping bodies.
+	Freed    int64
+	Duration time.Duration
+}
+
+// CompactionResult is the outcome of a database compaction.
+type CompactionResult struct {
+	// Before and After are the sizes of the database in bytes.
+	Before    int64
+	After     int64
+	Reclaimed int64
+	Duration  time.Duration
+}
+
+// PruningStatus reports the state of the pruner.
+type PruningStatus struct {
+	Enabled bool
+	// Pruned is the layer (exclusive) up to which bodies were pruned.
+	Pruned types.LayerID
+	// TotalFreed is the number of bytes freed since the node started.
+	TotalFreed int64
+	Last       *PruningResult
+	LastRun    time.Time
+	// LastCompaction is the outcome of the last database compaction.
+	LastCompaction *CompactionResult
+}
+
+// PrunerOpt for configuring Pruner.
+type PrunerOpt func(*Pruner)
+
+// WithPruningConfig defines cfg for Pruner.
+func WithPruningConfig(cfg PruningConfig) PrunerOpt {
+	return func(p *Pruner) {
+		p.cfg = cfg
+	}
+}
+
+// WithOnPruned defines a callback that is called after the mesh was pruned.
+func WithOnPruned(fn func()) PrunerOpt {
+	return func(p *Pruner) {
+		p.onPruned = fn
+	}
+}
+
+// WithPrunerLogger defines logger for Pruner.
+func WithPrunerLogger(logger log.Log) PrunerOpt {
+	return func(p *Pruner) {
+		p.logger = logger
+	}
+}
+
+// Pruner drops bodies of applied transactions that are older than the configured retention,
+// counting from the last verified layer. Headers and results are kept for the account history.
+//
+// Blocks and ballots are never pruned, as tortoise recovery and rerun replay them
+// starting from the genesis. Rerun results that would revert layers before Status().Pruned
+// are discarded by the tortoise, as such layers can't be applied again.
+type Pruner struct {
+	logger   log.Log
+	cfg      PruningConfig
+	db       *datastore.CachedDB
+	verifier layerVerifier
+	onPruned func()
+
+	mu     sync.Mutex
+	status PruningStatus
+}
+
+// NewPruner creates a new mesh pruner.
+func NewPruner(db *datastore.CachedDB, verifier layerVerifier, opts ...PrunerOpt) *Pruner {
+	p := &Pruner{
+		logger:   log.NewNop(),
+		cfg:      DefaultPruningConfig(),
+		db:       db,
+		verifier: verifier,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	p.status.Enabled = p.cfg.RetainLayers > 0
+	return p
+}
+
+// Run prunes the mesh periodically until the context is canceled.
+func (p *Pruner) Run(ctx context.Context) error {
+	if p.cfg.RetainLayers == 0 {
+		return nil
+	}
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			rst, err := p.Prune(ctx)
+			if err != nil {
+				p.logger.With().Error("failed to prune mesh", log.Context(ctx), log.Err(err))
+				continue
+			}
+			if p.cfg.Compact && rst.Freed > 0 {
+				if _, err := p.Compact(ctx); err != nil {
+					p.logger.With().Error("failed to compact database", log.Context(ctx), log.Err(err))
+				}
+			}
+		}
+	}
+}
+
+// Status returns the current pruning status.
+func (p *Pruner) Status() PruningStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	status := p.status
+	if status.Last != nil {
+		last := *status.Last
+		status.Last = &last
+	}
+	if status.LastCompaction != nil {
+		last := *status.LastCompaction
+		status.LastCompaction = &last
+	}
+	return status
+}
+
+// Prune drops bodies older than the retention window.
+func (p *Pruner) Prune(ctx context.Context) (PruningResult, error) {
+	if p.cfg.RetainLayers == 0 {
+		return PruningResult{}, ErrPruningDisabled
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	verified := p.verifier.LastVerified()
+	genesis := types.GetEffectiveGenesis()
+	if verified <= genesis.Add(p.cfg.RetainLayers) {
+		return PruningResult{}, nil
+	}
+	rst := PruningResult{Before: verified.Sub(p.cfg.RetainLayers)}
+	if rst.Before <= p.status.Pruned {
+		return PruningResult{}, nil
+	}
+
+	start := time.Now()
+	if err := p.db.WithTx(ctx, func(dbtx *sql.Tx) error {
+		var err error
+		rst.Transactions, rst.Freed, err = transactions.PruneBodies(dbtx, rst.Before)
+		return err
+	}); err != nil {
+		return PruningResult{}, fmt.Errorf("prune before %s: %w", rst.Before, err)
+	}
+	rst.Duration = time.Since(start)
+
+	p.status.Pruned = rst.Before
+	p.status.TotalFreed += rst.Freed
+	p.status.Last = &rst
+	p.status.LastRun = start
+	metrics.PrunedLayer.Set(float64(rst.Before))
+	metrics.PrunedBytes.Add(float64(rst.Freed))
+	if p.onPruned != nil {
+		p.onPruned()
+	}
+
+	p.logger.With().Info("pruned mesh",
+		log.Context(ctx),
+		log.Stringer("before", rst.Before),
+		log.Int("transactions", rst.Transactions),
+		log.Uint64("freed", uint64(rst.Freed)),
+		log.Duration("duration", rst.Duration),
+	)
+	return rst, nil
+}
+
+// Compact rebuilds the database to return the space freed by pruning to the filesystem.
+// Sqlite reuses freed pages for new data but never shrinks the file on its own.
+func (p *Pruner) Compact(ctx context.Context) (CompactionResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	start := time.Now()
+	before, free, err := sql.Size(p.db)
+	if err != nil {
+		return CompactionResult{}, fmt.Errorf("database size: %w", err)
+	}
+	p.logger.With().Info("compacting database",
+		log.Context(ctx),
+		log.Uint64("size", uint64(before)),
+		log.Uint64("free", uint64(free)),
+	)
+	if err := sql.Vacuum(p.db.Database); err != nil {
+		return CompactionResult{}, err
+	}
+	after, _, err := sql.Size(p.db)
+	if err != nil {
+		return CompactionResult{}, fmt.Errorf("database size: %w", err)
+	}
+	rst := CompactionResult{
+		Before:    before,
+		After:     after,
+		Reclaimed: before - after,
+		Duration:  time.Since(start),
+	}
+	p.status.LastCompaction = &rst
+	metrics.CompactedBytes.Add(float64(rst.Reclaimed))
+
+	p.logger.With().Info("compacted database",
+		log.Context(ctx),
+		log.Uint64("before", uint64(rst.Before)),
+		log.Uint64("after", uint64(rst.After)),
+		log.Duration("duration", rst.Duration),
+	)
+	return rst, nil
+}
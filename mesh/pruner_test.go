@@ -0,0 +1,114 @@
+package mesh
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/datastore"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/mesh/mocks"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/ballots"
+	"github.com/spacemeshos/go-spacemesh/sql/blocks"
+	"github.com/spacemeshos/go-spacemesh/sql/transactions"
+)
+
+func TestPruner(t *testing.T) {
+	types.SetLayersPerEpoch(3)
+	db := datastore.NewCachedDB(sql.InMemory(), logtest.New(t))
+	verifier := mocks.NewMocklayerVerifier(gomock.NewController(t))
+
+	genesis := types.GetEffectiveGenesis()
+	var (
+		blks []*types.Block
+		blts []*types.Ballot
+		txs  []*types.Transaction
+	)
+	for i := uint32(1); i <= 10; i++ {
+		lid := genesis.Add(i)
+		block := types.NewExistingBlock(types.BlockID{byte(i)}, types.InnerBlock{LayerIndex: lid})
+		require.NoError(t, blocks.Add(db, block))
+		blks = append(blks, block)
+		ballot := types.NewExistingBallot(types.BallotID{byte(i)}, types.EmptyEdSignature, types.NodeID{1}, lid)
+		require.NoError(t, ballots.Add(db, &ballot))
+		blts = append(blts, &ballot)
+		tx := &types.Transaction{RawTx: types.NewRawTx(types.RandomBytes(100))}
+		require.NoError(t, transactions.Add(db, tx, time.Now()))
+		require.NoError(t, db.WithTx(context.Background(), func(dbtx *sql.Tx) error {
+			return transactions.AddResult(dbtx, tx.ID, &types.TransactionResult{Layer: lid, Block: block.ID()})
+		}))
+		txs = append(txs, tx)
+	}
+
+	var pruned int
+	pruner := NewPruner(db, verifier,
+		WithPruningConfig(PruningConfig{RetainLayers: 5}),
+		WithPrunerLogger(logtest.New(t)),
+		WithOnPruned(func() { pruned++ }),
+	)
+	require.True(t, pruner.Status().Enabled)
+
+	verifier.EXPECT().LastVerified().Return(genesis.Add(5))
+	rst, err := pruner.Prune(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, rst.Transactions)
+	require.Zero(t, pruned)
+
+	verifier.EXPECT().LastVerified().Return(genesis.Add(8))
+	rst, err = pruner.Prune(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, genesis.Add(3), rst.Before)
+	require.Equal(t, 2, rst.Transactions)
+	require.Positive(t, rst.Freed)
+	require.Equal(t, 1, pruned)
+
+	status := pruner.Status()
+	require.Equal(t, genesis.Add(3), status.Pruned)
+	require.Equal(t, rst.Freed, status.TotalFreed)
+	require.Equal(t, &rst, status.Last)
+
+	for i := range txs {
+		// tortoise recovery replays blocks and ballots from the genesis
+		_, err := blocks.Get(db, blks[i].ID())
+		require.NoError(t, err)
+		_, err = ballots.Get(db, blts[i].ID())
+		require.NoError(t, err)
+
+		_, err = transactions.Get(db, txs[i].ID)
+		if blks[i].LayerIndex.Before(rst.Before) {
+			require.ErrorIs(t, err, sql.ErrNotFound)
+		} else {
+			require.NoError(t, err)
+		}
+	}
+
+	// nothing new to prune
+	verifier.EXPECT().LastVerified().Return(genesis.Add(8))
+	rst, err = pruner.Prune(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, PruningResult{}, rst)
+	require.Equal(t, genesis.Add(3), pruner.Status().Pruned)
+
+	compacted, err := pruner.Compact(context.Background())
+	require.NoError(t, err)
+	require.Positive(t, compacted.After)
+	require.Equal(t, compacted.Before-compacted.After, compacted.Reclaimed)
+	require.Equal(t, &compacted, pruner.Status().LastCompaction)
+	_, free, err := sql.Size(db)
+	require.NoError(t, err)
+	require.Zero(t, free)
+}
+
+func TestPrunerDisabled(t *testing.T) {
+	db := datastore.NewCachedDB(sql.InMemory(), logtest.New(t))
+	pruner := NewPruner(db, mocks.NewMocklayerVerifier(gomock.NewController(t)))
+	require.False(t, pruner.Status().Enabled)
+	_, err := pruner.Prune(context.Background())
+	require.ErrorIs(t, err, ErrPruningDisabled)
+	require.NoError(t, pruner.Run(context.Background()))
+}
@@ -0,0 +1,43 @@
+package mesh
+
+import (
+	"sync"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+)
+
+// Status is a consistent snapshot of the mesh progress.
+type Status struct {
+	// Latest is the latest layer this node had seen from blocks.
+	Latest types.LayerID
+	// Processed is the latest layer whose votes have been processed.
+	Processed types.LayerID
+	// Applied is the latest layer whose contents have been applied to the state.
+	Applied types.LayerID
+	// Verified is the latest layer applied with verified consensus results.
+	Verified types.LayerID
+}
+
+// status guards Status, so that all layers can be read under a single lock.
+type status struct {
+	mu    sync.RWMutex
+	value Status
+}
+
+func (s *status) get() Status {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.value
+}
+
+func (s *status) update(fn func(*Status)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(&s.value)
+}
+
+// MeshStatus returns latest, processed, applied and verified layers read under a single lock.
+// Use it instead of separate getters when several layers are compared.
+func (msh *Mesh) MeshStatus() Status {
+	return msh.status.get()
+}
@@ -0,0 +1,65 @@
+package mesh
+
+import (
+	"sync"
+	"time"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/mesh/metrics"
+)
+
+// VerificationStatus reports how far tortoise verification is behind processing.
+type VerificationStatus struct {
+	// Processed is the latest layer whose votes were counted.
+	Processed types.LayerID
+	// Verified is the latest layer verified by tortoise.
+	Verified types.LayerID
+	// Lag is the number of processed layers that are not yet verified.
+	Lag uint32
+	// Rate is the number of layers verified per second, measured between
+	// the last two times verified layer advanced.
+	Rate float64
+	// LastProgress is the time when verified layer advanced last time.
+	LastProgress time.Time
+}
+
+type verificationTracker struct {
+	mu     sync.Mutex
+	status VerificationStatus
+}
+
+func (t *verificationTracker) update(processed, verified types.LayerID, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if verified.After(t.status.Verified) {
+		if !t.status.LastProgress.IsZero() {
+			if elapsed := now.Sub(t.status.LastProgress); elapsed > 0 {
+				t.status.Rate = float64(verified.Difference(t.status.Verified)) / elapsed.Seconds()
+			}
+		}
+		t.status.Verified = verified
+		t.status.LastProgress = now
+	}
+	t.status.Processed = processed
+	t.status.Lag = 0
+	if processed.After(t.status.Verified) {
+		t.status.Lag = processed.Difference(t.status.Verified)
+	}
+
+	metrics.ProcessedLayer.Set(float64(processed))
+	metrics.VerifiedLayer.Set(float64(t.status.Verified))
+	metrics.VerificationLag.Set(float64(t.status.Lag))
+	metrics.VerificationRate.Set(t.status.Rate)
+}
+
+func (t *verificationTracker) get() VerificationStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.status
+}
+
+// VerificationStatus returns processed and verified layers, the lag between them
+// and the rate of verification.
+func (msh *Mesh) VerificationStatus() VerificationStatus {
+	return msh.verification.get()
+}
@@ -0,0 +1,35 @@
+package mesh
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+)
+
+func TestVerificationTracker(t *testing.T) {
+	var tracker verificationTracker
+	now := time.Now()
+
+	tracker.update(types.LayerID(10), types.LayerID(9), now)
+	status := tracker.get()
+	require.Equal(t, types.LayerID(10), status.Processed)
+	require.Equal(t, types.LayerID(9), status.Verified)
+	require.EqualValues(t, 1, status.Lag)
+	require.Zero(t, status.Rate)
+	require.Equal(t, now, status.LastProgress)
+
+	// verification is stuck
+	tracker.update(types.LayerID(14), types.LayerID(9), now.Add(10*time.Second))
+	status = tracker.get()
+	require.EqualValues(t, 5, status.Lag)
+	require.Equal(t, now, status.LastProgress)
+
+	tracker.update(types.LayerID(15), types.LayerID(14), now.Add(20*time.Second))
+	status = tracker.get()
+	require.EqualValues(t, 1, status.Lag)
+	require.Equal(t, 0.25, status.Rate)
+	require.Equal(t, now.Add(20*time.Second), status.LastProgress)
+}
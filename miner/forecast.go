@@ -0,0 +1,100 @@
+package miner
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/spacemeshos/economics/rewards"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/proposals"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/ballots"
+)
+
+// ErrNoEpochAtx is returned if the smesher is not eligible in the epoch, as it has no atx targeting it.
+var ErrNoEpochAtx = errors.New("no atx targeting the epoch")
+
+// EligibilityForecast is the expected participation of the smesher in the epoch.
+type EligibilityForecast struct {
+	Epoch       types.EpochID `json:"epoch"`
+	Atx         types.ATXID   `json:"atx"`
+	Weight      uint64        `json:"weight"`
+	TotalWeight uint64        `json:"total_weight"`
+	// Eligibilities is the number of ballots the smesher is eligible to publish in the epoch.
+	// each ballot is accompanied by a proposal, if the node has transactions to include.
+	Eligibilities uint32 `json:"eligibilities"`
+	// Used is the number of eligibilities used by the ballots that the smesher already published in the epoch.
+	Used uint32 `json:"used"`
+	// RewardMin, RewardExpected and RewardMax estimate the subsidy for all eligibilities in the epoch.
+	// the subsidy of a layer is split between its eligibilities, whose number varies around the layer size.
+	// the range assumes that a layer has layer size ± 2 * sqrt(layer size) eligibilities. fees are not included.
+	RewardMin      uint64 `json:"reward_min"`
+	RewardExpected uint64 `json:"reward_expected"`
+	RewardMax      uint64 `json:"reward_max"`
+}
+
+// Forecast returns the expected participation of the smesher in the epoch and the estimated rewards.
+// the number of eligibilities is taken from the reference ballot if the smesher already published it,
+// otherwise it is computed from the weight of all atxs targeting the epoch, the active set that the
+// smesher will select may be smaller.
+func (pb *ProposalBuilder) Forecast(epoch types.EpochID) (*EligibilityForecast, error) {
+	if epoch == 0 {
+		return nil, fmt.Errorf("%w: genesis epoch", ErrNoEpochAtx)
+	}
+	own, err := pb.cdb.GetEpochAtx(epoch-1, pb.cfg.nodeID)
+	if errors.Is(err, sql.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrNoEpochAtx, epoch)
+	} else if err != nil {
+		return nil, err
+	}
+	total, _, err := pb.cdb.GetEpochWeight(epoch)
+	if err != nil {
+		return nil, fmt.Errorf("epoch weight: %w", err)
+	}
+	forecast := &EligibilityForecast{
+		Epoch:       epoch,
+		Atx:         own.ID,
+		Weight:      own.GetWeight(),
+		TotalWeight: total,
+	}
+	published, err := ballots.BySmesher(pb.cdb, pb.cfg.nodeID, epoch)
+	if err != nil {
+		return nil, err
+	}
+	for _, ballot := range published {
+		if ballot.EpochData != nil {
+			forecast.Eligibilities = ballot.EpochData.EligibilityCount
+		}
+		forecast.Used += uint32(len(ballot.EligibilityProofs))
+	}
+	if forecast.Eligibilities == 0 {
+		forecast.Eligibilities, err = proposals.GetNumEligibleSlots(
+			forecast.Weight, pb.cfg.minActiveSetWeight, total, pb.cfg.layerSize, pb.cfg.layersPerEpoch)
+		if err != nil {
+			return nil, err
+		}
+	}
+	forecast.RewardMin, forecast.RewardExpected, forecast.RewardMax = estimateRewards(
+		epoch, forecast.Eligibilities, pb.cfg.layerSize, pb.cfg.layersPerEpoch)
+	return forecast, nil
+}
+
+func estimateRewards(epoch types.EpochID, eligibilities, layerSize, layersPerEpoch uint32) (uint64, uint64, uint64) {
+	if layerSize == 0 || layersPerEpoch == 0 {
+		return 0, 0, 0
+	}
+	var subsidy float64
+	for lid := epoch.FirstLayer(); lid < (epoch + 1).FirstLayer(); lid++ {
+		if lid.After(types.FirstEffectiveGenesis()) {
+			subsidy += float64(rewards.TotalSubsidyAtLayer(lid.Difference(types.FirstEffectiveGenesis())))
+		}
+	}
+	perLayer := subsidy / float64(layersPerEpoch) * float64(eligibilities)
+	deviation := 2 * math.Sqrt(float64(layerSize))
+	fewest := math.Max(1, float64(layerSize)-deviation)
+	return uint64(perLayer / (float64(layerSize) + deviation)),
+		uint64(perLayer / float64(layerSize)),
+		uint64(perLayer / fewest)
+}
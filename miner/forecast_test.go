@@ -0,0 +1,54 @@
+package miner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/signing"
+	"github.com/spacemeshos/go-spacemesh/sql/ballots"
+)
+
+func TestBuilder_Forecast(t *testing.T) {
+	b := createBuilder(t)
+	epoch := types.EpochID(3)
+
+	_, err := b.Forecast(epoch)
+	require.ErrorIs(t, err, ErrNoEpochAtx)
+
+	other, err := signing.NewEdSigner()
+	require.NoError(t, err)
+	own := genMinerATX(t, b.cdb, types.RandomATXID(), (epoch - 1).FirstLayer(), b.signer, time.Now())
+	genMinerATX(t, b.cdb, types.RandomATXID(), (epoch - 1).FirstLayer(), other, time.Now())
+
+	forecast, err := b.Forecast(epoch)
+	require.NoError(t, err)
+	require.Equal(t, own.ID(), forecast.Atx)
+	require.Equal(t, own.GetWeight(), forecast.Weight)
+	require.Equal(t, 2*own.GetWeight(), forecast.TotalWeight)
+	// half of the weight, layer size of 20 and 3 layers in the epoch
+	require.Equal(t, uint32(30), forecast.Eligibilities)
+	require.Zero(t, forecast.Used)
+	require.Positive(t, forecast.RewardExpected)
+	require.Less(t, forecast.RewardMin, forecast.RewardExpected)
+	require.Greater(t, forecast.RewardMax, forecast.RewardExpected)
+
+	// the number of eligibilities is taken from the published reference ballot
+	ee := &EpochEligibility{
+		Epoch: epoch,
+		Atx:   own.ID(),
+		Slots: 25,
+		Proofs: map[types.LayerID][]types.VotingEligibility{
+			epoch.FirstLayer(): genProofs(t, 2),
+		},
+	}
+	ref := genBallotWithEligibility(t, b.signer, types.RandomBeacon(), epoch.FirstLayer(), ee)
+	require.NoError(t, ballots.Add(b.cdb, ref))
+
+	forecast, err = b.Forecast(epoch)
+	require.NoError(t, err)
+	require.Equal(t, uint32(25), forecast.Eligibilities)
+	require.Equal(t, uint32(2), forecast.Used)
+}
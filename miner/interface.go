@@ -37,3 +37,7 @@ type layerClock interface {
 	CurrentLayer() types.LayerID
 	LayerToTime(types.LayerID) time.Time
 }
+
+type smeshingState interface {
+	Paused() bool
+}
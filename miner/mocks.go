@@ -298,3 +298,40 @@ func (mr *MocklayerClockMockRecorder) LayerToTime(arg0 interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LayerToTime", reflect.TypeOf((*MocklayerClock)(nil).LayerToTime), arg0)
 }
+
+// MocksmeshingState is a mock of smeshingState interface.
+type MocksmeshingState struct {
+	ctrl     *gomock.Controller
+	recorder *MocksmeshingStateMockRecorder
+}
+
+// MocksmeshingStateMockRecorder is the mock recorder for MocksmeshingState.
+type MocksmeshingStateMockRecorder struct {
+	mock *MocksmeshingState
+}
+
+// NewMocksmeshingState creates a new mock instance.
+func NewMocksmeshingState(ctrl *gomock.Controller) *MocksmeshingState {
+	mock := &MocksmeshingState{ctrl: ctrl}
+	mock.recorder = &MocksmeshingStateMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocksmeshingState) EXPECT() *MocksmeshingStateMockRecorder {
+	return m.recorder
+}
+
+// Paused mocks base method.
+func (m *MocksmeshingState) Paused() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Paused")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Paused indicates an expected call of Paused.
+func (mr *MocksmeshingStateMockRecorder) Paused() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Paused", reflect.TypeOf((*MocksmeshingState)(nil).Paused))
+}
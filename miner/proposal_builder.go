@@ -35,6 +35,7 @@ var (
 	errNotSynced      = errors.New("not building proposals: node not synced")
 	errNoBeacon       = errors.New("not building proposals: missing beacon")
 	errDuplicateLayer = errors.New("not building proposals: duplicate layer event")
+	errPaused         = errors.New("not building proposals: smeshing paused")
 )
 
 // ProposalBuilder builds Proposals for a miner.
@@ -57,6 +58,7 @@ type ProposalBuilder struct {
 	proposalOracle proposalOracle
 	beaconProvider system.BeaconGetter
 	syncer         system.SyncStateProvider
+	smeshing       smeshingState
 }
 
 // config defines configuration for the ProposalBuilder.
@@ -130,6 +132,13 @@ func WithNetworkDelay(delay time.Duration) Opt {
 	}
 }
 
+// WithSmeshingState defines the state of smeshing, proposals are not built while smeshing is paused.
+func WithSmeshingState(state smeshingState) Opt {
+	return func(pb *ProposalBuilder) {
+		pb.smeshing = state
+	}
+}
+
 func withOracle(o proposalOracle) Opt {
 	return func(pb *ProposalBuilder) {
 		pb.proposalOracle = o
@@ -361,6 +370,9 @@ func (pb *ProposalBuilder) handleLayer(ctx context.Context, layerID types.LayerI
 	if layerID <= types.GetEffectiveGenesis() {
 		return errGenesis
 	}
+	if pb.smeshing != nil && pb.smeshing.Paused() {
+		return errPaused
+	}
 	if !pb.syncer.IsSynced(ctx) {
 		return errNotSynced
 	}
@@ -461,7 +473,7 @@ func (pb *ProposalBuilder) createProposalLoop(ctx context.Context) {
 			}
 			next = current.Add(1)
 			lyrCtx := log.WithNewSessionID(ctx)
-			if err := pb.handleLayer(lyrCtx, current); err != nil && !errors.Is(err, errGenesis) {
+			if err := pb.handleLayer(lyrCtx, current); err != nil && !errors.Is(err, errGenesis) && !errors.Is(err, errPaused) {
 				pb.logger.WithContext(lyrCtx).With().Warning("failed to build proposal", current, log.Err(err))
 			}
 		}
@@ -298,6 +298,20 @@ func TestBuilder_HandleLayer_NotSynced(t *testing.T) {
 	require.ErrorIs(t, b.handleLayer(context.Background(), layerID), errNotSynced)
 }
 
+func TestBuilder_HandleLayer_Paused(t *testing.T) {
+	b := createBuilder(t)
+	smeshing := NewMocksmeshingState(gomock.NewController(t))
+	WithSmeshingState(smeshing)(b.ProposalBuilder)
+
+	layerID := types.LayerID(layersPerEpoch * 3)
+	smeshing.EXPECT().Paused().Return(true)
+	require.ErrorIs(t, b.handleLayer(context.Background(), layerID), errPaused)
+
+	smeshing.EXPECT().Paused().Return(false)
+	b.mSync.EXPECT().IsSynced(gomock.Any()).Return(false)
+	require.ErrorIs(t, b.handleLayer(context.Background(), layerID), errNotSynced)
+}
+
 func TestBuilder_HandleLayer_NoBeacon(t *testing.T) {
 	b := createBuilder(t)
 
@@ -4,6 +4,7 @@ package node
 import (
 	"context"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -12,6 +13,7 @@ import (
 	"os/signal"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -34,6 +36,7 @@ import (
 
 	"github.com/spacemeshos/go-spacemesh/activation"
 	"github.com/spacemeshos/go-spacemesh/api/grpcserver"
+	"github.com/spacemeshos/go-spacemesh/archive"
 	"github.com/spacemeshos/go-spacemesh/beacon"
 	"github.com/spacemeshos/go-spacemesh/blocks"
 	"github.com/spacemeshos/go-spacemesh/bootstrap"
@@ -63,8 +66,11 @@ import (
 	"github.com/spacemeshos/go-spacemesh/proposals"
 	"github.com/spacemeshos/go-spacemesh/signing"
 	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/certificates"
+	"github.com/spacemeshos/go-spacemesh/sql/hareparticipation"
 	"github.com/spacemeshos/go-spacemesh/sql/layers"
 	dbmetrics "github.com/spacemeshos/go-spacemesh/sql/metrics"
+	"github.com/spacemeshos/go-spacemesh/sql/syncstate"
 	"github.com/spacemeshos/go-spacemesh/syncer"
 	"github.com/spacemeshos/go-spacemesh/syncer/blockssync"
 	"github.com/spacemeshos/go-spacemesh/system"
@@ -79,6 +85,9 @@ const (
 	edKeyFileName   = "key.bin"
 	genesisFileName = "genesis.json"
 	dbFile          = "state.sql"
+
+	// hareSnapshotTimeout bounds the wait for a busy consensus process to report its state.
+	hareSnapshotTimeout = 10 * time.Second
 )
 
 // Logger names.
@@ -319,20 +328,28 @@ type App struct {
 	grpcPublicService  *grpcserver.Server
 	grpcPrivateService *grpcserver.Server
 	jsonAPIService     *grpcserver.JSONHTTPServer
+	jsonPrivateService *grpcserver.JSONHTTPServer
+	adminHandlers      map[string]http.HandlerFunc
+	grpcMetrics        *grpcserver.Metrics
+	health             *grpcserver.Health
 	syncer             *syncer.Syncer
+	backfiller         *syncer.Backfiller
 	proposalListener   *proposals.Handler
 	proposalBuilder    *miner.ProposalBuilder
 	mesh               *mesh.Mesh
+	pruner             *mesh.Pruner
 	cachedDB           *datastore.CachedDB
 	clock              *timesync.NodeClock
 	hare               *hare.Hare
 	hOracle            *eligibility.Oracle
 	blockGen           *blocks.Generator
 	certifier          *blocks.Certifier
+	blockHandler       *blocks.Handler
 	postSetupMgr       *activation.PostSetupManager
 	atxBuilder         *activation.Builder
 	atxHandler         *activation.Handler
 	txHandler          *txs.TxHandler
+	rebroadcaster      *txs.Rebroadcaster
 	validator          *activation.Validator
 	edVerifier         *signing.EdVerifier
 	beaconProtocol     *beacon.ProtocolDriver
@@ -364,6 +381,14 @@ func (app *App) LoadCheckpoint(ctx context.Context) (*checkpoint.PreservedData,
 	if restore == 0 {
 		return nil, fmt.Errorf("restore layer not set")
 	}
+	trusted := make([]types.NodeID, 0, len(app.Config.Recovery.TrustedSigners))
+	for _, signer := range app.Config.Recovery.TrustedSigners {
+		buf, err := hex.DecodeString(signer)
+		if err != nil || len(buf) != types.NodeIDSize {
+			return nil, fmt.Errorf("invalid trusted checkpoint signer %s", signer)
+		}
+		trusted = append(trusted, types.BytesToNodeID(buf))
+	}
 	cfg := &checkpoint.RecoverConfig{
 		GoldenAtx:      types.ATXID(app.Config.Genesis.GoldenATX()),
 		PostDataDir:    app.Config.SMESHING.Opts.DataDir,
@@ -373,6 +398,8 @@ func (app *App) LoadCheckpoint(ctx context.Context) (*checkpoint.PreservedData,
 		NodeID:         app.edSgn.NodeID(),
 		Uri:            checkpointFile,
 		Restore:        restore,
+		GenesisID:      app.Config.Genesis.GenesisID(),
+		TrustedSigners: trusted,
 	}
 	app.log.WithContext(ctx).With().Info("recover from checkpoint",
 		log.String("url", checkpointFile),
@@ -420,6 +447,9 @@ func (app *App) Unlock() {
 
 // Initialize parses and validates the node configuration and sets up logging.
 func (app *App) Initialize() error {
+	if err := app.Config.Genesis.LoadAccounts(); err != nil {
+		return err
+	}
 	gpath := filepath.Join(app.Config.DataDir(), genesisFileName)
 	var existing config.GenesisConfig
 	if err := existing.LoadFromFile(gpath); err != nil {
@@ -439,18 +469,42 @@ func (app *App) Initialize() error {
 		}
 	}
 
+	if err := app.Config.Genesis.ValidateLayers(app.Config.LayersPerEpoch); err != nil {
+		return err
+	}
+	if layer := app.Config.Genesis.EffectiveGenesis; layer != 0 {
+		types.SetEffectiveGenesis(layer)
+	}
+
+	if err := app.Config.HARE.Validate(); err != nil {
+		return fmt.Errorf("hare config: %w", err)
+	}
+	if err := app.Config.API.Validate(); err != nil {
+		return fmt.Errorf("api config: %w", err)
+	}
+	if retention := app.Config.StateRetention; retention != 0 && retention < app.Config.Tortoise.WindowSize {
+		return fmt.Errorf("state retention %d is shorter than the tortoise window %d",
+			retention, app.Config.Tortoise.WindowSize)
+	}
+	if err := vm.ValidateGasSchedules(app.Config.GasSchedules); err != nil {
+		return fmt.Errorf("vm config: %w", err)
+	}
+	if app.Config.HARE.Turbo && !app.Config.Standalone {
+		return errors.New("hare turbo mode is allowed only in standalone mode")
+	}
+
 	// tortoise wait zdist layers for hare to timeout for a layer. once hare timeout, tortoise will
 	// vote against all blocks in that layer. so it's important to make sure zdist takes longer than
 	// hare's max time duration to run consensus for a layer
 	maxHareRoundsPerLayer := 1 + app.Config.HARE.LimitIterations*hare.RoundsPerIteration // pre-round + 4 rounds per iteration
-	maxHareLayerDuration := app.Config.HARE.WakeupDelta + time.Duration(maxHareRoundsPerLayer)*app.Config.HARE.RoundDuration
+	maxHareLayerDuration := app.Config.HARE.WakeupDelta + time.Duration(maxHareRoundsPerLayer)*app.Config.HARE.MaxRoundDuration()
 	if app.Config.LayerDuration*time.Duration(app.Config.Tortoise.Zdist) <= maxHareLayerDuration {
 		app.log.With().Error("incompatible params",
 			log.Uint32("tortoise_zdist", app.Config.Tortoise.Zdist),
 			log.Duration("layer_duration", app.Config.LayerDuration),
 			log.Duration("hare_wakeup_delta", app.Config.HARE.WakeupDelta),
 			log.Int("hare_limit_iterations", app.Config.HARE.LimitIterations),
-			log.Duration("hare_round_duration", app.Config.HARE.RoundDuration))
+			log.Duration("hare_round_duration", app.Config.HARE.MaxRoundDuration()))
 
 		return errors.New("incompatible tortoise hare params")
 	}
@@ -563,13 +617,24 @@ func (app *App) initServices(ctx context.Context) error {
 	cfg := vm.DefaultConfig()
 	cfg.GasLimit = app.Config.BlockGasLimit
 	cfg.GenesisID = app.Config.Genesis.GenesisID()
+	cfg.StateRetention = app.Config.StateRetention
+	cfg.GasSchedules = app.Config.GasSchedules
+	if app.Config.ApplyWorkers != 0 {
+		cfg.ApplyWorkers = app.Config.ApplyWorkers
+	}
 	state := vm.New(app.db,
 		vm.WithConfig(cfg),
 		vm.WithLogger(app.addLogger(VMLogger, lg)))
 	app.conState = txs.NewConservativeState(state, app.db,
 		txs.WithCSConfig(txs.CSConfig{
-			BlockGasLimit:     app.Config.BlockGasLimit,
 			NumTXsPerProposal: app.Config.TxsPerProposal,
+			LayerSize:         int(app.Config.LayerAvgSize),
+			ReplaceFeeBump:    app.Config.ReplaceFeeBump,
+			MempoolMaxTXs:     app.Config.MempoolMaxTXs,
+			MempoolMaxAge:     app.Config.MempoolMaxAge,
+			MempoolMaxBytes:   app.Config.MempoolMaxBytes,
+			MempoolMaxAcctTXs: app.Config.MempoolMaxAcctTXs,
+			MempoolMaxOrphans: app.Config.MempoolMaxOrphans,
 		}),
 		txs.WithLogger(app.addLogger(ConStateLogger, lg)))
 
@@ -637,6 +702,28 @@ func (app *App) initServices(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to create mesh: %w", err)
 	}
+	if app.Config.Integrity.CheckOnStartup {
+		rst, err := mesh.CheckIntegrity(ctx, app.cachedDB, types.GetEffectiveGenesis().Add(1), msh.LatestLayer(), app.Config.Integrity.Repair)
+		if err != nil {
+			return fmt.Errorf("check mesh integrity: %w", err)
+		}
+		mesh.LogIntegrityReport(app.addLogger(MeshLogger, lg), rst)
+	}
+	if app.Config.Sync.Light && app.Config.SMESHING.Start {
+		return errors.New("smeshing is not supported in the light sync mode")
+	}
+	if retain := app.Config.Pruning.RetainLayers; retain != 0 && retain < trtlCfg.WindowSize {
+		return fmt.Errorf("pruning retention should not be smaller than tortoise window. prune-retain-layers: %d. tortoise-window-size: %d",
+			retain, trtlCfg.WindowSize)
+	}
+	pruner := mesh.NewPruner(app.cachedDB, msh,
+		mesh.WithPruningConfig(app.Config.Pruning),
+		mesh.WithPrunerLogger(app.addLogger(MeshLogger, lg)),
+		mesh.WithOnPruned(msh.OnPruned),
+	)
+	app.eg.Go(func() error {
+		return pruner.Run(ctx)
+	})
 
 	fetcherWrapped := &layerFetcher{}
 	atxHandler := activation.NewHandler(
@@ -654,6 +741,7 @@ func (app *App) initServices(ctx context.Context) error {
 		app.addLogger(ATXHandlerLogger, lg),
 		app.Config.POET,
 	)
+	atxHandler.Register(msh)
 
 	// we can't have an epoch offset which is greater/equal than the number of layers in an epoch
 
@@ -671,6 +759,7 @@ func (app *App) initServices(ctx context.Context) error {
 			MaxExceptions:          trtlCfg.MaxExceptions,
 			Hdist:                  trtlCfg.Hdist,
 			MinimalActiveSetWeight: trtlCfg.MinimalActiveSetWeight,
+			LateGrace:              app.Config.LateProposalGrace,
 		}),
 	)
 
@@ -682,8 +771,23 @@ func (app *App) initServices(ctx context.Context) error {
 		app.host.ID(),
 		app.addLogger(TxHandlerLogger, lg),
 	)
+	if app.Config.TxRebroadcastInterval > 0 {
+		app.rebroadcaster = txs.NewRebroadcaster(app.txHandler, app.host,
+			txs.RebroadcastConfig{
+				Interval:   app.Config.TxRebroadcastInterval,
+				MaxRetries: app.Config.TxRebroadcastRetries,
+			},
+			app.addLogger(TxHandlerLogger, lg),
+		)
+		app.eg.Go(func() error {
+			return app.rebroadcaster.Run(ctx, app.clock)
+		})
+	}
 
 	app.hOracle = eligibility.New(beaconProtocol, app.cachedDB, vrfVerifier, vrfSigner, app.Config.LayersPerEpoch, app.Config.HareEligibility, app.addLogger(HareOracleLogger, lg))
+	app.eg.Go(func() error {
+		return app.hOracle.Run(ctx, app.clock)
+	})
 	// TODO: genesisMinerWeight is set to app.Config.SpaceToCommit, because PoET ticks are currently hardcoded to 1
 
 	bscfg := app.Config.Bootstrap
@@ -699,10 +803,15 @@ func (app *App) initServices(ctx context.Context) error {
 		blocks.WithCertContext(ctx),
 		blocks.WithCertConfig(blocks.CertConfig{
 			CommitteeSize:    app.Config.HARE.N,
-			CertifyThreshold: app.Config.HARE.N/2 + 1,
+			CertifyThreshold: app.Config.HARE.CommitteeThreshold(),
 			LayerBuffer:      app.Config.Tortoise.Zdist,
 			NumLayersToKeep:  app.Config.Tortoise.Zdist * 2,
 		}),
+		// the committee changes at the same epochs as the hare committee
+		blocks.WithCommittee(func(epoch types.EpochID) (int, int) {
+			params := app.Config.HARE.EpochParams(epoch)
+			return params.N, params.Threshold
+		}),
 		blocks.WithCertifierLogger(app.addLogger(BlockCertLogger, lg)),
 	)
 
@@ -735,7 +844,6 @@ func (app *App) initServices(ctx context.Context) error {
 		blocks.WithConfig(blocks.Config{
 			LayerSize:          layerSize,
 			LayersPerEpoch:     layersPerEpoch,
-			BlockGasLimit:      app.Config.BlockGasLimit,
 			OptFilterThreshold: app.Config.OptFilterThreshold,
 			GenBlockInterval:   500 * time.Millisecond,
 		}),
@@ -761,26 +869,7 @@ func (app *App) initServices(ctx context.Context) error {
 		app.clock,
 		tortoiseWeakCoin{db: app.cachedDB, tortoise: trtl},
 		app.addLogger(HareLogger, lg),
-	)
-
-	proposalBuilder := miner.NewProposalBuilder(
-		ctx,
-		app.clock,
-		app.edSgn,
-		vrfSigner,
-		app.cachedDB,
-		app.host,
-		trtl,
-		beaconProtocol,
-		newSyncer,
-		app.conState,
-		miner.WithNodeID(app.edSgn.NodeID()),
-		miner.WithLayerSize(layerSize),
-		miner.WithLayerPerEpoch(layersPerEpoch),
-		miner.WithMinimalActiveSetWeight(app.Config.Tortoise.MinimalActiveSetWeight),
-		miner.WithHdist(app.Config.Tortoise.Hdist),
-		miner.WithNetworkDelay(app.Config.HARE.WakeupDelta),
-		miner.WithLogger(app.addLogger(ProposalBuilderLogger, lg)),
+		hare.WithPeerCounter(app.host),
 	)
 
 	postSetupMgr, err := activation.NewPostSetupManager(
@@ -844,6 +933,27 @@ func (app *App) initServices(ctx context.Context) error {
 		activation.WithValidator(app.validator),
 	)
 
+	proposalBuilder := miner.NewProposalBuilder(
+		ctx,
+		app.clock,
+		app.edSgn,
+		vrfSigner,
+		app.cachedDB,
+		app.host,
+		trtl,
+		beaconProtocol,
+		newSyncer,
+		app.conState,
+		miner.WithNodeID(app.edSgn.NodeID()),
+		miner.WithLayerSize(layerSize),
+		miner.WithLayerPerEpoch(layersPerEpoch),
+		miner.WithMinimalActiveSetWeight(app.Config.Tortoise.MinimalActiveSetWeight),
+		miner.WithHdist(app.Config.Tortoise.Hdist),
+		miner.WithNetworkDelay(app.Config.HARE.WakeupDelta),
+		miner.WithLogger(app.addLogger(ProposalBuilderLogger, lg)),
+		miner.WithSmeshingState(atxBuilder),
+	)
+
 	malfeasanceHandler := malfeasance.NewHandler(
 		app.cachedDB,
 		app.addLogger(MalfeasanceLogger, lg),
@@ -869,36 +979,80 @@ func (app *App) initServices(ctx context.Context) error {
 		}
 		return errors.New("not synced for gossip")
 	}
-	atxSyncHandler := func(_ context.Context, _ p2p.Peer, _ []byte) error {
-		if newSyncer.ListenToATXGossip() {
-			return nil
-		}
-		return errors.New("not synced for gossip")
-	}
 
 	app.host.Register(pubsub.BeaconWeakCoinProtocol, pubsub.ChainGossipHandler(syncHandler, beaconProtocol.HandleWeakCoinProposal))
 	app.host.Register(pubsub.BeaconProposalProtocol, pubsub.ChainGossipHandler(syncHandler, beaconProtocol.HandleProposal))
 	app.host.Register(pubsub.BeaconFirstVotesProtocol, pubsub.ChainGossipHandler(syncHandler, beaconProtocol.HandleFirstVotes))
 	app.host.Register(pubsub.BeaconFollowingVotesProtocol, pubsub.ChainGossipHandler(syncHandler, beaconProtocol.HandleFollowingVotes))
-	app.host.Register(pubsub.ProposalProtocol, pubsub.ChainGossipHandler(syncHandler, proposalListener.HandleProposal))
-	app.host.Register(pubsub.AtxProtocol, pubsub.ChainGossipHandler(atxSyncHandler, atxHandler.HandleGossipAtx))
-	app.host.Register(pubsub.TxProtocol, pubsub.ChainGossipHandler(syncHandler, app.txHandler.HandleGossipTransaction))
+	// data gossiped while the node is not synced is buffered and replayed after sync, hare and beacon
+	// messages are only relevant in the round they are gossiped in.
+	// light node doesn't have the state to validate proposals and transactions
+	if !app.Config.Sync.Light {
+		app.host.Register(pubsub.ProposalProtocol, newSyncer.GossipHandler(pubsub.ProposalProtocol, proposalListener.HandleProposal))
+		app.host.Register(pubsub.TxProtocol, newSyncer.GossipHandler(pubsub.TxProtocol, app.txHandler.HandleGossipTransaction))
+	}
+	app.host.Register(pubsub.AtxProtocol, newSyncer.ATXGossipHandler(pubsub.AtxProtocol, atxHandler.HandleGossipAtx))
 	app.host.Register(pubsub.HareProtocol, pubsub.ChainGossipHandler(syncHandler, app.hare.GetHareMsgHandler()))
-	app.host.Register(pubsub.BlockCertify, pubsub.ChainGossipHandler(syncHandler, app.certifier.HandleCertifyMessage))
-	app.host.Register(pubsub.MalfeasanceProof, pubsub.ChainGossipHandler(atxSyncHandler, malfeasanceHandler.HandleMalfeasanceProof))
+	app.host.Register(pubsub.HareBatchProtocol, pubsub.ChainGossipHandler(syncHandler, app.hare.GetHareBatchHandler()))
+	app.host.Register(pubsub.BlockCertify, newSyncer.GossipHandler(pubsub.BlockCertify, app.certifier.HandleCertifyMessage))
+	app.host.Register(pubsub.MalfeasanceProof, newSyncer.ATXGossipHandler(pubsub.MalfeasanceProof, malfeasanceHandler.HandleMalfeasanceProof))
 
 	app.proposalBuilder = proposalBuilder
 	app.proposalListener = proposalListener
 	app.mesh = msh
+	app.pruner = pruner
 	app.syncer = newSyncer
+	app.backfiller = syncer.NewBackfiller(app.db, fetcher, app.addLogger(SyncLogger, lg))
 	app.svm = state
 	app.atxBuilder = atxBuilder
 	app.postSetupMgr = postSetupMgr
 	app.atxHandler = atxHandler
+	app.blockHandler = blockHandler
 	app.poetDb = poetDb
 	app.fetcher = fetcher
 	app.beaconProtocol = beaconProtocol
 	app.tortoise = trtl
+	app.health = grpcserver.NewHealth(app.Config.API, app.db, msh, app.clock, app.host, app.addLogger(GRPCLogger, lg).WithName("Health"))
+	if app.Config.PprofHTTPServer {
+		http.HandleFunc("/debug/tortoise/explain", app.explainVote)
+		http.HandleFunc("/debug/tortoise/rerun", app.rerunStatus)
+		http.HandleFunc("/debug/hare/results", app.hareResults)
+		http.HandleFunc("/debug/hare/activeset", app.hareActiveSet)
+		http.HandleFunc("/debug/hare/participation", app.hareParticipation)
+		http.HandleFunc("/debug/syncer/state", app.syncerState)
+	}
+	if app.Config.API.IsPrivate(grpcserver.Admin) {
+		// control the node and expose internals of the running consensus, so they are served
+		// only by the private json gateway that requires the auth token
+		introspection := grpcserver.NewIntrospection()
+		introspection.RegisterQueue("fetch_unprocessed", func() int {
+			unprocessed, _ := fetcher.QueueDepths()
+			return unprocessed
+		})
+		introspection.RegisterQueue("fetch_ongoing", func() int {
+			_, ongoing := fetcher.QueueDepths()
+			return ongoing
+		})
+		introspection.RegisterQueue("mempool", func() int {
+			return app.conState.MempoolStats().Transactions
+		})
+		app.adminHandlers = map[string]http.HandlerFunc{
+			"/debug/mesh/compact":       app.compactMesh,
+			"/debug/mesh/check":         app.checkMesh,
+			"/debug/hare/instance":      app.hareInstance,
+			"/debug/fetch/bandwidth":    app.fetchBandwidth,
+			"/debug/syncer/backfill":    app.backfillLayer,
+			"/debug/admin/shutdown":     app.shutdown,
+			"/debug/admin/prune":        app.pruneMesh,
+			"/debug/admin/checkpoint":   app.createCheckpoint,
+			"/debug/smesher/forecast":   app.smesherForecast,
+			"/debug/runtime/goroutines": introspection.Goroutines,
+			"/debug/runtime/heap":       introspection.Heap,
+			"/debug/runtime/gc":         introspection.GC,
+			"/debug/runtime/queues":     introspection.Queues,
+			"/debug/network/topology":   grpcserver.NewTopology(app.host).Topology,
+		}
+	}
 	if !app.Config.TIME.Peersync.Disable {
 		app.ptimesync = peersync.New(
 			app.host,
@@ -913,6 +1067,27 @@ func (app *App) initServices(ctx context.Context) error {
 	return nil
 }
 
+// applyInitialBeacon sets the beacon for the first epoch after genesis from the genesis config,
+// unless the beacon for that epoch is already known.
+func (app *App) applyInitialBeacon() error {
+	beacon, err := app.Config.Genesis.Beacon()
+	if err != nil {
+		return err
+	}
+	if beacon == types.EmptyBeacon {
+		return nil
+	}
+	epoch := types.GetEffectiveGenesis().GetEpoch() + 1
+	if _, err := app.beaconProtocol.GetBeacon(epoch); err == nil {
+		return nil
+	}
+	app.log.With().Info("using initial beacon from genesis config", epoch, beacon)
+	if err := app.beaconProtocol.UpdateBeacon(epoch, beacon); err != nil {
+		return fmt.Errorf("update initial beacon: %w", err)
+	}
+	return nil
+}
+
 func (app *App) launchStandalone(ctx context.Context) error {
 	if !app.Config.Standalone {
 		return nil
@@ -990,6 +1165,407 @@ func (app *App) listenToUpdates(ctx context.Context) {
 	})
 }
 
+// explainVote writes how tortoise counts votes for the block, identified
+// by the base64 encoded id in the block query parameter.
+func (app *App) explainVote(w http.ResponseWriter, r *http.Request) {
+	var id types.BlockID
+	if err := id.UnmarshalText([]byte(r.URL.Query().Get("block"))); err != nil {
+		http.Error(w, fmt.Sprintf("invalid block id: %v", err), http.StatusBadRequest)
+		return
+	}
+	explained, err := app.tortoise.ExplainVote(id)
+	if errors.Is(err, tortoise.ErrUnknownBlock) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(explained); err != nil {
+		app.log.With().Warning("failed to write vote explanation", log.Err(err))
+	}
+}
+
+// rerunStatus writes progress of the background tortoise rerun.
+func (app *App) rerunStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(app.tortoise.RerunStatus()); err != nil {
+		app.log.With().Warning("failed to write rerun status", log.Err(err))
+	}
+}
+
+// checkMesh runs the mesh integrity check and writes the report.
+// Layers are limited by the optional from and to query parameters, dangling
+// index entries are deleted if repair=true, which requires POST.
+func (app *App) checkMesh(w http.ResponseWriter, r *http.Request) {
+	repair := r.URL.Query().Get("repair") == "true"
+	if repair && r.Method != http.MethodPost {
+		http.Error(w, "repair requires POST", http.StatusMethodNotAllowed)
+		return
+	}
+	from := types.GetEffectiveGenesis().Add(1)
+	to := app.mesh.LatestLayer()
+	for _, param := range []struct {
+		name string
+		lid  *types.LayerID
+	}{{"from", &from}, {"to", &to}} {
+		value := r.URL.Query().Get(param.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid %s: %v", param.name, err), http.StatusBadRequest)
+			return
+		}
+		*param.lid = types.LayerID(parsed)
+	}
+	rst, err := mesh.CheckIntegrity(r.Context(), app.cachedDB, from, to, repair)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	mesh.LogIntegrityReport(app.log, rst)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(rst); err != nil {
+		app.log.With().Warning("failed to write mesh integrity report", log.Err(err))
+	}
+}
+
+// hareLayerResult is the hare result of a layer compared with the block applied
+// according to the tortoise opinion.
+type hareLayerResult struct {
+	hare.LayerResult
+	HareOutput *types.BlockID `json:"hare_output,omitempty"`
+	Applied    *types.BlockID `json:"applied,omitempty"`
+	// Diverged is true if the layer was applied with a block other than the hare output.
+	Diverged bool `json:"diverged"`
+}
+
+// hareResults writes the hare results of the recent layers.
+func (app *App) hareResults(w http.ResponseWriter, r *http.Request) {
+	var rst []hareLayerResult
+	for _, result := range app.hare.Results() {
+		lr := hareLayerResult{LayerResult: result}
+		for _, lookup := range []struct {
+			get func(sql.Executor, types.LayerID) (types.BlockID, error)
+			bid **types.BlockID
+		}{{certificates.GetHareOutput, &lr.HareOutput}, {layers.GetApplied, &lr.Applied}} {
+			bid, err := lookup.get(app.db, result.Layer)
+			if errors.Is(err, sql.ErrNotFound) {
+				continue
+			} else if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			*lookup.bid = &bid
+		}
+		lr.Diverged = lr.HareOutput != nil && lr.Applied != nil && *lr.HareOutput != *lr.Applied
+		rst = append(rst, lr)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(rst); err != nil {
+		app.log.With().Warning("failed to write hare results", log.Err(err))
+	}
+}
+
+// hareActiveSet writes the size and the weight of the active set that the hare oracle
+// uses in the layer query parameter, or in the current layer if it is not set.
+func (app *App) hareActiveSet(w http.ResponseWriter, r *http.Request) {
+	lid := app.clock.CurrentLayer()
+	if value := r.URL.Query().Get("layer"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid layer: %v", err), http.StatusBadRequest)
+			return
+		}
+		lid = types.LayerID(parsed)
+	}
+	info, err := app.hOracle.ActiveSetInfo(r.Context(), lid)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(info); err != nil {
+		app.log.With().Warning("failed to write hare active set", log.Err(err))
+	}
+}
+
+// fetchBandwidth writes the bandwidth limits of the fetch protocols in bytes per second.
+// POST request updates the limits that are set in the download and upload query parameters.
+func (app *App) fetchBandwidth(w http.ResponseWriter, r *http.Request) {
+	download, upload := app.fetcher.BandwidthLimits()
+	if r.Method == http.MethodPost {
+		for _, param := range []struct {
+			name  string
+			value *int
+		}{
+			{"download", &download},
+			{"upload", &upload},
+		} {
+			value := r.URL.Query().Get(param.name)
+			if value == "" {
+				continue
+			}
+			parsed, err := strconv.ParseUint(value, 10, 31)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid %s limit: %v", param.name, err), http.StatusBadRequest)
+				return
+			}
+			*param.value = int(parsed)
+		}
+		app.fetcher.SetBandwidthLimits(download, upload)
+		app.log.With().Info("updated fetch bandwidth limits",
+			log.Int("download", download),
+			log.Int("upload", upload),
+		)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]int{
+		"download": download,
+		"upload":   upload,
+	}); err != nil {
+		app.log.With().Warning("failed to write fetch bandwidth", log.Err(err))
+	}
+}
+
+// hareInstance writes the live state of the hare consensus process running for the layer
+// query parameter, or for the current layer if it is not set.
+func (app *App) hareInstance(w http.ResponseWriter, r *http.Request) {
+	lid := app.clock.CurrentLayer()
+	if value := r.URL.Query().Get("layer"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid layer: %v", err), http.StatusBadRequest)
+			return
+		}
+		lid = types.LayerID(parsed)
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), hareSnapshotTimeout)
+	defer cancel()
+	snapshot, err := app.hare.Snapshot(ctx, lid)
+	if errors.Is(err, hare.ErrNotRunning) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(snapshot); err != nil {
+		app.log.With().Warning("failed to write hare instance", log.Err(err))
+	}
+}
+
+// hareParticipationRecord is a hare participation record with the hex encoded identity.
+type hareParticipationRecord struct {
+	hareparticipation.Record
+	NodeID string `json:"node_id"`
+}
+
+// syncerState writes the progress of the syncer persisted across restarts and the number of ATXs
+// by epoch that the syncer didn't finish fetching.
+func (app *App) syncerState(w http.ResponseWriter, r *http.Request) {
+	st, outstanding, err := app.syncer.PersistedState()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(struct {
+		syncstate.State
+		OutstandingAtxs map[types.EpochID]int `json:"outstanding_atxs"`
+	}{st, outstanding}); err != nil {
+		app.log.With().Warning("failed to write syncer state", log.Err(err))
+	}
+}
+
+// backfillLayer fetches the layer below the checkpoint set in the layer query parameter.
+func (app *App) backfillLayer(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "backfill requires POST", http.StatusMethodNotAllowed)
+		return
+	}
+	parsed, err := strconv.ParseUint(r.URL.Query().Get("layer"), 10, 32)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid layer: %v", err), http.StatusBadRequest)
+		return
+	}
+	if err := app.backfiller.BackfillLayer(r.Context(), types.LayerID(parsed)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// hareParticipation writes eligibility and participation of the node identity in hare rounds
+// for layers between the from and to query parameters. by default it covers the current epoch.
+func (app *App) hareParticipation(w http.ResponseWriter, r *http.Request) {
+	current := app.clock.CurrentLayer()
+	from, to := current.GetEpoch().FirstLayer(), current
+	for _, param := range []struct {
+		name string
+		lid  *types.LayerID
+	}{{"from", &from}, {"to", &to}} {
+		value := r.URL.Query().Get(param.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid %s layer: %v", param.name, err), http.StatusBadRequest)
+			return
+		}
+		*param.lid = types.LayerID(parsed)
+	}
+	records, err := hareparticipation.List(app.db, from, to)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rst := make([]hareParticipationRecord, 0, len(records))
+	for _, rec := range records {
+		rst = append(rst, hareParticipationRecord{Record: rec, NodeID: rec.NodeID.String()})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(rst); err != nil {
+		app.log.With().Warning("failed to write hare participation", log.Err(err))
+	}
+}
+
+// compactMesh compacts the database and reports the number of reclaimed bytes.
+func (app *App) compactMesh(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "compaction requires POST", http.StatusMethodNotAllowed)
+		return
+	}
+	rst, err := app.pruner.Compact(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(rst); err != nil {
+		app.log.With().Warning("failed to write compaction result", log.Err(err))
+	}
+}
+
+// smesherForecast writes the expected eligibilities and rewards of the node identity in the epoch
+// from the epoch query parameter, by default in the current epoch.
+func (app *App) smesherForecast(w http.ResponseWriter, r *http.Request) {
+	epoch := app.clock.CurrentLayer().GetEpoch()
+	if value := r.URL.Query().Get("epoch"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid epoch: %v", err), http.StatusBadRequest)
+			return
+		}
+		epoch = types.EpochID(parsed)
+	}
+	forecast, err := app.proposalBuilder.Forecast(epoch)
+	switch {
+	case errors.Is(err, miner.ErrNoEpochAtx):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(forecast); err != nil {
+		app.log.With().Warning("failed to write smesher forecast", log.Err(err))
+	}
+}
+
+// shutdown gracefully stops the node, as if it received the termination signal.
+func (app *App) shutdown(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "shutdown requires POST", http.StatusMethodNotAllowed)
+		return
+	}
+	app.log.Info("shutdown requested over admin endpoint")
+	w.WriteHeader(http.StatusAccepted)
+	select {
+	case app.errCh <- nil:
+	default:
+		// the node is already shutting down
+	}
+}
+
+// pruneMesh runs a single mesh pruning pass and writes its result as json.
+func (app *App) pruneMesh(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "pruning requires POST", http.StatusMethodNotAllowed)
+		return
+	}
+	rst, err := app.pruner.Prune(r.Context())
+	switch {
+	case errors.Is(err, mesh.ErrPruningDisabled):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(rst); err != nil {
+		app.log.With().Warning("failed to write pruning result", log.Err(err))
+	}
+}
+
+// createCheckpoint generates the checkpoint of the state at the snapshot layer in the data directory,
+// and writes the path of the checkpoint file as json. use CheckpointStream to download the checkpoint.
+func (app *App) createCheckpoint(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "checkpoint requires POST", http.StatusMethodNotAllowed)
+		return
+	}
+	snapshot, err := strconv.ParseUint(r.URL.Query().Get("layer"), 10, 32)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid layer: %v", err), http.StatusBadRequest)
+		return
+	}
+	numAtxs := 4
+	if value := r.URL.Query().Get("atxs"); value != "" {
+		if numAtxs, err = strconv.Atoi(value); err != nil || numAtxs < 1 {
+			http.Error(w, fmt.Sprintf("invalid number of atxs: %q", value), http.StatusBadRequest)
+			return
+		}
+	}
+	lid := types.LayerID(snapshot)
+	if err := checkpoint.Generate(r.Context(), afero.NewOsFs(), app.db, app.Config.DataDir(), lid, numAtxs, app.edSgn); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]string{
+		"file": checkpoint.SelfCheckpointFilename(app.Config.DataDir(), lid),
+	}); err != nil {
+		app.log.With().Warning("failed to write checkpoint result", log.Err(err))
+	}
+}
+
+// rerunTortoise periodically recomputes tortoise state from the database in the background.
+func (app *App) rerunTortoise(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := app.tortoise.Rerun(ctx, app.cachedDB, app.beaconProtocol, app.pruner.Status().Pruned); err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				app.log.With().Error("tortoise rerun failed", log.Err(err))
+			}
+		}
+	}
+}
+
 func (app *App) startServices(ctx context.Context) error {
 	if err := app.fetcher.Start(); err != nil {
 		return fmt.Errorf("failed to start fetcher: %w", err)
@@ -999,7 +1575,23 @@ func (app *App) startServices(ctx context.Context) error {
 		return nil
 	})
 	app.syncer.Start()
+	app.eg.Go(func() error {
+		return app.health.Run(ctx)
+	})
+	if app.Config.Sync.Backfill && types.GetEffectiveGenesis() != types.FirstEffectiveGenesis() {
+		app.eg.Go(func() error {
+			if err := app.backfiller.Run(ctx, app.Config.Sync.BackfillInterval); err != nil {
+				app.log.With().Error("stopped backfilling layers below the checkpoint", log.Err(err))
+			}
+			return nil
+		})
+	}
 	app.beaconProtocol.Start(ctx)
+	if interval := app.Config.Tortoise.RerunInterval; interval != 0 {
+		app.eg.Go(func() error {
+			return app.rerunTortoise(ctx, interval)
+		})
+	}
 
 	app.blockGen.Start()
 	app.certifier.Start()
@@ -1042,12 +1634,25 @@ func (app *App) initService(ctx context.Context, svc grpcserver.Service) (grpcse
 	case grpcserver.Mesh:
 		return grpcserver.NewMeshService(app.cachedDB, app.mesh, app.conState, app.clock, app.Config.LayersPerEpoch, app.Config.Genesis.GenesisID(), app.Config.LayerDuration, app.Config.LayerAvgSize, uint32(app.Config.TxsPerProposal), logger.WithName("Mesh")), nil
 	case grpcserver.Node:
-		return grpcserver.NewNodeService(app.host, app.mesh, app.clock, app.syncer, cmd.Version, cmd.Commit, logger.WithName("Node")), nil
+		info := grpcserver.NodeInfo{
+			Version:          cmd.Version,
+			Commit:           cmd.Commit,
+			BuildTime:        cmd.BuildTime,
+			NetworkID:        app.Config.Genesis.GenesisID().String(),
+			GenesisHash:      app.Config.Genesis.GoldenATX().String(),
+			GenesisTime:      app.Config.Genesis.GenesisTime,
+			MinPeerVersion:   app.Config.API.MinPeerVersion,
+			MinClientVersion: app.Config.API.MinClientVersion,
+		}
+		return grpcserver.NewNodeService(app.host, app.mesh, app.clock, app.syncer, app.hare, info, logger.WithName("Node")), nil
 	case grpcserver.Admin:
-		return grpcserver.NewAdminService(app.db, app.Config.DataDir(), logger.WithName("Admin")), nil
+		return grpcserver.NewAdminService(app.db, app.Config.DataDir(), app.edSgn, logger.WithName("Admin")), nil
 	case grpcserver.Smesher:
 		return grpcserver.NewSmesherService(app.postSetupMgr, app.atxBuilder, app.Config.API.SmesherStreamInterval, app.Config.SMESHING.Opts, logger.WithName("Smesher")), nil
 	case grpcserver.Transaction:
+		if app.rebroadcaster != nil {
+			return grpcserver.NewTransactionService(app.db, app.host, app.mesh, app.conState, app.syncer, app.rebroadcaster, logger.WithName("Transaction")), nil
+		}
 		return grpcserver.NewTransactionService(app.db, app.host, app.mesh, app.conState, app.syncer, app.txHandler, logger.WithName("Transaction")), nil
 	case grpcserver.Activation:
 		return grpcserver.NewActivationService(app.cachedDB, types.ATXID(app.Config.Genesis.GoldenATX()), logger.WithName("Activation")), nil
@@ -1055,27 +1660,61 @@ func (app *App) initService(ctx context.Context, svc grpcserver.Service) (grpcse
 	return nil, fmt.Errorf("unknown service %s", svc)
 }
 
-func (app *App) newGrpc(logger log.Log, endpoint string) *grpcserver.Server {
-	return grpcserver.New(endpoint, logger,
-		grpc.ChainStreamInterceptor(grpctags.StreamServerInterceptor(), grpczap.StreamServerInterceptor(logger.Zap())),
-		grpc.ChainUnaryInterceptor(grpctags.UnaryServerInterceptor(), grpczap.UnaryServerInterceptor(logger.Zap())),
+func (app *App) newGrpc(logger log.Log, endpoint string, opts ...grpc.ServerOption) *grpcserver.Server {
+	opts = append([]grpc.ServerOption{
+		grpc.ChainStreamInterceptor(
+			grpctags.StreamServerInterceptor(),
+			grpczap.StreamServerInterceptor(logger.Zap()),
+			app.grpcMetrics.StreamInterceptor,
+			grpcserver.StreamErrorCodes,
+		),
+		grpc.ChainUnaryInterceptor(
+			grpctags.UnaryServerInterceptor(),
+			grpczap.UnaryServerInterceptor(logger.Zap()),
+			app.grpcMetrics.UnaryInterceptor,
+			grpcserver.UnaryErrorCodes,
+		),
 		grpc.MaxSendMsgSize(app.Config.API.GrpcSendMsgSize),
 		grpc.MaxRecvMsgSize(app.Config.API.GrpcRecvMsgSize),
-	)
+	}, opts...)
+	if streams := app.Config.API.MaxConcurrentStreams; streams > 0 {
+		opts = append(opts, grpc.MaxConcurrentStreams(streams))
+	}
+	if limiter := grpcserver.NewRateLimiter(app.Config.API); limiter != nil {
+		opts = append(opts, limiter.ServerOptions()...)
+	}
+	server := grpcserver.New(endpoint, logger, opts...)
+	if app.Config.API.Reflection {
+		server.EnableReflection()
+	}
+	return server
 }
 
 func (app *App) startAPIServices(ctx context.Context) error {
 	logger := app.addLogger(GRPCLogger, app.log)
 	grpczap.SetGrpcLoggerV2(grpclog, logger.Zap())
+	app.grpcMetrics = grpcserver.NewMetrics(app.Config.API, logger.WithName("metrics"))
+	if err := grpcserver.SetCompressionLevel(app.Config.API); err != nil {
+		return err
+	}
+	jsonOpts := []grpcserver.JSONOpt{
+		grpcserver.WithCompressionLevel(app.Config.API.CompressionLevel),
+		grpcserver.WithMaxRequestSize(app.Config.API.GrpcRecvMsgSize),
+	}
 	var (
-		unique = map[grpcserver.Service]struct{}{}
-		public []grpcserver.ServiceAPI
+		unique  = map[grpcserver.Service]struct{}{}
+		public  []grpcserver.ServiceAPI
+		private []grpcserver.ServiceAPI
 	)
 	if len(app.Config.API.PublicServices) > 0 {
 		app.grpcPublicService = app.newGrpc(logger, app.Config.API.PublicListener)
 	}
 	if len(app.Config.API.PrivateServices) > 0 {
-		app.grpcPrivateService = app.newGrpc(logger, app.Config.API.PrivateListener)
+		auth, err := grpcserver.AuthServerOptions(app.Config.API)
+		if err != nil {
+			return fmt.Errorf("private grpc auth: %w", err)
+		}
+		app.grpcPrivateService = app.newGrpc(logger, app.Config.API.PrivateListener, auth...)
 	}
 	for _, svc := range app.Config.API.PublicServices {
 		if _, exists := unique[svc]; exists {
@@ -1098,15 +1737,46 @@ func (app *App) startAPIServices(ctx context.Context) error {
 			return err
 		}
 		gsvc.RegisterService(app.grpcPrivateService)
+		private = append(private, gsvc)
 		unique[svc] = struct{}{}
 	}
+	if app.health != nil {
+		for _, server := range []*grpcserver.Server{app.grpcPublicService, app.grpcPrivateService} {
+			if server != nil {
+				app.health.RegisterService(server)
+			}
+		}
+		if len(app.Config.API.HealthListener) > 0 {
+			app.health.Start(app.Config.API.HealthListener)
+		}
+	}
 	if len(app.Config.API.JSONListener) > 0 {
 		if len(public) == 0 {
 			return fmt.Errorf("can't start json server without public services")
 		}
-		app.jsonAPIService = grpcserver.NewJSONHTTPServer(app.Config.API.JSONListener, logger.WithName("JSON"))
+		opts := append(jsonOpts, grpcserver.WithWebsocketOrigins(app.Config.API.WebsocketOrigins))
+		if limiter := grpcserver.NewRateLimiter(app.Config.API); limiter != nil {
+			opts = append(opts, grpcserver.WithRateLimiter(limiter))
+		}
+		app.jsonAPIService = grpcserver.NewJSONHTTPServer(app.Config.API.JSONListener, logger.WithName("JSON"), opts...)
 		app.jsonAPIService.StartService(ctx, public...)
 	}
+	if len(app.Config.API.PrivateJSONListener) > 0 {
+		if len(private) == 0 {
+			return fmt.Errorf("can't start private json server without private services")
+		}
+		opts, err := grpcserver.AuthJSONOptions(app.Config.API)
+		if err != nil {
+			return fmt.Errorf("private json auth: %w", err)
+		}
+		opts = append(opts, grpcserver.WithAdminHandlers(app.adminHandlers))
+		if limiter := grpcserver.NewRateLimiter(app.Config.API); limiter != nil {
+			opts = append(opts, grpcserver.WithRateLimiter(limiter))
+		}
+		app.jsonPrivateService = grpcserver.NewJSONHTTPServer(app.Config.API.PrivateJSONListener,
+			logger.WithName("PrivateJSON"), append(jsonOpts, opts...)...)
+		app.jsonPrivateService.StartService(ctx, private...)
+	}
 	if app.grpcPublicService != nil {
 		app.grpcPublicService.Start()
 	}
@@ -1122,6 +1792,16 @@ func (app *App) stopServices(ctx context.Context) {
 			app.log.With().Error("error stopping json gateway server", log.Err(err))
 		}
 	}
+	if app.jsonPrivateService != nil {
+		if err := app.jsonPrivateService.Shutdown(ctx); err != nil {
+			app.log.With().Error("error stopping private json gateway server", log.Err(err))
+		}
+	}
+	if app.health != nil {
+		if err := app.health.Shutdown(ctx); err != nil {
+			app.log.With().Error("error stopping health http server", log.Err(err))
+		}
+	}
 
 	if app.grpcPublicService != nil {
 		app.log.Info("stopping public grpc service")
@@ -1270,6 +1950,7 @@ func (app *App) setupDBs(ctx context.Context, lg log.Log, dbPath string) error {
 	sqlDB, err := sql.Open("file:"+filepath.Join(dbPath, dbFile),
 		sql.WithConnections(app.Config.DatabaseConnections),
 		sql.WithLatencyMetering(app.Config.DatabaseLatencyMetering),
+		sql.WithLogger(app.addLogger(StateDbLogger, lg)),
 	)
 	if err != nil {
 		return fmt.Errorf("open sqlite db %w", err)
@@ -1395,11 +2076,17 @@ func (app *App) Start(ctx context.Context) error {
 
 	// need post verifying service to start first
 	app.preserveAfterRecovery(ctx)
+	if err := app.importArchive(ctx); err != nil {
+		return err
+	}
 
 	if err := app.startAPIServices(ctx); err != nil {
 		return err
 	}
 
+	if err := app.applyInitialBeacon(); err != nil {
+		return err
+	}
 	if err := app.launchStandalone(ctx); err != nil {
 		return err
 	}
@@ -1482,6 +2169,41 @@ func (app *App) preserveAfterRecovery(ctx context.Context) {
 	}
 }
 
+// importArchive validates and stores the mesh data from the archive, and processes the imported layers.
+func (app *App) importArchive(ctx context.Context) error {
+	if app.Config.ImportArchive == "" {
+		return nil
+	}
+	app.log.With().Info("importing archive", log.String("path", app.Config.ImportArchive))
+	last, err := archive.Import(ctx, app.log, app.Config.ImportArchive, archive.Validators{
+		Poet: func(ctx context.Context, hash types.Hash32, peer p2p.Peer, data []byte) error {
+			err := app.poetDb.ValidateAndStoreMsg(ctx, hash, peer, data)
+			if errors.Is(err, activation.ErrObjectExists) {
+				return nil
+			}
+			return err
+		},
+		Atx:         app.atxHandler.HandleSyncedAtx,
+		Tx:          app.txHandler.HandleBlockTransaction,
+		Ballot:      app.proposalListener.HandleSyncedBallot,
+		Block:       app.blockHandler.HandleSyncedBlock,
+		Certificate: app.certifier.HandleSyncedCertificate,
+	})
+	if err != nil {
+		return fmt.Errorf("import archive %s: %w", app.Config.ImportArchive, err)
+	}
+	for lid := app.mesh.ProcessedLayer().Add(1); !lid.After(last); lid = lid.Add(1) {
+		if err := app.mesh.ProcessLayer(ctx, lid); err != nil {
+			return fmt.Errorf("process imported layer %s: %w", lid, err)
+		}
+	}
+	app.log.With().Info("imported archive",
+		log.String("path", app.Config.ImportArchive),
+		log.Stringer("processed", app.mesh.ProcessedLayer()),
+	)
+	return nil
+}
+
 func (app *App) Host() *p2p.Host {
 	return app.host
 }
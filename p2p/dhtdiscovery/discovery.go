@@ -208,6 +208,14 @@ func (d *Discovery) Start() {
 	})
 }
 
+// RoutingTable returns the peers in the dht routing table, it is empty if the dht is disabled.
+func (d *Discovery) RoutingTable() []peer.ID {
+	if d.dht == nil {
+		return nil
+	}
+	return d.dht.RoutingTable().ListPeers()
+}
+
 func (d *Discovery) Stop() {
 	d.cancel()
 	d.eg.Wait()
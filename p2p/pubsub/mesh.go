@@ -0,0 +1,105 @@
+package pubsub
+
+import (
+	"sync"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+)
+
+// meshTracer tracks the peers in the gossipsub mesh of every joined topic.
+// the mesh isn't exposed by the gossipsub router, so it is rebuilt from the graft and prune events.
+type meshTracer struct {
+	mu   sync.Mutex
+	mesh map[string]map[peer.ID]struct{}
+}
+
+func newMeshTracer() *meshTracer {
+	return &meshTracer{mesh: map[string]map[peer.ID]struct{}{}}
+}
+
+// peers returns the mesh peers of the topic.
+func (t *meshTracer) peers(topic string) []peer.ID {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	rst := make([]peer.ID, 0, len(t.mesh[topic]))
+	for id := range t.mesh[topic] {
+		rst = append(rst, id)
+	}
+	return rst
+}
+
+// AddPeer is invoked when a new peer is added.
+func (t *meshTracer) AddPeer(peer.ID, protocol.ID) {}
+
+// RemovePeer is invoked when a peer is removed, it is removed from the mesh of all topics.
+func (t *meshTracer) RemovePeer(id peer.ID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, peers := range t.mesh {
+		delete(peers, id)
+	}
+}
+
+// Join is invoked when a new topic is joined.
+func (t *meshTracer) Join(topic string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, exist := t.mesh[topic]; !exist {
+		t.mesh[topic] = map[peer.ID]struct{}{}
+	}
+}
+
+// Leave is invoked when a topic is abandoned.
+func (t *meshTracer) Leave(topic string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.mesh, topic)
+}
+
+// Graft is invoked when a new peer is grafted on the mesh.
+func (t *meshTracer) Graft(id peer.ID, topic string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	peers, exist := t.mesh[topic]
+	if !exist {
+		peers = map[peer.ID]struct{}{}
+		t.mesh[topic] = peers
+	}
+	peers[id] = struct{}{}
+}
+
+// Prune is invoked when a peer is pruned from the mesh.
+func (t *meshTracer) Prune(id peer.ID, topic string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.mesh[topic], id)
+}
+
+// ValidateMessage is invoked when a message first enters the validation pipeline.
+func (t *meshTracer) ValidateMessage(*pubsub.Message) {}
+
+// DeliverMessage is invoked when a message is delivered.
+func (t *meshTracer) DeliverMessage(*pubsub.Message) {}
+
+// RejectMessage is invoked when a message is Rejected or Ignored.
+func (t *meshTracer) RejectMessage(*pubsub.Message, string) {}
+
+// DuplicateMessage is invoked when a duplicate message is dropped.
+func (t *meshTracer) DuplicateMessage(*pubsub.Message) {}
+
+// ThrottlePeer is invoked when a peer is throttled by the peer gater.
+func (t *meshTracer) ThrottlePeer(peer.ID) {}
+
+// RecvRPC is invoked when an incoming RPC is received.
+func (t *meshTracer) RecvRPC(*pubsub.RPC) {}
+
+// SendRPC is invoked when a RPC is sent.
+func (t *meshTracer) SendRPC(*pubsub.RPC, peer.ID) {}
+
+// DropRPC is invoked when an outbound RPC is dropped.
+func (t *meshTracer) DropRPC(*pubsub.RPC, peer.ID) {}
+
+// UndeliverableMessage is invoked when the consumer of Subscribe is not reading messages fast enough.
+func (t *meshTracer) UndeliverableMessage(*pubsub.Message) {}
@@ -58,6 +58,8 @@ const (
 
 	// HareProtocol is the protocol id for hare messages.
 	HareProtocol = "hr1"
+	// HareBatchProtocol is the protocol id for batches of hare messages for several layers.
+	HareBatchProtocol = "hb1"
 
 	// BlockCertify is the protocol id for block certification.
 	BlockCertify = "bc1"
@@ -97,7 +99,8 @@ type Config struct {
 // New creates PubSub instance.
 func New(ctx context.Context, logger log.Log, h host.Host, cfg Config) (*PubSub, error) {
 	// TODO(dshulyak) refactor code to accept options
-	opts := getOptions(cfg)
+	mesh := newMeshTracer()
+	opts := append(getOptions(cfg), pubsub.WithRawTracer(mesh))
 	ps, err := pubsub.NewGossipSub(ctx, h, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize gossipsub instance: %w", err)
@@ -105,6 +108,7 @@ func New(ctx context.Context, logger log.Log, h host.Host, cfg Config) (*PubSub,
 	return &PubSub{
 		logger: logger,
 		pubsub: ps,
+		mesh:   mesh,
 		topics: map[string]*pubsub.Topic{},
 		host:   h,
 	}, nil
@@ -49,3 +49,27 @@ func TestGossip(t *testing.T) {
 	}
 	require.Eventually(t, func() bool { return len(received) == count }, 5*time.Second, 10*time.Millisecond)
 }
+
+func TestMeshTracer(t *testing.T) {
+	tracer := newMeshTracer()
+	topic := "test"
+	p1, p2 := peer.ID("p1"), peer.ID("p2")
+
+	tracer.Join(topic)
+	require.Empty(t, tracer.peers(topic))
+	tracer.Graft(p1, topic)
+	tracer.Graft(p2, topic)
+	tracer.Graft(p1, "other")
+	require.ElementsMatch(t, []peer.ID{p1, p2}, tracer.peers(topic))
+
+	tracer.Prune(p2, topic)
+	require.Equal(t, []peer.ID{p1}, tracer.peers(topic))
+
+	tracer.RemovePeer(p1)
+	require.Empty(t, tracer.peers(topic))
+	require.Empty(t, tracer.peers("other"))
+
+	tracer.Graft(p2, topic)
+	tracer.Leave(topic)
+	require.Empty(t, tracer.peers(topic))
+}
@@ -19,6 +19,7 @@ import (
 type PubSub struct {
 	logger log.Log
 	pubsub *pubsub.PubSub
+	mesh   *meshTracer
 	host   host.Host
 
 	mu     sync.RWMutex
@@ -80,3 +81,13 @@ func (ps *PubSub) Publish(ctx context.Context, topic string, msg []byte) error {
 func (ps *PubSub) ProtocolPeers(protocol string) []peer.ID {
 	return ps.pubsub.ListPeers(protocol)
 }
+
+// Topics returns the topics the node is subscribed to.
+func (ps *PubSub) Topics() []string {
+	return ps.pubsub.GetTopics()
+}
+
+// MeshPeers returns the peers in the gossipsub mesh of the topic.
+func (ps *PubSub) MeshPeers(topic string) []peer.ID {
+	return ps.mesh.peers(topic)
+}
@@ -0,0 +1,161 @@
+package server
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Bandwidth limits the rate in bytes per second of the data received and sent by the servers that share it.
+// zero limit means that the rate is not limited. limits can be changed while the servers are running.
+// all methods are safe to call on a nil Bandwidth, which doesn't limit anything.
+type Bandwidth struct {
+	download, upload *rate.Limiter
+}
+
+// NewBandwidth creates Bandwidth with the download and upload limits.
+func NewBandwidth(download, upload int) *Bandwidth {
+	b := &Bandwidth{
+		download: rate.NewLimiter(rate.Inf, 0),
+		upload:   rate.NewLimiter(rate.Inf, 0),
+	}
+	b.SetLimits(download, upload)
+	return b
+}
+
+// SetLimits updates the download and upload limits.
+func (b *Bandwidth) SetLimits(download, upload int) {
+	if b == nil {
+		return
+	}
+	setLimit(b.download, download)
+	setLimit(b.upload, upload)
+}
+
+// Limits returns the download and upload limits.
+func (b *Bandwidth) Limits() (download, upload int) {
+	if b == nil {
+		return 0, 0
+	}
+	return getLimit(b.download), getLimit(b.upload)
+}
+
+func setLimit(l *rate.Limiter, bps int) {
+	if bps <= 0 {
+		l.SetLimit(rate.Inf)
+		l.SetBurst(0)
+		return
+	}
+	l.SetLimit(rate.Limit(bps))
+	l.SetBurst(bps)
+}
+
+func getLimit(l *rate.Limiter) int {
+	if l.Limit() == rate.Inf {
+		return 0
+	}
+	return int(l.Limit())
+}
+
+// deadline is the deadline of the stream that is extended by the time spent waiting for the local
+// limiter, so that the wait doesn't count against the timeout of the remote peer.
+// it is used by a single goroutine, and it is safe to call on a nil deadline.
+type deadline struct {
+	stream interface{ SetDeadline(time.Time) error }
+	at     time.Time
+}
+
+func newDeadline(stream interface{ SetDeadline(time.Time) error }, timeout time.Duration) *deadline {
+	d := &deadline{stream: stream, at: time.Now().Add(timeout)}
+	_ = stream.SetDeadline(d.at)
+	return d
+}
+
+func (d *deadline) extend(waited time.Duration) {
+	if d == nil || waited <= 0 {
+		return
+	}
+	d.at = d.at.Add(waited)
+	_ = d.stream.SetDeadline(d.at)
+}
+
+func (b *Bandwidth) reader(ctx context.Context, r io.Reader, d *deadline) io.Reader {
+	if b == nil {
+		return r
+	}
+	return &limitedReader{ctx: ctx, r: r, l: b.download, deadline: d}
+}
+
+func (b *Bandwidth) writer(ctx context.Context, w io.Writer, d *deadline) io.Writer {
+	if b == nil {
+		return w
+	}
+	return &limitedWriter{ctx: ctx, w: w, l: b.upload, deadline: d}
+}
+
+// wait waits until n bytes can be transferred and extends the deadline by the time it waited.
+// it waits in chunks of the burst, as the burst may be reduced concurrently.
+func wait(ctx context.Context, l *rate.Limiter, n int, d *deadline) error {
+	start := time.Now()
+	defer func() { d.extend(time.Since(start)) }()
+	for n > 0 {
+		chunk := n
+		if burst := l.Burst(); burst > 0 && chunk > burst {
+			chunk = burst
+		}
+		if err := l.WaitN(ctx, chunk); err != nil {
+			return err
+		}
+		n -= chunk
+	}
+	return nil
+}
+
+type limitedReader struct {
+	ctx      context.Context
+	r        io.Reader
+	l        *rate.Limiter
+	deadline *deadline
+}
+
+func (lr *limitedReader) Read(p []byte) (int, error) {
+	if burst := lr.l.Burst(); burst > 0 && len(p) > burst {
+		p = p[:burst]
+	}
+	n, err := lr.r.Read(p)
+	if n > 0 {
+		if werr := wait(lr.ctx, lr.l, n, lr.deadline); werr != nil && err == nil {
+			err = werr
+		}
+	}
+	return n, err
+}
+
+type limitedWriter struct {
+	ctx      context.Context
+	w        io.Writer
+	l        *rate.Limiter
+	deadline *deadline
+}
+
+func (lw *limitedWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		chunk := p
+		if burst := lw.l.Burst(); burst > 0 && len(chunk) > burst {
+			chunk = chunk[:burst]
+		}
+		if err := wait(lw.ctx, lw.l, len(chunk), lw.deadline); err != nil {
+			return written, err
+		}
+		n, err := lw.w.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBandwidth_Limits(t *testing.T) {
+	var b *Bandwidth
+	download, upload := b.Limits()
+	require.Zero(t, download)
+	require.Zero(t, upload)
+	b.SetLimits(10, 10)
+
+	b = NewBandwidth(100, 0)
+	download, upload = b.Limits()
+	require.Equal(t, 100, download)
+	require.Zero(t, upload)
+
+	b.SetLimits(0, 200)
+	download, upload = b.Limits()
+	require.Zero(t, download)
+	require.Equal(t, 200, upload)
+}
+
+func TestBandwidth_Rate(t *testing.T) {
+	const limit = 1000
+	data := bytes.Repeat([]byte{1}, 3*limit)
+	b := NewBandwidth(limit, limit)
+
+	start := time.Now()
+	var buf bytes.Buffer
+	n, err := b.writer(context.Background(), &buf, nil).Write(data)
+	require.NoError(t, err)
+	require.Equal(t, len(data), n)
+	require.Equal(t, data, buf.Bytes())
+	// the first second of data is available in burst
+	require.GreaterOrEqual(t, time.Since(start), 2*time.Second-100*time.Millisecond)
+
+	start = time.Now()
+	got, err := io.ReadAll(b.reader(context.Background(), bytes.NewReader(data), nil))
+	require.NoError(t, err)
+	require.Equal(t, data, got)
+	require.GreaterOrEqual(t, time.Since(start), 2*time.Second-100*time.Millisecond)
+
+	b.SetLimits(0, 0)
+	start = time.Now()
+	got, err = io.ReadAll(b.reader(context.Background(), bytes.NewReader(data), nil))
+	require.NoError(t, err)
+	require.Equal(t, data, got)
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestBandwidth_Canceled(t *testing.T) {
+	b := NewBandwidth(10, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := b.writer(ctx, io.Discard, nil).Write(make([]byte, 100))
+	require.Error(t, err)
+}
+
+type deadlineRecorder struct {
+	deadlines []time.Time
+}
+
+func (r *deadlineRecorder) SetDeadline(t time.Time) error {
+	r.deadlines = append(r.deadlines, t)
+	return nil
+}
+
+func TestBandwidth_ExtendsDeadline(t *testing.T) {
+	const limit = 1000
+	b := NewBandwidth(limit, limit)
+	var rec deadlineRecorder
+	dl := newDeadline(&rec, time.Second)
+	initial := dl.at
+
+	start := time.Now()
+	_, err := b.writer(context.Background(), io.Discard, dl).Write(make([]byte, 2*limit))
+	require.NoError(t, err)
+	waited := time.Since(start)
+	require.NotEmpty(t, rec.deadlines)
+	require.Equal(t, dl.at, rec.deadlines[len(rec.deadlines)-1])
+	// the deadline is extended by the time spent in the limiter
+	require.Greater(t, dl.at.Sub(initial), waited/2)
+	require.LessOrEqual(t, dl.at.Sub(initial), waited)
+}
@@ -51,6 +51,13 @@ func WithRequestSizeLimit(limit int) Opt {
 	}
 }
 
+// WithBandwidth limits the rate of requests and responses, the same bandwidth can be shared by many servers.
+func WithBandwidth(b *Bandwidth) Opt {
+	return func(s *Server) {
+		s.bandwidth = b
+	}
+}
+
 // Handler is the handler to be defined by the application.
 type Handler func(context.Context, []byte) ([]byte, error)
 
@@ -78,6 +85,7 @@ type Server struct {
 	handler      Handler
 	timeout      time.Duration
 	requestLimit int
+	bandwidth    *Bandwidth
 
 	h Host
 
@@ -104,9 +112,9 @@ func New(h Host, proto string, handler Handler, opts ...Opt) *Server {
 
 func (s *Server) streamHandler(stream network.Stream) {
 	defer stream.Close()
-	_ = stream.SetDeadline(time.Now().Add(s.timeout))
+	dl := newDeadline(stream, s.timeout)
 	defer stream.SetDeadline(time.Time{})
-	rd := bufio.NewReader(stream)
+	rd := bufio.NewReader(s.bandwidth.reader(s.ctx, stream, dl))
 	size, err := varint.ReadUvarint(rd)
 	if err != nil {
 		return
@@ -137,7 +145,7 @@ func (s *Server) streamHandler(stream network.Stream) {
 		resp.Data = buf
 	}
 
-	wr := bufio.NewWriter(stream)
+	wr := bufio.NewWriter(s.bandwidth.writer(s.ctx, stream, dl))
 	if _, err := codec.EncodeTo(wr, &resp); err != nil {
 		s.logger.With().Warning("failed to write response", log.Err(err))
 		return
@@ -164,18 +172,20 @@ func (s *Server) Request(ctx context.Context, pid peer.ID, req []byte, resp func
 				log.Duration("duration", time.Since(start)),
 			)
 		}()
-		ctx, cancel := context.WithTimeout(ctx, s.timeout)
+		sctx, cancel := context.WithTimeout(ctx, s.timeout)
 		defer cancel()
-		stream, err := s.h.NewStream(network.WithNoDial(ctx, "existing connection"), pid, protocol.ID(s.protocol))
+		stream, err := s.h.NewStream(network.WithNoDial(sctx, "existing connection"), pid, protocol.ID(s.protocol))
 		if err != nil {
 			failure(err)
 			return
 		}
 		defer stream.Close()
 		defer stream.SetDeadline(time.Time{})
-		_ = stream.SetDeadline(time.Now().Add(s.timeout))
+		// the time spent waiting for the local bandwidth limiter extends the deadline,
+		// so that the peer is not blamed for the timeouts caused by the limiter.
+		dl := newDeadline(stream, s.timeout)
 
-		wr := bufio.NewWriter(stream)
+		wr := bufio.NewWriter(s.bandwidth.writer(ctx, stream, dl))
 		sz := make([]byte, binary.MaxVarintLen64)
 		n := binary.PutUvarint(sz, uint64(len(req)))
 		_, err = wr.Write(sz[:n])
@@ -193,7 +203,7 @@ func (s *Server) Request(ctx context.Context, pid peer.ID, req []byte, resp func
 			return
 		}
 
-		rd := bufio.NewReader(stream)
+		rd := bufio.NewReader(s.bandwidth.reader(ctx, stream, dl))
 		var r Response
 		if _, err := codec.DecodeFrom(rd, &r); err != nil {
 			failure(err)
@@ -0,0 +1,36 @@
+package p2p
+
+import (
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/network"
+)
+
+// Connection describes the connection with the peer.
+type Connection struct {
+	Peer     Peer      `json:"peer"`
+	Address  string    `json:"address"`
+	Outbound bool      `json:"outbound"`
+	Opened   time.Time `json:"opened"`
+}
+
+// Connections returns the open connections with the peers.
+func (fh *Host) Connections() []Connection {
+	conns := fh.Network().Conns()
+	rst := make([]Connection, 0, len(conns))
+	for _, conn := range conns {
+		stat := conn.Stat()
+		rst = append(rst, Connection{
+			Peer:     conn.RemotePeer(),
+			Address:  conn.RemoteMultiaddr().String(),
+			Outbound: stat.Direction == network.DirOutbound,
+			Opened:   stat.Opened,
+		})
+	}
+	return rst
+}
+
+// RoutingTable returns the peers in the dht routing table of the discovery.
+func (fh *Host) RoutingTable() []Peer {
+	return fh.discovery.RoutingTable()
+}
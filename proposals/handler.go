@@ -5,8 +5,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
+	lru "github.com/hashicorp/golang-lru/v2"
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/spacemeshos/go-spacemesh/codec"
@@ -43,8 +45,12 @@ var (
 	errKnownProposal         = errors.New("known proposal")
 	errKnownBallot           = errors.New("known ballot")
 	errMaliciousBallot       = errors.New("malicious ballot")
+	errLateProposal          = fmt.Errorf("%w: late proposal", pubsub.ErrValidationReject)
 )
 
+// lateTrackerSize is the number of peers tracked for late arrivals.
+const lateTrackerSize = 1000
+
 // Handler processes Proposal from gossip and, if deems it valid, propagates it to peers.
 type Handler struct {
 	logger log.Log
@@ -58,6 +64,9 @@ type Handler struct {
 	validator  eligibilityValidator
 	decoder    ballotDecoder
 	clock      layerClock
+
+	lateMu sync.Mutex
+	late   *lru.Cache[p2p.Peer, uint64]
 }
 
 // Config defines configuration for the handler.
@@ -68,6 +77,11 @@ type Config struct {
 	MaxExceptions          int
 	Hdist                  uint32
 	MinimalActiveSetWeight uint64
+	// LateGrace is how long after the end of the layer a gossiped proposal is still
+	// processed. Proposals that arrive later are rejected and counted against the peer
+	// that sent them, ballots that are referenced by other ballots are still fetched
+	// by sync and passed to the tortoise. Zero disables the check.
+	LateGrace time.Duration
 }
 
 // defaultConfig for BlockHandler.
@@ -128,6 +142,11 @@ func NewHandler(
 	for _, opt := range opts {
 		opt(b)
 	}
+	late, err := lru.New[p2p.Peer, uint64](lateTrackerSize)
+	if err != nil {
+		b.logger.With().Fatal("failed to create late arrivals tracker", log.Err(err))
+	}
+	b.late = late
 	if b.validator == nil {
 		b.validator = NewEligibilityValidator(b.cfg.LayerSize, b.cfg.LayersPerEpoch, b.cfg.MinimalActiveSetWeight, cdb, bc, m, b.logger, verifier)
 	}
@@ -260,6 +279,12 @@ func (h *Handler) handleProposal(ctx context.Context, expHash types.Hash32, peer
 		return fmt.Errorf("%w: proposal want %s, got %s", errWrongHash, expHash.ShortString(), p.ID().AsHash32().ShortString())
 	}
 
+	if expHash == (types.Hash32{}) {
+		if err := h.checkLate(logger, peer, p.Layer, receivedTime); err != nil {
+			return err
+		}
+	}
+
 	if p.AtxID == types.EmptyATXID || p.AtxID == h.cfg.GoldenATXID {
 		badData.Inc()
 		return errInvalidATXID
@@ -339,6 +364,39 @@ func (h *Handler) handleProposal(ctx context.Context, expHash types.Hash32, peer
 	return nil
 }
 
+// checkLate checks if the gossiped proposal arrived after the end of its layer.
+// Proposals that arrived within the grace window are processed as usual, later ones are rejected.
+func (h *Handler) checkLate(logger log.Log, peer p2p.Peer, lid types.LayerID, received time.Time) error {
+	delay := received.Sub(h.clock.LayerToTime(lid.Add(1)))
+	if delay <= 0 {
+		return nil
+	}
+	total := h.trackLate(peer)
+	if h.cfg.LateGrace == 0 || delay <= h.cfg.LateGrace {
+		lateAccepted.Inc()
+		return nil
+	}
+	lateRejected.Inc()
+	logger.With().Debug("proposal arrived after grace window",
+		log.Stringer("peer", peer),
+		log.Duration("delay", delay),
+		log.Duration("grace", h.cfg.LateGrace),
+		log.Uint64("peer_late_total", total),
+	)
+	return fmt.Errorf("%w: received %v after the end of layer %s", errLateProposal, delay, lid)
+}
+
+// trackLate counts the late proposal against the peer and returns the peer's total.
+// only the most recent peers are tracked.
+func (h *Handler) trackLate(peer p2p.Peer) uint64 {
+	h.lateMu.Lock()
+	defer h.lateMu.Unlock()
+	total, _ := h.late.Get(peer)
+	total++
+	h.late.Add(peer, total)
+	return total
+}
+
 func (h *Handler) processBallot(ctx context.Context, logger log.Log, b *types.Ballot) (*types.MalfeasanceProof, error) {
 	t0 := time.Now()
 	if has, err := ballots.Has(h.cdb, b.ID()); err != nil {
@@ -1250,6 +1250,54 @@ func TestProposal_ValidProposal(t *testing.T) {
 	checkProposal(t, th.cdb, p, true)
 }
 
+func TestProposal_LateGossip(t *testing.T) {
+	for _, tc := range []struct {
+		desc  string
+		grace time.Duration
+		err   error
+	}{
+		{desc: "disabled"},
+		{desc: "within grace", grace: time.Minute},
+		{desc: "after grace", grace: time.Second, err: errLateProposal},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			th := createTestHandlerNoopDecoder(t)
+			th.cfg.LateGrace = tc.grace
+			lid := types.LayerID(10)
+			p := createProposal(t, withLayer(lid))
+			createAtx(t, th.cdb.Database, p.Layer.GetEpoch()-1, p.AtxID, p.SmesherID)
+			data := encodeProposal(t, p)
+			peer := p2p.Peer("buddy")
+			if tc.err == nil {
+				th.mf.EXPECT().RegisterPeerHashes(peer, collectHashes(*p))
+				th.mf.EXPECT().GetBallots(gomock.Any(), []types.BallotID{p.Votes.Base, p.RefBallot}).Return(nil)
+				th.md.EXPECT().GetMissingActiveSet(gomock.Any(), types.ATXIDList{p.AtxID}).Return(types.ATXIDList{p.AtxID})
+				th.mf.EXPECT().GetAtxs(gomock.Any(), types.ATXIDList{p.AtxID}).Return(nil)
+				th.mv.EXPECT().CheckEligibility(gomock.Any(), gomock.Any()).Return(true, nil)
+				th.mm.EXPECT().AddBallot(gomock.Any(), &p.Ballot).DoAndReturn(
+					func(_ context.Context, got *types.Ballot) (*types.MalfeasanceProof, error) {
+						require.NoError(t, ballots.Add(th.cdb, got))
+						return nil, nil
+					})
+				th.mf.EXPECT().GetProposalTxs(gomock.Any(), p.TxIDs).Return(nil)
+				th.mm.EXPECT().AddTXsFromProposal(gomock.Any(), p.Layer, p.ID(), p.TxIDs).Return(nil)
+			}
+			err := th.HandleProposal(context.Background(), peer, data)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				require.ErrorIs(t, err, pubsub.ErrValidationReject)
+			} else {
+				require.NoError(t, err)
+			}
+			checkProposal(t, th.cdb, p, tc.err == nil)
+			total, _ := th.late.Get(peer)
+			require.EqualValues(t, 1, total)
+			require.False(t, th.late.Contains(p2p.Peer("other")))
+		})
+	}
+}
+
 func TestMetrics(t *testing.T) {
 	th := createTestHandlerNoopDecoder(t)
 	lid := types.LayerID(100)
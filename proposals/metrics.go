@@ -102,3 +102,14 @@ var (
 	notEligible    = processErrors.WithLabelValues("elig")
 	failedPublish  = processErrors.WithLabelValues("pub")
 )
+
+var (
+	lateArrivals = metrics.NewCounter(
+		"late",
+		subsystem,
+		"number of gossiped proposals received after the end of their layer",
+		[]string{"outcome"},
+	)
+	lateAccepted = lateArrivals.WithLabelValues("accepted")
+	lateRejected = lateArrivals.WithLabelValues("rejected")
+)
@@ -3,6 +3,8 @@ package signing
 import (
 	"crypto/ed25519"
 
+	oasis "github.com/oasisprotocol/curve25519-voi/primitives/ed25519"
+
 	"github.com/spacemeshos/go-spacemesh/common/types"
 )
 
@@ -21,6 +23,11 @@ func WithVerifierPrefix(prefix []byte) VerifierOptionFunc {
 	}
 }
 
+// zip215Options select the cofactored verification equation of ZIP-215, which is the only one
+// supported by the batch verification. every signature accepted by crypto/ed25519 is accepted
+// with these options, but not the other way around.
+var zip215Options = &oasis.Options{Verify: oasis.VerifyOptionsZIP_215}
+
 // EdVerifier extracts public keys from signatures.
 type EdVerifier struct {
 	prefix []byte
@@ -41,9 +48,51 @@ func NewEdVerifier(opts ...VerifierOptionFunc) (*EdVerifier, error) {
 
 // Verify verifies that a signature matches public key and message.
 func (es *EdVerifier) Verify(d Domain, nodeID types.NodeID, m []byte, sig types.EdSignature) bool {
+	return ed25519.Verify(nodeID[:], es.message(d, m), sig[:])
+}
+
+// VerifyZIP215 verifies that a signature matches public key and message with the ZIP-215 rules,
+// the same as EdBatch. It accepts some signatures that Verify rejects, so all nodes must agree
+// on the messages for which it is used.
+func (es *EdVerifier) VerifyZIP215(d Domain, nodeID types.NodeID, m []byte, sig types.EdSignature) bool {
+	return oasis.VerifyWithOptions(nodeID[:], es.message(d, m), sig[:], zip215Options)
+}
+
+func (es *EdVerifier) message(d Domain, m []byte) []byte {
 	msg := make([]byte, 0, len(es.prefix)+1+len(m))
 	msg = append(msg, es.prefix...)
 	msg = append(msg, byte(d))
 	msg = append(msg, m...)
-	return ed25519.Verify(nodeID[:], msg, sig[:])
+	return msg
+}
+
+// NewBatch returns a batch to verify several signatures at once.
+func (es *EdVerifier) NewBatch() *EdBatch {
+	return &EdBatch{verifier: es, bv: oasis.NewBatchVerifier()}
+}
+
+// EdBatch accumulates signatures that are verified together, which is significantly
+// cheaper than verifying each of them if all signatures are valid.
+// A batch is valid only if every signature in it passes VerifyZIP215.
+type EdBatch struct {
+	verifier *EdVerifier
+	bv       *oasis.BatchVerifier
+	size     int
+}
+
+// Add adds the signature of the message to the batch.
+func (b *EdBatch) Add(d Domain, nodeID types.NodeID, m []byte, sig types.EdSignature) {
+	b.bv.AddWithOptions(nodeID[:], b.verifier.message(d, m), sig[:], zip215Options)
+	b.size++
+}
+
+// Size returns the number of signatures in the batch.
+func (b *EdBatch) Size() int {
+	return b.size
+}
+
+// Verify returns true if all signatures in the batch are valid.
+// It returns false for an empty batch.
+func (b *EdBatch) Verify() bool {
+	return b.bv.VerifyBatchOnly(nil)
 }
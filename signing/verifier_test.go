@@ -86,3 +86,71 @@ func Fuzz_EdVerifier(f *testing.F) {
 		require.True(t, ok)
 	})
 }
+
+func TestEdBatch(t *testing.T) {
+	verifier, err := signing.NewEdVerifier(signing.WithVerifierPrefix([]byte("one")))
+	require.NoError(t, err)
+	batch := verifier.NewBatch()
+	require.False(t, batch.Verify())
+
+	var sigs []types.EdSignature
+	for i := 0; i < 10; i++ {
+		signer, err := signing.NewEdSigner(signing.WithPrefix([]byte("one")))
+		require.NoError(t, err)
+		msg := []byte{byte(i)}
+		sig := signer.Sign(signing.HARE, msg)
+		sigs = append(sigs, sig)
+		batch.Add(signing.HARE, signer.NodeID(), msg, sig)
+	}
+	require.Equal(t, 10, batch.Size())
+	require.True(t, batch.Verify())
+
+	t.Run("invalid signature", func(t *testing.T) {
+		signer, err := signing.NewEdSigner(signing.WithPrefix([]byte("one")))
+		require.NoError(t, err)
+		batch := verifier.NewBatch()
+		batch.Add(signing.HARE, signer.NodeID(), []byte{1}, signer.Sign(signing.HARE, []byte{1}))
+		batch.Add(signing.HARE, signer.NodeID(), []byte{2}, sigs[0])
+		require.False(t, batch.Verify())
+	})
+
+	t.Run("same result as single verification", func(t *testing.T) {
+		signer, err := signing.NewEdSigner(signing.WithPrefix([]byte("one")))
+		require.NoError(t, err)
+		for i := 0; i < 100; i++ {
+			msg := types.RandomBytes(8)
+			sig := signer.Sign(signing.HARE, msg)
+			if i%2 == 1 {
+				sig[i%len(sig)] ^= 1
+			}
+			batch := verifier.NewBatch()
+			batch.Add(signing.HARE, signer.NodeID(), msg, sig)
+			require.Equal(t, verifier.VerifyZIP215(signing.HARE, signer.NodeID(), msg, sig), batch.Verify())
+		}
+	})
+
+	t.Run("different domain", func(t *testing.T) {
+		signer, err := signing.NewEdSigner(signing.WithPrefix([]byte("one")))
+		require.NoError(t, err)
+		batch := verifier.NewBatch()
+		batch.Add(signing.ATX, signer.NodeID(), []byte{1}, signer.Sign(signing.HARE, []byte{1}))
+		require.False(t, batch.Verify())
+	})
+}
+
+func TestEdVerifier_ZIP215(t *testing.T) {
+	verifier, err := signing.NewEdVerifier(signing.WithVerifierPrefix([]byte("one")))
+	require.NoError(t, err)
+	// the identity point as the public key and a signature with the small order point as R and zero S.
+	// it satisfies the cofactored equation of ZIP-215, but not the cofactorless one of crypto/ed25519.
+	var id types.NodeID
+	id[0] = 1
+	var sig types.EdSignature
+	msg := []byte{1}
+
+	require.False(t, verifier.Verify(signing.HARE, id, msg, sig))
+	require.True(t, verifier.VerifyZIP215(signing.HARE, id, msg, sig))
+	batch := verifier.NewBatch()
+	batch.Add(signing.HARE, id, msg, sig)
+	require.True(t, batch.Verify())
+}
@@ -1,12 +1,16 @@
 package accounts
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/spacemeshos/go-spacemesh/common/types"
 	"github.com/spacemeshos/go-spacemesh/sql"
 )
 
+// ErrPruned is returned for the state at the layer that was pruned.
+var ErrPruned = errors.New("accounts: state is pruned")
+
 func load(db sql.Executor, address types.Address, query string, enc sql.Encoder) (types.Account, error) {
 	var account types.Account
 	_, err := db.Exec(query, enc, func(stmt *sql.Statement) bool {
@@ -54,6 +58,9 @@ func Latest(db sql.Executor, address types.Address) (types.Account, error) {
 
 // Get account data that was valid at the specified layer.
 func Get(db sql.Executor, address types.Address, layer types.LayerID) (types.Account, error) {
+	if err := checkPruned(db, layer); err != nil {
+		return types.Account{}, err
+	}
 	account, err := load(db, address, "select balance, next_nonce, layer_updated, template, state from accounts where address = ?1 and layer_updated <= ?2;", func(stmt *sql.Statement) {
 		stmt.BindBytes(1, address.Bytes())
 		stmt.BindInt64(2, int64(layer))
@@ -90,6 +97,9 @@ func All(db sql.Executor) ([]*types.Account, error) {
 }
 
 func Snapshot(db sql.Executor, layer types.LayerID) ([]*types.Account, error) {
+	if err := checkPruned(db, layer); err != nil {
+		return nil, err
+	}
 	var rst []*types.Account
 	if rows, err := db.Exec(`
 			select address, balance, next_nonce, max(layer_updated), template, state from accounts 
@@ -146,6 +156,9 @@ func Update(db sql.Executor, to *types.Account) error {
 
 // Revert state after the layer.
 func Revert(db sql.Executor, after types.LayerID) error {
+	if err := checkPruned(db, after); err != nil {
+		return err
+	}
 	_, err := db.Exec(`delete from accounts where layer_updated > ?1;`,
 		func(stmt *sql.Statement) {
 			stmt.BindInt64(1, int64(after))
@@ -155,3 +168,46 @@ func Revert(db sql.Executor, after types.LayerID) error {
 	}
 	return nil
 }
+
+// Prune removes the account states updated before the layer if they are superseded by a later
+// state updated no later than the layer. the state of all accounts at the layer and after it is kept.
+func Prune(db sql.Executor, layer types.LayerID) error {
+	if _, err := db.Exec(`delete from accounts where layer_updated < ?1 and layer_updated <
+		(select max(a.layer_updated) from accounts a where a.address = accounts.address and a.layer_updated <= ?1);`,
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(layer))
+		}, nil); err != nil {
+		return fmt.Errorf("prune up to %v: %w", layer, err)
+	}
+	if _, err := db.Exec(`insert into accounts_pruned (id, layer) values (0, ?1)
+		on conflict(id) do update set layer = excluded.layer where excluded.layer > accounts_pruned.layer;`,
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(layer))
+		}, nil); err != nil {
+		return fmt.Errorf("set pruned layer %v: %w", layer, err)
+	}
+	return nil
+}
+
+// Pruned returns the layer before which the account states were pruned, zero if they were never pruned.
+func Pruned(db sql.Executor) (types.LayerID, error) {
+	var layer types.LayerID
+	if _, err := db.Exec("select layer from accounts_pruned where id = 0;", nil, func(stmt *sql.Statement) bool {
+		layer = types.LayerID(uint32(stmt.ColumnInt64(0)))
+		return false
+	}); err != nil {
+		return 0, fmt.Errorf("get pruned layer: %w", err)
+	}
+	return layer, nil
+}
+
+func checkPruned(db sql.Executor, layer types.LayerID) error {
+	pruned, err := Pruned(db)
+	if err != nil {
+		return err
+	}
+	if layer < pruned {
+		return fmt.Errorf("%w: layer %v is before %v", ErrPruned, layer, pruned)
+	}
+	return nil
+}
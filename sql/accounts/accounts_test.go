@@ -104,3 +104,47 @@ func TestSnapshot(t *testing.T) {
 		}
 	}
 }
+
+func TestPrune(t *testing.T) {
+	db := sql.InMemory()
+	pruned, err := Pruned(db)
+	require.NoError(t, err)
+	require.Zero(t, pruned)
+
+	addresses := []types.Address{{1, 1}, {2, 2}}
+	n := []int{10, 3}
+	for i, address := range addresses {
+		for _, update := range genSeq(address, n[i]) {
+			require.NoError(t, Update(db, update))
+		}
+	}
+	require.NoError(t, Prune(db, types.LayerID(5)))
+	pruned, err = Pruned(db)
+	require.NoError(t, err)
+	require.Equal(t, types.LayerID(5), pruned)
+
+	// the state at the pruned layer and after it is kept
+	for lid := types.LayerID(5); lid <= 10; lid++ {
+		got, err := Snapshot(db, lid)
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		require.EqualValues(t, lid, got[0].Layer)
+		require.EqualValues(t, 3, got[1].Layer)
+	}
+	_, err = Snapshot(db, types.LayerID(4))
+	require.ErrorIs(t, err, ErrPruned)
+	_, err = Get(db, addresses[0], types.LayerID(4))
+	require.ErrorIs(t, err, ErrPruned)
+	require.ErrorIs(t, Revert(db, types.LayerID(4)), ErrPruned)
+	require.NoError(t, Revert(db, types.LayerID(5)))
+
+	all, err := All(db)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+
+	// the pruned layer never moves back
+	require.NoError(t, Prune(db, types.LayerID(2)))
+	pruned, err = Pruned(db)
+	require.NoError(t, err)
+	require.Equal(t, types.LayerID(5), pruned)
+}
@@ -212,6 +212,45 @@ func GetIDsByEpoch(db sql.Executor, epoch types.EpochID) (ids []types.ATXID, err
 	return ids, nil
 }
 
+// GetIDsByEpochAfter returns up to limit ids of atxs published in the epoch, ordered by id and starting after the given id.
+func GetIDsByEpochAfter(db sql.Executor, epoch types.EpochID, after types.ATXID, limit int) (ids []types.ATXID, err error) {
+	enc := func(stmt *sql.Statement) {
+		stmt.BindInt64(1, int64(epoch))
+		stmt.BindBytes(2, after.Bytes())
+		stmt.BindInt64(3, int64(limit))
+	}
+	dec := func(stmt *sql.Statement) bool {
+		var id types.ATXID
+		stmt.ColumnBytes(0, id[:])
+		ids = append(ids, id)
+		return true
+	}
+	if _, err := db.Exec("select id from atxs where epoch = ?1 and id > ?2 order by id limit ?3;", enc, dec); err != nil {
+		return nil, fmt.Errorf("exec epoch %v after %v: %w", epoch, after, err)
+	}
+	return ids, nil
+}
+
+// GetIDsByNodeIDFrom returns up to limit ids of atxs published by the smesher, ordered by the publish epoch
+// and starting from the given epoch.
+func GetIDsByNodeIDFrom(db sql.Executor, nodeID types.NodeID, from types.EpochID, limit int) (ids []types.ATXID, err error) {
+	enc := func(stmt *sql.Statement) {
+		stmt.BindBytes(1, nodeID.Bytes())
+		stmt.BindInt64(2, int64(from))
+		stmt.BindInt64(3, int64(limit))
+	}
+	dec := func(stmt *sql.Statement) bool {
+		var id types.ATXID
+		stmt.ColumnBytes(0, id[:])
+		ids = append(ids, id)
+		return true
+	}
+	if _, err := db.Exec("select id from atxs where pubkey = ?1 and epoch >= ?2 order by epoch limit ?3;", enc, dec); err != nil {
+		return nil, fmt.Errorf("exec node id %v from %v: %w", nodeID, from, err)
+	}
+	return ids, nil
+}
+
 // VRFNonce gets the VRF nonce of a smesher for a given epoch.
 func VRFNonce(db sql.Executor, id types.NodeID, epoch types.EpochID) (nonce types.VRFPostIndex, err error) {
 	enc := func(stmt *sql.Statement) {
@@ -1,7 +1,9 @@
 package atxs_test
 
 import (
+	"bytes"
 	"os"
+	"sort"
 	"testing"
 	"time"
 
@@ -414,6 +416,72 @@ func TestGetIDsByEpoch(t *testing.T) {
 	require.EqualValues(t, []types.ATXID{atx4.ID()}, ids3)
 }
 
+func TestGetIDsByEpochAfter(t *testing.T) {
+	db := sql.InMemory()
+
+	epoch := types.EpochID(1)
+	var ids []types.ATXID
+	for i := 0; i < 5; i++ {
+		sig, err := signing.NewEdSigner()
+		require.NoError(t, err)
+		atx, err := newAtx(sig, withPublishEpoch(epoch))
+		require.NoError(t, err)
+		require.NoError(t, atxs.Add(db, atx))
+		ids = append(ids, atx.ID())
+	}
+	sig, err := signing.NewEdSigner()
+	require.NoError(t, err)
+	other, err := newAtx(sig, withPublishEpoch(epoch+1))
+	require.NoError(t, err)
+	require.NoError(t, atxs.Add(db, other))
+	sort.Slice(ids, func(i, j int) bool {
+		return bytes.Compare(ids[i].Bytes(), ids[j].Bytes()) < 0
+	})
+
+	got, err := atxs.GetIDsByEpochAfter(db, epoch, types.EmptyATXID, 3)
+	require.NoError(t, err)
+	require.Equal(t, ids[:3], got)
+
+	got, err = atxs.GetIDsByEpochAfter(db, epoch, got[2], 3)
+	require.NoError(t, err)
+	require.Equal(t, ids[3:], got)
+
+	got, err = atxs.GetIDsByEpochAfter(db, epoch, ids[4], 3)
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
+
+func TestGetIDsByNodeIDFrom(t *testing.T) {
+	db := sql.InMemory()
+
+	sig, err := signing.NewEdSigner()
+	require.NoError(t, err)
+	var ids []types.ATXID
+	for epoch := types.EpochID(1); epoch <= 5; epoch++ {
+		atx, err := newAtx(sig, withPublishEpoch(epoch))
+		require.NoError(t, err)
+		require.NoError(t, atxs.Add(db, atx))
+		ids = append(ids, atx.ID())
+	}
+	other, err := signing.NewEdSigner()
+	require.NoError(t, err)
+	atx, err := newAtx(other, withPublishEpoch(2))
+	require.NoError(t, err)
+	require.NoError(t, atxs.Add(db, atx))
+
+	got, err := atxs.GetIDsByNodeIDFrom(db, sig.NodeID(), 0, 3)
+	require.NoError(t, err)
+	require.Equal(t, ids[:3], got)
+
+	got, err = atxs.GetIDsByNodeIDFrom(db, sig.NodeID(), 4, 3)
+	require.NoError(t, err)
+	require.Equal(t, ids[3:], got)
+
+	got, err = atxs.GetIDsByNodeIDFrom(db, sig.NodeID(), 6, 3)
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
+
 func TestVRFNonce(t *testing.T) {
 	// Arrange
 	db := sql.InMemory()
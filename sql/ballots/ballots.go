@@ -94,7 +94,20 @@ func Get(db sql.Executor, id types.BallotID) (rst *types.Ballot, err error) {
 
 // Layer returns full body ballot for layer.
 func Layer(db sql.Executor, lid types.LayerID) (rst []*types.Ballot, err error) {
-	if _, err = db.Exec(`select id, pubkey, ballot, length(identities.proof)
+	if err = IterateLayer(db, lid, func(ballot *types.Ballot) bool {
+		rst = append(rst, ballot)
+		return true
+	}); err != nil {
+		return nil, err
+	}
+	return rst, nil
+}
+
+// IterateLayer decodes ballots in the layer one at a time and passes them to fn.
+// Iteration stops if fn returns false.
+func IterateLayer(db sql.Executor, lid types.LayerID, fn func(*types.Ballot) bool) error {
+	var derr error
+	if _, err := db.Exec(`select id, pubkey, ballot, length(identities.proof)
 		from ballots left join identities using(pubkey)
 		where layer = ?1;`, func(stmt *sql.Statement) {
 		stmt.BindInt64(1, int64(lid))
@@ -102,20 +115,19 @@ func Layer(db sql.Executor, lid types.LayerID) (rst []*types.Ballot, err error)
 		id := types.BallotID{}
 		stmt.ColumnBytes(0, id[:])
 		var ballot *types.Ballot
-		ballot, err = decodeBallot(id,
+		ballot, derr = decodeBallot(id,
 			stmt.ColumnReader(1),
 			stmt.ColumnReader(2),
 			stmt.ColumnInt(3) > 0,
 		)
-		if err != nil {
+		if derr != nil {
 			return false
 		}
-		rst = append(rst, ballot)
-		return true
+		return fn(ballot)
 	}); err != nil {
-		return nil, fmt.Errorf("ballots for layer %s: %w", lid, err)
+		return fmt.Errorf("ballots for layer %s: %w", lid, err)
 	}
-	return rst, err
+	return derr
 }
 
 // IDsInLayer returns ballots ids in the layer.
@@ -300,3 +312,32 @@ func AllFirstInEpoch(db sql.Executor, epoch types.EpochID) ([]*types.Ballot, err
 	}
 	return rst, nil
 }
+
+// BySmesher returns ballots produced by the smesher in the epoch.
+func BySmesher(db sql.Executor, nodeID types.NodeID, epoch types.EpochID) (rst []*types.Ballot, err error) {
+	if _, err = db.Exec(`select id, pubkey, ballot, length(identities.proof)
+		from ballots left join identities using(pubkey)
+		where pubkey = ?1 and layer between ?2 and ?3
+		order by layer;`, func(stmt *sql.Statement) {
+		stmt.BindBytes(1, nodeID.Bytes())
+		stmt.BindInt64(2, int64(epoch.FirstLayer()))
+		stmt.BindInt64(3, int64((epoch + 1).FirstLayer().Sub(1)))
+	}, func(stmt *sql.Statement) bool {
+		id := types.BallotID{}
+		stmt.ColumnBytes(0, id[:])
+		var ballot *types.Ballot
+		ballot, err = decodeBallot(id,
+			stmt.ColumnReader(1),
+			stmt.ColumnReader(2),
+			stmt.ColumnInt(3) > 0,
+		)
+		if err != nil {
+			return false
+		}
+		rst = append(rst, ballot)
+		return true
+	}); err != nil {
+		return nil, fmt.Errorf("ballots by %s in epoch %s: %w", nodeID, epoch, err)
+	}
+	return rst, err
+}
@@ -153,6 +153,33 @@ func TestLayerBallotBySmesher(t *testing.T) {
 	require.Equal(t, ballots[1], *prev)
 }
 
+func TestBySmesher(t *testing.T) {
+	db := sql.InMemory()
+	nodeID1 := types.RandomNodeID()
+	nodeID2 := types.RandomNodeID()
+	epoch := types.EpochID(2)
+	ballots := []types.Ballot{
+		types.NewExistingBallot(types.BallotID{1}, types.EmptyEdSignature, nodeID1, epoch.FirstLayer().Sub(1)),
+		types.NewExistingBallot(types.BallotID{2}, types.EmptyEdSignature, nodeID1, epoch.FirstLayer()),
+		types.NewExistingBallot(types.BallotID{3}, types.EmptyEdSignature, nodeID2, epoch.FirstLayer()),
+		types.NewExistingBallot(types.BallotID{4}, types.EmptyEdSignature, nodeID1, epoch.FirstLayer().Add(layersPerEpoch-1)),
+		types.NewExistingBallot(types.BallotID{5}, types.EmptyEdSignature, nodeID1, (epoch + 1).FirstLayer()),
+	}
+	for _, ballot := range ballots {
+		require.NoError(t, Add(db, &ballot))
+	}
+
+	got, err := BySmesher(db, nodeID1, epoch)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	require.Equal(t, ballots[1], *got[0])
+	require.Equal(t, ballots[3], *got[1])
+
+	got, err = BySmesher(db, nodeID2, epoch+1)
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
+
 func TestGetRefBallot(t *testing.T) {
 	db := sql.InMemory()
 	lid2 := types.LayerID(2)
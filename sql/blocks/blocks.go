@@ -40,6 +40,17 @@ func Add(db sql.Executor, block *types.Block) error {
 		}, nil); err != nil {
 		return fmt.Errorf("insert %s: %w", block.ID(), err)
 	}
+	for _, reward := range block.Rewards {
+		if _, err := db.Exec(`insert into block_rewards (atx, block, layer) values (?1, ?2, ?3)
+			on conflict do nothing;`,
+			func(stmt *sql.Statement) {
+				stmt.BindBytes(1, reward.AtxID.Bytes())
+				stmt.BindBytes(2, block.ID().Bytes())
+				stmt.BindInt64(3, int64(block.LayerIndex))
+			}, nil); err != nil {
+			return fmt.Errorf("insert reward %s for %s: %w", reward.AtxID, block.ID(), err)
+		}
+	}
 	return nil
 }
 
@@ -137,23 +148,35 @@ func GetLayer(db sql.Executor, id types.BlockID) (types.LayerID, error) {
 
 // Layer returns full body blocks for layer.
 func Layer(db sql.Executor, lid types.LayerID) ([]*types.Block, error) {
-	var (
-		blk *types.Block
-		rst []*types.Block
-		err error
-	)
-	if _, err = db.Exec("select id, block from blocks where layer = ?1;", func(stmt *sql.Statement) {
+	var rst []*types.Block
+	if err := IterateLayer(db, lid, func(block *types.Block) bool {
+		rst = append(rst, block)
+		return true
+	}); err != nil {
+		return nil, err
+	}
+	return rst, nil
+}
+
+// IterateLayer decodes blocks in the layer one at a time and passes them to fn.
+// Iteration stops if fn returns false.
+func IterateLayer(db sql.Executor, lid types.LayerID, fn func(*types.Block) bool) error {
+	var derr error
+	if _, err := db.Exec("select id, block from blocks where layer = ?1;", func(stmt *sql.Statement) {
 		stmt.BindInt64(1, int64(lid.Uint32()))
 	}, func(stmt *sql.Statement) bool {
 		id := types.BlockID{}
 		stmt.ColumnBytes(0, id[:])
-		blk, err = decodeBlock(stmt.ColumnReader(1), id)
-		rst = append(rst, blk)
-		return true
+		var blk *types.Block
+		blk, derr = decodeBlock(stmt.ColumnReader(1), id)
+		if derr != nil {
+			return false
+		}
+		return fn(blk)
 	}); err != nil {
-		return nil, fmt.Errorf("select blocks in layer %s: %w", lid, err)
+		return fmt.Errorf("select blocks in layer %s: %w", lid, err)
 	}
-	return rst, nil
+	return derr
 }
 
 // IDsInLayer returns list of block ids in the layer.
@@ -188,3 +211,46 @@ func ContextualValidity(db sql.Executor, lid types.LayerID) ([]types.BlockContex
 	}
 	return rst, nil
 }
+
+// IDsBySmesher returns ids of the blocks that reward the smesher in the epoch.
+func IDsBySmesher(db sql.Executor, nodeID types.NodeID, epoch types.EpochID) ([]types.BlockID, error) {
+	var ids []types.BlockID
+	if _, err := db.Exec(`select block_rewards.block from block_rewards
+		inner join atxs on atxs.id = block_rewards.atx
+		where atxs.pubkey = ?1 and block_rewards.layer between ?2 and ?3
+		order by block_rewards.layer;`, func(stmt *sql.Statement) {
+		stmt.BindBytes(1, nodeID.Bytes())
+		stmt.BindInt64(2, int64(epoch.FirstLayer()))
+		stmt.BindInt64(3, int64((epoch + 1).FirstLayer().Sub(1)))
+	}, func(stmt *sql.Statement) bool {
+		id := types.BlockID{}
+		stmt.ColumnBytes(0, id[:])
+		ids = append(ids, id)
+		return true
+	}); err != nil {
+		return nil, fmt.Errorf("blocks by %s in epoch %s: %w", nodeID, epoch, err)
+	}
+	return ids, nil
+}
+
+// CountDanglingRewards returns the number of block_rewards entries that reference missing blocks.
+func CountDanglingRewards(db sql.Executor) (int, error) {
+	var count int
+	if _, err := db.Exec(`select count(*) from block_rewards
+		where block not in (select id from blocks);`, nil, func(stmt *sql.Statement) bool {
+		count = stmt.ColumnInt(0)
+		return true
+	}); err != nil {
+		return 0, fmt.Errorf("count dangling rewards: %w", err)
+	}
+	return count, nil
+}
+
+// DeleteDanglingRewards deletes block_rewards entries that reference missing blocks.
+func DeleteDanglingRewards(db sql.Executor) error {
+	if _, err := db.Exec(`delete from block_rewards
+		where block not in (select id from blocks);`, nil, nil); err != nil {
+		return fmt.Errorf("delete dangling rewards: %w", err)
+	}
+	return nil
+}
@@ -3,11 +3,14 @@ package blocks
 import (
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/signing"
 	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/atxs"
 )
 
 func TestAddGet(t *testing.T) {
@@ -117,6 +120,14 @@ func TestLayerFilter(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, blks, 2)
 	require.ElementsMatch(t, blocks[:2], blks)
+
+	var iterated []*types.Block
+	require.NoError(t, IterateLayer(db, start, func(block *types.Block) bool {
+		iterated = append(iterated, block)
+		return false
+	}))
+	require.Len(t, iterated, 1)
+	require.Contains(t, blocks[:2], iterated[0])
 }
 
 func TestLayerOrdered(t *testing.T) {
@@ -217,3 +228,81 @@ func TestGetLayer(t *testing.T) {
 		require.Equal(t, b.LayerIndex, lid)
 	}
 }
+
+func TestIDsBySmesher(t *testing.T) {
+	types.SetLayersPerEpoch(3)
+	db := sql.InMemory()
+	epoch := types.EpochID(2)
+
+	var ids []types.ATXID
+	var nodes []types.NodeID
+	for i := 0; i < 2; i++ {
+		sig, err := signing.NewEdSigner()
+		require.NoError(t, err)
+		atx := &types.ActivationTx{InnerActivationTx: types.InnerActivationTx{
+			NIPostChallenge: types.NIPostChallenge{PublishEpoch: epoch - 1},
+			NumUnits:        1,
+		}}
+		atx.SetID(types.ATXID{byte(i + 1)})
+		atx.SmesherID = sig.NodeID()
+		atx.SetEffectiveNumUnits(atx.NumUnits)
+		atx.SetReceived(time.Now())
+		vatx, err := atx.Verify(0, 1)
+		require.NoError(t, err)
+		require.NoError(t, atxs.Add(db, vatx))
+		ids = append(ids, atx.ID())
+		nodes = append(nodes, sig.NodeID())
+	}
+
+	blocks := []*types.Block{
+		types.NewExistingBlock(types.BlockID{1}, types.InnerBlock{
+			LayerIndex: epoch.FirstLayer(),
+			Rewards:    []types.AnyReward{{AtxID: ids[0]}, {AtxID: ids[1]}},
+		}),
+		types.NewExistingBlock(types.BlockID{2}, types.InnerBlock{
+			LayerIndex: epoch.FirstLayer().Add(1),
+			Rewards:    []types.AnyReward{{AtxID: ids[1]}},
+		}),
+		types.NewExistingBlock(types.BlockID{3}, types.InnerBlock{
+			LayerIndex: (epoch + 1).FirstLayer(),
+			Rewards:    []types.AnyReward{{AtxID: ids[0]}},
+		}),
+	}
+	for _, block := range blocks {
+		require.NoError(t, Add(db, block))
+	}
+
+	got, err := IDsBySmesher(db, nodes[0], epoch)
+	require.NoError(t, err)
+	require.Equal(t, []types.BlockID{blocks[0].ID()}, got)
+	got, err = IDsBySmesher(db, nodes[1], epoch)
+	require.NoError(t, err)
+	require.Equal(t, []types.BlockID{blocks[0].ID(), blocks[1].ID()}, got)
+	got, err = IDsBySmesher(db, nodes[1], epoch+1)
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
+
+func TestDanglingRewards(t *testing.T) {
+	db := sql.InMemory()
+	block := types.NewExistingBlock(types.BlockID{1}, types.InnerBlock{
+		LayerIndex: types.LayerID(1),
+		Rewards:    []types.AnyReward{{AtxID: types.ATXID{1}}},
+	})
+	require.NoError(t, Add(db, block))
+
+	n, err := CountDanglingRewards(db)
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	_, err = db.Exec("delete from blocks;", nil, nil)
+	require.NoError(t, err)
+	n, err = CountDanglingRewards(db)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	require.NoError(t, DeleteDanglingRewards(db))
+	n, err = CountDanglingRewards(db)
+	require.NoError(t, err)
+	require.Zero(t, n)
+}
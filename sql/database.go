@@ -10,6 +10,8 @@ import (
 	"github.com/go-llsqlite/llsqlite"
 	"github.com/go-llsqlite/llsqlite/sqlitex"
 	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/spacemeshos/go-spacemesh/log"
 )
 
 var (
@@ -50,7 +52,8 @@ type Decoder func(*Statement) bool
 func defaultConf() *conf {
 	return &conf{
 		connections: 16,
-		migrations:  embeddedMigrations,
+		embedded:    true,
+		logger:      log.NewNop(),
 	}
 }
 
@@ -58,7 +61,9 @@ type conf struct {
 	flags         sqlite.OpenFlags
 	connections   int
 	migrations    Migrations
+	embedded      bool
 	enableLatency bool
+	logger        log.Log
 }
 
 // WithConnections overwrites number of pooled connections.
@@ -72,6 +77,14 @@ func WithConnections(n int) Opt {
 func WithMigrations(migrations Migrations) Opt {
 	return func(c *conf) {
 		c.migrations = migrations
+		c.embedded = false
+	}
+}
+
+// WithLogger defines logger for database, it is used to report progress of migrations.
+func WithLogger(logger log.Log) Opt {
+	return func(c *conf) {
+		c.logger = logger
 	}
 }
 
@@ -115,6 +128,11 @@ func Open(uri string, opts ...Opt) (*Database, error) {
 	if config.enableLatency {
 		db.latency = newQueryLatency()
 	}
+	if config.embedded {
+		config.migrations = func(db Executor) error {
+			return embeddedMigrations(db, config.logger)
+		}
+	}
 	if config.migrations != nil {
 		tx, err := db.Tx(context.Background())
 		if err != nil {
@@ -328,3 +346,37 @@ func (tx *Tx) Exec(query string, encoder Encoder, decoder Decoder) (int, error)
 	}
 	return exec(tx.conn, query, encoder, decoder)
 }
+
+// Size returns the number of bytes used by the database file and the number of bytes
+// in unused pages that can be reclaimed by Vacuum.
+func Size(db Executor) (total, free int64, err error) {
+	var pageSize, pages, freePages int64
+	for _, pragma := range []struct {
+		query string
+		value *int64
+	}{
+		{"PRAGMA page_size;", &pageSize},
+		{"PRAGMA page_count;", &pages},
+		{"PRAGMA freelist_count;", &freePages},
+	} {
+		if _, err := db.Exec(pragma.query, nil, func(stmt *Statement) bool {
+			*pragma.value = stmt.ColumnInt64(0)
+			return true
+		}); err != nil {
+			return 0, 0, fmt.Errorf("%s %w", pragma.query, err)
+		}
+	}
+	return pages * pageSize, freePages * pageSize, nil
+}
+
+// Vacuum rebuilds the database file, returning unused pages to the filesystem.
+// It can't be executed within a transaction and requires exclusive access to the database
+// while it runs.
+//
+// https://www.sqlite.org/lang_vacuum.html
+func Vacuum(db *Database) error {
+	if _, err := db.Exec("VACUUM;", nil, nil); err != nil {
+		return fmt.Errorf("vacuum %w", err)
+	}
+	return nil
+}
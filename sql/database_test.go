@@ -2,6 +2,7 @@ package sql
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -48,3 +49,29 @@ func TestTransactionIsolation(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, rows, 0)
 }
+
+func TestVacuum(t *testing.T) {
+	db := InMemory(WithMigrations(testTables))
+	for i := 0; i < 1000; i++ {
+		_, err := db.Exec("insert into testing1(id, field) values (?1, ?2)", func(stmt *Statement) {
+			stmt.BindText(1, fmt.Sprintf("%0100d", i))
+			stmt.BindInt64(2, int64(i))
+		}, nil)
+		require.NoError(t, err)
+	}
+	_, free, err := Size(db)
+	require.NoError(t, err)
+	require.Zero(t, free)
+
+	_, err = db.Exec("delete from testing1", nil, nil)
+	require.NoError(t, err)
+	before, free, err := Size(db)
+	require.NoError(t, err)
+	require.NotZero(t, free)
+
+	require.NoError(t, Vacuum(db))
+	after, free, err := Size(db)
+	require.NoError(t, err)
+	require.Zero(t, free)
+	require.Less(t, after, before)
+}
@@ -0,0 +1,89 @@
+package hareparticipation
+
+import (
+	"fmt"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/sql"
+)
+
+// Record describes whether the identity was eligible in the hare round and whether
+// it published a message. Reason explains why the identity didn't participate.
+type Record struct {
+	Layer        types.LayerID `json:"layer"`
+	Round        uint32        `json:"round"`
+	NodeID       types.NodeID  `json:"-"`
+	Eligibility  uint16        `json:"eligibility"`
+	Participated bool          `json:"participated"`
+	Reason       string        `json:"reason,omitempty"`
+}
+
+// Add records the eligibility of the identity in the round, replacing the previous record.
+func Add(db sql.Executor, rec *Record) error {
+	if _, err := db.Exec(`insert into hare_participation (layer, round, pubkey, eligibility, participated, reason)
+		values (?1, ?2, ?3, ?4, ?5, ?6)
+		on conflict do update set eligibility = ?4, participated = ?5, reason = ?6;`,
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(rec.Layer))
+			stmt.BindInt64(2, int64(rec.Round))
+			stmt.BindBytes(3, rec.NodeID.Bytes())
+			stmt.BindInt64(4, int64(rec.Eligibility))
+			stmt.BindBool(5, rec.Participated)
+			stmt.BindText(6, rec.Reason)
+		}, nil); err != nil {
+		return fmt.Errorf("add hare participation %s/%d: %w", rec.Layer, rec.Round, err)
+	}
+	return nil
+}
+
+// SetParticipated marks that the identity published a message in the round.
+func SetParticipated(db sql.Executor, lid types.LayerID, round uint32, id types.NodeID) error {
+	if rows, err := db.Exec(`update hare_participation set participated = 1, reason = ''
+		where layer = ?1 and round = ?2 and pubkey = ?3 returning layer;`,
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(lid))
+			stmt.BindInt64(2, int64(round))
+			stmt.BindBytes(3, id.Bytes())
+		}, nil); err != nil {
+		return fmt.Errorf("set hare participation %s/%d: %w", lid, round, err)
+	} else if rows == 0 {
+		return fmt.Errorf("set hare participation %s/%d: %w", lid, round, sql.ErrNotFound)
+	}
+	return nil
+}
+
+// List returns records for layers in the inclusive range, ordered by layer and round.
+func List(db sql.Executor, from, to types.LayerID) ([]Record, error) {
+	var rst []Record
+	if _, err := db.Exec(`select layer, round, pubkey, eligibility, participated, reason from hare_participation
+		where layer between ?1 and ?2 order by layer, round, pubkey;`,
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(from))
+			stmt.BindInt64(2, int64(to))
+		}, func(stmt *sql.Statement) bool {
+			rec := Record{
+				Layer:        types.LayerID(stmt.ColumnInt64(0)),
+				Round:        uint32(stmt.ColumnInt64(1)),
+				Eligibility:  uint16(stmt.ColumnInt64(3)),
+				Participated: stmt.ColumnInt(4) != 0,
+				Reason:       stmt.ColumnText(5),
+			}
+			stmt.ColumnBytes(2, rec.NodeID[:])
+			rst = append(rst, rec)
+			return true
+		}); err != nil {
+		return nil, fmt.Errorf("list hare participation %s-%s: %w", from, to, err)
+	}
+	return rst, nil
+}
+
+// Prune removes records for layers before lid.
+func Prune(db sql.Executor, lid types.LayerID) error {
+	if _, err := db.Exec("delete from hare_participation where layer < ?1;",
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(lid))
+		}, nil); err != nil {
+		return fmt.Errorf("prune hare participation %s: %w", lid, err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package hareparticipation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/sql"
+)
+
+func TestParticipation(t *testing.T) {
+	db := sql.InMemory()
+	lid := types.LayerID(10)
+	id := types.RandomNodeID()
+
+	require.NoError(t, Add(db, &Record{Layer: lid, Round: 0, NodeID: id, Eligibility: 2}))
+	require.NoError(t, Add(db, &Record{Layer: lid, Round: 1, NodeID: id, Reason: "not eligible"}))
+	require.NoError(t, Add(db, &Record{Layer: lid.Add(1), Round: 0, NodeID: id, Reason: "not active"}))
+	// evaluated again in the same round
+	require.NoError(t, Add(db, &Record{Layer: lid.Add(1), Round: 0, NodeID: id, Eligibility: 1}))
+
+	require.NoError(t, SetParticipated(db, lid, 0, id))
+	require.NoError(t, SetParticipated(db, lid.Add(1), 0, id))
+	require.ErrorIs(t, SetParticipated(db, lid, 2, id), sql.ErrNotFound)
+
+	recs, err := List(db, lid, lid)
+	require.NoError(t, err)
+	require.Equal(t, []Record{
+		{Layer: lid, Round: 0, NodeID: id, Eligibility: 2, Participated: true},
+		{Layer: lid, Round: 1, NodeID: id, Reason: "not eligible"},
+	}, recs)
+
+	recs, err = List(db, lid, lid.Add(5))
+	require.NoError(t, err)
+	require.Len(t, recs, 3)
+	require.Equal(t, Record{Layer: lid.Add(1), Round: 0, NodeID: id, Eligibility: 1, Participated: true}, recs[2])
+
+	recs, err = List(db, lid.Add(2), lid.Add(5))
+	require.NoError(t, err)
+	require.Empty(t, recs)
+}
+
+func TestPrune(t *testing.T) {
+	db := sql.InMemory()
+	lid := types.LayerID(10)
+	id := types.RandomNodeID()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, Add(db, &Record{Layer: lid.Add(uint32(i)), NodeID: id}))
+	}
+
+	require.NoError(t, Prune(db, lid.Add(1)))
+	recs, err := List(db, lid, lid.Add(2))
+	require.NoError(t, err)
+	require.Equal(t, []Record{
+		{Layer: lid.Add(1), NodeID: id},
+		{Layer: lid.Add(2), NodeID: id},
+	}, recs)
+}
@@ -0,0 +1,73 @@
+package harewal
+
+import (
+	"fmt"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/sql"
+)
+
+// Add records an encoded hare message for the layer. sent is true for messages
+// published by this node.
+func Add(db sql.Executor, lid types.LayerID, round uint32, sent bool, msg []byte) error {
+	id := types.CalcHash32(msg)
+	if _, err := db.Exec(`insert into hare_wal (layer, id, round, sent, msg) values (?1, ?2, ?3, ?4, ?5)
+		on conflict do update set sent = sent or ?4;`,
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(lid))
+			stmt.BindBytes(2, id[:])
+			stmt.BindInt64(3, int64(round))
+			stmt.BindBool(4, sent)
+			stmt.BindBytes(5, msg)
+		}, nil); err != nil {
+		return fmt.Errorf("add hare wal %s/%d: %w", lid, round, err)
+	}
+	return nil
+}
+
+// Layer returns all messages recorded for the layer in the order they were added.
+func Layer(db sql.Executor, lid types.LayerID) ([][]byte, error) {
+	var rst [][]byte
+	if _, err := db.Exec("select msg from hare_wal where layer = ?1 order by rowid asc;",
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(lid))
+		}, func(stmt *sql.Statement) bool {
+			msg := make([]byte, stmt.ColumnLen(0))
+			stmt.ColumnBytes(0, msg)
+			rst = append(rst, msg)
+			return true
+		}); err != nil {
+		return nil, fmt.Errorf("hare wal layer %s: %w", lid, err)
+	}
+	return rst, nil
+}
+
+// Sent returns the message published by this node in the round.
+func Sent(db sql.Executor, lid types.LayerID, round uint32) ([]byte, error) {
+	var msg []byte
+	if rows, err := db.Exec("select msg from hare_wal where layer = ?1 and round = ?2 and sent = 1 limit 1;",
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(lid))
+			stmt.BindInt64(2, int64(round))
+		}, func(stmt *sql.Statement) bool {
+			msg = make([]byte, stmt.ColumnLen(0))
+			stmt.ColumnBytes(0, msg)
+			return false
+		}); err != nil {
+		return nil, fmt.Errorf("hare wal sent %s/%d: %w", lid, round, err)
+	} else if rows == 0 {
+		return nil, fmt.Errorf("hare wal sent %s/%d: %w", lid, round, sql.ErrNotFound)
+	}
+	return msg, nil
+}
+
+// Prune removes messages for layers up to and including lid.
+func Prune(db sql.Executor, lid types.LayerID) error {
+	if _, err := db.Exec("delete from hare_wal where layer <= ?1;",
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(lid))
+		}, nil); err != nil {
+		return fmt.Errorf("prune hare wal %s: %w", lid, err)
+	}
+	return nil
+}
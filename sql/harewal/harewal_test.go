@@ -0,0 +1,43 @@
+package harewal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/sql"
+)
+
+func TestWAL(t *testing.T) {
+	db := sql.InMemory()
+	lid := types.LayerID(10)
+
+	require.NoError(t, Add(db, lid, 0, false, []byte("received")))
+	require.NoError(t, Add(db, lid, 0, true, []byte("sent")))
+	require.NoError(t, Add(db, lid, 1, false, []byte("own")))
+	// own message delivered back by gossip after it was recorded as received
+	require.NoError(t, Add(db, lid, 1, true, []byte("own")))
+	require.NoError(t, Add(db, lid.Add(1), 0, false, []byte("next")))
+
+	msgs, err := Layer(db, lid)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{[]byte("received"), []byte("sent"), []byte("own")}, msgs)
+
+	msg, err := Sent(db, lid, 0)
+	require.NoError(t, err)
+	require.Equal(t, []byte("sent"), msg)
+	msg, err = Sent(db, lid, 1)
+	require.NoError(t, err)
+	require.Equal(t, []byte("own"), msg)
+	_, err = Sent(db, lid, 2)
+	require.ErrorIs(t, err, sql.ErrNotFound)
+
+	require.NoError(t, Prune(db, lid))
+	msgs, err = Layer(db, lid)
+	require.NoError(t, err)
+	require.Empty(t, msgs)
+	msgs, err = Layer(db, lid.Add(1))
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+}
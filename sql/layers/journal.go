@@ -0,0 +1,77 @@
+package layers
+
+import (
+	"fmt"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/sql"
+)
+
+// JournalEntry is written before the layer is applied to the state and removed
+// once the layer is recorded as applied in the mesh.
+type JournalEntry struct {
+	Layer types.LayerID
+	Block types.BlockID
+	TxIDs []types.TransactionID
+}
+
+// AddJournal records that the layer is being applied.
+func AddJournal(db sql.Executor, entry *JournalEntry) error {
+	txs := make([]byte, 0, len(entry.TxIDs)*types.TransactionIDSize)
+	for _, tid := range entry.TxIDs {
+		txs = append(txs, tid[:]...)
+	}
+	if _, err := db.Exec(`insert into apply_journal (layer, block, txs) values (?1, ?2, ?3)
+		on conflict(layer) do update set block = ?2, txs = ?3;`,
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(entry.Layer))
+			stmt.BindBytes(2, entry.Block[:])
+			stmt.BindBytes(3, txs)
+		}, nil); err != nil {
+		return fmt.Errorf("add journal %s: %w", entry.Layer, err)
+	}
+	return nil
+}
+
+// GetJournal returns the lowest layer that was not completely applied.
+func GetJournal(db sql.Executor) (*JournalEntry, error) {
+	var entry *JournalEntry
+	if _, err := db.Exec("select layer, block, txs from apply_journal order by layer asc limit 1;", nil,
+		func(stmt *sql.Statement) bool {
+			entry = &JournalEntry{Layer: types.LayerID(uint32(stmt.ColumnInt64(0)))}
+			stmt.ColumnBytes(1, entry.Block[:])
+			txs := make([]byte, stmt.ColumnLen(2))
+			stmt.ColumnBytes(2, txs)
+			for i := 0; i+types.TransactionIDSize <= len(txs); i += types.TransactionIDSize {
+				var tid types.TransactionID
+				copy(tid[:], txs[i:])
+				entry.TxIDs = append(entry.TxIDs, tid)
+			}
+			return false
+		}); err != nil {
+		return nil, fmt.Errorf("get journal: %w", err)
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("%w: apply journal is empty", sql.ErrNotFound)
+	}
+	return entry, nil
+}
+
+// DeleteJournal removes journal entries up to and including the layer.
+func DeleteJournal(db sql.Executor, lid types.LayerID) error {
+	if _, err := db.Exec("delete from apply_journal where layer <= ?1;",
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(lid))
+		}, nil); err != nil {
+		return fmt.Errorf("delete journal %s: %w", lid, err)
+	}
+	return nil
+}
+
+// ClearJournal removes all journal entries.
+func ClearJournal(db sql.Executor) error {
+	if _, err := db.Exec("delete from apply_journal;", nil, nil); err != nil {
+		return fmt.Errorf("clear journal: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package layers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/sql"
+)
+
+func TestJournal(t *testing.T) {
+	db := sql.InMemory()
+	_, err := GetJournal(db)
+	require.ErrorIs(t, err, sql.ErrNotFound)
+
+	entries := []*JournalEntry{
+		{Layer: 11, Block: types.BlockID{1}, TxIDs: []types.TransactionID{{1}, {2}}},
+		{Layer: 12},
+	}
+	for _, entry := range entries {
+		require.NoError(t, AddJournal(db, entry))
+	}
+	got, err := GetJournal(db)
+	require.NoError(t, err)
+	require.Equal(t, entries[0], got)
+
+	entries[0].TxIDs = []types.TransactionID{{3}}
+	require.NoError(t, AddJournal(db, entries[0]))
+	got, err = GetJournal(db)
+	require.NoError(t, err)
+	require.Equal(t, entries[0], got)
+
+	require.NoError(t, DeleteJournal(db, 11))
+	got, err = GetJournal(db)
+	require.NoError(t, err)
+	require.Equal(t, entries[1], got)
+
+	require.NoError(t, ClearJournal(db))
+	_, err = GetJournal(db)
+	require.ErrorIs(t, err, sql.ErrNotFound)
+}
@@ -9,23 +9,56 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/spacemeshos/go-spacemesh/log"
 )
 
 //go:embed migrations/*.sql
 var embedded embed.FS
 
-type migration struct {
-	order   int
-	name    string
-	content *bufio.Scanner
+// Migration is a single versioned step that brings schema or data to the next version.
+type Migration struct {
+	// Order is a version of the database after migration is applied.
+	Order int
+	Name  string
+	Apply func(Executor, log.Log) error
 }
 
 // Migrations is interface for migrations provider.
 type Migrations func(Executor) error
 
-func embeddedMigrations(db Executor) error {
-	var migrations []migration
-	fs.WalkDir(embedded, "migrations", func(path string, d fs.DirEntry, err error) error {
+// codeMigrations are migrations that can't be expressed in sql, such as re-encodings.
+// They are ordered together with embedded sql migrations.
+var codeMigrations = []Migration{
+	{Order: 4, Name: "0004_backfill_block_rewards", Apply: backfillBlockRewards},
+}
+
+func sqlMigration(order int, name string, content []byte) Migration {
+	return Migration{
+		Order: order,
+		Name:  name,
+		Apply: func(db Executor, _ log.Log) error {
+			scanner := bufio.NewScanner(bytes.NewReader(content))
+			scanner.Split(func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+				if i := bytes.Index(data, []byte(";")); i >= 0 {
+					return i + 1, data[0 : i+1], nil
+				}
+				return 0, nil, nil
+			})
+			for scanner.Scan() {
+				if _, err := db.Exec(scanner.Text(), nil, nil); err != nil {
+					return fmt.Errorf("exec %s: %w", scanner.Text(), err)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func loadEmbedded() ([]Migration, error) {
+	var migrations []Migration
+	if err := fs.WalkDir(embedded, "migrations", func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return fmt.Errorf("walkdir %s: %w", path, err)
 		}
@@ -40,30 +73,42 @@ func embeddedMigrations(db Executor) error {
 		if err != nil {
 			return fmt.Errorf("invalid migration %s: %w", d.Name(), err)
 		}
-		f, err := embedded.Open(path)
+		content, err := embedded.ReadFile(path)
 		if err != nil {
 			return fmt.Errorf("readfile %s: %w", path, err)
 		}
-		scanner := bufio.NewScanner(f)
-		scanner.Split(func(data []byte, atEOF bool) (advance int, token []byte, err error) {
-			if i := bytes.Index(data, []byte(";")); i >= 0 {
-				return i + 1, data[0 : i+1], nil
-			}
-			return 0, nil, nil
-		})
-		migrations = append(migrations, migration{
-			order:   order,
-			name:    d.Name(),
-			content: scanner,
-		})
+		migrations = append(migrations, sqlMigration(order, d.Name(), content))
 		return nil
-	})
+	}); err != nil {
+		return nil, err
+	}
+	return migrations, nil
+}
+
+func embeddedMigrations(db Executor, logger log.Log) error {
+	migrations, err := loadEmbedded()
+	if err != nil {
+		return err
+	}
+	return applyMigrations(db, logger, append(migrations, codeMigrations...))
+}
+
+// applyMigrations applies migrations with order higher than the version stored
+// in the database and updates the version after each of them.
+// It is expected to be executed in a transaction, so that failed migration
+// doesn't leave database in a partially migrated state.
+func applyMigrations(db Executor, logger log.Log, migrations []Migration) error {
 	sort.Slice(migrations, func(i, j int) bool {
-		return migrations[i].order < migrations[j].order
+		return migrations[i].Order < migrations[j].Order
 	})
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Order == migrations[i-1].Order {
+			return fmt.Errorf("migrations %s and %s have the same order %d",
+				migrations[i-1].Name, migrations[i].Name, migrations[i].Order)
+		}
+	}
 
 	var current int
-
 	if _, err := db.Exec("PRAGMA user_version;", nil, func(stmt *Statement) bool {
 		current = stmt.ColumnInt(0)
 		return true
@@ -72,18 +117,31 @@ func embeddedMigrations(db Executor) error {
 	}
 
 	for _, m := range migrations {
-		if m.order <= current {
+		if m.Order <= current {
 			continue
 		}
-		for m.content.Scan() {
-			if _, err := db.Exec(m.content.Text(), nil, nil); err != nil {
-				return fmt.Errorf("exec %s: %w", m.content.Text(), err)
-			}
+		logger.With().Info("applying migration",
+			log.String("name", m.Name),
+			log.Int("from", current),
+			log.Int("to", m.Order),
+		)
+		start := time.Now()
+		if err := m.Apply(db, logger); err != nil {
+			logger.With().Error("migration failed, changes will be rolled back",
+				log.String("name", m.Name),
+				log.Err(err),
+			)
+			return fmt.Errorf("apply %s: %w", m.Name, err)
 		}
 		// binding values in pragma statement is not allowed
-		if _, err := db.Exec(fmt.Sprintf("PRAGMA user_version = %d;", m.order), nil, nil); err != nil {
-			return fmt.Errorf("update user_version to %d: %w", m.order, err)
+		if _, err := db.Exec(fmt.Sprintf("PRAGMA user_version = %d;", m.Order), nil, nil); err != nil {
+			return fmt.Errorf("update user_version to %d: %w", m.Order, err)
 		}
+		logger.With().Info("migration applied",
+			log.String("name", m.Name),
+			log.Duration("duration", time.Since(start)),
+		)
+		current = m.Order
 	}
 	return nil
 }
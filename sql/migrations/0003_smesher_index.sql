@@ -0,0 +1,10 @@
+CREATE INDEX ballots_by_pubkey_by_layer ON ballots (pubkey, layer);
+
+CREATE TABLE block_rewards
+(
+    atx   CHAR(32) NOT NULL,
+    block CHAR(20) NOT NULL,
+    layer INT NOT NULL,
+    PRIMARY KEY (atx, block)
+) WITHOUT ROWID;
+CREATE INDEX block_rewards_by_layer ON block_rewards (layer);
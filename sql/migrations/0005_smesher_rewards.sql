@@ -0,0 +1,10 @@
+CREATE TABLE smesher_rewards
+(
+    pubkey       CHAR(32) NOT NULL,
+    layer        INT NOT NULL,
+    coinbase     CHAR(24) NOT NULL,
+    total_reward UNSIGNED LONG INT,
+    layer_reward UNSIGNED LONG INT,
+    PRIMARY KEY (pubkey, layer)
+) WITHOUT ROWID;
+CREATE INDEX smesher_rewards_by_layer ON smesher_rewards (layer asc);
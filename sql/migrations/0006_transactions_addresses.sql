@@ -0,0 +1,16 @@
+CREATE TABLE transactions_addresses
+(
+    address   CHAR(24) NOT NULL,
+    tid       CHAR(32) NOT NULL,
+    direction INT NOT NULL,
+    PRIMARY KEY (address, tid)
+) WITHOUT ROWID;
+CREATE INDEX transactions_addresses_by_tid ON transactions_addresses (tid);
+INSERT INTO transactions_addresses (address, tid, direction)
+    SELECT principal, id, 1 FROM transactions WHERE principal IS NOT NULL
+    ON CONFLICT DO NOTHING;
+INSERT INTO transactions_addresses (address, tid, direction)
+    SELECT tra.address, tra.tid, 2 FROM transactions_results_addresses tra
+    INNER JOIN transactions t ON t.id = tra.tid
+    WHERE t.principal IS NULL OR tra.address != t.principal
+    ON CONFLICT DO NOTHING;
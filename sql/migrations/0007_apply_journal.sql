@@ -0,0 +1,6 @@
+CREATE TABLE apply_journal
+(
+    layer INT NOT NULL PRIMARY KEY,
+    block CHAR(20) NOT NULL,
+    txs   BLOB
+);
@@ -0,0 +1,9 @@
+CREATE TABLE hare_wal
+(
+    layer INT      NOT NULL,
+    id    CHAR(32) NOT NULL,
+    round INT      NOT NULL,
+    sent  BOOL     NOT NULL,
+    msg   BLOB     NOT NULL,
+    PRIMARY KEY (layer, id)
+);
@@ -0,0 +1,10 @@
+CREATE TABLE hare_participation
+(
+    layer        INT      NOT NULL,
+    round        INT      NOT NULL,
+    pubkey       CHAR(32) NOT NULL,
+    eligibility  INT      NOT NULL,
+    participated BOOL     NOT NULL,
+    reason       TEXT     NOT NULL,
+    PRIMARY KEY (layer, round, pubkey)
+);
@@ -0,0 +1 @@
+CREATE INDEX atxs_by_epoch_by_id ON atxs (epoch, id);
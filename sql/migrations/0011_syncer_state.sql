@@ -0,0 +1,11 @@
+CREATE TABLE syncer_state
+(
+    key   TEXT PRIMARY KEY,
+    value INT NOT NULL
+) WITHOUT ROWID;
+
+CREATE TABLE syncer_outstanding_atxs
+(
+    id    CHAR(32) PRIMARY KEY,
+    epoch INT NOT NULL
+) WITHOUT ROWID;
@@ -0,0 +1,5 @@
+CREATE TABLE accounts_pruned
+(
+    id    INT PRIMARY KEY,
+    layer INT NOT NULL
+) WITHOUT ROWID;
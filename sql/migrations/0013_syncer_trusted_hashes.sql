@@ -0,0 +1,5 @@
+CREATE TABLE syncer_trusted_hashes
+(
+    layer           INT PRIMARY KEY,
+    aggregated_hash CHAR(32) NOT NULL
+) WITHOUT ROWID;
@@ -0,0 +1,82 @@
+package sql
+
+import (
+	"fmt"
+
+	"github.com/spacemeshos/go-spacemesh/codec"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log"
+)
+
+// number of layers that are decoded together by backfilling migrations.
+const migrationBatchLayers = 1000
+
+type blockRewards struct {
+	id      types.BlockID
+	layer   types.LayerID
+	rewards []types.AnyReward
+}
+
+// backfillBlockRewards populates block_rewards index for blocks that were
+// stored before the index was introduced.
+func backfillBlockRewards(db Executor, logger log.Log) error {
+	var last types.LayerID
+	if _, err := db.Exec("select max(layer) from blocks;", nil, func(stmt *Statement) bool {
+		last = types.LayerID(stmt.ColumnInt64(0))
+		return true
+	}); err != nil {
+		return fmt.Errorf("max layer: %w", err)
+	}
+	total := 0
+	for from := types.LayerID(0); !from.After(last); from = from.Add(migrationBatchLayers) {
+		var (
+			batch   []blockRewards
+			decoded error
+		)
+		if _, err := db.Exec(`select id, layer, block from blocks
+			where layer >= ?1 and layer < ?2 and block is not null;`, func(stmt *Statement) {
+			stmt.BindInt64(1, int64(from))
+			stmt.BindInt64(2, int64(from.Add(migrationBatchLayers)))
+		}, func(stmt *Statement) bool {
+			var (
+				item  blockRewards
+				inner types.InnerBlock
+			)
+			stmt.ColumnBytes(0, item.id[:])
+			item.layer = types.LayerID(stmt.ColumnInt64(1))
+			if _, decoded = codec.DecodeFrom(stmt.ColumnReader(2), &inner); decoded != nil {
+				decoded = fmt.Errorf("decode block %s: %w", item.id, decoded)
+				return false
+			}
+			item.rewards = inner.Rewards
+			batch = append(batch, item)
+			return true
+		}); err != nil {
+			return fmt.Errorf("blocks from %s: %w", from, err)
+		}
+		if decoded != nil {
+			return decoded
+		}
+		for _, item := range batch {
+			for _, reward := range item.rewards {
+				if _, err := db.Exec(`insert into block_rewards (atx, block, layer) values (?1, ?2, ?3)
+					on conflict do nothing;`, func(stmt *Statement) {
+					stmt.BindBytes(1, reward.AtxID.Bytes())
+					stmt.BindBytes(2, item.id.Bytes())
+					stmt.BindInt64(3, int64(item.layer))
+				}, nil); err != nil {
+					return fmt.Errorf("insert reward %s for %s: %w", reward.AtxID, item.id, err)
+				}
+			}
+		}
+		total += len(batch)
+		if len(batch) > 0 {
+			logger.With().Info("backfilled block rewards",
+				log.Stringer("up_to", types.MinLayer(last, from.Add(migrationBatchLayers-1))),
+				log.Stringer("last", last),
+				log.Int("blocks", total),
+			)
+		}
+	}
+	return nil
+}
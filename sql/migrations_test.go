@@ -1,9 +1,16 @@
 package sql
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/codec"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
 )
 
 func TestMigrationsAppliedOnce(t *testing.T) {
@@ -15,5 +22,72 @@ func TestMigrationsAppliedOnce(t *testing.T) {
 		return true
 	})
 	require.NoError(t, err)
-	require.Equal(t, version, 2)
+	require.Equal(t, version, 13)
+}
+
+func TestApplyMigrations(t *testing.T) {
+	db := InMemory(WithMigrations(nil))
+	var applied []int
+	migration := func(order int, err error) Migration {
+		return Migration{
+			Order: order,
+			Name:  fmt.Sprintf("%04d_test", order),
+			Apply: func(Executor, log.Log) error {
+				applied = append(applied, order)
+				return err
+			},
+		}
+	}
+	require.NoError(t, applyMigrations(db, logtest.New(t), []Migration{
+		migration(2, nil), migration(1, nil),
+	}))
+	require.Equal(t, []int{1, 2}, applied)
+
+	applied = nil
+	require.NoError(t, applyMigrations(db, logtest.New(t), []Migration{
+		migration(2, nil), migration(1, nil), migration(3, nil),
+	}))
+	require.Equal(t, []int{3}, applied)
+
+	applied = nil
+	require.ErrorContains(t, applyMigrations(db, logtest.New(t), []Migration{
+		migration(4, nil), migration(4, nil),
+	}), "same order")
+	require.Empty(t, applied)
+
+	failure := errors.New("test")
+	require.ErrorIs(t, applyMigrations(db, logtest.New(t), []Migration{
+		migration(4, failure), migration(5, nil),
+	}), failure)
+	require.Equal(t, []int{4}, applied)
+}
+
+func TestBackfillBlockRewards(t *testing.T) {
+	db := InMemory()
+	atxs := []types.ATXID{{1}, {2}}
+	inner := []types.InnerBlock{
+		{LayerIndex: 1, Rewards: []types.AnyReward{{AtxID: atxs[0]}, {AtxID: atxs[1]}}},
+		{LayerIndex: migrationBatchLayers + 1, Rewards: []types.AnyReward{{AtxID: atxs[1]}}},
+	}
+	for i := range inner {
+		buf, err := codec.Encode(&inner[i])
+		require.NoError(t, err)
+		_, err = db.Exec("insert into blocks (id, layer, block) values (?1, ?2, ?3);", func(stmt *Statement) {
+			stmt.BindBytes(1, []byte{byte(i + 1)})
+			stmt.BindInt64(2, int64(inner[i].LayerIndex))
+			stmt.BindBytes(3, buf)
+		}, nil)
+		require.NoError(t, err)
+	}
+	require.NoError(t, backfillBlockRewards(db, logtest.New(t)))
+
+	count := func(atx types.ATXID) int {
+		rows, err := db.Exec("select 1 from block_rewards where atx = ?1;", func(stmt *Statement) {
+			stmt.BindBytes(1, atx.Bytes())
+		}, nil)
+		require.NoError(t, err)
+		return rows
+	}
+	require.Equal(t, 1, count(atxs[0]))
+	require.Equal(t, 2, count(atxs[1]))
 }
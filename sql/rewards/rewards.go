@@ -26,6 +26,26 @@ func Add(db sql.Executor, reward *types.Reward) error {
 	return nil
 }
 
+// AddSmesher records reward earned by the smesher.
+func AddSmesher(db sql.Executor, smesher types.NodeID, reward *types.Reward) error {
+	if _, err := db.Exec(`
+		insert into smesher_rewards (pubkey, layer, coinbase, total_reward, layer_reward) values (?1, ?2, ?3, ?4, ?5)
+		on conflict(pubkey, layer)
+			do update set
+				total_reward=add_uint64(total_reward, ?4),
+				layer_reward=add_uint64(layer_reward, ?5);`,
+		func(stmt *sql.Statement) {
+			stmt.BindBytes(1, smesher.Bytes())
+			stmt.BindInt64(2, int64(reward.Layer.Uint32()))
+			stmt.BindBytes(3, reward.Coinbase[:])
+			stmt.BindInt64(4, int64(reward.TotalReward))
+			stmt.BindInt64(5, int64(reward.LayerReward))
+		}, nil); err != nil {
+		return fmt.Errorf("insert smesher %s reward %+x: %w", smesher, reward, err)
+	}
+	return nil
+}
+
 // Revert the rewards to the specified layer.
 func Revert(db sql.Executor, revertTo types.LayerID) error {
 	if _, err := db.Exec(`delete from rewards where layer > ?1;`,
@@ -34,6 +54,12 @@ func Revert(db sql.Executor, revertTo types.LayerID) error {
 		}, nil); err != nil {
 		return fmt.Errorf("revert %v: %w", revertTo, err)
 	}
+	if _, err := db.Exec(`delete from smesher_rewards where layer > ?1;`,
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(revertTo.Uint32()))
+		}, nil); err != nil {
+		return fmt.Errorf("revert smesher rewards %v: %w", revertTo, err)
+	}
 	return nil
 }
 
@@ -54,3 +80,66 @@ func List(db sql.Executor, coinbase types.Address) (rst []*types.Reward, err err
 		})
 	return
 }
+
+// ListByLayers returns at most limit rewards for the coinbase address within the layers [from, to],
+// ordered by layer.
+func ListByLayers(db sql.Executor, coinbase types.Address, from, to types.LayerID, limit int) (rst []*types.Reward, err error) {
+	if _, err := db.Exec(`select layer, total_reward, layer_reward from rewards
+		where coinbase = ?1 and layer between ?2 and ?3 order by layer limit ?4;`,
+		func(stmt *sql.Statement) {
+			stmt.BindBytes(1, coinbase[:])
+			stmt.BindInt64(2, int64(from))
+			stmt.BindInt64(3, int64(to))
+			stmt.BindInt64(4, int64(limit))
+		}, func(stmt *sql.Statement) bool {
+			rst = append(rst, &types.Reward{
+				Coinbase:    coinbase,
+				Layer:       types.LayerID(uint32(stmt.ColumnInt64(0))),
+				TotalReward: uint64(stmt.ColumnInt64(1)),
+				LayerReward: uint64(stmt.ColumnInt64(2)),
+			})
+			return true
+		}); err != nil {
+		return nil, fmt.Errorf("list rewards for %s within [%s, %s]: %w", coinbase, from, to, err)
+	}
+	return rst, nil
+}
+
+// CountBySmesher returns the number of layers where the smesher earned rewards.
+func CountBySmesher(db sql.Executor, smesher types.NodeID) (int, error) {
+	var count int
+	if _, err := db.Exec("select count(*) from smesher_rewards where pubkey = ?1;",
+		func(stmt *sql.Statement) {
+			stmt.BindBytes(1, smesher.Bytes())
+		}, func(stmt *sql.Statement) bool {
+			count = stmt.ColumnInt(0)
+			return true
+		}); err != nil {
+		return 0, fmt.Errorf("count rewards for %s: %w", smesher, err)
+	}
+	return count, nil
+}
+
+// ListBySmesher returns at most limit rewards earned by the smesher, ordered by layer
+// and starting from offset.
+func ListBySmesher(db sql.Executor, smesher types.NodeID, offset, limit int) (rst []*types.Reward, err error) {
+	if _, err := db.Exec(`select layer, coinbase, total_reward, layer_reward from smesher_rewards
+		where pubkey = ?1 order by layer limit ?2 offset ?3;`,
+		func(stmt *sql.Statement) {
+			stmt.BindBytes(1, smesher.Bytes())
+			stmt.BindInt64(2, int64(limit))
+			stmt.BindInt64(3, int64(offset))
+		}, func(stmt *sql.Statement) bool {
+			reward := &types.Reward{
+				Layer:       types.LayerID(uint32(stmt.ColumnInt64(0))),
+				TotalReward: uint64(stmt.ColumnInt64(2)),
+				LayerReward: uint64(stmt.ColumnInt64(3)),
+			}
+			stmt.ColumnBytes(1, reward.Coinbase[:])
+			rst = append(rst, reward)
+			return true
+		}); err != nil {
+		return nil, fmt.Errorf("list rewards for %s: %w", smesher, err)
+	}
+	return rst, nil
+}
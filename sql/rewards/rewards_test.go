@@ -72,6 +72,15 @@ func TestRewards(t *testing.T) {
 	require.Equal(t, part, got[1].TotalReward)
 	require.Equal(t, lyrReward, got[1].LayerReward)
 
+	got, err = ListByLayers(db, coinbase2, lid2, lid2.Add(10), 10)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	require.Equal(t, lid2, got[0].Layer)
+	got, err = ListByLayers(db, coinbase2, lid1, lid2, 1)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	require.Equal(t, lid1, got[0].Layer)
+
 	unknownAddr := types.Address{1, 2, 3}
 	got, err = List(db, unknownAddr)
 	require.NoError(t, err)
@@ -94,3 +103,37 @@ func TestRewards(t *testing.T) {
 	require.Equal(t, part, got[0].TotalReward)
 	require.Equal(t, lyrReward, got[0].LayerReward)
 }
+
+func TestSmesherRewards(t *testing.T) {
+	db := sql.InMemory()
+	smesher := types.RandomNodeID()
+	coinbase := types.Address{1}
+	for i := 1; i <= 5; i++ {
+		require.NoError(t, AddSmesher(db, smesher, &types.Reward{
+			Layer:       types.LayerID(uint32(i)),
+			Coinbase:    coinbase,
+			TotalReward: uint64(i * 10),
+			LayerReward: uint64(i),
+		}))
+	}
+	require.NoError(t, AddSmesher(db, types.RandomNodeID(), &types.Reward{Layer: 1, Coinbase: coinbase}))
+
+	count, err := CountBySmesher(db, smesher)
+	require.NoError(t, err)
+	require.Equal(t, 5, count)
+
+	got, err := ListBySmesher(db, smesher, 1, 2)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	require.Equal(t, &types.Reward{Layer: 2, Coinbase: coinbase, TotalReward: 20, LayerReward: 2}, got[0])
+	require.Equal(t, &types.Reward{Layer: 3, Coinbase: coinbase, TotalReward: 30, LayerReward: 3}, got[1])
+
+	got, err = ListBySmesher(db, smesher, 4, 10)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+
+	require.NoError(t, Revert(db, 3))
+	count, err = CountBySmesher(db, smesher)
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+}
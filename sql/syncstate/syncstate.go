@@ -0,0 +1,172 @@
+package syncstate
+
+import (
+	"fmt"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/sql"
+)
+
+const (
+	keyLastSyncedLayer = "last_synced_layer"
+	keyLastAtxEpoch    = "last_atx_epoch"
+	keyBackfilledLayer = "backfilled_layer"
+)
+
+// State is the progress of the syncer persisted across restarts.
+type State struct {
+	LastSyncedLayer types.LayerID `json:"last_synced_layer"`
+	LastAtxEpoch    types.EpochID `json:"last_atx_epoch"`
+	// BackfilledLayer is the lowest layer below the checkpoint which historical data was fetched.
+	BackfilledLayer types.LayerID `json:"backfilled_layer,omitempty"`
+	// HasLastSyncedLayer, HasLastAtxEpoch and HasBackfilledLayer are false if the values were never persisted.
+	HasLastSyncedLayer bool `json:"-"`
+	HasLastAtxEpoch    bool `json:"-"`
+	HasBackfilledLayer bool `json:"-"`
+}
+
+func set(db sql.Executor, key string, value int64) error {
+	if _, err := db.Exec(`insert into syncer_state (key, value) values (?1, ?2)
+		on conflict (key) do update set value = ?2;`,
+		func(stmt *sql.Statement) {
+			stmt.BindText(1, key)
+			stmt.BindInt64(2, value)
+		}, nil); err != nil {
+		return fmt.Errorf("set syncer state %s: %w", key, err)
+	}
+	return nil
+}
+
+// SetLastSyncedLayer persists the last layer which data was fetched by the syncer.
+func SetLastSyncedLayer(db sql.Executor, lid types.LayerID) error {
+	return set(db, keyLastSyncedLayer, int64(lid))
+}
+
+// SetLastAtxEpoch persists the last epoch which ATXs were fetched by the syncer.
+func SetLastAtxEpoch(db sql.Executor, epoch types.EpochID) error {
+	return set(db, keyLastAtxEpoch, int64(epoch))
+}
+
+// SetBackfilledLayer persists the lowest layer which historical data was fetched.
+func SetBackfilledLayer(db sql.Executor, lid types.LayerID) error {
+	return set(db, keyBackfilledLayer, int64(lid))
+}
+
+// Get returns the persisted state of the syncer.
+func Get(db sql.Executor) (State, error) {
+	var st State
+	if _, err := db.Exec("select key, value from syncer_state;", nil,
+		func(stmt *sql.Statement) bool {
+			switch stmt.ColumnText(0) {
+			case keyLastSyncedLayer:
+				st.LastSyncedLayer = types.LayerID(stmt.ColumnInt64(1))
+				st.HasLastSyncedLayer = true
+			case keyLastAtxEpoch:
+				st.LastAtxEpoch = types.EpochID(stmt.ColumnInt64(1))
+				st.HasLastAtxEpoch = true
+			case keyBackfilledLayer:
+				st.BackfilledLayer = types.LayerID(stmt.ColumnInt64(1))
+				st.HasBackfilledLayer = true
+			}
+			return true
+		}); err != nil {
+		return State{}, fmt.Errorf("get syncer state: %w", err)
+	}
+	return st, nil
+}
+
+// SetTrustedHash persists the aggregated hash of the layer below the checkpoint that historical data
+// of the layer is verified against.
+func SetTrustedHash(db sql.Executor, lid types.LayerID, hash types.Hash32) error {
+	if _, err := db.Exec(`insert into syncer_trusted_hashes (layer, aggregated_hash) values (?1, ?2)
+		on conflict (layer) do update set aggregated_hash = ?2;`,
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(lid))
+			stmt.BindBytes(2, hash[:])
+		}, nil); err != nil {
+		return fmt.Errorf("set trusted hash %s: %w", lid, err)
+	}
+	return nil
+}
+
+// TrustedHash returns the trusted aggregated hash of the layer.
+func TrustedHash(db sql.Executor, lid types.LayerID) (types.Hash32, error) {
+	var hash types.Hash32
+	rows, err := db.Exec("select aggregated_hash from syncer_trusted_hashes where layer = ?1;",
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(lid))
+		}, func(stmt *sql.Statement) bool {
+			stmt.ColumnBytes(0, hash[:])
+			return false
+		})
+	if err != nil {
+		return types.Hash32{}, fmt.Errorf("trusted hash %s: %w", lid, err)
+	}
+	if rows == 0 {
+		return types.Hash32{}, fmt.Errorf("%w: trusted hash %s", sql.ErrNotFound, lid)
+	}
+	return hash, nil
+}
+
+// LatestTrustedLayer returns the highest layer with the trusted aggregated hash.
+func LatestTrustedLayer(db sql.Executor) (types.LayerID, error) {
+	var lid types.LayerID
+	rows, err := db.Exec("select layer from syncer_trusted_hashes order by layer desc limit 1;", nil,
+		func(stmt *sql.Statement) bool {
+			lid = types.LayerID(stmt.ColumnInt64(0))
+			return false
+		})
+	if err != nil {
+		return 0, fmt.Errorf("latest trusted layer: %w", err)
+	}
+	if rows == 0 {
+		return 0, fmt.Errorf("%w: no trusted hashes", sql.ErrNotFound)
+	}
+	return lid, nil
+}
+
+// AddOutstandingAtxs persists ATXs from the epoch that are being fetched by the syncer.
+// The batch is expected to be written in a single transaction.
+func AddOutstandingAtxs(db *sql.Tx, epoch types.EpochID, ids []types.ATXID) error {
+	for _, id := range ids {
+		if _, err := db.Exec(`insert into syncer_outstanding_atxs (id, epoch) values (?1, ?2)
+			on conflict (id) do nothing;`,
+			func(stmt *sql.Statement) {
+				stmt.BindBytes(1, id.Bytes())
+				stmt.BindInt64(2, int64(epoch))
+			}, nil); err != nil {
+			return fmt.Errorf("add outstanding atx %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// DeleteOutstandingAtxs removes ATXs that were fetched.
+// The batch is expected to be written in a single transaction.
+func DeleteOutstandingAtxs(db *sql.Tx, ids []types.ATXID) error {
+	for _, id := range ids {
+		if _, err := db.Exec("delete from syncer_outstanding_atxs where id = ?1;",
+			func(stmt *sql.Statement) {
+				stmt.BindBytes(1, id.Bytes())
+			}, nil); err != nil {
+			return fmt.Errorf("delete outstanding atx %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// OutstandingAtxs returns the ATXs that the syncer didn't finish fetching, grouped by epoch.
+func OutstandingAtxs(db sql.Executor) (map[types.EpochID][]types.ATXID, error) {
+	rst := map[types.EpochID][]types.ATXID{}
+	if _, err := db.Exec("select id, epoch from syncer_outstanding_atxs order by epoch, id;", nil,
+		func(stmt *sql.Statement) bool {
+			var id types.ATXID
+			stmt.ColumnBytes(0, id[:])
+			epoch := types.EpochID(stmt.ColumnInt64(1))
+			rst[epoch] = append(rst[epoch], id)
+			return true
+		}); err != nil {
+		return nil, fmt.Errorf("outstanding atxs: %w", err)
+	}
+	return rst, nil
+}
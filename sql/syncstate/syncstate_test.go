@@ -0,0 +1,85 @@
+package syncstate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/sql"
+)
+
+func TestState(t *testing.T) {
+	db := sql.InMemory()
+
+	st, err := Get(db)
+	require.NoError(t, err)
+	require.Equal(t, State{}, st)
+
+	require.NoError(t, SetLastSyncedLayer(db, 10))
+	st, err = Get(db)
+	require.NoError(t, err)
+	require.Equal(t, State{LastSyncedLayer: 10, HasLastSyncedLayer: true}, st)
+
+	require.NoError(t, SetLastSyncedLayer(db, 12))
+	require.NoError(t, SetLastAtxEpoch(db, 3))
+	require.NoError(t, SetBackfilledLayer(db, 7))
+	st, err = Get(db)
+	require.NoError(t, err)
+	require.Equal(t, State{
+		LastSyncedLayer:    12,
+		HasLastSyncedLayer: true,
+		LastAtxEpoch:       3,
+		HasLastAtxEpoch:    true,
+		BackfilledLayer:    7,
+		HasBackfilledLayer: true,
+	}, st)
+}
+
+func TestOutstandingAtxs(t *testing.T) {
+	db := sql.InMemory()
+
+	outstanding, err := OutstandingAtxs(db)
+	require.NoError(t, err)
+	require.Empty(t, outstanding)
+
+	ids := []types.ATXID{{1}, {2}, {3}}
+	require.NoError(t, db.WithTx(context.Background(), func(tx *sql.Tx) error {
+		if err := AddOutstandingAtxs(tx, 2, ids[:2]); err != nil {
+			return err
+		}
+		return AddOutstandingAtxs(tx, 3, ids[1:])
+	}))
+	outstanding, err = OutstandingAtxs(db)
+	require.NoError(t, err)
+	require.Equal(t, map[types.EpochID][]types.ATXID{
+		2: {ids[0], ids[1]},
+		3: {ids[2]},
+	}, outstanding)
+
+	require.NoError(t, db.WithTx(context.Background(), func(tx *sql.Tx) error {
+		return DeleteOutstandingAtxs(tx, ids[:2])
+	}))
+	outstanding, err = OutstandingAtxs(db)
+	require.NoError(t, err)
+	require.Equal(t, map[types.EpochID][]types.ATXID{3: {ids[2]}}, outstanding)
+}
+
+func TestTrustedHashes(t *testing.T) {
+	db := sql.InMemory()
+
+	_, err := LatestTrustedLayer(db)
+	require.ErrorIs(t, err, sql.ErrNotFound)
+	_, err = TrustedHash(db, 10)
+	require.ErrorIs(t, err, sql.ErrNotFound)
+
+	require.NoError(t, SetTrustedHash(db, 10, types.Hash32{1}))
+	require.NoError(t, SetTrustedHash(db, 9, types.Hash32{2}))
+	hash, err := TrustedHash(db, 9)
+	require.NoError(t, err)
+	require.Equal(t, types.Hash32{2}, hash)
+	latest, err := LatestTrustedLayer(db)
+	require.NoError(t, err)
+	require.Equal(t, types.LayerID(10), latest)
+}
@@ -0,0 +1,128 @@
+package transactions
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/sql"
+)
+
+// Direction of the transaction relative to the address.
+type Direction uint8
+
+const (
+	// Any direction.
+	Any Direction = iota
+	// Outgoing transactions are transactions where address is a principal.
+	Outgoing
+	// Incoming transactions are transactions that touched the address, but were not sent by it.
+	Incoming
+)
+
+// AddressFilter applies filter on query by address.
+type AddressFilter struct {
+	Address   types.Address
+	Direction Direction
+	// Start and End are inclusive bounds on the layer where transaction was applied.
+	// Transactions that are not applied are included if Pending is set.
+	Start, End *types.LayerID
+	Pending    bool
+}
+
+// where returns the where clause and the position of the next parameter.
+func (f *AddressFilter) where() (string, int) {
+	var q strings.Builder
+	q.WriteString(" where ta.address = ?1")
+	i := 2
+	if f.Direction != Any {
+		q.WriteString(" and ta.direction = ?")
+		q.WriteString(strconv.Itoa(i))
+		i++
+	}
+	q.WriteString(" and (")
+	if f.Pending {
+		q.WriteString("t.layer is null or ")
+	}
+	q.WriteString("(t.layer is not null")
+	if f.Start != nil {
+		q.WriteString(" and t.layer >= ?")
+		q.WriteString(strconv.Itoa(i))
+		i++
+	}
+	if f.End != nil {
+		q.WriteString(" and t.layer <= ?")
+		q.WriteString(strconv.Itoa(i))
+		i++
+	}
+	q.WriteString("))")
+	return q.String(), i
+}
+
+func (f *AddressFilter) binding(stmt *sql.Statement) {
+	stmt.BindBytes(1, f.Address[:])
+	position := 2
+	if f.Direction != Any {
+		stmt.BindInt64(position, int64(f.Direction))
+		position++
+	}
+	if f.Start != nil {
+		stmt.BindInt64(position, int64(*f.Start))
+		position++
+	}
+	if f.End != nil {
+		stmt.BindInt64(position, int64(*f.End))
+	}
+}
+
+// CountByAddress returns the number of transactions that match the filter.
+func CountByAddress(db sql.Executor, filter AddressFilter) (int, error) {
+	var count int
+	where, _ := filter.where()
+	if _, err := db.Exec(`select count(*) from transactions_addresses ta
+		inner join transactions t on t.id = ta.tid`+where+";",
+		filter.binding, func(stmt *sql.Statement) bool {
+			count = stmt.ColumnInt(0)
+			return true
+		}); err != nil {
+		return 0, fmt.Errorf("count by addr %s: %w", filter.Address, err)
+	}
+	return count, nil
+}
+
+// ListByAddress returns transactions that match the filter, ordered by layer and insertion.
+// Pending transactions are returned after applied ones. Limit -1 means no limit.
+func ListByAddress(db sql.Executor, filter AddressFilter, offset, limit int) ([]*types.MeshTransaction, error) {
+	var (
+		txs  []*types.MeshTransaction
+		ierr error
+	)
+	where, next := filter.where()
+	if _, err := db.Exec(fmt.Sprintf(`select t.tx, t.header, t.layer, t.block, t.timestamp, t.id
+		from transactions_addresses ta
+		inner join transactions t on t.id = ta.tid%s
+		order by t.layer is null, t.layer asc, t.timestamp asc, t.id asc
+		limit ?%d offset ?%d;`, where, next, next+1),
+		func(stmt *sql.Statement) {
+			filter.binding(stmt)
+			stmt.BindInt64(next, int64(limit))
+			stmt.BindInt64(next+1, int64(offset))
+		}, func(stmt *sql.Statement) bool {
+			var id types.TransactionID
+			stmt.ColumnBytes(5, id[:])
+			var tx *types.MeshTransaction
+			tx, ierr = decodeTransaction(id, stmt)
+			if ierr != nil {
+				return false
+			}
+			txs = append(txs, tx)
+			return true
+		}); err != nil {
+		return nil, fmt.Errorf("list by addr %s: %w", filter.Address, err)
+	}
+	if ierr != nil {
+		return nil, fmt.Errorf("list by addr %s: %w", filter.Address, ierr)
+	}
+	return txs, nil
+}
@@ -15,6 +15,17 @@ type ResultsFilter struct {
 	Address    *types.Address
 	Start, End *types.LayerID
 	TID        *types.TransactionID
+	// After skips the results up to and including the result of the transaction,
+	// in the order of the query.
+	After *ResultsCursor
+	// Limit is the max number of results, if not zero.
+	Limit int
+}
+
+// ResultsCursor is the position of the transaction result in the order by layer and id.
+type ResultsCursor struct {
+	Layer types.LayerID
+	ID    types.TransactionID
 }
 
 func (f *ResultsFilter) query() string {
@@ -44,8 +55,18 @@ func (f *ResultsFilter) query() string {
 	if f.TID != nil {
 		q.WriteString(" and id = ?")
 		q.WriteString(strconv.Itoa(i))
+		i++
 	}
-	q.WriteString("order by layer, id;")
+	if f.After != nil {
+		fmt.Fprintf(&q, " and (layer > ?%[1]d or (layer = ?%[1]d and id > ?%[2]d))", i, i+1)
+		i += 2
+	}
+	q.WriteString(" order by layer, id")
+	if f.Limit > 0 {
+		q.WriteString(" limit ?")
+		q.WriteString(strconv.Itoa(i))
+	}
+	q.WriteString(";")
 	return q.String()
 }
 
@@ -65,6 +86,15 @@ func (f *ResultsFilter) binding(stmt *sql.Statement) {
 	}
 	if f.TID != nil {
 		stmt.BindBytes(position, f.TID.Bytes())
+		position++
+	}
+	if f.After != nil {
+		stmt.BindInt64(position, int64(f.After.Layer))
+		stmt.BindBytes(position+1, f.After.ID.Bytes())
+		position += 2
+	}
+	if f.Limit > 0 {
+		stmt.BindInt64(position, int64(f.Limit))
 	}
 }
 
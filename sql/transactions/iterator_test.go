@@ -45,6 +45,14 @@ func matchTx(tx types.TransactionWithResult, filter ResultsFilter) bool {
 			return false
 		}
 	}
+	if filter.After != nil {
+		if tx.Layer.Before(filter.After.Layer) {
+			return false
+		}
+		if tx.Layer == filter.After.Layer && bytes.Compare(tx.ID[:], filter.After.ID[:]) <= 0 {
+			return false
+		}
+	}
 	return true
 }
 
@@ -55,6 +63,9 @@ func filterTxs(txs []types.TransactionWithResult, filter ResultsFilter) []types.
 			rst = append(rst, tx)
 		}
 	}
+	if filter.Limit > 0 && len(rst) > filter.Limit {
+		rst = rst[:filter.Limit]
+	}
 	return rst
 }
 
@@ -125,6 +136,27 @@ func TestIterateResults(t *testing.T) {
 				Start:   &gen.Layers[3],
 			},
 		},
+		{
+			desc: "After",
+			filter: ResultsFilter{
+				After: &ResultsCursor{Layer: txs[50].Layer, ID: txs[50].ID},
+			},
+		},
+		{
+			desc: "AddressAfterLimit",
+			filter: ResultsFilter{
+				Address: &gen.Addrs[0],
+				After:   &ResultsCursor{Layer: txs[20].Layer, ID: txs[20].ID},
+				Limit:   5,
+			},
+		},
+		{
+			desc: "Limit",
+			filter: ResultsFilter{
+				End:   &gen.Layers[8],
+				Limit: 10,
+			},
+		},
 	} {
 		t.Run(tc.desc, func(t *testing.T) {
 			expected := filterTxs(txs, tc.filter)
@@ -41,6 +41,41 @@ func Add(db sql.Executor, tx *types.Transaction, received time.Time) error {
 		}, nil); err != nil {
 		return fmt.Errorf("insert %s: %w", tx.ID, err)
 	}
+	if header != nil {
+		if err := addAddress(db, tx.Principal, tx.ID, Outgoing); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addAddress(db sql.Executor, address types.Address, tid types.TransactionID, direction Direction) error {
+	if _, err := db.Exec(`insert into transactions_addresses (address, tid, direction)
+		values (?1, ?2, ?3) on conflict do nothing;`,
+		func(stmt *sql.Statement) {
+			stmt.BindBytes(1, address[:])
+			stmt.BindBytes(2, tid[:])
+			stmt.BindInt64(3, int64(direction))
+		}, nil); err != nil {
+		return fmt.Errorf("index %s for %s: %w", tid, address, err)
+	}
+	return nil
+}
+
+// SetHeader replaces the header of the parsed transaction, e.g. when its max gas is recomputed
+// for the new gas costs.
+func SetHeader(db sql.Executor, id types.TransactionID, header *types.TxHeader) error {
+	buf, err := codec.Encode(header)
+	if err != nil {
+		return fmt.Errorf("encode header %s: %w", id, err)
+	}
+	if _, err := db.Exec(`update transactions set header = ?2 where id = ?1 and header is not null;`,
+		func(stmt *sql.Statement) {
+			stmt.BindBytes(1, id.Bytes())
+			stmt.BindBytes(2, buf)
+		}, nil); err != nil {
+		return fmt.Errorf("set header %s: %w", id, err)
+	}
 	return nil
 }
 
@@ -118,6 +153,33 @@ func GetAppliedLayer(db sql.Executor, tid types.TransactionID) (types.LayerID, e
 	return rst, nil
 }
 
+// GetAppliedGasPrices returns the gas prices of the transactions applied in the layer.
+// the headers are kept when the bodies are pruned, so the prices are available for any applied layer.
+func GetAppliedGasPrices(db sql.Executor, lid types.LayerID) ([]uint64, error) {
+	var (
+		prices []uint64
+		derr   error
+	)
+	_, err := db.Exec("select header from transactions where layer = ?1 and result is not null and header is not null",
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(lid))
+		}, func(stmt *sql.Statement) bool {
+			var header types.TxHeader
+			if _, derr = codec.DecodeFrom(stmt.ColumnReader(0), &header); derr != nil {
+				return false
+			}
+			prices = append(prices, header.GasPrice)
+			return true
+		})
+	if err == nil {
+		err = derr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("gas prices in layer %s: %w", lid, err)
+	}
+	return prices, nil
+}
+
 // UndoLayers unset all transactions to `statePending` from `from` layer to the max layer with applied transactions.
 func UndoLayers(db *sql.Tx, from types.LayerID) error {
 	_, err := db.Exec(`delete from transactions_results_addresses 
@@ -128,6 +190,15 @@ func UndoLayers(db *sql.Tx, from types.LayerID) error {
 	if err != nil {
 		return fmt.Errorf("delete addresses mapping %w", err)
 	}
+	_, err = db.Exec(`delete from transactions_addresses 
+		where direction = ?2 and tid in (select id from transactions where layer >= ?1);`,
+		func(stmt *sql.Statement) {
+			stmt.BindInt64(1, int64(from))
+			stmt.BindInt64(2, int64(Incoming))
+		}, nil)
+	if err != nil {
+		return fmt.Errorf("delete incoming addresses %w", err)
+	}
 	_, err = db.Exec(`update transactions 
 		set layer = null, block = null, result = null 
 		where layer >= ?1`,
@@ -175,9 +246,10 @@ func decodeTransaction(id types.TransactionID, stmt *sql.Statement) (*types.Mesh
 // Get gets a transaction from database.
 // Layer and Block fields are set if transaction was applied.
 // If transaction is included, but not applied check references in proposals and blocks.
+// Transactions with pruned bodies are not found.
 func Get(db sql.Executor, id types.TransactionID) (tx *types.MeshTransaction, err error) {
 	var rows int
-	rows, err = db.Exec("select tx, header, layer, block, timestamp from transactions where id = ?1",
+	rows, err = db.Exec("select tx, header, layer, block, timestamp from transactions where id = ?1 and tx is not null",
 		func(stmt *sql.Statement) {
 			stmt.BindBytes(1, id.Bytes())
 		}, func(stmt *sql.Statement) bool {
@@ -194,7 +266,7 @@ func Get(db sql.Executor, id types.TransactionID) (tx *types.MeshTransaction, er
 
 // GetBlob loads transaction as an encoded blob, ready to be sent over the wire.
 func GetBlob(db sql.Executor, id []byte) (buf []byte, err error) {
-	if rows, err := db.Exec("select tx from transactions where id = ?1",
+	if rows, err := db.Exec("select tx from transactions where id = ?1 and tx is not null",
 		func(stmt *sql.Statement) {
 			stmt.BindBytes(1, id)
 		}, func(stmt *sql.Statement) bool {
@@ -221,31 +293,6 @@ func Has(db sql.Executor, id types.TransactionID) (bool, error) {
 	return rows > 0, nil
 }
 
-// GetByAddress finds all transactions for an address.
-func GetByAddress(db sql.Executor, from, to types.LayerID, address types.Address) ([]*types.MeshTransaction, error) {
-	var txs []*types.MeshTransaction
-	if _, err := db.Exec(`
-		select tx, header, layer, block, timestamp, id from transactions
-		where principal = ?1 and (layer is null or layer between ?2 and ?3)`,
-		func(stmt *sql.Statement) {
-			stmt.BindBytes(1, address[:])
-			stmt.BindInt64(2, int64(from))
-			stmt.BindInt64(3, int64(to))
-		}, func(stmt *sql.Statement) bool {
-			var id types.TransactionID
-			stmt.ColumnBytes(5, id[:])
-			tx, err := decodeTransaction(id, stmt)
-			if err != nil {
-				return false
-			}
-			txs = append(txs, tx)
-			return true
-		}); err != nil {
-		return nil, fmt.Errorf("get by addr %s: %w", address, err)
-	}
-	return txs, nil
-}
-
 // AddressesWithPendingTransactions returns list of addresses with pending transactions.
 // Query is expensive, meant to be used only on startup.
 func AddressesWithPendingTransactions(db sql.Executor) ([]types.AddressNonce, error) {
@@ -309,9 +356,10 @@ func AddResult(db *sql.Tx, id types.TransactionID, rst *types.TransactionResult)
 		return fmt.Errorf("encode %w", err)
 	}
 
+	var principal types.Address
 	if rows, err := db.Exec(`update transactions
 		set result = ?2, layer = ?3, block = ?4 
-		where id = ?1 and result is null returning id;`,
+		where id = ?1 and result is null returning principal;`,
 		func(stmt *sql.Statement) {
 			stmt.BindBytes(1, id[:])
 			stmt.BindBytes(2, buf)
@@ -319,6 +367,7 @@ func AddResult(db *sql.Tx, id types.TransactionID, rst *types.TransactionResult)
 			stmt.BindBytes(4, rst.Block[:])
 		},
 		func(stmt *sql.Statement) bool {
+			stmt.ColumnBytes(0, principal[:])
 			return false
 		},
 	); err != nil {
@@ -336,6 +385,11 @@ func AddResult(db *sql.Tx, id types.TransactionID, rst *types.TransactionResult)
 			return fmt.Errorf("add address %s to %s: %w",
 				rst.Addresses[i].String(), id[:], err)
 		}
+		if rst.Addresses[i] != principal {
+			if err := addAddress(db, rst.Addresses[i], id, Incoming); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
@@ -383,3 +437,77 @@ func TransactionInBlock(db sql.Executor, id types.TransactionID, after types.Lay
 	}
 	return bid, rst, nil
 }
+
+// PruneBodies drops raw bodies of the transactions that were applied in layers before the specified one.
+// Headers and results are kept, so that the account history remains available.
+// Returns the number of pruned transactions and the number of freed bytes.
+func PruneBodies(db sql.Executor, before types.LayerID) (int, int64, error) {
+	var (
+		count int
+		size  int64
+	)
+	if _, err := db.Exec(`select count(*), coalesce(sum(length(tx)), 0) from transactions 
+		where layer < ?1 and result is not null and tx is not null;`, func(stmt *sql.Statement) {
+		stmt.BindInt64(1, int64(before))
+	}, func(stmt *sql.Statement) bool {
+		count = stmt.ColumnInt(0)
+		size = stmt.ColumnInt64(1)
+		return true
+	}); err != nil {
+		return 0, 0, fmt.Errorf("count tx bodies before %s: %w", before, err)
+	}
+	if count == 0 {
+		return 0, 0, nil
+	}
+	if _, err := db.Exec(`update transactions set tx = null 
+		where layer < ?1 and result is not null and tx is not null;`, func(stmt *sql.Statement) {
+		stmt.BindInt64(1, int64(before))
+	}, nil); err != nil {
+		return 0, 0, fmt.Errorf("prune tx bodies before %s: %w", before, err)
+	}
+	return count, size, nil
+}
+
+// CountDanglingBlockLinks returns the number of block_transactions entries that reference missing blocks.
+func CountDanglingBlockLinks(db sql.Executor) (int, error) {
+	var count int
+	if _, err := db.Exec(`select count(*) from block_transactions
+		where bid not in (select id from blocks);`, nil, func(stmt *sql.Statement) bool {
+		count = stmt.ColumnInt(0)
+		return true
+	}); err != nil {
+		return 0, fmt.Errorf("count dangling block links: %w", err)
+	}
+	return count, nil
+}
+
+// DeleteDanglingBlockLinks deletes block_transactions entries that reference missing blocks.
+func DeleteDanglingBlockLinks(db sql.Executor) error {
+	if _, err := db.Exec(`delete from block_transactions
+		where bid not in (select id from blocks);`, nil, nil); err != nil {
+		return fmt.Errorf("delete dangling block links: %w", err)
+	}
+	return nil
+}
+
+// CountDanglingAddresses returns the number of transactions_addresses entries that reference missing transactions.
+func CountDanglingAddresses(db sql.Executor) (int, error) {
+	var count int
+	if _, err := db.Exec(`select count(*) from transactions_addresses
+		where tid not in (select id from transactions);`, nil, func(stmt *sql.Statement) bool {
+		count = stmt.ColumnInt(0)
+		return true
+	}); err != nil {
+		return 0, fmt.Errorf("count dangling addresses: %w", err)
+	}
+	return count, nil
+}
+
+// DeleteDanglingAddresses deletes transactions_addresses entries that reference missing transactions.
+func DeleteDanglingAddresses(db sql.Executor) error {
+	if _, err := db.Exec(`delete from transactions_addresses
+		where tid not in (select id from transactions);`, nil, nil); err != nil {
+		return fmt.Errorf("delete dangling addresses: %w", err)
+	}
+	return nil
+}
@@ -130,6 +130,30 @@ func TestAddUpdatesHeader(t *testing.T) {
 	require.Nil(t, tx.TxHeader)
 }
 
+func TestSetHeader(t *testing.T) {
+	db := sql.InMemory()
+	tx := &types.Transaction{
+		RawTx:    types.NewRawTx([]byte{1, 2, 3}),
+		TxHeader: &types.TxHeader{Principal: types.Address{1}, MaxGas: 100},
+	}
+	require.NoError(t, transactions.Add(db, tx, time.Time{}))
+	unparsed := &types.Transaction{RawTx: types.NewRawTx([]byte{4, 5, 6})}
+	require.NoError(t, transactions.Add(db, unparsed, time.Time{}))
+
+	header := *tx.TxHeader
+	header.MaxGas = 200
+	require.NoError(t, transactions.SetHeader(db, tx.ID, &header))
+	got, err := transactions.Get(db, tx.ID)
+	require.NoError(t, err)
+	require.Equal(t, header, *got.TxHeader)
+
+	// the header of the unparsed transaction is set only by Add
+	require.NoError(t, transactions.SetHeader(db, unparsed.ID, &header))
+	got, err = transactions.Get(db, unparsed.ID)
+	require.NoError(t, err)
+	require.Nil(t, got.TxHeader)
+}
+
 func TestAddToProposal(t *testing.T) {
 	db := sql.InMemory()
 
@@ -273,40 +297,37 @@ func TestGetBlob(t *testing.T) {
 	}
 }
 
-func TestGetByAddress(t *testing.T) {
+func TestPruneBodies(t *testing.T) {
 	db := sql.InMemory()
 
 	rng := rand.New(rand.NewSource(1001))
-	signer1, err := signing.NewEdSigner(signing.WithKeyFromRand(rng))
-	require.NoError(t, err)
-	signer2, err := signing.NewEdSigner(signing.WithKeyFromRand(rng))
+	signer, err := signing.NewEdSigner(signing.WithKeyFromRand(rng))
 	require.NoError(t, err)
-	signer2Address := types.GenerateAddress(signer2.PublicKey().Bytes())
-	lid := types.LayerID(10)
-	txs := []*types.Transaction{
-		createTX(t, signer1, types.Address{1}, 1, 191, 1),
-		createTX(t, signer2, types.Address{2}, 1, 191, 1),
-		createTX(t, signer1, signer2Address, 1, 191, 1),
+	applied := createTX(t, signer, types.Address{1}, 1, 191, 1)
+	pending := createTX(t, signer, types.Address{1}, 2, 191, 1)
+	for _, tx := range []*types.Transaction{applied, pending} {
+		require.NoError(t, transactions.Add(db, tx, time.Now()))
 	}
-	received := time.Now()
-	require.NoError(t, db.WithTx(context.Background(), func(dbtx *sql.Tx) error {
-		for _, tx := range txs {
-			require.NoError(t, transactions.Add(dbtx, tx, received))
-			require.NoError(t, transactions.AddResult(dbtx, tx.ID, &types.TransactionResult{Layer: lid}))
-		}
-		return nil
+	require.NoError(t, db.WithTx(context.Background(), func(dtx *sql.Tx) error {
+		return transactions.AddResult(dtx, applied.ID, &types.TransactionResult{Layer: types.LayerID(10), Block: types.RandomBlockID()})
 	}))
 
-	// should be nothing before lid
-	got, err := transactions.GetByAddress(db, types.LayerID(1), lid.Sub(1), signer2Address)
+	n, size, err := transactions.PruneBodies(db, types.LayerID(11))
 	require.NoError(t, err)
-	require.Empty(t, got)
+	require.Equal(t, 1, n)
+	require.EqualValues(t, len(applied.Raw), size)
 
-	got, err = transactions.GetByAddress(db, 0, lid, signer2Address)
+	_, err = transactions.GetBlob(db, applied.ID[:])
+	require.ErrorIs(t, err, sql.ErrNotFound)
+	_, err = transactions.Get(db, applied.ID)
+	require.ErrorIs(t, err, sql.ErrNotFound)
+	exists, err := transactions.Has(db, applied.ID)
 	require.NoError(t, err)
-	require.Len(t, got, 1)
-	expected1 := makeMeshTX(txs[1], lid, types.EmptyBlockID, received, types.APPLIED)
-	checkMeshTXEqual(t, *expected1, *got[0])
+	require.True(t, exists)
+
+	buf, err := transactions.GetBlob(db, pending.ID[:])
+	require.NoError(t, err)
+	require.Equal(t, pending.Raw, buf)
 }
 
 func TestGetAcctPendingFromNonce(t *testing.T) {
@@ -376,6 +397,45 @@ func TestAppliedLayer(t *testing.T) {
 	require.ErrorIs(t, err, sql.ErrNotFound)
 }
 
+func TestGetAppliedGasPrices(t *testing.T) {
+	db := sql.InMemory()
+	rng := rand.New(rand.NewSource(1001))
+	signer, err := signing.NewEdSigner(signing.WithKeyFromRand(rng))
+	require.NoError(t, err)
+	txs := []*types.Transaction{
+		createTX(t, signer, types.Address{1}, 1, 191, 3),
+		createTX(t, signer, types.Address{1}, 2, 191, 5),
+		createTX(t, signer, types.Address{1}, 3, 191, 7),
+	}
+	lid := types.LayerID(10)
+	for _, tx := range txs {
+		require.NoError(t, transactions.Add(db, tx, time.Now()))
+	}
+	require.NoError(t, db.WithTx(context.Background(), func(dtx *sql.Tx) error {
+		for _, tx := range txs[:2] {
+			if err := transactions.AddResult(dtx, tx.ID, &types.TransactionResult{Layer: lid, Block: types.BlockID{1}}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+
+	prices, err := transactions.GetAppliedGasPrices(db, lid)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []uint64{3, 5}, prices)
+
+	// prices are available after the bodies are pruned
+	_, _, err = transactions.PruneBodies(db, lid.Add(1))
+	require.NoError(t, err)
+	prices, err = transactions.GetAppliedGasPrices(db, lid)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []uint64{3, 5}, prices)
+
+	prices, err = transactions.GetAppliedGasPrices(db, lid.Add(1))
+	require.NoError(t, err)
+	require.Empty(t, prices)
+}
+
 func TestAddressesWithPendingTransactions(t *testing.T) {
 	principals := []types.Address{
 		{1},
@@ -502,3 +562,108 @@ func TestTransactionInBlock(t *testing.T) {
 	_, _, err = transactions.TransactionInBlock(db, tid, lids[2])
 	require.ErrorIs(t, err, sql.ErrNotFound)
 }
+
+func TestListByAddress(t *testing.T) {
+	db := sql.InMemory()
+
+	rng := rand.New(rand.NewSource(1001))
+	signer1, err := signing.NewEdSigner(signing.WithKeyFromRand(rng))
+	require.NoError(t, err)
+	signer2, err := signing.NewEdSigner(signing.WithKeyFromRand(rng))
+	require.NoError(t, err)
+	addr1 := types.GenerateAddress(signer1.PublicKey().Bytes())
+	addr2 := types.GenerateAddress(signer2.PublicKey().Bytes())
+	lid := types.LayerID(10)
+	txs := []*types.Transaction{
+		createTX(t, signer1, types.Address{1}, 1, 191, 1),
+		createTX(t, signer2, addr1, 1, 191, 1),
+		createTX(t, signer1, addr2, 2, 191, 1),
+		createTX(t, signer1, addr2, 3, 191, 1),
+	}
+	received := time.Now()
+	require.NoError(t, db.WithTx(context.Background(), func(dbtx *sql.Tx) error {
+		for i, tx := range txs {
+			require.NoError(t, transactions.Add(dbtx, tx, received.Add(time.Duration(i))))
+		}
+		// the last transaction stays pending
+		recipients := []types.Address{{1}, addr1, addr2}
+		for i, tx := range txs[:3] {
+			rst := &types.TransactionResult{
+				Layer:     lid.Add(uint32(i)),
+				Addresses: []types.Address{tx.Principal, recipients[i]},
+			}
+			require.NoError(t, transactions.AddResult(dbtx, tx.ID, rst))
+		}
+		return nil
+	}))
+
+	ids := func(tb testing.TB, filter transactions.AddressFilter, offset, limit int) []types.TransactionID {
+		tb.Helper()
+		rst, err := transactions.ListByAddress(db, filter, offset, limit)
+		require.NoError(tb, err)
+		var ids []types.TransactionID
+		for _, tx := range rst {
+			ids = append(ids, tx.ID)
+		}
+		return ids
+	}
+	filter := transactions.AddressFilter{Address: addr1, Pending: true}
+	require.Equal(t, []types.TransactionID{txs[0].ID, txs[1].ID, txs[2].ID, txs[3].ID}, ids(t, filter, 0, -1))
+	count, err := transactions.CountByAddress(db, filter)
+	require.NoError(t, err)
+	require.Equal(t, 4, count)
+	require.Equal(t, []types.TransactionID{txs[1].ID, txs[2].ID}, ids(t, filter, 1, 2))
+
+	filter.Direction = transactions.Incoming
+	require.Equal(t, []types.TransactionID{txs[1].ID}, ids(t, filter, 0, -1))
+	filter.Direction = transactions.Outgoing
+	filter.Pending = false
+	require.Equal(t, []types.TransactionID{txs[0].ID, txs[2].ID}, ids(t, filter, 0, -1))
+
+	start, end := lid.Add(1), lid.Add(2)
+	filter = transactions.AddressFilter{Address: addr2, Start: &start, End: &end}
+	require.Equal(t, []types.TransactionID{txs[1].ID, txs[2].ID}, ids(t, filter, 0, -1))
+	end = lid.Add(1)
+	require.Equal(t, []types.TransactionID{txs[1].ID}, ids(t, filter, 0, -1))
+
+	require.NoError(t, db.WithTx(context.Background(), func(dbtx *sql.Tx) error {
+		return transactions.UndoLayers(dbtx, lid.Add(1))
+	}))
+	filter = transactions.AddressFilter{Address: addr1, Direction: transactions.Incoming, Pending: true}
+	require.Empty(t, ids(t, filter, 0, -1))
+	filter = transactions.AddressFilter{Address: addr2, Pending: true}
+	require.Equal(t, []types.TransactionID{txs[1].ID}, ids(t, filter, 0, -1))
+}
+
+func TestDanglingIndexes(t *testing.T) {
+	db := sql.InMemory()
+
+	rng := rand.New(rand.NewSource(1001))
+	signer, err := signing.NewEdSigner(signing.WithKeyFromRand(rng))
+	require.NoError(t, err)
+	tx := createTX(t, signer, types.Address{1}, 1, 191, 1)
+	require.NoError(t, transactions.Add(db, tx, time.Now()))
+	require.NoError(t, transactions.AddToBlock(db, tx.ID, types.LayerID(10), types.BlockID{1}))
+
+	n, err := transactions.CountDanglingBlockLinks(db)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	n, err = transactions.CountDanglingAddresses(db)
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	_, err = db.Exec("delete from transactions;", nil, nil)
+	require.NoError(t, err)
+	n, err = transactions.CountDanglingAddresses(db)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	require.NoError(t, transactions.DeleteDanglingBlockLinks(db))
+	require.NoError(t, transactions.DeleteDanglingAddresses(db))
+	n, err = transactions.CountDanglingBlockLinks(db)
+	require.NoError(t, err)
+	require.Zero(t, n)
+	n, err = transactions.CountDanglingAddresses(db)
+	require.NoError(t, err)
+	require.Zero(t, n)
+}
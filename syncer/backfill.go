@@ -0,0 +1,359 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/spacemeshos/go-spacemesh/codec"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/datastore"
+	"github.com/spacemeshos/go-spacemesh/fetch"
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/p2p"
+	"github.com/spacemeshos/go-spacemesh/p2p/pubsub"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/blocks"
+	"github.com/spacemeshos/go-spacemesh/sql/syncstate"
+	"github.com/spacemeshos/go-spacemesh/sql/transactions"
+	"github.com/spacemeshos/go-spacemesh/tortoise/opinionhash"
+)
+
+const (
+	// backfillPeers is the number of peers asked for the content of a historical layer.
+	backfillPeers = 5
+	// maxBackfillFailures is the number of consecutive failures after which backfill gives up.
+	maxBackfillFailures = 10
+	// maxBackfillBackoff limits the interval between retries to interval << maxBackfillBackoff.
+	maxBackfillBackoff = 5
+)
+
+var (
+	errNotHistorical  = errors.New("layer is not below the checkpoint")
+	errNotTrusted     = errors.New("no trusted aggregated hash for the layer")
+	errNoValidOpinion = errors.New("no peer opinion matches the trusted aggregated hash")
+)
+
+// Backfiller fetches certified blocks and their transactions for layers below the checkpoint the node
+// was recovered from. the node has no consensus state below the checkpoint, instead the layers are
+// verified against the chain of aggregated hashes that starts from the aggregated hash of the snapshot
+// layer in the checkpoint: the aggregated hash of the layer commits to the aggregated hash of the
+// previous layer and to the blocks of the layer. the layer is written only if its content matches
+// the trusted hash, and then the aggregated hash of the previous layer becomes trusted.
+// layers with more than one valid block can't be verified, as peers serve only the certified block.
+// ballots are not committed by the aggregated hashes and are not backfilled.
+// the data is stored for the API, it is never passed to the tortoise or applied to the state.
+type Backfiller struct {
+	logger   log.Log
+	db       *sql.Database
+	fetcher  backfillFetcher
+	inflight singleflight.Group
+}
+
+// NewBackfiller creates a new Backfiller instance.
+func NewBackfiller(db *sql.Database, fetcher backfillFetcher, lg log.Log) *Backfiller {
+	return &Backfiller{
+		logger:  lg,
+		db:      db,
+		fetcher: fetcher,
+	}
+}
+
+func historical(lid types.LayerID) bool {
+	return lid > types.FirstEffectiveGenesis() && lid <= types.GetEffectiveGenesis()
+}
+
+// Run backfills one layer every interval, from the highest layer with the trusted hash down to the genesis.
+// the lowest backfilled layer is persisted, so that backfill resumes from it after restart. a layer that
+// failed to backfill is retried with exponential backoff, backfill gives up after maxBackfillFailures
+// consecutive failures.
+func (b *Backfiller) Run(ctx context.Context, interval time.Duration) error {
+	st, err := syncstate.Get(b.db)
+	if err != nil {
+		return err
+	}
+	lid := types.GetEffectiveGenesis()
+	trusted, err := syncstate.LatestTrustedLayer(b.db)
+	switch {
+	case errors.Is(err, sql.ErrNotFound):
+		b.logger.WithContext(ctx).Info("checkpoint has no aggregated hash to verify historical layers against")
+		return nil
+	case err != nil:
+		return err
+	case trusted < lid:
+		// layers between the snapshot layer and the checkpoint are empty
+		lid = trusted
+	}
+	if st.HasBackfilledLayer && st.BackfilledLayer <= lid {
+		lid = st.BackfilledLayer - 1
+	}
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	failures := 0
+	for historical(lid) {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+		}
+		if err := b.BackfillLayer(ctx, lid); err != nil {
+			failures++
+			if failures == maxBackfillFailures {
+				return fmt.Errorf("backfill layer %s failed %d times: %w", lid, failures, err)
+			}
+			shift := failures
+			if shift > maxBackfillBackoff {
+				shift = maxBackfillBackoff
+			}
+			backoff := interval << shift
+			b.logger.WithContext(ctx).With().Warning("failed to backfill layer",
+				lid,
+				log.Int("failures", failures),
+				log.Duration("retry", backoff),
+				log.Err(err),
+			)
+			timer.Reset(backoff)
+			continue
+		}
+		failures = 0
+		timer.Reset(interval)
+		if err := syncstate.SetBackfilledLayer(b.db, lid); err != nil {
+			return err
+		}
+		backfilledLayer.Set(float64(lid))
+		lid--
+	}
+	b.logger.WithContext(ctx).With().Info("backfilled all layers below the checkpoint",
+		log.Stringer("checkpoint", types.GetEffectiveGenesis()),
+	)
+	return nil
+}
+
+// BackfillLayer fetches the certified block with its transactions for the layer below the checkpoint
+// and verifies them against the trusted aggregated hash of the layer. concurrent calls for the same
+// layer share the result.
+func (b *Backfiller) BackfillLayer(ctx context.Context, lid types.LayerID) error {
+	if !historical(lid) {
+		return fmt.Errorf("%w: %s", errNotHistorical, lid)
+	}
+	_, err, _ := b.inflight.Do(lid.String(), func() (any, error) {
+		return nil, b.backfillLayer(ctx, lid)
+	})
+	return err
+}
+
+// opinion is the content of the layer as served by peers.
+type opinion struct {
+	content
+	peers []p2p.Peer
+}
+
+type content struct {
+	prev  types.Hash32
+	block types.BlockID
+}
+
+func (b *Backfiller) backfillLayer(ctx context.Context, lid types.LayerID) error {
+	trusted, err := syncstate.TrustedHash(b.db, lid)
+	if errors.Is(err, sql.ErrNotFound) {
+		return fmt.Errorf("%w: %s", errNotTrusted, lid)
+	} else if err != nil {
+		return err
+	}
+	peers := b.fetcher.GetPeers()
+	if len(peers) == 0 {
+		return errNoPeers
+	}
+	rand.Shuffle(len(peers), func(i, j int) { peers[i], peers[j] = peers[j], peers[i] })
+	if len(peers) > backfillPeers {
+		peers = peers[:backfillPeers]
+	}
+	logger := b.logger.WithContext(ctx).WithFields(lid)
+	ctx = fetch.WithPriority(ctx, fetch.PriorityBackfill)
+
+	byContent := map[content]*opinion{}
+	for peer, data := range b.poll(ctx, len(peers), func(okCB func([]byte, p2p.Peer), errCB func(error, p2p.Peer)) error {
+		return b.fetcher.GetLayerOpinions(ctx, peers, lid, okCB, errCB)
+	}) {
+		var lo fetch.LayerOpinion
+		if err := codec.Decode(data, &lo); err != nil {
+			logger.With().Debug("malformed layer opinion", log.Stringer("peer", peer), log.Err(err))
+			continue
+		}
+		key := content{prev: lo.PrevAggHash}
+		if lo.Cert != nil {
+			key.block = lo.Cert.BlockID
+		}
+		if _, ok := byContent[key]; !ok {
+			byContent[key] = &opinion{content: key}
+		}
+		byContent[key].peers = append(byContent[key].peers, peer)
+	}
+	// opinions served by more peers are tried first
+	opinions := make([]*opinion, 0, len(byContent))
+	for _, op := range byContent {
+		opinions = append(opinions, op)
+	}
+	sort.Slice(opinions, func(i, j int) bool {
+		return len(opinions[i].peers) > len(opinions[j].peers)
+	})
+	for _, op := range opinions {
+		var block *types.Block
+		if op.block != types.EmptyBlockID {
+			for _, peer := range op.peers {
+				b.fetcher.RegisterPeerHashes(peer, []types.Hash32{op.block.AsHash32()})
+			}
+			block, err = b.fetchBlock(ctx, lid, op.block)
+			if err != nil {
+				logger.With().Debug("failed to fetch block", op.block, log.Err(err))
+				continue
+			}
+		}
+		hasher := opinionhash.New()
+		hasher.WritePrevious(op.prev)
+		if block != nil {
+			hasher.WriteSupport(block.ID(), block.TickHeight)
+		}
+		if hasher.Hash() != trusted {
+			logger.With().Debug("opinion doesn't match the trusted hash",
+				log.Stringer("prev", op.prev),
+				log.Stringer("block", op.block),
+				log.Int("peers", len(op.peers)),
+			)
+			continue
+		}
+		var txs []*types.Transaction
+		if block != nil {
+			for _, peer := range op.peers {
+				b.fetcher.RegisterPeerHashes(peer, types.TransactionIDsToHashes(block.TxIDs))
+			}
+			if txs, err = b.fetchTxs(ctx, block.TxIDs); err != nil {
+				return fmt.Errorf("fetch block txs: %w", err)
+			}
+		}
+		if err := b.save(ctx, lid, op.prev, block, txs); err != nil {
+			return err
+		}
+		logger.With().Debug("backfilled layer",
+			log.Stringer("block", op.block),
+			log.Int("txs", len(txs)),
+		)
+		return nil
+	}
+	return fmt.Errorf("%w: %s", errNoValidOpinion, lid)
+}
+
+// save writes the verified content of the layer and trusts the aggregated hash of the previous layer.
+func (b *Backfiller) save(
+	ctx context.Context,
+	lid types.LayerID,
+	prev types.Hash32,
+	block *types.Block,
+	txs []*types.Transaction,
+) error {
+	return b.db.WithTx(ctx, func(tx *sql.Tx) error {
+		if block != nil {
+			if err := blocks.Add(tx, block); err != nil && !errors.Is(err, sql.ErrObjectExists) {
+				return err
+			}
+			for _, t := range txs {
+				if err := transactions.Add(tx, t, time.Now()); err != nil {
+					return err
+				}
+			}
+			for _, tid := range block.TxIDs {
+				if err := transactions.AddToBlock(tx, tid, lid, block.ID()); err != nil {
+					return err
+				}
+			}
+		}
+		return syncstate.SetTrustedHash(tx, lid.Sub(1), prev)
+	})
+}
+
+// poll waits for responses from n peers to the request started by send.
+func (b *Backfiller) poll(
+	ctx context.Context,
+	n int,
+	send func(func([]byte, p2p.Peer), func(error, p2p.Peer)) error,
+) map[p2p.Peer][]byte {
+	ch := make(chan peerResult[[]byte], n)
+	okCB := func(data []byte, peer p2p.Peer) {
+		ch <- peerResult[[]byte]{peer: peer, data: &data}
+	}
+	errCB := func(err error, peer p2p.Peer) {
+		ch <- peerResult[[]byte]{peer: peer, err: err}
+	}
+	rst := map[p2p.Peer][]byte{}
+	if err := send(okCB, errCB); err != nil {
+		return rst
+	}
+	for i := 0; i < n; i++ {
+		select {
+		case <-ctx.Done():
+			return rst
+		case res := <-ch:
+			if res.err == nil {
+				rst[res.peer] = *res.data
+			}
+		}
+	}
+	return rst
+}
+
+// fetchBlock fetches the block without storing it, the block is stored only after it is verified.
+func (b *Backfiller) fetchBlock(ctx context.Context, lid types.LayerID, id types.BlockID) (*types.Block, error) {
+	var fetched *types.Block
+	if err := b.fetcher.GetHashes(ctx, []types.Hash32{id.AsHash32()}, datastore.BlockDB,
+		fetch.ValidatorFunc(func(_ context.Context, hash types.Hash32, _ p2p.Peer, data []byte) error {
+			var block types.Block
+			if err := codec.Decode(data, &block); err != nil {
+				return fmt.Errorf("%w: decode block: %s", pubsub.ErrValidationReject, err)
+			}
+			block.Initialize()
+			if block.ID().AsHash32() != hash {
+				return fmt.Errorf("%w: block hash mismatch", pubsub.ErrValidationReject)
+			}
+			if block.LayerIndex != lid {
+				return fmt.Errorf("%w: block in layer %s, want %s", pubsub.ErrValidationReject, block.LayerIndex, lid)
+			}
+			fetched = &block
+			return nil
+		})); err != nil {
+		return nil, err
+	}
+	if fetched == nil {
+		// the block is already stored locally
+		return blocks.Get(b.db, id)
+	}
+	return fetched, nil
+}
+
+// fetchTxs fetches the transactions without storing them. transactions that are already
+// stored locally are not returned.
+func (b *Backfiller) fetchTxs(ctx context.Context, ids []types.TransactionID) ([]*types.Transaction, error) {
+	var (
+		mu  sync.Mutex
+		rst []*types.Transaction
+	)
+	if err := b.fetcher.GetHashes(ctx, types.TransactionIDsToHashes(ids), datastore.TXDB,
+		fetch.ValidatorFunc(func(_ context.Context, hash types.Hash32, _ p2p.Peer, data []byte) error {
+			raw := types.NewRawTx(data)
+			if raw.ID.Hash32() != hash {
+				return fmt.Errorf("%w: transaction hash mismatch", pubsub.ErrValidationReject)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			rst = append(rst, &types.Transaction{RawTx: raw})
+			return nil
+		})); err != nil {
+		return nil, err
+	}
+	return rst, nil
+}
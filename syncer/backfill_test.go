@@ -0,0 +1,211 @@
+package syncer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/codec"
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/datastore"
+	"github.com/spacemeshos/go-spacemesh/fetch"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/p2p"
+	"github.com/spacemeshos/go-spacemesh/p2p/pubsub"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/blocks"
+	"github.com/spacemeshos/go-spacemesh/sql/syncstate"
+	"github.com/spacemeshos/go-spacemesh/sql/transactions"
+	"github.com/spacemeshos/go-spacemesh/syncer/mocks"
+	"github.com/spacemeshos/go-spacemesh/tortoise/opinionhash"
+)
+
+type testBackfiller struct {
+	*Backfiller
+	db      *sql.Database
+	fetcher *mocks.MockbackfillFetcher
+}
+
+func newTestBackfiller(t *testing.T) *testBackfiller {
+	checkpoint := types.FirstEffectiveGenesis().Add(5)
+	types.SetEffectiveGenesis(checkpoint.Uint32())
+	t.Cleanup(func() { types.SetEffectiveGenesis(types.FirstEffectiveGenesis().Uint32()) })
+
+	db := sql.InMemory()
+	fetcher := mocks.NewMockbackfillFetcher(gomock.NewController(t))
+	return &testBackfiller{
+		Backfiller: NewBackfiller(db, fetcher, logtest.New(t)),
+		db:         db,
+		fetcher:    fetcher,
+	}
+}
+
+func aggHash(prev types.Hash32, block *types.Block) types.Hash32 {
+	hasher := opinionhash.New()
+	hasher.WritePrevious(prev)
+	if block != nil {
+		hasher.WriteSupport(block.ID(), block.TickHeight)
+	}
+	return hasher.Hash()
+}
+
+// serve responds to the requests for the layer content with the data for the hint.
+func (tb *testBackfiller) serve(peers []p2p.Peer, lo *fetch.LayerOpinion, data map[datastore.Hint]map[types.Hash32][]byte) {
+	tb.fetcher.EXPECT().GetPeers().Return(peers)
+	tb.fetcher.EXPECT().GetLayerOpinions(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, peers []p2p.Peer, _ types.LayerID, okCB func([]byte, p2p.Peer), _ func(error, p2p.Peer)) error {
+			for _, peer := range peers {
+				okCB(codec.MustEncode(lo), peer)
+			}
+			return nil
+		})
+	tb.fetcher.EXPECT().RegisterPeerHashes(gomock.Any(), gomock.Any()).AnyTimes()
+	tb.fetcher.EXPECT().GetHashes(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, hashes []types.Hash32, hint datastore.Hint, validator fetch.SyncValidator) error {
+			for _, hash := range hashes {
+				if err := validator.HandleMessage(ctx, hash, "peer", data[hint][hash]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}).AnyTimes()
+}
+
+func TestBackfiller_Layer(t *testing.T) {
+	tb := newTestBackfiller(t)
+	lid := types.GetEffectiveGenesis().Sub(1)
+
+	tx := types.NewRawTx([]byte{1, 2, 3})
+	block := &types.Block{InnerBlock: types.InnerBlock{LayerIndex: lid, TickHeight: 10, TxIDs: []types.TransactionID{tx.ID}}}
+	block.Initialize()
+	prev := types.RandomHash()
+	require.NoError(t, syncstate.SetTrustedHash(tb.db, lid, aggHash(prev, block)))
+	tb.serve([]p2p.Peer{"a", "b"},
+		&fetch.LayerOpinion{PrevAggHash: prev, Cert: &types.Certificate{BlockID: block.ID()}},
+		map[datastore.Hint]map[types.Hash32][]byte{
+			datastore.BlockDB: {block.ID().AsHash32(): codec.MustEncode(block)},
+			datastore.TXDB:    {tx.ID.Hash32(): tx.Raw},
+		})
+	require.NoError(t, tb.BackfillLayer(context.Background(), lid))
+
+	_, err := blocks.Get(tb.db, block.ID())
+	require.NoError(t, err)
+	included, err := transactions.HasBlockTX(tb.db, block.ID(), tx.ID)
+	require.NoError(t, err)
+	require.True(t, included)
+	trusted, err := syncstate.TrustedHash(tb.db, lid.Sub(1))
+	require.NoError(t, err)
+	require.Equal(t, prev, trusted)
+}
+
+func TestBackfiller_Untrusted(t *testing.T) {
+	tb := newTestBackfiller(t)
+	lid := types.GetEffectiveGenesis().Sub(1)
+
+	block := &types.Block{InnerBlock: types.InnerBlock{LayerIndex: lid, TickHeight: 10}}
+	block.Initialize()
+	require.NoError(t, syncstate.SetTrustedHash(tb.db, lid, types.RandomHash()))
+	tb.serve([]p2p.Peer{"a"},
+		&fetch.LayerOpinion{PrevAggHash: types.RandomHash(), Cert: &types.Certificate{BlockID: block.ID()}},
+		map[datastore.Hint]map[types.Hash32][]byte{
+			datastore.BlockDB: {block.ID().AsHash32(): codec.MustEncode(block)},
+		})
+	require.ErrorIs(t, tb.BackfillLayer(context.Background(), lid), errNoValidOpinion)
+	_, err := blocks.Get(tb.db, block.ID())
+	require.ErrorIs(t, err, sql.ErrNotFound)
+	_, err = syncstate.TrustedHash(tb.db, lid.Sub(1))
+	require.ErrorIs(t, err, sql.ErrNotFound)
+
+	// layer without the trusted hash is not fetched
+	require.ErrorIs(t, tb.BackfillLayer(context.Background(), lid.Sub(1)), errNotTrusted)
+}
+
+func TestBackfiller_InvalidData(t *testing.T) {
+	tb := newTestBackfiller(t)
+	lid := types.GetEffectiveGenesis().Sub(1)
+
+	block := &types.Block{InnerBlock: types.InnerBlock{LayerIndex: lid.Sub(1)}}
+	block.Initialize()
+	prev := types.RandomHash()
+	require.NoError(t, syncstate.SetTrustedHash(tb.db, lid, aggHash(prev, block)))
+	tb.serve([]p2p.Peer{"a"},
+		&fetch.LayerOpinion{PrevAggHash: prev, Cert: &types.Certificate{BlockID: block.ID()}},
+		map[datastore.Hint]map[types.Hash32][]byte{
+			datastore.BlockDB: {block.ID().AsHash32(): codec.MustEncode(block)},
+		})
+	// block from another layer is rejected by the validator
+	require.ErrorIs(t, tb.BackfillLayer(context.Background(), lid), errNoValidOpinion)
+	_, err := blocks.Get(tb.db, block.ID())
+	require.ErrorIs(t, err, sql.ErrNotFound)
+}
+
+func TestBackfiller_RejectedTx(t *testing.T) {
+	tb := newTestBackfiller(t)
+	lid := types.GetEffectiveGenesis().Sub(1)
+
+	tx := types.NewRawTx([]byte{1, 2, 3})
+	block := &types.Block{InnerBlock: types.InnerBlock{LayerIndex: lid, TxIDs: []types.TransactionID{tx.ID}}}
+	block.Initialize()
+	prev := types.RandomHash()
+	require.NoError(t, syncstate.SetTrustedHash(tb.db, lid, aggHash(prev, block)))
+	tb.serve([]p2p.Peer{"a"},
+		&fetch.LayerOpinion{PrevAggHash: prev, Cert: &types.Certificate{BlockID: block.ID()}},
+		map[datastore.Hint]map[types.Hash32][]byte{
+			datastore.BlockDB: {block.ID().AsHash32(): codec.MustEncode(block)},
+			datastore.TXDB:    {tx.ID.Hash32(): {4, 5, 6}},
+		})
+	require.ErrorIs(t, tb.BackfillLayer(context.Background(), lid), pubsub.ErrValidationReject)
+	// nothing is written if any of the layer content is invalid
+	_, err := blocks.Get(tb.db, block.ID())
+	require.ErrorIs(t, err, sql.ErrNotFound)
+}
+
+func TestBackfiller_NotHistorical(t *testing.T) {
+	tb := newTestBackfiller(t)
+	for _, lid := range []types.LayerID{types.FirstEffectiveGenesis(), types.GetEffectiveGenesis().Add(1)} {
+		require.ErrorIs(t, tb.BackfillLayer(context.Background(), lid), errNotHistorical)
+	}
+}
+
+func TestBackfiller_Run(t *testing.T) {
+	tb := newTestBackfiller(t)
+	// nothing to verify the layers against
+	require.NoError(t, tb.Run(context.Background(), time.Millisecond))
+
+	// starts from the snapshot layer and resumes from the layer below the persisted one
+	snapshot := types.GetEffectiveGenesis().Sub(1)
+	hashes := map[types.LayerID]types.Hash32{types.FirstEffectiveGenesis(): types.RandomHash()}
+	for lid := types.FirstEffectiveGenesis().Add(1); lid <= snapshot; lid++ {
+		hashes[lid] = aggHash(hashes[lid.Sub(1)], nil)
+	}
+	require.NoError(t, syncstate.SetTrustedHash(tb.db, snapshot, hashes[snapshot]))
+	require.NoError(t, syncstate.SetTrustedHash(tb.db, snapshot.Sub(1), hashes[snapshot.Sub(1)]))
+	require.NoError(t, syncstate.SetBackfilledLayer(tb.db, snapshot))
+	for lid := snapshot.Sub(1); lid > types.FirstEffectiveGenesis(); lid-- {
+		tb.serve([]p2p.Peer{"a"}, &fetch.LayerOpinion{PrevAggHash: hashes[lid.Sub(1)]}, nil)
+	}
+	require.NoError(t, tb.Run(context.Background(), time.Millisecond))
+
+	st, err := syncstate.Get(tb.db)
+	require.NoError(t, err)
+	require.True(t, st.HasBackfilledLayer)
+	require.Equal(t, types.FirstEffectiveGenesis().Add(1), st.BackfilledLayer)
+
+	// nothing left to backfill
+	require.NoError(t, tb.Run(context.Background(), time.Millisecond))
+}
+
+func TestBackfiller_RunGivesUp(t *testing.T) {
+	tb := newTestBackfiller(t)
+	lid := types.GetEffectiveGenesis().Sub(1)
+	require.NoError(t, syncstate.SetTrustedHash(tb.db, lid, types.RandomHash()))
+	tb.fetcher.EXPECT().GetPeers().Return(nil).Times(maxBackfillFailures)
+	require.ErrorIs(t, tb.Run(context.Background(), time.Microsecond), errNoPeers)
+
+	st, err := syncstate.Get(tb.db)
+	require.NoError(t, err)
+	require.False(t, st.HasBackfilledLayer)
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/spacemeshos/go-spacemesh/fetch"
 	"github.com/spacemeshos/go-spacemesh/log"
 	"github.com/spacemeshos/go-spacemesh/p2p"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/syncstate"
 )
 
 var (
@@ -55,6 +57,7 @@ type DataFetch struct {
 	fetcher
 
 	logger  log.Log
+	db      *sql.Database
 	msh     meshProvider
 	ids     idProvider
 	asCache activeSetCache
@@ -64,10 +67,18 @@ type DataFetch struct {
 }
 
 // NewDataFetch creates a new DataFetch instance.
-func NewDataFetch(msh meshProvider, fetch fetcher, ids idProvider, cache activeSetCache, lg log.Log) *DataFetch {
+func NewDataFetch(
+	db *sql.Database,
+	msh meshProvider,
+	fetch fetcher,
+	ids idProvider,
+	cache activeSetCache,
+	lg log.Log,
+) *DataFetch {
 	return &DataFetch{
 		fetcher:   fetch,
 		logger:    lg,
+		db:        db,
 		msh:       msh,
 		ids:       ids,
 		asCache:   cache,
@@ -405,6 +416,8 @@ func (d *DataFetch) updateAtxPeer(epoch types.EpochID, peer p2p.Peer) {
 }
 
 // GetEpochATXs fetches all ATXs published in the specified epoch from a peer.
+// ATX IDs are requested in pages and only the ATXs missing in the page are fetched, before
+// requesting the next page.
 func (d *DataFetch) GetEpochATXs(ctx context.Context, epoch types.EpochID) error {
 	peers := d.fetcher.GetPeers()
 	if len(peers) == 0 {
@@ -419,6 +432,42 @@ func (d *DataFetch) GetEpochATXs(ctx context.Context, epoch types.EpochID) error
 		return nil
 	}
 
+	req := &fetch.EpochPageRequest{Epoch: epoch, Limit: fetch.MaxAtxsInPage}
+	for {
+		page, err := d.fetcher.PeerEpochPage(ctx, peer, req)
+		if err != nil && req.After == types.EmptyATXID {
+			// peer may not support paginated requests
+			d.logger.WithContext(ctx).With().Debug("failed to get first epoch page. requesting all atxs",
+				epoch,
+				log.Stringer("peer", peer),
+				log.Err(err),
+			)
+			return d.getAllEpochATXs(ctx, peer, epoch)
+		}
+		if err != nil {
+			atxPeerError.Inc()
+			return fmt.Errorf("get epoch page (peer %v): %w", peer, err)
+		}
+		if len(page.AtxIDs) == 0 && req.After == types.EmptyATXID {
+			d.logger.WithContext(ctx).With().Debug("peer have zero atx",
+				epoch,
+				log.Stringer("peer", peer),
+			)
+			return nil
+		}
+		d.updateAtxPeer(epoch, peer)
+		if err := d.fetchMissingATXs(ctx, peer, epoch, page.AtxIDs); err != nil {
+			return err
+		}
+		if len(page.AtxIDs) < int(req.Limit) {
+			return nil
+		}
+		req.After = page.AtxIDs[len(page.AtxIDs)-1]
+	}
+}
+
+// getAllEpochATXs fetches all ATXs published in the specified epoch from a peer with a single request for IDs.
+func (d *DataFetch) getAllEpochATXs(ctx context.Context, peer p2p.Peer, epoch types.EpochID) error {
 	ed, err := d.fetcher.PeerEpochInfo(ctx, peer, epoch)
 	if err != nil {
 		atxPeerError.Inc()
@@ -433,17 +482,32 @@ func (d *DataFetch) GetEpochATXs(ctx context.Context, epoch types.EpochID) error
 	}
 	d.updateAtxPeer(epoch, peer)
 	d.fetcher.RegisterPeerHashes(peer, types.ATXIDsToHashes(ed.AtxIDs))
-	missing := d.asCache.GetMissingActiveSet(epoch+1, ed.AtxIDs)
+	return d.fetchMissingATXs(ctx, peer, epoch, ed.AtxIDs)
+}
+
+func (d *DataFetch) fetchMissingATXs(ctx context.Context, peer p2p.Peer, epoch types.EpochID, ids []types.ATXID) error {
+	missing := d.asCache.GetMissingActiveSet(epoch+1, ids)
 	d.logger.WithContext(ctx).With().Debug("fetching atxs",
 		epoch,
 		log.Stringer("peer", peer),
-		log.Int("total", len(ed.AtxIDs)),
+		log.Int("total", len(ids)),
 		log.Int("missing", len(missing)),
 	)
 	if len(missing) > 0 {
+		// persisted so that the fetch is resumed if the node restarts before it completes
+		if err := d.db.WithTx(ctx, func(tx *sql.Tx) error {
+			return syncstate.AddOutstandingAtxs(tx, epoch, missing)
+		}); err != nil {
+			return err
+		}
 		if err := d.fetcher.GetAtxs(ctx, missing); err != nil {
 			return fmt.Errorf("get ATXs: %w", err)
 		}
+		if err := d.db.WithTx(ctx, func(tx *sql.Tx) error {
+			return syncstate.DeleteOutstandingAtxs(tx, missing)
+		}); err != nil {
+			return err
+		}
 	}
 	return nil
 }
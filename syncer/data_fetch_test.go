@@ -14,12 +14,15 @@ import (
 	"github.com/spacemeshos/go-spacemesh/fetch"
 	"github.com/spacemeshos/go-spacemesh/log/logtest"
 	"github.com/spacemeshos/go-spacemesh/p2p"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/syncstate"
 	"github.com/spacemeshos/go-spacemesh/syncer"
 	"github.com/spacemeshos/go-spacemesh/syncer/mocks"
 )
 
 type testDataFetch struct {
 	*syncer.DataFetch
+	db        *sql.Database
 	mMesh     *mocks.MockmeshProvider
 	mFetcher  *mocks.Mockfetcher
 	mIDs      *mocks.MockidProvider
@@ -30,12 +33,13 @@ func newTestDataFetch(t *testing.T) *testDataFetch {
 	ctrl := gomock.NewController(t)
 	lg := logtest.New(t)
 	tl := &testDataFetch{
+		db:        sql.InMemory(),
 		mMesh:     mocks.NewMockmeshProvider(ctrl),
 		mFetcher:  mocks.NewMockfetcher(ctrl),
 		mIDs:      mocks.NewMockidProvider(ctrl),
 		mAtxCache: mocks.NewMockactiveSetCache(ctrl),
 	}
-	tl.DataFetch = syncer.NewDataFetch(tl.mMesh, tl.mFetcher, tl.mIDs, tl.mAtxCache, lg)
+	tl.DataFetch = syncer.NewDataFetch(tl.db, tl.mMesh, tl.mFetcher, tl.mIDs, tl.mAtxCache, lg)
 	return tl
 }
 
@@ -266,6 +270,52 @@ func TestDataFetch_PollLayerOpinions(t *testing.T) {
 	}
 }
 
+func TestDataFetch_GetEpochATXs_Pages(t *testing.T) {
+	peers := GenPeers(2)
+	epoch := types.EpochID(11)
+	ids := types.RandomActiveSet(fetch.MaxAtxsInPage + 10)
+	pages := [][]types.ATXID{ids[:fetch.MaxAtxsInPage], ids[fetch.MaxAtxsInPage:]}
+	errFetch := errors.New("err fetch")
+	tt := []struct {
+		name     string
+		fetchErr error
+	}{
+		{
+			name: "success",
+		},
+		{
+			name:     "fetch failure",
+			fetchErr: errFetch,
+		},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			td := newTestDataFetch(t)
+			td.mFetcher.EXPECT().GetPeers().Return(peers)
+			var after types.ATXID
+			for _, page := range pages {
+				page := page
+				td.mFetcher.EXPECT().PeerEpochPage(gomock.Any(), gomock.Any(), &fetch.EpochPageRequest{
+					Epoch: epoch,
+					After: after,
+					Limit: fetch.MaxAtxsInPage,
+				}).Return(&fetch.EpochPage{AtxIDs: page}, nil)
+				td.mAtxCache.EXPECT().GetMissingActiveSet(epoch+1, page).Return(page[1:])
+				td.mFetcher.EXPECT().GetAtxs(gomock.Any(), page[1:]).Return(tc.fetchErr)
+				if tc.fetchErr != nil {
+					break
+				}
+				after = page[len(page)-1]
+			}
+			require.ErrorIs(t, td.GetEpochATXs(context.TODO(), epoch), tc.fetchErr)
+		})
+	}
+}
+
 func TestDataFetch_GetEpochATXs(t *testing.T) {
 	const numPeers = 4
 	peers := GenPeers(numPeers)
@@ -301,6 +351,7 @@ func TestDataFetch_GetEpochATXs(t *testing.T) {
 				AtxIDs: types.RandomActiveSet(11),
 			}
 			td.mFetcher.EXPECT().GetPeers().Return(peers)
+			td.mFetcher.EXPECT().PeerEpochPage(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("not supported"))
 			if tc.getErr == nil {
 				td.mAtxCache.EXPECT().GetMissingActiveSet(epoch+1, ed.AtxIDs).Return(ed.AtxIDs[1:])
 			}
@@ -311,11 +362,24 @@ func TestDataFetch_GetEpochATXs(t *testing.T) {
 						return nil, tc.getErr
 					} else {
 						td.mFetcher.EXPECT().RegisterPeerHashes(peer, types.ATXIDsToHashes(ed.AtxIDs))
-						td.mFetcher.EXPECT().GetAtxs(gomock.Any(), ed.AtxIDs[1:]).Return(tc.fetchErr)
+						td.mFetcher.EXPECT().GetAtxs(gomock.Any(), ed.AtxIDs[1:]).DoAndReturn(
+							func(context.Context, []types.ATXID) error {
+								outstanding, err := syncstate.OutstandingAtxs(td.db)
+								require.NoError(t, err)
+								require.ElementsMatch(t, ed.AtxIDs[1:], outstanding[epoch])
+								return tc.fetchErr
+							})
 						return ed, nil
 					}
 				})
 			require.ErrorIs(t, td.GetEpochATXs(context.TODO(), epoch), tc.err)
+			outstanding, err := syncstate.OutstandingAtxs(td.db)
+			require.NoError(t, err)
+			if tc.fetchErr != nil {
+				require.ElementsMatch(t, ed.AtxIDs[1:], outstanding[epoch])
+			} else {
+				require.Empty(t, outstanding)
+			}
 		})
 	}
 }
@@ -0,0 +1,110 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log"
+)
+
+var errValidationLag = errors.New("validation lags behind fetched layers")
+
+type layerResult struct {
+	lid types.LayerID
+	err error
+}
+
+// syncLayers fetches data for layers in the range [from, to) with Config.FetchWorkers concurrent workers.
+// the last synced layer advances only over the layers fetched without gaps, so that the layers
+// are validated in order. no more layers are dispatched after the first failure, or if the layer
+// is more than Config.MaxFetchAhead layers ahead of the processed layer.
+func (s *Syncer) syncLayers(ctx context.Context, from, to types.LayerID) error {
+	workers := s.cfg.FetchWorkers
+	if workers < 1 {
+		workers = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg      sync.WaitGroup
+		layers  = make(chan types.LayerID)
+		results = make(chan layerResult, workers)
+	)
+	for i := 0; i < workers; i++ {
+		worker := strconv.Itoa(i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for lid := range layers {
+				start := time.Now()
+				err := s.syncLayer(ctx, lid)
+				layerFetchDuration.WithLabelValues(worker).Observe(time.Since(start).Seconds())
+				outcome := "ok"
+				if err != nil {
+					outcome = "not"
+				}
+				layerFetches.WithLabelValues(worker, outcome).Inc()
+				results <- layerResult{lid: lid, err: err}
+			}
+		}()
+	}
+
+	var (
+		next     = from
+		inflight = 0
+		fetched  = map[types.LayerID]struct{}{}
+		failure  error
+	)
+	for {
+		var dispatch chan types.LayerID
+		if failure == nil && next.Before(to) && inflight < workers {
+			if s.tooFarAhead(next) {
+				failure = errValidationLag
+				fetchBackpressure.Inc()
+				s.logger.WithContext(ctx).With().Debug("layer fetching paused until validation catches up",
+					log.Stringer("next", next),
+					log.Stringer("processed", s.mesh.ProcessedLayer()),
+				)
+			} else {
+				dispatch = layers
+			}
+		}
+		if dispatch == nil && inflight == 0 {
+			break
+		}
+		select {
+		case dispatch <- next:
+			next = next.Add(1)
+			inflight++
+		case res := <-results:
+			inflight--
+			if res.err != nil {
+				if failure == nil {
+					failure = res.err
+				}
+				continue
+			}
+			fetched[res.lid] = struct{}{}
+			for lid := s.getLastSyncedLayer().Add(1); ; lid = lid.Add(1) {
+				if _, ok := fetched[lid]; !ok {
+					break
+				}
+				delete(fetched, lid)
+				s.setLastSyncedLayer(lid)
+			}
+		}
+	}
+	close(layers)
+	wg.Wait()
+	return failure
+}
+
+// tooFarAhead returns true if the layer should not be fetched until more layers are processed.
+func (s *Syncer) tooFarAhead(lid types.LayerID) bool {
+	return s.cfg.MaxFetchAhead > 0 && !s.cfg.Light && lid.After(s.mesh.ProcessedLayer().Add(s.cfg.MaxFetchAhead))
+}
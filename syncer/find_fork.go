@@ -18,6 +18,7 @@ import (
 var (
 	ErrPeerMeshChangedMidSession = errors.New("peer mesh changed mid session")
 	ErrNodeMeshChangedMidSession = errors.New("node mesh changed mid session")
+	ErrInconsistentMeshHashes    = errors.New("inconsistent layers for mesh hashes")
 )
 
 type layerHash struct {
@@ -291,7 +292,7 @@ func (ff *ForkFinder) sendRequest(ctx context.Context, logger log.Log, peer p2p.
 		log.Int("num_hashes", len(mh.Hashes)),
 	)
 	if int(count) != len(mh.Hashes) {
-		return nil, errors.New("inconsistent layers for mesh hashes")
+		return nil, ErrInconsistentMeshHashes
 	}
 	if mh.Hashes[0] != bnd.from.hash || mh.Hashes[count-1] != bnd.to.hash {
 		logger.With().Warning("peer boundary hashes have changed",
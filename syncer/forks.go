@@ -0,0 +1,53 @@
+package syncer
+
+import (
+	"sync"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/p2p"
+)
+
+// forkTracker tracks the peers on every side of the forks found by the node. once the node
+// processes the data fetched from all sides, the peers that reported a hash different from
+// the hash of the node are on the losing fork.
+type forkTracker struct {
+	mu sync.Mutex
+	// pending forks by the diverged layer.
+	pending map[types.LayerID]map[p2p.Peer]types.Hash32
+}
+
+func newForkTracker() *forkTracker {
+	return &forkTracker{pending: map[types.LayerID]map[p2p.Peer]types.Hash32{}}
+}
+
+// add records the hash reported by the peer for the diverged layer.
+func (f *forkTracker) add(lid types.LayerID, peer p2p.Peer, hash types.Hash32) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.pending[lid]; !ok {
+		f.pending[lid] = map[p2p.Peer]types.Hash32{}
+	}
+	f.pending[lid][peer] = hash
+}
+
+// resolve forgets the fork at the layer and returns the peers that reported hashes different
+// from the adopted hash of the layer.
+func (f *forkTracker) resolve(lid types.LayerID, adopted types.Hash32) []p2p.Peer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	peers, ok := f.pending[lid]
+	if !ok {
+		return nil
+	}
+	delete(f.pending, lid)
+	var losers []p2p.Peer
+	for peer, hash := range peers {
+		if hash == adopted {
+			continue
+		}
+		losers = append(losers, peer)
+	}
+	forksResolved.Inc()
+	losingPeers.Add(float64(len(losers)))
+	return losers
+}
@@ -0,0 +1,26 @@
+package syncer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/p2p"
+)
+
+func TestForkTracker(t *testing.T) {
+	tracker := newForkTracker()
+	lid := types.LayerID(10)
+	winner := types.RandomHash()
+	loser := types.RandomHash()
+	tracker.add(lid, p2p.Peer("a"), winner)
+	tracker.add(lid, p2p.Peer("b"), loser)
+	tracker.add(lid.Add(1), p2p.Peer("c"), loser)
+
+	require.Empty(t, tracker.resolve(lid.Sub(1), winner))
+	require.Equal(t, []p2p.Peer{"b"}, tracker.resolve(lid, winner))
+	// resolved forks are forgotten
+	require.Empty(t, tracker.resolve(lid, types.RandomHash()))
+	require.Len(t, tracker.pending, 1)
+}
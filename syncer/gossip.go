@@ -0,0 +1,144 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/p2p"
+	"github.com/spacemeshos/go-spacemesh/p2p/pubsub"
+)
+
+var errNotSyncedGossip = errors.New("not synced for gossip")
+
+type bufferedMessage struct {
+	topic    string
+	peer     p2p.Peer
+	msg      []byte
+	received types.LayerID
+	handler  pubsub.GossipHandler
+	// atx is true for messages gated by ListenToATXGossip.
+	atx bool
+}
+
+// gossipBuffer keeps gossip messages received while the node is not synced, so that they can
+// be replayed once it catches up. messages are ordered by the layer they were received in.
+// when the buffer is full the oldest message is dropped, as it is the furthest from the layers
+// the node will be working on after sync.
+type gossipBuffer struct {
+	mu   sync.Mutex
+	size int
+	msgs []bufferedMessage
+}
+
+func newGossipBuffer(size int) *gossipBuffer {
+	return &gossipBuffer{size: size}
+}
+
+// add buffers the message if listening returns false. the check is done while holding the lock,
+// so that a message is never buffered after the buffer was taken for replay.
+func (b *gossipBuffer) add(listening func() bool, msg bufferedMessage) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if listening() {
+		return false
+	}
+	if b.size == 0 {
+		gossipDropped.Inc()
+		return true
+	}
+	if len(b.msgs) == b.size {
+		b.msgs = b.msgs[1:]
+		gossipDropped.Inc()
+	}
+	b.msgs = append(b.msgs, msg)
+	gossipBuffered.Inc()
+	return true
+}
+
+// take removes and returns buffered messages gated by ListenToATXGossip if atx is true,
+// or by ListenToGossip otherwise.
+func (b *gossipBuffer) take(atx bool) []bufferedMessage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var taken, kept []bufferedMessage
+	for _, msg := range b.msgs {
+		if msg.atx == atx {
+			taken = append(taken, msg)
+		} else {
+			kept = append(kept, msg)
+		}
+	}
+	b.msgs = kept
+	return taken
+}
+
+// GossipHandler returns the gossip handler for the topic that is gated by ListenToGossip.
+// messages received while the node is not synced are buffered and replayed through the
+// handler once the node is synced for gossip.
+func (s *Syncer) GossipHandler(topic string, handler pubsub.GossipHandler) pubsub.GossipHandler {
+	return s.bufferingHandler(topic, handler, false)
+}
+
+// ATXGossipHandler is the same as GossipHandler, but gated by ListenToATXGossip.
+func (s *Syncer) ATXGossipHandler(topic string, handler pubsub.GossipHandler) pubsub.GossipHandler {
+	return s.bufferingHandler(topic, handler, true)
+}
+
+func (s *Syncer) bufferingHandler(topic string, handler pubsub.GossipHandler, atx bool) pubsub.GossipHandler {
+	listening := s.ListenToGossip
+	if atx {
+		listening = s.ListenToATXGossip
+	}
+	return func(ctx context.Context, peer p2p.Peer, msg []byte) error {
+		if s.gossip.add(listening, bufferedMessage{
+			topic:    topic,
+			peer:     peer,
+			msg:      msg,
+			received: s.ticker.CurrentLayer(),
+			handler:  handler,
+			atx:      atx,
+		}) {
+			return errNotSyncedGossip
+		}
+		return handler(ctx, peer, msg)
+	}
+}
+
+// replayGossip passes buffered messages to their handlers. messages received more than
+// GossipBufferLayers before the current layer are dropped, as they are unlikely to be relevant.
+func (s *Syncer) replayGossip(ctx context.Context, atx bool) {
+	msgs := s.gossip.take(atx)
+	if len(msgs) == 0 {
+		return
+	}
+	s.eg.Go(func() error {
+		current := s.ticker.CurrentLayer()
+		replayed := 0
+		for _, msg := range msgs {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if msg.received.Add(s.cfg.GossipBufferLayers) < current {
+				gossipDropped.Inc()
+				continue
+			}
+			if err := msg.handler(ctx, msg.peer, msg.msg); err != nil {
+				s.logger.WithContext(ctx).With().Debug("buffered gossip rejected",
+					log.String("topic", msg.topic),
+					log.Stringer("peer", msg.peer),
+					log.Err(err),
+				)
+			}
+			replayed++
+			gossipReplayed.Inc()
+		}
+		s.logger.WithContext(ctx).With().Info("replayed buffered gossip",
+			log.Int("buffered", len(msgs)),
+			log.Int("replayed", replayed),
+		)
+		return nil
+	})
+}
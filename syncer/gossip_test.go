@@ -0,0 +1,129 @@
+package syncer
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/p2p"
+)
+
+type gossipRecorder struct {
+	mu   sync.Mutex
+	msgs []string
+}
+
+func (r *gossipRecorder) handle(_ context.Context, _ p2p.Peer, msg []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.msgs = append(r.msgs, string(msg))
+	return nil
+}
+
+func (r *gossipRecorder) received() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.msgs...)
+}
+
+func newGossipSyncer(t *testing.T, size int) *testSyncer {
+	ts := newSyncerWithoutSyncTimer(t)
+	ts.syncer.gossip = newGossipBuffer(size)
+	ts.syncer.cfg.GossipBufferLayers = 2
+	ts.mTicker.advanceToLayer(10)
+	return ts
+}
+
+func TestGossipBuffer_Replay(t *testing.T) {
+	ts := newGossipSyncer(t, 10)
+	ctx := context.Background()
+	var r gossipRecorder
+	handler := ts.syncer.GossipHandler("topic", r.handle)
+
+	require.ErrorIs(t, handler(ctx, "p1", []byte("a")), errNotSyncedGossip)
+	require.ErrorIs(t, handler(ctx, "p2", []byte("b")), errNotSyncedGossip)
+	require.Empty(t, r.received())
+
+	ts.syncer.setSyncState(ctx, gossipSync)
+	require.Eventually(t, func() bool {
+		return len(r.received()) == 2
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, []string{"a", "b"}, r.received())
+
+	require.NoError(t, handler(ctx, "p1", []byte("c")))
+	require.Equal(t, []string{"a", "b", "c"}, r.received())
+}
+
+func TestGossipBuffer_DropsOldest(t *testing.T) {
+	ts := newGossipSyncer(t, 2)
+	ctx := context.Background()
+	var r gossipRecorder
+	handler := ts.syncer.GossipHandler("topic", r.handle)
+
+	for _, msg := range []string{"a", "b", "c"} {
+		require.ErrorIs(t, handler(ctx, "p1", []byte(msg)), errNotSyncedGossip)
+	}
+	ts.syncer.setSyncState(ctx, synced)
+	require.Eventually(t, func() bool {
+		return len(r.received()) == 2
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, []string{"b", "c"}, r.received())
+}
+
+func TestGossipBuffer_DropsStale(t *testing.T) {
+	ts := newGossipSyncer(t, 10)
+	ctx := context.Background()
+	var r gossipRecorder
+	handler := ts.syncer.GossipHandler("topic", r.handle)
+
+	require.ErrorIs(t, handler(ctx, "p1", []byte("stale")), errNotSyncedGossip)
+	ts.mTicker.advanceToLayer(12)
+	require.ErrorIs(t, handler(ctx, "p1", []byte("fresh")), errNotSyncedGossip)
+	ts.mTicker.advanceToLayer(13)
+
+	ts.syncer.setSyncState(ctx, gossipSync)
+	require.Eventually(t, func() bool {
+		return len(r.received()) == 1
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, []string{"fresh"}, r.received())
+}
+
+func TestGossipBuffer_ATX(t *testing.T) {
+	ts := newGossipSyncer(t, 10)
+	ctx := context.Background()
+	var data, atxs gossipRecorder
+	handler := ts.syncer.GossipHandler("data", data.handle)
+	atxHandler := ts.syncer.ATXGossipHandler("atx", atxs.handle)
+
+	require.ErrorIs(t, handler(ctx, "p1", []byte("a")), errNotSyncedGossip)
+	require.ErrorIs(t, atxHandler(ctx, "p1", []byte("b")), errNotSyncedGossip)
+
+	ts.syncer.setATXSynced(ctx)
+	require.Eventually(t, func() bool {
+		return len(atxs.received()) == 1
+	}, time.Second, 10*time.Millisecond)
+	require.Empty(t, data.received())
+	require.NoError(t, atxHandler(ctx, "p1", []byte("c")))
+	require.Equal(t, []string{"b", "c"}, atxs.received())
+
+	ts.syncer.setSyncState(ctx, gossipSync)
+	require.Eventually(t, func() bool {
+		return len(data.received()) == 1
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestGossipBuffer_Disabled(t *testing.T) {
+	ts := newGossipSyncer(t, 0)
+	ctx := context.Background()
+	var r gossipRecorder
+	handler := ts.syncer.GossipHandler("topic", r.handle)
+
+	require.ErrorIs(t, handler(ctx, "p1", []byte("a")), errNotSyncedGossip)
+	ts.syncer.setSyncState(ctx, synced)
+	require.Never(t, func() bool {
+		return len(r.received()) > 0
+	}, 100*time.Millisecond, 10*time.Millisecond)
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/datastore"
 	"github.com/spacemeshos/go-spacemesh/fetch"
 	"github.com/spacemeshos/go-spacemesh/p2p"
 )
@@ -48,9 +49,18 @@ type fetcher interface {
 
 	GetPeers() []p2p.Peer
 	PeerEpochInfo(context.Context, p2p.Peer, types.EpochID) (*fetch.EpochData, error)
+	PeerEpochPage(context.Context, p2p.Peer, *fetch.EpochPageRequest) (*fetch.EpochPage, error)
 	PeerMeshHashes(context.Context, p2p.Peer, *fetch.MeshHashRequest) (*fetch.MeshHashes, error)
 }
 
+// backfillFetcher is the interface to the low-level fetching for the layers below the checkpoint.
+type backfillFetcher interface {
+	GetPeers() []p2p.Peer
+	GetLayerOpinions(context.Context, []p2p.Peer, types.LayerID, func([]byte, p2p.Peer), func(error, p2p.Peer)) error
+	RegisterPeerHashes(peer p2p.Peer, hashes []types.Hash32)
+	GetHashes(context.Context, []types.Hash32, datastore.Hint, fetch.SyncValidator) error
+}
+
 type layerPatrol interface {
 	IsHareInCharge(types.LayerID) bool
 }
@@ -0,0 +1,69 @@
+package syncer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/fetch"
+	"github.com/spacemeshos/go-spacemesh/p2p"
+)
+
+// isolatedPeers tracks peers that reported aggregated layer hash they couldn't back up with data.
+// opinions from such peers are ignored until isolation expires.
+type isolatedPeers struct {
+	mu    sync.Mutex
+	peers map[p2p.Peer]time.Time
+}
+
+func newIsolatedPeers() *isolatedPeers {
+	return &isolatedPeers{peers: map[p2p.Peer]time.Time{}}
+}
+
+func (i *isolatedPeers) isolate(peer p2p.Peer, until time.Time) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if _, exist := i.peers[peer]; !exist {
+		numIsolatedPeers.Inc()
+	}
+	i.peers[peer] = until
+}
+
+func (i *isolatedPeers) isolated(peer p2p.Peer, now time.Time) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	until, exist := i.peers[peer]
+	if !exist {
+		return false
+	}
+	if !now.Before(until) {
+		delete(i.peers, peer)
+		numIsolatedPeers.Dec()
+		return false
+	}
+	return true
+}
+
+// filter returns opinions from peers that are not isolated.
+func (i *isolatedPeers) filter(opinions []*fetch.LayerOpinion, now time.Time) []*fetch.LayerOpinion {
+	rst := make([]*fetch.LayerOpinion, 0, len(opinions))
+	for _, opn := range opinions {
+		if i.isolated(opn.Peer(), now) {
+			continue
+		}
+		rst = append(rst, opn)
+	}
+	return rst
+}
+
+// countHashes counts the number of peers that reported each aggregated hash.
+func countHashes(opinions []*fetch.LayerOpinion) map[types.Hash32]int {
+	rst := map[types.Hash32]int{}
+	for _, opn := range opinions {
+		if opn.PrevAggHash == (types.Hash32{}) {
+			continue
+		}
+		rst[opn.PrevAggHash]++
+	}
+	return rst
+}
@@ -0,0 +1,39 @@
+package syncer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/fetch"
+	"github.com/spacemeshos/go-spacemesh/p2p"
+)
+
+func TestIsolatedPeers(t *testing.T) {
+	isolated := newIsolatedPeers()
+	now := time.Now()
+	isolated.isolate("a", now.Add(time.Minute))
+
+	require.True(t, isolated.isolated("a", now))
+	require.False(t, isolated.isolated("b", now))
+
+	opns := []*fetch.LayerOpinion{{}, {}}
+	opns[0].SetPeer("a")
+	opns[1].SetPeer("b")
+	filtered := isolated.filter(opns, now)
+	require.Len(t, filtered, 1)
+	require.Equal(t, p2p.Peer("b"), filtered[0].Peer())
+
+	require.False(t, isolated.isolated("a", now.Add(time.Minute)))
+	require.Len(t, isolated.filter(opns, now), 2)
+}
+
+func TestCountHashes(t *testing.T) {
+	h1, h2 := types.RandomHash(), types.RandomHash()
+	opns := []*fetch.LayerOpinion{
+		{PrevAggHash: h1}, {PrevAggHash: h2}, {PrevAggHash: h1}, {},
+	}
+	require.Equal(t, map[types.Hash32]int{h1: 2, h2: 1}, countHashes(opns))
+}
@@ -0,0 +1,88 @@
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/fetch"
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/sql/blocks"
+	"github.com/spacemeshos/go-spacemesh/sql/certificates"
+	"github.com/spacemeshos/go-spacemesh/sql/layers"
+)
+
+// headersOnly returns true if the layer is synced without its content in the light mode.
+// certificates are validated against the active set of the epoch, which is derived from the first
+// certified block in the epoch and the ballots before it. so the layers are synced in full until
+// the epoch has a certified block.
+func (s *Syncer) headersOnly(lid types.LayerID) bool {
+	if !s.cfg.Light {
+		return false
+	}
+	bid, err := certificates.FirstInEpoch(s.cdb, lid.GetEpoch())
+	if err != nil {
+		return false
+	}
+	first, err := blocks.GetLayer(s.cdb, bid)
+	if err != nil {
+		return false
+	}
+	return first.Before(lid)
+}
+
+// syncLayerLight syncs the aggregated hash and the certificate of the layer, and its content only
+// if it is needed to validate certificates. the layer is never processed by the mesh.
+func (s *Syncer) syncLayerLight(ctx context.Context, lid types.LayerID) error {
+	if !s.headersOnly(lid) {
+		if err := s.dataFetcher.PollLayerData(ctx, lid); err != nil {
+			return fmt.Errorf("PollLayerData: %w", err)
+		}
+	}
+	opinions, err := s.fetchOpinions(ctx, lid)
+	if err != nil {
+		return err
+	}
+	opinions = s.isolated.filter(opinions, time.Now())
+	if err := s.adoptAggHash(ctx, lid.Sub(1), opinions); err != nil {
+		return err
+	}
+	if err := s.adopt(ctx, lid, opinions); err != nil {
+		return err
+	}
+	dataLayer.Set(float64(lid))
+	return nil
+}
+
+// adoptAggHash saves the aggregated hash of the layer if it is reported by the quorum of peers, and
+// by the majority of the peers that reported it, so that a single peer can't make the node adopt a hash.
+// the node doesn't compute aggregated hashes in the light mode, as it doesn't have the content of the layers.
+func (s *Syncer) adoptAggHash(ctx context.Context, lid types.LayerID, opinions []*fetch.LayerOpinion) error {
+	if !lid.After(types.GetEffectiveGenesis()) {
+		return nil
+	}
+	var (
+		hash        types.Hash32
+		most, total int
+	)
+	for h, n := range countHashes(opinions) {
+		total += n
+		if n > most {
+			hash, most = h, n
+		}
+	}
+	if most == 0 {
+		return nil
+	}
+	if most < s.cfg.LightQuorum || 2*most <= total {
+		s.logger.WithContext(ctx).With().Warning("not enough peers agree on aggregated hash", lid,
+			log.Stringer("hash", hash),
+			log.Int("agree", most),
+			log.Int("reported", total),
+			log.Int("quorum", s.cfg.LightQuorum),
+		)
+		return nil
+	}
+	return layers.SetMeshHash(s.cdb, lid, hash)
+}
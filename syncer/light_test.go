@@ -0,0 +1,98 @@
+package syncer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/fetch"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/blocks"
+	"github.com/spacemeshos/go-spacemesh/sql/certificates"
+	"github.com/spacemeshos/go-spacemesh/sql/layers"
+)
+
+func newLightSyncer(t *testing.T) *testSyncer {
+	ts := newSyncerWithoutSyncTimer(t)
+	ts.syncer.cfg.Light = true
+	ts.syncer.cfg.LightQuorum = 2
+	return ts
+}
+
+func certifiedBlock(t *testing.T, ts *testSyncer, lid types.LayerID) *types.Certificate {
+	block := types.NewExistingBlock(types.RandomBlockID(), types.InnerBlock{LayerIndex: lid})
+	require.NoError(t, blocks.Add(ts.cdb, block))
+	cert := &types.Certificate{BlockID: block.ID()}
+	require.NoError(t, certificates.Add(ts.cdb, lid, cert))
+	return cert
+}
+
+func TestSyncer_LightFirstCertInEpoch(t *testing.T) {
+	ts := newLightSyncer(t)
+	lid := types.GetEffectiveGenesis().Add(1)
+	cert := &types.Certificate{BlockID: types.RandomBlockID()}
+
+	// the layers are synced in full until the epoch has a certified block
+	ts.mDataFetcher.EXPECT().PollLayerData(gomock.Any(), lid)
+	ts.mDataFetcher.EXPECT().PollLayerOpinions(gomock.Any(), lid).Return([]*fetch.LayerOpinion{{Cert: cert}}, nil)
+	ts.mDataFetcher.EXPECT().GetBlocks(gomock.Any(), []types.BlockID{cert.BlockID})
+	ts.mCertHdr.EXPECT().HandleSyncedCertificate(gomock.Any(), lid, cert)
+	require.NoError(t, ts.syncer.syncLayer(context.Background(), lid))
+}
+
+func TestSyncer_LightHeadersOnly(t *testing.T) {
+	ts := newLightSyncer(t)
+	first := types.GetEffectiveGenesis().Add(1)
+	certifiedBlock(t, ts, first)
+
+	lid := first.Add(1)
+	hash := types.RandomHash()
+	cert := &types.Certificate{BlockID: types.RandomBlockID()}
+	ts.mDataFetcher.EXPECT().PollLayerOpinions(gomock.Any(), lid).Return([]*fetch.LayerOpinion{
+		{PrevAggHash: hash, Cert: cert},
+		{PrevAggHash: hash},
+		{},
+	}, nil)
+	ts.mCertHdr.EXPECT().HandleSyncedCertificate(gomock.Any(), lid, cert)
+	require.NoError(t, ts.syncer.syncLayer(context.Background(), lid))
+
+	got, err := layers.GetAggregatedHash(ts.cdb, first)
+	require.NoError(t, err)
+	require.Equal(t, hash, got)
+
+	// layers are not processed by the mesh in the light mode
+	require.NoError(t, ts.syncer.processLayers(context.Background()))
+}
+
+func TestSyncer_LightAggHashDisagreement(t *testing.T) {
+	ts := newLightSyncer(t)
+	first := types.GetEffectiveGenesis().Add(1)
+	certifiedBlock(t, ts, first)
+
+	lid := first.Add(1)
+	hash := types.RandomHash()
+	for _, opinions := range [][]*fetch.LayerOpinion{
+		// peers disagree
+		{{PrevAggHash: hash}, {PrevAggHash: hash}, {PrevAggHash: types.RandomHash()}, {PrevAggHash: types.RandomHash()}},
+		// a single peer is not trusted
+		{{PrevAggHash: hash}, {}},
+	} {
+		ts.mDataFetcher.EXPECT().PollLayerOpinions(gomock.Any(), lid).Return(opinions, nil)
+		require.NoError(t, ts.syncer.syncLayer(context.Background(), lid))
+
+		_, err := layers.GetAggregatedHash(ts.cdb, first)
+		require.ErrorIs(t, err, sql.ErrNotFound)
+	}
+
+	// the majority of peers agrees
+	ts.mDataFetcher.EXPECT().PollLayerOpinions(gomock.Any(), lid).Return([]*fetch.LayerOpinion{
+		{PrevAggHash: hash}, {PrevAggHash: hash}, {PrevAggHash: types.RandomHash()},
+	}, nil)
+	require.NoError(t, ts.syncer.syncLayer(context.Background(), lid))
+	got, err := layers.GetAggregatedHash(ts.cdb, first)
+	require.NoError(t, err)
+	require.Equal(t, hash, got)
+}
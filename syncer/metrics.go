@@ -1,6 +1,8 @@
 package syncer
 
 import (
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/spacemeshos/go-spacemesh/metrics"
 )
 
@@ -87,4 +89,64 @@ var (
 	layerPeerError = peerError.WithLabelValues("layer")
 	opnsPeerError  = peerError.WithLabelValues("opns")
 	malPeerError   = peerError.WithLabelValues("mal")
+
+	numIsolatedPeers = metrics.NewGauge(
+		"isolated_peers",
+		namespace,
+		"number of peers isolated for serving divergent mesh data",
+		[]string{},
+	).WithLabelValues()
+
+	layerFetches = metrics.NewCounter(
+		"layer_fetches",
+		namespace,
+		"number of layers fetched by worker and outcome",
+		[]string{"worker", "outcome"},
+	)
+
+	layerFetchDuration = metrics.NewHistogramWithBuckets(
+		"layer_fetch_duration",
+		namespace,
+		"duration in seconds to fetch a layer by worker",
+		[]string{"worker"},
+		prometheus.ExponentialBuckets(0.01, 2, 12),
+	)
+
+	fetchBackpressure = metrics.NewCounter(
+		"fetch_backpressure",
+		namespace,
+		"number of sync runs that stopped fetching layers until validation catches up",
+		[]string{},
+	).WithLabelValues()
+
+	forksResolved = metrics.NewCounter(
+		"forks_resolved",
+		namespace,
+		"number of mesh forks resolved after fetching data from all sides",
+		[]string{},
+	).WithLabelValues()
+
+	losingPeers = metrics.NewCounter(
+		"losing_fork_peers",
+		namespace,
+		"number of peers found on the losing side of mesh forks",
+		[]string{},
+	).WithLabelValues()
+
+	gossipMessages = metrics.NewCounter(
+		"buffered_gossip",
+		namespace,
+		"number of gossip messages received while not synced by outcome",
+		[]string{"outcome"},
+	)
+	gossipBuffered = gossipMessages.WithLabelValues("buffered")
+	gossipDropped  = gossipMessages.WithLabelValues("dropped")
+	gossipReplayed = gossipMessages.WithLabelValues("replayed")
+
+	backfilledLayer = metrics.NewGauge(
+		"backfilled_layer",
+		namespace,
+		"lowest layer below the checkpoint backfilled from peers",
+		[]string{},
+	).WithLabelValues()
 )
@@ -11,6 +11,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	types "github.com/spacemeshos/go-spacemesh/common/types"
+	datastore "github.com/spacemeshos/go-spacemesh/datastore"
 	fetch "github.com/spacemeshos/go-spacemesh/fetch"
 	p2p "github.com/spacemeshos/go-spacemesh/p2p"
 )
@@ -288,6 +289,21 @@ func (mr *MockfetchLogicMockRecorder) PeerEpochInfo(arg0, arg1, arg2 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PeerEpochInfo", reflect.TypeOf((*MockfetchLogic)(nil).PeerEpochInfo), arg0, arg1, arg2)
 }
 
+// PeerEpochPage mocks base method.
+func (m *MockfetchLogic) PeerEpochPage(arg0 context.Context, arg1 p2p.Peer, arg2 *fetch.EpochPageRequest) (*fetch.EpochPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PeerEpochPage", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*fetch.EpochPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PeerEpochPage indicates an expected call of PeerEpochPage.
+func (mr *MockfetchLogicMockRecorder) PeerEpochPage(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PeerEpochPage", reflect.TypeOf((*MockfetchLogic)(nil).PeerEpochPage), arg0, arg1, arg2)
+}
+
 // PeerMeshHashes mocks base method.
 func (m *MockfetchLogic) PeerMeshHashes(arg0 context.Context, arg1 p2p.Peer, arg2 *fetch.MeshHashRequest) (*fetch.MeshHashes, error) {
 	m.ctrl.T.Helper()
@@ -513,6 +529,21 @@ func (mr *MockfetcherMockRecorder) PeerEpochInfo(arg0, arg1, arg2 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PeerEpochInfo", reflect.TypeOf((*Mockfetcher)(nil).PeerEpochInfo), arg0, arg1, arg2)
 }
 
+// PeerEpochPage mocks base method.
+func (m *Mockfetcher) PeerEpochPage(arg0 context.Context, arg1 p2p.Peer, arg2 *fetch.EpochPageRequest) (*fetch.EpochPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PeerEpochPage", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*fetch.EpochPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PeerEpochPage indicates an expected call of PeerEpochPage.
+func (mr *MockfetcherMockRecorder) PeerEpochPage(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PeerEpochPage", reflect.TypeOf((*Mockfetcher)(nil).PeerEpochPage), arg0, arg1, arg2)
+}
+
 // PeerMeshHashes mocks base method.
 func (m *Mockfetcher) PeerMeshHashes(arg0 context.Context, arg1 p2p.Peer, arg2 *fetch.MeshHashRequest) (*fetch.MeshHashes, error) {
 	m.ctrl.T.Helper()
@@ -540,6 +571,83 @@ func (mr *MockfetcherMockRecorder) RegisterPeerHashes(peer, hashes interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterPeerHashes", reflect.TypeOf((*Mockfetcher)(nil).RegisterPeerHashes), peer, hashes)
 }
 
+// MockbackfillFetcher is a mock of backfillFetcher interface.
+type MockbackfillFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockbackfillFetcherMockRecorder
+}
+
+// MockbackfillFetcherMockRecorder is the mock recorder for MockbackfillFetcher.
+type MockbackfillFetcherMockRecorder struct {
+	mock *MockbackfillFetcher
+}
+
+// NewMockbackfillFetcher creates a new mock instance.
+func NewMockbackfillFetcher(ctrl *gomock.Controller) *MockbackfillFetcher {
+	mock := &MockbackfillFetcher{ctrl: ctrl}
+	mock.recorder = &MockbackfillFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockbackfillFetcher) EXPECT() *MockbackfillFetcherMockRecorder {
+	return m.recorder
+}
+
+// GetHashes mocks base method.
+func (m *MockbackfillFetcher) GetHashes(arg0 context.Context, arg1 []types.Hash32, arg2 datastore.Hint, arg3 fetch.SyncValidator) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHashes", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetHashes indicates an expected call of GetHashes.
+func (mr *MockbackfillFetcherMockRecorder) GetHashes(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHashes", reflect.TypeOf((*MockbackfillFetcher)(nil).GetHashes), arg0, arg1, arg2, arg3)
+}
+
+// GetLayerOpinions mocks base method.
+func (m *MockbackfillFetcher) GetLayerOpinions(arg0 context.Context, arg1 []p2p.Peer, arg2 types.LayerID, arg3 func([]byte, p2p.Peer), arg4 func(error, p2p.Peer)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLayerOpinions", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetLayerOpinions indicates an expected call of GetLayerOpinions.
+func (mr *MockbackfillFetcherMockRecorder) GetLayerOpinions(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLayerOpinions", reflect.TypeOf((*MockbackfillFetcher)(nil).GetLayerOpinions), arg0, arg1, arg2, arg3, arg4)
+}
+
+// GetPeers mocks base method.
+func (m *MockbackfillFetcher) GetPeers() []p2p.Peer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPeers")
+	ret0, _ := ret[0].([]p2p.Peer)
+	return ret0
+}
+
+// GetPeers indicates an expected call of GetPeers.
+func (mr *MockbackfillFetcherMockRecorder) GetPeers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPeers", reflect.TypeOf((*MockbackfillFetcher)(nil).GetPeers))
+}
+
+// RegisterPeerHashes mocks base method.
+func (m *MockbackfillFetcher) RegisterPeerHashes(peer p2p.Peer, hashes []types.Hash32) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RegisterPeerHashes", peer, hashes)
+}
+
+// RegisterPeerHashes indicates an expected call of RegisterPeerHashes.
+func (mr *MockbackfillFetcherMockRecorder) RegisterPeerHashes(peer, hashes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterPeerHashes", reflect.TypeOf((*MockbackfillFetcher)(nil).RegisterPeerHashes), peer, hashes)
+}
+
 // MocklayerPatrol is a mock of layerPatrol interface.
 type MocklayerPatrol struct {
 	ctrl     *gomock.Controller
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"go.uber.org/zap/zapcore"
@@ -14,6 +15,7 @@ import (
 	"github.com/spacemeshos/go-spacemesh/log"
 	"github.com/spacemeshos/go-spacemesh/mesh"
 	"github.com/spacemeshos/go-spacemesh/p2p"
+	"github.com/spacemeshos/go-spacemesh/p2p/pubsub"
 	"github.com/spacemeshos/go-spacemesh/sql"
 	"github.com/spacemeshos/go-spacemesh/sql/certificates"
 	"github.com/spacemeshos/go-spacemesh/sql/layers"
@@ -35,21 +37,22 @@ func (s *Syncer) stateSynced() bool {
 
 func (s *Syncer) processLayers(ctx context.Context) error {
 	ctx = log.WithNewSessionID(ctx)
-	if !s.ticker.CurrentLayer().After(types.GetEffectiveGenesis()) {
+	if !s.ticker.CurrentLayer().After(types.GetEffectiveGenesis()) || s.cfg.Light {
 		return nil
 	}
 	if !s.ListenToATXGossip() {
 		return errATXsNotSynced
 	}
 
+	status := s.mesh.MeshStatus()
 	s.logger.WithContext(ctx).With().Debug("processing synced layers",
 		log.Stringer("current", s.ticker.CurrentLayer()),
-		log.Stringer("processed", s.mesh.ProcessedLayer()),
-		log.Stringer("in_state", s.mesh.LatestLayerInState()),
+		log.Stringer("processed", status.Processed),
+		log.Stringer("in_state", status.Applied),
 		log.Stringer("last_synced", s.getLastSyncedLayer()),
 	)
 
-	start := minLayer(s.mesh.LatestLayerInState(), s.mesh.ProcessedLayer())
+	start := minLayer(status.Applied, status.Processed)
 	start = minLayer(start, s.getLastSyncedLayer())
 	if start == types.GetEffectiveGenesis() {
 		start = start.Add(1)
@@ -80,6 +83,7 @@ func (s *Syncer) processLayers(ctx context.Context) error {
 		}
 
 		if opinions, err := s.fetchOpinions(ctx, lid); err == nil {
+			opinions = s.isolated.filter(opinions, time.Now())
 			if s.stateSynced() {
 				if err = s.checkMeshAgreement(ctx, lid, opinions); err != nil && errors.Is(err, errMeshHashDiverged) {
 					s.logger.WithContext(ctx).With().Debug("mesh hash diverged, trying to reach agreement",
@@ -107,13 +111,16 @@ func (s *Syncer) processLayers(ctx context.Context) error {
 			if !errors.Is(err, mesh.ErrMissingBlock) {
 				s.logger.WithContext(ctx).With().Warning("mesh failed to process layer from sync", lid, log.Err(err))
 			}
+		} else {
+			s.resolveFork(ctx, lid.Sub(1))
 		}
 	}
+	status = s.mesh.MeshStatus()
 	s.logger.WithContext(ctx).With().Debug("end of state sync",
 		log.Bool("state_synced", s.stateSynced()),
 		log.Stringer("current", s.ticker.CurrentLayer()),
-		log.Stringer("processed", s.mesh.ProcessedLayer()),
-		log.Stringer("in_state", s.mesh.LatestLayerInState()),
+		log.Stringer("processed", status.Processed),
+		log.Stringer("in_state", status.Applied),
 		log.Stringer("last_synced", s.getLastSyncedLayer()),
 	)
 	return nil
@@ -196,7 +203,8 @@ func (s *Syncer) adopt(ctx context.Context, lid types.LayerID, opinions []*fetch
 func (s *Syncer) certCutoffLayer() types.LayerID {
 	cutoff := types.GetEffectiveGenesis()
 	last := s.ticker.CurrentLayer()
-	if last.Uint32() > s.cfg.SyncCertDistance {
+	// light node syncs certificates for all layers, as it doesn't have tortoise opinions on them
+	if !s.cfg.Light && last.Uint32() > s.cfg.SyncCertDistance {
 		limit := last.Sub(s.cfg.SyncCertDistance)
 		if limit.After(cutoff) {
 			cutoff = limit
@@ -206,7 +214,7 @@ func (s *Syncer) certCutoffLayer() types.LayerID {
 }
 
 func (s *Syncer) adoptCert(ctx context.Context, lid types.LayerID, cert *types.Certificate) error {
-	if cert.BlockID != types.EmptyBlockID {
+	if cert.BlockID != types.EmptyBlockID && !s.headersOnly(lid) {
 		if err := s.dataFetcher.GetBlocks(ctx, []types.BlockID{cert.BlockID}); err != nil {
 			return fmt.Errorf("fetch block in cert %v: %w", cert.BlockID, err)
 		}
@@ -235,6 +243,15 @@ func (s *Syncer) ensureMeshAgreement(
 		fork types.LayerID
 		ed   *fetch.EpochData
 	)
+	// cross-check hashes reported by peers. hashes that are reported by more peers
+	// are tried first, so that a single peer can't steer the node away from the majority.
+	counts := countHashes(opinions)
+	// only the layers where some peers disagree with the node are tracked as forks
+	forked := len(counts) > 1 || (len(counts) == 1 && counts[prevHash] == 0)
+	opinions = append([]*fetch.LayerOpinion(nil), opinions...)
+	sort.SliceStable(opinions, func(i, j int) bool {
+		return counts[opinions[i].PrevAggHash] > counts[opinions[j].PrevAggHash]
+	})
 	for _, opn := range opinions {
 		if opn.PrevAggHash == (types.Hash32{}) {
 			continue
@@ -242,6 +259,9 @@ func (s *Syncer) ensureMeshAgreement(
 		if _, ok := resyncPeers[opn.Peer()]; ok {
 			continue
 		}
+		if forked {
+			s.forks.add(prevLid, opn.Peer(), opn.PrevAggHash)
+		}
 		if opn.PrevAggHash == prevHash {
 			s.forkFinder.UpdateAgreement(opn.Peer(), prevLid, prevHash, time.Now())
 			continue
@@ -253,6 +273,8 @@ func (s *Syncer) ensureMeshAgreement(
 			log.Stringer("peer", peer),
 			log.Stringer("disagreed", prevLid),
 			log.Stringer("peer_hash", opn.PrevAggHash),
+			log.Int("peers_with_hash", counts[opn.PrevAggHash]),
+			log.Int("peers_with_node_hash", counts[prevHash]),
 		)
 
 		if !s.forkFinder.NeedResync(prevLid, opn.PrevAggHash) {
@@ -308,6 +330,9 @@ func (s *Syncer) ensureMeshAgreement(
 						return nil
 					})),
 					log.Err(err))
+				if disproved(ctx, err) {
+					s.isolate(ctx, peer, prevLid, opn.PrevAggHash)
+				}
 				continue
 			}
 		} else {
@@ -324,6 +349,9 @@ func (s *Syncer) ensureMeshAgreement(
 				log.Stringer("peer", peer),
 				log.Err(err),
 			)
+			if disproved(ctx, err) {
+				s.isolate(ctx, peer, prevLid, opn.PrevAggHash)
+			}
 			continue
 		}
 
@@ -354,3 +382,52 @@ func (s *Syncer) ensureMeshAgreement(
 	s.forkFinder.Purge(true)
 	return nil
 }
+
+// resolveFork reports peers that were on the losing side of the fork at the layer,
+// after the tortoise counted the data fetched from all sides of the fork.
+func (s *Syncer) resolveFork(ctx context.Context, lid types.LayerID) {
+	hash, err := layers.GetAggregatedHash(s.cdb, lid)
+	if err != nil {
+		return
+	}
+	losers := s.forks.resolve(lid, hash)
+	if len(losers) == 0 {
+		return
+	}
+	s.logger.WithContext(ctx).With().Info("resolved mesh fork",
+		log.Stringer("diverged", lid),
+		log.Stringer("adopted_hash", hash),
+		log.Array("losing_peers", log.ArrayMarshalerFunc(func(encoder zapcore.ArrayEncoder) error {
+			for _, peer := range losers {
+				encoder.AppendString(peer.String())
+			}
+			return nil
+		})),
+	)
+}
+
+// isolate ignores opinions from the peer that failed to back up its divergent mesh hash.
+// disproved returns true if the peer failed to back its mesh hash because it served invalid data
+// or inconsistent mesh hashes. timeouts, transport errors and cancellation of the sync don't tell
+// anything about the mesh of the peer.
+func disproved(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	return errors.Is(err, pubsub.ErrValidationReject) ||
+		errors.Is(err, fetch.ErrQuarantined) ||
+		errors.Is(err, ErrInconsistentMeshHashes)
+}
+
+func (s *Syncer) isolate(ctx context.Context, peer p2p.Peer, lid types.LayerID, hash types.Hash32) {
+	if s.cfg.PeerIsolation == 0 {
+		return
+	}
+	s.logger.WithContext(ctx).With().Warning("isolating peer serving divergent mesh data",
+		log.Stringer("peer", peer),
+		log.Stringer("disagreed", lid),
+		log.Stringer("peer_hash", hash),
+		log.Duration("duration", s.cfg.PeerIsolation),
+	)
+	s.isolated.isolate(peer, time.Now().Add(s.cfg.PeerIsolation))
+}
@@ -3,8 +3,10 @@ package syncer
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
@@ -15,6 +17,7 @@ import (
 	"github.com/spacemeshos/go-spacemesh/fetch"
 	vm "github.com/spacemeshos/go-spacemesh/genvm"
 	"github.com/spacemeshos/go-spacemesh/p2p"
+	"github.com/spacemeshos/go-spacemesh/p2p/pubsub"
 	"github.com/spacemeshos/go-spacemesh/sql/blocks"
 	"github.com/spacemeshos/go-spacemesh/sql/certificates"
 	"github.com/spacemeshos/go-spacemesh/sql/layers"
@@ -38,7 +41,7 @@ func TestProcessLayers_MultiLayers(t *testing.T) {
 	gLid := types.GetEffectiveGenesis()
 	ts := newSyncerWithoutSyncTimer(t)
 	ts.syncer.cfg.SyncCertDistance = 10000
-	ts.syncer.setATXSynced()
+	ts.syncer.setATXSynced(context.Background())
 	current := gLid.Add(10)
 	ts.syncer.setLastSyncedLayer(current.Sub(1))
 	ts.mTicker.advanceToLayer(current)
@@ -134,7 +137,7 @@ func TestProcessLayers_OpinionsNotAdopted(t *testing.T) {
 
 			ts := newSyncerWithoutSyncTimer(t)
 			require.NoError(t, layers.SetMeshHash(ts.cdb, gLid, prevHash))
-			ts.syncer.setATXSynced()
+			ts.syncer.setATXSynced(context.Background())
 			current := lid.Add(1)
 			ts.syncer.setLastSyncedLayer(current.Sub(1))
 			ts.mTicker.advanceToLayer(current)
@@ -199,7 +202,7 @@ func TestProcessLayers_ATXsNotSynced(t *testing.T) {
 func TestProcessLayers_Shutdown(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	ts := newTestSyncer(t, never)
-	ts.syncer.setATXSynced()
+	ts.syncer.setATXSynced(context.Background())
 
 	lastSynced := types.GetEffectiveGenesis().Add(1)
 	ts.syncer.setLastSyncedLayer(lastSynced)
@@ -212,7 +215,7 @@ func TestProcessLayers_Shutdown(t *testing.T) {
 
 func TestProcessLayers_HareIsStillWorking(t *testing.T) {
 	ts := newSyncerWithoutSyncTimer(t)
-	ts.syncer.setATXSynced()
+	ts.syncer.setATXSynced(context.Background())
 	lastSynced := types.GetEffectiveGenesis().Add(1)
 	ts.syncer.setLastSyncedLayer(lastSynced)
 	ts.mTicker.advanceToLayer(lastSynced.Add(1))
@@ -235,7 +238,7 @@ func TestProcessLayers_HareIsStillWorking(t *testing.T) {
 
 func TestProcessLayers_HareTakesTooLong(t *testing.T) {
 	ts := newSyncerWithoutSyncTimer(t)
-	ts.syncer.setATXSynced()
+	ts.syncer.setATXSynced(context.Background())
 	glayer := types.GetEffectiveGenesis()
 	lastSynced := glayer.Add(ts.syncer.cfg.HareDelayLayers)
 	ts.syncer.setLastSyncedLayer(lastSynced)
@@ -260,7 +263,7 @@ func TestProcessLayers_HareTakesTooLong(t *testing.T) {
 
 func TestProcessLayers_OpinionsOptional(t *testing.T) {
 	ts := newSyncerWithoutSyncTimer(t)
-	ts.syncer.setATXSynced()
+	ts.syncer.setATXSynced(context.Background())
 	lastSynced := types.GetEffectiveGenesis().Add(1)
 	ts.syncer.setLastSyncedLayer(lastSynced)
 	ts.mTicker.advanceToLayer(lastSynced.Add(1))
@@ -278,7 +281,8 @@ func TestProcessLayers_OpinionsOptional(t *testing.T) {
 
 func TestProcessLayers_MeshHashDiverged(t *testing.T) {
 	ts := newSyncerWithoutSyncTimer(t)
-	ts.syncer.setATXSynced()
+	ts.syncer.cfg.PeerIsolation = time.Hour
+	ts.syncer.setATXSynced(context.Background())
 	current := types.GetEffectiveGenesis().Add(131)
 	ts.mTicker.advanceToLayer(current)
 	for lid := types.GetEffectiveGenesis().Add(1); lid.Before(current); lid = lid.Add(1) {
@@ -311,9 +315,9 @@ func TestProcessLayers_MeshHashDiverged(t *testing.T) {
 	opns[1].PrevAggHash = prevHash
 	// node will engage hash resolution with p0 and p2 because
 	// p1 has the same mesh hash as node
-	// p3's ATXs are not available,
+	// p3's ATXs are invalid,
 	// p4 failed epoch info query
-	// p5 failed fork finding sessions
+	// p5 served inconsistent mesh hashes during fork finding
 	// p6 already had a fork-finding session from previous runs.
 	epoch := instate.GetEpoch()
 	errUnknown := errors.New("unknown")
@@ -352,7 +356,7 @@ func TestProcessLayers_MeshHashDiverged(t *testing.T) {
 	ts.mDataFetcher.EXPECT().GetAtxs(gomock.Any(), gomock.Any()).DoAndReturn(
 		func(_ context.Context, got []types.ATXID) error {
 			require.ElementsMatch(t, eds[3].AtxIDs, got)
-			return errors.New("not available")
+			return fmt.Errorf("atx: %w", pubsub.ErrValidationReject)
 		},
 	)
 	ts.mDataFetcher.EXPECT().GetAtxs(gomock.Any(), gomock.Any()).DoAndReturn(
@@ -365,7 +369,7 @@ func TestProcessLayers_MeshHashDiverged(t *testing.T) {
 	fork2 := types.LayerID(121)
 	ts.mForkFinder.EXPECT().FindFork(gomock.Any(), opns[0].Peer(), instate.Sub(1), opns[0].PrevAggHash).Return(fork0, nil)
 	ts.mForkFinder.EXPECT().FindFork(gomock.Any(), opns[2].Peer(), instate.Sub(1), opns[2].PrevAggHash).Return(fork2, nil)
-	ts.mForkFinder.EXPECT().FindFork(gomock.Any(), opns[5].Peer(), instate.Sub(1), opns[5].PrevAggHash).Return(types.LayerID(0), errUnknown)
+	ts.mForkFinder.EXPECT().FindFork(gomock.Any(), opns[5].Peer(), instate.Sub(1), opns[5].PrevAggHash).Return(types.LayerID(0), ErrInconsistentMeshHashes)
 	for lid := fork0.Add(1); lid.Before(current); lid = lid.Add(1) {
 		ts.mDataFetcher.EXPECT().PollLayerData(gomock.Any(), lid, opns[0].Peer())
 	}
@@ -380,11 +384,72 @@ func TestProcessLayers_MeshHashDiverged(t *testing.T) {
 	ts.mTortoise.EXPECT().TallyVotes(gomock.Any(), instate)
 	ts.mTortoise.EXPECT().Updates().Return(fixture.RLayers(fixture.ROpinion(instate.Sub(1), opns[2].PrevAggHash)))
 	require.NoError(t, ts.syncer.processLayers(context.Background()))
+
+	// p3 and p5 failed to back up their mesh hash
+	now := time.Now()
+	for i := 0; i < numPeers; i++ {
+		require.Equal(t, i == 3 || i == 5, ts.syncer.isolated.isolated(opns[i].Peer(), now), "peer %d", i)
+	}
+	require.Len(t, ts.syncer.isolated.filter(opns, now), numPeers-2)
+
+	// the tortoise adopted the mesh of p2, the fork is resolved
+	adopted, err := layers.GetAggregatedHash(ts.cdb, instate.Sub(1))
+	require.NoError(t, err)
+	require.Equal(t, opns[2].PrevAggHash, adopted)
+	require.Empty(t, ts.syncer.forks.pending)
+}
+
+func TestProcessLayers_MeshHashDivergedTimeout(t *testing.T) {
+	ts := newSyncerWithoutSyncTimer(t)
+	ts.syncer.cfg.PeerIsolation = time.Hour
+	ts.syncer.setATXSynced(context.Background())
+	current := types.GetEffectiveGenesis().Add(131)
+	ts.mTicker.advanceToLayer(current)
+	for lid := types.GetEffectiveGenesis().Add(1); lid.Before(current); lid = lid.Add(1) {
+		ts.msh.SetZeroBlockLayer(context.Background(), lid)
+		ts.mTortoise.EXPECT().OnHareOutput(lid, types.EmptyBlockID)
+		ts.mTortoise.EXPECT().TallyVotes(gomock.Any(), lid)
+		ts.mTortoise.EXPECT().Updates().Return(fixture.RLayers(fixture.ROpinion(lid, types.RandomHash())))
+		ts.mVm.EXPECT().Apply(gomock.Any(), nil, nil)
+		ts.mConState.EXPECT().UpdateCache(gomock.Any(), lid, types.EmptyBlockID, nil, nil)
+		ts.mVm.EXPECT().GetStateRoot()
+		require.NoError(t, ts.msh.ProcessLayerPerHareOutput(context.Background(), lid, types.EmptyBlockID, false))
+	}
+	instate := ts.syncer.mesh.LatestLayerInState()
+	ts.syncer.setLastSyncedLayer(instate)
+	opns := make([]*fetch.LayerOpinion, 0, 2)
+	for i := 0; i < 2; i++ {
+		opn := &fetch.LayerOpinion{PrevAggHash: types.RandomHash()}
+		opn.SetPeer(p2p.Peer(strconv.Itoa(i)))
+		opns = append(opns, opn)
+	}
+	// fetching atxs from p0 and finding fork with p1 time out
+	ts.mLyrPatrol.EXPECT().IsHareInCharge(instate).Return(false)
+	ts.mDataFetcher.EXPECT().PollLayerOpinions(gomock.Any(), instate).Return(opns, nil)
+	for _, opn := range opns {
+		ts.mForkFinder.EXPECT().NeedResync(instate.Sub(1), opn.PrevAggHash).Return(true)
+		ts.mDataFetcher.EXPECT().PeerEpochInfo(gomock.Any(), opn.Peer(), instate.GetEpoch()-1).
+			Return(&fetch.EpochData{AtxIDs: types.RandomActiveSet(3)}, nil)
+	}
+	ts.mDataFetcher.EXPECT().GetAtxs(gomock.Any(), gomock.Any()).
+		Return(fmt.Errorf("hash: %w", context.DeadlineExceeded))
+	ts.mDataFetcher.EXPECT().GetAtxs(gomock.Any(), gomock.Any())
+	ts.mForkFinder.EXPECT().FindFork(gomock.Any(), opns[1].Peer(), instate.Sub(1), opns[1].PrevAggHash).
+		Return(types.LayerID(0), fmt.Errorf("find fork hash req: %w", context.DeadlineExceeded))
+	ts.mForkFinder.EXPECT().Purge(true)
+	ts.mTortoise.EXPECT().TallyVotes(gomock.Any(), instate)
+	ts.mTortoise.EXPECT().Updates()
+	require.NoError(t, ts.syncer.processLayers(context.Background()))
+
+	now := time.Now()
+	for _, opn := range opns {
+		require.False(t, ts.syncer.isolated.isolated(opn.Peer(), now), opn.Peer())
+	}
 }
 
 func TestProcessLayers_NoHashResolutionForNewlySyncedNode(t *testing.T) {
 	ts := newSyncerWithoutSyncTimer(t)
-	ts.syncer.setATXSynced()
+	ts.syncer.setATXSynced(context.Background())
 	current := types.GetEffectiveGenesis().Add(131)
 	ts.mTicker.advanceToLayer(current)
 	for lid := types.GetEffectiveGenesis().Add(1); lid.Before(current); lid = lid.Add(1) {
@@ -16,6 +16,8 @@ import (
 	"github.com/spacemeshos/go-spacemesh/log"
 	"github.com/spacemeshos/go-spacemesh/mesh"
 	"github.com/spacemeshos/go-spacemesh/p2p"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/syncstate"
 	"github.com/spacemeshos/go-spacemesh/system"
 )
 
@@ -26,20 +28,64 @@ type Config struct {
 	HareDelayLayers  uint32
 	SyncCertDistance uint32
 	MaxStaleDuration time.Duration
-	Standalone       bool
+	// PeerIsolation is the duration for which opinions are ignored from a peer
+	// that failed to back up its divergent aggregated layer hash.
+	PeerIsolation time.Duration
+	Standalone    bool
+	// FetchWorkers is the number of layers fetched concurrently. layers are fetched one by one if it is not set.
+	FetchWorkers int
+	// MaxFetchAhead is the number of layers that can be fetched ahead of the processed layer.
+	// fetching is not limited if it is zero.
+	MaxFetchAhead uint32
+	// GossipBufferSize is the number of gossip messages buffered while the node is not synced.
+	// gossip is dropped if it is zero.
+	GossipBufferSize int
+	// GossipBufferLayers is the number of layers after which buffered gossip is dropped instead of replayed.
+	GossipBufferLayers uint32
+	// BehindInterval and CatchUpInterval are the intervals between sync runs when the node is
+	// too far behind (notSynced) and when it listens to gossip before it is synced (gossipSync).
+	// Interval is used if they are not set.
+	BehindInterval  time.Duration
+	CatchUpInterval time.Duration
+	// OutOfSyncThreshold is the number of layers the node falls behind to become not synced.
+	// GossipSyncLayers is the number of layers the node listens to gossip before it is synced.
+	// defaults are used if they are not set. the node becomes not synced only when it is
+	// OutOfSyncThreshold layers behind, but gets back to gossip sync only when it fetched data
+	// for all layers, so that it doesn't flap between the states.
+	OutOfSyncThreshold uint32
+	GossipSyncLayers   uint32
+	// Backfill enables fetching of the layers below the checkpoint the node was recovered from,
+	// one layer every BackfillInterval.
+	Backfill         bool
+	BackfillInterval time.Duration
+	// Light enables the light sync mode. the node syncs aggregated hashes and certificates of the layers
+	// instead of their content, and doesn't process the layers. the content is synced only for the layers
+	// that are needed to validate certificates.
+	Light bool
+	// LightQuorum is the min number of peers that have to report the same aggregated hash of a layer
+	// for the node to adopt it in the light mode. they also have to be the majority of the peers
+	// that reported the hash.
+	LightQuorum int
 }
 
 // DefaultConfig for the syncer.
 func DefaultConfig() Config {
 	return Config{
-		Interval:         10 * time.Second,
-		EpochEndFraction: 0.8,
-		HareDelayLayers:  10,
-		SyncCertDistance: 10,
-		MaxStaleDuration: time.Second,
+		Interval:           10 * time.Second,
+		EpochEndFraction:   0.8,
+		HareDelayLayers:    10,
+		SyncCertDistance:   10,
+		MaxStaleDuration:   time.Second,
+		PeerIsolation:      10 * time.Minute,
+		FetchWorkers:       1,
+		GossipBufferSize:   1000,
+		GossipBufferLayers: 2,
+		BackfillInterval:   time.Second,
+		LightQuorum:        3,
 	}
 }
 
+// defaults for OutOfSyncThreshold and GossipSyncLayers.
 const (
 	outOfSyncThreshold  uint32 = 3 // see notSynced
 	numGossipSyncLayers uint32 = 2 // see gossipSync
@@ -120,6 +166,9 @@ type Syncer struct {
 	dataFetcher   fetchLogic
 	patrol        layerPatrol
 	forkFinder    forkFinder
+	isolated      *isolatedPeers
+	forks         *forkTracker
+	gossip        *gossipBuffer
 	syncOnce      sync.Once
 	syncState     atomic.Value
 	atxSyncState  atomic.Value
@@ -159,16 +208,19 @@ func NewSyncer(
 		mesh:             mesh,
 		certHandler:      ch,
 		patrol:           patrol,
+		isolated:         newIsolatedPeers(),
+		forks:            newForkTracker(),
 		awaitATXSyncedCh: make(chan struct{}),
 	}
 	for _, opt := range opts {
 		opt(s)
 	}
 
+	s.gossip = newGossipBuffer(s.cfg.GossipBufferSize)
 	s.syncTimer = time.NewTicker(s.cfg.Interval)
 	s.validateTimer = time.NewTicker(s.cfg.Interval * 2)
 	if s.dataFetcher == nil {
-		s.dataFetcher = NewDataFetch(mesh, fetcher, cdb, cache, s.logger)
+		s.dataFetcher = NewDataFetch(cdb.Database, mesh, fetcher, cdb, cache, s.logger)
 	}
 	if s.forkFinder == nil {
 		s.forkFinder = NewForkFinder(s.logger, cdb.Database, fetcher, s.cfg.MaxStaleDuration)
@@ -179,9 +231,64 @@ func NewSyncer(
 	s.targetSyncedLayer.Store(types.LayerID(0))
 	s.lastLayerSynced.Store(s.mesh.ProcessedLayer())
 	s.lastEpochSynced.Store(types.GetEffectiveGenesis().GetEpoch() - 1)
+	s.restoreState()
 	return s
 }
 
+// restoreState resumes syncing from the progress persisted before the restart.
+// the current epoch is always synced again, as ATXs published while the node was down could be missed.
+func (s *Syncer) restoreState() {
+	st, err := syncstate.Get(s.cdb)
+	if err != nil {
+		s.logger.With().Warning("failed to load syncer state", log.Err(err))
+		return
+	}
+	current := s.ticker.CurrentLayer()
+	if current == 0 {
+		return
+	}
+	if st.HasLastSyncedLayer {
+		lid := st.LastSyncedLayer
+		if lid >= current {
+			lid = current - 1
+		}
+		if lid > s.getLastSyncedLayer() {
+			s.lastLayerSynced.Store(lid)
+		}
+	}
+	if st.HasLastAtxEpoch && current.GetEpoch() > 0 {
+		epoch := st.LastAtxEpoch
+		if epoch >= current.GetEpoch() {
+			epoch = current.GetEpoch() - 1
+		}
+		if epoch > s.lastAtxEpoch() {
+			s.lastEpochSynced.Store(epoch)
+		}
+	}
+	s.logger.With().Info("restored syncer state",
+		log.Stringer("last_synced", s.getLastSyncedLayer()),
+		log.Stringer("last_atx_epoch", s.lastAtxEpoch()),
+	)
+}
+
+// PersistedState returns the progress of the syncer persisted in the database and
+// the number of ATXs by epoch that the syncer didn't finish fetching.
+func (s *Syncer) PersistedState() (syncstate.State, map[types.EpochID]int, error) {
+	st, err := syncstate.Get(s.cdb)
+	if err != nil {
+		return syncstate.State{}, nil, err
+	}
+	outstanding, err := syncstate.OutstandingAtxs(s.cdb)
+	if err != nil {
+		return syncstate.State{}, nil, err
+	}
+	counts := make(map[types.EpochID]int, len(outstanding))
+	for epoch, ids := range outstanding {
+		counts[epoch] = len(ids)
+	}
+	return st, counts, nil
+}
+
 // Close stops the syncing process and the goroutines syncer spawns.
 func (s *Syncer) Close() {
 	s.syncTimer.Stop()
@@ -222,7 +329,7 @@ func (s *Syncer) IsBeaconSynced(epoch types.EpochID) bool {
 	return err == nil
 }
 
-// Start starts the main sync loop that tries to sync data for every SyncInterval.
+// Start starts the main sync loop that tries to sync data at the interval configured for the current sync state.
 func (s *Syncer) Start() {
 	s.syncOnce.Do(func() {
 		ctx, cancel := context.WithCancel(context.Background())
@@ -232,6 +339,7 @@ func (s *Syncer) Start() {
 			if s.ticker.CurrentLayer() <= types.GetEffectiveGenesis() {
 				s.setSyncState(ctx, synced)
 			}
+			interval := s.cfg.Interval
 			for {
 				select {
 				case <-ctx.Done():
@@ -244,6 +352,14 @@ func (s *Syncer) Start() {
 					} else {
 						runFail.Inc()
 					}
+					if next := s.syncInterval(); next != interval {
+						s.logger.WithContext(ctx).With().Debug("updated sync interval",
+							log.String("state", s.getSyncState().String()),
+							log.Duration("interval", next),
+						)
+						interval = next
+						s.syncTimer.Reset(interval)
+					}
 				}
 			}
 		})
@@ -266,13 +382,14 @@ func (s *Syncer) Start() {
 	})
 }
 
-func (s *Syncer) setATXSynced() {
+func (s *Syncer) setATXSynced(ctx context.Context) {
 	s.atxSyncState.Store(synced)
 	select {
 	case <-s.awaitATXSyncedCh:
 	default:
 		close(s.awaitATXSyncedCh)
 		atxSynced.Set(1)
+		s.replayGossip(ctx, true)
 	}
 }
 
@@ -287,14 +404,18 @@ func (s *Syncer) getSyncState() syncState {
 func (s *Syncer) setSyncState(ctx context.Context, newState syncState) {
 	oldState := s.syncState.Swap(newState).(syncState)
 	if oldState != newState {
+		status := s.mesh.MeshStatus()
 		s.logger.WithContext(ctx).With().Info("sync state change",
 			log.String("from state", oldState.String()),
 			log.String("to state", newState.String()),
 			log.Stringer("current", s.ticker.CurrentLayer()),
 			log.Stringer("last synced", s.getLastSyncedLayer()),
-			log.Stringer("latest", s.mesh.LatestLayer()),
-			log.Stringer("processed", s.mesh.ProcessedLayer()))
+			log.Stringer("latest", status.Latest),
+			log.Stringer("processed", status.Processed))
 		events.ReportNodeStatusUpdate()
+		if oldState == notSynced {
+			s.replayGossip(ctx, false)
+		}
 	}
 	switch newState {
 	case notSynced:
@@ -312,6 +433,35 @@ func (s *Syncer) setSyncState(ctx context.Context, newState syncState) {
 	}
 }
 
+// syncInterval returns the interval between sync runs in the current sync state.
+func (s *Syncer) syncInterval() time.Duration {
+	switch s.getSyncState() {
+	case notSynced:
+		if s.cfg.BehindInterval > 0 {
+			return s.cfg.BehindInterval
+		}
+	case gossipSync:
+		if s.cfg.CatchUpInterval > 0 {
+			return s.cfg.CatchUpInterval
+		}
+	}
+	return s.cfg.Interval
+}
+
+func (s *Syncer) outOfSyncThreshold() uint32 {
+	if s.cfg.OutOfSyncThreshold > 0 {
+		return s.cfg.OutOfSyncThreshold
+	}
+	return outOfSyncThreshold
+}
+
+func (s *Syncer) gossipSyncLayers() uint32 {
+	if s.cfg.GossipSyncLayers > 0 {
+		return s.cfg.GossipSyncLayers
+	}
+	return numGossipSyncLayers
+}
+
 // setSyncerBusy returns false if the syncer is already running a sync process.
 // otherwise it sets syncer to be busy and returns true.
 func (s *Syncer) setSyncerBusy() bool {
@@ -325,12 +475,13 @@ func (s *Syncer) setSyncerIdle() {
 // targetSyncedLayer is used to signal at which layer we can set this node to synced state.
 func (s *Syncer) setTargetSyncedLayer(ctx context.Context, layerID types.LayerID) {
 	oldSyncLayer := s.targetSyncedLayer.Swap(layerID).(types.LayerID)
+	status := s.mesh.MeshStatus()
 	s.logger.WithContext(ctx).With().Debug("target synced layer changed",
 		log.Uint32("from_layer", oldSyncLayer.Uint32()),
 		log.Uint32("to_layer", layerID.Uint32()),
 		log.Stringer("current", s.ticker.CurrentLayer()),
-		log.Stringer("latest", s.mesh.LatestLayer()),
-		log.Stringer("processed", s.mesh.ProcessedLayer()))
+		log.Stringer("latest", status.Latest),
+		log.Stringer("processed", status.Processed))
 }
 
 func (s *Syncer) getTargetSyncedLayer() types.LayerID {
@@ -340,6 +491,9 @@ func (s *Syncer) getTargetSyncedLayer() types.LayerID {
 func (s *Syncer) setLastSyncedLayer(lid types.LayerID) {
 	s.lastLayerSynced.Store(lid)
 	syncedLayer.Set(float64(lid))
+	if err := syncstate.SetLastSyncedLayer(s.cdb, lid); err != nil {
+		s.logger.With().Warning("failed to persist last synced layer", lid, log.Err(err))
+	}
 }
 
 func (s *Syncer) getLastSyncedLayer() types.LayerID {
@@ -348,6 +502,9 @@ func (s *Syncer) getLastSyncedLayer() types.LayerID {
 
 func (s *Syncer) setLastAtxEpoch(epoch types.EpochID) {
 	s.lastEpochSynced.Store(epoch)
+	if err := syncstate.SetLastAtxEpoch(s.cdb, epoch); err != nil {
+		s.logger.With().Warning("failed to persist last atx epoch", epoch, log.Err(err))
+	}
 }
 
 func (s *Syncer) lastAtxEpoch() types.EpochID {
@@ -378,13 +535,14 @@ func (s *Syncer) synchronize(ctx context.Context) bool {
 	}
 
 	// no need to worry about race condition for s.run. only one instance of synchronize can run at a time
+	status := s.mesh.MeshStatus()
 	s.logger.WithContext(ctx).With().Debug("starting sync run",
 		log.Stringer("sync_state", s.getSyncState()),
 		log.Stringer("last_synced", s.getLastSyncedLayer()),
 		log.Stringer("current", s.ticker.CurrentLayer()),
-		log.Stringer("latest", s.mesh.LatestLayer()),
-		log.Stringer("in_state", s.mesh.LatestLayerInState()),
-		log.Stringer("processed", s.mesh.ProcessedLayer()),
+		log.Stringer("latest", status.Latest),
+		log.Stringer("in_state", status.Applied),
+		log.Stringer("processed", status.Processed),
 	)
 	// TODO
 	// https://github.com/spacemeshos/go-spacemesh/issues/3970
@@ -392,7 +550,7 @@ func (s *Syncer) synchronize(ctx context.Context) bool {
 	syncFunc := func() bool {
 		if s.cfg.Standalone {
 			s.setLastSyncedLayer(s.ticker.CurrentLayer().Sub(1))
-			s.setATXSynced()
+			s.setATXSynced(ctx)
 			return true
 		}
 		if len(s.dataFetcher.GetPeers()) == 0 {
@@ -407,11 +565,8 @@ func (s *Syncer) synchronize(ctx context.Context) bool {
 			return true
 		}
 		// always sync to currentLayer-1 to reduce race with gossip and hare/tortoise
-		for layerID := s.getLastSyncedLayer().Add(1); layerID.Before(s.ticker.CurrentLayer()); layerID = layerID.Add(1) {
-			if err := s.syncLayer(ctx, layerID); err != nil {
-				return false
-			}
-			s.setLastSyncedLayer(layerID)
+		if err := s.syncLayers(ctx, s.getLastSyncedLayer().Add(1), s.ticker.CurrentLayer()); err != nil {
+			return false
 		}
 		s.logger.WithContext(ctx).With().Debug("data is synced",
 			log.Stringer("current", s.ticker.CurrentLayer()),
@@ -422,14 +577,15 @@ func (s *Syncer) synchronize(ctx context.Context) bool {
 
 	success := syncFunc()
 	s.setStateAfterSync(ctx, success)
+	status = s.mesh.MeshStatus()
 	s.logger.WithContext(ctx).With().Debug("finished sync run",
 		log.Bool("success", success),
 		log.Stringer("sync_state", s.getSyncState()),
 		log.Stringer("last_synced", s.getLastSyncedLayer()),
 		log.Stringer("current", s.ticker.CurrentLayer()),
-		log.Stringer("latest", s.mesh.LatestLayer()),
-		log.Stringer("in_state", s.mesh.LatestLayerInState()),
-		log.Stringer("processed", s.mesh.ProcessedLayer()),
+		log.Stringer("latest", status.Latest),
+		log.Stringer("in_state", status.Applied),
+		log.Stringer("processed", status.Processed),
 	)
 	return success
 }
@@ -437,6 +593,7 @@ func (s *Syncer) synchronize(ctx context.Context) bool {
 func (s *Syncer) syncAtx(ctx context.Context) error {
 	if !s.ListenToATXGossip() {
 		s.logger.WithContext(ctx).With().Info("syncing atx from genesis", s.ticker.CurrentLayer())
+		s.fetchOutstandingATXs(ctx)
 		for epoch := s.lastAtxEpoch() + 1; epoch <= s.ticker.CurrentLayer().GetEpoch(); epoch++ {
 			if err := s.fetchATXsForEpoch(ctx, epoch); err != nil {
 				return err
@@ -450,7 +607,7 @@ func (s *Syncer) syncAtx(ctx context.Context) error {
 			return err
 		}
 		s.logger.WithContext(ctx).With().Info("malicious IDs synced")
-		s.setATXSynced()
+		s.setATXSynced(ctx)
 		return nil
 	}
 
@@ -476,12 +633,12 @@ func (s *Syncer) syncAtx(ctx context.Context) error {
 	return nil
 }
 
-func isTooFarBehind(ctx context.Context, logger log.Log, current, lastSynced types.LayerID) bool {
-	if current.After(lastSynced) && current.Difference(lastSynced) >= outOfSyncThreshold {
+func isTooFarBehind(ctx context.Context, logger log.Log, current, lastSynced types.LayerID, threshold uint32) bool {
+	if current.After(lastSynced) && current.Difference(lastSynced) >= threshold {
 		logger.WithContext(ctx).With().Info("node is too far behind",
 			log.Stringer("current", current),
 			log.Stringer("last synced", lastSynced),
-			log.Uint32("behind threshold", outOfSyncThreshold))
+			log.Uint32("behind threshold", threshold))
 		return true
 	}
 	return false
@@ -492,11 +649,11 @@ func (s *Syncer) setStateBeforeSync(ctx context.Context) {
 	if s.ticker.CurrentLayer() <= types.GetEffectiveGenesis() {
 		s.setSyncState(ctx, synced)
 		if current.GetEpoch() == 0 {
-			s.setATXSynced()
+			s.setATXSynced(ctx)
 		}
 		return
 	}
-	if isTooFarBehind(ctx, s.logger, current, s.getLastSyncedLayer()) {
+	if isTooFarBehind(ctx, s.logger, current, s.getLastSyncedLayer(), s.outOfSyncThreshold()) {
 		s.setSyncState(ctx, notSynced)
 	}
 }
@@ -516,13 +673,13 @@ func (s *Syncer) setStateAfterSync(ctx context.Context, success bool) {
 	// network outage.
 	switch currSyncState {
 	case synced:
-		if !success && isTooFarBehind(ctx, s.logger, current, s.getLastSyncedLayer()) {
+		if !success && isTooFarBehind(ctx, s.logger, current, s.getLastSyncedLayer(), s.outOfSyncThreshold()) {
 			s.setSyncState(ctx, notSynced)
 		}
 	case gossipSync:
 		if !success || !s.dataSynced() {
 			// push out the target synced layer
-			s.setTargetSyncedLayer(ctx, current.Add(numGossipSyncLayers))
+			s.setTargetSyncedLayer(ctx, current.Add(s.gossipSyncLayers()))
 			break
 		}
 		// if we have gossip-synced to the target synced layer, we are ready to participate in consensus
@@ -533,7 +690,7 @@ func (s *Syncer) setStateAfterSync(ctx context.Context, success bool) {
 		if success && s.dataSynced() {
 			// wait till s.ticker.GetCurrentLayer() + numGossipSyncLayers to participate in consensus
 			s.setSyncState(ctx, gossipSync)
-			s.setTargetSyncedLayer(ctx, current.Add(numGossipSyncLayers))
+			s.setTargetSyncedLayer(ctx, current.Add(s.gossipSyncLayers()))
 		}
 	}
 }
@@ -546,6 +703,9 @@ func (s *Syncer) syncMalfeasance(ctx context.Context) error {
 }
 
 func (s *Syncer) syncLayer(ctx context.Context, layerID types.LayerID, peers ...p2p.Peer) error {
+	if s.cfg.Light {
+		return s.syncLayerLight(ctx, layerID)
+	}
 	if err := s.dataFetcher.PollLayerData(ctx, layerID, peers...); err != nil {
 		return fmt.Errorf("PollLayerData: %w", err)
 	}
@@ -553,6 +713,31 @@ func (s *Syncer) syncLayer(ctx context.Context, layerID types.LayerID, peers ...
 	return nil
 }
 
+// fetchOutstandingATXs fetches ATXs that were requested before the node restarted and weren't fetched.
+// failures are not fatal, as the ATXs are requested again when their epoch is synced.
+func (s *Syncer) fetchOutstandingATXs(ctx context.Context) {
+	outstanding, err := syncstate.OutstandingAtxs(s.cdb)
+	if err != nil {
+		s.logger.WithContext(ctx).With().Warning("failed to load outstanding atxs", log.Err(err))
+		return
+	}
+	for epoch, ids := range outstanding {
+		if err := s.dataFetcher.GetAtxs(ctx, ids); err != nil {
+			s.logger.WithContext(ctx).With().Warning("failed to fetch outstanding atxs",
+				epoch,
+				log.Int("num_atxs", len(ids)),
+				log.Err(err),
+			)
+			continue
+		}
+		if err := s.cdb.WithTx(ctx, func(tx *sql.Tx) error {
+			return syncstate.DeleteOutstandingAtxs(tx, ids)
+		}); err != nil {
+			s.logger.WithContext(ctx).With().Warning("failed to delete outstanding atxs", epoch, log.Err(err))
+		}
+	}
+}
+
 // fetching ATXs published the specified epoch.
 func (s *Syncer) fetchATXsForEpoch(ctx context.Context, epoch types.EpochID) error {
 	if err := s.dataFetcher.GetEpochATXs(ctx, epoch); err != nil {
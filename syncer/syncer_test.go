@@ -20,6 +20,7 @@ import (
 	mmocks "github.com/spacemeshos/go-spacemesh/mesh/mocks"
 	"github.com/spacemeshos/go-spacemesh/p2p"
 	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/syncstate"
 	"github.com/spacemeshos/go-spacemesh/syncer/mocks"
 	smocks "github.com/spacemeshos/go-spacemesh/system/mocks"
 )
@@ -87,6 +88,7 @@ func newTestSyncer(t *testing.T, interval time.Duration) *testSyncer {
 	}
 	ts.cdb = datastore.NewCachedDB(sql.InMemory(), lg)
 	var err error
+	ts.mTortoise.EXPECT().LatestComplete().AnyTimes()
 	exec := mesh.NewExecutor(ts.cdb, ts.mVm, ts.mConState, lg)
 	ts.msh, err = mesh.NewMesh(ts.cdb, ts.mTicker, ts.mTortoise, exec, ts.mConState, lg)
 	require.NoError(t, err)
@@ -242,6 +244,66 @@ func TestSynchronize_FetchLayerDataFailed(t *testing.T) {
 	require.False(t, ts.syncer.IsSynced(context.Background()))
 }
 
+func TestSynchronize_FetchWorkers(t *testing.T) {
+	ts := newSyncerWithoutSyncTimer(t)
+	ts.syncer.cfg.FetchWorkers = 4
+	gLayer := types.GetEffectiveGenesis()
+	current := gLayer.Add(20)
+	ts.mTicker.advanceToLayer(current)
+	ts.mDataFetcher.EXPECT().GetEpochATXs(gomock.Any(), gomock.Any()).AnyTimes()
+	ts.mDataFetcher.EXPECT().PollMaliciousProofs(gomock.Any())
+	var fetched sync.Map
+	ts.mDataFetcher.EXPECT().PollLayerData(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, lid types.LayerID, _ ...p2p.Peer) error {
+			_, loaded := fetched.LoadOrStore(lid, struct{}{})
+			require.False(t, loaded)
+			return nil
+		}).Times(int(current.Sub(gLayer.Add(1).Uint32()).Uint32()))
+
+	require.True(t, ts.syncer.synchronize(context.Background()))
+	require.Equal(t, current.Sub(1), ts.syncer.getLastSyncedLayer())
+	require.True(t, ts.syncer.dataSynced())
+}
+
+func TestSynchronize_FetchWorkersFailed(t *testing.T) {
+	ts := newSyncerWithoutSyncTimer(t)
+	ts.syncer.cfg.FetchWorkers = 4
+	gLayer := types.GetEffectiveGenesis()
+	current := gLayer.Add(20)
+	ts.mTicker.advanceToLayer(current)
+	ts.mDataFetcher.EXPECT().GetEpochATXs(gomock.Any(), gomock.Any()).AnyTimes()
+	ts.mDataFetcher.EXPECT().PollMaliciousProofs(gomock.Any())
+	failed := gLayer.Add(5)
+	ts.mDataFetcher.EXPECT().PollLayerData(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, lid types.LayerID, _ ...p2p.Peer) error {
+			if lid == failed {
+				return errors.New("meh")
+			}
+			return nil
+		}).AnyTimes()
+
+	require.False(t, ts.syncer.synchronize(context.Background()))
+	require.Equal(t, failed.Sub(1), ts.syncer.getLastSyncedLayer())
+	require.False(t, ts.syncer.dataSynced())
+}
+
+func TestSynchronize_MaxFetchAhead(t *testing.T) {
+	ts := newSyncerWithoutSyncTimer(t)
+	ts.syncer.cfg.FetchWorkers = 2
+	ts.syncer.cfg.MaxFetchAhead = 3
+	gLayer := types.GetEffectiveGenesis()
+	current := gLayer.Add(20)
+	ts.mTicker.advanceToLayer(current)
+	ts.mDataFetcher.EXPECT().GetEpochATXs(gomock.Any(), gomock.Any()).AnyTimes()
+	ts.mDataFetcher.EXPECT().PollMaliciousProofs(gomock.Any())
+	for lid := gLayer.Add(1); !lid.After(gLayer.Add(3)); lid = lid.Add(1) {
+		ts.mDataFetcher.EXPECT().PollLayerData(gomock.Any(), lid)
+	}
+
+	require.False(t, ts.syncer.synchronize(context.Background()))
+	require.Equal(t, gLayer.Add(3), ts.syncer.getLastSyncedLayer())
+}
+
 func TestSynchronize_FetchMalfeasanceFailed(t *testing.T) {
 	ts := newSyncerWithoutSyncTimer(t)
 	gLayer := types.GetEffectiveGenesis()
@@ -396,6 +458,55 @@ func TestSyncAtxs(t *testing.T) {
 	}
 }
 
+func TestSyncer_RestoreState(t *testing.T) {
+	ts := newSyncerWithoutSyncTimer(t)
+	current := types.LayerID(10)
+	ts.mTicker.advanceToLayer(current)
+	outstanding := []types.ATXID{types.RandomATXID(), types.RandomATXID()}
+	require.NoError(t, syncstate.SetLastSyncedLayer(ts.cdb, 8))
+	require.NoError(t, syncstate.SetLastAtxEpoch(ts.cdb, 2))
+	require.NoError(t, ts.cdb.WithTx(context.Background(), func(tx *sql.Tx) error {
+		return syncstate.AddOutstandingAtxs(tx, 2, outstanding)
+	}))
+
+	restarted := NewSyncer(ts.cdb, ts.mTicker, ts.mBeacon, ts.msh, nil, nil, ts.mLyrPatrol, ts.mCertHdr,
+		WithConfig(ts.syncer.cfg),
+		WithLogger(logtest.New(t)),
+		withDataFetcher(ts.mDataFetcher),
+		withForkFinder(ts.mForkFinder))
+	require.Equal(t, types.LayerID(8), restarted.getLastSyncedLayer())
+	require.Equal(t, types.EpochID(2), restarted.lastAtxEpoch())
+
+	ts.mDataFetcher.EXPECT().GetAtxs(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, ids []types.ATXID) error {
+			require.ElementsMatch(t, outstanding, ids)
+			return nil
+		})
+	ts.mDataFetcher.EXPECT().GetEpochATXs(gomock.Any(), current.GetEpoch())
+	ts.mDataFetcher.EXPECT().PollMaliciousProofs(gomock.Any())
+	require.NoError(t, restarted.syncAtx(context.Background()))
+
+	st, counts, err := restarted.PersistedState()
+	require.NoError(t, err)
+	require.Equal(t, current.GetEpoch(), st.LastAtxEpoch)
+	require.Empty(t, counts)
+}
+
+func TestSyncer_RestoreStateCurrentEpoch(t *testing.T) {
+	ts := newSyncerWithoutSyncTimer(t)
+	current := types.LayerID(10)
+	ts.mTicker.advanceToLayer(current)
+	require.NoError(t, syncstate.SetLastSyncedLayer(ts.cdb, current))
+	require.NoError(t, syncstate.SetLastAtxEpoch(ts.cdb, current.GetEpoch()))
+
+	restarted := NewSyncer(ts.cdb, ts.mTicker, ts.mBeacon, ts.msh, nil, nil, ts.mLyrPatrol, ts.mCertHdr,
+		WithLogger(logtest.New(t)),
+		withDataFetcher(ts.mDataFetcher),
+		withForkFinder(ts.mForkFinder))
+	require.Equal(t, current.Sub(1), restarted.getLastSyncedLayer())
+	require.Equal(t, current.GetEpoch()-1, restarted.lastAtxEpoch())
+}
+
 func TestSynchronize_StaySyncedUponFailure(t *testing.T) {
 	ts := newSyncerWithoutSyncTimer(t)
 	lyr := startWithSyncedState(t, ts)
@@ -616,7 +727,7 @@ func TestSyncer_setATXSyncedTwice_NoError(t *testing.T) {
 	case <-time.After(100 * time.Millisecond):
 	}
 
-	ts.syncer.setATXSynced()
+	ts.syncer.setATXSynced(context.Background())
 
 	select {
 	case <-atxSync:
@@ -624,7 +735,7 @@ func TestSyncer_setATXSyncedTwice_NoError(t *testing.T) {
 		require.Fail(t, "should have reached synced state")
 	}
 
-	require.NotPanics(t, func() { ts.syncer.setATXSynced() })
+	require.NotPanics(t, func() { ts.syncer.setATXSynced(context.Background()) })
 }
 
 func TestSyncer_IsBeaconSynced(t *testing.T) {
@@ -666,3 +777,56 @@ func TestSyncBeforeGenesis(t *testing.T) {
 	}
 	require.True(t, ts.syncer.IsSynced(context.Background()))
 }
+
+func TestSyncer_StateIntervals(t *testing.T) {
+	ts := newSyncerWithoutSyncTimer(t)
+	ts.syncer.cfg.Interval = time.Minute
+	require.Equal(t, time.Minute, ts.syncer.syncInterval())
+	ts.syncer.setSyncState(context.Background(), gossipSync)
+	require.Equal(t, time.Minute, ts.syncer.syncInterval())
+
+	ts.syncer.cfg.BehindInterval = time.Second
+	ts.syncer.cfg.CatchUpInterval = 5 * time.Second
+	for _, tc := range []struct {
+		state    syncState
+		interval time.Duration
+	}{
+		{notSynced, time.Second},
+		{gossipSync, 5 * time.Second},
+		{synced, time.Minute},
+	} {
+		ts.syncer.setSyncState(context.Background(), tc.state)
+		require.Equal(t, tc.interval, ts.syncer.syncInterval(), tc.state)
+	}
+}
+
+func TestSyncer_StateThresholds(t *testing.T) {
+	ts := newSyncerWithoutSyncTimer(t)
+	require.Equal(t, outOfSyncThreshold, ts.syncer.outOfSyncThreshold())
+	require.Equal(t, numGossipSyncLayers, ts.syncer.gossipSyncLayers())
+
+	ts.syncer.cfg.OutOfSyncThreshold = 10
+	ts.syncer.cfg.GossipSyncLayers = 4
+	lyr := startWithSyncedState(t, ts)
+
+	// falling behind less than the threshold doesn't change the state
+	current := lyr.Add(outOfSyncThreshold)
+	ts.mTicker.advanceToLayer(current)
+	ts.mDataFetcher.EXPECT().GetEpochATXs(gomock.Any(), gomock.Any()).AnyTimes()
+	ts.mDataFetcher.EXPECT().PollLayerData(gomock.Any(), lyr).Return(errors.New("doh"))
+	require.False(t, ts.syncer.synchronize(context.Background()))
+	require.True(t, ts.syncer.IsSynced(context.Background()))
+
+	current = lyr.Add(10)
+	ts.mTicker.advanceToLayer(current)
+	ts.mDataFetcher.EXPECT().PollLayerData(gomock.Any(), lyr).Return(errors.New("doh"))
+	require.False(t, ts.syncer.synchronize(context.Background()))
+	require.False(t, ts.syncer.ListenToGossip())
+
+	for lid := lyr; lid < current; lid++ {
+		ts.mDataFetcher.EXPECT().PollLayerData(gomock.Any(), lid)
+	}
+	require.True(t, ts.syncer.synchronize(context.Background()))
+	require.True(t, ts.syncer.ListenToGossip())
+	require.Equal(t, current.Add(4), ts.syncer.getTargetSyncedLayer())
+}
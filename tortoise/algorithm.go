@@ -28,6 +28,16 @@ type Config struct {
 	// recorded in the first ballot, if that weight is less than minimal
 	// for purposes of eligibility computation.
 	MinimalActiveSetWeight uint64 `mapstructure:"tortoise-activeset-weight"`
+	// RerunInterval is the interval between background reruns of the tortoise from the database.
+	// Rerun is opt-in, it is disabled if interval is zero.
+	RerunInterval time.Duration `mapstructure:"tortoise-rerun-interval"`
+	// Updates change Hdist, Zdist and WindowSize starting from the specified layers.
+	// Updates must be ordered by layer.
+	Updates []ParamsUpdate `mapstructure:"tortoise-params-updates"`
+	// MemoryBudget is an approximate limit in bytes for ballots, blocks and layers kept in memory.
+	// If the state exceeds the budget layers are evicted before the window, but at least
+	// Hdist layers before the last verified layer are kept. Zero means no limit.
+	MemoryBudget uint64 `mapstructure:"tortoise-memory-budget"`
 
 	LayerSize uint32
 }
@@ -53,6 +63,8 @@ type Tortoise struct {
 	mu     sync.Mutex
 	trtl   *turtle
 	tracer *tracer
+
+	rerun rerunTracker
 }
 
 // Opt for configuring tortoise.
@@ -89,12 +101,16 @@ func New(opts ...Opt) (*Tortoise, error) {
 	for _, opt := range opts {
 		opt(t)
 	}
-	if t.cfg.Hdist < t.cfg.Zdist {
-		t.logger.Panic("hdist must be >= zdist",
-			zap.Uint32("hdist", t.cfg.Hdist),
-			zap.Uint32("zdist", t.cfg.Zdist),
-		)
+	if err := t.cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("tortoise config: %w", err)
 	}
+	t.logger.Info("tortoise parameters",
+		zap.Uint32("hdist", t.cfg.Hdist),
+		zap.Uint32("zdist", t.cfg.Zdist),
+		zap.Uint32("window", t.cfg.WindowSize),
+		zap.Int("updates", len(t.cfg.Updates)),
+	)
+	reportParams(t.cfg)
 	t.trtl = newTurtle(t.logger, t.cfg)
 	if t.tracer != nil {
 		t.tracer.On(&ConfigTrace{
@@ -104,6 +120,7 @@ func New(opts ...Opt) (*Tortoise, error) {
 			MaxExceptions:            uint32(t.cfg.MaxExceptions),
 			BadBeaconVoteDelayLayers: t.cfg.BadBeaconVoteDelayLayers,
 			LayerSize:                t.cfg.LayerSize,
+			Updates:                  t.cfg.Updates,
 			EpochSize:                types.GetLayersPerEpoch(),
 			EffectiveGenesis:         types.GetEffectiveGenesis().Uint32(),
 		})
@@ -0,0 +1,137 @@
+package tortoise
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+)
+
+// ErrUnknownBlock is returned if block is not tracked by tortoise, either
+// because it was never received or because it was evicted.
+var ErrUnknownBlock = errors.New("tortoise: unknown block")
+
+// BallotVote is a vote of a single ballot for the explained block.
+type BallotVote struct {
+	ID     types.BallotID `json:"id"`
+	Layer  types.LayerID  `json:"layer"`
+	Weight float64        `json:"weight"`
+	// Vote is one of support, against or abstain.
+	Vote string `json:"vote"`
+	// Ignored is set with a reason if the vote is not counted.
+	Ignored string `json:"ignored,omitempty"`
+}
+
+// VoteExplanation describes how tortoise counts votes for a block.
+type VoteExplanation struct {
+	Block    types.BlockID `json:"block"`
+	Layer    types.LayerID `json:"layer"`
+	Height   uint64        `json:"height"`
+	Data     bool          `json:"data"`
+	Hare     string        `json:"hare"`
+	Validity string        `json:"validity"`
+	Mode     string        `json:"mode"`
+	Verified bool          `json:"verified"`
+
+	// Margin is the weight counted by full tortoise.
+	Margin float64 `json:"margin"`
+	// Support, Against and Abstain are computed from all ballots known to tortoise,
+	// and may include ballots that are not yet counted by full tortoise.
+	Support float64 `json:"support"`
+	Against float64 `json:"against"`
+	Abstain float64 `json:"abstain"`
+
+	LocalThreshold  float64 `json:"local_threshold"`
+	GlobalThreshold float64 `json:"global_threshold"`
+
+	Ballots []BallotVote `json:"ballots"`
+}
+
+// ExplainVote reports how tortoise currently counts votes for the block.
+func (t *Tortoise) ExplainVote(id types.BlockID) (*VoteExplanation, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	block := t.trtl.findBlock(id)
+	if block == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownBlock, id)
+	}
+	return t.trtl.explain(block), nil
+}
+
+func (t *turtle) findBlock(id types.BlockID) *blockInfo {
+	for lid, layer := range t.layers {
+		if !lid.After(t.evicted) {
+			continue
+		}
+		for _, block := range layer.blocks {
+			if block.id == id {
+				return block
+			}
+		}
+	}
+	return nil
+}
+
+func (t *turtle) explain(block *blockInfo) *VoteExplanation {
+	mode := Mode(Verifying)
+	if t.isFull {
+		mode = Full
+	}
+	rst := &VoteExplanation{
+		Block:           block.id,
+		Layer:           block.layer,
+		Height:          block.height,
+		Data:            block.data,
+		Hare:            block.hare.String(),
+		Validity:        block.validity.String(),
+		Mode:            mode.String(),
+		Verified:        !block.layer.After(t.verified),
+		Margin:          block.margin.Float(),
+		LocalThreshold:  t.localThreshold.Float(),
+		GlobalThreshold: t.globalThreshold(t.Config, block.layer).Float(),
+	}
+	for lid := block.layer.Add(1); !lid.After(t.processed); lid = lid.Add(1) {
+		for _, ballot := range t.ballots[lid] {
+			vote := BallotVote{
+				ID:     ballot.id,
+				Layer:  ballot.layer,
+				Weight: ballot.weight.Float(),
+			}
+			var lvote *layerVote
+			for current := ballot.votes.tail; current != nil && !current.lid.Before(block.layer); current = current.prev {
+				if current.lid == block.layer {
+					lvote = current
+					break
+				}
+			}
+			if lvote == nil {
+				continue
+			}
+			decision := abstain
+			if lvote.vote != abstain {
+				decision = lvote.getVote(block)
+			}
+			vote.Vote = decision.String()
+			switch {
+			case ballot.malicious:
+				vote.Ignored = "malicious"
+			case ballot.reference == nil || block.height > ballot.reference.height:
+				vote.Ignored = "height"
+			case ballot.conditions.badBeacon && ballot.layer.Add(t.BadBeaconVoteDelayLayers).After(t.last):
+				vote.Ignored = "bad beacon"
+			}
+			if len(vote.Ignored) == 0 {
+				switch decision {
+				case support:
+					rst.Support += vote.Weight
+				case against:
+					rst.Against += vote.Weight
+				default:
+					rst.Abstain += vote.Weight
+				}
+			}
+			rst.Ballots = append(rst.Ballots, vote)
+		}
+	}
+	return rst
+}
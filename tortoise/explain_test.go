@@ -0,0 +1,59 @@
+package tortoise
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/sql/blocks"
+	"github.com/spacemeshos/go-spacemesh/tortoise/sim"
+)
+
+func TestExplainVote(t *testing.T) {
+	ctx := context.Background()
+	const size = 10
+	s := sim.New(sim.WithLayerSize(size))
+	s.Setup()
+
+	cfg := defaultTestConfig()
+	cfg.LayerSize = size
+
+	tortoise := tortoiseFromSimState(t, s.GetState(0), WithLogger(logtest.New(t)), WithConfig(cfg))
+	var last types.LayerID
+	for _, last = range sim.GenLayers(s, sim.WithSequence(3)) {
+		tortoise.TallyVotes(ctx, last)
+	}
+	target := last.Sub(2)
+	blks, err := blocks.Layer(s.GetState(0).DB, target)
+	require.NoError(t, err)
+	require.NotEmpty(t, blks)
+
+	var valid int
+	for _, block := range blks {
+		explained, err := tortoise.ExplainVote(block.ID())
+		require.NoError(t, err)
+		require.Equal(t, block.ID(), explained.Block)
+		require.Equal(t, target, explained.Layer)
+		require.True(t, explained.Verified)
+		require.Len(t, explained.Ballots, 2*size)
+		if explained.Validity == support.String() {
+			valid++
+			require.Zero(t, explained.Against)
+			require.Greater(t, explained.Support, explained.GlobalThreshold)
+		} else {
+			require.Zero(t, explained.Support)
+			require.Greater(t, explained.Against, explained.GlobalThreshold)
+		}
+		for _, ballot := range explained.Ballots {
+			require.Equal(t, explained.Validity, ballot.Vote)
+			require.Empty(t, ballot.Ignored)
+		}
+	}
+	require.Positive(t, valid)
+
+	_, err = tortoise.ExplainVote(types.RandomBlockID())
+	require.ErrorIs(t, err, ErrUnknownBlock)
+}
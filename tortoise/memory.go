@@ -0,0 +1,65 @@
+package tortoise
+
+import (
+	"unsafe"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+)
+
+var (
+	// ballotCost is an approximate size of the ballot in the state, including
+	// the vote for the previous layer that is allocated for every ballot.
+	ballotCost = uint64(unsafe.Sizeof(ballotInfo{}) + unsafe.Sizeof(layerVote{}) + unsafe.Sizeof(types.BallotID{}))
+	// blockCost is an approximate size of the block in the state.
+	blockCost = uint64(unsafe.Sizeof(blockInfo{}) + unsafe.Sizeof(&blockInfo{}))
+	// layerCost is an approximate size of the layer in the state.
+	layerCost = uint64(unsafe.Sizeof(layerInfo{}))
+)
+
+// memoryUsage tracks approximate memory used by ballots, blocks and layers in the state.
+type memoryUsage struct {
+	ballots, blocks, layers uint64
+}
+
+func (m *memoryUsage) total() uint64 {
+	return m.ballots*ballotCost + m.blocks*blockCost + m.layers*layerCost
+}
+
+func (s *state) layerMemory(lid types.LayerID) uint64 {
+	size := uint64(len(s.ballots[lid])) * ballotCost
+	if layer, exist := s.layers[lid]; exist {
+		size += uint64(len(layer.blocks))*blockCost + layerCost
+	}
+	return size
+}
+
+// boundedWindowStart moves the start of the window forward, until memory used by the state fits
+// into the MemoryBudget. At least Hdist layers before the last verified layer are kept in memory.
+//
+// Layers that are evicted earlier than the configured window are still stored in the database,
+// but votes that refer to them are rejected as outside the window, like with the smaller window size.
+func (t *turtle) boundedWindowStart(start types.LayerID) types.LayerID {
+	if t.MemoryBudget == 0 {
+		return start
+	}
+	usage := t.memory.total()
+	if usage <= t.MemoryBudget {
+		return start
+	}
+	limit := t.verified.Sub(t.Hdist)
+	if t.pending != 0 {
+		limit = types.MinLayer(limit, t.pending)
+	}
+	lid := maxLayer(start, t.evicted.Add(1))
+	for ; lid < limit && usage > t.MemoryBudget; lid = lid.Add(1) {
+		size := t.layerMemory(lid)
+		if size > usage {
+			size = usage
+		}
+		usage -= size
+	}
+	if lid > start {
+		overBudgetEvictions.Inc()
+	}
+	return maxLayer(start, lid)
+}
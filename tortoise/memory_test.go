@@ -0,0 +1,72 @@
+package tortoise
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/tortoise/sim"
+)
+
+func TestMemoryBudget(t *testing.T) {
+	const (
+		size   = 10
+		hdist  = 4
+		window = 100
+	)
+	for _, tc := range []struct {
+		desc   string
+		budget uint64
+		evict  func(verified types.LayerID) types.LayerID
+	}{
+		{
+			desc:  "unlimited",
+			evict: func(types.LayerID) types.LayerID { return types.GetEffectiveGenesis() - 1 },
+		},
+		{
+			desc:   "keeps hdist",
+			budget: 1,
+			evict:  func(verified types.LayerID) types.LayerID { return verified.Sub(hdist).Sub(1) },
+		},
+		{
+			desc:   "within budget",
+			budget: 1 << 30,
+			evict:  func(types.LayerID) types.LayerID { return types.GetEffectiveGenesis() - 1 },
+		},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := context.Background()
+			cfg := defaultTestConfig()
+			cfg.LayerSize = size
+			cfg.Hdist = hdist
+			cfg.Zdist = hdist
+			cfg.WindowSize = window
+			cfg.MemoryBudget = tc.budget
+
+			s := sim.New(sim.WithLayerSize(size))
+			s.Setup()
+			tortoise := tortoiseFromSimState(t, s.GetState(0), WithConfig(cfg), WithLogger(logtest.New(t)))
+
+			var last, verified types.LayerID
+			for _, last = range sim.GenLayers(s, sim.WithSequence(20)) {
+				tortoise.TallyVotes(ctx, last)
+				tortoise.Updates()
+				verified = tortoise.LatestComplete()
+			}
+			require.Equal(t, last.Sub(1), verified)
+			require.Equal(t, tc.evict(verified), tortoise.trtl.evicted)
+
+			var expected memoryUsage
+			for lid, layer := range tortoise.trtl.layers {
+				expected.layers++
+				expected.blocks += uint64(len(layer.blocks))
+				expected.ballots += uint64(len(tortoise.trtl.ballots[lid]))
+			}
+			require.Equal(t, expected, tortoise.trtl.memory)
+		})
+	}
+}
@@ -9,6 +9,29 @@ import (
 const namespace = "tortoise"
 
 var (
+	params = metrics.NewGauge(
+		"params",
+		namespace,
+		"Effective distance and window parameters",
+		[]string{"param"},
+	)
+	hdistParam  = params.WithLabelValues("hdist")
+	zdistParam  = params.WithLabelValues("zdist")
+	windowParam = params.WithLabelValues("window")
+
+	memoryEstimate = metrics.NewGauge(
+		"memory_estimate_bytes",
+		namespace,
+		"Approximate memory used by ballots, blocks and layers in the state",
+		[]string{},
+	).WithLabelValues()
+	overBudgetEvictions = metrics.NewCounter(
+		"over_budget_evictions",
+		namespace,
+		"Number of times layers were evicted before the window to fit into the memory budget",
+		[]string{},
+	).WithLabelValues()
+
 	ballotsNumber = metrics.NewGauge(
 		"ballots",
 		namespace,
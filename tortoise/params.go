@@ -0,0 +1,77 @@
+package tortoise
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+)
+
+// ParamsUpdate changes distance and window parameters starting from the Layer.
+type ParamsUpdate struct {
+	Layer      types.LayerID `mapstructure:"layer" json:"layer"`
+	Hdist      uint32        `mapstructure:"hdist" json:"hdist"`
+	Zdist      uint32        `mapstructure:"zdist" json:"zdist"`
+	WindowSize uint32        `mapstructure:"window-size" json:"window-size"`
+}
+
+func validateParams(hdist, zdist, window uint32) error {
+	if hdist < zdist {
+		return fmt.Errorf("hdist (%d) must be >= zdist (%d)", hdist, zdist)
+	}
+	// the votes within hdist are counted, they must not be evicted from the window
+	if window == 0 || window < hdist {
+		return fmt.Errorf("window size (%d) must be positive and >= hdist (%d)", window, hdist)
+	}
+	return nil
+}
+
+// Validate checks that base parameters and all updates are consistent.
+func (c *Config) Validate() error {
+	if err := validateParams(c.Hdist, c.Zdist, c.WindowSize); err != nil {
+		return err
+	}
+	var prev types.LayerID
+	for i, update := range c.Updates {
+		if update.Layer == 0 {
+			return errors.New("params update must have activation layer")
+		}
+		if i > 0 && !update.Layer.After(prev) {
+			return fmt.Errorf("params updates must be ordered by layer: %s follows %s", update.Layer, prev)
+		}
+		if err := validateParams(update.Hdist, update.Zdist, update.WindowSize); err != nil {
+			return fmt.Errorf("params update at %s: %w", update.Layer, err)
+		}
+		prev = update.Layer
+	}
+	return nil
+}
+
+// updateParams switches to the parameters that are active in the last layer.
+func (t *turtle) updateParams() {
+	for ; t.nextUpdate < len(t.Updates); t.nextUpdate++ {
+		update := t.Updates[t.nextUpdate]
+		if update.Layer.After(t.last) {
+			return
+		}
+		t.Hdist, t.Zdist, t.WindowSize = update.Hdist, update.Zdist, update.WindowSize
+		t.verifying.Hdist, t.verifying.Zdist, t.verifying.WindowSize = update.Hdist, update.Zdist, update.WindowSize
+		t.full.Hdist, t.full.Zdist, t.full.WindowSize = update.Hdist, update.Zdist, update.WindowSize
+		t.logger.Info("tortoise parameters updated",
+			zap.Uint32("activation", update.Layer.Uint32()),
+			zap.Uint32("last", t.last.Uint32()),
+			zap.Uint32("hdist", t.Hdist),
+			zap.Uint32("zdist", t.Zdist),
+			zap.Uint32("window", t.WindowSize),
+		)
+		reportParams(t.Config)
+	}
+}
+
+func reportParams(cfg Config) {
+	hdistParam.Set(float64(cfg.Hdist))
+	zdistParam.Set(float64(cfg.Zdist))
+	windowParam.Set(float64(cfg.WindowSize))
+}
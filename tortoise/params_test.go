@@ -0,0 +1,132 @@
+package tortoise
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/tortoise/sim"
+)
+
+func TestConfigValidate(t *testing.T) {
+	for _, tc := range []struct {
+		desc string
+		cfg  Config
+		err  bool
+	}{
+		{
+			desc: "default",
+			cfg:  DefaultConfig(),
+		},
+		{
+			desc: "zdist larger than hdist",
+			cfg:  Config{Hdist: 5, Zdist: 10, WindowSize: 100},
+			err:  true,
+		},
+		{
+			desc: "zero window",
+			cfg:  Config{Hdist: 10, Zdist: 8},
+			err:  true,
+		},
+		{
+			desc: "window smaller than hdist",
+			cfg:  Config{Hdist: 10, Zdist: 8, WindowSize: 9},
+			err:  true,
+		},
+		{
+			desc: "window equal to hdist",
+			cfg:  Config{Hdist: 10, Zdist: 8, WindowSize: 10},
+		},
+		{
+			desc: "ordered updates",
+			cfg: Config{Hdist: 10, Zdist: 8, WindowSize: 100, Updates: []ParamsUpdate{
+				{Layer: 10, Hdist: 5, Zdist: 5, WindowSize: 50},
+				{Layer: 20, Hdist: 20, Zdist: 10, WindowSize: 200},
+			}},
+		},
+		{
+			desc: "update without layer",
+			cfg:  Config{Hdist: 10, Zdist: 8, WindowSize: 100, Updates: []ParamsUpdate{{Hdist: 5, Zdist: 5, WindowSize: 50}}},
+			err:  true,
+		},
+		{
+			desc: "unordered updates",
+			cfg: Config{Hdist: 10, Zdist: 8, WindowSize: 100, Updates: []ParamsUpdate{
+				{Layer: 20, Hdist: 5, Zdist: 5, WindowSize: 50},
+				{Layer: 20, Hdist: 20, Zdist: 10, WindowSize: 200},
+			}},
+			err: true,
+		},
+		{
+			desc: "invalid update",
+			cfg:  Config{Hdist: 10, Zdist: 8, WindowSize: 100, Updates: []ParamsUpdate{{Layer: 10, Hdist: 5, Zdist: 6, WindowSize: 50}}},
+			err:  true,
+		},
+		{
+			desc: "update without window",
+			cfg:  Config{Hdist: 10, Zdist: 8, WindowSize: 100, Updates: []ParamsUpdate{{Layer: 10, Hdist: 5, Zdist: 5}}},
+			err:  true,
+		},
+		{
+			desc: "update with window smaller than hdist",
+			cfg:  Config{Hdist: 10, Zdist: 8, WindowSize: 100, Updates: []ParamsUpdate{{Layer: 10, Hdist: 20, Zdist: 10, WindowSize: 15}}},
+			err:  true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNewInvalidConfig(t *testing.T) {
+	_, err := New(WithConfig(Config{Hdist: 5, Zdist: 10, WindowSize: 100}))
+	require.Error(t, err)
+}
+
+func TestParamsUpdate(t *testing.T) {
+	const size = 10
+	s := sim.New(sim.WithLayerSize(size))
+	s.Setup()
+
+	ctx := context.Background()
+	cfg := defaultTestConfig()
+	cfg.LayerSize = size
+	update := ParamsUpdate{
+		Layer:      types.GetEffectiveGenesis().Add(3),
+		Hdist:      cfg.Hdist + 2,
+		Zdist:      cfg.Zdist + 1,
+		WindowSize: cfg.WindowSize + 5,
+	}
+	cfg.Updates = []ParamsUpdate{update}
+	tortoise := tortoiseFromSimState(t, s.GetState(0), WithConfig(cfg), WithLogger(logtest.New(t)))
+
+	var last types.LayerID
+	for _, lid := range sim.GenLayers(s, sim.WithSequence(2)) {
+		last = lid
+		tortoise.TallyVotes(ctx, lid)
+	}
+	require.True(t, update.Layer.After(last))
+	require.Equal(t, cfg.Hdist, tortoise.trtl.Hdist)
+
+	for _, lid := range sim.GenLayers(s, sim.WithSequence(3)) {
+		last = lid
+		tortoise.TallyVotes(ctx, lid)
+	}
+	require.False(t, update.Layer.After(last))
+	for _, c := range []Config{tortoise.trtl.Config, tortoise.trtl.verifying.Config, tortoise.trtl.full.Config} {
+		require.Equal(t, update.Hdist, c.Hdist)
+		require.Equal(t, update.Zdist, c.Zdist)
+		require.Equal(t, update.WindowSize, c.WindowSize)
+	}
+	require.Equal(t, last.Sub(1), tortoise.LatestComplete())
+}
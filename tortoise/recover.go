@@ -23,15 +23,30 @@ func Recover(db *datastore.CachedDB, latest types.LayerID, beacon system.BeaconG
 	if err != nil {
 		return nil, err
 	}
+	if _, err := recoverState(context.Background(), trtl, db, latest, beacon, nil); err != nil {
+		return nil, err
+	}
+	return trtl, nil
+}
 
+// recoverState loads state from database into trtl and returns the last loaded layer.
+// onLayer is called after every loaded layer if not nil.
+func recoverState(
+	ctx context.Context,
+	trtl *Tortoise,
+	db *datastore.CachedDB,
+	latest types.LayerID,
+	beacon system.BeaconGetter,
+	onLayer func(types.LayerID),
+) (types.LayerID, error) {
 	layer, err := ballots.LatestLayer(db)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load latest known layer: %w", err)
+		return 0, fmt.Errorf("failed to load latest known layer: %w", err)
 	}
 
 	malicious, err := identities.GetMalicious(db)
 	if err != nil {
-		return nil, fmt.Errorf("recover malicious %w", err)
+		return 0, fmt.Errorf("recover malicious %w", err)
 	}
 	for _, id := range malicious {
 		trtl.OnMalfeasance(id)
@@ -40,28 +55,34 @@ func Recover(db *datastore.CachedDB, latest types.LayerID, beacon system.BeaconG
 	if types.GetEffectiveGenesis() != types.FirstEffectiveGenesis() {
 		// need to load the golden atxs after a checkpoint recovery
 		if err := recoverEpoch(types.GetEffectiveGenesis().Add(1).GetEpoch(), trtl, db, beacon); err != nil {
-			return nil, err
+			return 0, err
 		}
 	}
 
 	epoch, err := atxs.LatestEpoch(db)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load latest epoch: %w", err)
+		return 0, fmt.Errorf("failed to load latest epoch: %w", err)
 	}
 	epoch++ // recoverEpoch expects target epoch, rather than publish
 	if layer.GetEpoch() != epoch {
 		for eid := layer.GetEpoch(); eid <= epoch; eid++ {
 			if err := recoverEpoch(eid, trtl, db, beacon); err != nil {
-				return nil, err
+				return 0, err
 			}
 		}
 	}
 	for lid := types.GetEffectiveGenesis().Add(1); !lid.After(layer); lid = lid.Add(1) {
-		if err := RecoverLayer(context.Background(), trtl, db, beacon, lid, types.MinLayer(layer, latest)); err != nil {
-			return nil, fmt.Errorf("failed to load tortoise state at layer %d: %w", lid, err)
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		if err := RecoverLayer(ctx, trtl, db, beacon, lid, types.MinLayer(layer, latest)); err != nil {
+			return 0, fmt.Errorf("failed to load tortoise state at layer %d: %w", lid, err)
+		}
+		if onLayer != nil {
+			onLayer(lid)
 		}
 	}
-	return trtl, nil
+	return layer, nil
 }
 
 func recoverEpoch(epoch types.EpochID, trtl *Tortoise, db *datastore.CachedDB, beacondb system.BeaconGetter) error {
@@ -0,0 +1,199 @@
+package tortoise
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/datastore"
+	"github.com/spacemeshos/go-spacemesh/sql/atxs"
+	"github.com/spacemeshos/go-spacemesh/sql/ballots"
+	"github.com/spacemeshos/go-spacemesh/sql/identities"
+	"github.com/spacemeshos/go-spacemesh/system"
+)
+
+var (
+	// ErrRerunInProgress is returned if rerun is requested while previous one is not finished.
+	ErrRerunInProgress = errors.New("tortoise: rerun in progress")
+	// ErrRerunPruned is returned if rerun changed opinion about layers whose transactions
+	// were already pruned. such layers can't be applied again, so the state is not replaced.
+	ErrRerunPruned = errors.New("tortoise: rerun changed opinion about pruned layers")
+)
+
+// RerunStatus describes progress of the rerun.
+type RerunStatus struct {
+	Running bool `json:"running"`
+	// From and To are the first and the last layers in the rerun window.
+	From    types.LayerID `json:"from"`
+	To      types.LayerID `json:"to"`
+	Current types.LayerID `json:"current"`
+	Started time.Time     `json:"started"`
+	// Estimated is the expected time of completion, computed from the average time per layer.
+	Estimated time.Time `json:"estimated,omitempty"`
+	Finished  time.Time `json:"finished,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+type rerunTracker struct {
+	mu     sync.Mutex
+	status RerunStatus
+}
+
+func (r *rerunTracker) start(from, to types.LayerID, now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.status.Running {
+		return false
+	}
+	r.status = RerunStatus{
+		Running: true,
+		From:    from,
+		To:      to,
+		Current: from,
+		Started: now,
+	}
+	return true
+}
+
+func (r *rerunTracker) progress(lid types.LayerID, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status.Current = lid
+	done := lid.Difference(r.status.From) + 1
+	if r.status.To.After(lid) && done > 0 {
+		elapsed := now.Sub(r.status.Started)
+		remaining := r.status.To.Difference(lid)
+		r.status.Estimated = now.Add(elapsed / time.Duration(done) * time.Duration(remaining))
+	} else {
+		r.status.Estimated = now
+	}
+}
+
+func (r *rerunTracker) finish(err error, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status.Running = false
+	r.status.Finished = now
+	if err != nil {
+		r.status.Error = err.Error()
+	}
+}
+
+func (r *rerunTracker) get() RerunStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.status
+}
+
+// RerunStatus returns progress of the current or the last finished rerun.
+func (t *Tortoise) RerunStatus() RerunStatus {
+	return t.rerun.get()
+}
+
+// Rerun recomputes tortoise state from the database and replaces current state with the result.
+//
+// Rerun doesn't hold the lock while the state is recomputed, so that tortoise can keep
+// processing new data. Lock is acquired only to load layers that were stored after rerun
+// started, and to swap the state.
+//
+// Layers before pruned had their transaction bodies pruned and can't be applied again.
+// If rerun changed opinion about any of them, the state is not replaced and ErrRerunPruned is returned.
+// Zero pruned means that nothing was pruned.
+func (t *Tortoise) Rerun(ctx context.Context, db *datastore.CachedDB, beacon system.BeaconGetter, pruned types.LayerID) error {
+	to, err := ballots.LatestLayer(db)
+	if err != nil {
+		return fmt.Errorf("rerun latest layer: %w", err)
+	}
+	if !t.rerun.start(types.GetEffectiveGenesis().Add(1), to, time.Now()) {
+		return ErrRerunInProgress
+	}
+	err = t.runRerun(ctx, db, beacon, pruned)
+	t.rerun.finish(err, time.Now())
+	return err
+}
+
+func (t *Tortoise) runRerun(ctx context.Context, db *datastore.CachedDB, beacon system.BeaconGetter, pruned types.LayerID) error {
+	start := time.Now()
+	rerun, err := New(WithConfig(t.cfg))
+	if err != nil {
+		return err
+	}
+	rerun.logger = t.logger.Named("rerun")
+	rerun.trtl.logger = rerun.logger
+
+	t.mu.Lock()
+	current := t.trtl.last
+	t.mu.Unlock()
+
+	t.logger.Info("tortoise rerun started", zap.Uint32("current", current.Uint32()))
+	last, err := recoverState(ctx, rerun, db, current, beacon, func(lid types.LayerID) {
+		t.rerun.progress(lid, time.Now())
+	})
+	if err != nil {
+		return fmt.Errorf("rerun: %w", err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := catchUp(ctx, rerun, db, beacon, last, t.trtl.last); err != nil {
+		return fmt.Errorf("rerun catch up: %w", err)
+	}
+	rerun.TallyVotes(ctx, t.trtl.last)
+	if pending := rerun.trtl.pending; pending != 0 && pending < pruned {
+		t.logger.Warn("tortoise rerun changed opinion about pruned layers",
+			zap.Uint32("pending", pending.Uint32()),
+			zap.Uint32("pruned", pruned.Uint32()),
+		)
+		return fmt.Errorf("%w: layer %d is before %d", ErrRerunPruned, pending, pruned)
+	}
+	t.trtl = rerun.trtl
+	t.trtl.logger = t.logger
+	t.logger.Info("tortoise rerun finished",
+		zap.Uint32("last", t.trtl.last.Uint32()),
+		zap.Uint32("verified", t.trtl.verified.Uint32()),
+		zap.Bool("full", t.trtl.isFull),
+		zap.Duration("duration", time.Since(start)),
+	)
+	return nil
+}
+
+// catchUp loads data that was stored after rerun loaded layers up to and including last.
+func catchUp(
+	ctx context.Context,
+	rerun *Tortoise,
+	db *datastore.CachedDB,
+	beacon system.BeaconGetter,
+	last, current types.LayerID,
+) error {
+	malicious, err := identities.GetMalicious(db)
+	if err != nil {
+		return fmt.Errorf("malicious: %w", err)
+	}
+	for _, id := range malicious {
+		rerun.OnMalfeasance(id)
+	}
+	epoch, err := atxs.LatestEpoch(db)
+	if err != nil {
+		return fmt.Errorf("latest epoch: %w", err)
+	}
+	for eid := last.GetEpoch(); eid <= epoch+1; eid++ {
+		if err := recoverEpoch(eid, rerun, db, beacon); err != nil {
+			return err
+		}
+	}
+	latest, err := ballots.LatestLayer(db)
+	if err != nil {
+		return fmt.Errorf("latest layer: %w", err)
+	}
+	for lid := last.Add(1); !lid.After(latest); lid = lid.Add(1) {
+		if err := RecoverLayer(ctx, rerun, db, beacon, lid, types.MinLayer(latest, current)); err != nil {
+			return fmt.Errorf("layer %d: %w", lid, err)
+		}
+	}
+	return nil
+}
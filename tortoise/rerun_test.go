@@ -148,3 +148,80 @@ func benchmarkTallyVotes(b *testing.B, size int, windowsize uint32, opts ...sim.
 		tortoise.TallyVotes(ctx, last)
 	}
 }
+
+func TestBackgroundRerun(t *testing.T) {
+	ctx := context.Background()
+	const size = 10
+	s := sim.New(sim.WithLayerSize(size))
+	s.Setup()
+
+	cfg := defaultTestConfig()
+	cfg.LayerSize = size
+	tortoise := tortoiseFromSimState(t, s.GetState(0), WithLogger(logtest.New(t)), WithConfig(cfg))
+	var last types.LayerID
+	for i := 0; i < 20; i++ {
+		last = s.Next()
+		tortoise.TallyVotes(ctx, last)
+	}
+	require.Equal(t, last.Sub(1), tortoise.LatestComplete())
+	require.False(t, tortoise.RerunStatus().Running)
+
+	require.NoError(t, tortoise.Rerun(ctx, s.GetState(0).DB, s.GetState(0).Beacons, 0))
+	status := tortoise.RerunStatus()
+	require.False(t, status.Running)
+	require.Empty(t, status.Error)
+	require.Equal(t, types.GetEffectiveGenesis().Add(1), status.From)
+	require.Equal(t, last, status.To)
+	require.Equal(t, last, status.Current)
+	require.False(t, status.Finished.Before(status.Started))
+	require.Equal(t, last.Sub(1), tortoise.LatestComplete())
+
+	// tortoise keeps working with the state computed by rerun
+	last = s.Next()
+	tortoise.TallyVotes(ctx, last)
+	require.Equal(t, last.Sub(1), tortoise.LatestComplete())
+}
+
+func TestRerunPruned(t *testing.T) {
+	ctx := context.Background()
+	const size = 10
+	s := sim.New(sim.WithLayerSize(size))
+	s.Setup()
+
+	cfg := defaultTestConfig()
+	cfg.LayerSize = size
+	tortoise := tortoiseFromSimState(t, s.GetState(0), WithLogger(logtest.New(t)), WithConfig(cfg))
+	var last types.LayerID
+	for i := 0; i < 20; i++ {
+		last = s.Next()
+		tortoise.TallyVotes(ctx, last)
+	}
+	require.NotEmpty(t, tortoise.Updates())
+	before := tortoise.trtl
+
+	// opinions are not persisted in the simulation, so rerun reports every layer as changed
+	err := tortoise.Rerun(ctx, s.GetState(0).DB, s.GetState(0).Beacons, last)
+	require.ErrorIs(t, err, ErrRerunPruned)
+	status := tortoise.RerunStatus()
+	require.False(t, status.Running)
+	require.NotEmpty(t, status.Error)
+	require.Same(t, before, tortoise.trtl)
+	require.Empty(t, tortoise.Updates())
+}
+
+func TestRerunProgress(t *testing.T) {
+	var tracker rerunTracker
+	start := time.Now()
+	require.True(t, tracker.start(11, 20, start))
+	require.False(t, tracker.start(11, 20, start))
+
+	tracker.progress(15, start.Add(5*time.Second))
+	status := tracker.get()
+	require.True(t, status.Running)
+	require.Equal(t, types.LayerID(15), status.Current)
+	require.Equal(t, start.Add(10*time.Second), status.Estimated)
+
+	tracker.finish(nil, start.Add(10*time.Second))
+	require.False(t, tracker.get().Running)
+	require.True(t, tracker.start(11, 20, start))
+}
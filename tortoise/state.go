@@ -69,6 +69,8 @@ type (
 		// malnodes is a collection with all nodes that equivocated in history.
 		// each node id is 32 bytes. 100 000 of such nodes is only about ~3MB
 		malnodes map[types.NodeID]struct{}
+
+		memory memoryUsage
 	}
 )
 
@@ -94,6 +96,7 @@ func (s *state) layer(lid types.LayerID) *layerInfo {
 	layer, exist := s.layers[lid]
 	if !exist {
 		layersNumber.Inc()
+		s.memory.layers++
 		layer = &layerInfo{lid: lid}
 		s.layers[lid] = layer
 	}
@@ -112,12 +115,14 @@ func (s *state) epoch(eid types.EpochID) *epochInfo {
 
 func (s *state) addBallot(ballot *ballotInfo) {
 	ballotsNumber.Inc()
+	s.memory.ballots++
 	s.ballots[ballot.layer] = append(s.ballots[ballot.layer], ballot)
 	s.ballotRefs[ballot.id] = ballot
 }
 
 func (s *state) addBlock(block *blockInfo) {
 	blocksNumber.Inc()
+	s.memory.blocks++
 	layer := s.layer(block.layer)
 	if layer.hareTerminated {
 		block.hare = against
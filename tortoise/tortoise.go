@@ -41,6 +41,9 @@ type turtle struct {
 
 	isFull bool
 	full   *full
+
+	// nextUpdate is the index of the next params update in Config.Updates.
+	nextUpdate int
 }
 
 // newTurtle creates a new verifying tortoise algorithm instance.
@@ -62,6 +65,7 @@ func newTurtle(logger *zap.Logger, config Config) *turtle {
 		lid:            genesis,
 		hareTerminated: true,
 	}
+	t.memory.layers++
 	t.verifying = newVerifying(config, t.state)
 	t.full = newFullTortoise(config, t.state)
 	t.full.counted = genesis
@@ -83,10 +87,9 @@ func (t *turtle) evict(ctx context.Context) {
 	if !t.verified.After(types.GetEffectiveGenesis().Add(t.Hdist)) {
 		return
 	}
-	windowStart, ok := t.lookbackWindowStart()
-	if !ok {
-		return
-	}
+	// window start is zero if verified layer is within the window
+	windowStart, _ := t.lookbackWindowStart()
+	windowStart = t.boundedWindowStart(windowStart)
 	t.logger.Debug("evict in memory state",
 		zap.Stringer("pending", t.pending),
 		zap.Stringer("from_layer", t.evicted.Add(1)),
@@ -101,10 +104,13 @@ func (t *turtle) evict(ctx context.Context) {
 	for lid := t.evicted.Add(1); lid.Before(windowStart); lid = lid.Add(1) {
 		for _, ballot := range t.ballots[lid] {
 			ballotsNumber.Dec()
+			t.memory.ballots--
 			delete(t.ballotRefs, ballot.id)
 		}
-		for range t.layers[lid].blocks {
-			blocksNumber.Dec()
+		if layer, exist := t.layers[lid]; exist {
+			blocksNumber.Sub(float64(len(layer.blocks)))
+			t.memory.blocks -= uint64(len(layer.blocks))
+			t.memory.layers--
 		}
 		layersNumber.Dec()
 		delete(t.layers, lid)
@@ -123,6 +129,7 @@ func (t *turtle) evict(ctx context.Context) {
 	}
 	t.evicted = windowStart.Sub(1)
 	evictedLayer.Set(float64(t.evicted))
+	memoryEstimate.Set(float64(t.memory.total()))
 }
 
 // EncodeVotes by choosing base ballot and explicit votes.
@@ -319,6 +326,7 @@ func (t *turtle) onLayer(ctx context.Context, last types.LayerID) {
 		update := t.last.GetEpoch() != last.GetEpoch()
 		t.last = last
 		lastLayer.Set(float64(t.last))
+		t.updateParams()
 		if update {
 			epoch := t.epoch(last.GetEpoch())
 			t.localThreshold = epoch.weight.
@@ -1133,7 +1133,10 @@ func TestBaseBallotPrioritization(t *testing.T) {
 			ctx := context.Background()
 			cfg := defaultTestConfig()
 			cfg.LayerSize = size
-			cfg.WindowSize = tc.window
+			if tc.window != 0 {
+				// the window can't be smaller than hdist
+				cfg.Hdist, cfg.Zdist, cfg.WindowSize = tc.window, tc.window, tc.window
+			}
 			tortoise := tortoiseFromSimState(t, s.GetState(0), WithConfig(cfg), WithLogger(logtest.New(t)))
 
 			for _, lid := range sim.GenLayers(s, tc.seqs...) {
@@ -1907,7 +1910,7 @@ func TestStateManagement(t *testing.T) {
 	const (
 		size   = 10
 		hdist  = 4
-		window = 2
+		window = 4
 	)
 	ctx := context.Background()
 	cfg := defaultTestConfig()
@@ -132,14 +132,15 @@ type traceEvent interface {
 }
 
 type ConfigTrace struct {
-	Hdist                    uint32 `json:"hdist"`
-	Zdist                    uint32 `json:"zdist"`
-	WindowSize               uint32 `json:"window"`
-	MaxExceptions            uint32 `json:"exceptions"`
-	BadBeaconVoteDelayLayers uint32 `json:"delay"`
-	LayerSize                uint32 `json:"layer-size"`
-	EpochSize                uint32 `json:"epoch-size"` // this field is not set in the original config
-	EffectiveGenesis         uint32 `json:"effective-genesis"`
+	Hdist                    uint32         `json:"hdist"`
+	Zdist                    uint32         `json:"zdist"`
+	WindowSize               uint32         `json:"window"`
+	MaxExceptions            uint32         `json:"exceptions"`
+	BadBeaconVoteDelayLayers uint32         `json:"delay"`
+	LayerSize                uint32         `json:"layer-size"`
+	EpochSize                uint32         `json:"epoch-size"` // this field is not set in the original config
+	EffectiveGenesis         uint32         `json:"effective-genesis"`
+	Updates                  []ParamsUpdate `json:"updates,omitempty"`
 }
 
 func (c *ConfigTrace) Type() eventType {
@@ -160,6 +161,7 @@ func (c *ConfigTrace) Run(r *traceRunner) error {
 		MaxExceptions:            int(c.MaxExceptions),
 		BadBeaconVoteDelayLayers: c.BadBeaconVoteDelayLayers,
 		LayerSize:                c.LayerSize,
+		Updates:                  c.Updates,
 	}))...)
 	if err != nil {
 		return err
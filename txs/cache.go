@@ -5,10 +5,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/bits"
 	"sort"
 	"sync"
 	"time"
 
+	lru "github.com/hashicorp/golang-lru/v2"
+
 	"github.com/spacemeshos/go-spacemesh/common/types"
 	"github.com/spacemeshos/go-spacemesh/events"
 	"github.com/spacemeshos/go-spacemesh/log"
@@ -25,8 +28,12 @@ const (
 var (
 	errBadNonce            = errors.New("bad nonce")
 	errInsufficientBalance = errors.New("insufficient balance")
-	errTooManyNonce        = errors.New("account has too many nonce pending")
+	errTooManyNonce        = errors.New("account has too many transactions pending")
 	errLayerNotInOrder     = errors.New("layers not applied in order")
+	// errReplacementUnderpriced is returned for the transaction with the nonce of the pending
+	// transaction, if its fee is not higher than the fee of the pending one by the required bump.
+	errReplacementUnderpriced = errors.New("replacement transaction underpriced")
+	errReplaced               = errors.New("replaced by fee")
 )
 
 // a candidate for the mempool.
@@ -68,11 +75,32 @@ type accountCache struct {
 	//   (that may contain incoming funds for that account)
 	// - a better tx arrived (higher fee) and made higher nonce txs infeasible due to insufficient balance
 	//   deemed by conservative state.
+	// - txs were evicted from the full mempool.
 	// TODO: evict accounts that only has DB-only txs
 	// https://github.com/spacemeshos/go-spacemesh/issues/3668
 	moreInDB bool
+	// maxAge is the max time the tx stays in the mempool, it is not reloaded from db once expired.
+	maxAge time.Duration
 
-	cachedTXs map[types.TransactionID]*NanoTX // shared with the cache instance
+	cachedTXs  map[types.TransactionID]*NanoTX // shared with the cache instance
+	cachedSize *int                            // shared with the cache instance
+	// maxTXs is the max number of the transactions of the account in the cache.
+	maxTXs int
+	// holdGaps is true if the transactions after the nonce gap are not added to the cache.
+	holdGaps bool
+}
+
+func (ac *accountCache) cache(ntx *NanoTX) {
+	ac.uncache(ntx.ID)
+	ac.cachedTXs[ntx.ID] = ntx
+	*ac.cachedSize += ntx.Size
+}
+
+func (ac *accountCache) uncache(tid types.TransactionID) {
+	if ntx, ok := ac.cachedTXs[tid]; ok {
+		delete(ac.cachedTXs, tid)
+		*ac.cachedSize -= ntx.Size
+	}
 }
 
 func (ac *accountCache) nextNonce() uint64 {
@@ -90,9 +118,9 @@ func (ac *accountCache) availBalance() uint64 {
 }
 
 func (ac *accountCache) precheck(logger log.Log, ntx *NanoTX) (*list.Element, *candidate, error) {
-	if ac.txsByNonce.Len() >= maxTXsPerAcct {
+	if ac.txsByNonce.Len() >= ac.maxTXs {
 		ac.moreInDB = true
-		return nil, nil, errTooManyNonce
+		return nil, nil, fmt.Errorf("%w: %d in mempool, max %d", errTooManyNonce, ac.txsByNonce.Len(), ac.maxTXs)
 	}
 	balance := ac.startBalance
 	var prev *list.Element
@@ -122,7 +150,18 @@ func (ac *accountCache) precheck(logger log.Log, ntx *NanoTX) (*list.Element, *c
 	return prev, &candidate{best: ntx, postBalance: balance - ntx.MaxSpending()}, nil
 }
 
-func (ac *accountCache) accept(logger log.Log, ntx *NanoTX, blockSeed []byte) error {
+// bumped returns true if the fee of the transaction is higher than the fee of the other one
+// by at least the given percentage.
+func bumped(ntx, other *NanoTX, percent uint64) bool {
+	hi, lo := bits.Mul64(ntx.Fee(), 100)
+	minHi, minLo := bits.Mul64(other.Fee(), 100+percent)
+	return hi > minHi || (hi == minHi && lo >= minLo)
+}
+
+// accept adds the transaction to the account cache. the transaction replaces the pending one with the
+// same nonce if it is better, and when feeBump is not zero, if its fee is also higher by feeBump percent.
+// the replaced transaction is returned.
+func (ac *accountCache) accept(logger log.Log, ntx *NanoTX, blockSeed []byte, feeBump uint64) (*NanoTX, error) {
 	var (
 		added, prev *list.Element
 		cand        *candidate
@@ -131,7 +170,7 @@ func (ac *accountCache) accept(logger log.Log, ntx *NanoTX, blockSeed []byte) er
 	)
 	prev, cand, err = ac.precheck(logger, ntx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if prev == nil { // insert at the first position
@@ -139,16 +178,26 @@ func (ac *accountCache) accept(logger log.Log, ntx *NanoTX, blockSeed []byte) er
 	} else if prevCand := prev.Value.(*candidate); prevCand.nonce() < ntx.Nonce {
 		added = ac.txsByNonce.InsertAfter(cand, prev)
 	} else { // existing nonce
+		if feeBump > 0 && !(ntx.Better(prevCand.best, blockSeed) && bumped(ntx, prevCand.best, feeBump)) {
+			logger.With().Debug("replacement transaction underpriced",
+				ntx.ID,
+				log.Stringer("pending", prevCand.id()),
+				log.Uint64("nonce", ntx.Nonce),
+				log.Uint64("fee", ntx.Fee()),
+				log.Uint64("pending_fee", prevCand.best.Fee()))
+			return nil, fmt.Errorf("%w: pending fee %d, required bump %d%%",
+				errReplacementUnderpriced, prevCand.best.Fee(), feeBump)
+		}
 		if !ntx.Better(prevCand.best, blockSeed) {
-			return nil
+			return nil, nil
 		}
 		added = prev
 		replaced = prevCand.best
-		delete(ac.cachedTXs, prevCand.best.ID)
+		ac.uncache(prevCand.best.ID)
 		prevCand.best = ntx
 		prevCand.postBalance = cand.postBalance
 	}
-	ac.cachedTXs[ntx.ID] = ntx
+	ac.cache(ntx)
 
 	if replaced != nil {
 		logger.With().Debug("better transaction replaced for nonce",
@@ -186,13 +235,13 @@ func (ac *accountCache) accept(logger log.Log, ntx *NanoTX, blockSeed []byte) er
 		rm := next
 		next = next.Next()
 		removed := ac.txsByNonce.Remove(rm).(*candidate)
-		delete(ac.cachedTXs, removed.id())
+		ac.uncache(removed.id())
 		logger.With().Debug("tx made infeasible by new/better transaction",
 			removed.id(),
 			log.Uint64("nonce", removed.nonce()),
 			log.Uint64("max_spending", ntx.MaxSpending()))
 	}
-	return nil
+	return replaced, nil
 }
 
 func nonceMarshaller(any any) log.ArrayMarshaler {
@@ -235,21 +284,38 @@ func (ac *accountCache) addBatch(logger log.Log, nonce2TXs map[uint64][]*NanoTX,
 		sortedNonce = append(sortedNonce, nonce)
 	}
 	sort.Slice(sortedNonce, func(i, j int) bool { return sortedNonce[i] < sortedNonce[j] })
+	now := time.Now()
 	for _, nonce := range sortedNonce {
-		best := findBest(nonce2TXs[nonce], balance, blockSeed)
+		ntxs := nonce2TXs[nonce]
+		if ac.maxAge > 0 {
+			ntxs = make([]*NanoTX, 0, len(nonce2TXs[nonce]))
+			for _, ntx := range nonce2TXs[nonce] {
+				if !ac.expired(ntx, now) {
+					ntxs = append(ntxs, ntx)
+				}
+			}
+		}
+		best := findBest(ntxs, balance, blockSeed)
 		if best == nil {
 			logger.With().Debug("no feasible transactions at nonce",
 				log.Uint64("nonce", nonce),
 				log.Uint64("balance", balance))
 			continue
 		}
+		if ac.holdGaps && best.Layer == 0 && nonce > ac.nextNonce() {
+			// the transactions after the nonce gap stay in db until it is filled
+			logger.With().Debug("nonce gap in pending txs",
+				log.Uint64("nonce", nonce),
+				log.Uint64("next_nonce", ac.nextNonce()))
+			break
+		}
 
 		logger.With().Debug("found best in nonce txs",
 			best.ID,
 			log.Uint64("nonce", nonce),
 			log.Uint64("fee", best.Fee()))
 
-		if err := ac.accept(logger, best, blockSeed); err != nil {
+		if _, err := ac.accept(logger, best, blockSeed, 0); err != nil {
 			if errors.Is(err, errTooManyNonce) {
 				break
 			}
@@ -283,15 +349,16 @@ func findBest(ntxs []*NanoTX, balance uint64, blockSeed []byte) *NanoTX {
 //   - nonce is smaller than the next nonce in state: reject from cache
 //   - too many txs present: reject from cache
 //   - nonce already exists in the cache:
-//     if it is better than the best candidate in that nonce group, swap
+//     if it is better than the best candidate in that nonce group and its fee is higher
+//     by the fee bump, swap. otherwise reject as underpriced if the fee bump is set.
 //   - nonce not present: add to cache.
-func (ac *accountCache) add(logger log.Log, tx *types.Transaction, received time.Time) error {
+func (ac *accountCache) add(logger log.Log, tx *types.Transaction, received time.Time, feeBump uint64) (*NanoTX, error) {
 	if tx.Nonce < ac.startNonce {
 		logger.With().Debug("nonce too small",
 			tx.ID,
 			log.Uint64("next_nonce", ac.startNonce),
 			log.Uint64("tx_nonce", tx.Nonce))
-		return errBadNonce
+		return nil, errBadNonce
 	}
 
 	ntx := NewNanoTX(&types.MeshTransaction{
@@ -301,17 +368,22 @@ func (ac *accountCache) add(logger log.Log, tx *types.Transaction, received time
 		BlockID:     types.EmptyBlockID,
 	})
 
-	err := ac.accept(logger, ntx, nil)
+	replaced, err := ac.accept(logger, ntx, nil, feeBump)
 	if err != nil {
 		if errors.Is(err, errTooManyNonce) {
 			mempoolTxCount.WithLabelValues(tooManyNonce).Inc()
 		} else if errors.Is(err, errInsufficientBalance) {
 			mempoolTxCount.WithLabelValues(balanceTooSmall).Inc()
+		} else if errors.Is(err, errReplacementUnderpriced) {
+			mempoolTxCount.WithLabelValues(underpriced).Inc()
 		}
-		return err
+		return nil, err
+	}
+	if replaced != nil {
+		mempoolTxCount.WithLabelValues(replacedByFee).Inc()
 	}
 	mempoolTxCount.WithLabelValues(mempool).Inc()
-	return nil
+	return replaced, nil
 }
 
 func (ac *accountCache) addPendingFromNonce(logger log.Log, db *sql.Database, nonce uint64, applied types.LayerID) error {
@@ -394,7 +466,7 @@ func (ac *accountCache) resetAfterApply(logger log.Log, db *sql.Database, nextNo
 	logger = logger.WithFields(ac.addr)
 	logger.With().Debug("resetting to nonce", log.Uint64("nonce", nextNonce))
 	for e := ac.txsByNonce.Front(); e != nil; e = e.Next() {
-		delete(ac.cachedTXs, e.Value.(*candidate).id())
+		ac.uncache(e.Value.(*candidate).id())
 	}
 	ac.txsByNonce = list.New()
 	ac.startNonce = nextNonce
@@ -411,18 +483,45 @@ type stateFunc func(types.Address) (uint64, uint64)
 type Cache struct {
 	logger log.Log
 	stateF stateFunc
+	// feeBump is the percentage by which the fee of the transaction must be higher than the fee
+	// of the pending transaction with the same principal and nonce to replace it.
+	// zero means that the better transaction replaces the pending one.
+	feeBump uint64
+	// maxTXs is the max number of transactions in the mempool, zero means no limit.
+	// the cheapest transactions are evicted when the limit is exceeded.
+	maxTXs int
+	// maxAge is the max time the transaction stays in the mempool, zero means no limit.
+	maxAge time.Duration
+	// maxBytes is the max total size of the transactions in the mempool, zero means no limit.
+	maxBytes int
+	// maxAccountTXs is the max number of the transactions of a single account in the mempool.
+	maxAccountTXs int
 
 	mu        sync.Mutex
 	pending   map[types.Address]*accountCache
 	cachedTXs map[types.TransactionID]*NanoTX // shared with accountCache instances
+	// cachedSize is the total size of the cached transactions, shared with accountCache instances.
+	cachedSize int
+	// orphans holds the transactions with the nonce gap until it is filled. it is nil if the cache
+	// doesn't hold back such transactions, e.g. the cache that orders the transactions of a block.
+	orphans *orphanPool
+	// rejected keeps the rejection reasons of the most recently rejected txs.
+	rejected *lru.Cache[types.TransactionID, string]
 }
 
 func NewCache(s stateFunc, logger log.Log) *Cache {
+	rejected, err := lru.New[types.TransactionID, string](maxRejected)
+	if err != nil {
+		logger.Fatal("failed to create rejected txs cache", err)
+	}
 	return &Cache{
 		logger:    logger,
 		stateF:    s,
 		pending:   make(map[types.Address]*accountCache),
 		cachedTXs: make(map[types.TransactionID]*NanoTX),
+		// the limit per account is also used by the block builder
+		maxAccountTXs: maxTXsPerAcct,
+		rejected:      rejected,
 	}
 }
 
@@ -525,6 +624,10 @@ func (c *Cache) createAcctIfNotPresent(addr types.Address) {
 			startBalance: balance,
 			txsByNonce:   list.New(),
 			cachedTXs:    c.cachedTXs,
+			cachedSize:   &c.cachedSize,
+			maxAge:       c.maxAge,
+			maxTXs:       c.maxAccountTXs,
+			holdGaps:     c.orphans != nil,
 		}
 	}
 }
@@ -550,10 +653,17 @@ func (c *Cache) cleanupAccounts(accounts map[types.Address]struct{}) {
 //     a tx rejected due to insufficient balance MAY become feasible after a layer is applied (principal
 //     received incoming funds). when we receive a errInsufficientBalance tx, we should store it in db and
 //     re-evaluate it after each layer is applied.
-//   - errTooManyNonce: when a principal has way too many nonces, we don't want to blow up the memory. they should
-//     be stored in db and retrieved after each earlier nonce is applied.
 func acceptable(err error) bool {
-	return err == nil || errors.Is(err, errInsufficientBalance) || errors.Is(err, errTooManyNonce)
+	return err == nil || errors.Is(err, errInsufficientBalance)
+}
+
+// persistable returns true if the transaction rejected due to the mempool limits should still be stored in db.
+// such transactions may be referenced by proposals or blocks, and may become feasible later.
+//   - errTooManyNonce: when a principal has way too many nonces, we don't want to blow up the memory. they are
+//     stored in db, relayed to peers and retrieved after each earlier nonce is applied.
+//   - errReplacementUnderpriced, errMempoolFull: the fee is too low for the mempool.
+func persistable(err error) bool {
+	return errors.Is(err, errTooManyNonce) || errors.Is(err, errReplacementUnderpriced) || errors.Is(err, errMempoolFull)
 }
 
 func (c *Cache) Add(ctx context.Context, db *sql.Database, tx *types.Transaction, received time.Time, mustPersist bool) error {
@@ -563,16 +673,35 @@ func (c *Cache) Add(ctx context.Context, db *sql.Database, tx *types.Transaction
 	c.createAcctIfNotPresent(principal)
 	defer c.cleanupAccounts(map[types.Address]struct{}{principal: {}})
 	logger := c.logger.WithContext(ctx).WithFields(principal)
-	err := c.pending[principal].add(logger, tx, received)
+	if c.orphans != nil && tx.Nonce > c.pending[principal].nextNonce() {
+		return c.addOrphan(logger, db, tx, received)
+	}
+	evicted, err := c.pending[principal].add(logger, tx, received, c.feeBump)
 	if acceptable(err) {
+		if err == nil {
+			c.promoteOrphans(logger, principal)
+		}
 		err = nil
-		mempoolTxCount.WithLabelValues(accepted).Inc()
+		for _, ntx := range c.evictOverflow(logger) {
+			if ntx.ID == tx.ID {
+				err = fmt.Errorf("%w: gas price %d", errMempoolFull, ntx.GasPrice)
+			}
+		}
+		if err == nil {
+			mempoolTxCount.WithLabelValues(accepted).Inc()
+		}
+	} else {
+		c.reject(tx.ID, err)
 	}
-	if err == nil || mustPersist {
+	if err == nil || mustPersist || persistable(err) {
 		if dbErr := transactions.Add(db, tx, received); dbErr != nil {
 			return dbErr
 		}
 	}
+	if evicted != nil {
+		c.reject(evicted.ID, fmt.Errorf("%w: %s", errReplaced, tx.ID))
+		events.ReportTxReplaced(principal, tx.Nonce, evicted.ID, tx.ID)
+	}
 	return err
 }
 
@@ -757,6 +886,11 @@ func (c *Cache) ApplyLayer(
 		}
 		acctResetDuration.Observe(float64(time.Since(t2)))
 	}
+	for principal := range c.pending {
+		c.promoteOrphans(logger, principal)
+	}
+	c.evictExpired(logger, time.Now())
+	c.evictOverflow(logger)
 	return nil
 }
 
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/events"
 	"github.com/spacemeshos/go-spacemesh/log/logtest"
 	"github.com/spacemeshos/go-spacemesh/signing"
 	"github.com/spacemeshos/go-spacemesh/sql"
@@ -558,7 +559,7 @@ func TestCache_Account_InsufficientBalance_AllPendingTXs(t *testing.T) {
 	require.True(t, tc.MoreInDB(ta.principal))
 }
 
-func TestCache_Account_Add_TooManyNonce_OK(t *testing.T) {
+func TestCache_Account_Add_TooManyNonce(t *testing.T) {
 	tc, ta := createSingleAccountTestCache(t)
 	ta.balance = uint64(1000000)
 	mtxs := genAndSaveTXs(t, tc.db, ta.signer, ta.nonce, ta.nonce+maxTXsPerAcct-1, time.Now())
@@ -568,9 +569,12 @@ func TestCache_Account_Add_TooManyNonce_OK(t *testing.T) {
 		Transaction: *newTx(t, ta.nonce+maxTXsPerAcct, defaultAmount, defaultFee, ta.signer),
 		Received:    time.Now(),
 	}
-	require.NoError(t, tc.Add(context.Background(), tc.db, &oneTooMany.Transaction, oneTooMany.Received, false))
+	require.ErrorIs(t, tc.Add(context.Background(), tc.db, &oneTooMany.Transaction, oneTooMany.Received, false), errTooManyNonce)
 	require.True(t, tc.MoreInDB(ta.principal))
 	checkNoTX(t, tc.Cache, oneTooMany.ID)
+	reason, ok := tc.Rejected(oneTooMany.ID)
+	require.True(t, ok)
+	require.Contains(t, reason, errTooManyNonce.Error())
 	checkTXStateFromDB(t, tc.db, append(mtxs, oneTooMany), types.MEMPOOL)
 
 	checkProjection(t, tc.Cache, ta.principal, newNextNonce, newBalance)
@@ -626,6 +630,55 @@ func TestCache_Account_Add_SuperiorReplacesInferior_EvictLaterNonce(t *testing.T
 	checkTXStateFromDB(t, tc.db, append(mtxs, better), types.MEMPOOL)
 }
 
+func TestCache_Account_Add_ReplaceByFee(t *testing.T) {
+	events.InitializeReporter()
+	t.Cleanup(events.CloseEventReporter)
+	tc, ta := createSingleAccountTestCache(t)
+	tc.feeBump = 10
+	pending := &types.MeshTransaction{
+		Transaction: *newTx(t, ta.nonce, defaultAmount, 100, ta.signer),
+		Received:    time.Now(),
+	}
+	require.NoError(t, transactions.Add(tc.db, &pending.Transaction, pending.Received))
+	buildSingleAccountCache(t, tc, ta, []*types.MeshTransaction{pending})
+
+	// the fee is higher, but not by the required bump
+	underpriced := &types.MeshTransaction{
+		Transaction: *newTx(t, ta.nonce, defaultAmount, 109, ta.signer),
+		Received:    time.Now(),
+	}
+	err := tc.Add(context.Background(), tc.db, &underpriced.Transaction, underpriced.Received, false)
+	require.ErrorIs(t, err, errReplacementUnderpriced)
+	checkTX(t, tc.Cache, pending.ID, 0, types.EmptyBlockID)
+	checkNoTX(t, tc.Cache, underpriced.ID)
+	_, ok := tc.Rejected(underpriced.ID)
+	require.True(t, ok)
+	checkTXStateFromDB(t, tc.db, []*types.MeshTransaction{underpriced}, types.MEMPOOL)
+
+	replacement := &types.MeshTransaction{
+		Transaction: *newTx(t, ta.nonce, defaultAmount, 110, ta.signer),
+		Received:    time.Now(),
+	}
+	require.NoError(t, tc.Add(context.Background(), tc.db, &replacement.Transaction, replacement.Received, false))
+	checkTX(t, tc.Cache, replacement.ID, 0, types.EmptyBlockID)
+	checkNoTX(t, tc.Cache, pending.ID)
+	checkProjection(t, tc.Cache, ta.principal, ta.nonce+1, ta.balance-replacement.Spending())
+	reason, ok := tc.Rejected(pending.ID)
+	require.True(t, ok)
+	require.Contains(t, reason, replacement.ID.String())
+
+	sub, replay, err := events.SubscribeStream(0, []events.StreamKind{events.StreamTxReplaced})
+	require.NoError(t, err)
+	defer sub.Close()
+	require.Len(t, replay, 1)
+	require.Equal(t, events.StreamTxReplacedData{
+		Principal: ta.principal.String(),
+		Nonce:     ta.nonce,
+		Replaced:  pending.ID.String(),
+		By:        replacement.ID.String(),
+	}, replay[0].Data)
+}
+
 func TestCache_Account_Add_UpdateHeader(t *testing.T) {
 	tc, ta := createSingleAccountTestCache(t)
 	buildSingleAccountCache(t, tc, ta, nil)
@@ -3,7 +3,6 @@ package txs
 import (
 	"context"
 	"fmt"
-	"math"
 	"math/rand"
 	"time"
 
@@ -16,16 +15,37 @@ import (
 	"github.com/spacemeshos/go-spacemesh/system"
 )
 
+// proposalCandidates is the ratio of the txs with the highest fee per unit of gas that are sampled
+// for the proposal to the number of txs in the proposal.
+const proposalCandidates = 2
+
 // CSConfig is the config for the conservative state/cache.
 type CSConfig struct {
-	BlockGasLimit     uint64
 	NumTXsPerProposal int
+	// LayerSize is the expected number of proposals in a layer, used to estimate how many
+	// transactions fit into a layer.
+	LayerSize int
+	// ReplaceFeeBump is the min fee increase in percentage for the transaction to replace
+	// the pending transaction with the same principal and nonce.
+	ReplaceFeeBump uint64
+	// MempoolMaxTXs is the max number of transactions in the mempool, zero means no limit.
+	MempoolMaxTXs int
+	// MempoolMaxAge is the max time the transaction stays in the mempool, zero means no limit.
+	MempoolMaxAge time.Duration
+	// MempoolMaxBytes is the max total size of the transactions in the mempool, zero means no limit.
+	MempoolMaxBytes int
+	// MempoolMaxAcctTXs is the max number of transactions of a single principal in the mempool.
+	MempoolMaxAcctTXs int
+	// MempoolMaxOrphans is the max number of transactions held until the gap before their nonces is filled.
+	MempoolMaxOrphans int
 }
 
 func defaultCSConfig() CSConfig {
 	return CSConfig{
-		BlockGasLimit:     math.MaxUint64,
 		NumTXsPerProposal: 100,
+		ReplaceFeeBump:    10,
+		MempoolMaxAcctTXs: maxTXsPerAcct,
+		MempoolMaxOrphans: maxOrphans,
 	}
 }
 
@@ -55,6 +75,7 @@ type ConservativeState struct {
 	cfg    CSConfig
 	db     *sql.Database
 	cache  *Cache
+	fees   feeHistory
 }
 
 // NewConservativeState returns a ConservativeState.
@@ -69,6 +90,17 @@ func NewConservativeState(state vmState, db *sql.Database, opts ...ConservativeS
 		opt(cs)
 	}
 	cs.cache = NewCache(cs.getState, cs.logger)
+	cs.cache.feeBump = cs.cfg.ReplaceFeeBump
+	cs.cache.maxTXs = cs.cfg.MempoolMaxTXs
+	cs.cache.maxAge = cs.cfg.MempoolMaxAge
+	cs.cache.maxBytes = cs.cfg.MempoolMaxBytes
+	if cs.cfg.MempoolMaxAcctTXs > 0 {
+		cs.cache.maxAccountTXs = cs.cfg.MempoolMaxAcctTXs
+	}
+	cs.cache.orphans = newOrphanPool(maxOrphans)
+	if cs.cfg.MempoolMaxOrphans > 0 {
+		cs.cache.orphans.max = cs.cfg.MempoolMaxOrphans
+	}
 	return cs
 }
 
@@ -85,25 +117,58 @@ func (cs *ConservativeState) getState(addr types.Address) (uint64, uint64) {
 }
 
 // SelectProposalTXs picks a specific number of random txs for miner to pack in a proposal.
+// txs with the higher fee per unit of gas are more likely to be picked, the txs of the same
+// principal are picked in the nonce order.
 func (cs *ConservativeState) SelectProposalTXs(lid types.LayerID, numEligibility int) []types.TransactionID {
 	logger := cs.logger.WithFields(lid)
-	mi := newMempoolIterator(logger, cs.cache, cs.cfg.BlockGasLimit)
-	predictedBlock, byAddrAndNonce := mi.PopAll()
+	mi := newMempoolIterator(logger, cs.cache, cs.vmState.GasSchedule(lid).GasLimit)
+	predictedBlock, _ := mi.PopAll()
 	numTXs := numEligibility * cs.cfg.NumTXsPerProposal
-	return getProposalTXs(logger.WithFields(lid), numTXs, predictedBlock, byAddrAndNonce)
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	return getProposalTXs(logger, rng, numTXs, predictedBlock)
 }
 
-func getProposalTXs(logger log.Log, numTXs int, predictedBlock []*NanoTX, byAddrAndNonce map[types.Address][]*NanoTX) []types.TransactionID {
-	if len(predictedBlock) <= numTXs {
-		result := make([]types.TransactionID, 0, len(predictedBlock))
-		for _, ntx := range predictedBlock {
-			result = append(result, ntx.ID)
+// getProposalTXs samples up to numTXs txs from the numTXs * proposalCandidates first txs of the predicted
+// block, which is ordered by the fee per unit of gas and by nonce for the same principal. the probability
+// of the tx to be sampled is proportional to its fee per unit of gas, so that proposals of different
+// miners don't pack the same txs.
+func getProposalTXs(logger log.Log, rng *rand.Rand, numTXs int, predictedBlock []*NanoTX) []types.TransactionID {
+	candidates := predictedBlock
+	if len(candidates) > numTXs*proposalCandidates {
+		candidates = candidates[:numTXs*proposalCandidates]
+	}
+	// no sampling if the mempool is exhausted
+	selected := candidates
+	if len(candidates) > numTXs {
+		// the candidates of the same principal are a prefix of its txs in the nonce order
+		byAddrAndNonce := make(map[types.Address][]*NanoTX)
+		for _, ntx := range candidates {
+			byAddrAndNonce[ntx.Principal] = append(byAddrAndNonce[ntx.Principal], ntx)
 		}
-		return result
+		shuffled := make([]*NanoTX, len(candidates))
+		copy(shuffled, candidates)
+		weightedShuffle(rng, shuffled)
+		selected = withNonceOrder(logger, numTXs, shuffled, byAddrAndNonce)
 	}
-	// randomly select transactions from the predicted block.
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
-	return ShuffleWithNonceOrder(logger, rng, numTXs, predictedBlock, byAddrAndNonce)
+
+	var gas, minPrice uint64
+	result := make([]types.TransactionID, 0, len(selected))
+	for i, ntx := range selected {
+		result = append(result, ntx.ID)
+		gas += ntx.MaxGas
+		if i == 0 || ntx.GasPrice < minPrice {
+			minPrice = ntx.GasPrice
+		}
+	}
+	selectionCandidates.Set(float64(len(candidates)))
+	selectionSelected.Set(float64(len(selected)))
+	selectionGas.Set(float64(gas))
+	selectionMinGasPrice.Set(float64(minPrice))
+	logger.With().Debug("selected txs for proposal",
+		log.Int("candidates", len(candidates)),
+		log.Int("selected", len(selected)),
+		log.Uint64("gas", gas))
+	return result
 }
 
 // Validation initializes validation request.
@@ -123,7 +188,20 @@ func (cs *ConservativeState) AddToCache(ctx context.Context, tx *types.Transacti
 
 // RevertCache reverts the conservative cache to the given layer.
 func (cs *ConservativeState) RevertCache(revertTo types.LayerID) error {
-	return cs.cache.RevertToLayer(cs.db, revertTo)
+	applied, err := layers.GetLastApplied(cs.db)
+	if err != nil {
+		return fmt.Errorf("get last applied: %w", err)
+	}
+	if err := cs.cache.RevertToLayer(cs.db, revertTo); err != nil {
+		return err
+	}
+	if err := cs.fees.load(cs.db, revertTo); err != nil {
+		return err
+	}
+	if cs.vmState.GasSchedule(applied.Add(1)).CostFactor != cs.vmState.GasSchedule(revertTo.Add(1)).CostFactor {
+		return cs.reschedule(revertTo.Add(1))
+	}
+	return nil
 }
 
 func (cs *ConservativeState) UpdateCache(
@@ -138,6 +216,55 @@ func (cs *ConservativeState) UpdateCache(
 		return err
 	}
 	cacheApplyDuration.Observe(float64(time.Since(t0)))
+	cs.fees.add(lid, results)
+	if cs.vmState.GasSchedule(lid).CostFactor != cs.vmState.GasSchedule(lid.Add(1)).CostFactor {
+		return cs.reschedule(lid.Add(1))
+	}
+	return nil
+}
+
+// reschedule recomputes the max gas of the pending transactions for the gas costs of the layer.
+// the headers of the transactions are parsed with the costs of the layer they were received in,
+// and the projected balances of the principals depend on them. the headers are updated in the
+// database and the cache is rebuilt from it.
+func (cs *ConservativeState) reschedule(lid types.LayerID) error {
+	addresses, err := transactions.AddressesWithPendingTransactions(cs.db)
+	if err != nil {
+		return fmt.Errorf("pending transactions: %w", err)
+	}
+	maxGas := map[types.TransactionID]uint64{}
+	if err := cs.db.WithTx(context.Background(), func(dbtx *sql.Tx) error {
+		for _, addr := range addresses {
+			mtxs, err := transactions.GetAcctPendingFromNonce(dbtx, addr.Address, addr.Nonce)
+			if err != nil {
+				return fmt.Errorf("get pending addr=%s nonce=%d: %w", addr.Address, addr.Nonce, err)
+			}
+			for _, mtx := range mtxs {
+				header, err := cs.vmState.ValidationAt(lid, mtx.RawTx).Parse()
+				if err != nil {
+					cs.logger.With().Warning("failed to reparse pending tx", mtx.ID, log.Err(err))
+					continue
+				}
+				if header.MaxGas == mtx.MaxGas {
+					continue
+				}
+				if err := transactions.SetHeader(dbtx, mtx.ID, header); err != nil {
+					return err
+				}
+				maxGas[mtx.ID] = header.MaxGas
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	cs.cache.rescheduleOrphans(maxGas)
+	if err := cs.cache.buildFromScratch(cs.db); err != nil {
+		return fmt.Errorf("rebuild cache for the gas schedule at %s: %w", lid, err)
+	}
+	cs.logger.With().Info("recomputed max gas of pending txs for the new gas schedule",
+		lid,
+		log.Int("updated", len(maxGas)))
 	return nil
 }
 
@@ -207,5 +334,29 @@ func (cs *ConservativeState) GetMeshTransactions(ids []types.TransactionID) ([]*
 // GetTransactionsByAddress retrieves txs for a single address in between layers [from, to].
 // Guarantees that transaction will appear exactly once, even if origin and recipient is the same, and in insertion order.
 func (cs *ConservativeState) GetTransactionsByAddress(from, to types.LayerID, address types.Address) ([]*types.MeshTransaction, error) {
-	return transactions.GetByAddress(cs.db, from, to, address)
+	txs, _, err := cs.ListTransactionsByAddress(from, to, address, 0, -1)
+	return txs, err
+}
+
+// ListTransactionsByAddress retrieves a page of txs for a single address in between layers [from, to]
+// and the total number of such txs. Pending txs are included after applied txs. Limit -1 means no limit.
+func (cs *ConservativeState) ListTransactionsByAddress(from, to types.LayerID, address types.Address, offset, limit int) ([]*types.MeshTransaction, int, error) {
+	filter := transactions.AddressFilter{
+		Address: address,
+		Start:   &from,
+		End:     &to,
+		Pending: true,
+	}
+	total, err := transactions.CountByAddress(cs.db, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	if offset >= total {
+		return nil, total, nil
+	}
+	txs, err := transactions.ListByAddress(cs.db, filter, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return txs, total, nil
 }
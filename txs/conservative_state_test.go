@@ -16,6 +16,7 @@ import (
 	"golang.org/x/exp/maps"
 
 	"github.com/spacemeshos/go-spacemesh/common/types"
+	vm "github.com/spacemeshos/go-spacemesh/genvm"
 	"github.com/spacemeshos/go-spacemesh/genvm/sdk"
 	"github.com/spacemeshos/go-spacemesh/genvm/sdk/wallet"
 	"github.com/spacemeshos/go-spacemesh/log"
@@ -69,6 +70,8 @@ type testConState struct {
 	logger log.Log
 	db     *sql.Database
 	mvm    *MockvmState
+	// schedules are the gas schedules of the vm, the base costs apply before the first one.
+	schedules []vm.GasSchedule
 
 	id peer.ID
 }
@@ -82,7 +85,6 @@ func createTestState(t *testing.T, gasLimit uint64) *testConState {
 	mvm := NewMockvmState(ctrl)
 	db := sql.InMemory()
 	cfg := CSConfig{
-		BlockGasLimit:     gasLimit,
 		NumTXsPerProposal: numTXsInProposal,
 	}
 	logger := logtest.New(t)
@@ -91,7 +93,7 @@ func createTestState(t *testing.T, gasLimit uint64) *testConState {
 	id, err := peer.IDFromPublicKey(pub)
 	require.NoError(t, err)
 
-	return &testConState{
+	tcs := &testConState{
 		ConservativeState: NewConservativeState(mvm, db,
 			WithCSConfig(cfg),
 			WithLogger(logger),
@@ -101,6 +103,16 @@ func createTestState(t *testing.T, gasLimit uint64) *testConState {
 		mvm:    mvm,
 		id:     id,
 	}
+	mvm.EXPECT().GasSchedule(gomock.Any()).DoAndReturn(func(lid types.LayerID) vm.GasSchedule {
+		active := vm.GasSchedule{CostFactor: 100, GasLimit: gasLimit}
+		for _, schedule := range tcs.schedules {
+			if !schedule.Layer.After(lid) {
+				active = schedule
+			}
+		}
+		return active
+	}).AnyTimes()
+	return tcs
 }
 
 func createConservativeState(t *testing.T) *testConState {
@@ -117,7 +129,7 @@ func addBatch(tb testing.TB, tcs *testConState, numTXs int) ([]types.Transaction
 		addr := types.GenerateAddress(signer.PublicKey().Bytes())
 		tcs.mvm.EXPECT().GetBalance(addr).Return(defaultBalance, nil).Times(1)
 		tcs.mvm.EXPECT().GetNonce(addr).Return(nonce, nil).Times(1)
-		tx := newTx(tb, nonce+5, defaultAmount, defaultFee, signer)
+		tx := newTx(tb, nonce, defaultAmount, defaultFee, signer)
 		require.NoError(tb, tcs.AddToCache(context.Background(), tx, time.Now()))
 		ids = append(ids, tx.ID)
 		txs = append(txs, tx)
@@ -127,25 +139,33 @@ func addBatch(tb testing.TB, tcs *testConState, numTXs int) ([]types.Transaction
 
 func TestSelectProposalTXs(t *testing.T) {
 	tcs := createConservativeState(t)
-	numTXs := 2 * numTXsInProposal
+	numTXs := 3 * numTXsInProposal
 	lid := types.LayerID(97)
 	bid := types.BlockID{100}
+	candidates := make(map[types.TransactionID]struct{}, proposalCandidates*numTXsInProposal)
 	for i := 0; i < numTXs; i++ {
 		signer, err := signing.NewEdSigner()
 		require.NoError(t, err)
 		addr := types.GenerateAddress(signer.PublicKey().Bytes())
 		tcs.mvm.EXPECT().GetBalance(addr).Return(defaultBalance, nil).Times(1)
 		tcs.mvm.EXPECT().GetNonce(addr).Return(uint64(1), nil).Times(1)
-		tx1 := newTx(t, 4, defaultAmount, defaultFee, signer)
+		tx1 := newTx(t, 1, defaultAmount, defaultFee, signer)
 		require.NoError(t, tcs.AddToCache(context.Background(), tx1, time.Now()))
 		// all the TXs with nonce 1 are pending in database
 		require.NoError(t, tcs.LinkTXsWithBlock(lid, bid, []types.TransactionID{tx1.ID}))
-		tx2 := newTx(t, 6, defaultAmount, defaultFee, signer)
+		tx2 := newTx(t, 2, defaultAmount, defaultFee+uint64(i), signer)
 		require.NoError(t, tcs.AddToCache(context.Background(), tx2, time.Now()))
+		if i >= numTXs-proposalCandidates*numTXsInProposal {
+			candidates[tx2.ID] = struct{}{}
+		}
 	}
 
+	// the transactions are sampled from the ones with the highest gas price
 	got := tcs.SelectProposalTXs(lid, 1)
 	require.Len(t, got, numTXsInProposal)
+	for _, id := range got {
+		require.Contains(t, candidates, id)
+	}
 
 	// a second call should have different result than the first, as the transactions should be random
 	// since this depends on a seed of `time.Now()` it will only be eventually different on windows
@@ -167,6 +187,23 @@ func TestSelectProposalTXs(t *testing.T) {
 	}, 100*time.Millisecond, 20*time.Millisecond)
 }
 
+func TestGetProposalTXs_WeightedByGasPrice(t *testing.T) {
+	now := time.Now()
+	dense := makeNanoTX(types.Address{1}, 9, now)
+	cheap := makeNanoTX(types.Address{2}, 1, now)
+	rng := rand.New(rand.NewSource(1))
+	picked := map[types.TransactionID]int{}
+	const rounds = 1000
+	for i := 0; i < rounds; i++ {
+		got := getProposalTXs(logtest.New(t), rng, 1, []*NanoTX{dense, cheap})
+		require.Len(t, got, 1)
+		picked[got[0]]++
+	}
+	// the expected ratio is 9 to 1
+	require.Greater(t, picked[dense.ID], 8*rounds/10)
+	require.Greater(t, picked[cheap.ID], 0)
+}
+
 func TestSelectProposalTXs_ExhaustGas(t *testing.T) {
 	numTXs := 2 * numTXsInProposal
 	lid := types.LayerID(97)
@@ -224,8 +261,8 @@ func TestSelectProposalTXs_ExhaustMemPool(t *testing.T) {
 		first := tcs.cache.cachedTXs[expected[i]]
 		second := tcs.cache.cachedTXs[expected[j]]
 
-		if first.Fee() != second.Fee() {
-			return first.Fee() > second.Fee()
+		if first.GasPrice != second.GasPrice {
+			return first.GasPrice > second.GasPrice
 		}
 		if !first.Received.Equal(second.Received) {
 			return first.Received.Before(second.Received)
@@ -388,8 +425,9 @@ func TestAddToCache_NonceGap(t *testing.T) {
 	tcs.mvm.EXPECT().GetBalance(tx.Principal).Return(defaultBalance, nil).Times(1)
 	tcs.mvm.EXPECT().GetNonce(tx.Principal).Return(tx.Nonce-2, nil).Times(1)
 	require.NoError(t, tcs.AddToCache(context.Background(), tx, time.Now()))
-	require.True(t, tcs.cache.Has(tx.ID))
-	require.False(t, tcs.cache.MoreInDB(tx.Principal))
+	require.False(t, tcs.cache.Has(tx.ID))
+	require.True(t, tcs.cache.MoreInDB(tx.Principal))
+	require.Equal(t, 1, tcs.MempoolStats().Orphans)
 	checkTXStateFromDB(t, tcs.db, []*types.MeshTransaction{{Transaction: *tx}}, types.MEMPOOL)
 }
 
@@ -415,11 +453,15 @@ func TestAddToCache_TooManyForOneAccount(t *testing.T) {
 	tcs.mvm.EXPECT().GetBalance(addr).Return(uint64(math.MaxUint64), nil).Times(1)
 	tcs.mvm.EXPECT().GetNonce(addr).Return(nonce, nil).Times(1)
 	mtxs := make([]*types.MeshTransaction, 0, maxTXsPerAcct+1)
-	for i := 0; i <= maxTXsPerAcct; i++ {
+	for i := 0; i < maxTXsPerAcct; i++ {
 		tx := newTx(t, nonce+uint64(i), defaultAmount, defaultFee, signer)
 		mtxs = append(mtxs, &types.MeshTransaction{Transaction: *tx})
 		require.NoError(t, tcs.AddToCache(context.Background(), tx, time.Now()))
 	}
+	// the tx above the limit is rejected, but persisted
+	tx := newTx(t, nonce+maxTXsPerAcct, defaultAmount, defaultFee, signer)
+	mtxs = append(mtxs, &types.MeshTransaction{Transaction: *tx})
+	require.ErrorIs(t, tcs.AddToCache(context.Background(), tx, time.Now()), errTooManyNonce)
 	require.True(t, tcs.cache.MoreInDB(addr))
 	checkTXStateFromDB(t, tcs.db, mtxs, types.MEMPOOL)
 }
@@ -538,6 +580,33 @@ func TestUpdateCache_EmptyLayer(t *testing.T) {
 	require.Len(t, mempoolTxs, len(ids))
 }
 
+func TestUpdateCache_GasSchedule(t *testing.T) {
+	tcs := createConservativeState(t)
+	lid := types.LayerID(1)
+	tcs.schedules = []vm.GasSchedule{{Layer: lid.Add(1), CostFactor: 200, GasLimit: 10 * defaultGas}}
+	ids, txs := addBatch(t, tcs, numTXs)
+	for _, tx := range txs {
+		header := *tx.TxHeader
+		header.MaxGas = 2 * defaultGas
+		req := smocks.NewMockValidationRequest(gomock.NewController(t))
+		req.EXPECT().Parse().Return(&header, nil)
+		tcs.mvm.EXPECT().ValidationAt(lid.Add(1), tx.RawTx).Return(req)
+	}
+	tcs.mvm.EXPECT().GetBalance(gomock.Any()).Return(defaultBalance, nil).AnyTimes()
+	tcs.mvm.EXPECT().GetNonce(gomock.Any()).Return(nonce, nil).AnyTimes()
+	require.Len(t, tcs.SelectProposalTXs(lid, 2), numTXs)
+
+	require.NoError(t, tcs.UpdateCache(context.Background(), lid, types.EmptyBlockID, nil, nil))
+	for _, id := range ids {
+		mtx, err := transactions.Get(tcs.db, id)
+		require.NoError(t, err)
+		require.Equal(t, 2*defaultGas, mtx.MaxGas)
+		require.Equal(t, 2*defaultGas, tcs.cache.Get(id).MaxGas)
+	}
+	// the gas limit of the new schedule fits 5 txs with the recomputed max gas
+	require.Len(t, tcs.SelectProposalTXs(lid.Add(1), 2), 5)
+}
+
 func TestConsistentHandling(t *testing.T) {
 	// there are two different workflows for transactions
 	// 1. receive gossiped transaction and verify it immediately
@@ -0,0 +1,143 @@
+package txs
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spacemeshos/go-spacemesh/log"
+)
+
+const (
+	// labels for the reasons of evictions from the mempool.
+	evictedFull    = "full"
+	evictedExpired = "expired"
+)
+
+var (
+	errMempoolFull = errors.New("mempool is full")
+	errTxExpired   = errors.New("transaction expired in mempool")
+)
+
+// cheaper returns true if the transaction should be evicted from the full mempool before the other one.
+// the transaction with the lower fee per unit of gas goes first, the older one for the same fee.
+func cheaper(ntx, other *NanoTX) bool {
+	if ntx.GasPrice != other.GasPrice {
+		return ntx.GasPrice < other.GasPrice
+	}
+	return ntx.Received.Before(other.Received)
+}
+
+// expired returns true if the transaction is in the mempool longer than the max age.
+// the transactions packed in proposals or blocks never expire.
+func (ac *accountCache) expired(ntx *NanoTX, now time.Time) bool {
+	return ac.maxAge > 0 && ntx.Layer == 0 && now.Sub(ntx.Received) > ac.maxAge
+}
+
+// evictBack removes the transaction with the highest nonce from the account cache.
+// it stays in the database and is reconsidered for the mempool after the next layer is applied.
+func (ac *accountCache) evictBack() *NanoTX {
+	removed := ac.txsByNonce.Remove(ac.txsByNonce.Back()).(*candidate)
+	ac.uncache(removed.id())
+	ac.moreInDB = true
+	return removed.best
+}
+
+// evictExpired removes the transactions starting from the first expired nonce from the account cache,
+// as the later transactions can't be applied without it. the expired transactions are returned
+// separately, the rest stay in the database and are reconsidered for the mempool after the next
+// layer is applied. the post balances of the remaining transactions don't depend on the removed ones.
+func (ac *accountCache) evictExpired(now time.Time) (expired, evicted []*NanoTX) {
+	e := ac.txsByNonce.Front()
+	for e != nil && !ac.expired(e.Value.(*candidate).best, now) {
+		e = e.Next()
+	}
+	for e != nil {
+		next := e.Next()
+		cand := ac.txsByNonce.Remove(e).(*candidate)
+		ac.uncache(cand.id())
+		if ac.expired(cand.best, now) {
+			expired = append(expired, cand.best)
+		} else {
+			evicted = append(evicted, cand.best)
+			ac.moreInDB = true
+		}
+		e = next
+	}
+	return expired, evicted
+}
+
+// cheapest returns the account with the cheapest transaction at the highest nonce, among
+// the transactions that are not packed in proposals or blocks.
+func (c *Cache) cheapest() *accountCache {
+	var (
+		acct  *accountCache
+		worst *NanoTX
+	)
+	for _, ac := range c.pending {
+		back := ac.txsByNonce.Back()
+		if back == nil {
+			continue
+		}
+		if cand := back.Value.(*candidate); cand.layer() == 0 && (worst == nil || cheaper(cand.best, worst)) {
+			acct, worst = ac, cand.best
+		}
+	}
+	return acct
+}
+
+// overflow returns true if the mempool exceeds either the count or the byte limit.
+func (c *Cache) overflow() bool {
+	return (c.maxTXs > 0 && len(c.cachedTXs) > c.maxTXs) || (c.maxBytes > 0 && c.cachedSize > c.maxBytes)
+}
+
+// evictOverflow evicts the cheapest transactions until the mempool fits the limits.
+// the evicted transactions are returned.
+func (c *Cache) evictOverflow(logger log.Log) []*NanoTX {
+	var evicted []*NanoTX
+	for c.overflow() {
+		acct := c.cheapest()
+		if acct == nil {
+			// everything left is packed in proposals or blocks
+			break
+		}
+		ntx := acct.evictBack()
+		logger.With().Debug("evicted tx from full mempool",
+			ntx.ID,
+			ntx.Principal,
+			log.Uint64("nonce", ntx.Nonce),
+			log.Uint64("gas_price", ntx.GasPrice))
+		c.reject(ntx.ID, fmt.Errorf("%w: gas price %d", errMempoolFull, ntx.GasPrice))
+		mempoolEvictions.WithLabelValues(evictedFull).Inc()
+		evicted = append(evicted, ntx)
+	}
+	return evicted
+}
+
+// evictExpired evicts the transactions that are in the mempool longer than the max age.
+func (c *Cache) evictExpired(logger log.Log, now time.Time) {
+	if c.maxAge <= 0 {
+		return
+	}
+	for addr, acct := range c.pending {
+		expired, evicted := acct.evictExpired(now)
+		for _, ntx := range expired {
+			logger.With().Debug("evicted expired tx from mempool",
+				ntx.ID,
+				ntx.Principal,
+				log.Uint64("nonce", ntx.Nonce),
+				log.Time("received", ntx.Received))
+			c.reject(ntx.ID, errTxExpired)
+			mempoolEvictions.WithLabelValues(evictedExpired).Inc()
+		}
+		for _, ntx := range evicted {
+			logger.With().Debug("evicted tx after the expired nonce from mempool",
+				ntx.ID,
+				ntx.Principal,
+				log.Uint64("nonce", ntx.Nonce))
+		}
+		if acct.shouldEvict() {
+			delete(c.pending, addr)
+		}
+	}
+}
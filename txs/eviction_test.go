@@ -0,0 +1,133 @@
+package txs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/sql/transactions"
+)
+
+func TestCache_EvictCheapestWhenFull(t *testing.T) {
+	tc, accounts := createCache(t, 3)
+	tc.maxTXs = 2
+	var accts []*testAcct
+	for _, ta := range accounts {
+		accts = append(accts, ta)
+	}
+	now := time.Now()
+	mtxs := make([]*types.MeshTransaction, 0, len(accts))
+	for i, price := range []uint64{5, 3, 4} {
+		mtx := &types.MeshTransaction{
+			Transaction: *newTx(t, accts[i].nonce, defaultAmount, price, accts[i].signer),
+			Received:    now,
+		}
+		require.NoError(t, tc.Add(context.Background(), tc.db, &mtx.Transaction, mtx.Received, false))
+		mtxs = append(mtxs, mtx)
+	}
+	checkTX(t, tc.Cache, mtxs[0].ID, 0, types.EmptyBlockID)
+	checkTX(t, tc.Cache, mtxs[2].ID, 0, types.EmptyBlockID)
+	checkNoTX(t, tc.Cache, mtxs[1].ID)
+	reason, ok := tc.Rejected(mtxs[1].ID)
+	require.True(t, ok)
+	require.Contains(t, reason, errMempoolFull.Error())
+	require.True(t, tc.MoreInDB(accts[1].principal))
+	checkTXStateFromDB(t, tc.db, mtxs, types.MEMPOOL)
+
+	// the new transaction is the cheapest one
+	cheap := newTx(t, accts[0].nonce+1, defaultAmount, 1, accts[0].signer)
+	require.ErrorIs(t, tc.Add(context.Background(), tc.db, cheap, now, false), errMempoolFull)
+	checkNoTX(t, tc.Cache, cheap.ID)
+	got, err := transactions.Get(tc.db, cheap.ID)
+	require.NoError(t, err)
+	require.Equal(t, cheap.ID, got.ID)
+	checkMempoolSize(t, tc.Cache, 2)
+}
+
+func TestCache_EvictWhenOverMaxBytes(t *testing.T) {
+	tc, accounts := createCache(t, 2)
+	var accts []*testAcct
+	for _, ta := range accounts {
+		accts = append(accts, ta)
+	}
+	now := time.Now()
+	expensive := newTx(t, accts[0].nonce, defaultAmount, 5, accts[0].signer)
+	cheap := newTx(t, accts[1].nonce, defaultAmount, 3, accts[1].signer)
+	tc.maxBytes = len(expensive.Raw) + len(cheap.Raw) - 1
+
+	require.NoError(t, tc.Add(context.Background(), tc.db, expensive, now, false))
+	require.Equal(t, len(expensive.Raw), tc.cachedSize)
+	require.ErrorIs(t, tc.Add(context.Background(), tc.db, cheap, now, false), errMempoolFull)
+	checkTX(t, tc.Cache, expensive.ID, 0, types.EmptyBlockID)
+	checkNoTX(t, tc.Cache, cheap.ID)
+	require.Equal(t, len(expensive.Raw), tc.cachedSize)
+}
+
+func TestCache_MaxAccountTXs(t *testing.T) {
+	tc, ta := createSingleAccountTestCache(t)
+	tc.maxAccountTXs = 2
+	now := time.Now()
+	for i := uint64(0); i < 2; i++ {
+		tx := newTx(t, ta.nonce+i, defaultAmount, defaultFee, ta.signer)
+		require.NoError(t, tc.Add(context.Background(), tc.db, tx, now, false))
+	}
+	tx := newTx(t, ta.nonce+2, defaultAmount, defaultFee, ta.signer)
+	require.ErrorIs(t, tc.Add(context.Background(), tc.db, tx, now, false), errTooManyNonce)
+	checkNoTX(t, tc.Cache, tx.ID)
+	checkMempoolSize(t, tc.Cache, 2)
+	require.True(t, tc.MoreInDB(ta.principal))
+	_, err := transactions.Get(tc.db, tx.ID)
+	require.NoError(t, err)
+}
+
+func TestCache_EvictExpired(t *testing.T) {
+	tc, ta := createSingleAccountTestCache(t)
+	tc.maxAge = time.Hour
+	now := time.Now()
+	first := newMeshTX(t, ta.nonce, ta.signer, defaultAmount, now)
+	old := newMeshTX(t, ta.nonce+1, ta.signer, defaultAmount, now.Add(-2*time.Hour))
+	fresh := newMeshTX(t, ta.nonce+2, ta.signer, defaultAmount, now)
+	for _, mtx := range []*types.MeshTransaction{first, old, fresh} {
+		require.NoError(t, tc.Add(context.Background(), tc.db, &mtx.Transaction, mtx.Received, false))
+	}
+	checkTX(t, tc.Cache, old.ID, 0, types.EmptyBlockID)
+	balance := tc.pending[ta.principal].txsByNonce.Front().Value.(*candidate).postBalance
+
+	// the transactions after the expired nonce are evicted too
+	tc.evictExpired(tc.logger, now)
+	checkTX(t, tc.Cache, first.ID, 0, types.EmptyBlockID)
+	checkNoTX(t, tc.Cache, old.ID)
+	checkNoTX(t, tc.Cache, fresh.ID)
+	checkMempoolSize(t, tc.Cache, 1)
+	require.True(t, tc.MoreInDB(ta.principal))
+	require.Equal(t, ta.nonce+1, tc.pending[ta.principal].nextNonce())
+	require.Equal(t, balance, tc.pending[ta.principal].availBalance())
+	reason, ok := tc.Rejected(old.ID)
+	require.True(t, ok)
+	require.Equal(t, errTxExpired.Error(), reason)
+	_, ok = tc.Rejected(fresh.ID)
+	require.False(t, ok)
+
+	// the expired transaction is not reloaded from the database
+	require.NoError(t, tc.BuildFromTXs([]*types.MeshTransaction{first, old}, nil))
+	checkTX(t, tc.Cache, first.ID, 0, types.EmptyBlockID)
+	checkNoTX(t, tc.Cache, old.ID)
+}
+
+func TestCache_PackedTXsNotEvicted(t *testing.T) {
+	tc, ta := createSingleAccountTestCache(t)
+	tc.maxTXs = 1
+	tc.maxAge = time.Hour
+	old := newMeshTX(t, ta.nonce, ta.signer, defaultAmount, time.Now().Add(-2*time.Hour))
+	old.LayerID = types.LayerID(10)
+	require.NoError(t, transactions.Add(tc.db, &old.Transaction, old.Received))
+	require.NoError(t, tc.BuildFromTXs([]*types.MeshTransaction{old}, nil))
+	checkTX(t, tc.Cache, old.ID, old.LayerID, types.EmptyBlockID)
+
+	tc.evictExpired(tc.logger, time.Now())
+	require.Empty(t, tc.evictOverflow(tc.logger))
+	checkTX(t, tc.Cache, old.ID, old.LayerID, types.EmptyBlockID)
+}
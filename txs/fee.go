@@ -0,0 +1,169 @@
+package txs
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/layers"
+	"github.com/spacemeshos/go-spacemesh/sql/transactions"
+)
+
+// feeHistoryLayers is the number of the most recently applied layers used for fee estimation.
+const feeHistoryLayers = 20
+
+// FeeEstimate is the recommended gas price for a transaction to be included within the target number of layers.
+type FeeEstimate struct {
+	GasPrice     uint64 `json:"gas_price"`
+	TargetLayers int    `json:"target_layers"`
+	// RecentGasPrice is the median gas price of the transactions included in the recent layers.
+	RecentGasPrice uint64 `json:"recent_gas_price"`
+	// SampleLayers is the number of recent layers the estimate is based on.
+	SampleLayers int `json:"sample_layers"`
+	// MempoolDepth is the number of transactions in the mempool that are not included in a block yet.
+	MempoolDepth int `json:"mempool_depth"`
+	// LayerCapacity is the estimated number of transactions included in a layer.
+	LayerCapacity int `json:"layer_capacity"`
+}
+
+type layerFees struct {
+	lid    types.LayerID
+	prices []uint64
+}
+
+// feeHistory keeps the gas prices of the transactions included in the recently applied layers.
+type feeHistory struct {
+	mu     sync.Mutex
+	layers []layerFees
+}
+
+func (h *feeHistory) add(lid types.LayerID, results []types.TransactionWithResult) {
+	prices := make([]uint64, 0, len(results))
+	for _, rst := range results {
+		if rst.TxHeader != nil {
+			prices = append(prices, rst.GasPrice)
+		}
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.layers = append(h.layers, layerFees{lid: lid, prices: prices})
+	if len(h.layers) > feeHistoryLayers {
+		h.layers = h.layers[len(h.layers)-feeHistoryLayers:]
+	}
+}
+
+// load replaces the history with the gas prices of the transactions applied in the layers up to last.
+// it is called when the state is reverted, including on startup, so that the estimate doesn't
+// depend on how long the node is running.
+func (h *feeHistory) load(db sql.Executor, last types.LayerID) error {
+	var loaded []layerFees
+	for lid := last; lid.After(types.GetEffectiveGenesis()) && len(loaded) < feeHistoryLayers; lid = lid.Sub(1) {
+		if _, err := layers.GetApplied(db, lid); errors.Is(err, sql.ErrNotFound) {
+			// not applied locally, e.g. before the checkpoint the node recovered from
+			break
+		} else if err != nil {
+			return fmt.Errorf("fee history: %w", err)
+		}
+		prices, err := transactions.GetAppliedGasPrices(db, lid)
+		if err != nil {
+			return fmt.Errorf("fee history: %w", err)
+		}
+		loaded = append(loaded, layerFees{lid: lid, prices: prices})
+	}
+	for i, j := 0, len(loaded)-1; i < j; i, j = i+1, j-1 {
+		loaded[i], loaded[j] = loaded[j], loaded[i]
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.layers = loaded
+	return nil
+}
+
+// stats returns the median gas price of the included transactions, the max number of transactions
+// included in a layer, and the number of layers.
+func (h *feeHistory) stats() (uint64, int, int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var (
+		all     []uint64
+		largest int
+	)
+	for _, layer := range h.layers {
+		all = append(all, layer.prices...)
+		if len(layer.prices) > largest {
+			largest = len(layer.prices)
+		}
+	}
+	if len(all) == 0 {
+		return 0, largest, len(h.layers)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+	return all[len(all)/2], largest, len(h.layers)
+}
+
+// waitingGasPrices returns the gas prices of the transactions in the cache that are not included in a block,
+// in descending order.
+func (c *Cache) waitingGasPrices() []uint64 {
+	prices := c.waiting()
+	// sorted outside the lock, to not block the cache on the api requests
+	sort.Slice(prices, func(i, j int) bool { return prices[i] > prices[j] })
+	return prices
+}
+
+func (c *Cache) waiting() []uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var prices []uint64
+	for _, acct := range c.pending {
+		for e := acct.txsByNonce.Front(); e != nil; e = e.Next() {
+			if cand := e.Value.(*candidate); cand.layer() == 0 {
+				prices = append(prices, cand.best.GasPrice)
+			}
+		}
+	}
+	return prices
+}
+
+// EstimateGasPrice returns the gas price for a transaction to be included within the target number of layers.
+// the transaction has to outbid the mempool transactions that don't fit into the target layers, and is not
+// recommended to pay less than the median gas price of the recently included transactions.
+func (cs *ConservativeState) EstimateGasPrice(targetLayers int) FeeEstimate {
+	if targetLayers < 1 {
+		targetLayers = 1
+	}
+	recent, largest, sample := cs.fees.stats()
+	capacity := cs.layerCapacity()
+	if largest > capacity {
+		capacity = largest
+	}
+	waiting := cs.cache.waitingGasPrices()
+	estimate := FeeEstimate{
+		GasPrice:       1,
+		TargetLayers:   targetLayers,
+		RecentGasPrice: recent,
+		SampleLayers:   sample,
+		MempoolDepth:   len(waiting),
+		LayerCapacity:  capacity,
+	}
+	if slots := targetLayers * capacity; slots > 0 && len(waiting) >= slots {
+		if price := waiting[slots-1] + 1; price > estimate.GasPrice {
+			estimate.GasPrice = price
+		}
+	}
+	if recent > estimate.GasPrice {
+		estimate.GasPrice = recent
+	}
+	return estimate
+}
+
+// layerCapacity is the expected max number of transactions included in a layer,
+// if every proposal in the layer packs distinct transactions.
+func (cs *ConservativeState) layerCapacity() int {
+	if cs.cfg.LayerSize <= 0 {
+		return cs.cfg.NumTXsPerProposal
+	}
+	return cs.cfg.LayerSize * cs.cfg.NumTXsPerProposal
+}
@@ -0,0 +1,99 @@
+package txs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/signing"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/layers"
+	"github.com/spacemeshos/go-spacemesh/sql/transactions"
+)
+
+func includedWithPrices(prices ...uint64) []types.TransactionWithResult {
+	rst := make([]types.TransactionWithResult, 0, len(prices))
+	for _, price := range prices {
+		rst = append(rst, types.TransactionWithResult{
+			Transaction: types.Transaction{TxHeader: &types.TxHeader{GasPrice: price}},
+		})
+	}
+	return rst
+}
+
+func TestEstimateGasPrice_Empty(t *testing.T) {
+	tcs := createConservativeState(t)
+	require.Equal(t, FeeEstimate{
+		GasPrice:      1,
+		TargetLayers:  1,
+		LayerCapacity: numTXsInProposal,
+	}, tcs.EstimateGasPrice(0))
+
+	// capacity accounts for all proposals in a layer
+	tcs.cfg.LayerSize = 3
+	require.Equal(t, 3*numTXsInProposal, tcs.EstimateGasPrice(1).LayerCapacity)
+}
+
+func TestEstimateGasPrice_Mempool(t *testing.T) {
+	tcs := createConservativeState(t)
+	addBatch(t, tcs, 2*numTXsInProposal)
+
+	// the mempool has more txs than fit into a layer
+	estimate := tcs.EstimateGasPrice(1)
+	require.Equal(t, defaultFee+1, estimate.GasPrice)
+	require.Equal(t, 2*numTXsInProposal, estimate.MempoolDepth)
+
+	// all txs fit into the target layers
+	require.Equal(t, uint64(1), tcs.EstimateGasPrice(3).GasPrice)
+}
+
+func TestEstimateGasPrice_Recent(t *testing.T) {
+	tcs := createConservativeState(t)
+	for i := 1; i <= feeHistoryLayers+1; i++ {
+		tcs.fees.add(types.LayerID(uint32(i)), includedWithPrices(5, 7, 9))
+	}
+	tcs.fees.add(types.LayerID(feeHistoryLayers+2), includedWithPrices(100, 100, 100, 100))
+	estimate := tcs.EstimateGasPrice(3)
+	require.Equal(t, uint64(7), estimate.GasPrice)
+	require.Equal(t, uint64(7), estimate.RecentGasPrice)
+	require.Equal(t, feeHistoryLayers, estimate.SampleLayers)
+	require.Equal(t, numTXsInProposal, estimate.LayerCapacity)
+}
+
+func TestEstimateGasPrice_Load(t *testing.T) {
+	tcs := createConservativeState(t)
+	signer, err := signing.NewEdSigner()
+	require.NoError(t, err)
+	start := types.GetEffectiveGenesis().Add(1)
+	last := start.Add(feeHistoryLayers)
+	for lid := start; !lid.After(last); lid = lid.Add(1) {
+		price := uint64(9)
+		if lid == start {
+			// out of the history window
+			price = 100
+		}
+		bid := types.RandomBlockID()
+		tx := newTx(t, uint64(lid), defaultAmount, price, signer)
+		require.NoError(t, transactions.Add(tcs.db, tx, time.Now()))
+		require.NoError(t, tcs.db.WithTx(context.Background(), func(dtx *sql.Tx) error {
+			return transactions.AddResult(dtx, tx.ID, &types.TransactionResult{Layer: lid, Block: bid})
+		}))
+		require.NoError(t, layers.SetApplied(tcs.db, lid, bid))
+	}
+
+	// history is loaded from the database after restart
+	require.Zero(t, tcs.EstimateGasPrice(1).SampleLayers)
+	require.NoError(t, tcs.RevertCache(last))
+	estimate := tcs.EstimateGasPrice(1)
+	require.Equal(t, feeHistoryLayers, estimate.SampleLayers)
+	require.Equal(t, uint64(9), estimate.RecentGasPrice)
+
+	// and reloaded up to the reverted layer
+	require.NoError(t, tcs.fees.load(tcs.db, start.Add(2)))
+	estimate = tcs.EstimateGasPrice(1)
+	require.Equal(t, 3, estimate.SampleLayers)
+	require.Equal(t, uint64(9), estimate.RecentGasPrice)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/libp2p/go-libp2p/core/peer"
@@ -47,6 +48,10 @@ func updateMetrics(err error, counter *prometheus.CounterVec) {
 		counter.WithLabelValues(duplicate).Inc()
 	case errors.Is(err, errBadNonce):
 		counter.WithLabelValues(rejectedBadNonce).Inc()
+	case errors.Is(err, errReplacementUnderpriced), errors.Is(err, errMempoolFull):
+		counter.WithLabelValues(underpriced).Inc()
+	case errors.Is(err, errTooManyNonce):
+		counter.WithLabelValues(tooManyNonce).Inc()
 	case errors.Is(err, errParse):
 		counter.WithLabelValues(cantParse).Inc()
 	case errors.Is(err, errVerify):
@@ -62,8 +67,13 @@ func (th *TxHandler) HandleGossipTransaction(ctx context.Context, peer p2p.Peer,
 		return nil
 	}
 
-	err := th.VerifyAndCacheTx(ctx, msg)
+	err := th.verifyAndCache(ctx, types.Hash32{}, msg, false)
 	updateMetrics(err, gossipTxCount)
+	// the transaction is valid and stored, it is loaded into the mempool once the earlier
+	// nonces of the principal are applied. it is still relayed, as peers may have room for it.
+	if errors.Is(err, errTooManyNonce) {
+		return nil
+	}
 	if err != nil {
 		th.logger.WithContext(ctx).With().Warning("failed to handle tx", log.Err(err))
 		return err
@@ -73,48 +83,105 @@ func (th *TxHandler) HandleGossipTransaction(ctx context.Context, peer p2p.Peer,
 
 // HandleProposalTransaction handles data received on the transactions synced as a part of proposal.
 func (th *TxHandler) HandleProposalTransaction(ctx context.Context, expHash types.Hash32, _ p2p.Peer, msg []byte) error {
-	err := th.verifyAndCache(ctx, expHash, msg)
+	err := th.verifyAndCache(ctx, expHash, msg, false)
 	updateMetrics(err, proposalTxCount)
-	if errors.Is(err, errDuplicateTX) {
+	// the transaction rejected by the mempool limits is saved, and the proposal can still reference it
+	if errors.Is(err, errDuplicateTX) || persistable(err) {
 		return nil
 	}
 	return err
 }
 
+// VerifyAndCacheTx verifies the transaction submitted via api and adds it to the cache.
+// Unlike the transactions received from peers, the transaction is rejected if it is too large
+// or the projected balance of the principal is not enough to cover its max spending.
+// The errors for the invalid transactions are *RejectError with the reason.
 func (th *TxHandler) VerifyAndCacheTx(ctx context.Context, msg []byte) error {
-	return th.verifyAndCache(ctx, types.Hash32{}, msg)
+	if len(msg) > maxSubmitTxSize {
+		return reject(RejectOversized, errOversized, map[string]string{
+			"size":     strconv.Itoa(len(msg)),
+			"max_size": strconv.Itoa(maxSubmitTxSize),
+		})
+	}
+	return th.verifyAndCache(ctx, types.Hash32{}, msg, true)
 }
 
-func (th *TxHandler) verifyAndCache(ctx context.Context, expHash types.Hash32, msg []byte) error {
+func (th *TxHandler) verifyAndCache(ctx context.Context, expHash types.Hash32, msg []byte, submitted bool) error {
 	raw := types.NewRawTx(msg)
+	err := th.verify(ctx, raw, expHash, submitted)
+	if errors.Is(err, errParse) || errors.Is(err, errVerify) {
+		th.state.RejectTx(raw.ID, err)
+	}
+	return err
+}
+
+func (th *TxHandler) verify(ctx context.Context, raw types.RawTx, expHash types.Hash32, submitted bool) error {
 	mtx, err := th.state.GetMeshTransaction(raw.ID)
 	if err != nil && !errors.Is(err, sql.ErrNotFound) {
 		return fmt.Errorf("get tx %w", err)
 	}
 	if mtx != nil && mtx.TxHeader != nil {
-		return errDuplicateTX
+		return reject(RejectDuplicate, errDuplicateTX, nil)
 	}
 
 	req := th.state.Validation(raw)
 	header, err := req.Parse()
 	if err != nil {
-		return fmt.Errorf("%w: %s (err: %s)", errParse, raw.ID, err)
+		return reject(RejectMalformed, fmt.Errorf("%w: %s (err: %s)", errParse, raw.ID, err), nil)
 	}
 	tx := &types.Transaction{RawTx: raw, TxHeader: header}
 	if expHash != (types.Hash32{}) && tx.ID.Hash32() != expHash {
 		return fmt.Errorf("%w: proposal tx want %s, got %s", errWrongHash, expHash.ShortString(), tx.ID.ShortString())
 	}
 	if header.GasPrice == 0 {
-		return fmt.Errorf("%w: zero gas price %s", errParse, raw.ID)
+		return reject(RejectFeeTooLow, fmt.Errorf("%w: zero gas price %s", errParse, raw.ID), map[string]string{
+			"gas_price":     "0",
+			"min_gas_price": "1",
+		})
 	}
 	if !req.Verify() {
-		return fmt.Errorf("%w: %s", errVerify, raw.ID)
+		return reject(RejectInvalidSignature, fmt.Errorf("%w: %s", errVerify, raw.ID), nil)
+	}
+	var nonce uint64
+	if submitted {
+		// the projected nonce and balance include the pending transactions of the principal.
+		// the transaction that replaces the pending one is left for the cache to decide.
+		var balance uint64
+		nonce, balance = th.state.GetProjection(header.Principal)
+		if header.Nonce >= nonce && balance < header.Spending() {
+			return reject(RejectInsufficientFunds, fmt.Errorf("%w: %s", errInsufficientFunds, raw.ID), map[string]string{
+				"projected_balance": strconv.FormatUint(balance, 10),
+				"max_spending":      strconv.FormatUint(header.Spending(), 10),
+			})
+		}
 	}
 	if err := th.state.AddToCache(ctx, tx, time.Now()); err != nil {
 		th.logger.WithContext(ctx).With().Warning("failed to add tx to conservative cache",
 			raw.ID,
 			log.Err(err),
 		)
+		if errors.Is(err, errBadNonce) {
+			metadata := map[string]string{"nonce": strconv.FormatUint(header.Nonce, 10)}
+			if submitted {
+				metadata["projected_nonce"] = strconv.FormatUint(nonce, 10)
+			}
+			return reject(RejectBadNonce, err, metadata)
+		}
+		if errors.Is(err, errReplacementUnderpriced) {
+			return reject(RejectFeeTooLow, err, map[string]string{
+				"fee": strconv.FormatUint(header.Fee(), 10),
+			})
+		}
+		if errors.Is(err, errMempoolFull) {
+			return reject(RejectFeeTooLow, err, map[string]string{
+				"gas_price": strconv.FormatUint(header.GasPrice, 10),
+			})
+		}
+		if errors.Is(err, errTooManyNonce) {
+			return reject(RejectTooManyPending, err, map[string]string{
+				"nonce": strconv.FormatUint(header.Nonce, 10),
+			})
+		}
 		return err
 	}
 	return nil
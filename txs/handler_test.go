@@ -3,6 +3,7 @@ package txs
 import (
 	"context"
 	"errors"
+	"strconv"
 	"testing"
 	"time"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/spacemeshos/go-spacemesh/p2p"
 	"github.com/spacemeshos/go-spacemesh/p2p/pubsub"
 	"github.com/spacemeshos/go-spacemesh/signing"
+	"github.com/spacemeshos/go-spacemesh/sql"
 	smocks "github.com/spacemeshos/go-spacemesh/system/mocks"
 )
 
@@ -142,6 +144,9 @@ func gossipExpectations(t *testing.T, fee uint64, hasErr, parseErr, addErr error
 		req := smocks.NewMockValidationRequest(ctrl)
 		req.EXPECT().Parse().Times(1).Return(tx.TxHeader, parseErr)
 		cstate.EXPECT().Validation(tx.RawTx).Times(1).Return(req)
+		if parseErr != nil || fee == 0 || !verify {
+			cstate.EXPECT().RejectTx(tx.ID, gomock.Any()).Times(1)
+		}
 		if parseErr == nil && fee != 0 {
 			req.EXPECT().Verify().Times(1).Return(verify)
 			if verify {
@@ -206,6 +211,20 @@ func Test_HandleGossip(t *testing.T) {
 			addErr: errors.New("test"),
 			expect: isErr,
 		},
+		{
+			desc:   "UnderpricedReplacement",
+			fee:    1,
+			verify: true,
+			addErr: errReplacementUnderpriced,
+			expect: isErr,
+		},
+		{
+			desc:   "TooManyPending",
+			fee:    1,
+			verify: true,
+			addErr: errTooManyNonce,
+			expect: nilErr,
+		},
 		{
 			desc:   "VerifyFalse",
 			fee:    1,
@@ -308,6 +327,12 @@ func Test_HandleProposal(t *testing.T) {
 			addErr: errors.New("test"),
 			fail:   true,
 		},
+		{
+			desc:   "UnderpricedReplacement",
+			fee:    1,
+			verify: true,
+			addErr: errReplacementUnderpriced,
+		},
 		{
 			desc: "ZeroPrice",
 			fail: true,
@@ -328,3 +353,90 @@ func Test_HandleProposal(t *testing.T) {
 		})
 	}
 }
+
+func Test_VerifyAndCacheTx(t *testing.T) {
+	signer, err := signing.NewEdSigner()
+	require.NoError(t, err)
+	for _, tc := range []struct {
+		desc     string
+		fee      uint64
+		verify   bool
+		nonce    uint64
+		balance  uint64
+		addErr   error
+		reason   RejectReason
+		metadata map[string]string
+	}{
+		{desc: "success", fee: 1, verify: true, nonce: 3, balance: 1000},
+		{desc: "zero fee", reason: RejectFeeTooLow, metadata: map[string]string{"gas_price": "0", "min_gas_price": "1"}},
+		{desc: "invalid signature", fee: 1, reason: RejectInvalidSignature},
+		{
+			desc: "insufficient funds", fee: 1, verify: true, nonce: 3, balance: 100,
+			reason:   RejectInsufficientFunds,
+			metadata: map[string]string{"projected_balance": "100", "max_spending": strconv.FormatUint(10+defaultGas, 10)},
+		},
+		{desc: "replaces pending", fee: 1, verify: true, nonce: 4, balance: 0},
+		{
+			desc: "bad nonce", fee: 1, verify: true, nonce: 3, balance: 1000, addErr: errBadNonce,
+			reason:   RejectBadNonce,
+			metadata: map[string]string{"nonce": "3", "projected_nonce": "3"},
+		},
+		{
+			desc: "underpriced replacement", fee: 1, verify: true, nonce: 4, balance: 0, addErr: errReplacementUnderpriced,
+			reason:   RejectFeeTooLow,
+			metadata: map[string]string{"fee": strconv.FormatUint(defaultGas, 10)},
+		},
+		{
+			desc: "mempool full", fee: 1, verify: true, nonce: 3, balance: 1000, addErr: errMempoolFull,
+			reason:   RejectFeeTooLow,
+			metadata: map[string]string{"gas_price": "1"},
+		},
+		{
+			desc: "too many pending", fee: 1, verify: true, nonce: 3, balance: 1000, addErr: errTooManyNonce,
+			reason:   RejectTooManyPending,
+			metadata: map[string]string{"nonce": "3"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			cstate := NewMockconservativeState(ctrl)
+			th := NewTxHandler(cstate, p2p.NoPeer, logtest.New(t))
+			tx := newTx(t, 3, 10, tc.fee, signer)
+
+			cstate.EXPECT().GetMeshTransaction(tx.ID).Return(nil, sql.ErrNotFound)
+			req := smocks.NewMockValidationRequest(ctrl)
+			req.EXPECT().Parse().Return(tx.TxHeader, nil)
+			cstate.EXPECT().Validation(tx.RawTx).Return(req)
+			cstate.EXPECT().RejectTx(tx.ID, gomock.Any()).AnyTimes()
+			if tc.fee != 0 {
+				req.EXPECT().Verify().Return(tc.verify)
+			}
+			if tc.verify {
+				cstate.EXPECT().GetProjection(tx.Principal).Return(tc.nonce, tc.balance)
+			}
+			if tc.verify && tc.reason != RejectInsufficientFunds {
+				cstate.EXPECT().AddToCache(gomock.Any(), gomock.Any(), gomock.Any()).Return(tc.addErr)
+			}
+
+			err := th.VerifyAndCacheTx(context.Background(), tx.Raw)
+			if tc.reason == "" {
+				require.NoError(t, err)
+				return
+			}
+			var rerr *RejectError
+			require.ErrorAs(t, err, &rerr)
+			require.Equal(t, tc.reason, rerr.Reason)
+			require.Equal(t, tc.metadata, rerr.Metadata)
+		})
+	}
+}
+
+func Test_VerifyAndCacheTxOversized(t *testing.T) {
+	th := NewTxHandler(NewMockconservativeState(gomock.NewController(t)), p2p.NoPeer, logtest.New(t))
+	err := th.VerifyAndCacheTx(context.Background(), make([]byte, maxSubmitTxSize+1))
+	var rerr *RejectError
+	require.ErrorAs(t, err, &rerr)
+	require.Equal(t, RejectOversized, rerr.Reason)
+	require.Equal(t, strconv.Itoa(maxSubmitTxSize+1), rerr.Metadata["size"])
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/spacemeshos/go-spacemesh/common/types"
+	vm "github.com/spacemeshos/go-spacemesh/genvm"
 	"github.com/spacemeshos/go-spacemesh/log"
 	"github.com/spacemeshos/go-spacemesh/system"
 )
@@ -17,14 +18,26 @@ type conservativeState interface {
 	AddToCache(context.Context, *types.Transaction, time.Time) error
 	AddToDB(*types.Transaction) error
 	GetMeshTransaction(types.TransactionID) (*types.MeshTransaction, error)
+	GetProjection(types.Address) (uint64, uint64)
+	MempoolStatus(types.TransactionID) (MempoolStatus, string, error)
+	RejectTx(types.TransactionID, error)
+}
+
+type layerClock interface {
+	CurrentLayer() types.LayerID
+	AwaitLayer(types.LayerID) <-chan struct{}
 }
 
 type vmState interface {
 	Validation(types.RawTx) system.ValidationRequest
+	ValidationAt(types.LayerID, types.RawTx) system.ValidationRequest
+	GasSchedule(types.LayerID) vm.GasSchedule
 	GetStateRoot() (types.Hash32, error)
 	GetLayerStateRoot(types.LayerID) (types.Hash32, error)
+	AccountProof(types.Address, types.LayerID) (*types.AccountProof, types.Hash32, error)
 	GetLayerApplied(types.TransactionID) (types.LayerID, error)
 	GetAllAccounts() ([]*types.Account, error)
+	GetAccount(types.Address) (types.Account, error)
 	GetBalance(types.Address) (uint64, error)
 	GetNonce(types.Address) (types.Nonce, error)
 }
@@ -0,0 +1,222 @@
+package txs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/transactions"
+)
+
+// maxRejected is the number of the most recently rejected transactions whose rejection reason is kept.
+const maxRejected = 10000
+
+// MempoolStatus is the status of the transaction in the mempool.
+type MempoolStatus string
+
+const (
+	// MempoolUnknown is the status of the transaction that is not in the mempool,
+	// either it was never received, or it is already applied.
+	MempoolUnknown MempoolStatus = "unknown"
+	// MempoolPending is the status of the transaction in the conservative cache.
+	// it is eligible for proposals, or already packed in a proposal or block that is not applied.
+	MempoolPending MempoolStatus = "pending"
+	// MempoolQueued is the status of the transaction that is persisted, but waits for the
+	// transactions with lower nonces or for the balance of the principal to be considered.
+	MempoolQueued MempoolStatus = "queued"
+	// MempoolRejected is the status of the transaction that was rejected recently.
+	MempoolRejected MempoolStatus = "rejected"
+)
+
+// MempoolStats is the summary of the transactions in the conservative cache.
+type MempoolStats struct {
+	Accounts       int    `json:"accounts"`
+	Transactions   int    `json:"transactions"`
+	MinGasPrice    uint64 `json:"min_gas_price"`
+	MedianGasPrice uint64 `json:"median_gas_price"`
+	MaxGasPrice    uint64 `json:"max_gas_price"`
+	// TotalFee is the sum of max fees of all transactions.
+	TotalFee uint64 `json:"total_fee"`
+	// Orphans is the number of transactions waiting for the nonce gap to be filled.
+	Orphans int `json:"orphans"`
+}
+
+// AccountProjection is the state of the account projected over its transactions in the conservative cache.
+type AccountProjection struct {
+	// Nonce and Balance are the state of the account before the pending transactions.
+	Nonce   uint64 `json:"nonce"`
+	Balance uint64 `json:"balance"`
+	// NextNonce is the nonce for the next transaction of the account.
+	NextNonce uint64 `json:"next_nonce"`
+	// SpendableBalance is the balance left after the max spending of the pending transactions.
+	SpendableBalance uint64 `json:"spendable_balance"`
+	// Pending is the number of the transactions of the account in the cache.
+	Pending int `json:"pending"`
+	// Queued is true if the account has transactions that are persisted, but not in the cache.
+	Queued bool `json:"queued"`
+}
+
+func (c *Cache) reject(tid types.TransactionID, err error) {
+	c.rejected.Add(tid, err.Error())
+}
+
+// Reject records the reason why the transaction was rejected.
+func (c *Cache) Reject(tid types.TransactionID, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reject(tid, err)
+}
+
+// Rejected returns the reason why the transaction was rejected, if it was rejected recently.
+func (c *Cache) Rejected(tid types.TransactionID) (string, bool) {
+	return c.rejected.Get(tid)
+}
+
+// ListMempool returns a page of the transactions in the cache ordered by principal and nonce,
+// and the total number of such transactions. only the transactions of the principal are listed if
+// it is not empty. limit -1 means no limit.
+func (c *Cache) ListMempool(principal types.Address, offset, limit int) ([]*NanoTX, int) {
+	all := c.mempoolTXs(principal)
+	// sorted outside the lock, to not block the cache on the api requests
+	sort.Slice(all, func(i, j int) bool {
+		if cmp := bytes.Compare(all[i].Principal[:], all[j].Principal[:]); cmp != 0 {
+			return cmp < 0
+		}
+		return all[i].Nonce < all[j].Nonce
+	})
+	total := len(all)
+	if offset >= total {
+		return nil, total
+	}
+	all = all[offset:]
+	if limit >= 0 && limit < len(all) {
+		all = all[:limit]
+	}
+	return all, total
+}
+
+// mempoolTXs returns the copies of the transactions in the cache, of the principal if it is not empty.
+func (c *Cache) mempoolTXs(principal types.Address) []*NanoTX {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var all []*NanoTX
+	for addr, acct := range c.pending {
+		if principal != (types.Address{}) && addr != principal {
+			continue
+		}
+		for e := acct.txsByNonce.Front(); e != nil; e = e.Next() {
+			ntx := *e.Value.(*candidate).best
+			all = append(all, &ntx)
+		}
+	}
+	return all
+}
+
+// MempoolStats returns the summary of the transactions in the cache.
+func (c *Cache) MempoolStats() MempoolStats {
+	stats, prices := c.mempoolPrices()
+	if len(prices) > 0 {
+		// sorted outside the lock, to not block the cache on the api requests
+		sort.Slice(prices, func(i, j int) bool { return prices[i] < prices[j] })
+		stats.MinGasPrice = prices[0]
+		stats.MedianGasPrice = prices[len(prices)/2]
+		stats.MaxGasPrice = prices[len(prices)-1]
+	}
+	return stats
+}
+
+// mempoolPrices returns the summary of the transactions in the cache without the gas price
+// aggregates, and the unsorted gas prices of the transactions.
+func (c *Cache) mempoolPrices() (MempoolStats, []uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var (
+		stats  MempoolStats
+		prices []uint64
+	)
+	for _, acct := range c.pending {
+		if acct.txsByNonce.Len() > 0 {
+			stats.Accounts++
+		}
+		for e := acct.txsByNonce.Front(); e != nil; e = e.Next() {
+			ntx := e.Value.(*candidate).best
+			prices = append(prices, ntx.GasPrice)
+			stats.TotalFee += ntx.Fee()
+		}
+	}
+	stats.Transactions = len(prices)
+	if c.orphans != nil {
+		stats.Orphans = c.orphans.size()
+	}
+	return stats, prices
+}
+
+// Projection returns the state of the account projected over its pending transactions.
+func (c *Cache) Projection(addr types.Address) AccountProjection {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	acct, ok := c.pending[addr]
+	if !ok {
+		nonce, balance := c.stateF(addr)
+		return AccountProjection{Nonce: nonce, Balance: balance, NextNonce: nonce, SpendableBalance: balance}
+	}
+	return AccountProjection{
+		Nonce:            acct.startNonce,
+		Balance:          acct.startBalance,
+		NextNonce:        acct.nextNonce(),
+		SpendableBalance: acct.availBalance(),
+		Pending:          acct.txsByNonce.Len(),
+		Queued:           acct.moreInDB,
+	}
+}
+
+// RejectTx records the reason why the transaction was rejected.
+func (cs *ConservativeState) RejectTx(tid types.TransactionID, err error) {
+	cs.cache.Reject(tid, err)
+}
+
+// ListMempool returns a page of the transactions in the mempool ordered by principal and nonce,
+// and the total number of such transactions. only the transactions of the principal are listed if
+// it is not empty. limit -1 means no limit.
+func (cs *ConservativeState) ListMempool(principal types.Address, offset, limit int) ([]*NanoTX, int) {
+	return cs.cache.ListMempool(principal, offset, limit)
+}
+
+// MempoolStats returns the summary of the transactions in the mempool.
+func (cs *ConservativeState) MempoolStats() MempoolStats {
+	return cs.cache.MempoolStats()
+}
+
+// Projection returns the next nonce and the spendable balance of the account, accounting for
+// its pending transactions.
+func (cs *ConservativeState) Projection(addr types.Address) AccountProjection {
+	return cs.cache.Projection(addr)
+}
+
+// MempoolStatus returns the status of the transaction in the mempool,
+// and the rejection reason if the transaction was rejected.
+func (cs *ConservativeState) MempoolStatus(tid types.TransactionID) (MempoolStatus, string, error) {
+	if cs.cache.Has(tid) {
+		return MempoolPending, "", nil
+	}
+	if reason, ok := cs.cache.Rejected(tid); ok {
+		return MempoolRejected, reason, nil
+	}
+	mtx, err := transactions.Get(cs.db, tid)
+	switch {
+	case errors.Is(err, sql.ErrNotFound):
+		return MempoolUnknown, "", nil
+	case err != nil:
+		return "", "", fmt.Errorf("get tx %s: %w", tid, err)
+	case mtx.State == types.MEMPOOL:
+		return MempoolQueued, "", nil
+	default:
+		return MempoolUnknown, "", nil
+	}
+}
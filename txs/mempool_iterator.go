@@ -25,9 +25,9 @@ func (pq priorityQueue) Len() int { return len(pq) }
 
 // Less implements head.Interface.
 func (pq priorityQueue) Less(i, j int) bool {
-	// We want Pop to give us the highest, not lowest, fee, so we use greater than here.
-	if pq[i].Fee() != pq[j].Fee() {
-		return pq[i].Fee() > pq[j].Fee()
+	// We want Pop to give us the highest, not lowest, fee per unit of gas, so we use greater than here.
+	if pq[i].GasPrice != pq[j].GasPrice {
+		return pq[i].GasPrice > pq[j].GasPrice
 	}
 	// if fees are equal, we want the older tx first
 	if !pq[i].Received.Equal(pq[j].Received) {
@@ -113,3 +113,21 @@ func TestPopAll_ExhaustMempool(t *testing.T) {
 	testPopAll(t, mi, expected)
 	require.Empty(t, mempool)
 }
+
+func TestPopAll_FeeDensity(t *testing.T) {
+	now := time.Now()
+	addr0 := types.Address{1, 2, 3}
+	addr1 := types.Address{2, 3, 4}
+	// the higher total fee, but the lower fee per unit of gas
+	expensive := makeNanoTX(addr0, 2, now)
+	expensive.MaxGas = 10
+	dense := makeNanoTX(addr1, 3, now.Add(time.Second))
+	ctrl := gomock.NewController(t)
+	mockCache := NewMockconStateCache(ctrl)
+	mockCache.EXPECT().GetMempool(gomock.Any()).Return(map[types.Address][]*NanoTX{
+		addr0: {expensive},
+		addr1: {dense},
+	})
+	mi := newMempoolIterator(logtest.New(t), mockCache, 100)
+	testPopAll(t, mi, []*NanoTX{dense, expensive})
+}
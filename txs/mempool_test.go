@@ -0,0 +1,135 @@
+package txs
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/signing"
+)
+
+func TestMempool_List(t *testing.T) {
+	tcs := createConservativeState(t)
+	ids, txs := addBatch(t, tcs, 5)
+
+	got, total := tcs.ListMempool(types.Address{}, 0, -1)
+	require.Equal(t, len(ids), total)
+	require.Len(t, got, len(ids))
+	for i := 1; i < len(got); i++ {
+		require.Negative(t, bytes.Compare(got[i-1].Principal[:], got[i].Principal[:]))
+	}
+
+	page, total := tcs.ListMempool(types.Address{}, 3, 10)
+	require.Equal(t, len(ids), total)
+	require.Equal(t, got[3:], page)
+
+	page, total = tcs.ListMempool(types.Address{}, 10, 10)
+	require.Equal(t, len(ids), total)
+	require.Empty(t, page)
+
+	page, total = tcs.ListMempool(txs[0].Principal, 0, -1)
+	require.Equal(t, 1, total)
+	require.Equal(t, txs[0].ID, page[0].ID)
+}
+
+func TestMempool_Stats(t *testing.T) {
+	tcs := createConservativeState(t)
+	require.Equal(t, MempoolStats{}, tcs.MempoolStats())
+
+	signer, err := signing.NewEdSigner()
+	require.NoError(t, err)
+	addr := types.GenerateAddress(signer.PublicKey().Bytes())
+	tcs.mvm.EXPECT().GetBalance(addr).Return(defaultBalance, nil)
+	tcs.mvm.EXPECT().GetNonce(addr).Return(nonce, nil)
+	for i, fee := range []uint64{3, 1, 2} {
+		tx := newTx(t, nonce+uint64(i), defaultAmount, fee, signer)
+		require.NoError(t, tcs.AddToCache(context.Background(), tx, time.Now()))
+	}
+	require.Equal(t, MempoolStats{
+		Accounts:       1,
+		Transactions:   3,
+		MinGasPrice:    1,
+		MedianGasPrice: 2,
+		MaxGasPrice:    3,
+		TotalFee:       6 * defaultGas,
+	}, tcs.MempoolStats())
+}
+
+func TestMempool_Projection(t *testing.T) {
+	tcs := createConservativeState(t)
+	signer, err := signing.NewEdSigner()
+	require.NoError(t, err)
+	addr := types.GenerateAddress(signer.PublicKey().Bytes())
+	tcs.mvm.EXPECT().GetBalance(addr).Return(defaultBalance, nil).Times(2)
+	tcs.mvm.EXPECT().GetNonce(addr).Return(nonce, nil).Times(2)
+	require.Equal(t, AccountProjection{
+		Nonce:            nonce,
+		Balance:          defaultBalance,
+		NextNonce:        nonce,
+		SpendableBalance: defaultBalance,
+	}, tcs.Projection(addr))
+
+	var spent uint64
+	for i := uint64(0); i < 2; i++ {
+		tx := newTx(t, nonce+i, defaultAmount, defaultFee, signer)
+		require.NoError(t, tcs.AddToCache(context.Background(), tx, time.Now()))
+		spent += tx.Spending()
+	}
+	// insufficient balance
+	queued := newTx(t, nonce+2, defaultBalance, defaultFee, signer)
+	require.NoError(t, tcs.AddToCache(context.Background(), queued, time.Now()))
+	require.Equal(t, AccountProjection{
+		Nonce:            nonce,
+		Balance:          defaultBalance,
+		NextNonce:        nonce + 2,
+		SpendableBalance: defaultBalance - spent,
+		Pending:          2,
+		Queued:           true,
+	}, tcs.Projection(addr))
+}
+
+func TestMempool_Status(t *testing.T) {
+	tcs := createConservativeState(t)
+	signer, err := signing.NewEdSigner()
+	require.NoError(t, err)
+	addr := types.GenerateAddress(signer.PublicKey().Bytes())
+	tcs.mvm.EXPECT().GetBalance(addr).Return(defaultBalance, nil)
+	tcs.mvm.EXPECT().GetNonce(addr).Return(nonce, nil)
+
+	pending := newTx(t, nonce, defaultAmount, defaultFee, signer)
+	require.NoError(t, tcs.AddToCache(context.Background(), pending, time.Now()))
+	status, reason, err := tcs.MempoolStatus(pending.ID)
+	require.NoError(t, err)
+	require.Equal(t, MempoolPending, status)
+	require.Empty(t, reason)
+
+	// the nonce of the principal in the state is past the nonce of the tx
+	bad := newTx(t, nonce-1, defaultAmount, defaultFee, signer)
+	require.ErrorIs(t, tcs.AddToCache(context.Background(), bad, time.Now()), errBadNonce)
+	status, reason, err = tcs.MempoolStatus(bad.ID)
+	require.NoError(t, err)
+	require.Equal(t, MempoolRejected, status)
+	require.Equal(t, errBadNonce.Error(), reason)
+
+	invalid := types.RandomTransactionID()
+	tcs.RejectTx(invalid, errVerify)
+	status, reason, err = tcs.MempoolStatus(invalid)
+	require.NoError(t, err)
+	require.Equal(t, MempoolRejected, status)
+	require.Equal(t, errVerify.Error(), reason)
+
+	// txs with insufficient balance are persisted, but not added to the cache
+	queued := newTx(t, nonce+1, defaultBalance, defaultFee, signer)
+	require.NoError(t, tcs.AddToCache(context.Background(), queued, time.Now()))
+	status, _, err = tcs.MempoolStatus(queued.ID)
+	require.NoError(t, err)
+	require.Equal(t, MempoolQueued, status)
+
+	status, _, err = tcs.MempoolStatus(types.RandomTransactionID())
+	require.NoError(t, err)
+	require.Equal(t, MempoolUnknown, status)
+}
@@ -24,7 +24,15 @@ const (
 	mempool         = "mempool"
 	balanceTooSmall = "balance"
 	tooManyNonce    = "too_many"
+	underpriced     = "underpriced"
+	replacedByFee   = "replaced"
+	orphaned        = "orphan"
+	promoted        = "promoted"
 	accepted        = "ok"
+
+	// labels for the outcome of the local tx rebroadcast.
+	rebroadcast = "rebroadcast"
+	dropped     = "dropped"
 )
 
 var (
@@ -58,6 +66,18 @@ var (
 		"number of transactions added to the mempool",
 		[]string{"outcome"},
 	)
+	localTxCount = metrics.NewCounter(
+		"local_txs",
+		namespace,
+		"number of rebroadcast and dropped transactions submitted via api",
+		[]string{"outcome"},
+	)
+	mempoolEvictions = metrics.NewCounter(
+		"mempool_evictions",
+		namespace,
+		"number of transactions evicted from the mempool",
+		[]string{"reason"},
+	)
 )
 
 var (
@@ -68,6 +88,30 @@ var (
 		[]string{},
 		prometheus.ExponentialBuckets(100_000_000, 2, 10),
 	).WithLabelValues()
+	selectionCandidates = metrics.NewGauge(
+		"proposal_selection_candidates",
+		namespace,
+		"number of mempool transactions that fit the block gas limit in the last proposal selection",
+		[]string{},
+	).WithLabelValues()
+	selectionSelected = metrics.NewGauge(
+		"proposal_selection_selected",
+		namespace,
+		"number of transactions selected for the last proposal",
+		[]string{},
+	).WithLabelValues()
+	selectionGas = metrics.NewGauge(
+		"proposal_selection_gas",
+		namespace,
+		"max gas of the transactions selected for the last proposal",
+		[]string{},
+	).WithLabelValues()
+	selectionMinGasPrice = metrics.NewGauge(
+		"proposal_selection_min_gas_price",
+		namespace,
+		"lowest gas price of the transactions selected for the last proposal",
+		[]string{},
+	).WithLabelValues()
 	acctResetDuration = metrics.NewHistogramWithBuckets(
 		"acct_reset_duration",
 		namespace,
@@ -15,6 +15,8 @@ type NanoTX struct {
 	ID types.TransactionID
 
 	Received time.Time
+	// Size is the size of the raw transaction in bytes.
+	Size int
 
 	Block types.BlockID
 	Layer types.LayerID
@@ -26,6 +28,7 @@ func NewNanoTX(mtx *types.MeshTransaction) *NanoTX {
 		ID:       mtx.ID,
 		TxHeader: *mtx.TxHeader,
 		Received: mtx.Received,
+		Size:     len(mtx.Raw),
 		Block:    mtx.BlockID,
 		Layer:    mtx.LayerID,
 	}
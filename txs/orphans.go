@@ -0,0 +1,183 @@
+package txs
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	"github.com/spacemeshos/go-spacemesh/sql/transactions"
+)
+
+// maxOrphans is the default max number of the transactions in the orphan pool.
+const maxOrphans = 1000
+
+var (
+	errOrphanUnderpriced = errors.New("orphan transaction underpriced")
+	errOrphanEvicted     = errors.New("evicted from full orphan pool")
+)
+
+// orphanPool holds the transactions whose nonce is ahead of the projected nonce of the principal.
+// they are promoted to the conservative cache once the transactions with the lower nonces fill the gap.
+type orphanPool struct {
+	max int
+	// byReceived orders the orphans by the time they were received, the oldest is at the front.
+	byReceived  *list.List
+	byPrincipal map[types.Address]map[uint64]*list.Element
+}
+
+func newOrphanPool(max int) *orphanPool {
+	return &orphanPool{
+		max:         max,
+		byReceived:  list.New(),
+		byPrincipal: make(map[types.Address]map[uint64]*list.Element),
+	}
+}
+
+// size returns the number of the orphans in the pool.
+func (p *orphanPool) size() int {
+	return p.byReceived.Len()
+}
+
+// check returns an error if the orphan with the same principal and nonce has the same or higher gas price.
+func (p *orphanPool) check(ntx *NanoTX) error {
+	if prev, ok := p.byPrincipal[ntx.Principal][ntx.Nonce]; ok {
+		if price := prev.Value.(*NanoTX).GasPrice; ntx.GasPrice <= price {
+			return fmt.Errorf("%w: pending gas price %d", errOrphanUnderpriced, price)
+		}
+	}
+	return nil
+}
+
+// add holds the transaction in the pool. the orphan with the same principal and nonce is replaced
+// if the new one has a higher gas price. when the pool is full, the oldest orphan is evicted.
+// the replaced and the evicted orphans are returned.
+func (p *orphanPool) add(ntx *NanoTX) (*NanoTX, *NanoTX, error) {
+	if err := p.check(ntx); err != nil {
+		return nil, nil, err
+	}
+	replaced := p.take(ntx.Principal, ntx.Nonce)
+	byNonce, ok := p.byPrincipal[ntx.Principal]
+	if !ok {
+		byNonce = make(map[uint64]*list.Element)
+		p.byPrincipal[ntx.Principal] = byNonce
+	}
+	byNonce[ntx.Nonce] = p.insert(ntx)
+	if replaced != nil || p.size() <= p.max {
+		return replaced, nil, nil
+	}
+	oldest := p.byReceived.Front().Value.(*NanoTX)
+	p.take(oldest.Principal, oldest.Nonce)
+	return nil, oldest, nil
+}
+
+// insert puts the orphan into byReceived after the orphans received before it.
+// orphans are usually received in order, so the position is found at the back.
+func (p *orphanPool) insert(ntx *NanoTX) *list.Element {
+	for e := p.byReceived.Back(); e != nil; e = e.Prev() {
+		if !ntx.Received.Before(e.Value.(*NanoTX).Received) {
+			return p.byReceived.InsertAfter(ntx, e)
+		}
+	}
+	return p.byReceived.PushFront(ntx)
+}
+
+// take removes the orphan with the principal and nonce from the pool and returns it.
+func (p *orphanPool) take(principal types.Address, nonce uint64) *NanoTX {
+	byNonce, ok := p.byPrincipal[principal]
+	if !ok {
+		return nil
+	}
+	e, ok := byNonce[nonce]
+	if !ok {
+		return nil
+	}
+	delete(byNonce, nonce)
+	if len(byNonce) == 0 {
+		delete(p.byPrincipal, principal)
+	}
+	return p.byReceived.Remove(e).(*NanoTX)
+}
+
+// prune removes the orphans of the principal with the nonces lower than the given one.
+func (p *orphanPool) prune(principal types.Address, nonce uint64) {
+	for n := range p.byPrincipal[principal] {
+		if n < nonce {
+			p.take(principal, n)
+		}
+	}
+}
+
+// rescheduleOrphans updates the max gas of the orphans recomputed for the new gas costs.
+func (c *Cache) rescheduleOrphans(maxGas map[types.TransactionID]uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.orphans == nil {
+		return
+	}
+	for e := c.orphans.byReceived.Front(); e != nil; e = e.Next() {
+		ntx := e.Value.(*NanoTX)
+		if gas, ok := maxGas[ntx.ID]; ok {
+			ntx.MaxGas = gas
+		}
+	}
+}
+
+// addOrphan persists the transaction with the nonce gap and holds it in the orphan pool.
+// the transaction is added to the pool only after it is persisted, so that the pool never
+// holds a transaction that is missing in db.
+func (c *Cache) addOrphan(logger log.Log, db *sql.Database, tx *types.Transaction, received time.Time) error {
+	ntx := NewNanoTX(&types.MeshTransaction{
+		Transaction: *tx,
+		Received:    received,
+		BlockID:     types.EmptyBlockID,
+	})
+	if err := c.orphans.check(ntx); err != nil {
+		c.reject(tx.ID, err)
+		return err
+	}
+	if err := transactions.Add(db, tx, received); err != nil {
+		return err
+	}
+	replaced, evicted, err := c.orphans.add(ntx)
+	if err != nil {
+		return err
+	}
+	// the orphan is reconsidered from db after a layer is applied, even if it is evicted from the pool
+	c.pending[tx.Principal].moreInDB = true
+	mempoolTxCount.WithLabelValues(orphaned).Inc()
+	logger.With().Debug("tx added to orphan pool",
+		tx.ID,
+		log.Uint64("nonce", tx.Nonce),
+		log.Uint64("next_nonce", c.pending[tx.Principal].nextNonce()))
+	if replaced != nil {
+		c.reject(replaced.ID, fmt.Errorf("%w: %s", errReplaced, tx.ID))
+	}
+	if evicted != nil {
+		c.reject(evicted.ID, errOrphanEvicted)
+	}
+	return nil
+}
+
+// promoteOrphans moves the orphans of the principal to the cache as long as their nonces follow
+// the projected nonce of the principal without a gap.
+func (c *Cache) promoteOrphans(logger log.Log, principal types.Address) {
+	acct, ok := c.pending[principal]
+	if c.orphans == nil || !ok {
+		return
+	}
+	c.orphans.prune(principal, acct.nextNonce())
+	for ntx := c.orphans.take(principal, acct.nextNonce()); ntx != nil; ntx = c.orphans.take(principal, acct.nextNonce()) {
+		if _, err := acct.accept(logger, ntx, nil, 0); err != nil {
+			// the orphan stays in db and is reconsidered after a layer is applied
+			logger.With().Debug("failed to promote orphan tx", ntx.ID, log.Err(err))
+			acct.moreInDB = true
+			return
+		}
+		mempoolTxCount.WithLabelValues(promoted).Inc()
+		logger.With().Debug("promoted orphan tx", ntx.ID, log.Uint64("nonce", ntx.Nonce))
+	}
+}
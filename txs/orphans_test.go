@@ -0,0 +1,135 @@
+package txs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/sql/layers"
+	"github.com/spacemeshos/go-spacemesh/sql/transactions"
+)
+
+func createOrphansTestCache(tb testing.TB, max int) (*testCache, *testAcct) {
+	tb.Helper()
+	tc, ta := createSingleAccountTestCache(tb)
+	tc.orphans = newOrphanPool(max)
+	return tc, ta
+}
+
+func TestCache_OrphansPromotedWhenGapFilled(t *testing.T) {
+	tc, ta := createOrphansTestCache(t, maxOrphans)
+	now := time.Now()
+	var mtxs []*types.MeshTransaction
+	for _, n := range []uint64{2, 1, 0} {
+		mtxs = append(mtxs, &types.MeshTransaction{
+			Transaction: *newTx(t, ta.nonce+n, defaultAmount, defaultFee, ta.signer),
+			Received:    now,
+		})
+	}
+	for _, mtx := range mtxs[:2] {
+		require.NoError(t, tc.Add(context.Background(), tc.db, &mtx.Transaction, mtx.Received, false))
+		checkNoTX(t, tc.Cache, mtx.ID)
+	}
+	require.Equal(t, 2, tc.MempoolStats().Orphans)
+	require.True(t, tc.MoreInDB(ta.principal))
+	checkTXStateFromDB(t, tc.db, mtxs[:2], types.MEMPOOL)
+
+	// the gap is filled
+	require.NoError(t, tc.Add(context.Background(), tc.db, &mtxs[2].Transaction, mtxs[2].Received, false))
+	for _, mtx := range mtxs {
+		checkTX(t, tc.Cache, mtx.ID, 0, types.EmptyBlockID)
+	}
+	require.Zero(t, tc.MempoolStats().Orphans)
+	checkProjection(t, tc.Cache, ta.principal, ta.nonce+3, ta.balance-3*mtxs[0].Spending())
+}
+
+func TestCache_OrphansPromotedAfterApply(t *testing.T) {
+	tc, ta := createOrphansTestCache(t, maxOrphans)
+	now := time.Now()
+	applied := newTx(t, ta.nonce, defaultAmount, defaultFee, ta.signer)
+	orphan := &types.MeshTransaction{
+		Transaction: *newTx(t, ta.nonce+1, defaultAmount, defaultFee, ta.signer),
+		Received:    now,
+	}
+	require.NoError(t, tc.Add(context.Background(), tc.db, &orphan.Transaction, orphan.Received, false))
+	checkNoTX(t, tc.Cache, orphan.ID)
+
+	// the gap is filled by a block
+	require.NoError(t, transactions.Add(tc.db, applied, now))
+	lid := types.LayerID(97)
+	bid := types.BlockID{1, 2, 3}
+	require.NoError(t, layers.SetApplied(tc.db, lid.Sub(1), types.RandomBlockID()))
+	ta.nonce++
+	ta.balance -= applied.Spending()
+	require.NoError(t, tc.ApplyLayer(context.Background(), tc.db, lid, bid, makeResults(lid, bid, *applied), nil))
+	checkTX(t, tc.Cache, orphan.ID, 0, types.EmptyBlockID)
+	require.Zero(t, tc.MempoolStats().Orphans)
+}
+
+func TestCache_OrphanPoolFull(t *testing.T) {
+	tc, ta := createOrphansTestCache(t, 1)
+	now := time.Now()
+	older := newTx(t, ta.nonce+1, defaultAmount, defaultFee, ta.signer)
+	newer := newTx(t, ta.nonce+2, defaultAmount, defaultFee, ta.signer)
+	require.NoError(t, tc.Add(context.Background(), tc.db, older, now.Add(-time.Second), false))
+	require.NoError(t, tc.Add(context.Background(), tc.db, newer, now, false))
+	require.Equal(t, 1, tc.MempoolStats().Orphans)
+	reason, ok := tc.Rejected(older.ID)
+	require.True(t, ok)
+	require.Equal(t, errOrphanEvicted.Error(), reason)
+	checkTXStateFromDB(t, tc.db, []*types.MeshTransaction{{Transaction: *older}, {Transaction: *newer}}, types.MEMPOOL)
+
+	// the orphan with the same nonce is replaced only for the higher gas price
+	same := newTx(t, ta.nonce+2, defaultAmount, defaultFee, ta.signer)
+	require.ErrorIs(t, tc.Add(context.Background(), tc.db, same, now, false), errOrphanUnderpriced)
+	better := newTx(t, ta.nonce+2, defaultAmount, defaultFee+1, ta.signer)
+	require.NoError(t, tc.Add(context.Background(), tc.db, better, now, false))
+	_, ok = tc.Rejected(newer.ID)
+	require.True(t, ok)
+	require.Equal(t, 1, tc.MempoolStats().Orphans)
+}
+
+func TestOrphanPool_EvictsOldest(t *testing.T) {
+	pool := newOrphanPool(2)
+	now := time.Now()
+	orphan := func(principal byte, nonce uint64, price uint64, received time.Time) *NanoTX {
+		ntx := &NanoTX{Received: received}
+		ntx.Principal = types.Address{principal}
+		ntx.Nonce = nonce
+		ntx.GasPrice = price
+		ntx.ID = types.TransactionID{principal, byte(nonce), byte(price)}
+		return ntx
+	}
+	newer := orphan(1, 1, 1, now)
+	older := orphan(2, 1, 1, now.Add(-time.Second))
+	for _, ntx := range []*NanoTX{newer, older} {
+		replaced, evicted, err := pool.add(ntx)
+		require.NoError(t, err)
+		require.Nil(t, replaced)
+		require.Nil(t, evicted)
+	}
+
+	// the replacement is ordered by its own received time
+	better := orphan(2, 1, 2, now.Add(time.Second))
+	replaced, evicted, err := pool.add(better)
+	require.NoError(t, err)
+	require.Equal(t, older, replaced)
+	require.Nil(t, evicted)
+
+	_, evicted, err = pool.add(orphan(3, 1, 1, now.Add(2*time.Second)))
+	require.NoError(t, err)
+	require.Equal(t, newer, evicted)
+	require.Equal(t, 2, pool.size())
+	require.Equal(t, better, pool.byReceived.Front().Value)
+}
+
+func TestCache_OrphanNotPooledIfNotPersisted(t *testing.T) {
+	tc, ta := createOrphansTestCache(t, maxOrphans)
+	require.NoError(t, tc.db.Close())
+	orphan := newTx(t, ta.nonce+1, defaultAmount, defaultFee, ta.signer)
+	require.Error(t, tc.Add(context.Background(), tc.db, orphan, time.Now(), false))
+	require.Zero(t, tc.orphans.size())
+}
@@ -0,0 +1,196 @@
+package txs
+
+import (
+	"context"
+	"sync"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/events"
+	"github.com/spacemeshos/go-spacemesh/log"
+	"github.com/spacemeshos/go-spacemesh/p2p/pubsub"
+)
+
+// maxLocalTXs is the max number of the tracked local transactions, new transactions are not tracked
+// until the earlier ones are confirmed or dropped.
+const maxLocalTXs = 10_000
+
+// RebroadcastConfig is the config for the rebroadcast of the local transactions.
+type RebroadcastConfig struct {
+	// Interval is the number of layers after which the unconfirmed transaction is gossiped again.
+	Interval uint32
+	// MaxRetries is the max number of times the transaction is gossiped again before it is dropped.
+	MaxRetries int
+}
+
+// localTX is the transaction submitted via the api of this node.
+type localTX struct {
+	raw     []byte
+	last    types.LayerID
+	retries int
+}
+
+// Rebroadcaster tracks the transactions submitted via the api of this node and gossips them
+// again if they are not confirmed after the configured number of layers.
+type Rebroadcaster struct {
+	handler   *TxHandler
+	logger    log.Log
+	cfg       RebroadcastConfig
+	publisher pubsub.Publisher
+
+	mu      sync.Mutex
+	current types.LayerID
+	local   map[types.TransactionID]*localTX
+}
+
+// NewRebroadcaster returns a Rebroadcaster for the transactions verified by the handler.
+func NewRebroadcaster(th *TxHandler, publisher pubsub.Publisher, cfg RebroadcastConfig, logger log.Log) *Rebroadcaster {
+	return &Rebroadcaster{
+		handler:   th,
+		logger:    logger,
+		cfg:       cfg,
+		publisher: publisher,
+		local:     make(map[types.TransactionID]*localTX),
+	}
+}
+
+// VerifyAndCacheTx verifies the transaction submitted via api and tracks it if it is accepted.
+func (r *Rebroadcaster) VerifyAndCacheTx(ctx context.Context, msg []byte) error {
+	if err := r.handler.VerifyAndCacheTx(ctx, msg); err != nil {
+		return err
+	}
+	raw := types.NewRawTx(msg)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.local) >= maxLocalTXs {
+		r.logger.WithContext(ctx).With().Warning("too many local txs, not tracked for rebroadcast", raw.ID)
+		return nil
+	}
+	r.local[raw.ID] = &localTX{raw: raw.Raw, last: r.current}
+	return nil
+}
+
+// Run rebroadcasts the local transactions on every layer until the context is canceled.
+func (r *Rebroadcaster) Run(ctx context.Context, clock layerClock) error {
+	for lid := clock.CurrentLayer(); ; lid = lid.Add(1) {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-clock.AwaitLayer(lid):
+		}
+		r.onLayer(ctx, lid)
+	}
+}
+
+// dueTX is the snapshot of the local transaction that is due for rebroadcast.
+type dueTX struct {
+	id      types.TransactionID
+	raw     []byte
+	retries int
+}
+
+type rebroadcastOutcome int
+
+const (
+	// rebroadcastSkip leaves the transaction as is, it is checked again in the next layer.
+	rebroadcastSkip rebroadcastOutcome = iota
+	// rebroadcastDelay postpones the transaction by the rebroadcast interval.
+	rebroadcastDelay
+	// rebroadcastDrop stops tracking the transaction.
+	rebroadcastDrop
+	// rebroadcastPublish gossips the transaction again.
+	rebroadcastPublish
+)
+
+// onLayer snapshots the transactions that are due for rebroadcast under the lock,
+// checks and publishes them without holding it, and records the outcome under the lock.
+func (r *Rebroadcaster) onLayer(ctx context.Context, lid types.LayerID) {
+	logger := r.logger.WithContext(ctx).WithFields(lid)
+	r.mu.Lock()
+	r.current = lid
+	var due []dueTX
+	for id, tx := range r.local {
+		if lid.Difference(tx.last) >= r.cfg.Interval {
+			due = append(due, dueTX{id: id, raw: tx.raw, retries: tx.retries})
+		}
+	}
+	r.mu.Unlock()
+
+	for _, tx := range due {
+		outcome := r.check(logger, tx)
+		if outcome == rebroadcastPublish {
+			if err := r.publisher.Publish(ctx, pubsub.TxProtocol, tx.raw); err != nil {
+				logger.With().Warning("failed to rebroadcast local tx", tx.id, log.Err(err))
+				outcome = rebroadcastSkip
+			} else {
+				localTxCount.WithLabelValues(rebroadcast).Inc()
+				logger.With().Debug("rebroadcast local tx", tx.id, log.Int("retries", tx.retries+1))
+			}
+		}
+		r.mu.Lock()
+		if ltx, ok := r.local[tx.id]; ok {
+			switch outcome {
+			case rebroadcastDrop:
+				delete(r.local, tx.id)
+			case rebroadcastDelay:
+				ltx.last = lid
+			case rebroadcastPublish:
+				ltx.retries++
+				ltx.last = lid
+			}
+		}
+		r.mu.Unlock()
+	}
+}
+
+// check decides what to do with the local transaction that is due for rebroadcast.
+// the transaction is dropped if it is applied, if the mempool rejected, replaced or evicted it,
+// if its nonce is below the projected nonce of the principal, or if it reached the max retries.
+func (r *Rebroadcaster) check(logger log.Log, tx dueTX) rebroadcastOutcome {
+	status, reason, err := r.handler.state.MempoolStatus(tx.id)
+	if err != nil {
+		logger.With().Warning("failed to get mempool status of local tx", tx.id, log.Err(err))
+		return rebroadcastSkip
+	}
+	if status == MempoolRejected {
+		logger.With().Info("local tx dropped after it was rejected by mempool",
+			tx.id,
+			log.String("reason", reason))
+		localTxCount.WithLabelValues(dropped).Inc()
+		return rebroadcastDrop
+	}
+	mtx, err := r.handler.state.GetMeshTransaction(tx.id)
+	if err != nil {
+		logger.With().Warning("failed to get local tx", tx.id, log.Err(err))
+		return rebroadcastSkip
+	}
+	switch {
+	case mtx.State == types.APPLIED:
+		return rebroadcastDrop
+	case mtx.LayerID != 0:
+		// packed in the proposal or block that is not applied yet
+		return rebroadcastDelay
+	}
+	if status != MempoolPending && mtx.TxHeader != nil {
+		// the nonce is taken by another transaction of the principal
+		if nonce, _ := r.handler.state.GetProjection(mtx.Principal); mtx.Nonce < nonce {
+			logger.With().Info("local tx dropped below the projected nonce",
+				tx.id,
+				log.Uint64("nonce", mtx.Nonce),
+				log.Uint64("projected_nonce", nonce))
+			localTxCount.WithLabelValues(dropped).Inc()
+			events.ReportTxDropped(tx.id, mtx.Principal, mtx.Nonce, tx.retries)
+			return rebroadcastDrop
+		}
+	}
+	if tx.retries >= r.cfg.MaxRetries {
+		logger.With().Info("local tx dropped after max rebroadcasts",
+			tx.id,
+			log.Int("retries", tx.retries))
+		localTxCount.WithLabelValues(dropped).Inc()
+		if mtx.TxHeader != nil {
+			events.ReportTxDropped(tx.id, mtx.Principal, mtx.Nonce, tx.retries)
+		}
+		return rebroadcastDrop
+	}
+	return rebroadcastPublish
+}
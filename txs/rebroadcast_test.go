@@ -0,0 +1,130 @@
+package txs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/events"
+	"github.com/spacemeshos/go-spacemesh/log/logtest"
+	"github.com/spacemeshos/go-spacemesh/p2p"
+	"github.com/spacemeshos/go-spacemesh/p2p/pubsub"
+	pmocks "github.com/spacemeshos/go-spacemesh/p2p/pubsub/mocks"
+	"github.com/spacemeshos/go-spacemesh/signing"
+	"github.com/spacemeshos/go-spacemesh/sql"
+	smocks "github.com/spacemeshos/go-spacemesh/system/mocks"
+)
+
+func submitLocalTx(t *testing.T, ctrl *gomock.Controller, cstate *MockconservativeState, r *Rebroadcaster, tx *types.Transaction) {
+	t.Helper()
+	cstate.EXPECT().GetMeshTransaction(tx.ID).Return(nil, sql.ErrNotFound)
+	req := smocks.NewMockValidationRequest(ctrl)
+	req.EXPECT().Parse().Return(tx.TxHeader, nil)
+	req.EXPECT().Verify().Return(true)
+	cstate.EXPECT().Validation(tx.RawTx).Return(req)
+	cstate.EXPECT().GetProjection(tx.Principal).Return(tx.Nonce, defaultBalance)
+	cstate.EXPECT().AddToCache(gomock.Any(), gomock.Any(), gomock.Any())
+	require.NoError(t, r.VerifyAndCacheTx(context.Background(), tx.Raw))
+}
+
+func TestRebroadcaster(t *testing.T) {
+	events.InitializeReporter()
+	t.Cleanup(events.CloseEventReporter)
+	signer, err := signing.NewEdSigner()
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	cstate := NewMockconservativeState(ctrl)
+	publisher := pmocks.NewMockPublisher(ctrl)
+	r := NewRebroadcaster(NewTxHandler(cstate, p2p.NoPeer, logtest.New(t)), publisher,
+		RebroadcastConfig{Interval: 2, MaxRetries: 1}, logtest.New(t))
+
+	pending := newTx(t, 1, defaultAmount, defaultFee, signer)
+	applied := newTx(t, 2, defaultAmount, defaultFee, signer)
+	submitLocalTx(t, ctrl, cstate, r, pending)
+	submitLocalTx(t, ctrl, cstate, r, applied)
+
+	// not enough layers since the submission
+	r.onLayer(context.Background(), types.LayerID(1))
+
+	cstate.EXPECT().MempoolStatus(pending.ID).Return(MempoolPending, "", nil)
+	cstate.EXPECT().GetMeshTransaction(pending.ID).Return(&types.MeshTransaction{Transaction: *pending, State: types.MEMPOOL}, nil)
+	cstate.EXPECT().MempoolStatus(applied.ID).Return(MempoolUnknown, "", nil)
+	cstate.EXPECT().GetMeshTransaction(applied.ID).Return(&types.MeshTransaction{Transaction: *applied, State: types.APPLIED}, nil)
+	publisher.EXPECT().Publish(gomock.Any(), pubsub.TxProtocol, pending.Raw).DoAndReturn(
+		func(context.Context, string, []byte) error {
+			// published without holding the lock
+			require.True(t, r.mu.TryLock())
+			r.mu.Unlock()
+			return nil
+		})
+	r.onLayer(context.Background(), types.LayerID(2))
+
+	r.onLayer(context.Background(), types.LayerID(3))
+
+	// the max number of retries is reached
+	cstate.EXPECT().MempoolStatus(pending.ID).Return(MempoolPending, "", nil)
+	cstate.EXPECT().GetMeshTransaction(pending.ID).Return(&types.MeshTransaction{Transaction: *pending, State: types.MEMPOOL}, nil)
+	r.onLayer(context.Background(), types.LayerID(4))
+	require.Empty(t, r.local)
+
+	sub, replay, err := events.SubscribeStream(0, []events.StreamKind{events.StreamTxDropped})
+	require.NoError(t, err)
+	defer sub.Close()
+	require.Equal(t, []events.StreamEvent{{
+		Cursor: 1,
+		Kind:   events.StreamTxDropped,
+		Data: events.StreamTxDroppedData{
+			ID:        pending.ID.String(),
+			Principal: pending.Principal.String(),
+			Nonce:     pending.Nonce,
+			Retries:   1,
+		},
+	}}, replay)
+}
+
+func TestRebroadcaster_PackedNotRebroadcast(t *testing.T) {
+	signer, err := signing.NewEdSigner()
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	cstate := NewMockconservativeState(ctrl)
+	r := NewRebroadcaster(NewTxHandler(cstate, p2p.NoPeer, logtest.New(t)), pmocks.NewMockPublisher(ctrl),
+		RebroadcastConfig{Interval: 1, MaxRetries: 1}, logtest.New(t))
+	tx := newTx(t, 1, defaultAmount, defaultFee, signer)
+	submitLocalTx(t, ctrl, cstate, r, tx)
+
+	cstate.EXPECT().MempoolStatus(tx.ID).Return(MempoolPending, "", nil)
+	cstate.EXPECT().GetMeshTransaction(tx.ID).Return(&types.MeshTransaction{
+		Transaction: *tx,
+		State:       types.MEMPOOL,
+		LayerID:     types.LayerID(1),
+	}, nil)
+	r.onLayer(context.Background(), types.LayerID(1))
+	require.Contains(t, r.local, tx.ID)
+	require.Zero(t, r.local[tx.ID].retries)
+}
+
+func TestRebroadcaster_DropStale(t *testing.T) {
+	signer, err := signing.NewEdSigner()
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	cstate := NewMockconservativeState(ctrl)
+	r := NewRebroadcaster(NewTxHandler(cstate, p2p.NoPeer, logtest.New(t)), pmocks.NewMockPublisher(ctrl),
+		RebroadcastConfig{Interval: 1, MaxRetries: 10}, logtest.New(t))
+	rejected := newTx(t, 1, defaultAmount, defaultFee, signer)
+	stale := newTx(t, 2, defaultAmount, defaultFee, signer)
+	submitLocalTx(t, ctrl, cstate, r, rejected)
+	submitLocalTx(t, ctrl, cstate, r, stale)
+
+	cstate.EXPECT().MempoolStatus(rejected.ID).Return(MempoolRejected, "replaced", nil)
+	cstate.EXPECT().MempoolStatus(stale.ID).Return(MempoolQueued, "", nil)
+	cstate.EXPECT().GetMeshTransaction(stale.ID).Return(&types.MeshTransaction{Transaction: *stale, State: types.MEMPOOL}, nil)
+	cstate.EXPECT().GetProjection(stale.Principal).Return(stale.Nonce+1, defaultBalance)
+	r.onLayer(context.Background(), types.LayerID(1))
+	require.Empty(t, r.local)
+}
@@ -0,0 +1,52 @@
+package txs
+
+import (
+	"errors"
+)
+
+// maxSubmitTxSize is the max size of the transaction submitted via api. the transactions of
+// the supported templates are well below the limit.
+const maxSubmitTxSize = 64 << 10
+
+var (
+	errOversized         = errors.New("tx is too large")
+	errInsufficientFunds = errors.New("insufficient projected balance")
+)
+
+// RejectReason is the machine readable reason why the transaction was rejected.
+type RejectReason string
+
+const (
+	RejectOversized         RejectReason = "TX_OVERSIZED"
+	RejectMalformed         RejectReason = "TX_MALFORMED"
+	RejectFeeTooLow         RejectReason = "TX_FEE_TOO_LOW"
+	RejectInvalidSignature  RejectReason = "TX_INVALID_SIGNATURE"
+	RejectDuplicate         RejectReason = "TX_DUPLICATE"
+	RejectBadNonce          RejectReason = "TX_BAD_NONCE"
+	RejectInsufficientFunds RejectReason = "TX_INSUFFICIENT_FUNDS"
+	RejectTooManyPending    RejectReason = "TX_TOO_MANY_PENDING"
+)
+
+// RejectError is returned for the transaction that didn't pass validation.
+type RejectError struct {
+	Reason RejectReason
+	// Metadata has the values relevant for the reason, e.g. the expected nonce.
+	Metadata map[string]string
+	err      error
+}
+
+func (e *RejectError) Error() string {
+	if e.err == nil {
+		return string(e.Reason)
+	}
+	return e.err.Error()
+}
+
+func (e *RejectError) Unwrap() error {
+	return e.err
+}
+
+// reject wraps the error with the reason, metadata is optional.
+func reject(reason RejectReason, err error, metadata map[string]string) *RejectError {
+	return &RejectError{Reason: reason, Metadata: metadata, err: err}
+}
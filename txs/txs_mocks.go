@@ -11,6 +11,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	types "github.com/spacemeshos/go-spacemesh/common/types"
+	vm "github.com/spacemeshos/go-spacemesh/genvm"
 	log "github.com/spacemeshos/go-spacemesh/log"
 	system "github.com/spacemeshos/go-spacemesh/system"
 )
@@ -81,6 +82,21 @@ func (mr *MockconservativeStateMockRecorder) GetMeshTransaction(arg0 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMeshTransaction", reflect.TypeOf((*MockconservativeState)(nil).GetMeshTransaction), arg0)
 }
 
+// GetProjection mocks base method.
+func (m *MockconservativeState) GetProjection(arg0 types.Address) (uint64, uint64) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProjection", arg0)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(uint64)
+	return ret0, ret1
+}
+
+// GetProjection indicates an expected call of GetProjection.
+func (mr *MockconservativeStateMockRecorder) GetProjection(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjection", reflect.TypeOf((*MockconservativeState)(nil).GetProjection), arg0)
+}
+
 // HasTx mocks base method.
 func (m *MockconservativeState) HasTx(arg0 types.TransactionID) (bool, error) {
 	m.ctrl.T.Helper()
@@ -96,6 +112,34 @@ func (mr *MockconservativeStateMockRecorder) HasTx(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasTx", reflect.TypeOf((*MockconservativeState)(nil).HasTx), arg0)
 }
 
+// MempoolStatus mocks base method.
+func (m *MockconservativeState) MempoolStatus(arg0 types.TransactionID) (MempoolStatus, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MempoolStatus", arg0)
+	ret0, _ := ret[0].(MempoolStatus)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// MempoolStatus indicates an expected call of MempoolStatus.
+func (mr *MockconservativeStateMockRecorder) MempoolStatus(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MempoolStatus", reflect.TypeOf((*MockconservativeState)(nil).MempoolStatus), arg0)
+}
+
+// RejectTx mocks base method.
+func (m *MockconservativeState) RejectTx(arg0 types.TransactionID, arg1 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RejectTx", arg0, arg1)
+}
+
+// RejectTx indicates an expected call of RejectTx.
+func (mr *MockconservativeStateMockRecorder) RejectTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectTx", reflect.TypeOf((*MockconservativeState)(nil).RejectTx), arg0, arg1)
+}
+
 // Validation mocks base method.
 func (m *MockconservativeState) Validation(arg0 types.RawTx) system.ValidationRequest {
 	m.ctrl.T.Helper()
@@ -110,6 +154,57 @@ func (mr *MockconservativeStateMockRecorder) Validation(arg0 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validation", reflect.TypeOf((*MockconservativeState)(nil).Validation), arg0)
 }
 
+// MocklayerClock is a mock of layerClock interface.
+type MocklayerClock struct {
+	ctrl     *gomock.Controller
+	recorder *MocklayerClockMockRecorder
+}
+
+// MocklayerClockMockRecorder is the mock recorder for MocklayerClock.
+type MocklayerClockMockRecorder struct {
+	mock *MocklayerClock
+}
+
+// NewMocklayerClock creates a new mock instance.
+func NewMocklayerClock(ctrl *gomock.Controller) *MocklayerClock {
+	mock := &MocklayerClock{ctrl: ctrl}
+	mock.recorder = &MocklayerClockMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocklayerClock) EXPECT() *MocklayerClockMockRecorder {
+	return m.recorder
+}
+
+// AwaitLayer mocks base method.
+func (m *MocklayerClock) AwaitLayer(arg0 types.LayerID) <-chan struct{} {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AwaitLayer", arg0)
+	ret0, _ := ret[0].(<-chan struct{})
+	return ret0
+}
+
+// AwaitLayer indicates an expected call of AwaitLayer.
+func (mr *MocklayerClockMockRecorder) AwaitLayer(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AwaitLayer", reflect.TypeOf((*MocklayerClock)(nil).AwaitLayer), arg0)
+}
+
+// CurrentLayer mocks base method.
+func (m *MocklayerClock) CurrentLayer() types.LayerID {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CurrentLayer")
+	ret0, _ := ret[0].(types.LayerID)
+	return ret0
+}
+
+// CurrentLayer indicates an expected call of CurrentLayer.
+func (mr *MocklayerClockMockRecorder) CurrentLayer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CurrentLayer", reflect.TypeOf((*MocklayerClock)(nil).CurrentLayer))
+}
+
 // MockvmState is a mock of vmState interface.
 type MockvmState struct {
 	ctrl     *gomock.Controller
@@ -133,6 +228,51 @@ func (m *MockvmState) EXPECT() *MockvmStateMockRecorder {
 	return m.recorder
 }
 
+// AccountProof mocks base method.
+func (m *MockvmState) AccountProof(arg0 types.Address, arg1 types.LayerID) (*types.AccountProof, types.Hash32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccountProof", arg0, arg1)
+	ret0, _ := ret[0].(*types.AccountProof)
+	ret1, _ := ret[1].(types.Hash32)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// AccountProof indicates an expected call of AccountProof.
+func (mr *MockvmStateMockRecorder) AccountProof(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountProof", reflect.TypeOf((*MockvmState)(nil).AccountProof), arg0, arg1)
+}
+
+// GasSchedule mocks base method.
+func (m *MockvmState) GasSchedule(arg0 types.LayerID) vm.GasSchedule {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GasSchedule", arg0)
+	ret0, _ := ret[0].(vm.GasSchedule)
+	return ret0
+}
+
+// GasSchedule indicates an expected call of GasSchedule.
+func (mr *MockvmStateMockRecorder) GasSchedule(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GasSchedule", reflect.TypeOf((*MockvmState)(nil).GasSchedule), arg0)
+}
+
+// GetAccount mocks base method.
+func (m *MockvmState) GetAccount(arg0 types.Address) (types.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccount", arg0)
+	ret0, _ := ret[0].(types.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccount indicates an expected call of GetAccount.
+func (mr *MockvmStateMockRecorder) GetAccount(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockvmState)(nil).GetAccount), arg0)
+}
+
 // GetAllAccounts mocks base method.
 func (m *MockvmState) GetAllAccounts() ([]*types.Account, error) {
 	m.ctrl.T.Helper()
@@ -237,6 +377,20 @@ func (mr *MockvmStateMockRecorder) Validation(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validation", reflect.TypeOf((*MockvmState)(nil).Validation), arg0)
 }
 
+// ValidationAt mocks base method.
+func (m *MockvmState) ValidationAt(arg0 types.LayerID, arg1 types.RawTx) system.ValidationRequest {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidationAt", arg0, arg1)
+	ret0, _ := ret[0].(system.ValidationRequest)
+	return ret0
+}
+
+// ValidationAt indicates an expected call of ValidationAt.
+func (mr *MockvmStateMockRecorder) ValidationAt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidationAt", reflect.TypeOf((*MockvmState)(nil).ValidationAt), arg0, arg1)
+}
+
 // MockconStateCache is a mock of conStateCache interface.
 type MockconStateCache struct {
 	ctrl     *gomock.Controller
@@ -1,7 +1,9 @@
 package txs
 
 import (
+	"math"
 	"math/rand"
+	"sort"
 
 	"github.com/spacemeshos/go-spacemesh/common/types"
 	"github.com/spacemeshos/go-spacemesh/common/util"
@@ -20,8 +22,40 @@ func ShuffleWithNonceOrder(
 	byAddrAndNonce map[types.Address][]*NanoTX,
 ) []types.TransactionID {
 	rng.Shuffle(len(ntxs), func(i, j int) { ntxs[i], ntxs[j] = ntxs[j], ntxs[i] })
+	ordered := withNonceOrder(logger, numTXs, ntxs, byAddrAndNonce)
+	result := make([]types.TransactionID, 0, len(ordered))
+	for _, ntx := range ordered {
+		result = append(result, ntx.ID)
+	}
+	return result
+}
+
+// weightedShuffle orders the transactions randomly, with the probability to be ahead proportional
+// to the fee per unit of gas (weighted random sampling by Efraimidis and Spirakis).
+func weightedShuffle(rng *rand.Rand, ntxs []*NanoTX) {
+	keys := make(map[*NanoTX]float64, len(ntxs))
+	for _, ntx := range ntxs {
+		weight := float64(ntx.GasPrice)
+		if weight == 0 {
+			weight = 1
+		}
+		keys[ntx] = math.Log(1-rng.Float64()) / weight
+	}
+	sort.Slice(ntxs, func(i, j int) bool {
+		return keys[ntxs[i]] > keys[ntxs[j]]
+	})
+}
+
+// withNonceOrder returns up to numTXs transactions with the principals in the order of ntxs,
+// the spot taken by the principal is filled with its transaction for the next nonce.
+func withNonceOrder(
+	logger log.Log,
+	numTXs int,
+	ntxs []*NanoTX,
+	byAddrAndNonce map[types.Address][]*NanoTX,
+) []*NanoTX {
 	total := util.Min(len(ntxs), numTXs)
-	result := make([]types.TransactionID, 0, total)
+	result := make([]*NanoTX, 0, total)
 	packed := make(map[types.Address][]uint64)
 	for _, ntx := range ntxs[:total] {
 		// if a spot is taken by a principal, we add its TX for the next eligible nonce
@@ -33,7 +67,7 @@ func ShuffleWithNonceOrder(
 			logger.With().Fatal("txs missing", p)
 		}
 		toAdd := byAddrAndNonce[p][0]
-		result = append(result, toAdd.ID)
+		result = append(result, toAdd)
 		if _, ok := packed[p]; !ok {
 			packed[p] = []uint64{toAdd.Nonce, toAdd.Nonce}
 		} else {